@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/awhite/wtree/internal/config"
+	"github.com/awhite/wtree/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+// aliases holds the registered alias expansions by name
+var aliases = map[string]string{}
+
+// registerAliases adds the global config's aliases as subcommands. It runs
+// before cobra parses flags, so it reads the config file itself; aliases
+// that would shadow a built-in command are ignored.
+func registerAliases(root *cobra.Command, args []string) {
+	cfgFile = configFlagFromArgs(args)
+	initConfig()
+
+	globalConfig, err := config.NewManager().LoadGlobalConfig()
+	if err != nil || len(globalConfig.Aliases) == 0 {
+		return
+	}
+
+	names := make([]string, 0, len(globalConfig.Aliases))
+	for name := range globalConfig.Aliases {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if cmd, _, err := root.Find([]string{name}); err == nil && cmd != root {
+			if verbose {
+				fmt.Fprintf(os.Stderr, "Warning: alias '%s' shadows a built-in command and is ignored\n", name)
+			}
+			continue
+		}
+		aliases[name] = globalConfig.Aliases[name]
+		root.AddCommand(newAliasCommand(root, name))
+	}
+}
+
+// newAliasCommand builds the subcommand for one alias. Flag parsing is left
+// to the expanded command.
+func newAliasCommand(root *cobra.Command, name string) *cobra.Command {
+	return &cobra.Command{
+		Use:                name,
+		Short:              fmt.Sprintf("Alias for `wtree %s`", aliases[name]),
+		DisableFlagParsing: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			global, args := splitGlobalFlags(root, args)
+			expanded, err := resolveAlias(name, args)
+			if err != nil {
+				return err
+			}
+			expanded = append(global, expanded...)
+			if verbose {
+				fmt.Fprintf(os.Stderr, "Expanding alias: wtree %s\n", strings.Join(expanded, " "))
+			}
+
+			// The expanded command reports its own errors
+			cmd.SilenceErrors = true
+			cmd.SilenceUsage = true
+			applyCommandDefaults(root, expanded)
+			root.SetArgs(expanded)
+			return root.Execute()
+		},
+	}
+}
+
+// resolveAlias expands an alias, following aliases that expand to other
+// aliases
+func resolveAlias(name string, args []string) ([]string, error) {
+	seen := map[string]bool{}
+	for {
+		if seen[name] {
+			return nil, types.NewConfigError("alias", fmt.Sprintf("alias '%s' expands recursively", name), nil)
+		}
+		seen[name] = true
+
+		expanded, err := types.ExpandAlias(aliases[name], args)
+		if err != nil {
+			return nil, types.NewValidationError("alias", fmt.Sprintf("alias '%s': %v", name, err), nil)
+		}
+		if _, ok := aliases[expanded[0]]; !ok {
+			return expanded, nil
+		}
+		name, args = expanded[0], expanded[1:]
+	}
+}
+
+// splitGlobalFlags separates wtree's global flags, which cobra leaves
+// unparsed for alias commands, from the alias's own arguments
+func splitGlobalFlags(root *cobra.Command, args []string) (global, rest []string) {
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if !strings.HasPrefix(arg, "-") || arg == "-" {
+			rest = append(rest, arg)
+			continue
+		}
+
+		name, _, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		flag := root.PersistentFlags().Lookup(name)
+		if flag == nil && !strings.HasPrefix(arg, "--") {
+			flag = root.PersistentFlags().ShorthandLookup(name)
+		}
+		if flag == nil {
+			rest = append(rest, arg)
+			continue
+		}
+
+		global = append(global, arg)
+		if !hasValue && flag.NoOptDefVal == "" && i+1 < len(args) {
+			i++
+			global = append(global, args[i])
+		}
+	}
+	return global, rest
+}
+
+// configFlagFromArgs finds the value of --config before flags are parsed
+func configFlagFromArgs(args []string) string {
+	for i, arg := range args {
+		if value, ok := strings.CutPrefix(arg, "--config="); ok {
+			return value
+		}
+		if arg == "--config" && i+1 < len(args) {
+			return args[i+1]
+		}
+	}
+	return ""
+}
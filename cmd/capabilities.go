@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/awhite/wtree/internal/config"
+	"github.com/awhite/wtree/internal/worktree"
+	"github.com/spf13/cobra"
+)
+
+// annotationCapabilities lists, comma-separated, what a command needs set up
+// before it runs. Commands without it work anywhere, even outside a git
+// repository.
+const annotationCapabilities = "wtree.capabilities"
+
+// Command capabilities
+const (
+	// capRepo: needs a git repository; the worktree manager is set up in
+	// PersistentPreRunE and shared through setupManager
+	capRepo = "repo"
+
+	// capReadOnly: must never write to disk, so it runs without the lock
+	// directory and git's optional locks, e.g. on read-only filesystems and
+	// in locked-down CI containers
+	capReadOnly = "read-only"
+
+	// capOutput: can print its results as JSON or YAML with --output
+	capOutput = "output"
+
+	// capFetch: fetches the remote first when fetch.auto is on. That fetch
+	// is a write, so it lifts capReadOnly.
+	capFetch = "fetch"
+)
+
+// readOnly is set when the running command is read-only
+var readOnly bool
+
+// recordsState is set when the running command may change the repository,
+// so the workspace state file is updated after it
+var recordsState bool
+
+// stopInterrupts ends the SIGINT/SIGTERM handling installed for commands
+// that change the repository
+var stopInterrupts = func() {}
+
+// capabilities builds the Annotations value declaring a command's capabilities
+func capabilities(caps ...string) map[string]string {
+	return map[string]string{annotationCapabilities: strings.Join(caps, ",")}
+}
+
+// hasCapability reports whether a command declares a capability
+func hasCapability(cmd *cobra.Command, capability string) bool {
+	for _, c := range strings.Split(cmd.Annotations[annotationCapabilities], ",") {
+		if c == capability {
+			return true
+		}
+	}
+	return false
+}
+
+// prepareCommand sets up what the command's capabilities ask for
+func prepareCommand(cmd *cobra.Command, args []string) error {
+	if err := startProfiling(); err != nil {
+		cmd.SilenceUsage = true
+		return err
+	}
+	if err := checkOutputFormat(cmd); err != nil {
+		return err
+	}
+	if hasCapability(cmd, capReadOnly) && !(hasCapability(cmd, capFetch) && autoFetches()) {
+		readOnly = true
+		// Keep git from taking optional locks, e.g. the index refresh done by `git status`
+		_ = os.Setenv("GIT_OPTIONAL_LOCKS", "0")
+	}
+	if needsOnboarding(cmd) {
+		if path, err := globalConfigPath(); err == nil {
+			if err := runOnboarding(path); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: first-run setup skipped: %v\n", err)
+			}
+		}
+	}
+	if hasCapability(cmd, capRepo) {
+		recordsState = !readOnly && !isDryRun(cmd)
+		if recordsState {
+			stopInterrupts = worktree.HandleInterrupts(func() {
+				// The failure that follows is not a usage mistake
+				cmd.SilenceUsage = true
+			}, func() {
+				if sharedManager != nil {
+					sharedManager.ReleaseLocks()
+				}
+			})
+		}
+		if _, err := setupManager(); err != nil {
+			// A missing repository is not a usage mistake
+			cmd.SilenceUsage = true
+			return err
+		}
+	}
+	return nil
+}
+
+// isDryRun reports whether the command runs with --dry-run, be it the
+// persistent flag or a command's own, such as cleanup's -n
+func isDryRun(cmd *cobra.Command) bool {
+	flag := cmd.Flags().Lookup("dry-run")
+	return flag != nil && flag.Value.String() == "true"
+}
+
+// autoFetches reports whether the global config has commands fetch the
+// remote first (fetch.auto)
+func autoFetches() bool {
+	globalConfig, err := config.NewManager().LoadGlobalConfig()
+	return err == nil && globalConfig.Fetch.Auto
+}
+
+// wantsPlugins reports whether args may name a plugin command, i.e. a
+// subcommand that is not built in. Plugins only add commands, so everything
+// else runs without loading them.
+func wantsPlugins(root *cobra.Command, args []string) bool {
+	cmd, rest, err := root.Find(args)
+	if err != nil {
+		return true
+	}
+	if cmd != root {
+		return false
+	}
+	for _, arg := range rest {
+		if !strings.HasPrefix(arg, "-") {
+			return true
+		}
+	}
+	return false
+}
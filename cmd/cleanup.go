@@ -1,7 +1,13 @@
 package cmd
 
 import (
+	"fmt"
+	"os"
+
+	"github.com/awhite/wtree/internal/github"
+	"github.com/awhite/wtree/internal/schedule"
 	"github.com/awhite/wtree/internal/worktree"
+	"github.com/awhite/wtree/pkg/types"
 	"github.com/spf13/cobra"
 )
 
@@ -12,30 +18,78 @@ var cleanupCmd = &cobra.Command{
 
 This command analyzes your worktrees and identifies candidates for cleanup:
 - Branches that have been merged into the main branch
-- Worktrees with no recent activity (stale)
+- PR worktrees whose pull request has been merged or closed
+- Worktrees with no recent activity (stale, with --older-than): not
+  created, committed to, or edited (files with uncommitted changes) for
+  that long
 - Broken or corrupted worktrees
 
 You can preview what will be cleaned up with --dry-run, and use various
 filters to be more selective about what gets cleaned up.
 
+Candidates with uncommitted changes are listed with their status and diff
+stat first. Choose with --on-dirty whether to skip them, stash the changes,
+move them to the trash, or force removal; --auto skips them by default.
+
+Branches GitHub reports as protected or as the head of an open PR are kept,
+even when their worktree is removed, unless --allow-protected is given.
+
+After worktrees are removed, on_prune hooks run once in the main checkout
+with their paths and branches in WTREE_PRUNED_WORKTREES and
+WTREE_PRUNED_BRANCHES, one per line.
+
 Examples:
   wtree cleanup                        # Interactive cleanup with prompts
   wtree cleanup --dry-run             # Preview what would be cleaned up
+  wtree cleanup --dry-run --output json  # Candidates as JSON for scripts
   wtree cleanup --merged-only         # Clean only merged branches
   wtree cleanup --auto                # Auto-cleanup without prompts
-  wtree cleanup --older-than 30d      # Clean worktrees older than 30 days`,
+  wtree cleanup --older-than 30d      # Clean worktrees older than 30 days
+  wtree cleanup --auto --on-dirty stash  # Stash uncommitted work, then clean
+  wtree cleanup --mine                # Consider only your own worktrees
+  wtree cleanup --tag experiment      # Consider only worktrees tagged experiment
+  wtree cleanup --merged-only --background  # Clean up while you keep working
+  wtree cleanup --install-schedule daily  # Run cleanup automatically every day
+  wtree cleanup --uninstall-schedule  # Remove the scheduled cleanup`,
+	Annotations: capabilities(capRepo, capOutput),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		manager, err := setupManager()
 		if err != nil {
 			return err
 		}
 
+		installSchedule, _ := cmd.Flags().GetString("install-schedule")
+		uninstallSchedule, _ := cmd.Flags().GetBool("uninstall-schedule")
+		if installSchedule != "" || uninstallSchedule {
+			return runScheduleCommand(manager, installSchedule, uninstallSchedule, isDryRun(cmd))
+		}
+
+		// A background job can't prompt, so it runs as --auto
+		if background, _ := cmd.Flags().GetBool("background"); background {
+			return startBackground(manager, cmd, manager.GetRepo().GetRepoName(), "--auto")
+		}
+
 		// Get flag values
-		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		dryRun := isDryRun(cmd)
 		mergedOnly, _ := cmd.Flags().GetBool("merged-only")
 		auto, _ := cmd.Flags().GetBool("auto")
 		olderThan, _ := cmd.Flags().GetString("older-than")
 		verbose, _ := cmd.Flags().GetBool("verbose")
+		onDirty, _ := cmd.Flags().GetString("on-dirty")
+		mine, _ := cmd.Flags().GetBool("mine")
+		allowProtected, _ := cmd.Flags().GetBool("allow-protected")
+
+		switch onDirty {
+		case "", worktree.OnDirtySkip, worktree.OnDirtyStash, worktree.OnDirtyTrash, worktree.OnDirtyForce:
+		default:
+			return types.NewValidationError("cleanup",
+				fmt.Sprintf("invalid --on-dirty '%s' (expected skip, stash, trash, or force)", onDirty), nil)
+		}
+
+		tags, err := tagFlag(cmd)
+		if err != nil {
+			return err
+		}
 
 		options := worktree.CleanupOptions{
 			DryRun:     dryRun,
@@ -43,8 +97,30 @@ Examples:
 			Auto:       auto,
 			OlderThan:  olderThan,
 			Verbose:    verbose,
+			OnDirty:    onDirty,
+			Mine:       mine,
+
+			AllowProtected: allowProtected,
+			Tags:           tags,
 		}
 
+		// Lets cleanup recognize PR worktrees whose PR was merged or closed
+		globalConfig := manager.GetGlobalConfig()
+		manager.SetGitHubClient(github.NewClient(
+			globalConfig.GitHub.CLICommand,
+			globalConfig.GitHub.CacheTimeout,
+		))
+
+		if structuredOutput() {
+			if !dryRun {
+				return types.NewValidationError("cleanup", "--output "+outputFormat+" needs --dry-run", nil)
+			}
+			reports, err := manager.CleanupReport(options)
+			if err != nil {
+				return err
+			}
+			return writeOutput(cmd, reports)
+		}
 		return manager.Cleanup(options)
 	},
 }
@@ -55,6 +131,117 @@ func init() {
 	cleanupCmd.Flags().BoolP("dry-run", "n", false, "preview what would be cleaned up")
 	cleanupCmd.Flags().Bool("merged-only", false, "clean only branches that have been merged")
 	cleanupCmd.Flags().Bool("auto", false, "automatically clean up without prompts")
-	cleanupCmd.Flags().String("older-than", "", "clean worktrees older than duration (e.g., 30d, 2w)")
+	cleanupCmd.Flags().String("older-than", "", "clean worktrees inactive for longer than this (e.g. 30d, 2w, 3mo)")
 	cleanupCmd.Flags().BoolP("verbose", "v", false, "show detailed information about cleanup candidates")
+	cleanupCmd.Flags().Bool("allow-protected", false, "delete branches even if GitHub reports them protected or in an open PR")
+	cleanupCmd.Flags().String("on-dirty", "", "handle candidates with uncommitted changes: skip, stash, trash, or force")
+	cleanupCmd.Flags().Bool("background", false, "run as a background job without prompts (see 'wtree jobs')")
+	cleanupCmd.Flags().Bool("mine", false, "consider only worktrees created by the current user")
+	addTagFilterFlag(cleanupCmd, "consider only worktrees with this tag (repeatable; all must match)")
+	cleanupCmd.Flags().String("install-schedule", "", "install a scheduled non-interactive cleanup (hourly, daily, weekly)")
+	cleanupCmd.Flags().Bool("uninstall-schedule", false, "remove the scheduled cleanup for this repository")
+
+	_ = cleanupCmd.RegisterFlagCompletionFunc("on-dirty", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{worktree.OnDirtySkip, worktree.OnDirtyStash, worktree.OnDirtyTrash, worktree.OnDirtyForce}, cobra.ShellCompDirectiveNoFileComp
+	})
+	_ = cleanupCmd.RegisterFlagCompletionFunc("install-schedule", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{"hourly", "daily", "weekly"}, cobra.ShellCompDirectiveNoFileComp
+	})
+}
+
+// runScheduleCommand installs or removes the scheduled cleanup job for the
+// repository; dryRun only shows what would be installed or removed
+func runScheduleCommand(manager *worktree.Manager, install string, uninstall, dryRun bool) error {
+	ui := manager.GetUI()
+
+	repoRoot, err := manager.GetRepo().GetRepoRoot()
+	if err != nil {
+		return err
+	}
+
+	if uninstall {
+		if dryRun {
+			return previewUninstallSchedule(manager, repoRoot)
+		}
+		result, err := schedule.Uninstall(repoRoot)
+		if err != nil {
+			return err
+		}
+		if result.Backend == schedule.BackendCron {
+			ui.Info("Remove the crontab line tagged '# %s' with 'crontab -e'", result.Crontab)
+			return nil
+		}
+		if len(result.Files) == 0 {
+			ui.Info("No scheduled cleanup installed for %s", repoRoot)
+			return nil
+		}
+		ui.Success("Removed scheduled cleanup (%s)", result.Backend)
+		return nil
+	}
+
+	interval, err := schedule.ParseInterval(install)
+	if err != nil {
+		return err
+	}
+
+	binary, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to resolve wtree executable: %w", err)
+	}
+
+	job := schedule.Job{RepoPath: repoRoot, Binary: binary, Interval: interval}
+	if dryRun {
+		plan, err := schedule.Plan(job)
+		if err != nil {
+			return err
+		}
+		ui.Info("[DRY RUN] Would schedule %s cleanup for %s via %s", interval, repoRoot, plan.Backend)
+		if plan.Backend == schedule.BackendCron {
+			ui.InfoIndented("%s", plan.Crontab)
+		}
+		for _, file := range plan.Files {
+			ui.InfoIndented("%s", file)
+		}
+		return nil
+	}
+
+	result, err := schedule.Install(job)
+	if err != nil {
+		return err
+	}
+
+	if result.Backend == schedule.BackendCron {
+		ui.Info("No systemd or launchd scheduler available; add this line with 'crontab -e':")
+		fmt.Println(result.Crontab)
+		return nil
+	}
+
+	ui.Success("Scheduled %s cleanup via %s", interval, result.Backend)
+	for _, file := range result.Files {
+		ui.InfoIndented("%s", file)
+	}
+	return nil
+}
+
+// previewUninstallSchedule shows what --uninstall-schedule would remove
+func previewUninstallSchedule(manager *worktree.Manager, repoRoot string) error {
+	ui := manager.GetUI()
+
+	installed, err := schedule.Installed(repoRoot)
+	if err != nil {
+		return err
+	}
+	if installed.Backend == schedule.BackendCron {
+		ui.Info("[DRY RUN] Would leave the crontab line tagged '# %s' for you to remove with 'crontab -e'", installed.Crontab)
+		return nil
+	}
+	if len(installed.Files) == 0 {
+		ui.Info("[DRY RUN] No scheduled cleanup installed for %s", repoRoot)
+		return nil
+	}
+	ui.Info("[DRY RUN] Would remove scheduled cleanup (%s)", installed.Backend)
+	for _, file := range installed.Files {
+		ui.InfoIndented("%s", file)
+	}
+	return nil
 }
@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"github.com/awhite/wtree/internal/worktree"
+	"github.com/spf13/cobra"
+)
+
+var compareCmd = &cobra.Command{
+	Use:   "compare <branch>",
+	Short: "Check out a branch next to its merge base for review",
+	Long: `Prepare a pair of worktrees for reviewing a branch: the branch's own
+(created if it has none) and a detached one at its merge base with the base
+branch, named <branch>-base. Both are opened in the editor as one workspace,
+or with --difftool compared with git difftool, so the only differences are
+what the branch changed.
+
+The base defaults to the branch checked out in the main worktree. Running
+compare again moves the base worktree to the current merge base. Both
+worktrees are tagged compare and compare-<branch>, so 'wtree list --tag
+compare' shows the pairs; remove them with 'wtree cleanup --tag compare'.
+
+Examples:
+  wtree compare feature
+  wtree compare feature --base release-1.2
+  wtree compare feature --difftool`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeBranchNames,
+	Annotations:       capabilities(capRepo),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		manager, err := setupManager()
+		if err != nil {
+			return err
+		}
+		applyEditorFlags(cmd, manager)
+
+		base, _ := cmd.Flags().GetString("base")
+		difftool, _ := cmd.Flags().GetBool("difftool")
+		noOpen, _ := cmd.Flags().GetBool("no-open")
+
+		options := worktree.CompareOptions{
+			Base:     base,
+			Difftool: difftool,
+			NoOpen:   noOpen,
+			DryRun:   isDryRun(cmd),
+		}
+
+		return manager.Compare(args[0], options)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(compareCmd)
+
+	compareCmd.Flags().String("base", "", "branch to compare against (default: the main worktree's branch)")
+	compareCmd.Flags().Bool("difftool", false, "compare with git difftool instead of opening the editor")
+	compareCmd.Flags().Bool("no-open", false, "only prepare the worktrees")
+	addEditorFlags(compareCmd)
+	_ = compareCmd.RegisterFlagCompletionFunc("base", completeBranchNames)
+}
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"io"
 	"os"
 
 	"github.com/spf13/cobra"
@@ -47,9 +48,17 @@ PowerShell:
 	RunE: func(cmd *cobra.Command, args []string) error {
 		switch args[0] {
 		case "bash":
-			return rootCmd.GenBashCompletion(os.Stdout)
+			if err := rootCmd.GenBashCompletion(os.Stdout); err != nil {
+				return err
+			}
+			_, err := io.WriteString(os.Stdout, gitAliasBashCompletion)
+			return err
 		case "zsh":
-			return rootCmd.GenZshCompletion(os.Stdout)
+			if err := rootCmd.GenZshCompletion(os.Stdout); err != nil {
+				return err
+			}
+			_, err := io.WriteString(os.Stdout, gitAliasZshCompletion)
+			return err
 		case "fish":
 			return rootCmd.GenFishCompletion(os.Stdout, true)
 		case "powershell":
@@ -60,6 +69,40 @@ PowerShell:
 	},
 }
 
+// gitAliasBashCompletion completes the aliases install-git-alias creates
+// (git wt, git worktree-manager) through git's bash completion, which calls
+// _git_<alias> with its words, cword and cur. It asks wtree for candidates
+// the way cobra's own script does and falls back to files unless told not to.
+const gitAliasBashCompletion = `
+# Completion for 'git wt' and 'git worktree-manager' (wtree install-git-alias)
+_git_wt() {
+    local IFS=$'\n' out directive start=$(( ${__git_cmd_idx:-1} + 1 ))
+    out=$(command wtree __complete "${words[@]:start:cword-start}" "$cur" 2>/dev/null) || return
+    directive=${out##*:}
+    out=${out%:*}
+    COMPREPLY=($(compgen -W "$(printf '%s\n' "$out" | cut -f1)" -- "$cur"))
+    if [ ${#COMPREPLY[@]} -eq 0 ] && [ $(( directive & 4 )) -eq 0 ]; then
+        COMPREPLY=($(compgen -f -- "$cur"))
+    fi
+}
+_git_worktree_manager() { _git_wt; }
+`
+
+// gitAliasZshCompletion registers the aliases with zsh's own git completion,
+// which completes user commands with _git-<alias> after shifting words so
+// the alias comes first; _wtree then completes as if wtree had been typed
+const gitAliasZshCompletion = `
+# Completion for 'git wt' and 'git worktree-manager' (wtree install-git-alias)
+() {
+    local -a user_commands
+    zstyle -a ':completion:*:*:git:*' user-commands user_commands
+    zstyle ':completion:*:*:git:*' user-commands $user_commands \
+        'wt:manage worktrees (wtree)' 'worktree-manager:manage worktrees (wtree)'
+}
+_git-wt() { words[1]=wtree; _wtree }
+_git-worktree-manager() { _git-wt }
+`
+
 func init() {
 	rootCmd.AddCommand(completionCmd)
 }
@@ -4,7 +4,8 @@ import (
 	"github.com/spf13/cobra"
 )
 
-// completeBranchNames provides completion for branch names
+// completeBranchNames provides completion for branch names, describing
+// branches that already have a worktree
 func completeBranchNames(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 	if len(args) != 0 {
 		return nil, cobra.ShellCompDirectiveNoFileComp
@@ -20,10 +21,23 @@ func completeBranchNames(cmd *cobra.Command, args []string, toComplete string) (
 		return nil, cobra.ShellCompDirectiveError
 	}
 
-	return branches, cobra.ShellCompDirectiveNoFileComp
+	descriptions := make(map[string]string)
+	if worktrees, err := manager.GetRepo().ListWorktrees(); err == nil {
+		for _, wt := range worktrees {
+			descriptions[wt.Branch] = "has worktree"
+		}
+	}
+
+	completions := make([]string, 0, len(branches))
+	for _, branch := range branches {
+		completions = append(completions, withDescription(branch, descriptions[branch]))
+	}
+
+	return completions, cobra.ShellCompDirectiveNoFileComp
 }
 
-// completeExistingWorktrees provides completion for existing worktree branches
+// completeExistingWorktrees provides completion for existing worktree branches,
+// described with their status, PR number, and note
 func completeExistingWorktrees(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 	if len(args) != 0 {
 		return nil, cobra.ShellCompDirectiveNoFileComp
@@ -39,12 +53,28 @@ func completeExistingWorktrees(cmd *cobra.Command, args []string, toComplete str
 		return nil, cobra.ShellCompDirectiveError
 	}
 
-	var branches []string
+	var completions []string
 	for _, wt := range worktrees {
-		if !wt.IsMainRepo { // Don't include main repo in completion
-			branches = append(branches, wt.Branch)
+		if wt.IsMainRepo { // Don't include main repo in completion
+			continue
 		}
+		// A detached worktree has no branch, so it completes as its path
+		value := wt.Branch
+		if value == "" {
+			value = wt.Path
+		}
+		completions = append(completions, withDescription(value, manager.DescribeWorktree(wt)))
 	}
 
-	return branches, cobra.ShellCompDirectiveNoFileComp
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+// withDescription formats a completion candidate using cobra's
+// "value<TAB>description" convention; shells without description
+// support show only the value
+func withDescription(value, description string) string {
+	if description == "" {
+		return value
+	}
+	return value + "\t" + description
 }
@@ -3,8 +3,8 @@ package cmd
 import (
 	"fmt"
 	"os"
-	"path/filepath"
 
+	"github.com/awhite/wtree/internal/config"
 	"github.com/awhite/wtree/pkg/types"
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v3"
@@ -44,7 +44,7 @@ file patterns that you can customize for your project.`,
 		// Create sample configuration
 		config := types.ProjectConfig{
 			Version:         "1.0",
-			WorktreePattern: "{repo}-{branch}",
+			WorktreePattern: types.DefaultWorktreePattern,
 			CopyFiles:       []string{".env.example"},
 			LinkFiles:       []string{"node_modules", "vendor"},
 			IgnoreFiles:     []string{"*.log", "*.tmp"},
@@ -81,16 +81,14 @@ var configGlobalCmd = &cobra.Command{
 	Long: `Initialize global WTree configuration.
 
 Creates the global configuration directory and file at
-$HOME/.config/wtree/config.yaml with default settings.`,
+$HOME/.config/wtree/config.yaml with default settings, or with --interactive
+asks for the common ones first, as on the first run.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		home, err := os.UserHomeDir()
+		configFile, err := globalConfigPath()
 		if err != nil {
-			return fmt.Errorf("failed to get home directory: %w", err)
+			return err
 		}
 
-		configDir := filepath.Join(home, ".config", "wtree")
-		configFile := filepath.Join(configDir, "config.yaml")
-
 		// Check if config already exists
 		if _, err := os.Stat(configFile); err == nil {
 			force, _ := cmd.Flags().GetBool("force")
@@ -99,26 +97,106 @@ $HOME/.config/wtree/config.yaml with default settings.`,
 			}
 		}
 
-		// Create config directory
-		if err := os.MkdirAll(configDir, 0755); err != nil {
-			return fmt.Errorf("failed to create config directory: %w", err)
+		if interactive, _ := cmd.Flags().GetBool("interactive"); interactive {
+			return runOnboarding(configFile)
 		}
 
 		// Create default global configuration
-		config := types.DefaultWTreeConfig()
+		if err := writeGlobalConfig(configFile, types.DefaultWTreeConfig()); err != nil {
+			return err
+		}
 
-		// Write to file
-		data, err := yaml.Marshal(config)
+		fmt.Printf("Created global configuration at: %s\n", configFile)
+		fmt.Println("Edit this file to customize global WTree settings")
+		return nil
+	},
+}
+
+var configDocsCmd = &cobra.Command{
+	Use:   "docs",
+	Short: "Print reference documentation for every config key",
+	Long: `Print the type, default and description of every global (config.yaml) and
+project (.wtreerc) configuration key, generated from this binary so it always
+matches what it accepts.
+
+Examples:
+  wtree config docs > CONFIG.md        # Markdown tables
+  wtree config docs --format man | man -l -`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		format, _ := cmd.Flags().GetString("format")
+		return config.WriteDocs(cmd.OutOrStdout(), format)
+	},
+}
+
+var configTrustCmd = &cobra.Command{
+	Use:   "trust",
+	Short: "Show or reset confirmations answered \"always\" in this repository",
+	Long: `Answering "a" (always) to a delete, cleanup or trash empty confirmation
+stops wtree asking for that operation in this repository. This lists the
+operations answered that way; --reset makes them ask again.
+
+A .wtreerc with hooks.security: off runs its hooks without the checks for
+destructive commands only once you trust it, by typing 'trust' when asked or
+with --hooks (for CI). Any change to the file needs trusting again.
+
+Examples:
+  wtree config trust                   # Operations that no longer ask here
+  wtree config trust --hooks           # Run this .wtreerc's hooks unchecked
+  wtree config trust --reset           # Ask again in this repository
+  wtree config trust --reset --all     # Ask again everywhere`,
+	Args:        cobra.NoArgs,
+	Annotations: capabilities(capRepo),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		manager, err := setupManager()
 		if err != nil {
-			return fmt.Errorf("failed to marshal config: %w", err)
+			return err
+		}
+		reset, _ := cmd.Flags().GetBool("reset")
+		all, _ := cmd.Flags().GetBool("all")
+		hooks, _ := cmd.Flags().GetBool("hooks")
+		if all && !reset {
+			return types.NewValidationError("config-trust", "--all requires --reset", nil)
+		}
+		if hooks && reset {
+			return types.NewValidationError("config-trust", "--hooks and --reset can't be combined", nil)
 		}
 
-		if err := os.WriteFile(configFile, data, 0644); err != nil {
-			return fmt.Errorf("failed to write config file: %w", err)
+		u := manager.GetUI()
+		if hooks {
+			if err := manager.AcknowledgeHookSecurity(); err != nil {
+				return err
+			}
+			u.Success("Hooks of this .wtreerc run without command checks (until it changes)")
+			return nil
+		}
+		if reset {
+			if err := manager.ResetTrust(all); err != nil {
+				return err
+			}
+			if all {
+				u.Success("Confirmations will be asked again in every repository")
+			} else {
+				u.Success("Confirmations will be asked again in this repository")
+			}
+			return nil
 		}
 
-		fmt.Printf("Created global configuration at: %s\n", configFile)
-		fmt.Println("Edit this file to customize global WTree settings")
+		operations, err := manager.TrustedOperations()
+		if err != nil {
+			return err
+		}
+		if manager.HookSecurityAcknowledged() {
+			u.Info("Hooks of this .wtreerc run without command checks (hooks.security: off)")
+		}
+		if len(operations) == 0 {
+			u.Info("Every confirmation is asked in this repository")
+			return nil
+		}
+		u.Info("Confirmed automatically in this repository:")
+		for _, operation := range operations {
+			u.InfoIndented("%s", operation)
+		}
 		return nil
 	},
 }
@@ -128,7 +206,17 @@ func init() {
 
 	configCmd.AddCommand(configInitCmd)
 	configCmd.AddCommand(configGlobalCmd)
+	configCmd.AddCommand(configDocsCmd)
+	configCmd.AddCommand(configTrustCmd)
 
 	configInitCmd.Flags().Bool("force", false, "overwrite existing .wtreerc file")
 	configGlobalCmd.Flags().Bool("force", false, "overwrite existing global config file")
+	configGlobalCmd.Flags().BoolP("interactive", "i", false, "ask for the editor, worktree directory, colors and GitHub CLI")
+	configTrustCmd.Flags().Bool("reset", false, "ask every confirmation again")
+	configTrustCmd.Flags().Bool("all", false, "with --reset, in every repository")
+	configTrustCmd.Flags().Bool("hooks", false, "trust this .wtreerc's hooks.security: off")
+	configDocsCmd.Flags().String("format", config.DocsFormatMarkdown, "output format: md or man")
+	_ = configDocsCmd.RegisterFlagCompletionFunc("format", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{config.DocsFormatMarkdown, config.DocsFormatMan}, cobra.ShellCompDirectiveNoFileComp
+	})
 }
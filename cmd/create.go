@@ -1,7 +1,9 @@
 package cmd
 
 import (
+	"github.com/awhite/wtree/internal/github"
 	"github.com/awhite/wtree/internal/worktree"
+	"github.com/awhite/wtree/pkg/types"
 	"github.com/spf13/cobra"
 )
 
@@ -16,28 +18,127 @@ created in the parent directory using the configured naming pattern.
 Examples:
   wtree create feature-branch           # Create worktree for existing branch
   wtree create -b new-feature main     # Create new branch from main
-  wtree create -f existing-branch      # Force creation even if path exists`,
-	Args:              cobra.ExactArgs(1),
+  wtree create --overwrite-path feature  # Replace whatever is at the path
+  wtree create -b spike main --note "cache layer spike" # Remember why it exists
+  wtree create -b spike --tag experiment   # Tag it for list/cleanup --tag
+  wtree create feature --host me@devbox # Create on a remote machine (experimental)
+  wtree create -b fix --track origin/main  # New branch tracking origin/main
+  wtree create -b fix --push-default       # Push the new branch and track it
+  wtree create feature --no-setup          # Skip copies and hooks for now
+  wtree create feature --skip-hooks        # Copy and link files, but run no hooks
+  wtree create feature --refresh           # Already exists? Rerun its setup
+  wtree create --from-issue 456            # Branch fix-456-login-times-out from the issue
+  wtree create --from-issue 456 --comment  # ...and tell the issue work started
+  wtree create feature --open --print-commands  # Show the editor command only
+  wtree create -b try-fix --exec 'make test' --rm  # Verify in a throwaway worktree
+
+Creating a worktree for a branch that already has one is not an error: the
+existing worktree's path is printed, --open opens it and --refresh reruns its
+setup, so scripts can call create unconditionally.
+
+With --exec the command is run with sh inside the worktree once setup is
+done, with the WTREE_* variables hooks receive. Its output is streamed and
+wtree exits with its status; --rm deletes the worktree afterwards unless it
+already existed.`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if issue, _ := cmd.Flags().GetInt("from-issue"); issue > 0 {
+			return cobra.MaximumNArgs(1)(cmd, args)
+		}
+		return cobra.ExactArgs(1)(cmd, args)
+	},
 	ValidArgsFunction: completeBranchNames,
+	Annotations:       capabilities(capRepo),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		manager, err := setupManager()
 		if err != nil {
 			return err
 		}
+		applyEditorFlags(cmd, manager)
+		if err := applyOnConflict(cmd, manager); err != nil {
+			return err
+		}
 
-		branchName := args[0]
+		branchName := ""
+		if len(args) > 0 {
+			branchName = args[0]
+		}
 
 		// Get flag values
 		createBranch, _ := cmd.Flags().GetBool("branch")
 		fromBranch, _ := cmd.Flags().GetString("from")
 		openEditor, _ := cmd.Flags().GetBool("open")
+		note, _ := cmd.Flags().GetString("note")
+		replaceNote, _ := cmd.Flags().GetBool("replace-note")
+		host, _ := cmd.Flags().GetString("host")
+		remotePath, _ := cmd.Flags().GetString("remote-path")
+		track, _ := cmd.Flags().GetString("track")
+		pushDefault, _ := cmd.Flags().GetBool("push-default")
+		noSetup, _ := cmd.Flags().GetBool("no-setup")
+		skipHooks, _ := cmd.Flags().GetBool("skip-hooks")
+		skipCopy, _ := cmd.Flags().GetBool("skip-copy")
+		skipLink, _ := cmd.Flags().GetBool("skip-link")
+		refresh, _ := cmd.Flags().GetBool("refresh")
+		fromIssue, _ := cmd.Flags().GetInt("from-issue")
+		comment, _ := cmd.Flags().GetBool("comment")
+		execCommand, _ := cmd.Flags().GetString("exec")
+		remove, _ := cmd.Flags().GetBool("rm")
+		tags, err := tagFlag(cmd)
+		if err != nil {
+			return err
+		}
+
+		if remove && execCommand == "" {
+			return types.NewValidationError("create", "--rm needs --exec", nil)
+		}
+		if execCommand != "" && (host != "" || fromIssue > 0) {
+			return types.NewValidationError("create", "--exec can't be combined with --host or --from-issue", nil)
+		}
 
 		options := worktree.CreateOptions{
-			CreateBranch: createBranch,
-			FromBranch:   fromBranch,
-			Force:        force,
-			OpenEditor:   openEditor,
-			DryRun:       dryRun,
+			CreateBranch:  createBranch,
+			FromBranch:    fromBranch,
+			OverwritePath: riskFlag(cmd, "overwrite-path"),
+			OpenEditor:    openEditor,
+			DryRun:        isDryRun(cmd),
+			Note:          note,
+			ReplaceNote:   replaceNote,
+			Track:         track,
+			PushDefault:   pushDefault,
+			NoSetup:       noSetup,
+			SkipHooks:     skipHooks,
+			SkipCopy:      skipCopy,
+			SkipLink:      skipLink,
+			Refresh:       refresh,
+			Tags:          tags,
+
+			Host:           host,
+			RemoteRepoPath: remotePath,
+		}
+
+		if host != "" {
+			return manager.CreateRemote(branchName, options)
+		}
+
+		if fromIssue > 0 {
+			globalConfig := manager.GetGlobalConfig()
+			manager.SetGitHubClient(github.NewClient(
+				globalConfig.GitHub.CLICommand,
+				globalConfig.GitHub.CacheTimeout,
+			))
+			return manager.CreateFromIssue(fromIssue, branchName, worktree.IssueOptions{
+				Create:  options,
+				Comment: comment,
+			})
+		}
+
+		if execCommand != "" {
+			// A failing command is not a usage mistake
+			cmd.SilenceUsage = true
+			return manager.CreateExec(branchName, worktree.ExecOptions{
+				Create:  options,
+				Command: execCommand,
+				Remove:  remove,
+			})
 		}
 
 		return manager.Create(branchName, options)
@@ -50,6 +151,24 @@ func init() {
 	createCmd.Flags().BoolP("branch", "b", false, "create new branch if it doesn't exist")
 	createCmd.Flags().StringP("from", "", "HEAD", "base branch for new branch creation")
 	createCmd.Flags().BoolP("open", "o", false, "open in editor after creation")
+	createCmd.Flags().String("note", "", "description of why this worktree exists (shown in list/status)")
+	createCmd.Flags().Bool("replace-note", false, "let --note replace a description the branch already has")
+	addTagFilterFlag(createCmd, "tag the new worktree, e.g. review or experiment (repeatable)")
+	createCmd.Flags().String("track", "", "upstream for a new branch, e.g. origin/main")
+	createCmd.Flags().Bool("push-default", false, "push a new branch to the default remote and track it")
+	createCmd.Flags().Bool("no-setup", false, "skip copy/link files and post_create hooks (run 'wtree setup' later)")
+	createCmd.Flags().Bool("skip-hooks", false, "skip pre_create and post_create hooks")
+	addSkipFilesFlags(createCmd)
+	createCmd.Flags().Bool("refresh", false, "if the worktree already exists, rerun its setup (copy/link files and post_create hooks)")
+	addOverwritePathFlag(createCmd)
+	addOnConflictFlag(createCmd)
+	createCmd.Flags().String("host", "", "create the worktree on a remote machine over SSH (experimental, user@host)")
+	addEditorFlags(createCmd)
+	createCmd.Flags().Int("from-issue", 0, "create a new branch named after this GitHub issue and record the issue with the worktree")
+	createCmd.Flags().Bool("comment", false, "with --from-issue, comment on the issue that work has started")
+	createCmd.Flags().String("exec", "", "run this shell command in the worktree after setup and exit with its status")
+	createCmd.Flags().Bool("rm", false, "with --exec, delete the worktree afterwards")
+	createCmd.Flags().String("remote-path", "", "repository path on the remote host (default: same as local)")
 
 	// Register completion for the --from flag
 	_ = createCmd.RegisterFlagCompletionFunc("from", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"github.com/awhite/wtree/internal/worktree"
+	"github.com/awhite/wtree/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+var createManyCmd = &cobra.Command{
+	Use:   "create-many [branch...]",
+	Short: "Create worktrees for several branches at once",
+	Long: `Create worktrees for several branches in parallel, so their setup and hooks
+run side by side instead of one after another. Up to
+performance.max_concurrent_operations are created at a time.
+
+Branches come from the arguments, from --matching (a glob over local
+branches), or both. A line is printed as each worktree is ready; a failure
+doesn't stop the others, and a summary shows every result at the end.
+Branches that already have worktrees are reported and left as they are.
+
+Examples:
+  wtree create-many api-docs login-fix release-notes
+  wtree create-many --matching 'feature-*'
+  wtree create-many -b spike-a spike-b --from main
+  wtree create-many --matching 'review-*' --no-setup`,
+	ValidArgsFunction: completeBranchNames,
+	Annotations:       capabilities(capRepo),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		manager, err := setupManager()
+		if err != nil {
+			return err
+		}
+		if err := applyOnConflict(cmd, manager); err != nil {
+			return err
+		}
+
+		branches := args
+		if pattern, _ := cmd.Flags().GetString("matching"); pattern != "" {
+			matches, err := manager.MatchingBranches(pattern)
+			if err != nil {
+				return err
+			}
+			if len(matches) == 0 {
+				return types.NewValidationError("create-many", "no local branches match '"+pattern+"'", nil)
+			}
+			branches = append(branches, matches...)
+		}
+
+		createBranch, _ := cmd.Flags().GetBool("branch")
+		fromBranch, _ := cmd.Flags().GetString("from")
+		noSetup, _ := cmd.Flags().GetBool("no-setup")
+		skipHooks, _ := cmd.Flags().GetBool("skip-hooks")
+		skipCopy, _ := cmd.Flags().GetBool("skip-copy")
+		skipLink, _ := cmd.Flags().GetBool("skip-link")
+		tags, err := tagFlag(cmd)
+		if err != nil {
+			return err
+		}
+
+		options := worktree.CreateOptions{
+			CreateBranch: createBranch,
+			FromBranch:   fromBranch,
+			DryRun:       isDryRun(cmd),
+			NoSetup:      noSetup,
+			SkipHooks:    skipHooks,
+			SkipCopy:     skipCopy,
+			SkipLink:     skipLink,
+			Tags:         tags,
+		}
+
+		// Failures are reported per branch; usage wouldn't help
+		cmd.SilenceUsage = true
+		return manager.CreateMany(branches, options)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(createManyCmd)
+
+	createManyCmd.Flags().String("matching", "", "also create worktrees for local branches matching this glob, e.g. 'feature-*'")
+	createManyCmd.Flags().BoolP("branch", "b", false, "create branches that don't exist")
+	createManyCmd.Flags().String("from", "HEAD", "base branch for new branches")
+	createManyCmd.Flags().Bool("no-setup", false, "skip copy/link files and post_create hooks (run 'wtree setup' later)")
+	createManyCmd.Flags().Bool("skip-hooks", false, "skip pre_create and post_create hooks")
+	addSkipFilesFlags(createManyCmd)
+	addOnConflictFlag(createManyCmd)
+	addTagFilterFlag(createManyCmd, "tag the new worktrees (repeatable)")
+	_ = createManyCmd.RegisterFlagCompletionFunc("from", completeBranchNames)
+}
@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/awhite/wtree/internal/config"
+	"github.com/spf13/cobra"
+)
+
+// applyCommandDefaults presets the flags of the command named by args from
+// the global config's defaults section. It runs before cobra parses flags,
+// so flags given on the command line still win.
+func applyCommandDefaults(root *cobra.Command, args []string) {
+	globalConfig, err := config.NewManager().LoadGlobalConfig()
+	if err != nil || len(globalConfig.Defaults) == 0 {
+		return
+	}
+	cmd, _, err := root.Find(args)
+	if err != nil || cmd == root {
+		return
+	}
+	command := strings.TrimPrefix(cmd.CommandPath(), root.Name()+" ")
+	defaults := globalConfig.Defaults[command]
+
+	names := make([]string, 0, len(defaults))
+	for name := range defaults {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		// Config keys use underscores, flags dashes
+		flagName := strings.ReplaceAll(name, "_", "-")
+		flag := cmd.Flags().Lookup(flagName)
+		if flag == nil {
+			flag = cmd.InheritedFlags().Lookup(flagName)
+		}
+		if flag == nil {
+			fmt.Fprintf(os.Stderr, "Warning: defaults for '%s' set '%s', which is not one of its flags\n", command, name)
+			continue
+		}
+		if err := flag.Value.Set(defaultFlagValue(defaults[name])); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: invalid default for %s --%s: %v\n", command, flagName, err)
+		}
+	}
+}
+
+// defaultFlagValue renders a config value the way it would be written on the
+// command line; lists become comma-separated
+func defaultFlagValue(value interface{}) string {
+	if items, ok := value.([]interface{}); ok {
+		parts := make([]string, len(items))
+		for i, item := range items {
+			parts[i] = fmt.Sprint(item)
+		}
+		return strings.Join(parts, ",")
+	}
+	return fmt.Sprint(value)
+}
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"github.com/awhite/wtree/internal/github"
 	"github.com/awhite/wtree/internal/worktree"
 	"github.com/spf13/cobra"
 )
@@ -11,15 +12,33 @@ var deleteCmd = &cobra.Command{
 	Long: `Delete a git worktree by branch name or path.
 
 You can specify either the branch name or the worktree path. Use -b to also
-delete the associated branch. Use --ignore-dirty to delete even if there
-are uncommitted changes.
+delete the associated branch; an unmerged branch is kept unless
+--force-branch-delete is given too. Use --ignore-dirty to delete even if
+there are uncommitted changes or a git operation in progress, and --yes to
+skip the confirmation. When the GitHub CLI is set up, a branch that is
+protected or has an open PR is kept unless --allow-protected is given.
+
+Deleting the worktree your shell is currently in is refused unless --yes
+is given, in which case wtree tells you where to cd afterwards.
+
+With --trash (or trash.enabled in the global config) the worktree is moved
+to the trash instead, where it can be brought back with "wtree trash restore".
+
+A worktree with uncommitted changes is refused. With --autostash (or
+autostash in the global config) they are stashed as wtree-auto/<branch>
+first, and restored when you next create or switch to a worktree for the
+branch; without it you are offered the stash when asked interactively.
 
 Examples:
   wtree delete feature-branch          # Delete worktree for branch
   wtree delete -b feature-branch       # Delete worktree and branch
-  wtree delete --ignore-dirty old-work # Delete even if dirty`,
+  wtree delete -b --force-branch-delete spike  # ...even if spike is unmerged
+  wtree delete --ignore-dirty old-work # Delete even if dirty
+  wtree delete --autostash wip         # Stash the changes, then delete
+  wtree delete --trash experiment      # Delete, keeping a restorable copy`,
 	Args:              cobra.ExactArgs(1),
 	ValidArgsFunction: completeExistingWorktrees,
+	Annotations:       capabilities(capRepo),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		manager, err := setupManager()
 		if err != nil {
@@ -30,13 +49,30 @@ Examples:
 
 		// Get flag values
 		deleteBranch, _ := cmd.Flags().GetBool("branch")
-		ignoreDirty, _ := cmd.Flags().GetBool("ignore-dirty")
+		trash, _ := cmd.Flags().GetBool("trash")
+		permanent, _ := cmd.Flags().GetBool("permanent")
+		allowProtected, _ := cmd.Flags().GetBool("allow-protected")
+		autostash, _ := cmd.Flags().GetBool("autostash")
 
 		options := worktree.DeleteOptions{
-			DeleteBranch: deleteBranch,
-			Force:        force,
-			IgnoreDirty:  ignoreDirty,
-			DryRun:       dryRun,
+			DeleteBranch:      deleteBranch,
+			Yes:               assumeYes(),
+			IgnoreDirty:       riskFlag(cmd, "ignore-dirty"),
+			ForceBranchDelete: riskFlag(cmd, "force-branch-delete"),
+			DryRun:            isDryRun(cmd),
+			Trash:             trash,
+			Permanent:         permanent,
+			AllowProtected:    allowProtected,
+			Autostash:         autostash,
+		}
+
+		// Lets delete keep branches that are protected or in an open PR
+		if deleteBranch && !allowProtected {
+			globalConfig := manager.GetGlobalConfig()
+			manager.SetGitHubClient(github.NewClient(
+				globalConfig.GitHub.CLICommand,
+				globalConfig.GitHub.CacheTimeout,
+			))
 		}
 
 		return manager.Delete(identifier, options)
@@ -48,4 +84,9 @@ func init() {
 
 	deleteCmd.Flags().BoolP("branch", "b", false, "also delete the branch")
 	deleteCmd.Flags().Bool("ignore-dirty", false, "delete even if worktree has uncommitted changes")
+	deleteCmd.Flags().Bool("autostash", false, "stash uncommitted changes as wtree-auto/<branch> before deleting")
+	deleteCmd.Flags().Bool("force-branch-delete", false, "with -b, delete the branch even if it isn't merged")
+	deleteCmd.Flags().Bool("allow-protected", false, "with -b, delete the branch even if GitHub reports it protected or in an open PR")
+	deleteCmd.Flags().Bool("trash", false, "move the worktree to the trash instead of removing it")
+	deleteCmd.Flags().Bool("permanent", false, "remove permanently even when trash.enabled is set")
 }
@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"fmt"
+	"os/exec"
+
+	"github.com/awhite/wtree/internal/git"
+	"github.com/awhite/wtree/internal/ui"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check git and the environment wtree depends on",
+	Long: `Check the installed git version and which version-gated git features are
+available, along with the other tools and configuration wtree uses.
+
+Exits non-zero when git is missing or too old, or the configuration does
+not load. Like version, this works outside a git repository.
+
+Examples:
+  wtree doctor                         # Report what works on this machine`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		u := ui.NewManager(!viper.GetBool("no_color"), verbose)
+		problems := 0
+
+		u.Header("Git")
+		v, err := git.DetectVersion()
+		if err != nil {
+			u.Error("git not found: %v", err)
+			return fmt.Errorf("wtree needs git %s or newer", git.MinimumVersion.MinVersion())
+		}
+		gitPath, _ := exec.LookPath("git")
+		u.Info("%s (%s)", v.Raw, gitPath)
+		if git.Supports(git.MinimumVersion) {
+			u.Success("git %s meets the minimum version %s", v, git.MinimumVersion.MinVersion())
+		} else {
+			u.Error("git %s is older than the minimum version %s", v, git.MinimumVersion.MinVersion())
+			problems++
+		}
+
+		table := u.NewTable()
+		table.SetHeaders("Feature", "Needs", "Available")
+		for _, feature := range git.Features {
+			available := "yes"
+			if !git.Supports(feature) {
+				available = "no"
+			}
+			table.AddRow(feature.Name, "git "+feature.MinVersion(), available)
+		}
+		table.Render()
+
+		// Loading the manager validates both configs, but needs a repository
+		ghCommand := "gh"
+		repoRoot := ""
+		var repoErr error
+		if _, err := git.NewRepository(""); err != nil {
+			repoErr = err
+		} else if manager, err := setupManager(); err != nil {
+			u.Error("Configuration failed to load: %v", err)
+			problems++
+		} else {
+			ghCommand = manager.GetGlobalConfig().GitHub.CLICommand
+			repoRoot, _ = manager.GetRepo().GetRepoRoot()
+		}
+
+		u.Header("Tools")
+		if path, err := exec.LookPath(ghCommand); err == nil {
+			u.Success("%s found (%s)", ghCommand, path)
+		} else {
+			u.Warning("%s not found; pr commands are unavailable", ghCommand)
+		}
+
+		u.Header("Repository")
+		switch {
+		case repoErr != nil:
+			u.Info("Not in a git repository")
+		case repoRoot != "":
+			u.Success("%s", repoRoot)
+			u.Success("Global and project configuration loaded")
+		}
+
+		if problems > 0 {
+			return fmt.Errorf("%d problem(s) found", problems)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+}
@@ -16,14 +16,20 @@ at once, which is useful for different workflows (e.g., code + terminal).
 Examples:
   wtree editors feature-branch          # Open in all configured editors
   wtree editors --editors code,vim .    # Open in specific editors
-  wtree editors --terminal feature-branch  # Also open terminal`,
+  wtree editors --terminal feature-branch  # Also open terminal
+  wtree editors --print-commands -t .   # Show the commands instead of running them
+
+Every editor and terminal launch is recorded in the audit log
+($XDG_DATA_HOME/wtree/audit.log) with its command and any error.`,
 	Args:              cobra.MaximumNArgs(1),
 	ValidArgsFunction: completeExistingWorktrees,
+	Annotations:       capabilities(capRepo),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		manager, err := setupManager()
 		if err != nil {
 			return err
 		}
+		applyEditorFlags(cmd, manager)
 
 		var identifier string
 		if len(args) > 0 {
@@ -50,4 +56,20 @@ func init() {
 
 	editorsCmd.Flags().String("editors", "", "comma-separated list of editors to open (e.g., 'code,vim')")
 	editorsCmd.Flags().BoolP("terminal", "t", false, "also open a terminal in the worktree")
+	addEditorFlags(editorsCmd)
+}
+
+// addEditorFlags adds --print-commands and --new-window to a command that
+// opens editors
+func addEditorFlags(cmd *cobra.Command) {
+	cmd.Flags().Bool("print-commands", false, "print the editor/terminal commands instead of running them")
+	cmd.Flags().Bool("new-window", false, "open a new editor window even if the worktree is open in one already")
+}
+
+// applyEditorFlags passes --print-commands and --new-window on to the manager
+func applyEditorFlags(cmd *cobra.Command, manager *worktree.Manager) {
+	printCommands, _ := cmd.Flags().GetBool("print-commands")
+	manager.SetPrintCommands(printCommands)
+	newWindow, _ := cmd.Flags().GetBool("new-window")
+	manager.SetNewWindow(newWindow)
 }
@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"github.com/awhite/wtree/internal/worktree"
+	"github.com/spf13/cobra"
+)
+
+var envCmd = &cobra.Command{
+	Use:   "env [branch-or-path]",
+	Short: "Print the WTREE_* variables for a worktree",
+	Long: `Print the WTREE_* variables hooks receive (branch, repository and worktree
+paths, PR details for PR worktrees) for a worktree, the current one by
+default, so scripts and Makefiles can use the same context.
+
+Examples:
+  eval "$(wtree env)"                      # Export into the current shell
+  wtree env feature --format dotenv > .env.wtree
+  wtree env pr-123 --format json | jq -r .WTREE_PR_URL`,
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeExistingWorktrees,
+	SilenceUsage:      true,
+	Annotations:       capabilities(capRepo, capReadOnly),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		manager, err := setupManager()
+		if err != nil {
+			return err
+		}
+
+		identifier := ""
+		if len(args) > 0 {
+			identifier = args[0]
+		}
+		env, err := manager.Env(identifier)
+		if err != nil {
+			return err
+		}
+
+		format, _ := cmd.Flags().GetString("format")
+		return worktree.WriteEnv(cmd.OutOrStdout(), env, format)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(envCmd)
+
+	envCmd.Flags().String("format", worktree.EnvFormatShell, "output format: shell, json or dotenv")
+	_ = envCmd.RegisterFlagCompletionFunc("format", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{worktree.EnvFormatShell, worktree.EnvFormatJSON, worktree.EnvFormatDotenv}, cobra.ShellCompDirectiveNoFileComp
+	})
+}
@@ -0,0 +1,22 @@
+package cmd
+
+import "github.com/spf13/cobra"
+
+// assumeYes reports whether confirmations are skipped: --yes, or the
+// deprecated --force
+func assumeYes() bool {
+	return yes || force
+}
+
+// riskFlag reads one of the opt-in risky flags (--overwrite-path,
+// --ignore-dirty, --force-branch-delete), which the deprecated --force
+// turns on all at once
+func riskFlag(cmd *cobra.Command, name string) bool {
+	value, _ := cmd.Flags().GetBool(name)
+	return value || force
+}
+
+// addOverwritePathFlag adds --overwrite-path to a command creating worktrees
+func addOverwritePathFlag(cmd *cobra.Command) {
+	cmd.Flags().Bool("overwrite-path", false, "remove whatever already exists at the worktree path")
+}
@@ -0,0 +1,132 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/awhite/wtree/internal/git"
+	"github.com/awhite/wtree/internal/remote"
+	"github.com/awhite/wtree/internal/ui"
+	"github.com/awhite/wtree/pkg/types"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// gitAliasNames are the git aliases install-git-alias sets up by default
+var gitAliasNames = []string{"wt", "worktree-manager"}
+
+var installGitAliasCmd = &cobra.Command{
+	Use:   "install-git-alias",
+	Short: "Make 'git wt' run wtree",
+	Long: `Configure git aliases that run wtree, so 'git wt create -b feature' works
+like 'wtree create -b feature'. By default 'git wt' and 'git worktree-manager'
+are added to your global git config; --local adds them to the current
+repository only.
+
+An alias that already runs something else is left alone unless --yes is
+given. --uninstall removes aliases that run wtree.
+
+Completion for the aliases comes with wtree's completion script when it is
+sourced (source <(wtree completion bash), or zsh): it hooks into git's own
+completion, so 'git wt sw<TAB>' completes like 'wtree sw<TAB>'.
+
+Examples:
+  wtree install-git-alias                  # git wt, git worktree-manager
+  wtree install-git-alias --name w         # git w
+  wtree install-git-alias --local
+  wtree install-git-alias --uninstall`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		local, _ := cmd.Flags().GetBool("local")
+		uninstall, _ := cmd.Flags().GetBool("uninstall")
+		names, _ := cmd.Flags().GetStringSlice("name")
+
+		scope := "global"
+		if local {
+			scope = "local"
+		}
+		u := ui.NewManager(!viper.GetBool("no_color"), verbose)
+		target := "!" + gitAliasTarget()
+
+		for _, name := range names {
+			if name == "" || strings.ContainsAny(name, " \t.=") {
+				return types.NewValidationError("install-git-alias", fmt.Sprintf("invalid alias name '%s'", name), nil)
+			}
+			key := "alias." + name
+			current, err := git.ScopedConfigValue(scope, key)
+			if err != nil {
+				return err
+			}
+
+			if uninstall {
+				if !isWtreeAlias(current) {
+					u.Info("git %s doesn't run wtree; left alone", name)
+					continue
+				}
+				if isDryRun(cmd) {
+					u.Info("[DRY RUN] Would remove git %s (%s config)", name, scope)
+					continue
+				}
+				if err := git.SetScopedConfigValue(scope, key, ""); err != nil {
+					return err
+				}
+				u.Success("Removed git %s", name)
+				continue
+			}
+
+			switch {
+			case current == target:
+				u.Info("git %s already runs wtree", name)
+				continue
+			case current != "" && !isWtreeAlias(current) && !assumeYes():
+				return types.NewValidationError("install-git-alias",
+					fmt.Sprintf("git %s is already an alias for '%s'; pass --yes to replace it", name, current), nil)
+			}
+			if isDryRun(cmd) {
+				u.Info("[DRY RUN] Would set %s = %s (%s config)", key, target, scope)
+				continue
+			}
+			if err := git.SetScopedConfigValue(scope, key, target); err != nil {
+				return err
+			}
+			u.Success("git %s now runs wtree (%s config)", name, scope)
+		}
+
+		if !uninstall && !isDryRun(cmd) {
+			u.Info("For completion through git, source wtree's completion script: source <(wtree completion bash)")
+		}
+		return nil
+	},
+}
+
+// gitAliasTarget returns how an alias invokes wtree: by name when wtree is
+// on PATH, so upgrades that move the binary keep working, otherwise by the
+// path of this executable
+func gitAliasTarget() string {
+	if _, err := exec.LookPath("wtree"); err == nil {
+		return "wtree"
+	}
+	if executable, err := os.Executable(); err == nil {
+		return remote.Quote(executable)
+	}
+	return "wtree"
+}
+
+// isWtreeAlias reports whether a git alias value runs wtree
+func isWtreeAlias(value string) bool {
+	fields := strings.Fields(strings.TrimPrefix(value, "!"))
+	return strings.HasPrefix(value, "!") && len(fields) > 0 &&
+		filepath.Base(strings.Trim(fields[0], `'"`)) == "wtree"
+}
+
+func init() {
+	rootCmd.AddCommand(installGitAliasCmd)
+
+	installGitAliasCmd.Flags().Bool("local", false, "add the aliases to this repository's git config instead of the global one")
+	installGitAliasCmd.Flags().Bool("uninstall", false, "remove the aliases that run wtree")
+	installGitAliasCmd.Flags().StringSlice("name", gitAliasNames, "alias names to manage")
+}
@@ -16,10 +16,14 @@ This mode provides an intuitive interface for:
 - Batch operations on multiple branches
 - Visual preview of operations
 
+In create and cleanup mode, toggle several entries by number (e.g. "1 3 5-7"
+or "all") and press Enter to run them as one batch with a final report.
+
 Examples:
   wtree interactive                 # Launch interactive browser
   wtree interactive --create        # Interactive branch creation
   wtree interactive --cleanup       # Interactive cleanup mode`,
+	Annotations: capabilities(capRepo),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		manager, err := setupManager()
 		if err != nil {
@@ -35,7 +39,7 @@ Examples:
 			CreateMode:  createMode,
 			CleanupMode: cleanupMode,
 			SwitchMode:  switchMode,
-			DryRun:      dryRun,
+			DryRun:      isDryRun(cmd),
 		}
 
 		return manager.Interactive(options)
@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var internalLockCmd = &cobra.Command{
+	Use:   "internal-lock [--held-by-hook] -- <command> [args...]",
+	Short: "Run a command holding the repository's git lock",
+	Long: `Run a command while holding the lock wtree takes around its own changes to
+the repository (creating and deleting branches and worktrees, rollback).
+
+Hooks that run git commands such as 'git fetch' or 'git lfs pull' can race
+with a parallel 'wtree create' in the same repository; wrapping them in
+internal-lock makes them wait their turn instead. With --held-by-hook the
+command must be run by a hook: wtree checks the WTREE_LOCK_TOKEN it passes
+hooks against the operation running them.
+
+Examples (in .wtreerc):
+  hooks:
+    post_create:
+      - wtree internal-lock --held-by-hook -- git lfs pull
+      - wtree internal-lock --held-by-hook -- git fetch origin main`,
+	Args:         cobra.MinimumNArgs(1),
+	SilenceUsage: true,
+	Annotations:  capabilities(capRepo),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		manager, err := setupManager()
+		if err != nil {
+			return err
+		}
+
+		heldByHook, _ := cmd.Flags().GetBool("held-by-hook")
+		return manager.RunWithGitLock(args, heldByHook)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(internalLockCmd)
+
+	internalLockCmd.Flags().Bool("held-by-hook", false, "require being run from a wtree hook, checked by its WTREE_LOCK_TOKEN")
+	// Flags after the command belong to it
+	internalLockCmd.Flags().SetInterspersed(false)
+}
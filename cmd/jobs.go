@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"github.com/awhite/wtree/internal/worktree"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+var jobsCmd = &cobra.Command{
+	Use:   "jobs",
+	Short: "Monitor background jobs",
+	Long: `Monitor wtree commands running in the background for this repository:
+project setup started by create when setup.background is enabled, and
+cleanup --background.
+
+Each job's output is logged under the repository's git directory, in
+wtree/jobs/<id>.log. Job IDs may be abbreviated to a unique prefix.
+
+Cancelling stops the wtree process; a hook command it had already started
+may run to completion.
+
+Examples:
+  wtree jobs                           # List recent jobs and their state
+  wtree jobs logs 3f2a --follow        # Stream a job's output until it ends
+  wtree jobs cancel 3f2a               # Stop a running job`,
+	Args:        cobra.NoArgs,
+	Annotations: capabilities(capRepo, capReadOnly),
+	RunE:        runJobsList,
+}
+
+var jobsListCmd = &cobra.Command{
+	Use:         "list",
+	Short:       "List background jobs",
+	Args:        cobra.NoArgs,
+	Annotations: capabilities(capRepo, capReadOnly),
+	RunE:        runJobsList,
+}
+
+var jobsLogsCmd = &cobra.Command{
+	Use:               "logs <job-id>",
+	Short:             "Show a background job's output",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeJobIDs,
+	Annotations:       capabilities(capRepo, capReadOnly),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		manager, err := setupManager()
+		if err != nil {
+			return err
+		}
+
+		follow, _ := cmd.Flags().GetBool("follow")
+		return manager.ShowJobLog(args[0], follow)
+	},
+}
+
+var jobsCancelCmd = &cobra.Command{
+	Use:               "cancel <job-id>",
+	Short:             "Stop a running background job",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeJobIDs,
+	Annotations:       capabilities(capRepo),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		manager, err := setupManager()
+		if err != nil {
+			return err
+		}
+
+		return manager.CancelJob(args[0])
+	},
+}
+
+func runJobsList(cmd *cobra.Command, args []string) error {
+	manager, err := setupManager()
+	if err != nil {
+		return err
+	}
+
+	return manager.ShowJobs()
+}
+
+// completeJobIDs completes the IDs of the repository's jobs
+func completeJobIDs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	manager, err := setupManager()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	jobs, err := manager.ListJobs()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	var ids []string
+	for _, job := range jobs {
+		ids = append(ids, job.ID+"\t"+job.Kind+" "+job.Target+" ("+job.State()+")")
+	}
+	return ids, cobra.ShellCompDirectiveNoFileComp
+}
+
+// startBackground re-runs cmd as a background job without --background,
+// adding extraArgs (e.g. --auto, since a job can't prompt)
+func startBackground(manager *worktree.Manager, cmd *cobra.Command, target string, extraArgs ...string) error {
+	args := []string{cmd.Name()}
+	cmd.Flags().Visit(func(flag *pflag.Flag) {
+		if flag.Name != "background" {
+			args = append(args, "--"+flag.Name+"="+flag.Value.String())
+		}
+	})
+	args = append(args, extraArgs...)
+
+	job, err := manager.StartJob(cmd.Name(), target, args)
+	if err != nil {
+		return err
+	}
+	manager.GetUI().Success("Started %s in the background (job %s); follow it with 'wtree jobs logs %s --follow'", cmd.Name(), job.ID, job.ID)
+	return nil
+}
+
+func init() {
+	rootCmd.AddCommand(jobsCmd)
+	jobsCmd.AddCommand(jobsListCmd)
+	jobsCmd.AddCommand(jobsLogsCmd)
+	jobsCmd.AddCommand(jobsCancelCmd)
+
+	jobsLogsCmd.Flags().Bool("follow", false, "keep printing output until the job ends")
+}
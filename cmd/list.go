@@ -1,7 +1,10 @@
 package cmd
 
 import (
+	"fmt"
+
 	"github.com/awhite/wtree/internal/worktree"
+	"github.com/awhite/wtree/pkg/types"
 	"github.com/spf13/cobra"
 )
 
@@ -17,8 +20,23 @@ Examples:
   wtree list                           # List all worktrees
   wtree list --status                  # List with git status
   wtree list --filter feature         # Filter by branch name
-  wtree list --dirty                   # Show only dirty worktrees`,
-	Aliases: []string{"ls"},
+  wtree list --dirty                   # Show only dirty worktrees
+  wtree list --group-by age            # Group by last commit age
+  wtree list --summary                 # Show aggregate counts only
+  wtree list --mine                    # Show only your worktrees
+  wtree list --tag review              # Show only worktrees tagged review
+  wtree list --porcelain               # Stable output for scripts
+  wtree list --status --output json    # Structured output for jq
+  wtree list --status --watch          # Redraw every 2s until Ctrl-C
+
+Branches are marked with ● dirty (with --status), ↑N/↓N ahead/behind
+(with --status), ⚑ PR worktree and 🔒 locked. --porcelain prints
+"path<TAB>branch<TAB>type<TAB>markers" lines, where markers is a comma
+list of dirty, ahead=N, behind=N, pr=N and locked, or "-". --output json
+(or yaml) prints an array of worktree objects instead, with their status
+under "status" when --status or --dirty is given.`,
+	Aliases:     []string{"ls"},
+	Annotations: capabilities(capRepo, capReadOnly, capOutput),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		manager, err := setupManager()
 		if err != nil {
@@ -29,14 +47,55 @@ Examples:
 		showStatus, _ := cmd.Flags().GetBool("status")
 		branchFilter, _ := cmd.Flags().GetString("filter")
 		onlyDirty, _ := cmd.Flags().GetBool("dirty")
+		groupBy, _ := cmd.Flags().GetString("group-by")
+		summary, _ := cmd.Flags().GetBool("summary")
+		mine, _ := cmd.Flags().GetBool("mine")
+		porcelain, _ := cmd.Flags().GetBool("porcelain")
+		tags, err := tagFlag(cmd)
+		if err != nil {
+			return err
+		}
+
+		switch groupBy {
+		case "", worktree.GroupByState, worktree.GroupByAge, worktree.GroupByAuthor:
+		default:
+			return types.NewValidationError("list",
+				fmt.Sprintf("invalid --group-by '%s' (expected state, age, or author)", groupBy), nil)
+		}
 
 		options := worktree.ListOptions{
 			ShowStatus:   showStatus,
 			BranchFilter: branchFilter,
 			OnlyDirty:    onlyDirty,
+			GroupBy:      groupBy,
+			Summary:      summary,
+			Mine:         mine,
+			Porcelain:    porcelain,
+			Tags:         tags,
+		}
+
+		if err := rejectWithOutput(cmd, "porcelain", "summary", "group-by"); err != nil {
+			return err
+		}
+		render := func() error { return manager.List(options) }
+		if structuredOutput() {
+			render = func() error {
+				reports, err := manager.ListReport(options)
+				if err != nil {
+					return err
+				}
+				return writeOutput(cmd, reports)
+			}
 		}
 
-		return manager.List(options)
+		interval, err := watchInterval(cmd)
+		if err != nil {
+			return err
+		}
+		if interval > 0 {
+			return runWatching(interval, render)
+		}
+		return render()
 	},
 }
 
@@ -46,4 +105,14 @@ func init() {
 	listCmd.Flags().BoolP("status", "s", false, "show git status for each worktree")
 	listCmd.Flags().StringP("filter", "", "", "filter by branch name (substring match)")
 	listCmd.Flags().Bool("dirty", false, "show only worktrees with uncommitted changes")
+	listCmd.Flags().String("group-by", "", "group worktrees by state, age, or author")
+	listCmd.Flags().Bool("summary", false, "print aggregate counts (clean/dirty, merged/unmerged, disk) instead of rows")
+	listCmd.Flags().Bool("porcelain", false, "print stable tab-separated lines for scripts: path, branch, type, markers")
+	listCmd.Flags().Bool("mine", false, "show only worktrees created by the current user")
+	addTagFilterFlag(listCmd, "show only worktrees with this tag (repeatable; all must match)")
+	addWatchFlag(listCmd)
+
+	_ = listCmd.RegisterFlagCompletionFunc("group-by", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{worktree.GroupByState, worktree.GroupByAge, worktree.GroupByAuthor}, cobra.ShellCompDirectiveNoFileComp
+	})
 }
@@ -10,14 +10,27 @@ var mergeCmd = &cobra.Command{
 	Short: "Merge a branch into current worktree",
 	Long: `Merge changes from the specified branch into the current worktree.
 
-The working directory must be clean unless --force is used. This runs
-pre-merge and post-merge hooks if configured in .wtreerc.
+The working directory must be clean unless --ignore-dirty is used. This runs
+pre-merge and post-merge hooks if configured in .wtreerc. Any
+pre_merge_checks are run in the source branch's worktree first and
+abort the merge on failure unless --skip-checks is given.
+
+Use --signoff and --gpg-sign for compliance workflows that require signed
+merges. Defaults can be set with git config (wtree.signoff, wtree.gpgSign),
+including per-worktree config.
+
+With --dry-run nothing is merged: the merge base, the commits that would be
+merged and the files predicted to conflict (git 2.38+) are shown instead.
 
 Examples:
   wtree merge feature-branch           # Merge feature into current
   wtree merge -m "Custom message" fix  # Merge with custom message
-  wtree merge --force dirty-branch     # Force merge even if dirty`,
-	Args: cobra.ExactArgs(1),
+  wtree merge --ignore-dirty dirty-branch  # Merge even if dirty
+  wtree merge --dry-run feature-branch # Preview commits and conflicts
+  wtree merge --skip-checks hotfix     # Merge without running pre_merge_checks
+  wtree merge --signoff --gpg-sign fix # Create a signed-off, signed merge`,
+	Args:        cobra.ExactArgs(1),
+	Annotations: capabilities(capRepo),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		manager, err := setupManager()
 		if err != nil {
@@ -28,10 +41,19 @@ Examples:
 
 		// Get flag values
 		message, _ := cmd.Flags().GetString("message")
+		skipChecks, _ := cmd.Flags().GetBool("skip-checks")
+		signoff, _ := cmd.Flags().GetBool("signoff")
+		gpgSign, _ := cmd.Flags().GetBool("gpg-sign")
+		gpgKeyID, _ := cmd.Flags().GetString("gpg-key")
 
 		options := worktree.MergeOptions{
-			Message: message,
-			Force:   force,
+			Message:     message,
+			IgnoreDirty: riskFlag(cmd, "ignore-dirty"),
+			SkipChecks:  skipChecks,
+			Signoff:     signoff,
+			GPGSign:     gpgSign,
+			GPGKeyID:    gpgKeyID,
+			DryRun:      isDryRun(cmd),
 		}
 
 		return manager.Merge(sourceBranch, options)
@@ -42,4 +64,9 @@ func init() {
 	rootCmd.AddCommand(mergeCmd)
 
 	mergeCmd.Flags().StringP("message", "m", "", "custom merge commit message")
+	mergeCmd.Flags().Bool("ignore-dirty", false, "merge even if the working directory has uncommitted changes")
+	mergeCmd.Flags().Bool("skip-checks", false, "skip pre_merge_checks defined in .wtreerc")
+	mergeCmd.Flags().Bool("signoff", false, "add a Signed-off-by trailer to the merge commit")
+	mergeCmd.Flags().BoolP("gpg-sign", "S", false, "GPG-sign the merge commit")
+	mergeCmd.Flags().String("gpg-key", "", "GPG key ID to sign with (implies --gpg-sign)")
 }
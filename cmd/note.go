@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var noteCmd = &cobra.Command{
+	Use:   "note",
+	Short: "Leave notes on worktrees",
+	Long: `Leave free-form notes on a worktree to remember where you left off.
+
+Notes are kept in the worktree's own git directory, so they go away with the
+worktree. The latest ones are shown by 'wtree status', and the latest note by
+'wtree list' for worktrees whose branch has no description.
+
+Examples:
+  wtree note add "waiting on API review, then wire up retries"
+  wtree note add feature-auth "token refresh still flaky"
+  wtree note list                      # Notes of the current worktree
+  wtree status --todos                 # Also count TODO/FIXME added vs main`,
+}
+
+var noteAddCmd = &cobra.Command{
+	Use:   "add [branch] <text>",
+	Short: "Add a note to a worktree (the current one by default)",
+	Args:  cobra.RangeArgs(1, 2),
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return completeExistingWorktrees(cmd, args, toComplete)
+		}
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	},
+	Annotations: capabilities(capRepo),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		manager, err := setupManager()
+		if err != nil {
+			return err
+		}
+
+		identifier, text := "", args[0]
+		if len(args) == 2 {
+			identifier, text = args[0], args[1]
+		}
+		return manager.AddNote(identifier, text, isDryRun(cmd))
+	},
+}
+
+var noteListCmd = &cobra.Command{
+	Use:               "list [branch]",
+	Short:             "List a worktree's notes, oldest first",
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeExistingWorktrees,
+	Annotations:       capabilities(capRepo, capReadOnly),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		manager, err := setupManager()
+		if err != nil {
+			return err
+		}
+
+		identifier := ""
+		if len(args) > 0 {
+			identifier = args[0]
+		}
+		return manager.ListNotes(identifier)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(noteCmd)
+
+	noteCmd.AddCommand(noteAddCmd)
+	noteCmd.AddCommand(noteListCmd)
+}
@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/awhite/wtree/internal/ui"
+	"github.com/awhite/wtree/internal/worktree"
+	"github.com/awhite/wtree/pkg/types"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
+)
+
+// noOnboardingEnv turns off the first-run setup, e.g. for scripts that run
+// wtree in a terminal
+const noOnboardingEnv = "WTREE_NO_ONBOARDING"
+
+// globalConfigPath returns where the global config file is written
+func globalConfigPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(home, ".config", "wtree", "config.yaml"), nil
+}
+
+// needsOnboarding reports whether to run the first-run setup before cmd:
+// there is no global config yet, someone is at the terminal to answer, and
+// the command may write to disk
+func needsOnboarding(cmd *cobra.Command) bool {
+	// Hooks running wtree get a lock token; they are never a first run
+	if cfgFile != "" || viper.ConfigFileUsed() != "" || os.Getenv(noOnboardingEnv) != "" || os.Getenv(worktree.LockTokenEnv) != "" {
+		return false
+	}
+	if isDryRun(cmd) || hasCapability(cmd, capReadOnly) || !hasCapability(cmd, capRepo) {
+		return false
+	}
+	return isTerminal(os.Stdin) && isTerminal(os.Stdout)
+}
+
+// isTerminal reports whether f is an interactive terminal
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// runOnboarding asks for the settings new users most often want to change,
+// writes them with the defaults to the global config file, and loads it
+func runOnboarding(path string) error {
+	prompt := ui.NewManager(true, false)
+	config := types.DefaultWTreeConfig()
+
+	prompt.Header("Welcome to wtree")
+	prompt.Info("No global config found; answer a few questions to create %s", path)
+	prompt.Info("(press Enter to keep a default; set %s=1 to skip this)", noOnboardingEnv)
+
+	editor := config.Editor
+	if env := os.Getenv("VISUAL"); env != "" {
+		editor = env
+	} else if env := os.Getenv("EDITOR"); env != "" {
+		editor = env
+	}
+	var err error
+	if config.Editor, err = prompt.Ask("Editor for --open", editor); err != nil {
+		return err
+	}
+
+	parent, err := prompt.Ask("Directory for new worktrees (empty: next to each repository)", "")
+	if err != nil {
+		return err
+	}
+	config.Paths.WorktreeParent = parent
+
+	colors, err := prompt.Ask("Colored output? (y/n)", "y")
+	if err != nil {
+		return err
+	}
+	config.UI.Colors = !strings.HasPrefix(strings.ToLower(colors), "n")
+
+	github, err := prompt.Ask("Enable pull request commands (wtree pr)? (y/n)", "y")
+	if err != nil {
+		return err
+	}
+	if !strings.HasPrefix(strings.ToLower(github), "n") {
+		if config.GitHub.CLICommand, err = prompt.Ask("GitHub CLI command", config.GitHub.CLICommand); err != nil {
+			return err
+		}
+		if _, err := exec.LookPath(config.GitHub.CLICommand); err != nil {
+			prompt.Warning("%s was not found; install the GitHub CLI (https://cli.github.com) to use wtree pr", config.GitHub.CLICommand)
+		}
+	}
+
+	if err := writeGlobalConfig(path, config); err != nil {
+		return err
+	}
+	prompt.Success("Created %s (see 'wtree config docs' for every setting)", path)
+	prompt.Info("")
+
+	viper.SetConfigFile(path)
+	return viper.ReadInConfig()
+}
+
+// writeGlobalConfig writes config to path, creating its directory
+func writeGlobalConfig(path string, config *types.WTreeConfig) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+	data, err := yaml.Marshal(config)
+	if err != nil {
+		return fmt.Errorf("failed to marshal config: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"github.com/awhite/wtree/internal/worktree"
+	"github.com/awhite/wtree/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+// outputFormat is the global --output format: text, json or yaml
+var outputFormat string
+
+// structuredOutput reports whether --output asks for JSON or YAML
+func structuredOutput() bool {
+	return outputFormat == worktree.OutputJSON || outputFormat == worktree.OutputYAML
+}
+
+// writeOutput prints a report in the --output format
+func writeOutput(cmd *cobra.Command, report interface{}) error {
+	return worktree.WriteOutput(cmd.OutOrStdout(), outputFormat, report)
+}
+
+// checkOutputFormat rejects an unknown --output, and JSON or YAML for
+// commands without capOutput
+func checkOutputFormat(cmd *cobra.Command) error {
+	if err := worktree.ValidateOutputFormat(outputFormat); err != nil {
+		return err
+	}
+	if structuredOutput() && !hasCapability(cmd, capOutput) {
+		return types.NewValidationError("output",
+			"--output "+outputFormat+" is supported by list, status, pr list and cleanup --dry-run", nil)
+	}
+	return nil
+}
+
+// rejectWithOutput fails when a flag that changes the text output is
+// combined with --output json or yaml
+func rejectWithOutput(cmd *cobra.Command, flags ...string) error {
+	if !structuredOutput() {
+		return nil
+	}
+	for _, flag := range flags {
+		if cmd.Flags().Changed(flag) {
+			return types.NewValidationError("output", "--"+flag+" cannot be combined with --output "+outputFormat, nil)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"github.com/awhite/wtree/internal/worktree"
+	"github.com/spf13/cobra"
+)
+
+var exportPatchCmd = &cobra.Command{
+	Use:   "export-patch [branch]",
+	Short: "Export a branch's commits as patch files or a git bundle",
+	Long: `Write the commits a branch has over a base as patch files, one per commit,
+for mailing with git send-email or applying elsewhere, or with --bundle as a
+single git bundle for carrying to a machine without access to the remote.
+
+Without a branch, the current worktree's branch is exported. The base
+defaults to the branch checked out in the main worktree. Only commits are
+exported; uncommitted changes in the branch's worktree are left out.
+
+Examples:
+  wtree export-patch feature -o out/               # out/0001-....patch, ...
+  wtree export-patch feature --since release-1.2 -o out/
+  wtree export-patch feature --bundle -o feature.bundle`,
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeBranchNames,
+	Annotations:       capabilities(capRepo),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		manager, err := setupManager()
+		if err != nil {
+			return err
+		}
+
+		branch := ""
+		if len(args) > 0 {
+			branch = args[0]
+		}
+		since, _ := cmd.Flags().GetString("since")
+		out, _ := cmd.Flags().GetString("out")
+		bundle, _ := cmd.Flags().GetBool("bundle")
+
+		options := worktree.ExportPatchOptions{
+			Since:  since,
+			Output: out,
+			Bundle: bundle,
+			DryRun: isDryRun(cmd),
+		}
+
+		return manager.ExportPatch(branch, options)
+	},
+}
+
+var importPatchCmd = &cobra.Command{
+	Use:   "import-patch <patches> <new-branch>",
+	Short: "Create a worktree from exported patches or a git bundle",
+	Long: `Create a new branch and worktree from what export-patch wrote: a
+directory of .patch files (applied in name order with git am), a single
+patch or mailbox file, or a git bundle.
+
+Patches are applied on top of --from, by default the branch checked out in
+the main worktree. A bundle carries its own history; the repository must
+already have the commits it was made against.
+
+Examples:
+  wtree import-patch out/ feature
+  wtree import-patch fix.patch hotfix --from release-1.2
+  wtree import-patch feature.bundle feature`,
+	Args:        cobra.ExactArgs(2),
+	Annotations: capabilities(capRepo),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		manager, err := setupManager()
+		if err != nil {
+			return err
+		}
+
+		from, _ := cmd.Flags().GetString("from")
+		openEditor, _ := cmd.Flags().GetBool("open")
+
+		options := worktree.ImportPatchOptions{
+			From:       from,
+			OpenEditor: openEditor,
+			DryRun:     isDryRun(cmd),
+		}
+
+		return manager.ImportPatch(args[0], args[1], options)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(exportPatchCmd)
+	rootCmd.AddCommand(importPatchCmd)
+
+	exportPatchCmd.Flags().String("since", "", "base to export commits since (default: the main worktree's branch)")
+	exportPatchCmd.Flags().StringP("out", "o", "", "directory for the patches, or the bundle file (default: current directory)")
+	exportPatchCmd.Flags().Bool("bundle", false, "write a git bundle instead of patch files")
+	_ = exportPatchCmd.RegisterFlagCompletionFunc("since", completeBranchNames)
+
+	importPatchCmd.Flags().String("from", "", "branch or commit to apply the patches to (default: the main worktree's branch)")
+	importPatchCmd.Flags().BoolP("open", "o", false, "open the new worktree in the editor")
+	_ = importPatchCmd.RegisterFlagCompletionFunc("from", completeBranchNames)
+}
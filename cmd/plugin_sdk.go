@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	pluginsdk "github.com/awhite/wtree/pkg/plugin"
+	"github.com/spf13/cobra"
+)
+
+// pluginContext builds the context handed to plugins from the shared manager
+func pluginContext() (*pluginsdk.Context, error) {
+	wtreeManager, err := setupManager()
+	if err != nil {
+		return nil, fmt.Errorf("failed to setup wtree manager: %w", err)
+	}
+
+	return &pluginsdk.Context{
+		WorktreeManager: wtreeManager,
+		GitRepo:         wtreeManager.GetRepository(),
+		ConfigManager:   wtreeManager.GetConfigManager(),
+		UIManager:       wtreeManager.GetUIManager(),
+		PluginData:      make(map[string]interface{}),
+	}, nil
+}
+
+// registerSDKCommands adds the commands compiled-in plugins registered with
+// pkg/plugin. Like aliases, they can't shadow a built-in command.
+func registerSDKCommands(root *cobra.Command) {
+	for _, command := range pluginsdk.Commands() {
+		if cmd, _, err := root.Find([]string{command.Name}); err == nil && cmd != root {
+			if verbose {
+				fmt.Fprintf(os.Stderr, "Warning: plugin command '%s' shadows a built-in command and is ignored\n", command.Name)
+			}
+			continue
+		}
+
+		run := command.Run
+		root.AddCommand(&cobra.Command{
+			Use:   command.Name,
+			Short: command.Short,
+			Long:  command.Long,
+			RunE: func(cmd *cobra.Command, args []string) error {
+				ctx, err := pluginContext()
+				if err != nil {
+					return err
+				}
+				return run(ctx, args)
+			},
+		})
+	}
+}
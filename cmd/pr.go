@@ -37,9 +37,10 @@ PR branch locally, and creates a worktree with the naming pattern
 Examples:
   wtree pr create 123              # Create worktree for PR #123
   wtree pr create 456 -o           # Create and open in editor
-  wtree pr create 789 --force      # Force creation even if path exists`,
-	Aliases: []string{"checkout", "co"},
-	Args:    cobra.ExactArgs(1),
+  wtree pr create 789 --overwrite-path  # Replace whatever is at the path`,
+	Aliases:     []string{"checkout", "co"},
+	Args:        cobra.ExactArgs(1),
+	Annotations: capabilities(capRepo),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		// Parse PR number
 		prNumber, err := strconv.Atoi(args[0])
@@ -51,6 +52,10 @@ Examples:
 		if err != nil {
 			return err
 		}
+		applyEditorFlags(cmd, manager)
+		if err := applyOnConflict(cmd, manager); err != nil {
+			return err
+		}
 
 		// Create GitHub client
 		globalConfig := manager.GetGlobalConfig()
@@ -66,8 +71,8 @@ Examples:
 		openEditor, _ := cmd.Flags().GetBool("open")
 
 		options := worktree.PRWorktreeOptions{
-			Force:      force,
-			OpenEditor: openEditor,
+			OverwritePath: riskFlag(cmd, "overwrite-path"),
+			OpenEditor:    openEditor,
 		}
 
 		return prManager.CreatePRWorktree(prNumber, options)
@@ -76,10 +81,11 @@ Examples:
 
 // For convenience, also allow `wtree pr <number>` as a shortcut
 var prNumberCmd = &cobra.Command{
-	Use:   "<pr-number>",
-	Short: "Create worktree for PR (shorthand)",
-	Long:  `Create worktree for a GitHub PR. This is a shorthand for 'wtree pr create <pr-number>'.`,
-	Args:  cobra.ExactArgs(1),
+	Use:         "<pr-number>",
+	Short:       "Create worktree for PR (shorthand)",
+	Long:        `Create worktree for a GitHub PR. This is a shorthand for 'wtree pr create <pr-number>'.`,
+	Args:        cobra.ExactArgs(1),
+	Annotations: capabilities(capRepo),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		// This is the same as pr create, but as a direct subcommand
 		return prCreateCmd.RunE(cmd, args)
@@ -97,8 +103,10 @@ PR worktree. Includes both active and inactive PR worktrees.
 
 Examples:
   wtree pr list                    # List all PR worktrees
-  wtree pr list --verbose          # List with detailed information`,
-	Aliases: []string{"ls"},
+  wtree pr list --verbose          # List with detailed information
+  wtree pr list --output json      # Structured output for jq`,
+	Aliases:     []string{"ls"},
+	Annotations: capabilities(capRepo, capOutput),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		manager, err := setupManager()
 		if err != nil {
@@ -115,6 +123,14 @@ Examples:
 		// Create PR manager
 		prManager := worktree.NewPRManager(manager, githubClient)
 
+		if structuredOutput() {
+			reports, err := prManager.ListPRReport()
+			if err != nil {
+				return err
+			}
+			return writeOutput(cmd, reports)
+		}
+
 		// Get all PR worktrees
 		prWorktrees, err := prManager.ListPRWorktrees()
 		if err != nil {
@@ -131,7 +147,7 @@ Examples:
 		ui.Header("GitHub PR Worktrees")
 
 		table := ui.NewTable()
-		table.SetHeaders("PR", "Title", "Author", "State", "Path")
+		table.SetHeaders("PR", "Title", "Author", "State", "Updated", "Path")
 
 		for _, prWt := range prWorktrees {
 			title := prWt.PRTitle
@@ -157,6 +173,7 @@ Examples:
 				title,
 				author,
 				state,
+				ui.Time(prWt.LastUpdate),
 				prWt.Path,
 			)
 		}
@@ -173,7 +190,9 @@ var prCleanCmd = &cobra.Command{
 
 By default, cleans up worktrees for closed and merged PRs. You can
 specify different criteria using flags. Use --dry-run to preview
-what would be cleaned up.
+what would be cleaned up, including the state GitHub reports for each PR.
+Worktrees of PRs that are still open are kept unless --allow-protected is
+given.
 
 Examples:
   wtree pr clean                   # Clean up closed/merged PRs
@@ -181,7 +200,8 @@ Examples:
   wtree pr clean --state merged    # Clean up only merged PRs
   wtree pr clean --dry-run         # Preview cleanup without executing
   wtree pr clean --limit 10        # Clean up at most 10 worktrees`,
-	Aliases: []string{"cleanup"},
+	Aliases:     []string{"cleanup"},
+	Annotations: capabilities(capRepo),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		manager, err := setupManager()
 		if err != nil {
@@ -200,8 +220,8 @@ Examples:
 
 		// Get flag values
 		state, _ := cmd.Flags().GetString("state")
-		dryRun, _ := cmd.Flags().GetBool("dry-run")
 		limit, _ := cmd.Flags().GetInt("limit")
+		allowProtected, _ := cmd.Flags().GetBool("allow-protected")
 
 		// Default state to "closed" if not specified
 		if state == "" {
@@ -210,15 +230,54 @@ Examples:
 
 		options := worktree.PRCleanupOptions{
 			State:  state,
-			Force:  force,
-			DryRun: dryRun,
+			Yes:    assumeYes(),
+			DryRun: isDryRun(cmd),
 			Limit:  limit,
+
+			AllowProtected: allowProtected,
 		}
 
 		return prManager.CleanupPRWorktrees(options)
 	},
 }
 
+var prSyncLocalCmd = &cobra.Command{
+	Use:   "sync-local",
+	Short: "Copy your local overrides into every PR worktree",
+	Long: `Copy your personal local overrides from the main checkout into every
+existing PR worktree in one pass, so review environments behave like your
+main checkout.
+
+The files copied are review.local_files from the global config (by default
+.wtreerc.local, .vscode/settings.json, *.code-workspace and
+.idea/workspace.xml) plus any .env* file at the top of the main checkout
+containing the line "# wtree: shareable". Files that are already up to date
+are skipped.
+
+pre_sync and post_sync hooks run in each PR worktree around its copy; a
+failing pre_sync skips that worktree.
+
+Examples:
+  wtree pr sync-local              # Copy overrides into all PR worktrees
+  wtree pr sync-local --dry-run    # Show which files would be copied`,
+	Annotations: capabilities(capRepo),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		manager, err := setupManager()
+		if err != nil {
+			return err
+		}
+
+		// Only local files are involved, so no GitHub client is needed
+		prManager := worktree.NewPRManager(manager, nil)
+
+		options := worktree.PRSyncLocalOptions{
+			DryRun: isDryRun(cmd),
+		}
+
+		return prManager.SyncLocalOverrides(options)
+	},
+}
+
 func init() {
 	rootCmd.AddCommand(prCmd)
 
@@ -226,15 +285,19 @@ func init() {
 	prCmd.AddCommand(prCreateCmd)
 	prCmd.AddCommand(prListCmd)
 	prCmd.AddCommand(prCleanCmd)
+	prCmd.AddCommand(prSyncLocalCmd)
 
 	// Add the hidden shorthand command
 	prCmd.AddCommand(prNumberCmd)
 
 	// Flags for pr create
 	prCreateCmd.Flags().BoolP("open", "o", false, "open in editor after creation")
+	addOverwritePathFlag(prCreateCmd)
+	addOnConflictFlag(prCreateCmd)
+	addEditorFlags(prCreateCmd)
 
 	// Flags for pr clean
 	prCleanCmd.Flags().String("state", "", "PR state to clean up (open, closed, merged, all)")
-	prCleanCmd.Flags().Bool("dry-run", false, "show what would be cleaned up without executing")
+	prCleanCmd.Flags().Bool("allow-protected", false, "also remove worktrees whose PR is still open")
 	prCleanCmd.Flags().Int("limit", 0, "maximum number of PRs to clean up (0 = no limit)")
 }
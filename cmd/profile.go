@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"runtime/pprof"
+	"runtime/trace"
+)
+
+var (
+	showTimings bool
+	cpuProfile  string
+	traceFile   string
+)
+
+// stopProfiling ends whatever startProfiling started; Execute calls it once
+// the command has run
+var stopProfiling = func() {}
+
+// startProfiling starts the CPU profile and execution trace requested with
+// --cpuprofile and --trace
+func startProfiling() error {
+	var stops []func()
+	stopProfiling = func() {
+		for i := len(stops) - 1; i >= 0; i-- {
+			stops[i]()
+		}
+		stops = nil
+	}
+
+	if cpuProfile != "" {
+		f, err := os.Create(cpuProfile)
+		if err != nil {
+			return fmt.Errorf("failed to create CPU profile: %w", err)
+		}
+		if err := pprof.StartCPUProfile(f); err != nil {
+			f.Close()
+			return fmt.Errorf("failed to start CPU profile: %w", err)
+		}
+		stops = append(stops, func() {
+			pprof.StopCPUProfile()
+			f.Close()
+		})
+	}
+
+	if traceFile != "" {
+		f, err := os.Create(traceFile)
+		if err != nil {
+			stopProfiling()
+			return fmt.Errorf("failed to create trace file: %w", err)
+		}
+		if err := trace.Start(f); err != nil {
+			f.Close()
+			stopProfiling()
+			return fmt.Errorf("failed to start trace: %w", err)
+		}
+		stops = append(stops, func() {
+			trace.Stop()
+			f.Close()
+		})
+	}
+
+	return nil
+}
+
+func init() {
+	rootCmd.PersistentFlags().BoolVar(&showTimings, "timings", false, "report how long each phase of create/delete/cleanup took")
+
+	// Debugging aids for maintainers diagnosing slow environments
+	rootCmd.PersistentFlags().StringVar(&cpuProfile, "cpuprofile", "", "write a CPU profile to this file (go tool pprof)")
+	rootCmd.PersistentFlags().StringVar(&traceFile, "trace", "", "write an execution trace to this file (go tool trace)")
+	_ = rootCmd.PersistentFlags().MarkHidden("cpuprofile")
+	_ = rootCmd.PersistentFlags().MarkHidden("trace")
+}
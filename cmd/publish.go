@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"github.com/awhite/wtree/internal/worktree"
+	"github.com/spf13/cobra"
+)
+
+var publishCmd = &cobra.Command{
+	Use:   "publish [branch-or-path]",
+	Short: "Push a worktree's branch and set its upstream",
+	Long: `Push a worktree's branch to a remote and set it as the branch's upstream,
+so ahead/behind information and sync work for it.
+
+Without an argument, the branch of the current worktree is published. The
+remote defaults to git's remote.pushDefault, or origin.
+
+Examples:
+  wtree publish                        # Publish the current worktree's branch
+  wtree publish feature-branch         # Publish another worktree's branch
+  wtree publish feature --remote fork  # Push to a different remote`,
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeExistingWorktrees,
+	Annotations:       capabilities(capRepo),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		manager, err := setupManager()
+		if err != nil {
+			return err
+		}
+
+		identifier := ""
+		if len(args) > 0 {
+			identifier = args[0]
+		}
+		remote, _ := cmd.Flags().GetString("remote")
+
+		options := worktree.PublishOptions{
+			Remote: remote,
+			DryRun: isDryRun(cmd),
+		}
+
+		return manager.Publish(identifier, options)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(publishCmd)
+
+	publishCmd.Flags().String("remote", "", "remote to push to (default: remote.pushDefault or origin)")
+}
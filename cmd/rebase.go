@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"github.com/awhite/wtree/internal/worktree"
+	"github.com/spf13/cobra"
+)
+
+var rebaseCmd = &cobra.Command{
+	Use:   "rebase <target-branch>",
+	Short: "Rebase the current worktree's branch",
+	Long: `Rebase the current worktree's branch onto the target branch.
+
+The working directory must be clean. This runs pre_rebase and post_rebase
+hooks if configured in .wtreerc, so a project can regenerate files after its
+commits are replayed.
+
+A rebase that stops with conflicts is left in progress: resolve them, then
+run 'wtree rebase --continue', which finishes it and runs post_rebase, or
+'wtree rebase --abort'. A new rebase is refused while one is stopped.
+
+Use --signoff and --gpg-sign for compliance workflows; the wtree.signoff and
+wtree.gpgSign git config defaults apply as they do to merges.
+
+With --dry-run nothing is rebased: how many commits would be replayed and
+how far the target has moved on are shown instead.
+
+Examples:
+  wtree rebase main                    # Replay the branch on top of main
+  wtree rebase -i main                 # Edit the commits first
+  wtree rebase --onto main feature-a   # Move commits after feature-a onto main
+  wtree rebase --dry-run main          # Preview the rebase
+  wtree rebase --continue              # After resolving conflicts
+  wtree rebase --abort                 # Give up on a stopped rebase`,
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeBranchNames,
+	Annotations:       capabilities(capRepo),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		manager, err := setupManager()
+		if err != nil {
+			return err
+		}
+
+		target := ""
+		if len(args) > 0 {
+			target = args[0]
+		}
+
+		// Get flag values
+		onto, _ := cmd.Flags().GetString("onto")
+		interactive, _ := cmd.Flags().GetBool("interactive")
+		signoff, _ := cmd.Flags().GetBool("signoff")
+		gpgSign, _ := cmd.Flags().GetBool("gpg-sign")
+		gpgKeyID, _ := cmd.Flags().GetString("gpg-key")
+		continueRebase, _ := cmd.Flags().GetBool("continue")
+		abort, _ := cmd.Flags().GetBool("abort")
+
+		options := worktree.RebaseOptions{
+			Onto:        onto,
+			Interactive: interactive,
+			Signoff:     signoff,
+			GPGSign:     gpgSign,
+			GPGKeyID:    gpgKeyID,
+			Continue:    continueRebase,
+			Abort:       abort,
+			DryRun:      isDryRun(cmd),
+		}
+
+		return manager.Rebase(target, options)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(rebaseCmd)
+
+	rebaseCmd.Flags().String("onto", "", "replay the commits after the target branch onto this branch instead")
+	rebaseCmd.Flags().BoolP("interactive", "i", false, "edit the list of commits to replay (git rebase --interactive)")
+	rebaseCmd.Flags().Bool("signoff", false, "add a Signed-off-by trailer to each rebased commit")
+	rebaseCmd.Flags().BoolP("gpg-sign", "S", false, "GPG-sign the rebased commits")
+	rebaseCmd.Flags().String("gpg-key", "", "GPG key ID to sign with (implies --gpg-sign)")
+	rebaseCmd.Flags().Bool("continue", false, "finish a rebase stopped by conflicts, after resolving them")
+	rebaseCmd.Flags().Bool("abort", false, "abandon a rebase stopped by conflicts")
+	_ = rebaseCmd.RegisterFlagCompletionFunc("onto", completeBranchNames)
+}
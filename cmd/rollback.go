@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"github.com/awhite/wtree/internal/worktree"
+	"github.com/awhite/wtree/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+var rollbackCmd = &cobra.Command{
+	Use:   "rollback --last",
+	Short: "Finish the rollback of an interrupted operation",
+	Long: `When create fails, wtree undoes what it did so far (the branch, the worktree,
+its directory) and reports each step. The steps are also kept on disk while
+the operation runs, so if wtree is killed before it can roll back, or a
+rollback step fails, 'wtree rollback --last' replays what is left of the
+latest such operation.
+
+Examples:
+  wtree rollback --last --dry-run      # Show the steps that would run
+  wtree rollback --last --yes          # Run them without asking`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	Annotations:  capabilities(capRepo),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		manager, err := setupManager()
+		if err != nil {
+			return err
+		}
+
+		if last, _ := cmd.Flags().GetBool("last"); !last {
+			return types.NewValidationError("rollback", "--last is required: it is the only rollback there is to replay", nil)
+		}
+		return manager.RollbackLast(worktree.RollbackOptions{DryRun: isDryRun(cmd), Yes: assumeYes()})
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(rollbackCmd)
+
+	rollbackCmd.Flags().Bool("last", false, "replay the rollback of the latest interrupted operation")
+}
@@ -1,8 +1,10 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/awhite/wtree/internal/config"
 	"github.com/awhite/wtree/internal/git"
@@ -15,10 +17,12 @@ import (
 )
 
 var (
-	cfgFile string
-	verbose bool
-	dryRun  bool
-	force   bool
+	cfgFile   string
+	verbose   bool
+	force     bool
+	yes       bool
+	lockWait  time.Duration
+	stealLock bool
 )
 
 // rootCmd represents the base command when called without any subcommands
@@ -28,10 +32,6 @@ var (
 	date    = "unknown" // Set at build time
 )
 
-// Use blank identifier to indicate these are intentionally unused for now
-var _ = commit
-var _ = date
-
 var rootCmd = &cobra.Command{
 	Use:     "wtree",
 	Short:   "Generic git worktree manager",
@@ -48,19 +48,43 @@ Examples:
   wtree delete feature-branch      # Delete worktree
   wtree switch main                # Switch to main worktree
   wtree merge feature-branch       # Merge branch into current`,
+	PersistentPreRunE: prepareCommand,
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
 func Execute() error {
-	// Initialize plugins if not in plugin management mode
-	if err := initializePlugins(); err != nil {
-		// Log warning but don't fail startup
-		if verbose {
-			fmt.Fprintf(os.Stderr, "Warning: plugin initialization failed: %v\n", err)
+	// Add commands of plugins compiled in through pkg/plugin
+	registerSDKCommands(rootCmd)
+
+	// Register aliases from the global config as subcommands
+	registerAliases(rootCmd, os.Args[1:])
+
+	// Preset flags from the global config's defaults section
+	applyCommandDefaults(rootCmd, os.Args[1:])
+
+	// Initialize plugins only when the arguments may name a plugin command
+	if wantsPlugins(rootCmd, os.Args[1:]) {
+		if err := initializePlugins(); err != nil {
+			// Log warning but don't fail startup
+			if verbose {
+				fmt.Fprintf(os.Stderr, "Warning: plugin initialization failed: %v\n", err)
+			}
 		}
 	}
 	
-	return rootCmd.Execute()
+	err := rootCmd.Execute()
+	stopInterrupts()
+	if worktree.Interrupted() && !errors.Is(err, types.ErrInterrupted) {
+		err = types.ErrInterrupted
+	}
+	// Also after failures, which may have changed something before failing
+	if recordsState && sharedManager != nil {
+		sharedManager.RecordState()
+	}
+	stopProfiling()
+	// Background jobs record how they finished for `wtree jobs`
+	worktree.FinishJob(os.Getenv(worktree.JobFileEnv), err)
+	return err
 }
 
 func init() {
@@ -69,8 +93,16 @@ func init() {
 	// Global flags
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.config/wtree/config.yaml)")
 	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "verbose output")
-	rootCmd.PersistentFlags().BoolVar(&dryRun, "dry-run", false, "show what would happen without executing")
-	rootCmd.PersistentFlags().BoolVarP(&force, "force", "f", false, "skip confirmations and force operations")
+	rootCmd.PersistentFlags().Bool("dry-run", false, "show what would happen without executing")
+	rootCmd.PersistentFlags().BoolVarP(&yes, "yes", "y", false, "answer yes to confirmations")
+	rootCmd.PersistentFlags().BoolVarP(&force, "force", "f", false, "shorthand for --yes and every risky flag the command has")
+	_ = rootCmd.PersistentFlags().MarkDeprecated("force", "use --yes, --overwrite-path, --ignore-dirty or --force-branch-delete")
+	rootCmd.PersistentFlags().DurationVar(&lockWait, "wait", 0, "keep retrying a held operation lock for this long (e.g. 2m)")
+	rootCmd.PersistentFlags().BoolVar(&stealLock, "steal", false, "offer to clear an operation lock held by another process")
+	rootCmd.PersistentFlags().StringVar(&outputFormat, "output", worktree.OutputText, "output format for list, status, pr list and cleanup --dry-run: text, json or yaml")
+	_ = rootCmd.RegisterFlagCompletionFunc("output", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{worktree.OutputText, worktree.OutputJSON, worktree.OutputYAML}, cobra.ShellCompDirectiveNoFileComp
+	})
 }
 
 // initConfig reads in config file and ENV variables if set.
@@ -101,8 +133,16 @@ func initConfig() {
 	}
 }
 
-// setupManager creates and initializes the worktree manager
+// sharedManager is the worktree manager shared by everything in this run
+var sharedManager *worktree.Manager
+
+// setupManager returns the worktree manager, creating and initializing it on
+// first use
 func setupManager() (*worktree.Manager, error) {
+	if sharedManager != nil {
+		return sharedManager, nil
+	}
+
 	// Initialize git repository
 	repo, err := git.NewRepository("")
 	if err != nil {
@@ -114,17 +154,26 @@ func setupManager() (*worktree.Manager, error) {
 
 	// Initialize UI manager
 	colors := !viper.GetBool("no_color")
+	if viper.IsSet("ui.colors") {
+		colors = colors && viper.GetBool("ui.colors")
+	}
 	uiMgr := ui.NewManager(colors, verbose)
 
 	// Create worktree manager
-	manager := worktree.NewManager(repo, configMgr, uiMgr)
+	m := worktree.NewManager(repo, configMgr, uiMgr)
+	m.SetLockOptions(lockWait, stealLock)
+	m.SetTimings(showTimings)
+	if readOnly {
+		m.SetReadOnly()
+	}
 
 	// Initialize manager (loads configs)
-	if err := manager.Initialize(); err != nil {
+	if err := m.Initialize(); err != nil {
 		return nil, err
 	}
 
-	return manager, nil
+	sharedManager = m
+	return sharedManager, nil
 }
 
 // Global plugin manager instance
@@ -138,19 +187,10 @@ func initializePlugins() error {
 		return nil
 	}
 	
-	// Setup core wtree components
-	wtreeManager, err := setupManager()
-	if err != nil {
-		return fmt.Errorf("failed to setup wtree manager: %w", err)
-	}
-
 	// Create plugin context
-	pluginCtx := &types.PluginContext{
-		WorktreeManager: wtreeManager,
-		GitRepo:         wtreeManager.GetRepository(),
-		ConfigManager:   wtreeManager.GetConfigManager(),
-		UIManager:       wtreeManager.GetUIManager(),
-		PluginData:      make(map[string]interface{}),
+	pluginCtx, err := pluginContext()
+	if err != nil {
+		return err
 	}
 
 	// Get plugin directories
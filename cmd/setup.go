@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/awhite/wtree/internal/worktree"
+	"github.com/awhite/wtree/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+var setupCmd = &cobra.Command{
+	Use:   "setup <branch-or-path>",
+	Short: "Re-run project setup in an existing worktree",
+	Long: `Re-apply the project's copy_files, link_files, and git_hooks settings to an
+existing worktree.
+
+Files whose size and modification time already match are skipped, so
+running setup repeatedly only copies what changed. Destinations that exist
+with different content are conflicts: you are asked per file whether to keep
+it, overwrite it, see a diff, or rename it to <name>.orig first. Use
+--on-conflict to decide up front; without a terminal, conflicts are kept.
+
+Examples:
+  wtree setup feature-branch                         # Refresh copied and linked files
+  wtree setup --hooks feature-branch                 # Also run post_create hooks
+  wtree setup --skip-copy feature-branch             # Refresh links only
+  wtree setup --on-conflict overwrite feature-branch # Replace files edited in the worktree`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeExistingWorktrees,
+	Annotations:       capabilities(capRepo),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		manager, err := setupManager()
+		if err != nil {
+			return err
+		}
+		if err := applyOnConflict(cmd, manager); err != nil {
+			return err
+		}
+
+		runHooks, _ := cmd.Flags().GetBool("hooks")
+		skipCopy, _ := cmd.Flags().GetBool("skip-copy")
+		skipLink, _ := cmd.Flags().GetBool("skip-link")
+
+		options := worktree.SetupOptions{
+			RunHooks: runHooks,
+			SkipCopy: skipCopy,
+			SkipLink: skipLink,
+		}
+
+		return manager.Setup(args[0], options)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(setupCmd)
+
+	setupCmd.Flags().Bool("hooks", false, "also run post_create hooks")
+	addSkipFilesFlags(setupCmd)
+	addOnConflictFlag(setupCmd)
+}
+
+// addSkipFilesFlags adds --skip-copy and --skip-link to a command that sets
+// up worktrees
+func addSkipFilesFlags(cmd *cobra.Command) {
+	cmd.Flags().Bool("skip-copy", false, "skip copy_files")
+	cmd.Flags().Bool("skip-link", false, "skip link_files")
+}
+
+// addOnConflictFlag adds --on-conflict to a command that copies and links files
+func addOnConflictFlag(cmd *cobra.Command) {
+	cmd.Flags().String("on-conflict", "", "when a copied or linked file already exists and differs: ask, keep, overwrite, or rename")
+	_ = cmd.RegisterFlagCompletionFunc("on-conflict", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{worktree.OnConflictAsk, worktree.OnConflictKeep, worktree.OnConflictOverwrite, worktree.OnConflictRename}, cobra.ShellCompDirectiveNoFileComp
+	})
+}
+
+// applyOnConflict validates --on-conflict and passes it on to the manager
+func applyOnConflict(cmd *cobra.Command, manager *worktree.Manager) error {
+	policy, _ := cmd.Flags().GetString("on-conflict")
+	switch policy {
+	case "", worktree.OnConflictAsk, worktree.OnConflictKeep, worktree.OnConflictOverwrite, worktree.OnConflictRename:
+	default:
+		return types.NewValidationError("on-conflict",
+			fmt.Sprintf("invalid --on-conflict '%s' (expected ask, keep, overwrite, or rename)", policy), nil)
+	}
+	manager.SetOnConflict(policy)
+	return nil
+}
@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/awhite/wtree/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+// shellInitScripts are the wtree wrapper functions shell-init prints. Each
+// runs wtree with WTREE_CD_FILE set to a fresh temporary file and changes
+// into the directory wtree writes there, if any: switch writes its target,
+// and delete the main checkout when the shell was inside the deleted
+// worktree. The exit status is wtree's.
+var shellInitScripts = map[string]string{
+	"bash": posixShellInit,
+	"zsh":  posixShellInit,
+	"fish": `function wtree --wraps wtree --description 'wtree, changing directory on switch'
+    set -l wtree_tmpdir /tmp
+    set -q TMPDIR; and set wtree_tmpdir $TMPDIR
+    set -l wtree_cd_file (mktemp $wtree_tmpdir/wtree-cd.XXXXXX); or return
+    env WTREE_CD_FILE=$wtree_cd_file wtree $argv
+    set -l wtree_status $status
+    if test -s $wtree_cd_file
+        cd (cat $wtree_cd_file); or set wtree_status $status
+    end
+    rm -f $wtree_cd_file
+    return $wtree_status
+end
+`,
+}
+
+const posixShellInit = `wtree() {
+  local wtree_cd_file wtree_status
+  wtree_cd_file="$(mktemp "${TMPDIR:-/tmp}/wtree-cd.XXXXXX")" || return
+  WTREE_CD_FILE="$wtree_cd_file" command wtree "$@"
+  wtree_status=$?
+  if [ -s "$wtree_cd_file" ]; then
+    cd -- "$(cat "$wtree_cd_file")" || wtree_status=$?
+  fi
+  rm -f -- "$wtree_cd_file"
+  return $wtree_status
+}
+`
+
+var shellInitCmd = &cobra.Command{
+	Use:   "shell-init [bash|zsh|fish]",
+	Short: "Print the shell function that lets switch change directory",
+	Long: `Print a wtree shell function for your shell's startup file. With it,
+'wtree switch feature' changes the directory of the calling shell instead of
+printing a cd command, and deleting the worktree the shell is in moves it
+back to the main checkout. Everything else runs unchanged.
+
+Without an argument the shell is taken from $SHELL. Like completion, this
+works outside a git repository.
+
+Examples:
+  eval "$(wtree shell-init bash)"     # In ~/.bashrc
+  eval "$(wtree shell-init zsh)"      # In ~/.zshrc
+  wtree shell-init fish | source      # In ~/.config/fish/config.fish`,
+	DisableFlagsInUseLine: true,
+	SilenceUsage:          true,
+	ValidArgs:             []string{"bash", "zsh", "fish"},
+	Args:                  cobra.MatchAll(cobra.MaximumNArgs(1), cobra.OnlyValidArgs),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		shell := filepath.Base(os.Getenv("SHELL"))
+		if len(args) > 0 {
+			shell = args[0]
+		}
+		script, ok := shellInitScripts[shell]
+		if !ok {
+			return types.NewValidationError("shell-init",
+				fmt.Sprintf("unsupported shell '%s'; pass bash, zsh or fish", shell), nil)
+		}
+		fmt.Fprint(cmd.OutOrStdout(), script)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(shellInitCmd)
+}
@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"github.com/awhite/wtree/internal/worktree"
+	"github.com/spf13/cobra"
+)
+
+var splitCmd = &cobra.Command{
+	Use:   "split <new-branch> [paths...]",
+	Short: "Move uncommitted changes into a new worktree",
+	Long: `Move uncommitted changes out of the current worktree into a new branch
+and worktree, for when one tree has accumulated two features.
+
+The changes to the given paths (or all changes, including untracked files,
+when no paths are given) are stashed, a new branch is created at the current
+commit with its own worktree, and the stash is applied there. The current
+worktree is left clean for those paths. If anything fails, the changes are
+put back and the new worktree and branch are removed.
+
+Examples:
+  wtree split feature-b src/b/ docs/b.md   # Move only these paths
+  wtree split feature-b                     # Move everything
+  wtree split feature-b src/b --open        # Open the new worktree afterwards`,
+	Args:        cobra.MinimumNArgs(1),
+	Annotations: capabilities(capRepo),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		manager, err := setupManager()
+		if err != nil {
+			return err
+		}
+		applyEditorFlags(cmd, manager)
+
+		openEditor, _ := cmd.Flags().GetBool("open")
+
+		options := worktree.SplitOptions{
+			OpenEditor: openEditor,
+			DryRun:     isDryRun(cmd),
+		}
+
+		return manager.Split(args[0], args[1:], options)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(splitCmd)
+
+	splitCmd.Flags().BoolP("open", "o", false, "open the new worktree in the editor")
+	addEditorFlags(splitCmd)
+}
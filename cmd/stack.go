@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"github.com/awhite/wtree/internal/worktree"
+	"github.com/spf13/cobra"
+)
+
+var stackCmd = &cobra.Command{
+	Use:   "stack [branch]",
+	Short: "Work with stacks of dependent branches",
+	Long: `Manage stacked branches, where each branch builds on the one before it and
+gets its own worktree, as in stacked-PR workflows.
+
+Each branch's parent is recorded in git config (branch.<name>.wtree-parent),
+so the stack survives across commands. Without a subcommand, the stack
+containing the current worktree's branch (or the named one) is shown.
+
+Examples:
+  wtree stack create main api ui       # api on main, ui on api, each a worktree
+  wtree stack                          # Show the stack of the current branch
+  wtree stack restack                  # Rebase each branch onto its parent`,
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeBranchNames,
+	Annotations:       capabilities(capRepo, capReadOnly),
+	RunE:              runStackShow,
+}
+
+var stackShowCmd = &cobra.Command{
+	Use:               "show [branch]",
+	Short:             "Show a stack in order, marking branches that need a restack",
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeBranchNames,
+	Annotations:       capabilities(capRepo, capReadOnly),
+	RunE:              runStackShow,
+}
+
+var stackCreateCmd = &cobra.Command{
+	Use:   "create <base> <branch>...",
+	Short: "Create a worktree for each branch, stacked in order on base",
+	Long: `Create a worktree for each branch, the first branched from base and each
+next one from the branch before it. Branches and worktrees that already exist
+are reused, so naming a stack again with more branches extends it.`,
+	Args:              cobra.MinimumNArgs(2),
+	ValidArgsFunction: completeBranchNames,
+	Annotations:       capabilities(capRepo),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		manager, err := setupManager()
+		if err != nil {
+			return err
+		}
+
+		noSetup, _ := cmd.Flags().GetBool("no-setup")
+		options := worktree.CreateOptions{
+			OverwritePath: riskFlag(cmd, "overwrite-path"),
+			DryRun:        isDryRun(cmd),
+			NoSetup:       noSetup,
+		}
+		return manager.CreateStack(args[0], args[1:], options)
+	},
+}
+
+var stackRestackCmd = &cobra.Command{
+	Use:   "restack [branch]",
+	Short: "Rebase each branch of a stack onto its parent",
+	Long: `Rebase each branch of the stack onto its parent, starting nearest the base,
+in the branch's own worktree. Only the branch's own commits are replayed, so
+this also works after a parent was amended or rebased.
+
+A branch with conflicts is left mid-rebase for you to resolve, and the
+branches stacked on it are skipped; run restack again afterwards to finish.
+Branches without a worktree or with uncommitted changes are skipped too.`,
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeBranchNames,
+	Annotations:       capabilities(capRepo),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		manager, err := setupManager()
+		if err != nil {
+			return err
+		}
+
+		return manager.Restack(stackArg(args), worktree.RestackOptions{DryRun: isDryRun(cmd)})
+	},
+}
+
+func runStackShow(cmd *cobra.Command, args []string) error {
+	manager, err := setupManager()
+	if err != nil {
+		return err
+	}
+
+	return manager.ShowStack(stackArg(args))
+}
+
+// stackArg returns the optional branch argument of the stack commands
+func stackArg(args []string) string {
+	if len(args) > 0 {
+		return args[0]
+	}
+	return ""
+}
+
+func init() {
+	rootCmd.AddCommand(stackCmd)
+
+	stackCmd.AddCommand(stackShowCmd)
+	stackCmd.AddCommand(stackCreateCmd)
+	stackCmd.AddCommand(stackRestackCmd)
+
+	stackCreateCmd.Flags().Bool("no-setup", false, "skip copy/link files and post_create hooks")
+	addOverwritePathFlag(stackCreateCmd)
+}
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"github.com/awhite/wtree/internal/worktree"
+	"github.com/awhite/wtree/pkg/types"
 	"github.com/spf13/cobra"
 )
 
@@ -15,12 +16,20 @@ This provides a comprehensive overview of your worktree ecosystem, helping you
 understand which branches need attention, which are behind their remotes,
 and which have uncommitted changes.
 
+With --check, status turns into a gate for CI or cron: it lists only the
+worktrees that violate the given limits and exits non-zero if there are any.
+
 Examples:
   wtree status                         # Show status for all worktrees
   wtree status --current               # Show only current worktree status
   wtree status --branch feature       # Show status for specific branch
-  wtree status --verbose               # Show detailed git information`,
-	Aliases: []string{"st"},
+  wtree status --verbose               # Show detailed git information
+  wtree status --todos                 # Count TODO/FIXME added/removed vs main
+  wtree status --porcelain             # Stable output for scripts (see list)
+  wtree status --watch=5s              # Redraw every 5s until Ctrl-C
+  wtree status --check --max-behind 50 --fail-on-dirty  # Fail on stale or dirty worktrees`,
+	Aliases:     []string{"st"},
+	Annotations: capabilities(capRepo, capReadOnly, capFetch, capOutput),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		manager, err := setupManager()
 		if err != nil {
@@ -31,14 +40,64 @@ Examples:
 		currentOnly, _ := cmd.Flags().GetBool("current")
 		branchFilter, _ := cmd.Flags().GetString("branch")
 		verbose, _ := cmd.Flags().GetBool("verbose")
+		porcelain, _ := cmd.Flags().GetBool("porcelain")
+		todos, _ := cmd.Flags().GetBool("todos")
+		check, _ := cmd.Flags().GetBool("check")
+		maxBehind, _ := cmd.Flags().GetInt("max-behind")
+		failOnDirty, _ := cmd.Flags().GetBool("fail-on-dirty")
+
+		limited := cmd.Flags().Changed("max-behind") || failOnDirty
+		if check && !limited {
+			return types.NewValidationError("status", "--check needs a limit: --max-behind or --fail-on-dirty", nil)
+		}
+		if limited && !check {
+			return types.NewValidationError("status", "--max-behind and --fail-on-dirty apply only with --check", nil)
+		}
+		if !cmd.Flags().Changed("max-behind") {
+			maxBehind = -1
+		} else if maxBehind < 0 {
+			return types.NewValidationError("status", "--max-behind cannot be negative", nil)
+		}
+		// A failed check is an expected outcome, not a usage error
+		cmd.SilenceUsage = check
+
+		interval, err := watchInterval(cmd)
+		if err != nil {
+			return err
+		}
+		if interval > 0 && check {
+			return types.NewValidationError("status", "--watch cannot be combined with --check", nil)
+		}
 
 		options := worktree.StatusOptions{
 			CurrentOnly:  currentOnly,
 			BranchFilter: branchFilter,
 			Verbose:      verbose,
+			Porcelain:    porcelain,
+			TODOs:        todos,
+			Check:        check,
+			MaxBehind:    maxBehind,
+			FailOnDirty:  failOnDirty,
 		}
 
-		return manager.Status(options)
+		if err := rejectWithOutput(cmd, "porcelain", "check"); err != nil {
+			return err
+		}
+		render := func() error { return manager.Status(options) }
+		if structuredOutput() {
+			render = func() error {
+				reports, err := manager.StatusReport(options)
+				if err != nil {
+					return err
+				}
+				return writeOutput(cmd, reports)
+			}
+		}
+
+		if interval > 0 {
+			return runWatching(interval, render)
+		}
+		return render()
 	},
 }
 
@@ -47,5 +106,11 @@ func init() {
 
 	statusCmd.Flags().BoolP("current", "c", false, "show only current worktree status")
 	statusCmd.Flags().StringP("branch", "b", "", "show status for specific branch")
+	statusCmd.Flags().Bool("porcelain", false, "print stable tab-separated lines for scripts: path, branch, type, markers")
+	statusCmd.Flags().Bool("todos", false, "count TODO/FIXME lines each worktree added and removed versus the main branch")
 	statusCmd.Flags().BoolP("verbose", "v", false, "show detailed git information")
+	statusCmd.Flags().Bool("check", false, "list only worktrees violating the limits below and exit non-zero if any do")
+	statusCmd.Flags().Int("max-behind", 0, "with --check, most commits a worktree may be behind the main branch")
+	statusCmd.Flags().Bool("fail-on-dirty", false, "with --check, fail on uncommitted changes or a git operation in progress")
+	addWatchFlag(statusCmd)
 }
@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"github.com/awhite/wtree/internal/worktree"
+	"github.com/spf13/cobra"
+)
+
+var subCmd = &cobra.Command{
+	Use:   "sub",
+	Short: "Work on submodules inside a worktree",
+	Long: `Check out a branch of a submodule inside a worktree, to patch the submodule
+alongside the parent.
+
+A linked worktree's submodules start out empty. 'sub create' fills one with a
+worktree of the submodule's repository, owned by its checkout in the main
+worktree, so branches and commits are shared with it. The link is recorded in
+the parent worktree: status shows it, and delete and cleanup remove the
+submodule worktree before the parent (its branch is kept).
+
+New branches start from the commit the parent worktree pins. Commit the
+submodule's new commit in the parent to pin it there.
+
+Examples:
+  wtree sub create vendor/lib fix-parser        # In the current worktree
+  wtree sub create vendor/lib fix-parser --in feature-x
+  wtree sub list`,
+}
+
+var subCreateCmd = &cobra.Command{
+	Use:         "create <submodule> <branch>",
+	Short:       "Check out a branch of a submodule in a worktree",
+	Args:        cobra.ExactArgs(2),
+	Annotations: capabilities(capRepo),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		manager, err := setupManager()
+		if err != nil {
+			return err
+		}
+
+		in, _ := cmd.Flags().GetString("in")
+		from, _ := cmd.Flags().GetString("from")
+
+		options := worktree.SubCreateOptions{
+			In:     in,
+			From:   from,
+			DryRun: isDryRun(cmd),
+		}
+
+		return manager.SubCreate(args[0], args[1], options)
+	},
+}
+
+var subListCmd = &cobra.Command{
+	Use:         "list",
+	Short:       "List submodule worktrees",
+	Args:        cobra.NoArgs,
+	Annotations: capabilities(capRepo, capReadOnly),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		manager, err := setupManager()
+		if err != nil {
+			return err
+		}
+		return manager.ListSubmoduleLinks()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(subCmd)
+
+	subCmd.AddCommand(subCreateCmd)
+	subCmd.AddCommand(subListCmd)
+
+	subCreateCmd.Flags().String("in", "", "worktree to check the submodule out in (default: the current one)")
+	subCreateCmd.Flags().String("from", "", "commit or branch of the submodule a new branch starts from (default: the pinned commit)")
+	_ = subCreateCmd.RegisterFlagCompletionFunc("in", completeExistingWorktrees)
+}
@@ -6,33 +6,59 @@ import (
 )
 
 var switchCmd = &cobra.Command{
-	Use:   "switch <branch-or-path>",
+	Use:   "switch [branch-or-path]",
 	Short: "Switch to a worktree",
 	Long: `Switch to a different worktree by branch name or path.
 
 This command helps you navigate between worktrees. You can specify either
 the branch name or the worktree path. Use -o to automatically open in
-your configured editor.
+your configured editor. Without an argument, pick from the existing
+worktrees, shown with their dirty state and last activity. With --dry-run
+only the target path is printed.
+
+Leaving a worktree with uncommitted changes, --autostash (or autostash in
+the global config) stashes them as wtree-auto/<branch>; with shell
+integration you are offered this interactively. Switching to a worktree
+whose branch has such a stash restores it.
+
+Switching away from another worktree runs its pre_switch hooks there first
+(a failure stops the switch), then post_switch in the worktree switched to,
+for example to stop and start dev servers.
+
+Without shell integration switch prints a cd command for eval, and
+everything else, hook output included, on stderr; after
+eval "$(wtree shell-init bash)" (or zsh, fish) in your shell's startup file,
+'wtree switch feature' changes directory directly.
 
 Examples:
+  wtree switch                         # Pick a worktree
   wtree switch main                    # Switch to main worktree
   wtree switch feature-branch          # Switch to feature branch worktree
-  wtree switch -o bugfix               # Switch and open in editor`,
-	Args:              cobra.ExactArgs(1),
+  wtree switch -o bugfix               # Switch and open in editor
+  wtree switch --autostash main        # Stash changes here, then switch`,
+	Args:              cobra.MaximumNArgs(1),
 	ValidArgsFunction: completeExistingWorktrees,
+	Annotations:       capabilities(capRepo),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		manager, err := setupManager()
 		if err != nil {
 			return err
 		}
+		applyEditorFlags(cmd, manager)
 
-		identifier := args[0]
+		identifier := ""
+		if len(args) > 0 {
+			identifier = args[0]
+		}
 
 		// Get flag values
 		openEditor, _ := cmd.Flags().GetBool("open")
+		autostash, _ := cmd.Flags().GetBool("autostash")
 
 		options := worktree.SwitchOptions{
 			OpenEditor: openEditor,
+			DryRun:     isDryRun(cmd),
+			Autostash:  autostash,
 		}
 
 		return manager.Switch(identifier, options)
@@ -43,4 +69,6 @@ func init() {
 	rootCmd.AddCommand(switchCmd)
 
 	switchCmd.Flags().BoolP("open", "o", false, "open in editor after switching")
+	switchCmd.Flags().Bool("autostash", false, "stash the current worktree's uncommitted changes as wtree-auto/<branch>")
+	addEditorFlags(switchCmd)
 }
@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"github.com/awhite/wtree/internal/worktree"
+	"github.com/spf13/cobra"
+)
+
+var tagCmd = &cobra.Command{
+	Use:   "tag",
+	Short: "Tag worktrees by purpose",
+	Long: `Tag worktrees by what they are for (review, experiment, release...) so they
+can be listed and cleaned up by category.
+
+Tags are kept in the worktree's own git directory, so they go away with the
+worktree. They are shown by 'wtree list' and filter list and cleanup with
+--tag; create takes --tag too. With a single argument, add and remove apply
+to the current worktree.
+
+Examples:
+  wtree tag add feature-x experiment     # Tag feature-x
+  wtree tag add review                   # Tag the current worktree
+  wtree tag remove feature-x experiment
+  wtree tag list                         # Every tagged worktree
+  wtree create -b spike --tag experiment
+  wtree list --tag review
+  wtree cleanup --tag experiment --older-than 2w`,
+}
+
+var tagAddCmd = &cobra.Command{
+	Use:               "add [branch] <tag>...",
+	Short:             "Tag a worktree (the current one by default)",
+	Args:              cobra.MinimumNArgs(1),
+	ValidArgsFunction: completeTagArgs,
+	Annotations:       capabilities(capRepo),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		manager, err := setupManager()
+		if err != nil {
+			return err
+		}
+		identifier, tags := splitTagArgs(args)
+		return manager.AddTags(identifier, tags, isDryRun(cmd))
+	},
+}
+
+var tagRemoveCmd = &cobra.Command{
+	Use:               "remove [branch] <tag>...",
+	Short:             "Remove tags from a worktree (the current one by default)",
+	Aliases:           []string{"rm"},
+	Args:              cobra.MinimumNArgs(1),
+	ValidArgsFunction: completeTagArgs,
+	Annotations:       capabilities(capRepo),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		manager, err := setupManager()
+		if err != nil {
+			return err
+		}
+		identifier, tags := splitTagArgs(args)
+		return manager.RemoveTags(identifier, tags, isDryRun(cmd))
+	},
+}
+
+var tagListCmd = &cobra.Command{
+	Use:         "list",
+	Short:       "List tagged worktrees",
+	Args:        cobra.NoArgs,
+	Annotations: capabilities(capRepo, capReadOnly),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		manager, err := setupManager()
+		if err != nil {
+			return err
+		}
+		return manager.ListTags()
+	},
+}
+
+// splitTagArgs splits tag add/remove arguments into the worktree, empty for
+// the current one, and the tags
+func splitTagArgs(args []string) (string, []string) {
+	if len(args) == 1 {
+		return "", args
+	}
+	return args[0], args[1:]
+}
+
+// completeTagArgs completes the worktree of tag add/remove first
+func completeTagArgs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) == 0 {
+		return completeExistingWorktrees(cmd, args, toComplete)
+	}
+	return nil, cobra.ShellCompDirectiveNoFileComp
+}
+
+// addTagFilterFlag adds --tag to a command that works on many worktrees
+func addTagFilterFlag(cmd *cobra.Command, usage string) {
+	cmd.Flags().StringSlice("tag", nil, usage)
+}
+
+// tagFlag reads --tag, normalized
+func tagFlag(cmd *cobra.Command) ([]string, error) {
+	tags, _ := cmd.Flags().GetStringSlice("tag")
+	if len(tags) == 0 {
+		return nil, nil
+	}
+	return worktree.NormalizeTags(tags)
+}
+
+func init() {
+	rootCmd.AddCommand(tagCmd)
+
+	tagCmd.AddCommand(tagAddCmd)
+	tagCmd.AddCommand(tagRemoveCmd)
+	tagCmd.AddCommand(tagListCmd)
+}
@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"github.com/awhite/wtree/internal/worktree"
+	"github.com/spf13/cobra"
+)
+
+var trashCmd = &cobra.Command{
+	Use:   "trash",
+	Short: "Manage deleted worktrees kept in the trash",
+	Long: `Manage worktrees deleted with --trash (or with trash.enabled set).
+
+Trashed worktrees are kept under ~/.local/share/wtree/trash, including any
+uncommitted and untracked files, for trash.retention (e.g. 2w), or else
+trash.retention_days days.
+
+Examples:
+  wtree trash list                     # Show trashed worktrees
+  wtree trash restore feature-x        # Restore the newest entry for a branch
+  wtree trash empty --expired          # Remove entries past retention`,
+}
+
+var trashListCmd = &cobra.Command{
+	Use:         "list",
+	Short:       "List trashed worktrees",
+	Args:        cobra.NoArgs,
+	Annotations: capabilities(capRepo),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		manager, err := setupManager()
+		if err != nil {
+			return err
+		}
+
+		return manager.TrashList()
+	},
+}
+
+var trashRestoreCmd = &cobra.Command{
+	Use:   "restore <id-or-branch>",
+	Short: "Restore a trashed worktree to its original path",
+	Args:  cobra.ExactArgs(1),
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		manager, err := setupManager()
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return manager.TrashIDs(), cobra.ShellCompDirectiveNoFileComp
+	},
+	Annotations: capabilities(capRepo),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		manager, err := setupManager()
+		if err != nil {
+			return err
+		}
+
+		return manager.TrashRestore(args[0])
+	},
+}
+
+var trashEmptyCmd = &cobra.Command{
+	Use:         "empty",
+	Short:       "Permanently remove trashed worktrees",
+	Args:        cobra.NoArgs,
+	Annotations: capabilities(capRepo),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		manager, err := setupManager()
+		if err != nil {
+			return err
+		}
+
+		expired, _ := cmd.Flags().GetBool("expired")
+
+		return manager.TrashEmpty(worktree.TrashEmptyOptions{
+			ExpiredOnly: expired,
+			Yes:         assumeYes(),
+		})
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(trashCmd)
+
+	trashCmd.AddCommand(trashListCmd)
+	trashCmd.AddCommand(trashRestoreCmd)
+	trashCmd.AddCommand(trashEmptyCmd)
+
+	trashEmptyCmd.Flags().Bool("expired", false, "only remove entries past trash.retention (or trash.retention_days)")
+}
@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/awhite/wtree/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+// defaultWatchInterval is how often --watch refreshes without a value
+const defaultWatchInterval = "2s"
+
+// addWatchFlag adds --watch to a command that can keep redrawing its output
+func addWatchFlag(cmd *cobra.Command) {
+	cmd.Flags().String("watch", "", "redraw the output every interval until interrupted, e.g. --watch=10s")
+	cmd.Flags().Lookup("watch").NoOptDefVal = defaultWatchInterval
+}
+
+// watchInterval returns the --watch interval, or zero when not watching. A
+// bare number is seconds.
+func watchInterval(cmd *cobra.Command) (time.Duration, error) {
+	value, _ := cmd.Flags().GetString("watch")
+	if !cmd.Flags().Changed("watch") {
+		return 0, nil
+	}
+	if seconds, err := strconv.ParseFloat(value, 64); err == nil {
+		value = fmt.Sprintf("%gs", seconds)
+	}
+	interval, err := types.ParseDuration(value)
+	if err != nil {
+		return 0, types.NewValidationError("watch", err.Error(), nil)
+	}
+	if interval < 100*time.Millisecond {
+		return 0, types.NewValidationError("watch", "--watch interval must be at least 100ms", nil)
+	}
+	return interval, nil
+}
+
+// runWatching runs render every interval until interrupted, like watch(1):
+// on a terminal the screen is cleared and redrawn under a header line,
+// otherwise each refresh is appended. A failed refresh is shown and the
+// next one tried.
+func runWatching(interval time.Duration, render func() error) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	redraw := isTerminal(os.Stdout)
+	title := "wtree " + strings.Join(os.Args[1:], " ")
+	for {
+		if redraw {
+			fmt.Print("\033[H\033[2J")
+		}
+		fmt.Printf("Every %s: %s    %s\n\n", interval, title, time.Now().Format("2006-01-02 15:04:05"))
+		if err := render(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		}
+		if !redraw {
+			fmt.Println()
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(interval):
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+var whichCmd = &cobra.Command{
+	Use:   "which <branch-or-path>",
+	Short: "Print the path of a branch's worktree",
+	Long: `Print the filesystem path of the worktree for a branch.
+
+Only the path is written to stdout, and the command exits non-zero when the
+branch has no worktree, which makes it convenient for scripting and a
+lightweight alternative to eval "$(wtree switch ...)".
+
+Examples:
+  cd "$(wtree which feature-x)"        # Jump to a worktree
+  code "$(wtree which bugfix)"         # Open a worktree in an editor
+  wtree which feature-x || wtree create feature-x`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeExistingWorktrees,
+	SilenceUsage:      true,
+	Annotations:       capabilities(capRepo, capReadOnly),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		manager, err := setupManager()
+		if err != nil {
+			return err
+		}
+
+		path, err := manager.Which(args[0])
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintln(cmd.OutOrStdout(), path)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(whichCmd)
+}
@@ -1,14 +1,20 @@
 package config
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"os"
+	"path"
 	"path/filepath"
+	"reflect"
+	"slices"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/awhite/wtree/pkg/types"
+	"github.com/go-viper/mapstructure/v2"
 	"github.com/spf13/viper"
 	"gopkg.in/yaml.v3"
 )
@@ -25,6 +31,11 @@ func NewManager() *Manager {
 	return &Manager{}
 }
 
+// GlobalConfigFile returns the global config file in use, or "" when none was found
+func (m *Manager) GlobalConfigFile() string {
+	return viper.ConfigFileUsed()
+}
+
 // LoadGlobalConfig loads the global WTree configuration
 func (m *Manager) LoadGlobalConfig() (*types.WTreeConfig, error) {
 	m.mu.Lock()
@@ -38,7 +49,7 @@ func (m *Manager) LoadGlobalConfig() (*types.WTreeConfig, error) {
 	config := types.DefaultWTreeConfig()
 
 	// Apply configuration from viper (which handles file, env vars, flags)
-	if err := viper.Unmarshal(config); err != nil {
+	if err := viper.Unmarshal(config, viper.DecodeHook(decodeHooks())); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal global config: %w", err)
 	}
 
@@ -51,6 +62,20 @@ func (m *Manager) LoadGlobalConfig() (*types.WTreeConfig, error) {
 	return config, nil
 }
 
+// decodeHooks are viper's default decode hooks, with durations parsed by
+// types.ParseDuration so config can say "30d" or "2 weeks"
+func decodeHooks() mapstructure.DecodeHookFunc {
+	return mapstructure.ComposeDecodeHookFunc(
+		func(from, to reflect.Type, data interface{}) (interface{}, error) {
+			if from.Kind() != reflect.String || to != reflect.TypeOf(time.Duration(0)) {
+				return data, nil
+			}
+			return types.ParseDuration(data.(string))
+		},
+		mapstructure.StringToSliceHookFunc(","),
+	)
+}
+
 // LoadProjectConfig loads the project-specific configuration from .wtreerc
 func (m *Manager) LoadProjectConfig(repoPath string) (*types.ProjectConfig, error) {
 	m.mu.Lock()
@@ -67,7 +92,110 @@ func (m *Manager) LoadProjectConfig(repoPath string) (*types.ProjectConfig, erro
 	if err != nil {
 		return nil, fmt.Errorf("failed to read .wtreerc: %w", err)
 	}
+	return m.parseProjectConfig(data, repoPath)
+}
+
+// ResolveProjectConfig loads the project configuration that applies inside
+// worktreeRoot, a checkout of the repository whose main checkout is mainRoot,
+// following policy (types.ProjectConfigWorktree, Main or Merge). It also
+// returns where the configuration came from: one of those policies, or
+// types.ProjectConfigDefault when no .wtreerc was found.
+func (m *Manager) ResolveProjectConfig(worktreeRoot, mainRoot, policy string) (*types.ProjectConfig, string, error) {
+	if mainRoot == "" || mainRoot == worktreeRoot {
+		source := types.ProjectConfigMain
+		if !fileExists(filepath.Join(worktreeRoot, ".wtreerc")) {
+			source = types.ProjectConfigDefault
+		}
+		config, err := m.LoadProjectConfig(worktreeRoot)
+		return config, source, err
+	}
 
+	worktreeFile := filepath.Join(worktreeRoot, ".wtreerc")
+	mainFile := filepath.Join(mainRoot, ".wtreerc")
+	switch policy {
+	case types.ProjectConfigMain:
+		worktreeFile = ""
+	case types.ProjectConfigMerge:
+	default:
+		mainFile = ""
+	}
+	haveWorktree := worktreeFile != "" && fileExists(worktreeFile)
+	haveMain := mainFile != "" && fileExists(mainFile)
+
+	switch {
+	case haveWorktree && haveMain:
+		data, err := mergeProjectConfigFiles(mainFile, worktreeFile)
+		if err != nil {
+			return nil, "", err
+		}
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		config, err := m.parseProjectConfig(data, worktreeRoot)
+		return config, types.ProjectConfigMerge, err
+	case haveWorktree:
+		config, err := m.LoadProjectConfig(worktreeRoot)
+		return config, types.ProjectConfigWorktree, err
+	case haveMain:
+		config, err := m.LoadProjectConfig(mainRoot)
+		return config, types.ProjectConfigMain, err
+	default:
+		config, err := m.LoadProjectConfig(worktreeRoot)
+		return config, types.ProjectConfigDefault, err
+	}
+}
+
+// mergeProjectConfigFiles returns the YAML of base with the keys of overlay
+// on top. Nested mappings such as hooks merge key by key; any other value,
+// lists included, is replaced.
+func mergeProjectConfigFiles(base, overlay string) ([]byte, error) {
+	var nodes [2]yaml.Node
+	for i, file := range []string{base, overlay} {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read .wtreerc: %w", err)
+		}
+		if err := yaml.Unmarshal(data, &nodes[i]); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", file, err)
+		}
+	}
+	if len(nodes[0].Content) == 0 {
+		return yaml.Marshal(&nodes[1])
+	}
+	if len(nodes[1].Content) > 0 {
+		mergeYAMLMappings(nodes[0].Content[0], nodes[1].Content[0])
+	}
+	return yaml.Marshal(&nodes[0])
+}
+
+// mergeYAMLMappings sets every key of overlay in base, recursing into keys
+// that are mappings in both
+func mergeYAMLMappings(base, overlay *yaml.Node) {
+	if base.Kind != yaml.MappingNode || overlay.Kind != yaml.MappingNode {
+		*base = *overlay
+		return
+	}
+	for i := 0; i+1 < len(overlay.Content); i += 2 {
+		key, value := overlay.Content[i], overlay.Content[i+1]
+		found := false
+		for j := 0; j+1 < len(base.Content); j += 2 {
+			if base.Content[j].Value == key.Value {
+				if base.Content[j+1].Kind == yaml.MappingNode && value.Kind == yaml.MappingNode {
+					mergeYAMLMappings(base.Content[j+1], value)
+				} else {
+					base.Content[j+1] = value
+				}
+				found = true
+				break
+			}
+		}
+		if !found {
+			base.Content = append(base.Content, key, value)
+		}
+	}
+}
+
+// parseProjectConfig parses, defaults and validates a .wtreerc; m.mu must be held
+func (m *Manager) parseProjectConfig(data []byte, repoPath string) (*types.ProjectConfig, error) {
 	var config types.ProjectConfig
 	if err := yaml.Unmarshal(data, &config); err != nil {
 		return nil, fmt.Errorf("failed to parse .wtreerc: %w", err)
@@ -78,11 +206,13 @@ func (m *Manager) LoadProjectConfig(repoPath string) (*types.ProjectConfig, erro
 		config.Version = "1.0"
 	}
 	if config.WorktreePattern == "" {
-		config.WorktreePattern = "{repo}-{branch}"
+		config.WorktreePattern = types.DefaultWorktreePattern
 	}
 	if config.Hooks == nil {
 		config.Hooks = make(map[types.HookEvent][]string)
 	}
+	sum := sha256.Sum256(data)
+	config.Digest = hex.EncodeToString(sum[:])
 
 	// Validate configuration
 	if err := m.validateProjectConfig(&config, repoPath); err != nil {
@@ -124,6 +254,120 @@ func (m *Manager) validateGlobalConfig(config *types.WTreeConfig) error {
 	if config.Hooks.MaxParallel > 10 {
 		config.Hooks.MaxParallel = 10
 	}
+	if config.GitHub.MaxConcurrentRequests <= 0 {
+		config.GitHub.MaxConcurrentRequests = 1
+	}
+	if config.GitHub.MaxConcurrentRequests > 16 {
+		config.GitHub.MaxConcurrentRequests = 16
+	}
+
+	// Validate allowed roots are absolute (or home-relative) paths
+	for _, root := range config.Paths.AllowedRoots {
+		if !filepath.IsAbs(root) && !strings.HasPrefix(root, "~/") {
+			return types.NewValidationError("config",
+				fmt.Sprintf("paths.allowed_roots entry '%s' must be an absolute path", root), nil)
+		}
+	}
+
+	switch config.Paths.CrossDevice {
+	case "", types.CrossDeviceCopy, types.CrossDeviceWarn, types.CrossDeviceAbort:
+	default:
+		return types.NewValidationError("config",
+			fmt.Sprintf("paths.cross_device must be copy, warn or abort, got '%s'", config.Paths.CrossDevice), nil)
+	}
+
+	if err := validateHookSecurityPolicy(config.Hooks.Security); err != nil {
+		return err
+	}
+
+	if config.Paths.MaxLength < 0 {
+		return types.NewValidationError("config", "paths.max_length cannot be negative", nil)
+	}
+
+	// Validate trash retention
+	if config.Trash.RetentionDays < 0 {
+		return types.NewValidationError("config", "trash.retention_days cannot be negative", nil)
+	}
+	if config.Trash.Retention < 0 {
+		return types.NewValidationError("config", "trash.retention cannot be negative", nil)
+	}
+
+	// Validate fetch interval
+	if config.Fetch.MinInterval < 0 {
+		return types.NewValidationError("config", "fetch.min_interval cannot be negative", nil)
+	}
+
+	switch config.UI.TimeFormat {
+	case "", types.TimeFormatRelative, types.TimeFormatAbsolute:
+	default:
+		return types.NewValidationError("config",
+			fmt.Sprintf("ui.time_format must be relative or absolute, got '%s'", config.UI.TimeFormat), nil)
+	}
+
+	switch config.ProjectConfigSource {
+	case "", types.ProjectConfigWorktree, types.ProjectConfigMain, types.ProjectConfigMerge:
+	default:
+		return types.NewValidationError("config",
+			fmt.Sprintf("project_config_source must be worktree, main or merge, got '%s'", config.ProjectConfigSource), nil)
+	}
+
+	// Validate notifications
+	for _, notification := range config.Notifications {
+		if !strings.HasPrefix(notification.URL, "https://") && !strings.HasPrefix(notification.URL, "http://") {
+			return types.NewValidationError("config",
+				fmt.Sprintf("notification url '%s' must be an http(s) URL", notification.URL), nil)
+		}
+		switch notification.Format {
+		case "", types.NotifyFormatSlack, types.NotifyFormatDiscord, types.NotifyFormatWebhook:
+		default:
+			return types.NewValidationError("config",
+				fmt.Sprintf("invalid notification format '%s' (expected slack, discord, or webhook)", notification.Format), nil)
+		}
+		for _, event := range notification.Events {
+			if !slices.Contains(types.NotifyEvents, event) {
+				return types.NewValidationError("config",
+					fmt.Sprintf("unknown notification event '%s' (expected one of %s)", event, strings.Join(types.NotifyEvents, ", ")), nil)
+			}
+		}
+	}
+
+	// Validate aliases
+	for name, expansion := range config.Aliases {
+		if name == "" || strings.ContainsAny(name, " \t") || strings.HasPrefix(name, "-") {
+			return types.NewValidationError("config",
+				fmt.Sprintf("invalid alias name '%s'", name), nil)
+		}
+		if len(strings.Fields(expansion)) == 0 {
+			return types.NewValidationError("config",
+				fmt.Sprintf("alias '%s' has an empty expansion", name), nil)
+		}
+	}
+
+	// Validate command defaults; flag names are checked when the command runs
+	for command, flags := range config.Defaults {
+		if len(strings.Fields(command)) == 0 {
+			return types.NewValidationError("config", "defaults has an empty command name", nil)
+		}
+		for flag, value := range flags {
+			if _, ok := value.(map[string]interface{}); ok {
+				return types.NewValidationError("config",
+					fmt.Sprintf("defaults.%s.%s must be a flag value, not a map (write subcommands as \"%s %s\")", command, flag, command, flag), nil)
+			}
+		}
+	}
+
+	// Validate worktree limits
+	if config.Limits.MaxWorktrees < 0 {
+		return types.NewValidationError("config", "limits.max_worktrees cannot be negative", nil)
+	}
+	switch config.Limits.OnLimit {
+	case "":
+		config.Limits.OnLimit = types.LimitModeWarn
+	case types.LimitModeWarn, types.LimitModeBlock:
+	default:
+		return types.NewValidationError("config",
+			fmt.Sprintf("invalid limits.on_limit '%s' (expected warn or block)", config.Limits.OnLimit), nil)
+	}
 
 	return nil
 }
@@ -143,6 +387,54 @@ func (m *Manager) validateProjectConfig(config *types.ProjectConfig, repoPath st
 				return types.NewValidationError("config",
 					fmt.Sprintf("empty hook command in %s", event), nil)
 			}
+			if types.IsHookRecipe(hook) && !types.KnownHookRecipe(hook) {
+				return types.NewValidationError("config",
+					fmt.Sprintf("unknown hook recipe '%s' in %s (expected one of %s)",
+						hook, event, strings.Join(types.HookRecipes, ", ")), nil)
+			}
+		}
+	}
+	for event, conditions := range config.HookConditions {
+		for _, condition := range conditions {
+			if err := validateCondition(condition, fmt.Sprintf("%s hook", event)); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Validate pre-merge check commands are not empty
+	for _, check := range config.PreMergeChecks {
+		if strings.TrimSpace(check) == "" {
+			return types.NewValidationError("config", "empty command in pre_merge_checks", nil)
+		}
+	}
+
+	if strings.ContainsAny(config.BranchSlug.Separator, `/\:*?"<>| `) {
+		return types.NewValidationError("config",
+			fmt.Sprintf("invalid branch_slug.separator '%s': it can't contain path separators, spaces or any of :*?\"<>|", config.BranchSlug.Separator), nil)
+	}
+
+	switch config.HookSecurity {
+	case "", types.HookSecurityStrict, types.HookSecurityStandard, types.HookSecurityOff:
+	default:
+		return types.NewValidationError("config",
+			fmt.Sprintf("invalid hooks.security '%s' (expected strict, standard, or off)", config.HookSecurity), nil)
+	}
+
+	// Validate git_hooks mode
+	switch config.GitHooks {
+	case "", types.GitHooksCopy, types.GitHooksLink, types.GitHooksInstall:
+	default:
+		return types.NewValidationError("config",
+			fmt.Sprintf("invalid git_hooks '%s' (expected copy, link, or install)", config.GitHooks), nil)
+	}
+
+	// Validate open_urls are web URLs or start with a URL placeholder
+	for _, openURL := range config.OpenURLs {
+		if !strings.HasPrefix(openURL, "http://") && !strings.HasPrefix(openURL, "https://") &&
+			!strings.HasPrefix(openURL, "{") {
+			return types.NewValidationError("config",
+				fmt.Sprintf("invalid open_urls entry '%s': must be an http(s) URL", openURL), nil)
 		}
 	}
 
@@ -155,6 +447,85 @@ func (m *Manager) validateProjectConfig(config *types.ProjectConfig, repoPath st
 		}
 	}
 
+	// Validate object entries; paths get the same checks as plain patterns
+	allEntries := append(append([]types.FileEntry{}, config.CopyEntries...), config.LinkEntries...)
+	for _, entry := range allEntries {
+		if err := m.validateFileEntry(entry, repoPath); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validateFileEntry checks a copy_files/link_files object entry
+func (m *Manager) validateFileEntry(entry types.FileEntry, repoPath string) error {
+	var paths []string
+	switch {
+	case entry.Path != "" && (entry.From != "" || entry.To != ""):
+		return types.NewValidationError("config",
+			fmt.Sprintf("file entry '%s' cannot combine path with from/to", entry.Path), nil)
+	case entry.Path != "":
+		paths = []string{entry.Path}
+	case entry.From == "" || entry.To == "":
+		return types.NewValidationError("config",
+			fmt.Sprintf("file entry {from: %q, to: %q} needs both from and to", entry.From, entry.To), nil)
+	default:
+		paths = []string{entry.From, entry.To}
+	}
+
+	for _, path := range paths {
+		if err := m.validateFilePattern(path, repoPath); err != nil {
+			return types.NewValidationError("config",
+				fmt.Sprintf("invalid file entry path '%s': %v", path, err), err)
+		}
+	}
+
+	return validateCondition(entry.When, "file entry")
+}
+
+// validateCondition checks the values of a when: condition
+func validateCondition(condition *types.Condition, owner string) error {
+	if condition == nil {
+		return nil
+	}
+
+	for _, name := range condition.OS {
+		if !slices.Contains(types.KnownOS, types.NormalizeOS(name)) {
+			return types.NewValidationError("config",
+				fmt.Sprintf("unknown os '%s' in %s condition (valid: %s)", name, owner, strings.Join(types.KnownOS, ", ")), nil)
+		}
+	}
+
+	for _, pattern := range condition.Branch {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return types.NewValidationError("config",
+				fmt.Sprintf("invalid branch pattern '%s' in %s condition", pattern, owner), err)
+		}
+	}
+
+	return nil
+}
+
+// validateHookSecurityPolicy checks the global hooks.security mode and that
+// its allow and deny entries are usable
+func validateHookSecurityPolicy(policy types.HookSecurityPolicy) error {
+	switch policy.Mode {
+	case "", types.HookSecurityStrict, types.HookSecurityStandard, types.HookSecurityWarn, types.HookSecurityOff:
+	default:
+		return types.NewValidationError("config",
+			fmt.Sprintf("hooks.security.mode must be strict, standard, warn or off, got '%s'", policy.Mode), nil)
+	}
+
+	for _, command := range policy.Allow {
+		if strings.TrimSpace(command) == "" {
+			return types.NewValidationError("config", "empty command in hooks.security.allow", nil)
+		}
+	}
+	if _, err := policy.DenyPatterns(); err != nil {
+		return types.NewValidationError("config", fmt.Sprintf("invalid hooks.security.deny entry: %v", err), err)
+	}
+
 	return nil
 }
 
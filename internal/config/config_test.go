@@ -4,8 +4,10 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/awhite/wtree/pkg/types"
+	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -130,6 +132,104 @@ func TestManager_validateProjectConfig(t *testing.T) {
 			},
 			expectError: true,
 		},
+		{
+			name: "empty pre-merge check",
+			config: &types.ProjectConfig{
+				Version:        "1.0",
+				PreMergeChecks: []string{"npm test", "  "},
+			},
+			expectError: true,
+		},
+		{
+			name: "valid open urls",
+			config: &types.ProjectConfig{
+				Version:  "1.0",
+				OpenURLs: []string{"{pr_url}", "https://staging.example.com/{branch}"},
+			},
+			expectError: false,
+		},
+		{
+			name: "valid from/to entry",
+			config: &types.ProjectConfig{
+				Version:     "1.0",
+				CopyEntries: []types.FileEntry{{From: "configs/dev.env", To: ".env"}},
+			},
+			expectError: false,
+		},
+		{
+			name: "from/to entry escaping the worktree",
+			config: &types.ProjectConfig{
+				Version:     "1.0",
+				LinkEntries: []types.FileEntry{{From: "cache", To: "../../outside"}},
+			},
+			expectError: true,
+		},
+		{
+			name: "from/to entry missing to",
+			config: &types.ProjectConfig{
+				Version:     "1.0",
+				CopyEntries: []types.FileEntry{{From: "configs/dev.env"}},
+			},
+			expectError: true,
+		},
+		{
+			name: "os-conditioned path entry",
+			config: &types.ProjectConfig{
+				Version:     "1.0",
+				LinkEntries: []types.FileEntry{{Path: "node_modules", When: &types.Condition{OS: types.StringList{"macos", "linux"}}}},
+			},
+			expectError: false,
+		},
+		{
+			name: "unknown os in entry condition",
+			config: &types.ProjectConfig{
+				Version:     "1.0",
+				LinkEntries: []types.FileEntry{{Path: "node_modules", When: &types.Condition{OS: types.StringList{"win32"}}}},
+			},
+			expectError: true,
+		},
+		{
+			name: "entry with both path and from",
+			config: &types.ProjectConfig{
+				Version:     "1.0",
+				CopyEntries: []types.FileEntry{{Path: "dist", From: "build"}},
+			},
+			expectError: true,
+		},
+		{
+			name: "branch-conditioned hook",
+			config: &types.ProjectConfig{
+				Version:        "1.0",
+				Hooks:          map[types.HookEvent][]string{types.HookPostCreate: {"cp .env.production .env"}},
+				HookConditions: map[types.HookEvent][]*types.Condition{types.HookPostCreate: {{Branch: types.StringList{"release/*"}}}},
+			},
+			expectError: false,
+		},
+		{
+			name: "invalid branch pattern in hook condition",
+			config: &types.ProjectConfig{
+				Version:        "1.0",
+				Hooks:          map[types.HookEvent][]string{types.HookPostCreate: {"make"}},
+				HookConditions: map[types.HookEvent][]*types.Condition{types.HookPostCreate: {{Branch: types.StringList{"release/["}}}},
+			},
+			expectError: true,
+		},
+		{
+			name: "invalid git hooks mode",
+			config: &types.ProjectConfig{
+				Version:  "1.0",
+				GitHooks: "symlink",
+			},
+			expectError: true,
+		},
+		{
+			name: "non-web open url",
+			config: &types.ProjectConfig{
+				Version:  "1.0",
+				OpenURLs: []string{"file:///etc/passwd"},
+			},
+			expectError: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -180,3 +280,202 @@ func TestManager_ResolveEditor(t *testing.T) {
 		})
 	}
 }
+
+func TestManager_validateGlobalConfigLimits(t *testing.T) {
+	manager := NewManager()
+
+	tests := []struct {
+		name         string
+		limits       types.LimitsConfig
+		expectError  bool
+		expectedMode string
+	}{
+		{
+			name:         "defaults",
+			limits:       types.LimitsConfig{},
+			expectedMode: types.LimitModeWarn,
+		},
+		{
+			name:         "block mode",
+			limits:       types.LimitsConfig{MaxWorktrees: 5, OnLimit: types.LimitModeBlock},
+			expectedMode: types.LimitModeBlock,
+		},
+		{
+			name:        "negative limit",
+			limits:      types.LimitsConfig{MaxWorktrees: -1},
+			expectError: true,
+		},
+		{
+			name:        "invalid mode",
+			limits:      types.LimitsConfig{MaxWorktrees: 5, OnLimit: "explode"},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := types.DefaultWTreeConfig()
+			config.Limits = tt.limits
+
+			err := manager.validateGlobalConfig(config)
+			if tt.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedMode, config.Limits.OnLimit)
+			}
+		})
+	}
+}
+
+func TestManager_validateGlobalConfigDefaults(t *testing.T) {
+	manager := NewManager()
+
+	tests := []struct {
+		name        string
+		defaults    map[string]map[string]interface{}
+		expectError bool
+	}{
+		{name: "valid", defaults: map[string]map[string]interface{}{
+			"create":    {"open": true, "branch": true},
+			"pr create": {"open": true},
+		}},
+		{name: "empty command", defaults: map[string]map[string]interface{}{" ": {"open": true}}, expectError: true},
+		{name: "nested subcommand", defaults: map[string]map[string]interface{}{
+			"pr": {"create": map[string]interface{}{"open": true}},
+		}, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := types.DefaultWTreeConfig()
+			config.Defaults = tt.defaults
+
+			err := manager.validateGlobalConfig(config)
+			if tt.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestManager_validateGlobalConfigAliases(t *testing.T) {
+	manager := NewManager()
+
+	tests := []struct {
+		name        string
+		aliases     map[string]string
+		expectError bool
+	}{
+		{name: "valid", aliases: map[string]string{"rev": "pr create {1} --open"}},
+		{name: "name with space", aliases: map[string]string{"my rev": "pr create {1}"}, expectError: true},
+		{name: "name looks like a flag", aliases: map[string]string{"-r": "pr create {1}"}, expectError: true},
+		{name: "empty expansion", aliases: map[string]string{"rev": "  "}, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := types.DefaultWTreeConfig()
+			config.Aliases = tt.aliases
+
+			err := manager.validateGlobalConfig(config)
+			if tt.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestManager_LoadGlobalConfigHumanDurations(t *testing.T) {
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+	viper.Set("trash.retention", "2 weeks")
+	viper.Set("github.cache_timeout", "1d")
+	viper.Set("hooks.timeout", "1h30m")
+
+	config, err := NewManager().LoadGlobalConfig()
+	require.NoError(t, err)
+	assert.Equal(t, 2*types.Week, config.Trash.Retention)
+	assert.Equal(t, types.Day, config.GitHub.CacheTimeout)
+	assert.Equal(t, 90*time.Minute, config.Hooks.Timeout)
+}
+
+func TestManager_ResolveProjectConfig(t *testing.T) {
+	mainRoot, worktreeRoot := t.TempDir(), t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(mainRoot, ".wtreerc"), []byte(`
+copy_files: [".env"]
+hooks:
+  post_create: ["npm install"]
+  pre_delete: ["make clean"]
+`), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(worktreeRoot, ".wtreerc"), []byte(`
+link_files: ["node_modules"]
+hooks:
+  post_create: ["pnpm install"]
+`), 0644))
+
+	config, source, err := NewManager().ResolveProjectConfig(worktreeRoot, mainRoot, types.ProjectConfigWorktree)
+	require.NoError(t, err)
+	assert.Equal(t, types.ProjectConfigWorktree, source)
+	assert.Empty(t, config.CopyFiles)
+
+	config, source, err = NewManager().ResolveProjectConfig(worktreeRoot, mainRoot, types.ProjectConfigMain)
+	require.NoError(t, err)
+	assert.Equal(t, types.ProjectConfigMain, source)
+	assert.Equal(t, []string{"npm install"}, config.Hooks[types.HookPostCreate])
+
+	config, source, err = NewManager().ResolveProjectConfig(worktreeRoot, mainRoot, types.ProjectConfigMerge)
+	require.NoError(t, err)
+	assert.Equal(t, types.ProjectConfigMerge, source)
+	assert.Equal(t, []string{".env"}, config.CopyFiles)
+	assert.Equal(t, []string{"node_modules"}, config.LinkFiles)
+	assert.Equal(t, []string{"pnpm install"}, config.Hooks[types.HookPostCreate])
+	assert.Equal(t, []string{"make clean"}, config.Hooks[types.HookPreDelete])
+
+	// Merging with nothing in the worktree is just the main checkout's
+	_, source, err = NewManager().ResolveProjectConfig(t.TempDir(), mainRoot, types.ProjectConfigMerge)
+	require.NoError(t, err)
+	assert.Equal(t, types.ProjectConfigMain, source)
+
+	_, source, err = NewManager().ResolveProjectConfig(t.TempDir(), "", types.ProjectConfigMerge)
+	require.NoError(t, err)
+	assert.Equal(t, types.ProjectConfigDefault, source)
+}
+
+func TestManager_validateGlobalConfigHookSecurity(t *testing.T) {
+	manager := NewManager()
+
+	tests := []struct {
+		name        string
+		policy      types.HookSecurityPolicy
+		expectError bool
+	}{
+		{name: "default", policy: types.HookSecurityPolicy{}},
+		{name: "warn with lists", policy: types.HookSecurityPolicy{
+			Mode:  types.HookSecurityWarn,
+			Allow: []string{"rm -rf ./node_modules && npm ci"},
+			Deny:  []string{`\bnpm\s+publish\b`},
+		}},
+		{name: "unknown mode", policy: types.HookSecurityPolicy{Mode: "lenient"}, expectError: true},
+		{name: "empty allow entry", policy: types.HookSecurityPolicy{Allow: []string{" "}}, expectError: true},
+		{name: "invalid deny pattern", policy: types.HookSecurityPolicy{Deny: []string{"npm ("}}, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := types.DefaultWTreeConfig()
+			config.Hooks.Security = tt.policy
+
+			err := manager.validateGlobalConfig(config)
+			if tt.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,177 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/awhite/wtree/pkg/types"
+)
+
+// Formats for WriteDocs
+const (
+	DocsFormatMarkdown = "md"
+	DocsFormatMan      = "man"
+)
+
+// KeyDoc documents one configuration key
+type KeyDoc struct {
+	Key         string // Dotted path, e.g. "ui.colors" or "notifications[].url"
+	Type        string
+	Default     string
+	Description string
+}
+
+// GlobalKeys documents every key of the global config.yaml
+func GlobalKeys() []KeyDoc {
+	return describeKeys(reflect.ValueOf(*types.DefaultWTreeConfig()), "")
+}
+
+// ProjectKeys documents every key of .wtreerc
+func ProjectKeys() []KeyDoc {
+	return describeKeys(reflect.ValueOf(*types.DefaultProjectConfig()), "")
+}
+
+// describeKeys walks a config struct by its yaml tags, taking descriptions
+// from desc tags and defaults from the given value
+func describeKeys(value reflect.Value, prefix string) []KeyDoc {
+	var docs []KeyDoc
+	valueType := value.Type()
+	for i := 0; i < valueType.NumField(); i++ {
+		field := valueType.Field(i)
+		name := strings.Split(field.Tag.Get("yaml"), ",")[0]
+		if name == "" || name == "-" {
+			continue
+		}
+		key := prefix + name
+		fieldValue := value.Field(i)
+
+		switch {
+		case field.Type.Kind() == reflect.Struct && field.Type != reflect.TypeOf(time.Duration(0)):
+			docs = append(docs, describeKeys(fieldValue, key+".")...)
+		case field.Type.Kind() == reflect.Slice && field.Type.Elem().Kind() == reflect.Struct:
+			docs = append(docs, describeKeys(reflect.Zero(field.Type.Elem()), key+"[].")...)
+		default:
+			docs = append(docs, KeyDoc{
+				Key:         key,
+				Type:        typeName(field.Type),
+				Default:     defaultText(fieldValue),
+				Description: field.Tag.Get("desc"),
+			})
+		}
+	}
+	return docs
+}
+
+// typeName describes a field type the way a config author writes it
+func typeName(t reflect.Type) string {
+	if t == reflect.TypeOf(time.Duration(0)) {
+		return "duration"
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return "bool"
+	case reflect.Int, reflect.Int64:
+		return "int"
+	case reflect.Slice:
+		return "list of " + typeName(t.Elem())
+	case reflect.Map:
+		return "map of " + typeName(t.Key()) + " to " + typeName(t.Elem())
+	default:
+		return "string"
+	}
+}
+
+// defaultText renders a default value, or "" for an empty string, list or map
+func defaultText(value reflect.Value) string {
+	switch value.Kind() {
+	case reflect.String, reflect.Slice, reflect.Map:
+		if value.Len() == 0 {
+			return ""
+		}
+	}
+	if d, ok := value.Interface().(time.Duration); ok {
+		return d.String()
+	}
+	switch value.Kind() {
+	case reflect.Slice:
+		items := make([]string, value.Len())
+		for i := range items {
+			items[i] = fmt.Sprint(value.Index(i).Interface())
+		}
+		return strings.Join(items, ", ")
+	case reflect.Map:
+		var keys []string
+		for _, key := range value.MapKeys() {
+			keys = append(keys, fmt.Sprint(key.Interface()))
+		}
+		sort.Strings(keys)
+		return strings.Join(keys, ", ")
+	default:
+		return fmt.Sprint(value.Interface())
+	}
+}
+
+// WriteDocs writes reference documentation for the global and project
+// configuration in format (md or man)
+func WriteDocs(w io.Writer, format string) error {
+	sections := []struct {
+		title string
+		file  string
+		keys  []KeyDoc
+	}{
+		{"Global configuration", "~/.config/wtree/config.yaml", GlobalKeys()},
+		{"Project configuration", ".wtreerc", ProjectKeys()},
+	}
+
+	switch format {
+	case DocsFormatMarkdown, "":
+		fmt.Fprintln(w, "# WTree configuration reference")
+		for _, section := range sections {
+			fmt.Fprintf(w, "\n## %s (`%s`)\n\n", section.title, section.file)
+			fmt.Fprintln(w, "| Key | Type | Default | Description |")
+			fmt.Fprintln(w, "| --- | --- | --- | --- |")
+			for _, key := range section.keys {
+				def := ""
+				if key.Default != "" {
+					def = "`" + key.Default + "`"
+				}
+				fmt.Fprintf(w, "| `%s` | %s | %s | %s |\n", key.Key, key.Type, def, strings.ReplaceAll(key.Description, "|", `\|`))
+			}
+		}
+	case DocsFormatMan:
+		fmt.Fprintln(w, `.TH WTREE-CONFIG 5 "" "wtree" "WTree Manual"`)
+		fmt.Fprintln(w, ".SH NAME")
+		fmt.Fprintln(w, `wtree-config \- wtree configuration reference`)
+		for _, section := range sections {
+			fmt.Fprintf(w, ".SH %s\n", strings.ToUpper(section.title))
+			fmt.Fprintf(w, "Keys of \\fI%s\\fR.\n", manEscape(section.file))
+			for _, key := range section.keys {
+				fmt.Fprintln(w, ".TP")
+				fmt.Fprintf(w, "\\fB%s\\fR (%s", manEscape(key.Key), key.Type)
+				if key.Default != "" {
+					fmt.Fprintf(w, ", default %s", manEscape(key.Default))
+				}
+				fmt.Fprintln(w, ")")
+				fmt.Fprintln(w, manEscape(key.Description))
+			}
+		}
+	default:
+		return types.NewValidationError("config-docs",
+			fmt.Sprintf("unknown format '%s' (expected md or man)", format), nil)
+	}
+	return nil
+}
+
+// manEscape keeps text from being read as roff requests or escapes
+func manEscape(text string) string {
+	text = strings.ReplaceAll(text, `\`, `\e`)
+	text = strings.ReplaceAll(text, "-", `\-`)
+	if strings.HasPrefix(text, ".") || strings.HasPrefix(text, "'") {
+		text = `\&` + text
+	}
+	return text
+}
@@ -0,0 +1,41 @@
+package config
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigKeys_AllDescribed(t *testing.T) {
+	for _, key := range append(GlobalKeys(), ProjectKeys()...) {
+		assert.NotEmpty(t, key.Description, "config key %s needs a desc tag", key.Key)
+	}
+}
+
+func TestGlobalKeys(t *testing.T) {
+	byKey := make(map[string]KeyDoc)
+	for _, key := range GlobalKeys() {
+		byKey[key.Key] = key
+	}
+
+	assert.Equal(t, KeyDoc{Key: "hooks.timeout", Type: "duration", Default: "5m0s", Description: "Time limit for each hook command"}, byKey["hooks.timeout"])
+	assert.Equal(t, "false", byKey["trash.enabled"].Default)
+	assert.Equal(t, "list of string", byKey["notifications[].events"].Type)
+	assert.Contains(t, byKey, "ui.accessible")
+}
+
+func TestWriteDocs(t *testing.T) {
+	var md bytes.Buffer
+	require.NoError(t, WriteDocs(&md, DocsFormatMarkdown))
+	assert.Contains(t, md.String(), "| `fetch.min_interval` | duration | `5m0s` |")
+	assert.Contains(t, md.String(), "## Project configuration (`.wtreerc`)")
+
+	var man bytes.Buffer
+	require.NoError(t, WriteDocs(&man, DocsFormatMan))
+	assert.Contains(t, man.String(), ".TH WTREE-CONFIG 5")
+	assert.Contains(t, man.String(), `\fBgit_hooks\fR (string)`)
+
+	assert.Error(t, WriteDocs(&md, "html"))
+}
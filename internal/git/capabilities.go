@@ -0,0 +1,124 @@
+package git
+
+import (
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/awhite/wtree/pkg/types"
+)
+
+// Version is an installed git version
+type Version struct {
+	Major, Minor, Patch int
+	Raw                 string // `git version` output, e.g. "git version 2.39.2 (Apple Git-143)"
+}
+
+// String returns the numeric version, e.g. "2.39.2"
+func (v Version) String() string {
+	return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
+
+// AtLeast reports whether v is major.minor or newer
+func (v Version) AtLeast(major, minor int) bool {
+	return v.Major > major || (v.Major == major && v.Minor >= minor)
+}
+
+var versionPattern = regexp.MustCompile(`(\d+)\.(\d+)(?:\.(\d+))?`)
+
+// ParseVersion reads the output of `git version`
+func ParseVersion(output string) (Version, error) {
+	match := versionPattern.FindStringSubmatch(output)
+	if match == nil {
+		return Version{}, fmt.Errorf("unrecognized git version: %q", strings.TrimSpace(output))
+	}
+	v := Version{Raw: strings.TrimSpace(output)}
+	v.Major, _ = strconv.Atoi(match[1])
+	v.Minor, _ = strconv.Atoi(match[2])
+	v.Patch, _ = strconv.Atoi(match[3])
+	return v, nil
+}
+
+var (
+	detectOnce    sync.Once
+	detected      Version
+	detectedError error
+)
+
+// DetectVersion returns the installed git version, running `git version`
+// once per process
+func DetectVersion() (Version, error) {
+	detectOnce.Do(func() {
+		output, err := exec.Command("git", "version").Output()
+		if err != nil {
+			detectedError = fmt.Errorf("failed to run git: %w", err)
+			return
+		}
+		detected, detectedError = ParseVersion(string(output))
+	})
+	return detected, detectedError
+}
+
+// Feature is git functionality that needs at least a given git version
+type Feature struct {
+	Name         string
+	Major, Minor int
+}
+
+// MinVersion returns the version the feature needs, e.g. "2.17"
+func (f Feature) MinVersion() string {
+	return fmt.Sprintf("%d.%d", f.Major, f.Minor)
+}
+
+// Git features wtree uses or may use
+var (
+	FeatureWorktreeRemove = Feature{"git worktree remove", 2, 17}
+	FeatureWorktreeMove   = Feature{"git worktree move", 2, 17}
+	FeatureStashPathspec  = Feature{"git stash push with paths", 2, 13}
+	FeaturePorcelainV2    = Feature{"git status --porcelain=v2", 2, 11}
+	FeatureSparseCone     = Feature{"sparse-checkout cone mode", 2, 25}
+	FeatureWorktreeRepair = Feature{"git worktree repair", 2, 30}
+	FeatureWorktreeListZ  = Feature{"git worktree list -z", 2, 36}
+	FeatureMergeTree      = Feature{"git merge-tree --write-tree", 2, 38}
+	FeatureAheadBehind    = Feature{"git for-each-ref %(ahead-behind)", 2, 41}
+	FeatureWorktreeOrphan = Feature{"git worktree add --orphan", 2, 42}
+)
+
+// Features lists the gated features in version order, for `wtree doctor`
+var Features = []Feature{
+	FeaturePorcelainV2,
+	FeatureStashPathspec,
+	FeatureWorktreeRemove,
+	FeatureWorktreeMove,
+	FeatureSparseCone,
+	FeatureWorktreeRepair,
+	FeatureWorktreeListZ,
+	FeatureMergeTree,
+	FeatureAheadBehind,
+	FeatureWorktreeOrphan,
+}
+
+// MinimumVersion is the oldest git wtree's core commands work with: deleting
+// worktrees needs `git worktree remove`
+var MinimumVersion = FeatureWorktreeRemove
+
+// Supports reports whether the installed git has feature. When the version
+// can't be determined the feature is assumed present and git reports any
+// failure itself.
+func Supports(feature Feature) bool {
+	v, err := DetectVersion()
+	return err != nil || v.AtLeast(feature.Major, feature.Minor)
+}
+
+// Require fails with a clear message when the installed git lacks feature
+func Require(feature Feature) error {
+	if Supports(feature) {
+		return nil
+	}
+	v, _ := DetectVersion()
+	return types.NewGitError("git-version",
+		fmt.Sprintf("%s requires git %s or newer (found %s); please upgrade git", feature.Name, feature.MinVersion(), v), nil)
+}
@@ -0,0 +1,38 @@
+package git
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseVersion(t *testing.T) {
+	tests := []struct {
+		output   string
+		expected string
+	}{
+		{"git version 2.39.2\n", "2.39.2"},
+		{"git version 2.39.3 (Apple Git-146)", "2.39.3"},
+		{"git version 2.45.1.windows.1", "2.45.1"},
+		{"git version 2.17", "2.17.0"},
+	}
+
+	for _, tt := range tests {
+		v, err := ParseVersion(tt.output)
+		require.NoError(t, err, tt.output)
+		assert.Equal(t, tt.expected, v.String())
+	}
+
+	_, err := ParseVersion("not git")
+	assert.Error(t, err)
+}
+
+func TestVersion_AtLeast(t *testing.T) {
+	v := Version{Major: 2, Minor: 25, Patch: 1}
+	assert.True(t, v.AtLeast(2, 17))
+	assert.True(t, v.AtLeast(2, 25))
+	assert.False(t, v.AtLeast(2, 30))
+	assert.False(t, v.AtLeast(3, 0))
+	assert.True(t, Version{Major: 3}.AtLeast(2, 42))
+}
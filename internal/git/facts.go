@@ -0,0 +1,107 @@
+package git
+
+import (
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/awhite/wtree/pkg/types"
+)
+
+// BranchFacts describes a branch relative to a base branch, for deciding
+// whether it can go
+type BranchFacts struct {
+	Branch        string
+	LastCommit    CommitInfo // Tip of the branch
+	MergeBase     string     // Empty when the branch shares no history with base
+	MergeBaseTime time.Time  // Committer date of the merge base
+	Ahead         int        // Commits on the branch that base doesn't have
+	Behind        int        // Commits on base that the branch doesn't have
+}
+
+// BranchFacts gathers BranchFacts for branches against base with a few git
+// calls for all of them rather than several per branch: one for-each-ref for
+// the tips (and, on git 2.41+, the ahead/behind counts), one merge-base per
+// branch and one show for the merge-base dates. Branches that don't exist
+// are left out.
+func (r *GitRepo) BranchFacts(branches []string, base string) (map[string]*BranchFacts, error) {
+	facts := make(map[string]*BranchFacts)
+	if len(branches) == 0 {
+		return facts, nil
+	}
+
+	batchCounts := Supports(FeatureAheadBehind)
+	format := "%(refname:short)%00%(objectname)%00%(authorname)%00%(committerdate:unix)"
+	if batchCounts {
+		format += "%00%(ahead-behind:" + base + ")"
+	}
+	args := []string{"for-each-ref", "--format=" + format}
+	for _, branch := range branches {
+		args = append(args, "refs/heads/"+branch)
+	}
+	output, err := r.query(args...)
+	if err != nil {
+		return nil, types.NewGitError("for-each-ref", "failed to read branch tips", err)
+	}
+
+	wanted := make(map[string]bool)
+	for _, branch := range branches {
+		wanted[branch] = true
+	}
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		fields := strings.Split(line, "\x00")
+		if len(fields) < 4 || !wanted[fields[0]] {
+			continue
+		}
+		fact := &BranchFacts{Branch: fields[0], LastCommit: CommitInfo{Hash: fields[1], Author: fields[2]}}
+		if seconds, err := strconv.ParseInt(fields[3], 10, 64); err == nil {
+			fact.LastCommit.Time = time.Unix(seconds, 0)
+		}
+		if batchCounts && len(fields) > 4 {
+			_, _ = fmt.Sscanf(fields[4], "%d %d", &fact.Ahead, &fact.Behind)
+		}
+		facts[fact.Branch] = fact
+	}
+
+	var mergeBases []string
+	for _, fact := range facts {
+		if !batchCounts {
+			if fact.Ahead, fact.Behind, err = r.AheadBehind(fact.Branch, base); err != nil {
+				return nil, err
+			}
+		}
+		// No merge base (exit 1) just means unrelated histories
+		if mergeBase, err := r.query("merge-base", base, fact.Branch); err == nil {
+			fact.MergeBase = strings.TrimSpace(mergeBase)
+			mergeBases = append(mergeBases, fact.MergeBase)
+		}
+	}
+
+	if len(mergeBases) > 0 {
+		output, err := r.query(append([]string{"show", "-s", "--format=%H %ct"}, mergeBases...)...)
+		if err != nil {
+			return nil, types.NewGitError("show", "failed to read merge-base dates", err)
+		}
+		dates := make(map[string]time.Time)
+		for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+			hash, seconds, _ := strings.Cut(line, " ")
+			if value, err := strconv.ParseInt(seconds, 10, 64); err == nil {
+				dates[hash] = time.Unix(value, 0)
+			}
+		}
+		for _, fact := range facts {
+			fact.MergeBaseTime = dates[fact.MergeBase]
+		}
+	}
+	return facts, nil
+}
+
+// query runs a read-only git command in the repository and returns its output
+func (r *GitRepo) query(args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = r.repoRoot
+	output, err := cmd.Output()
+	return string(output), err
+}
@@ -0,0 +1,84 @@
+package git
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/awhite/wtree/pkg/types"
+)
+
+// FormatPatches writes the commits branch has over base to dir as mailbox
+// patch files, one per commit, and returns their paths in order
+func (r *GitRepo) FormatPatches(base, branch, dir string) ([]string, error) {
+	cmd := exec.Command("git", "format-patch", "-o", dir, base+".."+branch)
+	cmd.Dir = r.repoRoot
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, types.NewGitError("format-patch",
+			fmt.Sprintf("failed to format patches for %s..%s: %s", base, branch, strings.TrimSpace(stderr.String())), err)
+	}
+	return strings.Fields(string(output)), nil
+}
+
+// CreateBundle writes branch to a bundle file. With a base, only the commits
+// base lacks are included and the receiving repository must have base.
+func (r *GitRepo) CreateBundle(file, base, branch string) error {
+	revs := branch
+	if base != "" {
+		revs = base + ".." + branch
+	}
+	cmd := exec.Command("git", "bundle", "create", "--quiet", file, revs)
+	cmd.Dir = r.repoRoot
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return types.NewGitError("bundle",
+			fmt.Sprintf("failed to bundle %s: %s", revs, strings.TrimSpace(string(output))), err)
+	}
+	return nil
+}
+
+// BundleHeads checks that a bundle can be applied to this repository, i.e.
+// that it has the commits the bundle builds on, and returns the branches in
+// it (refs/heads/...)
+func (r *GitRepo) BundleHeads(file string) ([]string, error) {
+	verify := exec.Command("git", "bundle", "verify", "--quiet", file)
+	verify.Dir = r.repoRoot
+	if output, err := verify.CombinedOutput(); err != nil {
+		return nil, types.NewGitError("bundle",
+			fmt.Sprintf("cannot use bundle %s: %s", file, strings.TrimSpace(string(output))), err)
+	}
+
+	cmd := exec.Command("git", "bundle", "list-heads", file)
+	cmd.Dir = r.repoRoot
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, types.NewGitError("bundle", fmt.Sprintf("failed to read bundle %s", file), err)
+	}
+	var heads []string
+	for _, line := range strings.Split(string(output), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 2 && strings.HasPrefix(fields[1], "refs/heads/") {
+			heads = append(heads, fields[1])
+		}
+	}
+	return heads, nil
+}
+
+// ApplyPatches commits mailbox patches onto a worktree's branch with git am,
+// falling back to a three-way merge. On failure the half-applied series is
+// aborted, leaving the worktree as it was.
+func (r *GitRepo) ApplyPatches(path string, patches []string) error {
+	args := append([]string{"am", "--3way", "--quiet"}, patches...)
+	cmd := exec.Command("git", args...)
+	cmd.Dir = path
+	if output, err := cmd.CombinedOutput(); err != nil {
+		abort := exec.Command("git", "am", "--abort")
+		abort.Dir = path
+		_ = abort.Run()
+		return types.NewGitError("am",
+			fmt.Sprintf("failed to apply patches in %s: %s", path, strings.TrimSpace(string(output))), err)
+	}
+	return nil
+}
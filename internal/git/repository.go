@@ -5,7 +5,9 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/awhite/wtree/pkg/types"
 )
@@ -14,29 +16,63 @@ import (
 type Repository interface {
 	// Repository queries
 	GetCurrentBranch() (string, error)
+	GetHeadState() (*HeadState, error)
 	BranchExists(name string) bool
 	IsClean() (bool, error)
 	GetRepoRoot() (string, error)
 	GetRepoName() string
 	GetParentDir() string
+	GetConfigValue(key string) (string, error)
+	SetConfigValue(key, value string) error
+	GetGitCommonDir() (string, error)
+	ResolveRef(ref string) (string, error)
 
 	// Branch operations
 	CreateBranch(name, from string) error
 	DeleteBranch(name string, force bool) error
 	ListBranches() ([]string, error)
+	SetBranchDescription(branch, description string) error
+	SetUpstream(branch, upstream string) error
 
 	// Worktree operations
 	CreateWorktree(path, branch string) error
 	RemoveWorktree(path string, force bool) error
+	PruneWorktrees() error
 	ListWorktrees() ([]*types.WorktreeInfo, error)
+	ListWorktreesWithStatus() ([]*types.WorktreeInfo, error)
 
 	// Status operations
 	GetWorktreeStatus(path string) (*WorktreeStatus, error)
+	GetLastCommit(path string) (*CommitInfo, error)
+	IsBranchMerged(branch, into string) (bool, error)
+	AheadBehind(branch, base string) (ahead, behind int, err error)
+	BranchFacts(branches []string, base string) (map[string]*BranchFacts, error)
+	DescribeChanges(path string) (string, error)
+	ChangedPaths(path string) ([]string, error)
+	DiffFromMergeBase(path, base string) (string, error)
 
 	// Advanced operations
-	Merge(branch string, message string) error
+	Merge(branch string, options MergeOptions) error
+	PreviewMerge(branch string) (*MergePreview, error)
+	Rebase(path, upstream string, options RebaseOptions) error
+	RebaseContinue(path string) error
+	RebaseAbort(path string) error
 	Checkout(branch string) error
+	DetachHead(path, commit string) error
 	Fetch(remote string, refspec ...string) error
+	Push(path, remote, branch string) error
+	Stash(path, message string) error
+	StashPaths(path, message string, paths []string) (string, error)
+	StashApply(path, stash string) error
+	StashDrop(stash string) error
+	StashPop(path, stash string) error
+	FindStash(message string) (string, error)
+
+	// Patch and bundle exchange
+	FormatPatches(base, branch, dir string) ([]string, error)
+	CreateBundle(file, base, branch string) error
+	BundleHeads(file string) ([]string, error)
+	ApplyPatches(path string, patches []string) error
 }
 
 // GitRepo implements Repository interface using git commands
@@ -53,6 +89,68 @@ type WorktreeStatus struct {
 	ChangedFiles int
 	Ahead        int
 	Behind       int
+	Operation    *OperationState // In-progress rebase/merge/etc., nil if none
+}
+
+// HeadState describes what HEAD points at
+type HeadState struct {
+	Branch   string // Checked-out branch; empty when detached
+	Commit   string // Commit HEAD resolves to; empty on an unborn branch
+	Detached bool
+	Unborn   bool // The branch has no commits yet, e.g. in a fresh repository
+}
+
+// String formats the state for display: "main", "detached@1a2b3c4" or
+// "main (unborn)"
+func (h *HeadState) String() string {
+	switch {
+	case h.Detached:
+		return "detached@" + ShortHash(h.Commit)
+	case h.Unborn:
+		return h.Branch + " (unborn)"
+	default:
+		return h.Branch
+	}
+}
+
+// ShortHash abbreviates a commit hash for display
+func ShortHash(hash string) string {
+	if len(hash) > 7 {
+		return hash[:7]
+	}
+	return hash
+}
+
+// CommitInfo describes a single commit
+type CommitInfo struct {
+	Hash   string
+	Author string
+	Time   time.Time
+}
+
+// MergeOptions controls how a merge commit is created
+type MergeOptions struct {
+	Message  string // Custom merge message
+	Signoff  bool   // Add a Signed-off-by trailer
+	GPGSign  bool   // GPG-sign the merge commit
+	GPGKeyID string // Key to sign with (empty uses git's default key)
+}
+
+// MergePreview describes what merging a branch into HEAD would do
+type MergePreview struct {
+	MergeBase string   // Common ancestor of HEAD and the branch
+	Commits   []string // "<short hash> <subject>" of each commit to be merged, newest first
+	Predicted bool     // Whether conflicts could be predicted (needs git merge-tree --write-tree)
+	Conflicts []string // Files that would conflict
+}
+
+// RebaseOptions defines options for rebasing a worktree's branch
+type RebaseOptions struct {
+	Onto        string // Replay the commits after upstream onto this commit instead
+	Interactive bool   // Edit the todo list first; runs git attached to the terminal
+	Signoff     bool   // Add a Signed-off-by trailer to each rebased commit
+	GPGSign     bool   // GPG-sign the rebased commits
+	GPGKeyID    string // Key to sign with (empty uses git's default key)
 }
 
 // NewRepository creates a new git repository instance
@@ -96,6 +194,24 @@ func (r *GitRepo) GetRepoRoot() (string, error) {
 	return root, nil
 }
 
+// GetGitCommonDir returns the absolute path of the git directory shared by
+// all worktrees (the main repository's .git directory)
+func (r *GitRepo) GetGitCommonDir() (string, error) {
+	cmd := exec.Command("git", "rev-parse", "--git-common-dir")
+	cmd.Dir = r.repoRoot
+	output, err := cmd.Output()
+	if err != nil {
+		return "", types.NewGitError("git-common-dir", "failed to get git common directory", err)
+	}
+
+	commonDir := strings.TrimSpace(string(output))
+	if !filepath.IsAbs(commonDir) {
+		commonDir = filepath.Join(r.repoRoot, commonDir)
+	}
+
+	return filepath.Clean(commonDir), nil
+}
+
 // GetRepoName returns the name of the repository
 func (r *GitRepo) GetRepoName() string {
 	return r.repoName
@@ -108,19 +224,43 @@ func (r *GitRepo) GetParentDir() string {
 
 // GetCurrentBranch returns the current branch name
 func (r *GitRepo) GetCurrentBranch() (string, error) {
-	cmd := exec.Command("git", "rev-parse", "--abbrev-ref", "HEAD")
-	cmd.Dir = r.repoRoot
-	output, err := cmd.Output()
+	head, err := r.GetHeadState()
 	if err != nil {
-		return "", types.NewGitError("current-branch", "failed to get current branch", err)
+		return "", err
+	}
+	if head.Detached {
+		return "", types.NewGitError("current-branch",
+			fmt.Sprintf("HEAD is detached at %s", ShortHash(head.Commit)), nil)
+	}
+	return head.Branch, nil
+}
+
+// GetHeadState reports the checked-out branch and commit, including the
+// detached and unborn states that have no branch or no commit
+func (r *GitRepo) GetHeadState() (*HeadState, error) {
+	head := &HeadState{}
+
+	cmd := exec.Command("git", "rev-parse", "-q", "--verify", "HEAD^{commit}")
+	cmd.Dir = r.repoRoot
+	if output, err := cmd.Output(); err == nil {
+		head.Commit = strings.TrimSpace(string(output))
 	}
 
-	branch := strings.TrimSpace(string(output))
-	if branch == "HEAD" {
-		return "", types.NewGitError("current-branch", "detached HEAD state", nil)
+	// symbolic-ref exits 1 when HEAD is detached
+	cmd = exec.Command("git", "symbolic-ref", "-q", "--short", "HEAD")
+	cmd.Dir = r.repoRoot
+	output, err := cmd.Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); !ok || exitErr.ExitCode() != 1 {
+			return nil, types.NewGitError("head-state", "failed to read HEAD", err)
+		}
+		head.Detached = true
+		return head, nil
 	}
 
-	return branch, nil
+	head.Branch = strings.TrimSpace(string(output))
+	head.Unborn = head.Commit == ""
+	return head, nil
 }
 
 // BranchExists checks if a branch exists
@@ -162,6 +302,17 @@ func (r *GitRepo) CreateBranch(name, from string) error {
 	return nil
 }
 
+// SetUpstream makes branch track upstream, e.g. "origin/main"
+func (r *GitRepo) SetUpstream(branch, upstream string) error {
+	cmd := exec.Command("git", "branch", "--set-upstream-to="+upstream, branch)
+	cmd.Dir = r.repoRoot
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return types.NewGitError("set-upstream",
+			fmt.Sprintf("failed to set upstream of '%s' to '%s': %s", branch, upstream, strings.TrimSpace(string(output))), err)
+	}
+	return nil
+}
+
 // DeleteBranch deletes a branch
 func (r *GitRepo) DeleteBranch(name string, force bool) error {
 	args := []string{"branch"}
@@ -206,10 +357,14 @@ func (r *GitRepo) ListBranches() ([]string, error) {
 
 // CreateWorktree creates a new worktree
 func (r *GitRepo) CreateWorktree(path, branch string) error {
-	// Ensure path doesn't exist
-	if _, err := os.Stat(path); !os.IsNotExist(err) {
+	// The path may be an empty directory (callers reserve it first), but
+	// must not already hold anything
+	if entries, err := os.ReadDir(path); err == nil && len(entries) > 0 {
 		return types.NewGitError("create-worktree",
 			fmt.Sprintf("path already exists: %s", path), nil)
+	} else if err != nil && !os.IsNotExist(err) {
+		return types.NewGitError("create-worktree",
+			fmt.Sprintf("path already exists: %s", path), err)
 	}
 
 	cmd := exec.Command("git", "worktree", "add", path, branch)
@@ -224,6 +379,9 @@ func (r *GitRepo) CreateWorktree(path, branch string) error {
 
 // RemoveWorktree removes a worktree
 func (r *GitRepo) RemoveWorktree(path string, force bool) error {
+	if err := Require(FeatureWorktreeRemove); err != nil {
+		return err
+	}
 	args := []string{"worktree", "remove"}
 	if force {
 		args = append(args, "--force")
@@ -240,6 +398,150 @@ func (r *GitRepo) RemoveWorktree(path string, force bool) error {
 	return nil
 }
 
+// Stash saves a worktree's uncommitted changes, including untracked files,
+// to the repository's stash, which outlives the worktree
+func (r *GitRepo) Stash(path, message string) error {
+	cmd := exec.Command("git", "stash", "push", "--include-untracked", "-m", message)
+	cmd.Dir = path
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return types.NewGitError("stash",
+			fmt.Sprintf("failed to stash changes in %s: %s", path, strings.TrimSpace(string(output))), err)
+	}
+
+	return nil
+}
+
+// StashPaths stashes a worktree's uncommitted changes to the given paths, or
+// to everything when paths is empty, including untracked files. It returns
+// the stash commit, or "" when there was nothing to stash.
+func (r *GitRepo) StashPaths(path, message string, paths []string) (string, error) {
+	if err := Require(FeatureStashPathspec); err != nil {
+		return "", err
+	}
+	before := r.stashTop(path)
+
+	args := []string{"stash", "push", "--include-untracked", "-m", message}
+	if len(paths) > 0 {
+		args = append(append(args, "--"), paths...)
+	}
+	cmd := exec.Command("git", args...)
+	cmd.Dir = path
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return "", types.NewGitError("stash",
+			fmt.Sprintf("failed to stash changes in %s: %s", path, strings.TrimSpace(string(output))), err)
+	}
+
+	if top := r.stashTop(path); top != before {
+		return top, nil
+	}
+	return "", nil
+}
+
+// stashTop returns the newest stash commit, or "" when the stash is empty
+func (r *GitRepo) stashTop(path string) string {
+	cmd := exec.Command("git", "rev-parse", "-q", "--verify", "refs/stash")
+	cmd.Dir = path
+	output, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// StashApply applies a stash commit to a worktree, leaving it in the stash
+func (r *GitRepo) StashApply(path, stash string) error {
+	cmd := exec.Command("git", "stash", "apply", stash)
+	cmd.Dir = path
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return types.NewGitError("stash-apply",
+			fmt.Sprintf("failed to apply stash in %s: %s", path, strings.TrimSpace(string(output))), err)
+	}
+	return nil
+}
+
+// StashDrop removes a stash commit from the stash list
+func (r *GitRepo) StashDrop(stash string) error {
+	ref, err := r.stashRef("stash-drop", stash)
+	if err != nil {
+		return err
+	}
+	cmd := exec.Command("git", "stash", "drop", ref)
+	cmd.Dir = r.repoRoot
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return types.NewGitError("stash-drop",
+			fmt.Sprintf("failed to drop stash %s: %s", ShortHash(stash), strings.TrimSpace(string(output))), err)
+	}
+	return nil
+}
+
+// StashPop applies a stash commit to the worktree at path and removes it
+// from the stash list. A conflict keeps the stash.
+func (r *GitRepo) StashPop(path, stash string) error {
+	ref, err := r.stashRef("stash-pop", stash)
+	if err != nil {
+		return err
+	}
+	cmd := exec.Command("git", "stash", "pop", ref)
+	cmd.Dir = path
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return types.NewGitError("stash-pop",
+			fmt.Sprintf("failed to restore stash %s in %s: %s", ShortHash(stash), path, strings.TrimSpace(string(output))), err)
+	}
+	return nil
+}
+
+// FindStash returns the newest stash commit whose message is message, as
+// given to Stash, or "" when there is none. Stashes are shared by all of a
+// repository's worktrees.
+func (r *GitRepo) FindStash(message string) (string, error) {
+	cmd := exec.Command("git", "stash", "list", "--format=%H%x00%s")
+	cmd.Dir = r.repoRoot
+	output, err := cmd.Output()
+	if err != nil {
+		return "", types.NewGitError("stash-list", "failed to list stashes", err)
+	}
+	return findStash(string(output), message), nil
+}
+
+// findStash picks the first stash named message from stash list lines of
+// hash and subject; git records the subject as "On <branch>: <message>"
+func findStash(output, message string) string {
+	for _, line := range strings.Split(output, "\n") {
+		hash, subject, ok := strings.Cut(line, "\x00")
+		if ok && (subject == message || strings.HasSuffix(subject, ": "+message)) {
+			return hash
+		}
+	}
+	return ""
+}
+
+// stashRef returns the stash@{n} entry of a stash commit
+func (r *GitRepo) stashRef(operation, stash string) (string, error) {
+	cmd := exec.Command("git", "stash", "list", "--format=%H")
+	cmd.Dir = r.repoRoot
+	output, err := cmd.Output()
+	if err != nil {
+		return "", types.NewGitError(operation, "failed to list stashes", err)
+	}
+	for i, hash := range strings.Fields(string(output)) {
+		if hash == stash {
+			return fmt.Sprintf("stash@{%d}", i), nil
+		}
+	}
+	return "", types.NewGitError(operation, fmt.Sprintf("stash %s not found", ShortHash(stash)), nil)
+}
+
+// PruneWorktrees drops git's records of worktrees whose directories are gone
+func (r *GitRepo) PruneWorktrees() error {
+	cmd := exec.Command("git", "worktree", "prune")
+	cmd.Dir = r.repoRoot
+	if err := cmd.Run(); err != nil {
+		return types.NewGitError("prune-worktrees", "failed to prune worktrees", err)
+	}
+
+	return nil
+}
+
 // ListWorktrees returns a list of all worktrees
 func (r *GitRepo) ListWorktrees() ([]*types.WorktreeInfo, error) {
 	cmd := exec.Command("git", "worktree", "list", "--porcelain")
@@ -249,7 +551,79 @@ func (r *GitRepo) ListWorktrees() ([]*types.WorktreeInfo, error) {
 		return nil, types.NewGitError("list-worktrees", "failed to list worktrees", err)
 	}
 
-	return r.parseWorktreeList(string(output))
+	worktrees, err := r.parseWorktreeList(string(output))
+	if err != nil {
+		return nil, err
+	}
+	r.fillTracking(worktrees)
+	return worktrees, nil
+}
+
+// ListWorktreesWithStatus lists worktrees like ListWorktrees and also checks
+// each one's status, which costs a few git calls per worktree
+func (r *GitRepo) ListWorktreesWithStatus() ([]*types.WorktreeInfo, error) {
+	worktrees, err := r.ListWorktrees()
+	if err != nil {
+		return nil, err
+	}
+	for _, wt := range worktrees {
+		if wt.Prunable {
+			continue
+		}
+		status, err := r.GetWorktreeStatus(wt.Path)
+		if err != nil {
+			continue
+		}
+		wt.StatusLoaded = true
+		wt.IsClean = status.IsClean
+		if status.Operation != nil {
+			wt.Operation = status.Operation.String()
+		}
+	}
+	return worktrees, nil
+}
+
+// fillTracking sets the upstream of each worktree's branch and how far apart
+// they are, from one for-each-ref; worktrees keep zero values if it fails
+func (r *GitRepo) fillTracking(worktrees []*types.WorktreeInfo) {
+	output, err := r.query("for-each-ref", "--format=%(refname:short)%00%(upstream:short)%00%(upstream:track,nobracket)", "refs/heads")
+	if err != nil {
+		return
+	}
+	tracking := parseTracking(output)
+	for _, wt := range worktrees {
+		if t, ok := tracking[wt.Branch]; ok && wt.Branch != "" {
+			wt.Upstream, wt.Ahead, wt.Behind = t.upstream, t.ahead, t.behind
+		}
+	}
+}
+
+// branchTracking is a branch's upstream and how far apart they are
+type branchTracking struct {
+	upstream      string
+	ahead, behind int
+}
+
+// parseTracking parses for-each-ref lines of branch, upstream and track
+// ("ahead 2, behind 1", "gone" or empty), NUL separated
+func parseTracking(output string) map[string]branchTracking {
+	tracking := make(map[string]branchTracking)
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		fields := strings.Split(line, "\x00")
+		if len(fields) < 3 || fields[1] == "" {
+			continue
+		}
+		t := branchTracking{upstream: fields[1]}
+		for _, part := range strings.Split(fields[2], ", ") {
+			if count, ok := strings.CutPrefix(part, "ahead "); ok {
+				t.ahead, _ = strconv.Atoi(count)
+			} else if count, ok := strings.CutPrefix(part, "behind "); ok {
+				t.behind, _ = strconv.Atoi(count)
+			}
+		}
+		tracking[fields[0]] = t
+	}
+	return tracking
 }
 
 // parseWorktreeList parses the output of git worktree list --porcelain
@@ -272,10 +646,20 @@ func (r *GitRepo) parseWorktreeList(output string) ([]*types.WorktreeInfo, error
 				Path: strings.TrimPrefix(line, "worktree "),
 			}
 		} else if strings.HasPrefix(line, "HEAD ") && current != nil {
-			// Extract commit hash if needed
+			current.Head = strings.TrimPrefix(line, "HEAD ")
+			// An unborn branch has no commit yet
+			current.Unborn = strings.Trim(current.Head, "0") == ""
+		} else if line == "detached" && current != nil {
+			current.Detached = true
 		} else if strings.HasPrefix(line, "branch ") && current != nil {
 			branch := strings.TrimPrefix(line, "branch refs/heads/")
 			current.Branch = branch
+		} else if (line == "locked" || strings.HasPrefix(line, "locked ")) && current != nil {
+			current.Locked = true
+			current.LockReason = strings.TrimPrefix(strings.TrimPrefix(line, "locked"), " ")
+		} else if (line == "prunable" || strings.HasPrefix(line, "prunable ")) && current != nil {
+			current.Prunable = true
+			current.PrunableReason = strings.TrimPrefix(strings.TrimPrefix(line, "prunable"), " ")
 		} else if line == "bare" && current != nil {
 			current.IsMainRepo = true
 		}
@@ -285,12 +669,10 @@ func (r *GitRepo) parseWorktreeList(output string) ([]*types.WorktreeInfo, error
 		worktrees = append(worktrees, current)
 	}
 
-	// Determine main repository
-	for _, wt := range worktrees {
-		if wt.Path == r.repoRoot {
-			wt.IsMainRepo = true
-			break
-		}
+	// Git always lists the main worktree first; repoRoot can't be used here
+	// because it is the current linked worktree when run from inside one
+	if len(worktrees) > 0 {
+		worktrees[0].IsMainRepo = true
 	}
 
 	return worktrees, nil
@@ -324,22 +706,419 @@ func (r *GitRepo) GetWorktreeStatus(path string) (*WorktreeStatus, error) {
 		}
 	}
 
+	// Detect rebase/merge/cherry-pick/bisect left in progress
+	if operation, err := DetectOperationState(path); err == nil {
+		status.Operation = operation
+	}
+
 	return status, nil
 }
 
+// HasUntrackedFiles reports whether the worktree at path has untracked files
+// that aren't ignored; WorktreeStatus.IsClean doesn't count them
+func HasUntrackedFiles(path string) (bool, error) {
+	cmd := exec.Command("git", "ls-files", "--others", "--exclude-standard", "--directory", "--no-empty-directory")
+	cmd.Dir = path
+	output, err := cmd.Output()
+	if err != nil {
+		return false, types.NewGitError("worktree-status", "failed to list untracked files", err)
+	}
+	return strings.TrimSpace(string(output)) != "", nil
+}
+
 // Merge merges a branch into the current branch
-func (r *GitRepo) Merge(branch string, message string) error {
+func (r *GitRepo) Merge(branch string, options MergeOptions) error {
+	cmd := exec.Command("git", mergeArgs(branch, options)...)
+	cmd.Dir = r.repoRoot
+	if err := cmd.Run(); err != nil {
+		return types.NewGitError("merge",
+			fmt.Sprintf("failed to merge branch '%s'", branch), err)
+	}
+
+	return nil
+}
+
+// mergeArgs builds the git merge command line for Merge
+func mergeArgs(branch string, options MergeOptions) []string {
 	args := []string{"merge"}
-	if message != "" {
-		args = append(args, "-m", message)
+	if options.Message != "" {
+		args = append(args, "-m", options.Message)
+	}
+	if options.Signoff {
+		args = append(args, "--signoff")
 	}
-	args = append(args, branch)
+	if options.GPGSign {
+		args = append(args, "--gpg-sign"+keyIDSuffix(options.GPGKeyID))
+	}
+	return append(args, branch)
+}
+
+// PreviewMerge works out what merging branch into HEAD would do without
+// touching the working tree or index
+func (r *GitRepo) PreviewMerge(branch string) (*MergePreview, error) {
+	mergeBase := exec.Command("git", "merge-base", "HEAD", branch)
+	mergeBase.Dir = r.repoRoot
+	output, err := mergeBase.Output()
+	if err != nil {
+		return nil, types.NewGitError("merge-base",
+			fmt.Sprintf("no common ancestor with '%s'", branch), err)
+	}
+	preview := &MergePreview{MergeBase: strings.TrimSpace(string(output))}
+
+	log := exec.Command("git", "log", "--format=%h %s", "HEAD.."+branch)
+	log.Dir = r.repoRoot
+	if output, err = log.Output(); err != nil {
+		return nil, types.NewGitError("log",
+			fmt.Sprintf("failed to list the commits '%s' would merge", branch), err)
+	}
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		if line != "" {
+			preview.Commits = append(preview.Commits, line)
+		}
+	}
+
+	if len(preview.Commits) == 0 || !Supports(FeatureMergeTree) {
+		return preview, nil
+	}
+	mergeTree := exec.Command("git", "merge-tree", "--write-tree", "--name-only", "--no-messages", "HEAD", branch)
+	mergeTree.Dir = r.repoRoot
+	output, err = mergeTree.Output()
+	if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 1 {
+		err = nil // Conflicts
+	}
+	if err != nil {
+		return nil, types.NewGitError("merge-tree",
+			fmt.Sprintf("failed to predict conflicts merging '%s'", branch), err)
+	}
+	preview.Predicted = true
+	preview.Conflicts = parseMergeTreeConflicts(string(output))
+	return preview, nil
+}
+
+// parseMergeTreeConflicts reads the conflicted files from the output of
+// `git merge-tree --write-tree --name-only`: the lines between the tree
+// object on the first line and the first blank line
+func parseMergeTreeConflicts(output string) []string {
+	var conflicts []string
+	seen := map[string]bool{}
+	lines := strings.Split(output, "\n")
+	for _, line := range lines[1:] {
+		if line == "" {
+			break
+		}
+		if !seen[line] {
+			seen[line] = true
+			conflicts = append(conflicts, line)
+		}
+	}
+	return conflicts
+}
+
+// Rebase rebases the branch checked out at path onto upstream. A conflict
+// leaves the rebase in progress for the user to resolve.
+func (r *GitRepo) Rebase(path, upstream string, options RebaseOptions) error {
+	args := []string{"rebase"}
+	if options.Onto != "" {
+		args = append(args, "--onto", options.Onto)
+	}
+	if options.Signoff {
+		args = append(args, "--signoff")
+	}
+	if options.GPGSign {
+		args = append(args, "--gpg-sign"+keyIDSuffix(options.GPGKeyID))
+	}
+	if options.Interactive {
+		args = append(args, "--interactive")
+	}
+	args = append(args, upstream)
 
 	cmd := exec.Command("git", args...)
+	cmd.Dir = path
+	if options.Interactive {
+		return runAttached(cmd, "rebase", fmt.Sprintf("failed to rebase onto '%s'", upstream))
+	}
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return types.NewGitError("rebase",
+			fmt.Sprintf("failed to rebase onto '%s': %s", upstream, strings.TrimSpace(string(output))), err)
+	}
+
+	return nil
+}
+
+// RebaseContinue resumes the rebase in progress at path after conflicts were
+// resolved. git may open an editor for a commit message, so it runs attached
+// to the terminal.
+func (r *GitRepo) RebaseContinue(path string) error {
+	cmd := exec.Command("git", "rebase", "--continue")
+	cmd.Dir = path
+	return runAttached(cmd, "rebase", "failed to continue the rebase")
+}
+
+// RebaseAbort abandons the rebase in progress at path, restoring the branch
+// as it was before
+func (r *GitRepo) RebaseAbort(path string) error {
+	cmd := exec.Command("git", "rebase", "--abort")
+	cmd.Dir = path
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return types.NewGitError("rebase",
+			fmt.Sprintf("failed to abort the rebase: %s", strings.TrimSpace(string(output))), err)
+	}
+	return nil
+}
+
+// runAttached runs a git command that may need the user, such as one opening
+// an editor, connected to the terminal
+func runAttached(cmd *exec.Cmd, operation, message string) error {
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return types.NewGitError(operation, message, err)
+	}
+	return nil
+}
+
+// keyIDSuffix formats an optional GPG key ID for --gpg-sign=<keyid>
+func keyIDSuffix(keyID string) string {
+	if keyID == "" {
+		return ""
+	}
+	return "=" + keyID
+}
+
+// GetLastCommit returns the HEAD commit of the worktree at path
+func (r *GitRepo) GetLastCommit(path string) (*CommitInfo, error) {
+	cmd := exec.Command("git", "log", "-1", "--format=%H%x00%an%x00%ct")
+	cmd.Dir = path
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, types.NewGitError("log", fmt.Sprintf("failed to read last commit in %s", path), err)
+	}
+
+	fields := strings.Split(strings.TrimSpace(string(output)), "\x00")
+	if len(fields) != 3 {
+		return nil, types.NewGitError("log", fmt.Sprintf("no commits in %s", path), nil)
+	}
+
+	timestamp, err := strconv.ParseInt(fields[2], 10, 64)
+	if err != nil {
+		return nil, types.NewGitError("log", "invalid commit timestamp", err)
+	}
+
+	return &CommitInfo{Hash: fields[0], Author: fields[1], Time: time.Unix(timestamp, 0)}, nil
+}
+
+// DescribeChanges summarizes a worktree's uncommitted changes as
+// `git status --short` followed by `git diff --stat HEAD`
+func (r *GitRepo) DescribeChanges(path string) (string, error) {
+	status := exec.Command("git", "status", "--short")
+	status.Dir = path
+	statusOutput, err := status.Output()
+	if err != nil {
+		return "", types.NewGitError("status", fmt.Sprintf("failed to get status of %s", path), err)
+	}
+
+	diff := exec.Command("git", "diff", "--stat", "HEAD")
+	diff.Dir = path
+	diffOutput, err := diff.Output()
+	if err != nil {
+		return "", types.NewGitError("diff", fmt.Sprintf("failed to get diff stat of %s", path), err)
+	}
+
+	return strings.TrimRight(string(statusOutput)+string(diffOutput), "\n"), nil
+}
+
+// ChangedPaths lists the files of the worktree at path with uncommitted
+// changes, untracked ones included, relative to the worktree root
+func (r *GitRepo) ChangedPaths(path string) ([]string, error) {
+	cmd := exec.Command("git", "status", "--porcelain", "-z", "--untracked-files=all")
+	cmd.Dir = path
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, types.NewGitError("status", fmt.Sprintf("failed to get status of %s", path), err)
+	}
+	return parseChangedPaths(string(output)), nil
+}
+
+// parseChangedPaths parses git status --porcelain -z: "XY path" records,
+// where renames and copies are followed by a record with the old path
+func parseChangedPaths(output string) []string {
+	var paths []string
+	records := strings.Split(output, "\x00")
+	for i := 0; i < len(records); i++ {
+		record := records[i]
+		if len(record) < 4 {
+			continue
+		}
+		paths = append(paths, record[3:])
+		if record[0] == 'R' || record[0] == 'C' {
+			i++
+		}
+	}
+	return paths
+}
+
+// DiffFromMergeBase returns the zero-context diff of the worktree at path,
+// including uncommitted changes, against its merge base with base
+func (r *GitRepo) DiffFromMergeBase(path, base string) (string, error) {
+	mergeBase := exec.Command("git", "merge-base", base, "HEAD")
+	mergeBase.Dir = path
+	output, err := mergeBase.Output()
+	if err != nil {
+		return "", types.NewGitError("merge-base",
+			fmt.Sprintf("failed to find the merge base of %s and '%s'", path, base), err)
+	}
+
+	diff := exec.Command("git", "diff", "--unified=0", "--no-color", strings.TrimSpace(string(output)))
+	diff.Dir = path
+	diffOutput, err := diff.Output()
+	if err != nil {
+		return "", types.NewGitError("diff", fmt.Sprintf("failed to diff %s against '%s'", path, base), err)
+	}
+
+	return string(diffOutput), nil
+}
+
+// IsBranchMerged reports whether branch is fully merged into the into branch
+func (r *GitRepo) IsBranchMerged(branch, into string) (bool, error) {
+	cmd := exec.Command("git", "merge-base", "--is-ancestor", branch, into)
 	cmd.Dir = r.repoRoot
 	if err := cmd.Run(); err != nil {
-		return types.NewGitError("merge",
-			fmt.Sprintf("failed to merge branch '%s'", branch), err)
+		if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 1 {
+			return false, nil
+		}
+		return false, types.NewGitError("merge-base",
+			fmt.Sprintf("failed to check if '%s' is merged into '%s'", branch, into), err)
+	}
+
+	return true, nil
+}
+
+// AheadBehind counts the commits branch has that base doesn't, and the
+// commits base has that branch doesn't
+func (r *GitRepo) AheadBehind(branch, base string) (ahead, behind int, err error) {
+	cmd := exec.Command("git", "rev-list", "--left-right", "--count", branch+"..."+base)
+	cmd.Dir = r.repoRoot
+	output, err := cmd.Output()
+	if err != nil {
+		return 0, 0, types.NewGitError("rev-list",
+			fmt.Sprintf("failed to compare '%s' with '%s'", branch, base), err)
+	}
+	if _, err := fmt.Sscanf(string(output), "%d %d", &ahead, &behind); err != nil {
+		return 0, 0, types.NewGitError("rev-list",
+			fmt.Sprintf("unexpected output comparing '%s' with '%s': %q", branch, base, output), err)
+	}
+	return ahead, behind, nil
+}
+
+// GetConfigValue reads a git config value as seen from the repository,
+// including any per-worktree configuration. Unset keys return an empty string.
+func (r *GitRepo) GetConfigValue(key string) (string, error) {
+	cmd := exec.Command("git", "config", "--get", key)
+	cmd.Dir = r.workingDir
+	output, err := cmd.Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 1 {
+			return "", nil
+		}
+		return "", types.NewGitError("config",
+			fmt.Sprintf("failed to read git config '%s'", key), err)
+	}
+
+	return strings.TrimSpace(string(output)), nil
+}
+
+// ParseConfigBool interprets a git config value as git does for booleans:
+// true, yes and on, or false, no, off and empty, in any case, and integers,
+// true unless 0
+func ParseConfigBool(value string) (bool, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "true", "yes", "on":
+		return true, nil
+	case "false", "no", "off", "":
+		return false, nil
+	}
+	n, err := strconv.ParseInt(strings.TrimSpace(value), 0, 64)
+	if err != nil {
+		return false, fmt.Errorf("invalid boolean config value %q", value)
+	}
+	return n != 0, nil
+}
+
+// SetConfigValue writes a repository git config value; an empty value
+// unsets the key
+func (r *GitRepo) SetConfigValue(key, value string) error {
+	args := []string{"config", key, value}
+	if value == "" {
+		args = []string{"config", "--unset", key}
+	}
+	cmd := exec.Command("git", args...)
+	cmd.Dir = r.repoRoot
+	if err := cmd.Run(); err != nil {
+		// Unsetting a key that isn't set exits 5
+		if exitErr, ok := err.(*exec.ExitError); ok && value == "" && exitErr.ExitCode() == 5 {
+			return nil
+		}
+		return types.NewGitError("config",
+			fmt.Sprintf("failed to set git config '%s'", key), err)
+	}
+
+	return nil
+}
+
+// ScopedConfigValue reads a git config value from one scope: "global" for
+// the user's configuration, or "local" for the repository in the working
+// directory. Unset keys return an empty string.
+func ScopedConfigValue(scope, key string) (string, error) {
+	output, err := exec.Command("git", "config", "--"+scope, "--get", key).Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 1 {
+			return "", nil
+		}
+		return "", types.NewGitError("config",
+			fmt.Sprintf("failed to read %s git config '%s'", scope, key), err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// SetScopedConfigValue writes a git config value in one scope, as for
+// ScopedConfigValue; an empty value unsets the key
+func SetScopedConfigValue(scope, key, value string) error {
+	args := []string{"config", "--" + scope, key, value}
+	if value == "" {
+		args = []string{"config", "--" + scope, "--unset", key}
+	}
+	if err := exec.Command("git", args...).Run(); err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok && value == "" && exitErr.ExitCode() == 5 {
+			return nil
+		}
+		return types.NewGitError("config",
+			fmt.Sprintf("failed to set %s git config '%s'", scope, key), err)
+	}
+	return nil
+}
+
+// ResolveRef returns the commit a branch, tag or other revision points at
+func (r *GitRepo) ResolveRef(ref string) (string, error) {
+	cmd := exec.Command("git", "rev-parse", "--verify", "--quiet", ref+"^{commit}")
+	cmd.Dir = r.repoRoot
+	output, err := cmd.Output()
+	if err != nil {
+		return "", types.NewGitError("rev-parse",
+			fmt.Sprintf("unknown revision '%s'", ref), err)
+	}
+
+	return strings.TrimSpace(string(output)), nil
+}
+
+// SetBranchDescription stores a branch description in branch.<name>.description,
+// the same key used by `git branch --edit-description`
+func (r *GitRepo) SetBranchDescription(branch, description string) error {
+	cmd := exec.Command("git", "config", "branch."+branch+".description", description)
+	cmd.Dir = r.repoRoot
+	if err := cmd.Run(); err != nil {
+		return types.NewGitError("branch-description",
+			fmt.Sprintf("failed to set description for branch '%s'", branch), err)
 	}
 
 	return nil
@@ -357,6 +1136,29 @@ func (r *GitRepo) Checkout(branch string) error {
 	return nil
 }
 
+// DetachHead checks out commit in the worktree at path with a detached HEAD
+func (r *GitRepo) DetachHead(path, commit string) error {
+	cmd := exec.Command("git", "checkout", "--quiet", "--detach", commit)
+	cmd.Dir = path
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return types.NewGitError("checkout",
+			fmt.Sprintf("failed to check out %s in %s: %s", ShortHash(commit), path, strings.TrimSpace(string(output))), err)
+	}
+	return nil
+}
+
+// Push pushes branch from the worktree at path to remote and makes the
+// pushed branch its upstream
+func (r *GitRepo) Push(path, remote, branch string) error {
+	cmd := exec.Command("git", "push", "--set-upstream", remote, branch)
+	cmd.Dir = path
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return types.NewGitError("push",
+			fmt.Sprintf("failed to push '%s' to '%s': %s", branch, remote, strings.TrimSpace(string(output))), err)
+	}
+	return nil
+}
+
 // Fetch fetches from remote repository
 func (r *GitRepo) Fetch(remote string, refspecs ...string) error {
 	args := []string{"fetch", remote}
@@ -0,0 +1,215 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseWorktreeList_MainIsFirstEntry(t *testing.T) {
+	output := `worktree /src/repo
+HEAD 1111111111111111111111111111111111111111
+branch refs/heads/main
+
+worktree /src/repo-feature
+HEAD 2222222222222222222222222222222222222222
+branch refs/heads/feature
+`
+
+	// Run from inside the linked worktree
+	r := &GitRepo{repoRoot: "/src/repo-feature"}
+	worktrees, err := r.parseWorktreeList(output)
+	require.NoError(t, err)
+	require.Len(t, worktrees, 2)
+
+	assert.True(t, worktrees[0].IsMainRepo)
+	assert.Equal(t, "main", worktrees[0].Branch)
+	assert.False(t, worktrees[1].IsMainRepo)
+	assert.Equal(t, "feature", worktrees[1].Branch)
+}
+
+func TestParseWorktreeList_DetachedAndUnborn(t *testing.T) {
+	output := `worktree /src/repo
+HEAD 0000000000000000000000000000000000000000
+branch refs/heads/main
+
+worktree /src/repo-review
+HEAD 2222222222222222222222222222222222222222
+detached
+locked on a usb drive
+`
+
+	r := &GitRepo{repoRoot: "/src/repo"}
+	worktrees, err := r.parseWorktreeList(output)
+	require.NoError(t, err)
+	require.Len(t, worktrees, 2)
+
+	assert.True(t, worktrees[0].Unborn)
+	assert.Equal(t, "main (unborn)", worktrees[0].DisplayBranch())
+
+	assert.True(t, worktrees[1].Detached)
+	assert.Empty(t, worktrees[1].Branch)
+	assert.Equal(t, "2222222222222222222222222222222222222222", worktrees[1].Head)
+	assert.Equal(t, "detached@2222222", worktrees[1].DisplayBranch())
+	assert.True(t, worktrees[1].Locked)
+	assert.Equal(t, "on a usb drive", worktrees[1].LockReason)
+	assert.False(t, worktrees[0].Locked)
+}
+
+func TestParseWorktreeList_LockedAndPrunable(t *testing.T) {
+	output := `worktree /src/repo
+HEAD 1111111111111111111111111111111111111111
+branch refs/heads/main
+
+worktree /src/repo-locked
+HEAD 2222222222222222222222222222222222222222
+branch refs/heads/locked
+locked
+
+worktree /src/repo-gone
+HEAD 3333333333333333333333333333333333333333
+branch refs/heads/gone
+prunable gitdir file points to non-existent location
+`
+
+	r := &GitRepo{repoRoot: "/src/repo"}
+	worktrees, err := r.parseWorktreeList(output)
+	require.NoError(t, err)
+	require.Len(t, worktrees, 3)
+
+	assert.True(t, worktrees[1].Locked)
+	assert.Empty(t, worktrees[1].LockReason)
+	assert.False(t, worktrees[1].Prunable)
+
+	assert.True(t, worktrees[2].Prunable)
+	assert.Equal(t, "gitdir file points to non-existent location", worktrees[2].PrunableReason)
+	assert.Equal(t, "3333333333333333333333333333333333333333", worktrees[2].Head)
+}
+
+func TestFindStash(t *testing.T) {
+	output := "aaa\x00On main: unrelated\n" +
+		"bbb\x00On feature: wtree-auto/feature\n" +
+		"ccc\x00On feature: wtree-auto/feature\n" +
+		"ddd\x00WIP on other: 1234567 subject\n"
+
+	assert.Equal(t, "bbb", findStash(output, "wtree-auto/feature"))
+	assert.Empty(t, findStash(output, "wtree-auto/other"))
+	assert.Empty(t, findStash("", "wtree-auto/feature"))
+}
+
+func TestParseTracking(t *testing.T) {
+	output := "main\x00origin/main\x00\n" +
+		"feature\x00origin/feature\x00ahead 2, behind 1\n" +
+		"ahead\x00origin/ahead\x00ahead 3\n" +
+		"deleted\x00origin/deleted\x00gone\n" +
+		"local\x00\x00\n"
+
+	tracking := parseTracking(output)
+	assert.Equal(t, branchTracking{upstream: "origin/main"}, tracking["main"])
+	assert.Equal(t, branchTracking{upstream: "origin/feature", ahead: 2, behind: 1}, tracking["feature"])
+	assert.Equal(t, branchTracking{upstream: "origin/ahead", ahead: 3}, tracking["ahead"])
+	assert.Equal(t, branchTracking{upstream: "origin/deleted"}, tracking["deleted"])
+	assert.NotContains(t, tracking, "local")
+}
+
+func TestHeadState_String(t *testing.T) {
+	assert.Equal(t, "main", (&HeadState{Branch: "main", Commit: "abc"}).String())
+	assert.Equal(t, "detached@1a2b3c4", (&HeadState{Commit: "1a2b3c4d5e6f", Detached: true}).String())
+	assert.Equal(t, "main (unborn)", (&HeadState{Branch: "main", Unborn: true}).String())
+}
+
+func TestParseMergeTreeConflicts(t *testing.T) {
+	assert.Empty(t, parseMergeTreeConflicts("4b825dc642cb6eb9a060e54bf8d69288fbee4904\n"))
+
+	output := "4b825dc642cb6eb9a060e54bf8d69288fbee4904\nmain.go\nREADME.md\nmain.go\n\nAuto-merging main.go\n"
+	assert.Equal(t, []string{"main.go", "README.md"}, parseMergeTreeConflicts(output))
+}
+
+func TestParseChangedPaths(t *testing.T) {
+	assert.Empty(t, parseChangedPaths(""))
+
+	output := " M main.go\x00R  new.go\x00old.go\x00?? docs/notes.md\x00"
+	assert.Equal(t, []string{"main.go", "new.go", "docs/notes.md"}, parseChangedPaths(output))
+}
+
+func TestParseSubmodules(t *testing.T) {
+	output := "submodule.vendor/lib.path vendor/lib\nsubmodule.docs.path site/docs\n"
+	assert.Equal(t, []Submodule{
+		{Name: "vendor/lib", Path: "vendor/lib"},
+		{Name: "docs", Path: "site/docs"},
+	}, parseSubmodules(output))
+	assert.Empty(t, parseSubmodules(""))
+}
+
+func TestParseConfigBool(t *testing.T) {
+	for _, value := range []string{"true", "yes", "on", "TRUE", "Yes", "1", "2", "-1"} {
+		enabled, err := ParseConfigBool(value)
+		require.NoError(t, err, value)
+		assert.True(t, enabled, value)
+	}
+	for _, value := range []string{"false", "no", "off", "Off", "0", ""} {
+		enabled, err := ParseConfigBool(value)
+		require.NoError(t, err, value)
+		assert.False(t, enabled, value)
+	}
+	_, err := ParseConfigBool("maybe")
+	assert.Error(t, err)
+}
+
+func TestMergeArgs(t *testing.T) {
+	assert.Equal(t, []string{"merge", "feature"}, mergeArgs("feature", MergeOptions{}))
+	assert.Equal(t, []string{"merge", "-m", "Merge it", "--signoff", "--gpg-sign", "feature"},
+		mergeArgs("feature", MergeOptions{Message: "Merge it", Signoff: true, GPGSign: true}))
+	assert.Equal(t, []string{"merge", "--gpg-sign=ABCD1234", "feature"},
+		mergeArgs("feature", MergeOptions{GPGSign: true, GPGKeyID: "ABCD1234"}))
+}
+
+// newTestRepo creates a repository with one commit on main
+func newTestRepo(t *testing.T) Repository {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	dir, err := filepath.EvalSymlinks(t.TempDir())
+	require.NoError(t, err)
+	for _, args := range [][]string{
+		{"init", "--quiet", "--initial-branch=main"},
+		{"-c", "user.name=wtree test", "-c", "user.email=test@example.com", "commit", "--quiet", "--allow-empty", "-m", "Initial commit"},
+		{"branch", "feature"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		output, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(output))
+	}
+	repo, err := NewRepository(dir)
+	require.NoError(t, err)
+	return repo
+}
+
+// Create reserves the worktree directory before git worktree add, which
+// CreateWorktree used to refuse as already existing
+func TestGitRepo_CreateWorktreeIntoReservedDirectory(t *testing.T) {
+	repo := newTestRepo(t)
+	path := filepath.Join(repo.GetParentDir(), "reserved")
+	require.NoError(t, os.Mkdir(path, 0755))
+
+	require.NoError(t, repo.CreateWorktree(path, "feature"))
+	assert.FileExists(t, filepath.Join(path, ".git"))
+}
+
+func TestGitRepo_CreateWorktreeRefusesNonEmptyPath(t *testing.T) {
+	repo := newTestRepo(t)
+	path := filepath.Join(repo.GetParentDir(), "taken")
+	require.NoError(t, os.Mkdir(path, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(path, "keep.txt"), []byte("keep"), 0644))
+
+	err := repo.CreateWorktree(path, "feature")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "already exists")
+	assert.FileExists(t, filepath.Join(path, "keep.txt"))
+}
@@ -0,0 +1,155 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// OperationKind identifies a multi-step git operation that can be left in progress
+type OperationKind string
+
+const (
+	OperationRebase     OperationKind = "rebase"
+	OperationMerge      OperationKind = "merge"
+	OperationCherryPick OperationKind = "cherry-pick"
+	OperationRevert     OperationKind = "revert"
+	OperationBisect     OperationKind = "bisect"
+	OperationApplyMail  OperationKind = "am"
+)
+
+// OperationState describes a git operation in progress in a worktree
+type OperationState struct {
+	Kind    OperationKind
+	Current int // Current step, when git records one (rebase/am)
+	Total   int // Total steps, when git records one (rebase/am)
+}
+
+// String formats the state for display, e.g. "REBASING 3/7"
+func (s *OperationState) String() string {
+	var label string
+	switch s.Kind {
+	case OperationRebase:
+		label = "REBASING"
+	case OperationMerge:
+		label = "MERGING"
+	case OperationCherryPick:
+		label = "CHERRY-PICKING"
+	case OperationRevert:
+		label = "REVERTING"
+	case OperationBisect:
+		label = "BISECTING"
+	case OperationApplyMail:
+		label = "AM"
+	default:
+		label = strings.ToUpper(string(s.Kind))
+	}
+
+	if s.Total > 0 {
+		return fmt.Sprintf("%s %d/%d", label, s.Current, s.Total)
+	}
+	return label
+}
+
+// DetectOperationState inspects a worktree's git directory for in-progress
+// rebase, merge, cherry-pick, revert, bisect, or am state. It returns nil
+// when no operation is in progress.
+func DetectOperationState(worktreePath string) (*OperationState, error) {
+	gitDir, err := ResolveGitDir(worktreePath)
+	if err != nil {
+		return nil, err
+	}
+
+	// Interactive and merge-based rebases
+	if dirExists(filepath.Join(gitDir, "rebase-merge")) {
+		return &OperationState{
+			Kind:    OperationRebase,
+			Current: readIntFile(filepath.Join(gitDir, "rebase-merge", "msgnum")),
+			Total:   readIntFile(filepath.Join(gitDir, "rebase-merge", "end")),
+		}, nil
+	}
+
+	// Apply-based rebases and git am share rebase-apply
+	applyDir := filepath.Join(gitDir, "rebase-apply")
+	if dirExists(applyDir) {
+		kind := OperationRebase
+		if fileExists(filepath.Join(applyDir, "applying")) {
+			kind = OperationApplyMail
+		}
+		return &OperationState{
+			Kind:    kind,
+			Current: readIntFile(filepath.Join(applyDir, "next")),
+			Total:   readIntFile(filepath.Join(applyDir, "last")),
+		}, nil
+	}
+
+	markers := []struct {
+		file string
+		kind OperationKind
+	}{
+		{"MERGE_HEAD", OperationMerge},
+		{"CHERRY_PICK_HEAD", OperationCherryPick},
+		{"REVERT_HEAD", OperationRevert},
+		{"BISECT_LOG", OperationBisect},
+	}
+	for _, marker := range markers {
+		if fileExists(filepath.Join(gitDir, marker.file)) {
+			return &OperationState{Kind: marker.kind}, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// ResolveGitDir returns the git directory for a worktree, following the
+// "gitdir: <path>" file that linked worktrees use in place of a .git directory
+func ResolveGitDir(worktreePath string) (string, error) {
+	dotGit := filepath.Join(worktreePath, ".git")
+	info, err := os.Stat(dotGit)
+	if err != nil {
+		return "", fmt.Errorf("failed to read git directory for %s: %w", worktreePath, err)
+	}
+
+	if info.IsDir() {
+		return dotGit, nil
+	}
+
+	data, err := os.ReadFile(dotGit)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", dotGit, err)
+	}
+
+	gitDir := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(string(data)), "gitdir:"))
+	if gitDir == "" {
+		return "", fmt.Errorf("invalid .git file in %s", worktreePath)
+	}
+	if !filepath.IsAbs(gitDir) {
+		gitDir = filepath.Join(worktreePath, gitDir)
+	}
+
+	return filepath.Clean(gitDir), nil
+}
+
+func readIntFile(path string) int {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0
+	}
+	value, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil {
+		return 0
+	}
+	return value
+}
+
+func dirExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
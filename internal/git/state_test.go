@@ -0,0 +1,100 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectOperationState(t *testing.T) {
+	tests := []struct {
+		name     string
+		setup    func(t *testing.T, gitDir string)
+		expected string
+	}{
+		{
+			name:     "no operation",
+			setup:    func(t *testing.T, gitDir string) {},
+			expected: "",
+		},
+		{
+			name: "interactive rebase with progress",
+			setup: func(t *testing.T, gitDir string) {
+				dir := filepath.Join(gitDir, "rebase-merge")
+				require.NoError(t, os.MkdirAll(dir, 0755))
+				require.NoError(t, os.WriteFile(filepath.Join(dir, "msgnum"), []byte("3\n"), 0644))
+				require.NoError(t, os.WriteFile(filepath.Join(dir, "end"), []byte("7\n"), 0644))
+			},
+			expected: "REBASING 3/7",
+		},
+		{
+			name: "git am",
+			setup: func(t *testing.T, gitDir string) {
+				dir := filepath.Join(gitDir, "rebase-apply")
+				require.NoError(t, os.MkdirAll(dir, 0755))
+				require.NoError(t, os.WriteFile(filepath.Join(dir, "applying"), nil, 0644))
+				require.NoError(t, os.WriteFile(filepath.Join(dir, "next"), []byte("2"), 0644))
+				require.NoError(t, os.WriteFile(filepath.Join(dir, "last"), []byte("5"), 0644))
+			},
+			expected: "AM 2/5",
+		},
+		{
+			name: "merge",
+			setup: func(t *testing.T, gitDir string) {
+				require.NoError(t, os.WriteFile(filepath.Join(gitDir, "MERGE_HEAD"), []byte("abc\n"), 0644))
+			},
+			expected: "MERGING",
+		},
+		{
+			name: "cherry-pick",
+			setup: func(t *testing.T, gitDir string) {
+				require.NoError(t, os.WriteFile(filepath.Join(gitDir, "CHERRY_PICK_HEAD"), []byte("abc\n"), 0644))
+			},
+			expected: "CHERRY-PICKING",
+		},
+		{
+			name: "bisect",
+			setup: func(t *testing.T, gitDir string) {
+				require.NoError(t, os.WriteFile(filepath.Join(gitDir, "BISECT_LOG"), []byte("# bisect\n"), 0644))
+			},
+			expected: "BISECTING",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			worktree := t.TempDir()
+			gitDir := filepath.Join(worktree, ".git")
+			require.NoError(t, os.Mkdir(gitDir, 0755))
+			tt.setup(t, gitDir)
+
+			state, err := DetectOperationState(worktree)
+			require.NoError(t, err)
+			if tt.expected == "" {
+				assert.Nil(t, state)
+			} else {
+				require.NotNil(t, state)
+				assert.Equal(t, tt.expected, state.String())
+			}
+		})
+	}
+}
+
+func TestDetectOperationState_LinkedWorktree(t *testing.T) {
+	root := t.TempDir()
+	gitDir := filepath.Join(root, "main", ".git", "worktrees", "feature")
+	require.NoError(t, os.MkdirAll(gitDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(gitDir, "REVERT_HEAD"), []byte("abc\n"), 0644))
+
+	worktree := filepath.Join(root, "feature")
+	require.NoError(t, os.Mkdir(worktree, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(worktree, ".git"), []byte("gitdir: "+gitDir+"\n"), 0644))
+
+	state, err := DetectOperationState(worktree)
+	require.NoError(t, err)
+	require.NotNil(t, state)
+	assert.Equal(t, OperationRevert, state.Kind)
+}
@@ -0,0 +1,101 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/awhite/wtree/pkg/types"
+)
+
+// Submodule is a submodule declared in a worktree's .gitmodules
+type Submodule struct {
+	Name string
+	Path string // Relative to the worktree root
+}
+
+// Submodules returns the submodules declared in the .gitmodules of the
+// worktree at worktreePath; none when it has no .gitmodules
+func Submodules(worktreePath string) ([]Submodule, error) {
+	file := filepath.Join(worktreePath, ".gitmodules")
+	if _, err := os.Stat(file); os.IsNotExist(err) {
+		return nil, nil
+	}
+	cmd := exec.Command("git", "config", "--file", file, "--get-regexp", `^submodule\..*\.path$`)
+	output, err := cmd.Output()
+	if err != nil {
+		// git config exits 1 when nothing matches
+		if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 1 {
+			return nil, nil
+		}
+		return nil, types.NewGitError("submodules", fmt.Sprintf("failed to read %s", file), err)
+	}
+	return parseSubmodules(string(output)), nil
+}
+
+// parseSubmodules parses 'submodule.<name>.path <path>' lines
+func parseSubmodules(output string) []Submodule {
+	var submodules []Submodule
+	for _, line := range strings.Split(output, "\n") {
+		key, path, ok := strings.Cut(strings.TrimSpace(line), " ")
+		if !ok {
+			continue
+		}
+		name := strings.TrimSuffix(strings.TrimPrefix(key, "submodule."), ".path")
+		submodules = append(submodules, Submodule{Name: name, Path: path})
+	}
+	return submodules
+}
+
+// SubmoduleCommit returns the commit the worktree at worktreePath pins the
+// submodule at path to
+func SubmoduleCommit(worktreePath, path string) (string, error) {
+	cmd := exec.Command("git", "rev-parse", "HEAD:"+path)
+	cmd.Dir = worktreePath
+	output, err := cmd.Output()
+	if err != nil {
+		return "", types.NewGitError("submodules",
+			fmt.Sprintf("failed to find the commit of submodule '%s' in %s", path, worktreePath), err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// AddLinkedWorktree adds a worktree of the repository checked out at
+// repoDir, such as a submodule's, at path on branch. A branch the repository
+// doesn't have is created from from.
+func AddLinkedWorktree(repoDir, path, branch, from string) error {
+	args := []string{"worktree", "add", path, branch}
+	verify := exec.Command("git", "rev-parse", "--verify", "--quiet", "refs/heads/"+branch)
+	verify.Dir = repoDir
+	if verify.Run() != nil {
+		args = []string{"worktree", "add", "-b", branch, path, from}
+	}
+
+	cmd := exec.Command("git", args...)
+	cmd.Dir = repoDir
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return types.NewGitError("create-worktree",
+			fmt.Sprintf("failed to create worktree at '%s' for branch '%s': %s", path, branch, strings.TrimSpace(string(output))), err)
+	}
+	return nil
+}
+
+// RemoveLinkedWorktree removes a worktree of the repository checked out at
+// repoDir
+func RemoveLinkedWorktree(repoDir, path string, force bool) error {
+	args := []string{"worktree", "remove"}
+	if force {
+		args = append(args, "--force")
+	}
+	args = append(args, path)
+
+	cmd := exec.Command("git", args...)
+	cmd.Dir = repoDir
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return types.NewGitError("remove-worktree",
+			fmt.Sprintf("failed to remove worktree at '%s': %s", path, strings.TrimSpace(string(output))), err)
+	}
+	return nil
+}
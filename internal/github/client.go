@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"net/url"
 	"os/exec"
 	"path/filepath"
 	"regexp"
@@ -52,6 +53,15 @@ type PRInfo struct {
 	Repository string    `json:"repository"`
 }
 
+// IssueInfo represents information about a GitHub issue
+type IssueInfo struct {
+	Number int      `json:"number"`
+	Title  string   `json:"title"`
+	Labels []string `json:"labels"`
+	State  string   `json:"state"`
+	URL    string   `json:"url"`
+}
+
 // validateCLICommand validates the GitHub CLI command for security
 func validateCLICommand(cliCommand string) error {
 	// Empty command defaults to "gh", which is safe
@@ -296,6 +306,68 @@ func (c *Client) CheckoutPR(prNumber int) (string, error) {
 	return prInfo.HeadRef, nil
 }
 
+// GetIssue fetches the title, labels and state of an issue
+func (c *Client) GetIssue(number int) (*IssueInfo, error) {
+	if number <= 0 {
+		return nil, types.NewValidationError("issue-number", "issue number must be positive", nil)
+	}
+
+	cmd := exec.Command(c.cliCommand, "issue", "view", strconv.Itoa(number), "--json", "number,title,labels,state,url")
+
+	output, err := cmd.Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			stderr := string(exitErr.Stderr)
+			if strings.Contains(stderr, "Could not resolve") || strings.Contains(stderr, "not found") {
+				return nil, types.NewValidationError("issue-not-found",
+					fmt.Sprintf("issue #%d not found in this repository", number), nil)
+			}
+		}
+		return nil, types.NewGitError("github-issue-fetch",
+			fmt.Sprintf("failed to fetch issue #%d", number), err)
+	}
+
+	return parseIssue(output)
+}
+
+// parseIssue decodes the JSON printed by gh issue view
+func parseIssue(output []byte) (*IssueInfo, error) {
+	var issueData struct {
+		Number int    `json:"number"`
+		Title  string `json:"title"`
+		Labels []struct {
+			Name string `json:"name"`
+		} `json:"labels"`
+		State string `json:"state"`
+		URL   string `json:"url"`
+	}
+
+	if err := json.Unmarshal(output, &issueData); err != nil {
+		return nil, types.NewConfigError("github-json-parse", "failed to parse GitHub response", err)
+	}
+
+	issue := &IssueInfo{
+		Number: issueData.Number,
+		Title:  issueData.Title,
+		State:  strings.ToLower(issueData.State),
+		URL:    issueData.URL,
+	}
+	for _, label := range issueData.Labels {
+		issue.Labels = append(issue.Labels, label.Name)
+	}
+	return issue, nil
+}
+
+// CommentOnIssue posts a comment on an issue
+func (c *Client) CommentOnIssue(number int, body string) error {
+	cmd := exec.Command(c.cliCommand, "issue", "comment", strconv.Itoa(number), "--body", body)
+	if err := cmd.Run(); err != nil {
+		return types.NewGitError("github-issue-comment",
+			fmt.Sprintf("failed to comment on issue #%d", number), err)
+	}
+	return nil
+}
+
 // getRepositoryName gets the current repository name from GitHub
 func (c *Client) getRepositoryName() (string, error) {
 	cmd := exec.Command(c.cliCommand, "repo", "view", "--json", "name")
@@ -330,3 +402,52 @@ func (c *Client) ValidatePRState(prInfo *PRInfo) error {
 
 	return nil
 }
+
+// BranchStatus describes what GitHub knows about a branch that makes it
+// unsafe to delete
+type BranchStatus struct {
+	Protected bool // Branch protection is enabled
+	OpenPR    int  // Number of an open PR from the branch, 0 if none
+}
+
+// GetBranchStatus looks up branch protection and open PRs for a branch. A
+// branch that doesn't exist on GitHub is neither protected nor in review.
+func (c *Client) GetBranchStatus(branch string) (*BranchStatus, error) {
+	status := &BranchStatus{}
+
+	cmd := exec.Command(c.cliCommand, "api", "repos/{owner}/{repo}/branches/"+url.PathEscape(branch), "--jq", ".protected")
+	output, err := cmd.Output()
+	if err != nil {
+		exitErr, ok := err.(*exec.ExitError)
+		if !ok || !strings.Contains(string(exitErr.Stderr), "HTTP 404") {
+			return nil, types.NewGitError("github-branch",
+				fmt.Sprintf("failed to check protection of branch '%s'", branch), err)
+		}
+	}
+	status.Protected = strings.TrimSpace(string(output)) == "true"
+
+	cmd = exec.Command(c.cliCommand, "pr", "list", "--head", branch, "--state", "open", "--json", "number", "--limit", "1")
+	if output, err = cmd.Output(); err != nil {
+		return nil, types.NewGitError("github-pr-list",
+			fmt.Sprintf("failed to list open PRs for branch '%s'", branch), err)
+	}
+	if status.OpenPR, err = parseOpenPR(output); err != nil {
+		return nil, err
+	}
+
+	return status, nil
+}
+
+// parseOpenPR reads the PR number from gh pr list --json number, 0 if none
+func parseOpenPR(output []byte) (int, error) {
+	var prs []struct {
+		Number int `json:"number"`
+	}
+	if err := json.Unmarshal(output, &prs); err != nil {
+		return 0, types.NewConfigError("github-json-parse", "failed to parse GitHub response", err)
+	}
+	if len(prs) == 0 {
+		return 0, nil
+	}
+	return prs[0].Number, nil
+}
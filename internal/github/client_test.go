@@ -0,0 +1,38 @@
+package github
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseIssue(t *testing.T) {
+	output := `{"number":456,"title":"Login times out","labels":[{"name":"bug"},{"name":"auth"}],"state":"OPEN","url":"https://github.com/o/r/issues/456"}`
+
+	issue, err := parseIssue([]byte(output))
+	require.NoError(t, err)
+	assert.Equal(t, &IssueInfo{
+		Number: 456,
+		Title:  "Login times out",
+		Labels: []string{"bug", "auth"},
+		State:  "open",
+		URL:    "https://github.com/o/r/issues/456",
+	}, issue)
+
+	_, err = parseIssue([]byte("not json"))
+	assert.Error(t, err)
+}
+
+func TestParseOpenPR(t *testing.T) {
+	number, err := parseOpenPR([]byte(`[{"number":42}]`))
+	require.NoError(t, err)
+	assert.Equal(t, 42, number)
+
+	number, err = parseOpenPR([]byte(`[]`))
+	require.NoError(t, err)
+	assert.Equal(t, 0, number)
+
+	_, err = parseOpenPR([]byte("not json"))
+	assert.Error(t, err)
+}
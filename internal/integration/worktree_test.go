@@ -0,0 +1,555 @@
+package integration
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/awhite/wtree/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMain(m *testing.M) { testutil.Main(m) }
+
+func TestCreate_NewBranch(t *testing.T) {
+	repo := testutil.NewRepo(t)
+
+	repo.MustRun("-y", "create", "-b", "feature/login")
+
+	path := repo.Sibling("repo-feature-login")
+	assert.Equal(t, []string{repo.Dir, path}, repo.Worktrees())
+	assert.True(t, repo.HasBranch("feature/login"))
+	assert.Equal(t, "feature/login", repo.GitIn(path, "branch", "--show-current"))
+}
+
+func TestCreate_MissingBranchWithoutFlagFails(t *testing.T) {
+	repo := testutil.NewRepo(t)
+
+	result := repo.Run("-y", "create", "missing")
+
+	assert.NotZero(t, result.ExitCode)
+	assert.Contains(t, result.Stderr, "does not exist")
+	assert.Equal(t, []string{repo.Dir}, repo.Worktrees())
+	assert.False(t, repo.HasBranch("missing"))
+}
+
+func TestCreate_CopiesFilesAndRunsHooks(t *testing.T) {
+	repo := testutil.NewRepo(t)
+	repo.Commit("Add wtree config", map[string]string{
+		".gitignore": ".env\n",
+		".wtreerc": `version: "1.0"
+copy_files:
+  - .env
+hooks:
+  post_create:
+    - echo "{{.Branch}}" > hook-ran
+`,
+	})
+	repo.WriteFile(".env", "SECRET=1\n")
+
+	repo.MustRun("-y", "create", "-b", "setup")
+
+	path := repo.Sibling("repo-setup")
+	assert.Equal(t, "SECRET=1\n", readFile(t, filepath.Join(path, ".env")))
+	assert.Equal(t, "setup\n", readFile(t, filepath.Join(path, "hook-ran")))
+}
+
+func TestDelete_RemovesWorktreeAndKeepsBranch(t *testing.T) {
+	repo := testutil.NewRepo(t)
+	repo.MustRun("-y", "create", "-b", "feature")
+	path := repo.Sibling("repo-feature")
+
+	repo.MustRun("-y", "delete", "feature")
+
+	assert.Equal(t, []string{repo.Dir}, repo.Worktrees())
+	assert.NoDirExists(t, path)
+	assert.True(t, repo.HasBranch("feature"))
+}
+
+func TestDelete_UnknownWorktreeFails(t *testing.T) {
+	repo := testutil.NewRepo(t)
+
+	result := repo.Run("-y", "delete", "nope")
+
+	assert.NotZero(t, result.ExitCode)
+	assert.Contains(t, result.Stderr, "worktree not found")
+}
+
+func TestCleanup_RemovesWorktreeWhoseDirectoryIsGone(t *testing.T) {
+	repo := testutil.NewRepo(t)
+	repo.MustRun("-y", "create", "-b", "gone")
+	repo.MustRun("-y", "create", "-b", "kept")
+	require.NoError(t, os.RemoveAll(repo.Sibling("repo-gone")))
+
+	repo.MustRun("cleanup", "--auto")
+
+	assert.Equal(t, []string{repo.Dir, repo.Sibling("repo-kept")}, repo.Worktrees())
+}
+
+func TestSwitch_PrintsWorktreePath(t *testing.T) {
+	repo := testutil.NewRepo(t)
+	repo.MustRun("-y", "create", "-b", "feature/login")
+
+	result := repo.MustRun("switch", "--dry-run", "feature-login")
+
+	assert.Contains(t, result.Stdout, repo.Sibling("repo-feature-login"))
+}
+
+func TestList_ReportsUpstream(t *testing.T) {
+	repo := testutil.NewRepo(t)
+	repo.AddRemote("origin")
+
+	result := repo.MustRun("list", "--output", "json")
+
+	var worktrees []struct {
+		Path     string `json:"path"`
+		Main     bool   `json:"main"`
+		Upstream string `json:"upstream"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(result.Stdout), &worktrees), result.Output())
+	require.Len(t, worktrees, 1)
+	assert.True(t, worktrees[0].Main)
+	assert.Equal(t, "origin/main", worktrees[0].Upstream)
+}
+
+func TestList_SummaryHonorsDirty(t *testing.T) {
+	repo := testutil.NewRepo(t)
+	repo.MustRun("-y", "create", "-b", "clean")
+	repo.MustRun("-y", "create", "-b", "dirty")
+	require.NoError(t, os.WriteFile(filepath.Join(repo.Sibling("repo-dirty"), "README.md"), []byte("# changed\n"), 0644))
+
+	summary := func(args ...string) map[string]string {
+		result := repo.MustRun(append([]string{"list", "--summary"}, args...)...)
+		counts := make(map[string]string)
+		for _, line := range strings.Split(result.Stdout, "\n") {
+			// Rows look like "┌Worktrees │ 2 ┐"
+			if metric, count, ok := strings.Cut(strings.Trim(line, "┌┐ "), "│"); ok {
+				counts[strings.TrimSpace(metric)] = strings.TrimSpace(count)
+			}
+		}
+		return counts
+	}
+
+	all := summary()
+	assert.Equal(t, "2", all["Worktrees"])
+	assert.Equal(t, "1", all["Clean"])
+	assert.Equal(t, "1", all["Dirty"])
+
+	dirty := summary("--dirty")
+	assert.Equal(t, "1", dirty["Worktrees"])
+	assert.Equal(t, "0", dirty["Clean"])
+	assert.Equal(t, "1", dirty["Dirty"])
+}
+
+func TestCleanup_ScheduleDryRunChangesNothing(t *testing.T) {
+	repo := testutil.NewRepo(t)
+
+	// The job files a real install would write, under every scheduler
+	hash := sha256.Sum256([]byte(repo.Git("rev-parse", "--show-toplevel")))
+	name := fmt.Sprintf("wtree-cleanup-%x", hash[:6])
+	units := filepath.Join(repo.Home, ".config", "systemd", "user")
+	agents := filepath.Join(repo.Home, "Library", "LaunchAgents")
+	files := []string{
+		filepath.Join(units, name+".service"),
+		filepath.Join(units, name+".timer"),
+		filepath.Join(agents, "com.wtree."+strings.TrimPrefix(name, "wtree-")+".plist"),
+	}
+
+	result := repo.MustRun("cleanup", "--install-schedule", "daily", "--dry-run")
+	assert.Contains(t, result.Stdout, "[DRY RUN] Would schedule daily cleanup")
+	assert.NoDirExists(t, units, "a dry run writes no systemd units")
+	assert.NoDirExists(t, agents, "a dry run writes no launchd agent")
+
+	for _, file := range files {
+		require.NoError(t, os.MkdirAll(filepath.Dir(file), 0755))
+		require.NoError(t, os.WriteFile(file, []byte("installed\n"), 0644))
+	}
+	result = repo.MustRun("cleanup", "--uninstall-schedule", "--dry-run")
+	assert.Contains(t, result.Stdout, "[DRY RUN]")
+	for _, file := range files {
+		assert.FileExists(t, file, "a dry run removes nothing")
+	}
+}
+
+func TestCleanup_DryRunRecordsNoState(t *testing.T) {
+	repo := testutil.NewRepo(t)
+	state := filepath.Join(repo.Home, ".local", "share", "wtree", "state.json")
+
+	repo.MustRun("cleanup", "-n")
+	assert.NoFileExists(t, state, "cleanup's own --dry-run changes nothing")
+	repo.MustRun("--dry-run", "create", "-b", "feature")
+	assert.NoFileExists(t, state)
+
+	repo.MustRun("cleanup", "--auto")
+	assert.FileExists(t, state)
+}
+
+func TestStatus_AutoFetches(t *testing.T) {
+	repo := testutil.NewRepo(t)
+	repo.AddRemote("origin")
+	configDir := filepath.Join(repo.Home, ".config", "wtree")
+	require.NoError(t, os.MkdirAll(configDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(configDir, "config.yaml"), []byte("fetch:\n  auto: true\n"), 0644))
+
+	result := repo.MustRun("status")
+
+	assert.FileExists(t, filepath.Join(repo.Dir, ".git", "FETCH_HEAD"))
+	assert.FileExists(t, filepath.Join(repo.Dir, ".git", "wtree", "last-fetch"))
+	assert.NotContains(t, result.Output(), "never fetched")
+}
+
+func TestRebase_ReplaysBranchAndRunsHooks(t *testing.T) {
+	repo := testutil.NewRepo(t)
+	repo.Commit("Add wtree config", map[string]string{
+		".wtreerc": `version: "1.0"
+hooks:
+  pre_rebase:
+    - echo "{{.Branch}} onto {{.TargetBranch}}" > ../pre-rebase
+  post_rebase:
+    - git log --format=%s -n 3 > ../post-rebase
+`,
+	})
+	repo.MustRun("-y", "create", "-b", "feature")
+	path := repo.Sibling("repo-feature")
+	repo.GitIn(path, "commit", "--quiet", "--allow-empty", "-m", "Feature work")
+	repo.Commit("Main moved on", nil)
+
+	result := repo.RunIn(path, "rebase", "main")
+	require.Zero(t, result.ExitCode, result.Output())
+
+	assert.Equal(t, "feature onto main\n", readFile(t, repo.Sibling("pre-rebase")))
+	assert.Equal(t, "Feature work\nMain moved on\nAdd wtree config\n", readFile(t, repo.Sibling("post-rebase")))
+}
+
+func TestRebase_ConflictThenAbort(t *testing.T) {
+	repo := testutil.NewRepo(t)
+	repo.MustRun("-y", "create", "-b", "feature")
+	path := repo.Sibling("repo-feature")
+	require.NoError(t, os.WriteFile(filepath.Join(path, "README.md"), []byte("feature\n"), 0644))
+	repo.GitIn(path, "commit", "--quiet", "-am", "Feature readme")
+	repo.Commit("Main readme", map[string]string{"README.md": "main\n"})
+
+	result := repo.RunIn(path, "rebase", "main")
+	assert.NotZero(t, result.ExitCode)
+	assert.Contains(t, result.Stderr, "wtree rebase --continue")
+
+	again := repo.RunIn(path, "rebase", "main")
+	assert.NotZero(t, again.ExitCode)
+	assert.Contains(t, again.Stderr, "already in progress")
+
+	result = repo.RunIn(path, "rebase", "--abort")
+	require.Zero(t, result.ExitCode, result.Output())
+	assert.Equal(t, "Feature readme", repo.GitIn(path, "log", "-1", "--format=%s"))
+	assert.Equal(t, "feature\n", readFile(t, filepath.Join(path, "README.md")))
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestSwitch_AutostashRestoresOnReturn(t *testing.T) {
+	repo := testutil.NewRepo(t)
+	repo.MustRun("-y", "create", "-b", "feature")
+	path := repo.Sibling("repo-feature")
+	require.NoError(t, os.WriteFile(filepath.Join(path, "README.md"), []byte("edited\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(path, "notes.txt"), []byte("wip\n"), 0644))
+
+	result := repo.RunIn(path, "switch", "--autostash", "main")
+	require.Zero(t, result.ExitCode, result.Output())
+	assert.Empty(t, repo.GitIn(path, "status", "--porcelain"))
+	assert.Contains(t, repo.Git("stash", "list"), "wtree-auto/feature")
+
+	repo.MustRun("switch", "feature")
+	assert.Equal(t, "edited\n", readFile(t, filepath.Join(path, "README.md")))
+	assert.Equal(t, "wip\n", readFile(t, filepath.Join(path, "notes.txt")))
+	assert.Empty(t, repo.Git("stash", "list"))
+}
+
+func TestDelete_AutostashRestoresOnCreate(t *testing.T) {
+	repo := testutil.NewRepo(t)
+	repo.MustRun("-y", "create", "-b", "feature")
+	path := repo.Sibling("repo-feature")
+	require.NoError(t, os.WriteFile(filepath.Join(path, "notes.txt"), []byte("wip\n"), 0644))
+
+	result := repo.Run("-y", "delete", "feature")
+	assert.NotZero(t, result.ExitCode)
+	assert.Contains(t, result.Stderr, "--autostash")
+	assert.DirExists(t, path)
+
+	repo.MustRun("-y", "delete", "--autostash", "feature")
+	assert.NoDirExists(t, path)
+
+	repo.MustRun("-y", "create", "feature")
+	assert.Equal(t, "wip\n", readFile(t, filepath.Join(path, "notes.txt")))
+	assert.Empty(t, repo.Git("stash", "list"))
+}
+
+func TestDelete_AutostashFromConfig(t *testing.T) {
+	repo := testutil.NewRepo(t)
+	configDir := filepath.Join(repo.Home, ".config", "wtree")
+	require.NoError(t, os.MkdirAll(configDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(configDir, "config.yaml"), []byte("autostash: true\n"), 0644))
+	repo.MustRun("-y", "create", "-b", "feature")
+	require.NoError(t, os.WriteFile(filepath.Join(repo.Sibling("repo-feature"), "notes.txt"), []byte("wip\n"), 0644))
+
+	repo.MustRun("-y", "delete", "feature")
+
+	assert.Contains(t, repo.Git("stash", "list"), "wtree-auto/feature")
+}
+
+func TestCreate_HookAllowedByGlobalPolicy(t *testing.T) {
+	repo := testutil.NewRepo(t)
+	repo.Commit("Add wtree config", map[string]string{
+		".wtreerc": `version: "1.0"
+hooks:
+  post_create:
+    - rm -rf ./node_modules && echo installed > installed
+`,
+	})
+
+	result := repo.MustRun("-y", "create", "-b", "blocked")
+	assert.Contains(t, result.Stdout, "Rejected")
+	assert.NoFileExists(t, filepath.Join(repo.Sibling("repo-blocked"), "installed"))
+
+	configDir := filepath.Join(repo.Home, ".config", "wtree")
+	require.NoError(t, os.MkdirAll(configDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(configDir, "config.yaml"), []byte(`hooks:
+  security:
+    allow:
+      - rm -rf ./node_modules && echo installed > installed
+`), 0644))
+
+	repo.MustRun("-y", "create", "-b", "allowed")
+	assert.Equal(t, "installed\n", readFile(t, filepath.Join(repo.Sibling("repo-allowed"), "installed")))
+}
+
+func TestSwitch_RunsSwitchHooks(t *testing.T) {
+	repo := testutil.NewRepo(t)
+	repo.Commit("Add wtree config", map[string]string{
+		".wtreerc": `version: "1.0"
+hooks:
+  pre_switch:
+    - echo "{{.Branch}} to {{.TargetBranch}}" > ../pre-switch
+  post_switch:
+    - echo "$WTREE_SWITCH_FROM" > ../post-switch; pwd >> ../post-switch
+`,
+	})
+	repo.MustRun("-y", "create", "-b", "feature")
+
+	repo.MustRun("switch", "feature")
+
+	assert.Equal(t, "main to feature\n", readFile(t, repo.Sibling("pre-switch")))
+	assert.Equal(t, repo.Dir+"\n"+repo.Sibling("repo-feature")+"\n", readFile(t, repo.Sibling("post-switch")))
+}
+
+func TestSwitch_OnlyCdCommandReachesStdout(t *testing.T) {
+	repo := testutil.NewRepo(t)
+	repo.Commit("Add wtree config", map[string]string{
+		".wtreerc": `version: "1.0"
+hooks:
+  pre_switch:
+    - echo leaving
+  post_switch:
+    - echo arrived
+`,
+	})
+	repo.MustRun("-y", "create", "-b", "feature")
+	path := repo.Sibling("repo-feature")
+	require.NoError(t, os.WriteFile(filepath.Join(path, "notes.txt"), []byte("wip\n"), 0644))
+	require.Zero(t, repo.RunIn(path, "switch", "--autostash", "main").ExitCode)
+
+	// eval "$(wtree switch feature)" must only run the cd
+	result := repo.MustRun("switch", "feature")
+
+	lines := strings.Split(strings.TrimSpace(result.Stdout), "\n")
+	require.Len(t, lines, 1, result.Output())
+	assert.True(t, strings.HasPrefix(lines[0], "cd "), lines[0])
+	assert.Contains(t, lines[0], path)
+	assert.Contains(t, result.Stderr, "leaving")
+	assert.Contains(t, result.Stderr, "arrived")
+	assert.Contains(t, result.Stderr, "Restored the changes stashed as wtree-auto/feature")
+}
+
+func TestCleanup_RunsPruneHookWithRemovedWorktrees(t *testing.T) {
+	repo := testutil.NewRepo(t)
+	repo.Commit("Add wtree config", map[string]string{
+		".wtreerc": `version: "1.0"
+hooks:
+  on_prune:
+    - echo "$WTREE_PRUNED_BRANCHES" > ../pruned
+`,
+	})
+	repo.MustRun("-y", "create", "-b", "gone")
+	repo.MustRun("-y", "create", "-b", "kept")
+	require.NoError(t, os.RemoveAll(repo.Sibling("repo-gone")))
+
+	repo.MustRun("cleanup", "--auto")
+
+	assert.Equal(t, "gone\n", readFile(t, repo.Sibling("pruned")))
+}
+
+func TestCreateMany_ConcurrentSetupKeepsManifestsApart(t *testing.T) {
+	repo := testutil.NewRepo(t)
+	repo.Commit("Add wtree config", map[string]string{
+		".gitignore": "*.local\n",
+		".wtreerc": `version: "1.0"
+copy_files:
+  - "*.local"
+`,
+	})
+	// Enough files that the workers' setups overlap
+	const files = 200
+	for i := 0; i < files; i++ {
+		repo.WriteFile(fmt.Sprintf("%03d.local", i), "local\n")
+	}
+	configDir := filepath.Join(repo.Home, ".config", "wtree")
+	require.NoError(t, os.MkdirAll(configDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(configDir, "config.yaml"),
+		[]byte("performance:\n  max_concurrent_operations: 6\n"), 0644))
+
+	branches := []string{"one", "two", "three", "four", "five", "six"}
+	result := repo.Run(append([]string{"-y", "create-many", "-b"}, branches...)...)
+	require.Zero(t, result.ExitCode, result.Output())
+	assert.NotContains(t, result.Stderr, "DATA RACE")
+
+	for _, branch := range branches {
+		path := repo.Sibling("repo-" + branch)
+		gitDir := repo.GitIn(path, "rev-parse", "--absolute-git-dir")
+		var manifest struct {
+			Worktree   string `json:"worktree"`
+			Operations []struct {
+				Destination string `json:"destination"`
+			} `json:"operations"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(readFile(t, filepath.Join(gitDir, "wtree", "setup-manifest.json"))), &manifest))
+		assert.Equal(t, path, manifest.Worktree)
+		require.Len(t, manifest.Operations, files, branch)
+		for _, operation := range manifest.Operations {
+			assert.Equal(t, path, filepath.Dir(operation.Destination), branch)
+		}
+	}
+}
+
+func TestSplit_MovesChangesFromTheCurrentWorktree(t *testing.T) {
+	repo := testutil.NewRepo(t)
+	repo.MustRun("-y", "create", "-b", "feature")
+	source := repo.Sibling("repo-feature")
+	require.NoError(t, os.WriteFile(filepath.Join(source, "feature.txt"), []byte("feature\n"), 0644))
+	repo.GitIn(source, "add", "feature.txt")
+	repo.GitIn(source, "commit", "--quiet", "-m", "Feature work")
+	featureHead := repo.GitIn(source, "rev-parse", "HEAD")
+
+	require.NoError(t, os.WriteFile(filepath.Join(source, "README.md"), []byte("# changed\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(source, "other.txt"), []byte("other\n"), 0644))
+
+	result := repo.RunIn(source, "-y", "split", "moved", "README.md")
+	require.Zero(t, result.ExitCode, result.Output())
+
+	// Named after the worktree it was split from
+	target := repo.Sibling("repo-feature-moved")
+	// Started from the feature worktree's commit, not main's
+	assert.Equal(t, featureHead, repo.GitIn(target, "rev-parse", "HEAD"))
+	assert.Equal(t, "# changed\n", readFile(t, filepath.Join(target, "README.md")))
+	assert.NoFileExists(t, filepath.Join(target, "other.txt"))
+	assert.Equal(t, "# repo\n", readFile(t, filepath.Join(source, "README.md")))
+	assert.Equal(t, "other\n", readFile(t, filepath.Join(source, "other.txt")))
+	assert.Empty(t, repo.Git("stash", "list"))
+}
+
+func TestSplit_FailureRemovesNewWorktreeAndRestoresChanges(t *testing.T) {
+	repo := testutil.NewRepo(t)
+	// The hook edits the file being moved, so the changes can't be applied
+	repo.Commit("Add wtree config", map[string]string{
+		".wtreerc": `version: "1.0"
+hooks:
+  post_create:
+    - echo conflict >> README.md
+`,
+	})
+	repo.WriteFile("README.md", "# changed\n")
+
+	result := repo.Run("-y", "split", "moved")
+	require.NotZero(t, result.ExitCode, result.Output())
+
+	assert.Equal(t, []string{repo.Dir}, repo.Worktrees())
+	assert.NoDirExists(t, repo.Sibling("repo-moved"))
+	assert.False(t, repo.HasBranch("moved"))
+	assert.Equal(t, "# changed\n", readFile(t, filepath.Join(repo.Dir, "README.md")))
+	assert.Empty(t, repo.Git("stash", "list"))
+}
+
+func TestCreate_NoteKeepsExistingDescription(t *testing.T) {
+	repo := testutil.NewRepo(t)
+	repo.MustRun("-y", "create", "-b", "feature", "--note", "cache layer spike")
+	assert.Equal(t, "cache layer spike", repo.Git("config", "branch.feature.description"))
+	repo.MustRun("-y", "delete", "feature")
+
+	result := repo.Run("-y", "create", "feature", "--note", "something else")
+	require.NotZero(t, result.ExitCode, result.Output())
+	assert.Contains(t, result.Stderr, "--replace-note")
+	assert.Equal(t, "cache layer spike", repo.Git("config", "branch.feature.description"))
+	assert.NoDirExists(t, repo.Sibling("repo-feature"))
+
+	repo.MustRun("-y", "create", "feature", "--note", "something else", "--replace-note")
+	assert.Equal(t, "something else", repo.Git("config", "branch.feature.description"))
+}
+
+func TestCreate_ReuseAppliesNoteAndTags(t *testing.T) {
+	repo := testutil.NewRepo(t)
+	repo.MustRun("-y", "create", "-b", "feat", "--note", "first", "--tag", "spike")
+
+	result := repo.Run("-y", "create", "feat", "--note", "second")
+	require.NotZero(t, result.ExitCode, "an existing worktree keeps its note without --replace-note")
+	assert.Equal(t, "first", repo.Git("config", "branch.feat.description"))
+
+	repo.MustRun("-y", "create", "feat", "--note", "second", "--replace-note", "--tag", "review")
+	assert.Equal(t, "second", repo.Git("config", "branch.feat.description"))
+
+	var worktrees []struct {
+		Branch string   `json:"branch"`
+		Tags   []string `json:"tags"`
+	}
+	result = repo.MustRun("list", "--output", "json")
+	require.NoError(t, json.Unmarshal([]byte(result.Stdout), &worktrees), result.Output())
+	require.Len(t, worktrees, 2)
+	assert.Equal(t, []string{"review", "spike"}, worktrees[1].Tags, "tags are added to the ones it has")
+}
+
+func TestCreate_ReusesExistingWorktree(t *testing.T) {
+	repo := testutil.NewRepo(t)
+	repo.Commit("Add wtree config", map[string]string{
+		".gitignore": ".env\n",
+		".wtreerc": `version: "1.0"
+copy_files:
+  - .env
+hooks:
+  post_create:
+    - echo ran >> hook-runs
+`,
+	})
+	repo.WriteFile(".env", "SECRET=1\n")
+	repo.MustRun("-y", "create", "-b", "feature")
+	path := repo.Sibling("repo-feature")
+
+	// Again, without --refresh: nothing is set up twice
+	repo.WriteFile(".env", "SECRET=2\n")
+	result := repo.MustRun("-y", "create", "-b", "feature")
+	assert.Contains(t, result.Stdout, "already exists")
+	assert.Equal(t, []string{repo.Dir, path}, repo.Worktrees())
+	assert.Equal(t, "ran\n", readFile(t, filepath.Join(path, "hook-runs")))
+	assert.Equal(t, "SECRET=1\n", readFile(t, filepath.Join(path, ".env")))
+
+	// --refresh reruns the setup in place
+	repo.MustRun("-y", "create", "feature", "--refresh")
+	assert.Equal(t, []string{repo.Dir, path}, repo.Worktrees())
+	assert.Equal(t, "ran\nran\n", readFile(t, filepath.Join(path, "hook-runs")))
+	assert.Equal(t, "SECRET=2\n", readFile(t, filepath.Join(path, ".env")))
+}
@@ -0,0 +1,96 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/awhite/wtree/pkg/types"
+)
+
+// defaultMessages are sent for events whose notification sets no message
+var defaultMessages = map[string]string{
+	types.NotifyWorktreeCreated: "{user} created worktree {branch} in {repo} on {host}",
+	types.NotifyPRCreated:       "{user} created a worktree for PR #{pr_number} ({pr_title}) in {repo} on {host}",
+	types.NotifyCleanup:         "{user} cleaned up {count} worktrees in {repo} on {host}",
+}
+
+// Timeout bounds each delivery so a slow endpoint never stalls a command
+const Timeout = 5 * time.Second
+
+// Event is something that happened, with the values its message template
+// can reference
+type Event struct {
+	Name string
+	Vars map[string]string
+}
+
+// Wants reports whether target should receive event
+func Wants(target types.NotificationConfig, event Event) bool {
+	return len(target.Events) == 0 || slices.Contains(target.Events, event.Name)
+}
+
+// Message expands the target's message template, or the event's default
+func Message(target types.NotificationConfig, event Event) string {
+	template := target.Message
+	if template == "" {
+		template = defaultMessages[event.Name]
+	}
+	expanded := strings.ReplaceAll(template, "{event}", event.Name)
+	for key, value := range event.Vars {
+		expanded = strings.ReplaceAll(expanded, "{"+key+"}", value)
+	}
+	return expanded
+}
+
+// Payload builds the request body for target in its format
+func Payload(target types.NotificationConfig, event Event) ([]byte, error) {
+	message := Message(target, event)
+	switch target.Format {
+	case types.NotifyFormatSlack:
+		return json.Marshal(map[string]string{"text": message})
+	case types.NotifyFormatDiscord:
+		return json.Marshal(map[string]string{"content": message})
+	default:
+		body := map[string]string{"event": event.Name, "message": message}
+		for key, value := range event.Vars {
+			body[key] = value
+		}
+		return json.Marshal(body)
+	}
+}
+
+// Send posts event to target, failing on transport errors and non-2xx replies
+func Send(client *http.Client, target types.NotificationConfig, event Event) error {
+	payload, err := Payload(target, event)
+	if err != nil {
+		return fmt.Errorf("failed to encode notification: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), Timeout)
+	defer cancel()
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, target.URL, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("invalid notification url: %w", err)
+	}
+	request.Header.Set("Content-Type", "application/json")
+
+	response, err := client.Do(request)
+	if err != nil {
+		return fmt.Errorf("notification failed: %w", err)
+	}
+	defer response.Body.Close()
+	_, _ = io.Copy(io.Discard, response.Body)
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return fmt.Errorf("notification rejected: %s", response.Status)
+	}
+	return nil
+}
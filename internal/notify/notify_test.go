@@ -0,0 +1,84 @@
+package notify
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/awhite/wtree/pkg/types"
+)
+
+func cleanupEvent() Event {
+	return Event{Name: types.NotifyCleanup, Vars: map[string]string{
+		"user": "ana", "repo": "shop", "host": "staging-1", "count": "3",
+	}}
+}
+
+func TestMessage(t *testing.T) {
+	target := types.NotificationConfig{}
+	assert.Equal(t, "ana cleaned up 3 worktrees in shop on staging-1", Message(target, cleanupEvent()))
+
+	target.Message = "{event}: {count} gone from {host}"
+	assert.Equal(t, "cleanup: 3 gone from staging-1", Message(target, cleanupEvent()))
+}
+
+func TestWants(t *testing.T) {
+	assert.True(t, Wants(types.NotificationConfig{}, cleanupEvent()))
+	assert.True(t, Wants(types.NotificationConfig{Events: []string{types.NotifyCleanup}}, cleanupEvent()))
+	assert.False(t, Wants(types.NotificationConfig{Events: []string{types.NotifyPRCreated}}, cleanupEvent()))
+}
+
+func TestPayload(t *testing.T) {
+	tests := []struct {
+		format string
+		key    string
+	}{
+		{types.NotifyFormatSlack, "text"},
+		{types.NotifyFormatDiscord, "content"},
+		{types.NotifyFormatWebhook, "message"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			payload, err := Payload(types.NotificationConfig{Format: tt.format}, cleanupEvent())
+			require.NoError(t, err)
+
+			var body map[string]string
+			require.NoError(t, json.Unmarshal(payload, &body))
+			assert.Equal(t, "ana cleaned up 3 worktrees in shop on staging-1", body[tt.key])
+		})
+	}
+
+	payload, err := Payload(types.NotificationConfig{}, cleanupEvent())
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"event":"cleanup","message":"ana cleaned up 3 worktrees in shop on staging-1",
+		"user":"ana","repo":"shop","host":"staging-1","count":"3"}`, string(payload))
+}
+
+func TestSend(t *testing.T) {
+	var received []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		received, _ = io.ReadAll(r.Body)
+	}))
+	defer server.Close()
+
+	target := types.NotificationConfig{URL: server.URL, Format: types.NotifyFormatSlack}
+	require.NoError(t, Send(server.Client(), target, cleanupEvent()))
+	assert.JSONEq(t, `{"text":"ana cleaned up 3 worktrees in shop on staging-1"}`, string(received))
+}
+
+func TestSend_Rejected(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	err := Send(server.Client(), types.NotificationConfig{URL: server.URL}, cleanupEvent())
+	assert.ErrorContains(t, err, "403")
+}
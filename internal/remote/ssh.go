@@ -0,0 +1,81 @@
+package remote
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/awhite/wtree/pkg/types"
+)
+
+// hostPattern accepts "host" or "user@host"; anything that could be read as
+// an ssh option or shell syntax is rejected
+var hostPattern = regexp.MustCompile(`^([A-Za-z0-9._-]+@)?[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// Runner executes shell commands on a remote machine over SSH
+type Runner struct {
+	host string
+}
+
+// NewRunner validates the host and returns a runner for it
+func NewRunner(host string) (*Runner, error) {
+	if !hostPattern.MatchString(host) {
+		return nil, types.NewValidationError("remote-host",
+			fmt.Sprintf("invalid SSH host '%s' (expected user@host)", host), nil)
+	}
+	return &Runner{host: host}, nil
+}
+
+// Host returns the SSH destination
+func (r *Runner) Host() string {
+	return r.host
+}
+
+// Run executes a shell command in dir on the remote host with the given
+// environment variables exported, returning combined output
+func (r *Runner) Run(dir string, env map[string]string, command string) ([]byte, error) {
+	cmd := exec.Command("ssh", r.Args(dir, env, command)...)
+	var output bytes.Buffer
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+
+	if err := cmd.Run(); err != nil {
+		return output.Bytes(), fmt.Errorf("ssh %s: %w: %s", r.host, err, strings.TrimSpace(output.String()))
+	}
+	return output.Bytes(), nil
+}
+
+// Args builds the ssh argument list for running command on the remote host
+func (r *Runner) Args(dir string, env map[string]string, command string) []string {
+	return []string{"-o", "BatchMode=yes", r.host, "--", BuildScript(dir, env, command)}
+}
+
+// BuildScript renders the remote shell script: change into dir, export env,
+// and run command through sh so the remote login shell does not matter
+func BuildScript(dir string, env map[string]string, command string) string {
+	var script strings.Builder
+
+	if dir != "" {
+		script.WriteString("cd " + Quote(dir) + " && ")
+	}
+
+	keys := make([]string, 0, len(env))
+	for key := range env {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		script.WriteString("export " + key + "=" + Quote(env[key]) + " && ")
+	}
+
+	script.WriteString("sh -c " + Quote(command))
+	return script.String()
+}
+
+// Quote single-quotes a value for POSIX shells
+func Quote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", "'\"'\"'") + "'"
+}
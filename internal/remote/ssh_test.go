@@ -0,0 +1,41 @@
+package remote
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewRunner(t *testing.T) {
+	valid := []string{"devbox", "user@devbox", "me@build-01.example.com"}
+	for _, host := range valid {
+		_, err := NewRunner(host)
+		assert.NoError(t, err, host)
+	}
+
+	invalid := []string{"", "-oProxyCommand=evil", "user@", "dev box", "user@host;ls"}
+	for _, host := range invalid {
+		_, err := NewRunner(host)
+		assert.Error(t, err, host)
+	}
+}
+
+func TestBuildScript(t *testing.T) {
+	script := BuildScript("/src/my repo", map[string]string{
+		"WTREE_BRANCH": "feature",
+		"WTREE_EVENT":  "post_create",
+	}, "echo 'hi'")
+
+	assert.Equal(t,
+		`cd '/src/my repo' && export WTREE_BRANCH='feature' && export WTREE_EVENT='post_create' && sh -c 'echo '"'"'hi'"'"''`,
+		script)
+}
+
+func TestRunnerArgs(t *testing.T) {
+	runner, err := NewRunner("user@devbox")
+	require.NoError(t, err)
+
+	args := runner.Args("", nil, "git status")
+	assert.Equal(t, []string{"-o", "BatchMode=yes", "user@devbox", "--", "sh -c 'git status'"}, args)
+}
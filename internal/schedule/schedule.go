@@ -0,0 +1,370 @@
+package schedule
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/awhite/wtree/pkg/types"
+)
+
+// Interval represents how often the scheduled cleanup runs
+type Interval string
+
+const (
+	IntervalHourly Interval = "hourly"
+	IntervalDaily  Interval = "daily"
+	IntervalWeekly Interval = "weekly"
+)
+
+// Backend identifies the scheduler used to run cleanup
+type Backend string
+
+const (
+	BackendSystemd Backend = "systemd"
+	BackendLaunchd Backend = "launchd"
+	BackendCron    Backend = "cron"
+)
+
+// Job describes a scheduled cleanup for a single repository
+type Job struct {
+	RepoPath string
+	Binary   string
+	Interval Interval
+}
+
+// Result describes what Install or Uninstall did
+type Result struct {
+	Backend Backend
+	Files   []string // Unit/agent files written or removed
+	Crontab string   // Crontab line for the user to install (cron backend only)
+}
+
+// ParseInterval validates an interval name
+func ParseInterval(value string) (Interval, error) {
+	switch Interval(strings.ToLower(strings.TrimSpace(value))) {
+	case IntervalHourly:
+		return IntervalHourly, nil
+	case IntervalDaily:
+		return IntervalDaily, nil
+	case IntervalWeekly:
+		return IntervalWeekly, nil
+	default:
+		return "", types.NewValidationError("schedule",
+			fmt.Sprintf("invalid schedule '%s' (expected hourly, daily, or weekly)", value), nil)
+	}
+}
+
+// Install registers the job with the platform scheduler. When no supported
+// scheduler is available, the crontab line is returned instead of installed.
+func Install(job Job) (*Result, error) {
+	switch detectBackend() {
+	case BackendLaunchd:
+		return installLaunchd(job)
+	case BackendSystemd:
+		return installSystemd(job)
+	default:
+		return &Result{Backend: BackendCron, Crontab: CrontabLine(job)}, nil
+	}
+}
+
+// Uninstall removes the job for the given repository from the platform scheduler
+func Uninstall(repoPath string) (*Result, error) {
+	switch detectBackend() {
+	case BackendLaunchd:
+		return uninstallLaunchd(repoPath)
+	case BackendSystemd:
+		return uninstallSystemd(repoPath)
+	default:
+		return &Result{Backend: BackendCron, Crontab: jobName(repoPath)}, nil
+	}
+}
+
+// Plan reports what Install would do for the job, without doing it: the
+// files it would write, or the crontab line it would return
+func Plan(job Job) (*Result, error) {
+	switch detectBackend() {
+	case BackendLaunchd:
+		plistPath, err := launchdPlistPath(job.RepoPath)
+		if err != nil {
+			return nil, err
+		}
+		return &Result{Backend: BackendLaunchd, Files: []string{plistPath}}, nil
+	case BackendSystemd:
+		servicePath, timerPath, err := systemdUnitPaths(job.RepoPath)
+		if err != nil {
+			return nil, err
+		}
+		return &Result{Backend: BackendSystemd, Files: []string{servicePath, timerPath}}, nil
+	default:
+		return &Result{Backend: BackendCron, Crontab: CrontabLine(job)}, nil
+	}
+}
+
+// Installed reports what Uninstall would do for the given repository,
+// without doing it: the job files that exist and would be removed
+func Installed(repoPath string) (*Result, error) {
+	var backend Backend
+	var files []string
+	switch detectBackend() {
+	case BackendLaunchd:
+		plistPath, err := launchdPlistPath(repoPath)
+		if err != nil {
+			return nil, err
+		}
+		backend, files = BackendLaunchd, []string{plistPath}
+	case BackendSystemd:
+		servicePath, timerPath, err := systemdUnitPaths(repoPath)
+		if err != nil {
+			return nil, err
+		}
+		backend, files = BackendSystemd, []string{timerPath, servicePath}
+	default:
+		return &Result{Backend: BackendCron, Crontab: jobName(repoPath)}, nil
+	}
+
+	result := &Result{Backend: backend}
+	for _, file := range files {
+		if _, err := os.Stat(file); err == nil {
+			result.Files = append(result.Files, file)
+		}
+	}
+	return result, nil
+}
+
+// CrontabLine renders a crontab entry running cleanup for the job
+func CrontabLine(job Job) string {
+	var spec string
+	switch job.Interval {
+	case IntervalHourly:
+		spec = "0 * * * *"
+	case IntervalWeekly:
+		spec = "0 3 * * 0"
+	default:
+		spec = "0 3 * * *"
+	}
+	return fmt.Sprintf("%s cd %s && %s # %s", spec, shellQuote(job.RepoPath), cleanupCommand(job.Binary), jobName(job.RepoPath))
+}
+
+// SystemdUnits renders the service and timer units for the job
+func SystemdUnits(job Job) (service, timer string) {
+	name := jobName(job.RepoPath)
+
+	service = fmt.Sprintf(`[Unit]
+Description=wtree scheduled cleanup for %s
+
+[Service]
+Type=oneshot
+WorkingDirectory=%s
+ExecStart=%s
+`, job.RepoPath, job.RepoPath, cleanupCommand(job.Binary))
+
+	timer = fmt.Sprintf(`[Unit]
+Description=Run %s %s
+
+[Timer]
+OnCalendar=%s
+Persistent=true
+
+[Install]
+WantedBy=timers.target
+`, name, job.Interval, job.Interval)
+
+	return service, timer
+}
+
+// LaunchdPlist renders the launchd agent definition for the job
+func LaunchdPlist(job Job) string {
+	interval := 86400
+	switch job.Interval {
+	case IntervalHourly:
+		interval = 3600
+	case IntervalWeekly:
+		interval = 604800
+	}
+
+	return fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+	<key>Label</key>
+	<string>%s</string>
+	<key>ProgramArguments</key>
+	<array>
+		<string>%s</string>
+		<string>cleanup</string>
+		<string>--auto</string>
+	</array>
+	<key>WorkingDirectory</key>
+	<string>%s</string>
+	<key>StartInterval</key>
+	<integer>%d</integer>
+</dict>
+</plist>
+`, launchdLabel(job.RepoPath), xmlEscape(job.Binary), xmlEscape(job.RepoPath), interval)
+}
+
+// detectBackend picks the scheduler available on this platform
+func detectBackend() Backend {
+	switch runtime.GOOS {
+	case "darwin":
+		return BackendLaunchd
+	case "linux":
+		if _, err := exec.LookPath("systemctl"); err == nil {
+			if exec.Command("systemctl", "--user", "show-environment").Run() == nil {
+				return BackendSystemd
+			}
+		}
+	}
+	return BackendCron
+}
+
+func installSystemd(job Job) (*Result, error) {
+	servicePath, timerPath, err := systemdUnitPaths(job.RepoPath)
+	if err != nil {
+		return nil, err
+	}
+	unitDir := filepath.Dir(servicePath)
+	if err := os.MkdirAll(unitDir, 0755); err != nil {
+		return nil, types.NewFileSystemError("install-schedule", unitDir, "failed to create systemd unit directory", err)
+	}
+
+	name := jobName(job.RepoPath)
+	service, timer := SystemdUnits(job)
+
+	if err := os.WriteFile(servicePath, []byte(service), 0644); err != nil {
+		return nil, types.NewFileSystemError("install-schedule", servicePath, "failed to write service unit", err)
+	}
+	if err := os.WriteFile(timerPath, []byte(timer), 0644); err != nil {
+		return nil, types.NewFileSystemError("install-schedule", timerPath, "failed to write timer unit", err)
+	}
+
+	if err := exec.Command("systemctl", "--user", "daemon-reload").Run(); err != nil {
+		return nil, fmt.Errorf("failed to reload systemd user units: %w", err)
+	}
+	if err := exec.Command("systemctl", "--user", "enable", "--now", name+".timer").Run(); err != nil {
+		return nil, fmt.Errorf("failed to enable %s.timer: %w", name, err)
+	}
+
+	return &Result{Backend: BackendSystemd, Files: []string{servicePath, timerPath}}, nil
+}
+
+func uninstallSystemd(repoPath string) (*Result, error) {
+	servicePath, timerPath, err := systemdUnitPaths(repoPath)
+	if err != nil {
+		return nil, err
+	}
+
+	name := jobName(repoPath)
+	_ = exec.Command("systemctl", "--user", "disable", "--now", name+".timer").Run() // May not be enabled
+
+	result := &Result{Backend: BackendSystemd}
+	for _, path := range []string{timerPath, servicePath} {
+		if err := os.Remove(path); err == nil {
+			result.Files = append(result.Files, path)
+		} else if !os.IsNotExist(err) {
+			return nil, types.NewFileSystemError("uninstall-schedule", path, "failed to remove unit file", err)
+		}
+	}
+
+	_ = exec.Command("systemctl", "--user", "daemon-reload").Run()
+	return result, nil
+}
+
+func installLaunchd(job Job) (*Result, error) {
+	plistPath, err := launchdPlistPath(job.RepoPath)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(plistPath), 0755); err != nil {
+		return nil, types.NewFileSystemError("install-schedule", plistPath, "failed to create LaunchAgents directory", err)
+	}
+
+	if err := os.WriteFile(plistPath, []byte(LaunchdPlist(job)), 0644); err != nil {
+		return nil, types.NewFileSystemError("install-schedule", plistPath, "failed to write launchd agent", err)
+	}
+
+	_ = exec.Command("launchctl", "unload", plistPath).Run() // Ignore if not loaded yet
+	if err := exec.Command("launchctl", "load", plistPath).Run(); err != nil {
+		return nil, types.NewFileSystemError("install-schedule", plistPath, "failed to load launchd agent", err)
+	}
+
+	return &Result{Backend: BackendLaunchd, Files: []string{plistPath}}, nil
+}
+
+func uninstallLaunchd(repoPath string) (*Result, error) {
+	plistPath, err := launchdPlistPath(repoPath)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{Backend: BackendLaunchd}
+	if _, err := os.Stat(plistPath); os.IsNotExist(err) {
+		return result, nil
+	}
+
+	_ = exec.Command("launchctl", "unload", plistPath).Run()
+	if err := os.Remove(plistPath); err != nil {
+		return nil, types.NewFileSystemError("uninstall-schedule", plistPath, "failed to remove launchd agent", err)
+	}
+	result.Files = append(result.Files, plistPath)
+
+	return result, nil
+}
+
+func systemdUnitDir() (string, error) {
+	if configHome := os.Getenv("XDG_CONFIG_HOME"); configHome != "" {
+		return filepath.Join(configHome, "systemd", "user"), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(home, ".config", "systemd", "user"), nil
+}
+
+// systemdUnitPaths returns where the service and timer units of the
+// repository's job are written
+func systemdUnitPaths(repoPath string) (service, timer string, err error) {
+	unitDir, err := systemdUnitDir()
+	if err != nil {
+		return "", "", err
+	}
+	name := jobName(repoPath)
+	return filepath.Join(unitDir, name+".service"), filepath.Join(unitDir, name+".timer"), nil
+}
+
+func launchdPlistPath(repoPath string) (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(home, "Library", "LaunchAgents", launchdLabel(repoPath)+".plist"), nil
+}
+
+// jobName creates a stable per-repository job name
+func jobName(repoPath string) string {
+	hash := sha256.Sum256([]byte(repoPath))
+	return fmt.Sprintf("wtree-cleanup-%x", hash[:6])
+}
+
+func launchdLabel(repoPath string) string {
+	return "com.wtree." + strings.TrimPrefix(jobName(repoPath), "wtree-")
+}
+
+func cleanupCommand(binary string) string {
+	return shellQuote(binary) + " cleanup --auto"
+}
+
+func shellQuote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", "'\"'\"'") + "'"
+}
+
+func xmlEscape(value string) string {
+	replacer := strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", `"`, "&quot;", "'", "&apos;")
+	return replacer.Replace(value)
+}
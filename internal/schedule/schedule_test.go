@@ -0,0 +1,69 @@
+package schedule
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseInterval(t *testing.T) {
+	tests := []struct {
+		input       string
+		expected    Interval
+		expectError bool
+	}{
+		{input: "daily", expected: IntervalDaily},
+		{input: "Hourly", expected: IntervalHourly},
+		{input: " weekly ", expected: IntervalWeekly},
+		{input: "monthly", expectError: true},
+		{input: "", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			interval, err := ParseInterval(tt.input)
+			if tt.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, interval)
+			}
+		})
+	}
+}
+
+func TestCrontabLine(t *testing.T) {
+	job := Job{RepoPath: "/home/dev/my repo", Binary: "/usr/local/bin/wtree", Interval: IntervalDaily}
+
+	line := CrontabLine(job)
+	assert.Contains(t, line, "0 3 * * * ")
+	assert.Contains(t, line, "cd '/home/dev/my repo'")
+	assert.Contains(t, line, "'/usr/local/bin/wtree' cleanup --auto")
+	assert.Contains(t, line, "# "+jobName(job.RepoPath))
+
+	job.Interval = IntervalHourly
+	assert.Contains(t, CrontabLine(job), "0 * * * * ")
+}
+
+func TestSystemdUnits(t *testing.T) {
+	job := Job{RepoPath: "/repo", Binary: "/bin/wtree", Interval: IntervalWeekly}
+
+	service, timer := SystemdUnits(job)
+	assert.Contains(t, service, "WorkingDirectory=/repo")
+	assert.Contains(t, service, "ExecStart='/bin/wtree' cleanup --auto")
+	assert.Contains(t, timer, "OnCalendar=weekly")
+}
+
+func TestLaunchdPlist(t *testing.T) {
+	job := Job{RepoPath: "/repo/a&b", Binary: "/bin/wtree", Interval: IntervalHourly}
+
+	plist := LaunchdPlist(job)
+	assert.Contains(t, plist, "<string>/repo/a&amp;b</string>")
+	assert.Contains(t, plist, "<integer>3600</integer>")
+	assert.Contains(t, plist, launchdLabel(job.RepoPath))
+}
+
+func TestJobNameStablePerRepo(t *testing.T) {
+	assert.Equal(t, jobName("/repo/one"), jobName("/repo/one"))
+	assert.NotEqual(t, jobName("/repo/one"), jobName("/repo/two"))
+}
@@ -0,0 +1,119 @@
+package testutil
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// binary is the wtree binary Main built; empty under -short
+var binary string
+
+// Main runs a test package's tests, building the wtree binary for them once
+// first. Call it from TestMain. Under -short nothing is built, and NewRepo
+// skips the tests.
+func Main(m *testing.M) {
+	flag.Parse()
+	if testing.Short() {
+		os.Exit(m.Run())
+	}
+
+	dir, err := os.MkdirTemp("", "wtree-integration-")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to create build directory: %v\n", err)
+		os.Exit(1)
+	}
+	binary, err = build(dir)
+	if err != nil {
+		os.RemoveAll(dir)
+		fmt.Fprintf(os.Stderr, "failed to build wtree: %v\n", err)
+		os.Exit(1)
+	}
+
+	code := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+// build compiles the module's main package into dir, with the race detector
+// under go test -race
+func build(dir string) (string, error) {
+	_, source, _, _ := runtime.Caller(0)
+	output := filepath.Join(dir, "wtree")
+	if runtime.GOOS == "windows" {
+		output += ".exe"
+	}
+	args := []string{"build", "-o", output}
+	if raceEnabled {
+		args = append(args, "-race")
+	}
+	cmd := exec.Command("go", append(args, "github.com/awhite/wtree")...)
+	cmd.Dir = filepath.Dir(source)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return "", fmt.Errorf("%w\n%s", err, out)
+	}
+	return output, nil
+}
+
+// Result is how a wtree command ended
+type Result struct {
+	Args     []string
+	Stdout   string
+	Stderr   string
+	ExitCode int
+}
+
+// Output is stdout and stderr together, for failure messages
+func (r Result) Output() string {
+	return r.Stdout + r.Stderr
+}
+
+// Run runs wtree with args in the main worktree. Standard input is empty, so
+// confirmations need -y.
+func (r *Repo) Run(args ...string) Result {
+	r.t.Helper()
+	return r.RunIn(r.Dir, args...)
+}
+
+// RunIn runs wtree with args in dir, such as a worktree it created
+func (r *Repo) RunIn(dir string, args ...string) Result {
+	r.t.Helper()
+	if binary == "" {
+		r.t.Fatal("wtree binary not built; call testutil.Main from TestMain")
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command(binary, args...)
+	cmd.Dir = dir
+	cmd.Env = r.env
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	result := Result{Args: args}
+	err := cmd.Run()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		result.ExitCode = exitErr.ExitCode()
+	} else if err != nil {
+		r.t.Fatalf("failed to run wtree %s: %v", strings.Join(args, " "), err)
+	}
+	result.Stdout, result.Stderr = stdout.String(), stderr.String()
+	return result
+}
+
+// MustRun runs wtree like Run and fails the test unless it succeeds
+func (r *Repo) MustRun(args ...string) Result {
+	r.t.Helper()
+	result := r.Run(args...)
+	if result.ExitCode != 0 {
+		r.t.Fatalf("wtree %s exited %d:\n%s", strings.Join(args, " "), result.ExitCode, result.Output())
+	}
+	return result
+}
@@ -0,0 +1,7 @@
+//go:build !race
+
+package testutil
+
+// raceEnabled builds the wtree binary with the race detector when the tests
+// run with it
+const raceEnabled = false
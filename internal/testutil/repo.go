@@ -0,0 +1,191 @@
+// Package testutil builds throwaway git repositories and runs the wtree
+// binary against them, for integration tests of whole commands: create,
+// delete, cleanup, switch... with real git rather than mocks.
+//
+// A test package using it builds the binary once in TestMain:
+//
+//	func TestMain(m *testing.M) { testutil.Main(m) }
+//
+//	func TestCreate(t *testing.T) {
+//		repo := testutil.NewRepo(t)
+//		repo.MustRun("-y", "create", "-b", "feature")
+//	}
+package testutil
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// Repo is a git repository in a temporary directory, with a fresh home
+// directory so neither the user's git nor wtree configuration applies.
+// Worktrees wtree creates for it land next to it, in Root.
+type Repo struct {
+	t    testing.TB
+	Root string // Temporary directory holding the repository, its worktrees, remotes and home
+	Dir  string // The main worktree
+	Home string
+	env  []string
+}
+
+// NewRepo creates a repository on main with one commit. Under -short the
+// test is skipped: integration tests run git and the wtree binary.
+func NewRepo(t testing.TB) *Repo {
+	t.Helper()
+	if testing.Short() {
+		t.Skip("integration test")
+	}
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+
+	// Resolved, so paths match what git reports (/var is /private/var on macOS)
+	root, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to resolve temporary directory: %v", err)
+	}
+	r := &Repo{
+		t:    t,
+		Root: root,
+		Dir:  filepath.Join(root, "repo"),
+		Home: filepath.Join(root, "home"),
+	}
+	r.env = isolatedEnv(r.Home)
+	for _, dir := range []string{r.Dir, r.Home} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatalf("failed to create %s: %v", dir, err)
+		}
+	}
+
+	r.Git("init", "--quiet", "--initial-branch=main")
+	r.Commit("Initial commit", map[string]string{"README.md": "# repo\n"})
+	return r
+}
+
+// isolatedEnv is the environment git and wtree run with: the test's own
+// environment with home, configuration and identity replaced
+func isolatedEnv(home string) []string {
+	var env []string
+	for _, kv := range os.Environ() {
+		name, _, _ := strings.Cut(kv, "=")
+		if strings.HasPrefix(name, "GIT_") || strings.HasPrefix(name, "XDG_") ||
+			strings.HasPrefix(name, "WTREE_") || name == "HOME" || name == "USERPROFILE" {
+			continue
+		}
+		env = append(env, kv)
+	}
+	return append(env,
+		"HOME="+home,
+		"USERPROFILE="+home,
+		"XDG_CONFIG_HOME="+filepath.Join(home, ".config"),
+		"XDG_DATA_HOME="+filepath.Join(home, ".local", "share"),
+		"GIT_CONFIG_GLOBAL="+filepath.Join(home, ".gitconfig"),
+		"GIT_CONFIG_NOSYSTEM=1",
+		"GIT_AUTHOR_NAME=wtree test",
+		"GIT_AUTHOR_EMAIL=test@example.com",
+		"GIT_COMMITTER_NAME=wtree test",
+		"GIT_COMMITTER_EMAIL=test@example.com",
+		"GIT_TERMINAL_PROMPT=0",
+	)
+}
+
+// Git runs git in the main worktree and returns its trimmed output; the
+// test fails if git does
+func (r *Repo) Git(args ...string) string {
+	r.t.Helper()
+	return r.GitIn(r.Dir, args...)
+}
+
+// GitIn runs git in dir, such as a worktree wtree created
+func (r *Repo) GitIn(dir string, args ...string) string {
+	r.t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Env = r.env
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		r.t.Fatalf("git %s in %s: %v\n%s", strings.Join(args, " "), dir, err, output)
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// WriteFile writes a file of the main worktree, creating its directories
+func (r *Repo) WriteFile(name, content string) {
+	r.t.Helper()
+	path := filepath.Join(r.Dir, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		r.t.Fatalf("failed to create directory for %s: %v", name, err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		r.t.Fatalf("failed to write %s: %v", name, err)
+	}
+}
+
+// Commit writes files, relative to the main worktree, and commits them on
+// the current branch. It returns the new commit.
+func (r *Repo) Commit(message string, files map[string]string) string {
+	r.t.Helper()
+	for name, content := range files {
+		r.WriteFile(name, content)
+		r.Git("add", "--", name)
+	}
+	r.Git("commit", "--quiet", "--allow-empty", "-m", message)
+	return r.Git("rev-parse", "HEAD")
+}
+
+// Branch creates a branch at from, without checking it out
+func (r *Repo) Branch(name, from string) {
+	r.t.Helper()
+	r.Git("branch", name, from)
+}
+
+// AddRemote creates a bare repository, adds it as remote name by file://
+// URL and pushes main to it, tracked. It returns the bare repository's path.
+func (r *Repo) AddRemote(name string) string {
+	r.t.Helper()
+	bare := filepath.Join(r.Root, "remotes", name+".git")
+	if err := os.MkdirAll(bare, 0755); err != nil {
+		r.t.Fatalf("failed to create %s: %v", bare, err)
+	}
+	r.GitIn(bare, "init", "--quiet", "--bare", "--initial-branch=main")
+	r.Git("remote", "add", name, FileURL(bare))
+	r.Git("push", "--quiet", "--set-upstream", name, "main")
+	return bare
+}
+
+// FileURL is the file:// URL of a local repository
+func FileURL(path string) string {
+	path = filepath.ToSlash(path)
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path // C:/repo
+	}
+	return "file://" + path
+}
+
+// Sibling returns the path of name next to the main worktree, where wtree
+// puts worktrees by default: Sibling("repo-feature")
+func (r *Repo) Sibling(name string) string {
+	return filepath.Join(r.Root, name)
+}
+
+// Worktrees returns the paths of the repository's worktrees, the main one
+// first, as git lists them
+func (r *Repo) Worktrees() []string {
+	r.t.Helper()
+	var paths []string
+	for _, line := range strings.Split(r.Git("worktree", "list", "--porcelain"), "\n") {
+		if path, ok := strings.CutPrefix(line, "worktree "); ok {
+			paths = append(paths, filepath.Clean(path))
+		}
+	}
+	return paths
+}
+
+// HasBranch reports whether the repository has a local branch name
+func (r *Repo) HasBranch(name string) bool {
+	r.t.Helper()
+	return r.Git("branch", "--list", name) != ""
+}
@@ -0,0 +1,32 @@
+package ui
+
+import (
+	"fmt"
+	"os/exec"
+	"runtime"
+)
+
+// OpenURL opens a URL in the user's default browser
+func (m *Manager) OpenURL(url string) error {
+	args := browserCommand(runtime.GOOS, url)
+	cmd := exec.Command(args[0], args[1:]...)
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to open %s: %w", url, err)
+	}
+
+	// Don't wait for the browser; reap the opener in the background
+	go func() { _ = cmd.Wait() }()
+	return nil
+}
+
+// browserCommand returns the platform command that opens a URL
+func browserCommand(goos, url string) []string {
+	switch goos {
+	case "darwin":
+		return []string{"open", url}
+	case "windows":
+		return []string{"rundll32", "url.dll,FileProtocolHandler", url}
+	default:
+		return []string{"xdg-open", url}
+	}
+}
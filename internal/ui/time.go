@@ -0,0 +1,63 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+)
+
+// SetAbsoluteTimes switches Time from relative times ("3 days ago") to ISO
+// 8601 timestamps, for ui.time_format: absolute
+func (m *Manager) SetAbsoluteTimes(absolute bool) {
+	m.absoluteTimes = absolute
+}
+
+// Time renders a time for tables and messages: relative to now unless
+// absolute times are enabled, and "-" when t is zero. A nil manager renders
+// relative times.
+func (m *Manager) Time(t time.Time) string {
+	switch {
+	case t.IsZero():
+		return "-"
+	case m != nil && m.absoluteTimes:
+		return t.Format(time.RFC3339)
+	}
+	return RelativeTime(t, time.Now())
+}
+
+// relativeUnits are the steps RelativeTime rounds down to, largest first
+var relativeUnits = []struct {
+	name string
+	size time.Duration
+}{
+	{"year", 365 * 24 * time.Hour},
+	{"month", 30 * 24 * time.Hour},
+	{"week", 7 * 24 * time.Hour},
+	{"day", 24 * time.Hour},
+	{"hour", time.Hour},
+	{"minute", time.Minute},
+}
+
+// RelativeTime renders how far t is from now in its largest whole unit,
+// e.g. "3 days ago" or, for times after now, "in 2 hours"
+func RelativeTime(t, now time.Time) string {
+	d := now.Sub(t)
+	future := d < 0
+	if future {
+		d = -d
+	}
+	for _, unit := range relativeUnits {
+		if d < unit.size {
+			continue
+		}
+		count := int(d / unit.size)
+		amount := fmt.Sprintf("%d %ss", count, unit.name)
+		if count == 1 {
+			amount = "1 " + unit.name
+		}
+		if future {
+			return "in " + amount
+		}
+		return amount + " ago"
+	}
+	return "just now"
+}
@@ -0,0 +1,39 @@
+package ui
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRelativeTime(t *testing.T) {
+	now := time.Date(2026, 10, 14, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		ago  time.Duration
+		want string
+	}{
+		{10 * time.Second, "just now"},
+		{time.Minute, "1 minute ago"},
+		{45 * time.Minute, "45 minutes ago"},
+		{5 * time.Hour, "5 hours ago"},
+		{3 * 24 * time.Hour, "3 days ago"},
+		{15 * 24 * time.Hour, "2 weeks ago"},
+		{70 * 24 * time.Hour, "2 months ago"},
+		{400 * 24 * time.Hour, "1 year ago"},
+		{-2 * 24 * time.Hour, "in 2 days"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, RelativeTime(now.Add(-tt.ago), now), "for %s", tt.ago)
+	}
+}
+
+func TestManager_Time(t *testing.T) {
+	m := NewManager(false, false)
+	assert.Equal(t, "-", m.Time(time.Time{}))
+	assert.Equal(t, "1 hour ago", m.Time(time.Now().Add(-90*time.Minute)))
+
+	m.SetAbsoluteTimes(true)
+	when := time.Date(2026, 10, 14, 12, 0, 0, 0, time.UTC)
+	assert.Equal(t, "2026-10-14T12:00:00Z", m.Time(when))
+}
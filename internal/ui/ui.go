@@ -3,6 +3,7 @@ package ui
 import (
 	"bufio"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 	"time"
@@ -25,6 +26,15 @@ const (
 type Manager struct {
 	colors  bool
 	verbose bool
+	quiet   bool // Suppress everything but errors
+
+	// Plain descriptive lines instead of glyphs, spinners and redrawing
+	accessible bool
+
+	// ISO 8601 timestamps instead of relative times
+	absoluteTimes bool
+
+	out io.Writer // Where messages go; nil is standard output
 }
 
 // NewManager creates a new UI manager
@@ -35,68 +45,125 @@ func NewManager(colors, verbose bool) *Manager {
 	}
 }
 
+// Quiet returns a copy of the manager that prints only errors, for work
+// running in the background whose progress is reported elsewhere
+func (m *Manager) Quiet() *Manager {
+	quiet := *m
+	quiet.quiet = true
+	return &quiet
+}
+
+// WithOutput returns a copy of the manager that prints to out, such as
+// standard error when standard output is read by a script
+func (m *Manager) WithOutput(out io.Writer) *Manager {
+	copied := *m
+	copied.out = out
+	return &copied
+}
+
+// Output returns where the manager prints; standard output for a nil manager
+func (m *Manager) Output() io.Writer {
+	if m == nil || m.out == nil {
+		return os.Stdout
+	}
+	return m.out
+}
+
+// SetAccessible switches to output suited to screen readers and log
+// collectors: words instead of glyphs, and no carriage-return redrawing
+func (m *Manager) SetAccessible(accessible bool) {
+	m.accessible = accessible
+}
+
+// Accessible reports whether accessible output is enabled
+func (m *Manager) Accessible() bool {
+	return m.accessible
+}
+
 // Success prints a success message
 func (m *Manager) Success(format string, args ...interface{}) {
+	if m.quiet {
+		return
+	}
 	message := fmt.Sprintf(format, args...)
-	if m.colors {
-		fmt.Printf("%s✓%s %s\n", Green, Reset, message)
+	if m.accessible {
+		fmt.Fprintf(m.Output(), "Done: %s\n", message)
+	} else if m.colors {
+		fmt.Fprintf(m.Output(), "%s✓%s %s\n", Green, Reset, message)
 	} else {
-		fmt.Printf("✓ %s\n", message)
+		fmt.Fprintf(m.Output(), "✓ %s\n", message)
 	}
 }
 
 // Error prints an error message
 func (m *Manager) Error(format string, args ...interface{}) {
 	message := fmt.Sprintf(format, args...)
-	if m.colors {
-		fmt.Printf("%s✗%s %s\n", Red, Reset, message)
+	if m.accessible {
+		fmt.Fprintf(m.Output(), "Error: %s\n", message)
+	} else if m.colors {
+		fmt.Fprintf(m.Output(), "%s✗%s %s\n", Red, Reset, message)
 	} else {
-		fmt.Printf("✗ %s\n", message)
+		fmt.Fprintf(m.Output(), "✗ %s\n", message)
 	}
 }
 
 // Warning prints a warning message
 func (m *Manager) Warning(format string, args ...interface{}) {
+	if m.quiet {
+		return
+	}
 	message := fmt.Sprintf(format, args...)
-	if m.colors {
-		fmt.Printf("%s⚠%s %s\n", Yellow, Reset, message)
+	if m.accessible {
+		fmt.Fprintf(m.Output(), "Warning: %s\n", message)
+	} else if m.colors {
+		fmt.Fprintf(m.Output(), "%s⚠%s %s\n", Yellow, Reset, message)
 	} else {
-		fmt.Printf("⚠ %s\n", message)
+		fmt.Fprintf(m.Output(), "⚠ %s\n", message)
 	}
 }
 
 // Info prints an informational message
 func (m *Manager) Info(format string, args ...interface{}) {
+	if m.quiet {
+		return
+	}
 	message := fmt.Sprintf(format, args...)
-	if m.colors {
-		fmt.Printf("%sℹ%s %s\n", Blue, Reset, message)
+	if m.accessible {
+		fmt.Fprintln(m.Output(), message)
+	} else if m.colors {
+		fmt.Fprintf(m.Output(), "%sℹ%s %s\n", Blue, Reset, message)
 	} else {
-		fmt.Printf("ℹ %s\n", message)
+		fmt.Fprintf(m.Output(), "ℹ %s\n", message)
 	}
 }
 
 // Progress prints a progress message (only if verbose)
 func (m *Manager) Progress(format string, args ...interface{}) {
-	if !m.verbose {
+	if !m.verbose || m.quiet {
 		return
 	}
 	message := fmt.Sprintf(format, args...)
-	if m.colors {
-		fmt.Printf("%s⣾%s %s\n", Blue, Reset, message)
+	if m.accessible {
+		fmt.Fprintln(m.Output(), message)
+	} else if m.colors {
+		fmt.Fprintf(m.Output(), "%s⣾%s %s\n", Blue, Reset, message)
 	} else {
-		fmt.Printf("→ %s\n", message)
+		fmt.Fprintf(m.Output(), "→ %s\n", message)
 	}
 }
 
 // InfoIndented prints an indented info message
 func (m *Manager) InfoIndented(format string, args ...interface{}) {
+	if m.quiet {
+		return
+	}
 	message := fmt.Sprintf(format, args...)
-	fmt.Printf("  %s\n", message)
+	fmt.Fprintf(m.Output(), "  %s\n", message)
 }
 
 // Confirm asks the user for confirmation
 func (m *Manager) Confirm(message string) error {
-	fmt.Printf("%s [y/N]: ", message)
+	fmt.Fprintf(m.Output(), "%s [y/N]: ", message)
 
 	reader := bufio.NewReader(os.Stdin)
 	response, err := reader.ReadString('\n')
@@ -112,17 +179,75 @@ func (m *Manager) Confirm(message string) error {
 	return nil
 }
 
+// Ask prompts for a line of input, returning defaultValue when the answer is empty
+func (m *Manager) Ask(message, defaultValue string) (string, error) {
+	if defaultValue != "" {
+		fmt.Fprintf(m.Output(), "%s [%s]: ", message, defaultValue)
+	} else {
+		fmt.Fprintf(m.Output(), "%s: ", message)
+	}
+
+	reader := bufio.NewReader(os.Stdin)
+	response, err := reader.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+
+	if response = strings.TrimSpace(response); response == "" {
+		return defaultValue, nil
+	}
+	return response, nil
+}
+
+// ConfirmAlways is Confirm that also accepts "a" (always), reported as
+// always so the caller can stop asking
+func (m *Manager) ConfirmAlways(message string) (bool, error) {
+	fmt.Fprintf(m.Output(), "%s [y/N/a(lways)]: ", message)
+
+	reader := bufio.NewReader(os.Stdin)
+	response, err := reader.ReadString('\n')
+	if err != nil {
+		return false, err
+	}
+
+	switch strings.TrimSpace(strings.ToLower(response)) {
+	case "y", "yes":
+		return false, nil
+	case "a", "always":
+		return true, nil
+	default:
+		return false, fmt.Errorf("operation cancelled by user")
+	}
+}
+
+// ConfirmTyped asks the user to type an exact phrase to confirm a risky operation
+func (m *Manager) ConfirmTyped(message, phrase string) error {
+	fmt.Fprintf(m.Output(), "%s: ", message)
+
+	reader := bufio.NewReader(os.Stdin)
+	response, err := reader.ReadString('\n')
+	if err != nil {
+		return err
+	}
+
+	if strings.TrimSpace(response) != phrase {
+		return fmt.Errorf("operation cancelled by user")
+	}
+
+	return nil
+}
+
 // ConfirmWithOptions asks the user for confirmation with custom options
 func (m *Manager) ConfirmWithOptions(message string, options map[string]string) (string, error) {
 	// Show options
-	fmt.Printf("%s\n", message)
+	fmt.Fprintf(m.Output(), "%s\n", message)
 	var keys []string
 	for key, desc := range options {
-		fmt.Printf("  [%s] %s\n", key, desc)
+		fmt.Fprintf(m.Output(), "  [%s] %s\n", key, desc)
 		keys = append(keys, key)
 	}
 	_ = keys // keys variable is used for potential future functionality
-	fmt.Print("Choose: ")
+	fmt.Fprint(m.Output(), "Choose: ")
 
 	reader := bufio.NewReader(os.Stdin)
 	response, err := reader.ReadString('\n')
@@ -142,20 +267,28 @@ func (m *Manager) ConfirmWithOptions(message string, options map[string]string)
 
 // Header prints a section header
 func (m *Manager) Header(format string, args ...interface{}) {
+	if m.quiet {
+		return
+	}
 	message := fmt.Sprintf(format, args...)
-	if m.colors {
-		fmt.Printf("\n%s%s=== %s ===%s\n", Bold, Blue, message, Reset)
+	if m.accessible {
+		fmt.Fprintf(m.Output(), "\n%s\n", message)
+	} else if m.colors {
+		fmt.Fprintf(m.Output(), "\n%s%s=== %s ===%s\n", Bold, Blue, message, Reset)
 	} else {
-		fmt.Printf("\n=== %s ===\n", message)
+		fmt.Fprintf(m.Output(), "\n=== %s ===\n", message)
 	}
 }
 
 // Separator prints a visual separator
 func (m *Manager) Separator() {
+	if m.accessible {
+		return
+	}
 	if m.colors {
-		fmt.Printf("%s%s%s\n", Gray, strings.Repeat("─", 50), Reset)
+		fmt.Fprintf(m.Output(), "%s%s%s\n", Gray, strings.Repeat("─", 50), Reset)
 	} else {
-		fmt.Println(strings.Repeat("-", 50))
+		fmt.Fprintln(m.Output(), strings.Repeat("-", 50))
 	}
 }
 
@@ -189,16 +322,30 @@ func (t *Table) Render() {
 		return
 	}
 
+	// Box drawing reads badly aloud; describe each row as "Header: value"
+	if t.manager.accessible {
+		for _, row := range t.rows {
+			var parts []string
+			for i, cell := range row {
+				if i < len(t.headers) && cell != "" {
+					parts = append(parts, fmt.Sprintf("%s: %s", t.headers[i], cell))
+				}
+			}
+			fmt.Fprintln(t.manager.Output(), strings.Join(parts, ", "))
+		}
+		return
+	}
+
 	// Calculate column widths
 	widths := make([]int, len(t.headers))
 	for i, header := range t.headers {
-		widths[i] = len(header)
+		widths[i] = displayWidth(header)
 	}
 
 	for _, row := range t.rows {
 		for i, cell := range row {
-			if i < len(widths) && len(cell) > widths[i] {
-				widths[i] = len(cell)
+			if i < len(widths) && displayWidth(cell) > widths[i] {
+				widths[i] = displayWidth(cell)
 			}
 		}
 	}
@@ -225,14 +372,29 @@ func (t *Table) printRow(cells []string, widths []int, isHeader bool) {
 	for i, cell := range cells {
 		width := widths[i]
 		if i < len(widths) {
+			padded := cell + strings.Repeat(" ", max(0, width-displayWidth(cell)))
 			if isHeader && t.manager.colors {
-				parts = append(parts, fmt.Sprintf("%s%-*s%s", Bold, width, cell, Reset))
+				parts = append(parts, Bold+padded+Reset)
 			} else {
-				parts = append(parts, fmt.Sprintf("%-*s", width, cell))
+				parts = append(parts, padded)
 			}
 		}
 	}
-	fmt.Printf("┌%s┐\n", strings.Join(parts, " │ "))
+	fmt.Fprintf(t.manager.Output(), "┌%s┐\n", strings.Join(parts, " │ "))
+}
+
+// displayWidth returns the number of terminal columns s occupies, counting
+// emoji as two columns
+func displayWidth(s string) int {
+	width := 0
+	for _, r := range s {
+		if r >= 0x1F300 && r <= 0x1FAFF {
+			width += 2
+		} else {
+			width++
+		}
+	}
+	return width
 }
 
 // ProgressBar represents a simple progress bar (placeholder for future enhancement)
@@ -272,20 +434,24 @@ func (pb *ProgressBar) render() {
 	}
 
 	percent := float64(pb.current) / float64(pb.total)
+	if pb.manager.accessible {
+		fmt.Fprintf(pb.manager.Output(), "Progress: %d of %d\n", pb.current, pb.total)
+		return
+	}
 	filled := int(percent * float64(pb.width))
 
 	bar := strings.Repeat("█", filled) + strings.Repeat("░", pb.width-filled)
 
 	if pb.manager.colors {
-		fmt.Printf("\r%s[%s]%s %.1f%% (%d/%d)",
+		fmt.Fprintf(pb.manager.Output(), "\r%s[%s]%s %.1f%% (%d/%d)",
 			Blue, bar, Reset, percent*100, pb.current, pb.total)
 	} else {
-		fmt.Printf("\r[%s] %.1f%% (%d/%d)",
+		fmt.Fprintf(pb.manager.Output(), "\r[%s] %.1f%% (%d/%d)",
 			bar, percent*100, pb.current, pb.total)
 	}
 
 	if pb.current >= pb.total {
-		fmt.Println() // New line when complete
+		fmt.Fprintln(pb.manager.Output()) // New line when complete
 	}
 }
 
@@ -296,11 +462,15 @@ func (pb *ProgressBar) Finish() {
 
 // SetMessage sets a custom message for the progress bar
 func (pb *ProgressBar) SetMessage(message string) {
+	if pb.manager.accessible {
+		fmt.Fprintf(pb.manager.Output(), "Progress: %d of %d, %s\n", pb.current, pb.total, message)
+		return
+	}
 	pb.render()
 	if pb.manager.colors {
-		fmt.Printf(" %s%s%s", Cyan, message, Reset)
+		fmt.Fprintf(pb.manager.Output(), " %s%s%s", Cyan, message, Reset)
 	} else {
-		fmt.Printf(" %s", message)
+		fmt.Fprintf(pb.manager.Output(), " %s", message)
 	}
 }
 
@@ -328,6 +498,10 @@ func (m *Manager) NewSpinner(message string) *Spinner {
 
 // Start starts the spinner
 func (s *Spinner) Start() {
+	if s.manager.accessible {
+		fmt.Fprintln(s.manager.Output(), s.message)
+		return
+	}
 	s.active = true
 	go s.spin()
 }
@@ -336,8 +510,10 @@ func (s *Spinner) Start() {
 func (s *Spinner) Stop() {
 	if s.active {
 		s.active = false
-		s.stopChan <- true
-		fmt.Print("\r\033[K") // Clear line
+		// Closing rather than sending: spin may already have seen active go
+		// false and returned, and nobody would receive
+		close(s.stopChan)
+		fmt.Fprint(s.manager.Output(), "\r\033[K") // Clear line
 	}
 }
 
@@ -348,16 +524,16 @@ func (s *Spinner) UpdateMessage(message string) {
 
 // spin runs the spinning animation
 func (s *Spinner) spin() {
-	for s.active {
+	for {
 		select {
 		case <-s.stopChan:
 			return
 		default:
 			char := s.chars[s.index%len(s.chars)]
 			if s.manager.colors {
-				fmt.Printf("\r%s%s%s %s", Blue, char, Reset, s.message)
+				fmt.Fprintf(s.manager.Output(), "\r%s%s%s %s", Blue, char, Reset, s.message)
 			} else {
-				fmt.Printf("\r%s %s", char, s.message)
+				fmt.Fprintf(s.manager.Output(), "\r%s %s", char, s.message)
 			}
 			s.index++
 			time.Sleep(100 * time.Millisecond)
@@ -404,7 +580,7 @@ func (msp *MultiStepProgress) StartStep(index int) {
 	if index < len(msp.statuses) {
 		msp.current = index
 		msp.statuses[index] = "running"
-		msp.render()
+		msp.render(index)
 	}
 }
 
@@ -412,7 +588,7 @@ func (msp *MultiStepProgress) StartStep(index int) {
 func (msp *MultiStepProgress) CompleteStep(index int) {
 	if index < len(msp.statuses) {
 		msp.statuses[index] = "completed"
-		msp.render()
+		msp.render(index)
 	}
 }
 
@@ -420,13 +596,20 @@ func (msp *MultiStepProgress) CompleteStep(index int) {
 func (msp *MultiStepProgress) FailStep(index int) {
 	if index < len(msp.statuses) {
 		msp.statuses[index] = "failed"
-		msp.render()
+		msp.render(index)
 	}
 }
 
-// render displays the multi-step progress
-func (msp *MultiStepProgress) render() {
-	fmt.Println() // New line
+// render displays the multi-step progress after the step at index changed
+func (msp *MultiStepProgress) render(index int) {
+	if msp.manager.quiet {
+		return
+	}
+	if msp.manager.accessible {
+		msp.renderAccessible(index)
+		return
+	}
+	fmt.Fprintln(msp.manager.Output()) // New line
 	for i, step := range msp.steps {
 		var icon, color string
 		switch msp.statuses[i] {
@@ -441,13 +624,30 @@ func (msp *MultiStepProgress) render() {
 		}
 
 		if msp.manager.colors {
-			fmt.Printf("  %s%s%s %s\n", color, icon, Reset, step)
+			fmt.Fprintf(msp.manager.Output(), "  %s%s%s %s\n", color, icon, Reset, step)
 		} else {
-			fmt.Printf("  %s %s\n", icon, step)
+			fmt.Fprintf(msp.manager.Output(), "  %s %s\n", icon, step)
 		}
 	}
 }
 
+// renderAccessible describes the step that changed in one line, e.g.
+// "Step 2 of 4: creating git worktree"
+func (msp *MultiStepProgress) renderAccessible(index int) {
+	step := msp.steps[index]
+	if step != "" {
+		step = strings.ToLower(step[:1]) + step[1:]
+	}
+	switch msp.statuses[index] {
+	case "running":
+		fmt.Fprintf(msp.manager.Output(), "Step %d of %d: %s\n", index+1, len(msp.steps), step)
+	case "completed":
+		fmt.Fprintf(msp.manager.Output(), "Step %d of %d done\n", index+1, len(msp.steps))
+	case "failed":
+		fmt.Fprintf(msp.manager.Output(), "Step %d of %d failed: %s\n", index+1, len(msp.steps), step)
+	}
+}
+
 // ColorString applies color to a string if colors are enabled
 func (m *Manager) ColorString(text, color string) string {
 	if !m.colors {
@@ -0,0 +1,49 @@
+package worktree
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommandLine(t *testing.T) {
+	assert.Equal(t, "code /tmp/repo-feature", commandLine([]string{"code", "/tmp/repo-feature"}))
+	assert.Equal(t, `xterm -e 'cd /tmp/my repo && bash'`, commandLine([]string{"xterm", "-e", "cd /tmp/my repo && bash"}))
+	assert.Equal(t, `code '/tmp/it'"'"'s'`, commandLine([]string{"code", "/tmp/it's"}))
+}
+
+func TestManager_executeEditorCommandAudit(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	m := &Manager{}
+
+	err := m.executeEditorCommand([]string{"wtree-no-such-editor", "/tmp/repo"})
+	assert.Error(t, err)
+
+	path, err := auditFile()
+	require.NoError(t, err)
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	require.Len(t, lines, 1)
+
+	var entry auditEntry
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &entry))
+	assert.Equal(t, "launch", entry.Event)
+	assert.Equal(t, []string{"wtree-no-such-editor", "/tmp/repo"}, entry.Command)
+	assert.NotEmpty(t, entry.Error)
+}
+
+func TestManager_executeEditorCommandPrintOnly(t *testing.T) {
+	dataHome := t.TempDir()
+	t.Setenv("XDG_DATA_HOME", dataHome)
+	m := &Manager{}
+	m.SetPrintCommands(true)
+
+	assert.NoError(t, m.executeEditorCommand([]string{"wtree-no-such-editor", "/tmp/repo"}))
+	assert.NoFileExists(t, filepath.Join(dataHome, "wtree", "audit.log"))
+}
@@ -0,0 +1,113 @@
+package worktree
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/awhite/wtree/internal/git"
+	"github.com/awhite/wtree/pkg/types"
+)
+
+// autostashPrefix starts the message of stashes wtree makes of a worktree it
+// leaves; the branch follows, e.g. wtree-auto/feature-x
+const autostashPrefix = "wtree-auto/"
+
+// autostashMessage names the autostash of branch
+func autostashMessage(branch string) string {
+	return autostashPrefix + branch
+}
+
+// autostashEnabled reports whether dirty worktrees are stashed without
+// asking: --autostash, or autostash in the global config
+func (m *Manager) autostashEnabled(requested bool) bool {
+	return requested || (m.globalConfig != nil && m.globalConfig.Autostash)
+}
+
+// autostash stashes a worktree's uncommitted changes, untracked files
+// included, as wtree-auto/<branch>. Stashes are shared by the repository's
+// worktrees, so it survives the worktree being deleted.
+func (m *Manager) autostash(wt *types.WorktreeInfo) error {
+	if wt.Branch == "" {
+		return types.NewValidationError("autostash",
+			fmt.Sprintf("HEAD is detached in %s, so its changes can't be stashed for a branch; commit or stash them yourself", wt.Path), nil)
+	}
+	message := autostashMessage(wt.Branch)
+	if err := m.repo.Stash(wt.Path, message); err != nil {
+		return err
+	}
+	m.completed("Stashed the uncommitted changes of %s as %s", wt.Branch, message)
+	m.ui.InfoIndented("They are restored when you switch to or create a worktree for '%s'", wt.Branch)
+	return nil
+}
+
+// restoreAutostash pops the autostash of a worktree's branch into it, if
+// there is one. A failure, such as a conflict, leaves the stash in place.
+func (m *Manager) restoreAutostash(wt *types.WorktreeInfo) {
+	if wt.Branch == "" {
+		return
+	}
+	message := autostashMessage(wt.Branch)
+	stash, err := m.repo.FindStash(message)
+	if err != nil || stash == "" {
+		return
+	}
+	if err := m.repo.StashPop(wt.Path, stash); err != nil {
+		m.warn("Failed to restore the changes stashed as %s; they are kept in 'git stash list': %v", message, err)
+		return
+	}
+	m.completed("Restored the changes stashed as %s", message)
+}
+
+// stashOnLeave stashes the current worktree's uncommitted changes before
+// switching to target, with --autostash or autostash set. Otherwise, when a
+// prompt can be shown (shell integration, so the output isn't captured by
+// eval), it offers to; a declined offer leaves the changes where they are.
+func (m *Manager) stashOnLeave(target *types.WorktreeInfo, requested bool) error {
+	current, err := m.currentWorktree()
+	if err != nil || current.Path == target.Path {
+		return nil
+	}
+	status, err := m.repo.GetWorktreeStatus(current.Path)
+	if err != nil || status.Operation != nil || (status.IsClean && !hasUntrackedFiles(current.Path)) {
+		return nil
+	}
+	if current.Branch == "" {
+		m.warn("HEAD is detached in %s; its uncommitted changes stay there", current.Path)
+		return nil
+	}
+
+	if !m.autostashEnabled(requested) {
+		if os.Getenv("WTREE_CD_FILE") == "" || !stdinIsTerminal() {
+			m.ui.Info("%s has uncommitted changes; --autostash stashes them while you're away", current.DisplayBranch())
+			return nil
+		}
+		if m.confirm("", fmt.Sprintf("Stash the uncommitted changes in %s while you're away?", current.DisplayBranch())) != nil {
+			return nil
+		}
+	}
+	return m.autostash(current)
+}
+
+// hasUntrackedFiles reports whether a worktree has untracked files. IsClean
+// ignores them, but git worktree remove refuses them like changes. Unknown
+// is false.
+func hasUntrackedFiles(path string) bool {
+	untracked, err := git.HasUntrackedFiles(path)
+	return err == nil && untracked
+}
+
+// offerDeleteAutostash decides whether a dirty worktree about to be deleted
+// is stashed first: with --autostash or autostash set, or when the user
+// agrees at a prompt
+func (m *Manager) offerDeleteAutostash(wt *types.WorktreeInfo, options DeleteOptions) bool {
+	if wt.Branch == "" {
+		return false
+	}
+	if m.autostashEnabled(options.Autostash) {
+		return true
+	}
+	if options.Yes || !stdinIsTerminal() {
+		return false
+	}
+	return m.confirm("", fmt.Sprintf("Worktree has uncommitted changes. Stash them as %s?", autostashMessage(wt.Branch))) == nil
+}
@@ -0,0 +1,114 @@
+package worktree
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/awhite/wtree/pkg/types"
+)
+
+// caseInsensitiveDir reports whether the file system holding dir, or its
+// nearest existing parent, treats names differing only in case as the same,
+// as macOS and Windows do by default
+func caseInsensitiveDir(dir string) bool {
+	for !pathExists(dir) && filepath.Dir(dir) != dir {
+		dir = filepath.Dir(dir)
+	}
+	probe, err := os.CreateTemp(dir, ".wtree-case-")
+	if err != nil {
+		return false
+	}
+	probe.Close()
+	defer os.Remove(probe.Name())
+	_, err = os.Stat(filepath.Join(dir, strings.ToUpper(filepath.Base(probe.Name()))))
+	return err == nil
+}
+
+// caseConflict reports whether two slash-separated names clash on a
+// case-insensitive file system: some leading component differs only in case
+func caseConflict(a, b string) bool {
+	as, bs := strings.Split(a, "/"), strings.Split(b, "/")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		if as[i] == bs[i] {
+			continue
+		}
+		return strings.EqualFold(as[i], bs[i])
+	}
+	return false
+}
+
+// caseCollision returns the existing entry under parent that rel would land
+// on if case were ignored, or "" when there is none
+func caseCollision(parent, rel string) string {
+	dir := parent
+	for _, name := range strings.Split(filepath.ToSlash(rel), "/") {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return ""
+		}
+		found := ""
+		for _, entry := range entries {
+			if entry.Name() == name {
+				found = name
+				break
+			}
+			if strings.EqualFold(entry.Name(), name) {
+				return filepath.Join(dir, entry.Name())
+			}
+		}
+		if found == "" {
+			return ""
+		}
+		dir = filepath.Join(dir, found)
+	}
+	return ""
+}
+
+// avoidCaseCollision keeps a new worktree from landing in the directory of
+// another one whose name differs only in case (Feature-X and feature-x) on
+// a case-insensitive file system, by adding a hash of the branch name
+func (m *Manager) avoidCaseCollision(parent, path, branchName string) string {
+	rel, err := filepath.Rel(parent, path)
+	if err != nil {
+		return path
+	}
+	existing := caseCollision(parent, rel)
+	if existing == "" || !caseInsensitiveDir(parent) {
+		return path
+	}
+	sum := sha1.Sum([]byte(branchName))
+	distinct := path + "-" + hex.EncodeToString(sum[:])[:shortenHashChars]
+	m.warn("%s differs only in case from %s, which this file system treats as the same; using %s",
+		path, existing, distinct)
+	return distinct
+}
+
+// checkBranchCase refuses a new branch whose name differs only in case from
+// an existing one: on case-insensitive file systems their refs overwrite
+// each other. Elsewhere it only warns, since clones on macOS or Windows
+// would still clash.
+func (m *Manager) checkBranchCase(branchName string) error {
+	branches, err := m.repo.ListBranches()
+	if err != nil {
+		return nil
+	}
+	for _, existing := range branches {
+		if !caseConflict(existing, branchName) {
+			continue
+		}
+		repoRoot, _ := m.repo.GetRepoRoot()
+		if repoRoot != "" && !caseInsensitiveDir(repoRoot) {
+			m.warn("Branch '%s' differs only in case from '%s'; they will clash in clones on macOS and Windows",
+				branchName, existing)
+			return nil
+		}
+		return types.NewValidationError("create-branch",
+			fmt.Sprintf("branch '%s' differs only in case from existing branch '%s', and this file system doesn't tell them apart; use '%s' or another name",
+				branchName, existing, existing), nil)
+	}
+	return nil
+}
@@ -0,0 +1,29 @@
+package worktree
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCaseConflict(t *testing.T) {
+	assert.True(t, caseConflict("feature-x", "Feature-X"))
+	assert.True(t, caseConflict("team/one", "Team/two"), "the ref directories clash")
+	assert.False(t, caseConflict("feature-x", "feature-x"))
+	assert.False(t, caseConflict("team/one", "team/One2"))
+	assert.False(t, caseConflict("feature-x", "feature-y"))
+}
+
+func TestCaseCollision(t *testing.T) {
+	parent := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(parent, "repo-feature-x"), 0755))
+	require.NoError(t, os.MkdirAll(filepath.Join(parent, "repo-team", "one"), 0755))
+
+	assert.Equal(t, filepath.Join(parent, "repo-feature-x"), caseCollision(parent, "repo-Feature-X"))
+	assert.Equal(t, filepath.Join(parent, "repo-team", "one"), caseCollision(parent, "repo-team/ONE"))
+	assert.Empty(t, caseCollision(parent, "repo-feature-x"), "the same name is no collision")
+	assert.Empty(t, caseCollision(parent, "repo-other"))
+}
@@ -0,0 +1,57 @@
+package worktree
+
+import (
+	"fmt"
+)
+
+// addCandidateFacts looks up, in one batch, the merge base with mainBranch,
+// the commits ahead of it and the last commit of every candidate with a
+// branch. Candidates without an activity date get the last commit's.
+func (m *Manager) addCandidateFacts(candidates []CleanupCandidate, mainBranch string) {
+	if mainBranch == "" {
+		return
+	}
+	var branches []string
+	for _, candidate := range candidates {
+		if candidate.Branch != "" && candidate.Branch != mainBranch {
+			branches = append(branches, candidate.Branch)
+		}
+	}
+	facts, err := m.repo.BranchFacts(branches, mainBranch)
+	if err != nil {
+		m.warn("Could not compare candidates with %s: %v", mainBranch, err)
+		return
+	}
+	for i := range candidates {
+		fact, ok := facts[candidates[i].Branch]
+		if !ok {
+			continue
+		}
+		candidates[i].Facts = fact
+		if candidates[i].LastActivity == "N/A" && !fact.LastCommit.Time.IsZero() {
+			candidates[i].LastActivity = fmt.Sprintf("%s by %s", m.ui.Time(fact.LastCommit.Time), fact.LastCommit.Author)
+			candidates[i].LastActiveAt = fact.LastCommit.Time
+		}
+	}
+}
+
+// candidateAhead renders the commits a candidate has that the main branch
+// doesn't, for the candidate table
+func candidateAhead(candidate CleanupCandidate) string {
+	if candidate.Facts == nil {
+		return "-"
+	}
+	return fmt.Sprintf("%d", candidate.Facts.Ahead)
+}
+
+// candidateMergeBase renders when a candidate forked from the main branch,
+// e.g. "6 weeks ago", for the candidate table
+func (m *Manager) candidateMergeBase(candidate CleanupCandidate) string {
+	if candidate.Facts == nil {
+		return "-"
+	}
+	if candidate.Facts.MergeBase == "" || candidate.Facts.MergeBaseTime.IsZero() {
+		return "none"
+	}
+	return m.ui.Time(candidate.Facts.MergeBaseTime)
+}
@@ -0,0 +1,48 @@
+package worktree
+
+import (
+	"testing"
+	"time"
+
+	"github.com/awhite/wtree/internal/git"
+	"github.com/awhite/wtree/internal/ui"
+	"github.com/stretchr/testify/assert"
+)
+
+// factsMockRepo answers BranchFacts from a fixed table
+type factsMockRepo struct {
+	MockGitRepo
+	facts map[string]*git.BranchFacts
+}
+
+func (r *factsMockRepo) BranchFacts(branches []string, base string) (map[string]*git.BranchFacts, error) {
+	return r.facts, nil
+}
+
+func TestManager_addCandidateFacts(t *testing.T) {
+	now := time.Date(2026, 10, 14, 12, 0, 0, 0, time.UTC)
+	fact := &git.BranchFacts{
+		Branch:        "feature",
+		LastCommit:    git.CommitInfo{Author: "sam", Time: now.Add(-48 * time.Hour)},
+		MergeBase:     "abc123",
+		MergeBaseTime: now.Add(-10 * 24 * time.Hour),
+		Ahead:         3,
+	}
+	m := &Manager{repo: &factsMockRepo{facts: map[string]*git.BranchFacts{"feature": fact}}, ui: ui.NewManager(false, false)}
+	m.ui.SetAbsoluteTimes(true)
+
+	candidates := []CleanupCandidate{
+		{Branch: "feature", LastActivity: "N/A"},
+		{Branch: "detached@1234567", LastActivity: "N/A"},
+	}
+	m.addCandidateFacts(candidates, "main")
+
+	assert.Equal(t, "2026-10-12T12:00:00Z by sam", candidates[0].LastActivity)
+	assert.Equal(t, fact.LastCommit.Time, candidates[0].LastActiveAt)
+	assert.Equal(t, "3", candidateAhead(candidates[0]))
+	assert.Equal(t, "2026-10-04T12:00:00Z", m.candidateMergeBase(candidates[0]))
+
+	assert.Equal(t, "N/A", candidates[1].LastActivity)
+	assert.Equal(t, "-", candidateAhead(candidates[1]))
+	assert.Equal(t, "-", m.candidateMergeBase(candidates[1]))
+}
@@ -0,0 +1,168 @@
+package worktree
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+
+	"github.com/awhite/wtree/internal/git"
+	"github.com/awhite/wtree/pkg/types"
+)
+
+// compareTags labels both worktrees of a compare pair in list, e.g.
+// "compare-feature"; "compare" when the branch name can't be a tag
+func compareTags(branch string) []string {
+	if tags, err := NormalizeTags([]string{"compare-" + branch}); err == nil {
+		return append(tags, "compare")
+	}
+	return []string{"compare"}
+}
+
+// Compare prepares two worktrees for reviewing a branch by reading both
+// trees: the branch's own, and a detached one at its merge base with the
+// base branch, so the only differences are what the branch changed. It then
+// opens both in the editor as one workspace, or with Difftool runs git
+// difftool across them.
+func (m *Manager) Compare(branch string, options CompareOptions) error {
+	if !m.repo.BranchExists(branch) {
+		return types.NewValidationError("compare", fmt.Sprintf("branch '%s' does not exist", branch), nil)
+	}
+	base := options.Base
+	if base == "" {
+		var err error
+		if base, err = m.mainCheckoutBranch(); err != nil {
+			return err
+		}
+	}
+	if base == branch {
+		return types.NewValidationError("compare",
+			fmt.Sprintf("'%s' is the base branch; pass --base to compare it with another", branch), nil)
+	}
+
+	facts, err := m.repo.BranchFacts([]string{branch}, base)
+	if err != nil {
+		return err
+	}
+	fact := facts[branch]
+	if fact == nil || fact.MergeBase == "" {
+		return types.NewValidationError("compare",
+			fmt.Sprintf("'%s' and '%s' have no common history", branch, base), nil)
+	}
+	mergeBase := fact.MergeBase
+
+	basePath, err := m.generateWorktreePath(branch + "-base")
+	if err != nil {
+		return fmt.Errorf("failed to generate worktree path: %w", err)
+	}
+
+	if options.DryRun {
+		if existing := m.branchWorktree(branch); existing != nil {
+			m.ui.Info("[DRY RUN] Would use the worktree of '%s': %s", branch, existing.Path)
+		} else {
+			m.ui.Info("[DRY RUN] Would create a worktree for '%s'", branch)
+		}
+		m.ui.Info("[DRY RUN] Would check out %s (merge base with '%s') at %s", git.ShortHash(mergeBase), base, basePath)
+		return nil
+	}
+
+	wt := m.branchWorktree(branch)
+	if wt == nil {
+		if err := m.Create(branch, CreateOptions{}); err != nil {
+			return err
+		}
+		if wt = m.branchWorktree(branch); wt == nil {
+			return types.NewGitError("compare", fmt.Sprintf("worktree for '%s' not found after creating it", branch), nil)
+		}
+	}
+	if err := m.baseWorktree(basePath, mergeBase); err != nil {
+		return err
+	}
+
+	tags := compareTags(branch)
+	for _, path := range []string{wt.Path, basePath} {
+		merged, _ := NormalizeTags(append(worktreeTags(path), tags...))
+		if err := saveTags(path, merged); err != nil {
+			m.warn("Failed to label %s: %v", path, err)
+		}
+	}
+
+	m.completed("Comparing '%s' with %s (merge base with '%s')", branch, git.ShortHash(mergeBase), base)
+	m.ui.InfoIndented("Branch: %s", wt.Path)
+	m.ui.InfoIndented("Base:   %s", basePath)
+
+	switch {
+	case options.NoOpen:
+		return nil
+	case options.Difftool:
+		return m.runDifftool(basePath, wt.Path)
+	}
+	editor := m.configMgr.ResolveEditor(m.globalConfig, m.projectConfig)
+	m.ui.Info("Opening in %s: %s and %s", editor, basePath, wt.Path)
+	return m.executeEditorCommand(m.editorArgs(editor, basePath, wt.Path))
+}
+
+// baseWorktree makes the worktree at path a detached checkout of commit,
+// creating it or moving an existing one there
+func (m *Manager) baseWorktree(path, commit string) error {
+	worktrees, err := m.repo.ListWorktrees()
+	if err != nil {
+		return fmt.Errorf("failed to list worktrees: %w", err)
+	}
+	for _, wt := range worktrees {
+		if wt.Path != path {
+			continue
+		}
+		if !wt.Detached {
+			return types.NewValidationError("compare",
+				fmt.Sprintf("%s has branch '%s' checked out; remove it to compare there", path, wt.Branch), nil)
+		}
+		if wt.Head == commit {
+			return nil
+		}
+		if status, err := m.repo.GetWorktreeStatus(path); err == nil && !status.IsClean {
+			return types.NewValidationError("compare",
+				fmt.Sprintf("%s has uncommitted changes; commit or discard them first", path), nil)
+		}
+		return m.withGitLock(func() error { return m.repo.DetachHead(path, commit) })
+	}
+
+	if pathExists(path) {
+		return types.NewFileSystemError("compare", path,
+			fmt.Sprintf("%s already exists and is not a worktree; remove it first", path), nil)
+	}
+	m.ui.Info("Creating worktree at: %s", path)
+	if err := m.withGitLock(func() error { return m.repo.CreateWorktree(path, commit) }); err != nil {
+		return err
+	}
+	if err := recordCreation(path, ""); err != nil {
+		m.warn("Failed to record worktree metadata: %v", err)
+	}
+	return nil
+}
+
+// runDifftool runs git difftool over two directories, in the foreground.
+// Differences are the point, so git's exit status 1 for them is success.
+func (m *Manager) runDifftool(basePath, branchPath string) error {
+	args := []string{"git", "difftool", "--no-index", "--no-prompt", basePath, branchPath}
+	if m.printCommands {
+		fmt.Println(commandLine(args))
+		return nil
+	}
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	entry := auditEntry{Event: "launch", Command: args}
+	err := cmd.Run()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+		err = nil
+	}
+	if err != nil {
+		entry.Error = err.Error()
+	}
+	m.audit(entry)
+	return err
+}
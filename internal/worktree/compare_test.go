@@ -0,0 +1,22 @@
+package worktree
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompareTags(t *testing.T) {
+	assert.Equal(t, []string{"compare-feature", "compare"}, compareTags("feature"))
+	assert.Equal(t, []string{"compare"}, compareTags("bad name!"))
+}
+
+func TestManager_Compare_Validation(t *testing.T) {
+	m := &Manager{repo: &patchMockRepo{}}
+	err := m.Compare("feature", CompareOptions{})
+	assert.ErrorContains(t, err, "does not exist")
+
+	m = &Manager{repo: &MockGitRepo{}}
+	err = m.Compare("main", CompareOptions{Base: "main"})
+	assert.ErrorContains(t, err, "is the base branch")
+}
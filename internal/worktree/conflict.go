@@ -0,0 +1,52 @@
+package worktree
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+)
+
+// askFileConflict asks what to do with a copy or link destination that
+// already exists with different content, showing a diff on request. With no
+// terminal to ask on, the existing file is kept.
+func (m *Manager) askFileConflict(src, dst string) (string, error) {
+	if m.ui == nil || !stdinIsTerminal() {
+		m.warn("Keeping existing %s (differs from %s; see --on-conflict)", dst, src)
+		return OnConflictKeep, nil
+	}
+
+	for {
+		choice, err := m.ui.ConfirmWithOptions(
+			fmt.Sprintf("%s already exists and differs from %s:", dst, src),
+			map[string]string{
+				"k": "keep the existing file",
+				"o": "overwrite it",
+				"d": "show the differences",
+				"r": "rename it to .orig, then overwrite",
+			})
+		if err != nil {
+			m.warn("Keeping existing %s: %v", dst, err)
+			return OnConflictKeep, nil
+		}
+		if choice == "d" {
+			showFileDiff(dst, src)
+			continue
+		}
+		return map[string]string{"k": OnConflictKeep, "o": OnConflictOverwrite, "r": OnConflictRename}[choice], nil
+	}
+}
+
+// showFileDiff prints the differences between two files or directories
+func showFileDiff(from, to string) {
+	cmd := exec.Command("git", "diff", "--no-index", "--", from, to)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	// git diff exits 1 when the files differ
+	_ = cmd.Run()
+}
+
+// stdinIsTerminal reports whether standard input is an interactive terminal
+func stdinIsTerminal() bool {
+	info, err := os.Stdin.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,177 @@
+package worktree
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/awhite/wtree/pkg/types"
+)
+
+// createResult is the outcome of creating one worktree of a batch
+type createResult struct {
+	branch  string
+	existed bool // The branch already had a worktree
+	err     error
+}
+
+// MatchingBranches returns the local branches matching a glob pattern, e.g.
+// 'feature-*', sorted
+func (m *Manager) MatchingBranches(pattern string) ([]string, error) {
+	if _, err := path.Match(pattern, ""); err != nil {
+		return nil, types.NewValidationError("matching", fmt.Sprintf("invalid pattern '%s'", pattern), err)
+	}
+	branches, err := m.repo.ListBranches()
+	if err != nil {
+		return nil, err
+	}
+	var matches []string
+	for _, branch := range branches {
+		if ok, _ := path.Match(pattern, branch); ok {
+			matches = append(matches, branch)
+		}
+	}
+	sort.Strings(matches)
+	return matches, nil
+}
+
+// CreateMany creates worktrees for several branches at once, with up to
+// performance.max_concurrent_operations running together so their setup
+// hooks overlap. A row is printed as each finishes; failures don't stop
+// the others and are summarized at the end.
+func (m *Manager) CreateMany(branches []string, options CreateOptions) error {
+	seen := make(map[string]bool)
+	var unique []string
+	for _, branch := range branches {
+		if !seen[branch] {
+			seen[branch] = true
+			unique = append(unique, branch)
+		}
+	}
+	branches = unique
+	if len(branches) == 0 {
+		return types.NewValidationError("create-many", "no branches to create worktrees for", nil)
+	}
+	for _, branch := range branches {
+		if err := m.validateCreateOptions(branch, options); err != nil {
+			return fmt.Errorf("%s: %w", branch, err)
+		}
+	}
+	if err := m.checkBatchLimit(branches); err != nil {
+		return err
+	}
+
+	if options.DryRun {
+		for _, branch := range branches {
+			if existing := m.branchWorktree(branch); existing != nil {
+				m.ui.Info("[DRY RUN] Worktree for '%s' already exists: %s", branch, existing.Path)
+			} else {
+				m.ui.Info("[DRY RUN] Would create a worktree for '%s'", branch)
+			}
+		}
+		return nil
+	}
+
+	workers := 1
+	if m.globalConfig != nil && m.globalConfig.Performance.MaxConcurrentOps > 1 {
+		workers = m.globalConfig.Performance.MaxConcurrentOps
+	}
+	m.ui.Header("Creating %d worktrees (%d at a time)", len(branches), min(workers, len(branches)))
+
+	// Fetch once here rather than in every create at the same time
+	m.autoFetch()
+
+	results := make([]createResult, len(branches))
+	var mu sync.Mutex
+	finished := 0
+	forEachConcurrently(len(branches), workers, func(i int) {
+		// Each create rolls back on its own; interrupted batch creates are
+		// not replayable with 'wtree rollback', as plans are one per process.
+		// The file manager records each setup for its manifest, so every
+		// worker needs its own.
+		worker := m.quietCopy()
+		worker.rollback = NewRollbackManager(m.repo)
+		worker.fileManager = m.fileManager.Clone()
+		worker.timings = nil
+
+		existed := m.branchWorktree(branches[i]) != nil
+		endCreate := m.timings.Start("create " + branches[i])
+		err := worker.Create(branches[i], options)
+		endCreate()
+		results[i] = createResult{branch: branches[i], existed: existed, err: err}
+
+		mu.Lock()
+		defer mu.Unlock()
+		finished++
+		if err != nil {
+			m.ui.Error("[%d/%d] %s: %v", finished, len(branches), branches[i], err)
+		} else {
+			m.completed("[%d/%d] %s", finished, len(branches), branches[i])
+		}
+	})
+
+	m.ui.Header("Create Summary")
+	table := m.ui.NewTable()
+	table.SetHeaders("Branch", "Path", "Result")
+	var failed []string
+	for _, result := range results {
+		outcome, wtPath := "created", "-"
+		if wt := m.branchWorktree(result.branch); wt != nil {
+			wtPath = wt.Path
+		}
+		switch {
+		case result.err != nil:
+			outcome = "failed: " + result.err.Error()
+			failed = append(failed, result.branch)
+		case result.existed:
+			outcome = "already existed"
+		}
+		table.AddRow(result.branch, wtPath, outcome)
+	}
+	table.Render()
+
+	if len(failed) > 0 {
+		return fmt.Errorf("%d of %d worktrees failed: %s", len(failed), len(branches), strings.Join(failed, ", "))
+	}
+	m.completed("Created %d worktrees", len(branches))
+	return nil
+}
+
+// checkBatchLimit applies limits.max_worktrees to a whole batch up front,
+// since concurrent creates would each see room for one more
+func (m *Manager) checkBatchLimit(branches []string) error {
+	if m.globalConfig == nil || m.globalConfig.Limits.MaxWorktrees <= 0 {
+		return nil
+	}
+	worktrees, err := m.repo.ListWorktrees()
+	if err != nil {
+		return fmt.Errorf("failed to list worktrees: %w", err)
+	}
+	active, adding := 0, 0
+	existing := make(map[string]bool)
+	for _, wt := range worktrees {
+		if !wt.IsMainRepo {
+			active++
+			existing[wt.Branch] = true
+		}
+	}
+	for _, branch := range branches {
+		if !existing[branch] {
+			adding++
+		}
+	}
+
+	limit := m.globalConfig.Limits.MaxWorktrees
+	if active+adding <= limit {
+		return nil
+	}
+	msg := fmt.Sprintf("creating %d worktrees would make %d active (limit %d); run 'wtree cleanup' to remove stale worktrees",
+		adding, active+adding, limit)
+	if m.globalConfig.Limits.OnLimit == types.LimitModeBlock {
+		return types.NewValidationError("worktree-limit", msg, nil)
+	}
+	m.warn("%s", msg)
+	return nil
+}
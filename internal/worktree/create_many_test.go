@@ -0,0 +1,35 @@
+package worktree
+
+import (
+	"testing"
+
+	"github.com/awhite/wtree/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestManager_MatchingBranches(t *testing.T) {
+	m := &Manager{repo: &MockGitRepo{}}
+
+	matches, err := m.MatchingBranches("feature*")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"feature1", "feature2"}, matches)
+
+	_, err = m.MatchingBranches("[")
+	assert.Error(t, err)
+}
+
+func TestManager_checkBatchLimit(t *testing.T) {
+	repo := &stateMockRepo{worktrees: []*types.WorktreeInfo{
+		{Path: "/repo", Branch: "main", IsMainRepo: true},
+		{Path: "/repo-a", Branch: "a"},
+	}}
+	config := types.DefaultWTreeConfig()
+	config.Limits.MaxWorktrees = 3
+	config.Limits.OnLimit = types.LimitModeBlock
+	m := &Manager{repo: repo, globalConfig: config}
+
+	// a already has a worktree, so only b and c count
+	assert.NoError(t, m.checkBatchLimit([]string{"a", "b", "c"}))
+	assert.ErrorContains(t, m.checkBatchLimit([]string{"b", "c", "d"}), "limit 3")
+}
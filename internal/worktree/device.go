@@ -0,0 +1,141 @@
+package worktree
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/awhite/wtree/pkg/types"
+)
+
+// sameDevice reports whether a and b are on the same file system. The
+// nearest existing parent is checked for paths that don't exist yet, and
+// paths that can't be checked count as the same.
+func sameDevice(a, b string) bool {
+	idA, errA := deviceID(existingParent(a))
+	idB, errB := deviceID(existingParent(b))
+	return errA != nil || errB != nil || idA == idB
+}
+
+// existingParent returns path, or its nearest parent that exists
+func existingParent(path string) string {
+	for {
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+		parent := filepath.Dir(path)
+		if parent == path {
+			return path
+		}
+		path = parent
+	}
+}
+
+// crossDevicePolicy returns paths.cross_device, defaulting to copy
+func (m *Manager) crossDevicePolicy() string {
+	if m.globalConfig == nil || m.globalConfig.Paths.CrossDevice == "" {
+		return types.CrossDeviceCopy
+	}
+	return m.globalConfig.Paths.CrossDevice
+}
+
+// copyLinksAcrossDevices applies paths.cross_device to link_files when the
+// worktree is on another volume than the main checkout, where symlinks
+// would dangle whenever either volume is unmounted. It reports whether the
+// links should be copied instead.
+func (m *Manager) copyLinksAcrossDevices(repoRoot, worktreePath string) (bool, error) {
+	if sameDevice(repoRoot, worktreePath) {
+		return false, nil
+	}
+	switch m.crossDevicePolicy() {
+	case types.CrossDeviceAbort:
+		return false, types.NewFileSystemError("link-files", worktreePath,
+			fmt.Sprintf("the worktree is on a different volume than %s and paths.cross_device is abort", repoRoot), nil)
+	case types.CrossDeviceWarn:
+		m.warn("The worktree is on a different volume than %s; link_files will point across volumes", repoRoot)
+		return false, nil
+	default:
+		m.ui.Info("The worktree is on a different volume than %s; copying link_files instead of linking them", repoRoot)
+		return true, nil
+	}
+}
+
+// movePath renames src to dst, falling back to copying and removing src when
+// they are on different volumes, as allowed by paths.cross_device
+func (m *Manager) movePath(src, dst string) error {
+	err := os.Rename(src, dst)
+	if err == nil || !isCrossDevice(err) {
+		return err
+	}
+
+	switch m.crossDevicePolicy() {
+	case types.CrossDeviceAbort:
+		return fmt.Errorf("%s and %s are on different volumes and paths.cross_device is abort: %w", src, dst, err)
+	case types.CrossDeviceWarn:
+		m.warn("%s and %s are on different volumes; copying instead of moving", src, dst)
+	default:
+		m.ui.Info("Copying %s to another volume...", src)
+	}
+
+	if err := copyTree(src, dst); err != nil {
+		_ = os.RemoveAll(dst)
+		return fmt.Errorf("failed to copy across volumes: %w", err)
+	}
+	return os.RemoveAll(src)
+}
+
+// copyTree copies the directory src to dst as-is: symlinks are recreated,
+// not followed, and file modes are kept
+func copyTree(src, dst string) error {
+	return filepath.WalkDir(src, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case entry.IsDir():
+			return os.MkdirAll(target, info.Mode().Perm())
+		case info.Mode()&os.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(link, target)
+		case info.Mode().IsRegular():
+			return copyRegularFile(path, target, info.Mode().Perm())
+		default:
+			// Sockets, pipes and devices have no place in a worktree
+			return nil
+		}
+	})
+}
+
+// copyRegularFile copies one file's contents with the given mode
+func copyRegularFile(src, dst string, mode fs.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
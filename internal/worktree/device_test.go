@@ -0,0 +1,40 @@
+package worktree
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSameDevice(t *testing.T) {
+	dir := t.TempDir()
+	assert.True(t, sameDevice(dir, filepath.Join(dir, "not", "created", "yet")))
+}
+
+func TestCopyTree(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("symlinks need extra privileges on Windows")
+	}
+	src := filepath.Join(t.TempDir(), "src")
+	require.NoError(t, os.MkdirAll(filepath.Join(src, "bin"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(src, "bin", "run"), []byte("#!/bin/sh\n"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(src, ".env"), []byte("A=1\n"), 0600))
+	require.NoError(t, os.Symlink("bin/run", filepath.Join(src, "run")))
+
+	dst := filepath.Join(t.TempDir(), "dst")
+	require.NoError(t, copyTree(src, dst))
+
+	info, err := os.Stat(filepath.Join(dst, "bin", "run"))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0755), info.Mode().Perm())
+	data, err := os.ReadFile(filepath.Join(dst, ".env"))
+	require.NoError(t, err)
+	assert.Equal(t, "A=1\n", string(data))
+	link, err := os.Readlink(filepath.Join(dst, "run"))
+	require.NoError(t, err)
+	assert.Equal(t, "bin/run", link, "symlinks are recreated, not followed")
+}
@@ -0,0 +1,28 @@
+//go:build !windows
+
+package worktree
+
+import (
+	"errors"
+	"os"
+	"syscall"
+)
+
+// deviceID identifies the file system holding path
+func deviceID(path string) (uint64, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0, err
+	}
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return 0, errors.New("device information unavailable")
+	}
+	return uint64(stat.Dev), nil
+}
+
+// isCrossDevice reports whether err is a rename failing because source and
+// destination are on different file systems
+func isCrossDevice(err error) bool {
+	return errors.Is(err, syscall.EXDEV)
+}
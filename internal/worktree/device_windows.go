@@ -0,0 +1,33 @@
+//go:build windows
+
+package worktree
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"syscall"
+)
+
+// errorNotSameDevice is ERROR_NOT_SAME_DEVICE, returned by MoveFile
+const errorNotSameDevice = syscall.Errno(17)
+
+// deviceID identifies the volume holding path by its drive letter or UNC
+// share
+func deviceID(path string) (uint64, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return 0, err
+	}
+	var id uint64
+	for _, c := range strings.ToLower(filepath.VolumeName(abs)) {
+		id = id*31 + uint64(c)
+	}
+	return id, nil
+}
+
+// isCrossDevice reports whether err is a rename failing because source and
+// destination are on different volumes
+func isCrossDevice(err error) bool {
+	return errors.Is(err, errorNotSameDevice)
+}
@@ -0,0 +1,91 @@
+package worktree
+
+import (
+	"fmt"
+	"strings"
+)
+
+// markDirtyCandidates flags candidates with uncommitted changes and returns how many there are
+func (m *Manager) markDirtyCandidates(candidates []CleanupCandidate) int {
+	dirty := 0
+	for i := range candidates {
+		if !pathExists(candidates[i].Path) {
+			continue
+		}
+		status, err := m.repo.GetWorktreeStatus(candidates[i].Path)
+		if err == nil && status != nil && !status.IsClean {
+			candidates[i].Dirty = true
+			dirty++
+		}
+	}
+	return dirty
+}
+
+// showDirtyChanges prints the short status and diff stat of dirty candidates
+func (m *Manager) showDirtyChanges(candidates []CleanupCandidate) {
+	m.ui.Header("Uncommitted Changes")
+	for _, candidate := range candidates {
+		if !candidate.Dirty {
+			continue
+		}
+		m.warn("%s (%s)", candidate.Branch, candidate.Path)
+		changes, err := m.repo.DescribeChanges(candidate.Path)
+		if err != nil {
+			m.ui.InfoIndented("could not read changes: %v", err)
+			continue
+		}
+		for _, line := range strings.Split(changes, "\n") {
+			m.ui.InfoIndented("%s", line)
+		}
+	}
+}
+
+// resolveDirtyCandidates applies the on-dirty policy, asking when none was
+// given interactively. Auto mode defaults to skipping dirty worktrees so
+// unattended runs never discard work. Candidates that are skipped, or whose
+// stash fails, are dropped from the returned list.
+func (m *Manager) resolveDirtyCandidates(candidates []CleanupCandidate, dirty int, options CleanupOptions) []CleanupCandidate {
+	action := options.OnDirty
+	if action == "" {
+		action = OnDirtySkip
+		if !options.Auto {
+			choice, err := m.ui.ConfirmWithOptions(
+				fmt.Sprintf("%d cleanup candidates have uncommitted changes:", dirty),
+				map[string]string{
+					"k": "keep them (skip)",
+					"s": "stash changes, then remove",
+					"t": "move to the trash",
+					"f": "remove and discard changes",
+				})
+			if err == nil {
+				action = map[string]string{"k": OnDirtySkip, "s": OnDirtyStash, "t": OnDirtyTrash, "f": OnDirtyForce}[choice]
+			}
+		}
+	}
+
+	var kept []CleanupCandidate
+	for _, candidate := range candidates {
+		if !candidate.Dirty {
+			kept = append(kept, candidate)
+			continue
+		}
+
+		switch action {
+		case OnDirtySkip:
+			m.ui.Info("Skipping %s: uncommitted changes", candidate.Branch)
+			continue
+		case OnDirtyStash:
+			// Stashes live in the shared refs, so they survive the worktree
+			message := fmt.Sprintf("wtree cleanup: %s", candidate.Branch)
+			if err := m.repo.Stash(candidate.Path, message); err != nil {
+				m.warn("Skipping %s: %v", candidate.Branch, err)
+				continue
+			}
+			m.ui.Info("Stashed changes from %s (\"%s\")", candidate.Branch, message)
+		case OnDirtyTrash:
+			candidate.Trash = true
+		}
+		kept = append(kept, candidate)
+	}
+	return kept
+}
@@ -0,0 +1,43 @@
+package worktree
+
+import (
+	"testing"
+
+	"github.com/awhite/wtree/internal/ui"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestManager_resolveDirtyCandidates(t *testing.T) {
+	candidates := []CleanupCandidate{
+		{Branch: "clean", Path: "/wt/clean"},
+		{Branch: "wip", Path: "/wt/wip", Dirty: true},
+	}
+	m := &Manager{repo: &MockGitRepo{}, ui: ui.NewManager(false, false)}
+
+	tests := []struct {
+		name     string
+		options  CleanupOptions
+		branches []string
+		trashed  bool
+	}{
+		{"auto defaults to skip", CleanupOptions{Auto: true}, []string{"clean"}, false},
+		{"skip", CleanupOptions{OnDirty: OnDirtySkip}, []string{"clean"}, false},
+		{"stash", CleanupOptions{OnDirty: OnDirtyStash}, []string{"clean", "wip"}, false},
+		{"trash", CleanupOptions{OnDirty: OnDirtyTrash}, []string{"clean", "wip"}, true},
+		{"force", CleanupOptions{OnDirty: OnDirtyForce}, []string{"clean", "wip"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kept := m.resolveDirtyCandidates(candidates, 1, tt.options)
+			var branches []string
+			for _, candidate := range kept {
+				branches = append(branches, candidate.Branch)
+			}
+			assert.Equal(t, tt.branches, branches)
+			if len(kept) == 2 {
+				assert.Equal(t, tt.trashed, kept[1].Trash)
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package worktree
+
+import (
+	"context"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// editorStatusTimeout bounds asking a running editor which folders it has
+// open; the editor is opened normally when it doesn't answer in time
+const editorStatusTimeout = 5 * time.Second
+
+// windowedEditors are the VS Code family: they take --new-window and
+// --reuse-window, and report their open folders with --status
+var windowedEditors = map[string]bool{
+	"code":          true,
+	"code-insiders": true,
+	"codium":        true,
+	"cursor":        true,
+	"windsurf":      true,
+}
+
+// editorArgs builds the command opening paths in editor. An editor of the VS
+// Code family that already shows the single path is asked to focus that
+// window rather than open a duplicate, unless --new-window was given.
+func (m *Manager) editorArgs(editor string, paths ...string) []string {
+	if !windowedEditors[editor] {
+		return append([]string{editor}, paths...)
+	}
+	if m.newWindow {
+		return append([]string{editor, "--new-window"}, paths...)
+	}
+	if len(paths) == 1 && !m.printCommands && editorHasFolderOpen(editor, paths[0]) {
+		m.ui.Info("%s is already open in %s; focusing that window (use --new-window for another)", paths[0], editor)
+		return []string{editor, "--reuse-window", paths[0]}
+	}
+	return append([]string{editor}, paths...)
+}
+
+// editorHasFolderOpen asks a running editor, with --status, whether a window
+// shows the folder at path. Folders are reported by name only, so this is a
+// heuristic; no running editor, or one that doesn't answer, is false.
+func editorHasFolderOpen(editor, path string) bool {
+	if _, err := exec.LookPath(editor); err != nil {
+		return false
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), editorStatusTimeout)
+	defer cancel()
+	output, err := exec.CommandContext(ctx, editor, "--status").Output()
+	if err != nil {
+		return false
+	}
+	return statusShowsFolder(string(output), filepath.Base(path))
+}
+
+// statusShowsFolder reports whether editor --status output lists a folder
+// named name, in lines like "|    Folder (myapp-feature): 312 files"
+func statusShowsFolder(output, name string) bool {
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(strings.TrimLeft(line, "| "))
+		if strings.HasPrefix(line, "Folder ("+name+")") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,31 @@
+package worktree
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatusShowsFolder(t *testing.T) {
+	output := `Version:          Code 1.90.0
+|  Window (main.go — app-feature — Visual Studio Code)
+|    Folder (app-feature): 312 files
+|      File types: go(120) md(4)
+`
+	assert.True(t, statusShowsFolder(output, "app-feature"))
+	assert.False(t, statusShowsFolder(output, "app"))
+	assert.False(t, statusShowsFolder("", "app-feature"))
+}
+
+func TestManager_editorArgs(t *testing.T) {
+	m := &Manager{}
+	assert.Equal(t, []string{"vim", "/wt/a"}, m.editorArgs("vim", "/wt/a"))
+
+	// Printed commands don't depend on what is open right now
+	m.SetPrintCommands(true)
+	assert.Equal(t, []string{"code", "/wt/a"}, m.editorArgs("code", "/wt/a"))
+
+	m.SetNewWindow(true)
+	assert.Equal(t, []string{"code", "--new-window", "/wt/a", "/wt/b"}, m.editorArgs("code", "/wt/a", "/wt/b"))
+	assert.Equal(t, []string{"vim", "/wt/a"}, m.editorArgs("vim", "/wt/a"))
+}
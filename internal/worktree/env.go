@@ -0,0 +1,88 @@
+package worktree
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/awhite/wtree/pkg/types"
+)
+
+// Formats for WriteEnv
+const (
+	EnvFormatShell  = "shell"
+	EnvFormatJSON   = "json"
+	EnvFormatDotenv = "dotenv"
+)
+
+// Env returns the WTREE_* variables hooks would receive for a worktree, the
+// current one when identifier is empty. Variables without a value are left out.
+func (m *Manager) Env(identifier string) (map[string]string, error) {
+	var wt *types.WorktreeInfo
+	var err error
+	if identifier == "" {
+		wt, err = m.currentWorktree()
+	} else {
+		wt, err = m.resolveWorktree(identifier)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := m.buildHookContext("", wt.Branch, wt.Path)
+	pm := &PRManager{Manager: m}
+	if prInfo, err := pm.loadPRMetadata(wt.Path); err == nil {
+		ctx.TargetBranch = prInfo.BaseRef
+		setPREnvironment(ctx.Environment, prInfo)
+	} else if prNumber := pm.extractPRNumber(wt.Path, m.repo.GetRepoName()); prNumber > 0 {
+		ctx.Environment["WTREE_PR_NUMBER"] = strconv.Itoa(prNumber)
+	}
+
+	env := hookEnvironment(ctx)
+	for key, value := range env {
+		if value == "" {
+			delete(env, key)
+		}
+	}
+	return env, nil
+}
+
+// WriteEnv prints variables sorted by name as shell exports, a JSON object
+// or a dotenv file
+func WriteEnv(w io.Writer, env map[string]string, format string) error {
+	keys := make([]string, 0, len(env))
+	for key := range env {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	switch format {
+	case EnvFormatShell, "":
+		for _, key := range keys {
+			fmt.Fprintf(w, "export %s=%s\n", key, shellescape(env[key]))
+		}
+	case EnvFormatDotenv:
+		for _, key := range keys {
+			fmt.Fprintf(w, "%s=%s\n", key, dotenvQuote(env[key]))
+		}
+	case EnvFormatJSON:
+		data, err := json.MarshalIndent(env, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(w, string(data))
+	default:
+		return types.NewValidationError("env",
+			fmt.Sprintf("unknown format '%s' (expected shell, json or dotenv)", format), nil)
+	}
+	return nil
+}
+
+// dotenvQuote double-quotes a value the way dotenv parsers read it back
+func dotenvQuote(value string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "$", `\$`)
+	return `"` + replacer.Replace(value) + `"`
+}
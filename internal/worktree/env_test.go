@@ -0,0 +1,30 @@
+package worktree
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteEnv(t *testing.T) {
+	env := map[string]string{
+		"WTREE_BRANCH":   "feature",
+		"WTREE_PR_TITLE": `Fix "it's" $HOME`,
+	}
+
+	var out bytes.Buffer
+	require.NoError(t, WriteEnv(&out, env, EnvFormatShell))
+	assert.Equal(t, "export WTREE_BRANCH='feature'\nexport WTREE_PR_TITLE='Fix \"it'\"'\"'s\" $HOME'\n", out.String())
+
+	out.Reset()
+	require.NoError(t, WriteEnv(&out, env, EnvFormatDotenv))
+	assert.Equal(t, "WTREE_BRANCH=\"feature\"\nWTREE_PR_TITLE=\"Fix \\\"it's\\\" \\$HOME\"\n", out.String())
+
+	out.Reset()
+	require.NoError(t, WriteEnv(&out, env, EnvFormatJSON))
+	assert.JSONEq(t, `{"WTREE_BRANCH": "feature", "WTREE_PR_TITLE": "Fix \"it's\" $HOME"}`, out.String())
+
+	assert.Error(t, WriteEnv(&out, env, "yaml"))
+}
@@ -0,0 +1,155 @@
+package worktree
+
+import (
+	"fmt"
+
+	"github.com/awhite/wtree/internal/ui"
+)
+
+// Observer receives what the manager reports while it works, so frontends
+// other than the CLI (JSON output, a TUI, an HTTP server) can present it.
+// Observers may be called from several goroutines at once.
+type Observer interface {
+	// ProgressStarted reports a step that is starting
+	ProgressStarted(message string)
+	// StepCompleted reports a step that finished successfully
+	StepCompleted(message string)
+	// Warning reports a problem that does not stop the operation
+	Warning(message string)
+	// NeedsConfirmation asks before a destructive step, returning an error
+	// to decline it. always approves future requests for the same operation
+	// in this repository too.
+	NeedsConfirmation(request Confirmation) (always bool, err error)
+}
+
+// Confirmation is a question the manager needs answered before continuing
+type Confirmation struct {
+	Message   string
+	Operation string // When set, the answer may be "always" for this operation
+	Phrase    string // When set, the answer must be this exact phrase
+}
+
+// CLIObserver renders events to the terminal through a ui.Manager
+type CLIObserver struct {
+	ui *ui.Manager
+}
+
+// NewCLIObserver creates an observer that prints to the terminal
+func NewCLIObserver(ui *ui.Manager) *CLIObserver {
+	return &CLIObserver{ui: ui}
+}
+
+// ProgressStarted prints an in-progress line
+func (o *CLIObserver) ProgressStarted(message string) {
+	o.ui.Progress("%s", message)
+}
+
+// StepCompleted prints a success line
+func (o *CLIObserver) StepCompleted(message string) {
+	o.ui.Success("%s", message)
+}
+
+// Warning prints a warning line
+func (o *CLIObserver) Warning(message string) {
+	o.ui.Warning("%s", message)
+}
+
+// NeedsConfirmation prompts on the terminal
+func (o *CLIObserver) NeedsConfirmation(request Confirmation) (bool, error) {
+	switch {
+	case request.Phrase != "":
+		return false, o.ui.ConfirmTyped(request.Message, request.Phrase)
+	case request.Operation != "":
+		return o.ui.ConfirmAlways(request.Message)
+	default:
+		return false, o.ui.Confirm(request.Message)
+	}
+}
+
+// Subscribe adds an observer that receives the manager's events alongside
+// the terminal output
+func (m *Manager) Subscribe(observer Observer) {
+	m.observers = append(m.observers, observer)
+}
+
+// progress announces a step that is starting
+func (m *Manager) progress(format string, args ...interface{}) {
+	message := fmt.Sprintf(format, args...)
+	for _, observer := range m.observers {
+		observer.ProgressStarted(message)
+	}
+}
+
+// completed announces a step that finished successfully
+func (m *Manager) completed(format string, args ...interface{}) {
+	message := fmt.Sprintf(format, args...)
+	for _, observer := range m.observers {
+		observer.StepCompleted(message)
+	}
+}
+
+// warn reports a problem that does not stop the operation
+func (m *Manager) warn(format string, args ...interface{}) {
+	message := fmt.Sprintf(format, args...)
+	for _, observer := range m.observers {
+		observer.Warning(message)
+	}
+}
+
+// confirm asks every observer to approve message; any one declining, or
+// there being no one to ask, cancels the operation. When every observer
+// answers "always", operation is approved from then on in this repository.
+func (m *Manager) confirm(operation, message string) error {
+	if m.alwaysConfirmed(operation) {
+		m.completed("%s yes (always for this repository; reset with 'wtree config trust --reset')", message)
+		return nil
+	}
+	return m.askConfirmation(Confirmation{Message: message, Operation: operation})
+}
+
+// confirmTyped is confirm where the answer must be phrase
+func (m *Manager) confirmTyped(message, phrase string) error {
+	return m.askConfirmation(Confirmation{Message: message, Phrase: phrase})
+}
+
+func (m *Manager) askConfirmation(request Confirmation) error {
+	if len(m.observers) == 0 {
+		return fmt.Errorf("operation cancelled: confirmation needed for %q", request.Message)
+	}
+	always := request.Operation != ""
+	for _, observer := range m.observers {
+		answeredAlways, err := observer.NeedsConfirmation(request)
+		if err != nil {
+			return err
+		}
+		always = always && answeredAlways
+	}
+	if always {
+		if err := m.rememberConfirmation(request.Operation); err != nil {
+			m.warn("Failed to remember the answer: %v", err)
+		}
+	}
+	return nil
+}
+
+// quietCopy returns a copy of the manager whose terminal output shows only
+// errors, for work whose progress is reported elsewhere. Other observers
+// keep receiving its events.
+func (m *Manager) quietCopy() *Manager {
+	return m.withUI(m.ui.Quiet())
+}
+
+// withUI returns a copy of the manager whose terminal output goes through
+// u, for its messages and for its CLI observers alike
+func (m *Manager) withUI(u *ui.Manager) *Manager {
+	copied := *m
+	copied.ui = u
+	copied.observers = make([]Observer, len(m.observers))
+	for i, observer := range m.observers {
+		if _, ok := observer.(*CLIObserver); ok {
+			observer = NewCLIObserver(u)
+		}
+		copied.observers[i] = observer
+	}
+	return &copied
+}
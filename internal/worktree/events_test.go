@@ -0,0 +1,70 @@
+package worktree
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/awhite/wtree/internal/ui"
+)
+
+// recordingObserver collects events for tests
+type recordingObserver struct {
+	events  []string
+	decline bool
+	always  bool
+}
+
+func (r *recordingObserver) ProgressStarted(message string) {
+	r.events = append(r.events, "progress: "+message)
+}
+func (r *recordingObserver) StepCompleted(message string) {
+	r.events = append(r.events, "completed: "+message)
+}
+func (r *recordingObserver) Warning(message string) { r.events = append(r.events, "warning: "+message) }
+func (r *recordingObserver) NeedsConfirmation(request Confirmation) (bool, error) {
+	r.events = append(r.events, "confirm: "+request.Message)
+	if r.decline {
+		return false, errors.New("declined")
+	}
+	return r.always, nil
+}
+
+func TestManager_events(t *testing.T) {
+	recorder := &recordingObserver{}
+	m := &Manager{}
+	m.Subscribe(recorder)
+
+	m.progress("Copying %d files", 2)
+	m.completed("Worktree %s ready", "feature")
+	m.warn("Hook failed")
+	assert.NoError(t, m.confirm("", "Delete feature?"))
+
+	assert.Equal(t, []string{
+		"progress: Copying 2 files",
+		"completed: Worktree feature ready",
+		"warning: Hook failed",
+		"confirm: Delete feature?",
+	}, recorder.events)
+
+	recorder.decline = true
+	assert.Error(t, m.confirmTyped("Type 'steal'", "steal"))
+}
+
+func TestManager_confirmWithoutObservers(t *testing.T) {
+	assert.Error(t, (&Manager{}).confirm("", "Delete feature?"))
+}
+
+func TestManager_quietCopyKeepsObservers(t *testing.T) {
+	recorder := &recordingObserver{}
+	m := NewManager(&MockGitRepo{}, nil, ui.NewManager(false, false))
+	m.Subscribe(recorder)
+
+	quiet := m.quietCopy()
+	quiet.warn("skipped")
+
+	assert.Len(t, quiet.observers, 2)
+	assert.NotSame(t, m.observers[0], quiet.observers[0], "terminal output goes through the quiet ui")
+	assert.Equal(t, []string{"warning: skipped"}, recorder.events)
+}
@@ -0,0 +1,78 @@
+package worktree
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/awhite/wtree/pkg/types"
+)
+
+// CreateExec creates a worktree, runs a shell command in it once setup is
+// done and, with Remove, deletes it again. The command's output is streamed
+// and a non-zero exit is returned as a types.ExitCodeError.
+func (m *Manager) CreateExec(branch string, options ExecOptions) error {
+	if strings.TrimSpace(options.Command) == "" {
+		return types.NewValidationError("exec", "a command to run is required", nil)
+	}
+	existed := m.branchWorktree(branch) != nil
+
+	if err := m.Create(branch, options.Create); err != nil {
+		return err
+	}
+	if options.Create.DryRun {
+		m.ui.Info("[DRY RUN] Would run in the worktree: %s", options.Command)
+		if options.Remove && !existed {
+			m.ui.Info("[DRY RUN] Would delete the worktree afterwards")
+		}
+		return nil
+	}
+
+	wt := m.branchWorktree(branch)
+	if wt == nil {
+		return types.NewValidationError("exec",
+			fmt.Sprintf("no worktree for '%s' to run the command in", branch), nil)
+	}
+
+	m.ui.Info("Running in %s: %s", wt.Path, options.Command)
+	runErr := m.runInWorktree(wt, options.Command)
+	if runErr == nil {
+		m.completed("Command succeeded: %s", options.Command)
+	}
+
+	if options.Remove {
+		if existed {
+			m.warn("Keeping %s: it existed before this run", wt.Path)
+		} else if err := m.Delete(wt.Path, DeleteOptions{Yes: true, IgnoreDirty: true, Permanent: true}); err != nil {
+			m.warn("Failed to delete the worktree: %v", err)
+		}
+	}
+	return runErr
+}
+
+// runInWorktree runs command with sh in a worktree, attached to the
+// terminal and with the WTREE_* variables hooks receive
+func (m *Manager) runInWorktree(wt *types.WorktreeInfo, command string) error {
+	ctx := m.buildHookContext("", wt.Branch, wt.Path)
+	cmd := exec.Command("sh", "-c", command)
+	cmd.Dir = wt.Path
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.Env = os.Environ()
+	for key, value := range hookEnvironment(ctx) {
+		cmd.Env = append(cmd.Env, key+"="+value)
+	}
+
+	err := cmd.Run()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return &types.ExitCodeError{Command: command, Code: exitErr.ExitCode()}
+	}
+	if err != nil {
+		return fmt.Errorf("failed to run '%s': %w", command, err)
+	}
+	return nil
+}
@@ -0,0 +1,27 @@
+package worktree
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/awhite/wtree/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestManager_runInWorktree(t *testing.T) {
+	path := t.TempDir()
+	m := &Manager{repo: &MockGitRepo{}}
+	wt := &types.WorktreeInfo{Path: path, Branch: "feature"}
+
+	require.NoError(t, m.runInWorktree(wt, `echo "$WTREE_BRANCH" > branch.txt`))
+	data, err := os.ReadFile(filepath.Join(path, "branch.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "feature\n", string(data))
+
+	err = m.runInWorktree(wt, "exit 3")
+	assert.Equal(t, 3, types.ExitCode(err))
+
+	assert.Error(t, m.CreateExec("feature", ExecOptions{Command: " "}))
+}
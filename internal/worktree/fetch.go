@@ -0,0 +1,105 @@
+package worktree
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/awhite/wtree/pkg/types"
+)
+
+// fetchMarkerPath returns the file whose modification time records the last
+// successful auto-fetch. FETCH_HEAD can't be used: git rewrites it even when
+// the fetch fails.
+func (m *Manager) fetchMarkerPath() (string, error) {
+	commonDir, err := m.repo.GetGitCommonDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(commonDir, "wtree", "last-fetch"), nil
+}
+
+// lastFetch returns when the repository last fetched successfully
+func (m *Manager) lastFetch() (time.Time, bool) {
+	path, err := m.fetchMarkerPath()
+	if err != nil {
+		return time.Time{}, false
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return info.ModTime(), true
+}
+
+// recordFetch marks the remote data as fetched now
+func (m *Manager) recordFetch() error {
+	path, err := m.fetchMarkerPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return types.NewFileSystemError("fetch", path, "failed to create the wtree directory", err)
+	}
+	if err := os.WriteFile(path, []byte(time.Now().Format(time.RFC3339)+"\n"), 0644); err != nil {
+		return types.NewFileSystemError("fetch", path, "failed to record the fetch", err)
+	}
+	return nil
+}
+
+// fetchRemote is the remote fetch.auto fetches
+func (m *Manager) fetchRemote() string {
+	if m.globalConfig != nil && m.globalConfig.Fetch.Remote != "" {
+		return m.globalConfig.Fetch.Remote
+	}
+	return m.defaultRemote()
+}
+
+// autoFetch fetches the remote when fetch.auto is set and the last fetch is
+// older than fetch.min_interval. A failed fetch only warns: stale data is
+// better than no command. Read-only mode never fetches; commands that fetch
+// first, such as status, only run read-only without fetch.auto.
+func (m *Manager) autoFetch() {
+	if m.readOnly || m.globalConfig == nil || !m.globalConfig.Fetch.Auto {
+		return
+	}
+	if last, ok := m.lastFetch(); ok && time.Since(last) < m.globalConfig.Fetch.MinInterval {
+		return
+	}
+
+	remote := m.fetchRemote()
+	m.progress("Fetching %s", remote)
+	if err := m.repo.Fetch(remote); err != nil {
+		m.warn("Auto-fetch from %s failed: %v", remote, err)
+		return
+	}
+	if err := m.recordFetch(); err != nil {
+		m.warn("Failed to record the fetch: %v", err)
+	}
+}
+
+// remoteFreshness describes how old the remote-tracking data is, e.g.
+// "remote data 3m old"
+func (m *Manager) remoteFreshness() string {
+	last, ok := m.lastFetch()
+	if !ok {
+		return "remote data never fetched"
+	}
+	return fmt.Sprintf("remote data %s old", formatAge(time.Since(last)))
+}
+
+// formatAge renders a duration coarsely for display, e.g. "45s", "3m", "2h"
+// or "5d"
+func formatAge(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd", int(d.Hours()/24))
+	}
+}
@@ -0,0 +1,71 @@
+package worktree
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/awhite/wtree/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatAge(t *testing.T) {
+	assert.Equal(t, "45s", formatAge(45*time.Second))
+	assert.Equal(t, "3m", formatAge(3*time.Minute+20*time.Second))
+	assert.Equal(t, "2h", formatAge(150*time.Minute))
+	assert.Equal(t, "5d", formatAge(5*24*time.Hour+time.Hour))
+}
+
+type fetchMockRepo struct {
+	MockGitRepo
+	commonDir string
+	err       error
+	fetches   int
+}
+
+func (r *fetchMockRepo) GetGitCommonDir() (string, error) { return r.commonDir, nil }
+func (r *fetchMockRepo) Fetch(remote string, refspec ...string) error {
+	r.fetches++
+	return r.err
+}
+
+func newFetchManager(repo *fetchMockRepo) *Manager {
+	config := types.DefaultWTreeConfig()
+	config.Fetch.Auto = true
+	config.Fetch.MinInterval = time.Hour
+	return &Manager{repo: repo, globalConfig: config}
+}
+
+func TestAutoFetch_RecordsOnlySuccessfulFetches(t *testing.T) {
+	repo := &fetchMockRepo{commonDir: t.TempDir(), err: errors.New("network down")}
+	m := newFetchManager(repo)
+
+	m.autoFetch()
+	assert.Equal(t, 1, repo.fetches)
+	_, ok := m.lastFetch()
+	assert.False(t, ok, "a failed fetch should not count as fresh")
+
+	// Still stale, so the next command tries again
+	repo.err = nil
+	m.autoFetch()
+	assert.Equal(t, 2, repo.fetches)
+	last, ok := m.lastFetch()
+	require.True(t, ok)
+	assert.WithinDuration(t, time.Now(), last, time.Minute)
+
+	// Fresh now, so it's skipped within the interval
+	m.autoFetch()
+	assert.Equal(t, 2, repo.fetches)
+}
+
+func TestAutoFetch_SkippedWhenReadOnly(t *testing.T) {
+	repo := &fetchMockRepo{commonDir: t.TempDir()}
+	m := newFetchManager(repo)
+	m.SetReadOnly()
+
+	m.autoFetch()
+	assert.Zero(t, repo.fetches)
+	assert.NoDirExists(t, filepath.Join(repo.commonDir, "wtree"))
+}
@@ -1,6 +1,7 @@
 package worktree
 
 import (
+	"bytes"
 	"fmt"
 	"io"
 	"log"
@@ -14,7 +15,62 @@ import (
 // FileManager handles generic file operations for worktrees
 type FileManager struct {
 	verbose         bool
-	allowedBasePath string // Base path that operations are restricted to
+	allowedBasePath string   // Base path that operations are restricted to
+	allowedRoots    []string // Additional canonical roots permitted alongside the base path
+	stats           FileStats
+	operations      []FileOperation
+	placed          map[string]FileOperation // Destinations wtree wrote, by path, kept across ResetStats
+	onConflict      string                   // OnConflict* policy for destinations that already exist
+	resolver        ConflictResolver         // Asked per file when the policy is "ask"
+	batched         bool                     // Copy for a network file system (see SetBatched)
+}
+
+// How copies and links treat a destination that already exists with
+// different content. One wtree placed itself and nobody changed since is
+// replaced without asking: it is a stale copy, not a conflict.
+const (
+	OnConflictAsk       = "ask"       // Ask per file, keeping it when there is no one to ask
+	OnConflictKeep      = "keep"      // Leave the existing file in place
+	OnConflictOverwrite = "overwrite" // Replace the existing file
+	OnConflictRename    = "rename"    // Move the existing file aside to <name>.orig, then replace it
+)
+
+// ConflictResolver chooses keep, overwrite or rename for a destination that
+// already exists and differs from src
+type ConflictResolver func(src, dst string) (string, error)
+
+// FileOperation records a single copy or link performed (or found up to date)
+type FileOperation struct {
+	Action      string `json:"action"` // "copy" or "link"
+	Source      string `json:"source"`
+	Destination string `json:"destination"`
+	Unchanged   bool   `json:"unchanged,omitempty"`
+	ModTime     int64  `json:"mod_time,omitempty"` // Modification time given to a copy, in Unix nanoseconds
+}
+
+// FileStats counts the outcome of file operations since the last ResetStats
+type FileStats struct {
+	Copied    int // Files copied
+	Linked    int // Links created
+	Unchanged int // Files or links already up to date at the destination
+	Kept      int // Differing files left in place by the conflict policy
+	Renamed   int // Differing files moved aside to <name>.orig
+}
+
+// String formats the stats for display, e.g. "42 copied, 310 unchanged"
+func (s FileStats) String() string {
+	parts := []string{fmt.Sprintf("%d copied", s.Copied)}
+	if s.Linked > 0 {
+		parts = append(parts, fmt.Sprintf("%d linked", s.Linked))
+	}
+	parts = append(parts, fmt.Sprintf("%d unchanged", s.Unchanged))
+	if s.Kept > 0 {
+		parts = append(parts, fmt.Sprintf("%d kept", s.Kept))
+	}
+	if s.Renamed > 0 {
+		parts = append(parts, fmt.Sprintf("%d renamed to .orig", s.Renamed))
+	}
+	return strings.Join(parts, ", ")
 }
 
 // NewFileManager creates a new file manager
@@ -22,24 +78,144 @@ func NewFileManager(verbose bool) *FileManager {
 	return &FileManager{verbose: verbose}
 }
 
+// Clone returns a file manager with the same restrictions and settings but
+// nothing recorded, for setting up another worktree at the same time
+func (fm *FileManager) Clone() *FileManager {
+	return &FileManager{
+		verbose:         fm.verbose,
+		allowedBasePath: fm.allowedBasePath,
+		allowedRoots:    append([]string(nil), fm.allowedRoots...),
+		onConflict:      fm.onConflict,
+		resolver:        fm.resolver,
+		batched:         fm.batched,
+	}
+}
+
+// ResetStats clears the file operation counters and recorded operations
+func (fm *FileManager) ResetStats() {
+	fm.stats = FileStats{}
+	fm.operations = nil
+}
+
+// Stats returns the file operation counters since the last ResetStats
+func (fm *FileManager) Stats() FileStats {
+	return fm.stats
+}
+
+// Operations returns the file operations recorded since the last ResetStats
+func (fm *FileManager) Operations() []FileOperation {
+	return fm.operations
+}
+
+// SetConflictPolicy sets how existing destinations that differ are handled.
+// An empty policy means ask; resolver may be nil, in which case ask keeps.
+func (fm *FileManager) SetConflictPolicy(policy string, resolver ConflictResolver) {
+	fm.onConflict = policy
+	fm.resolver = resolver
+}
+
+// SetPreviousOperations remembers what an earlier setup of the same worktree
+// placed, so its untouched copies and links are updated rather than treated
+// as conflicts
+func (fm *FileManager) SetPreviousOperations(operations []FileOperation) {
+	for _, op := range operations {
+		if _, ok := fm.placed[op.Destination]; !ok {
+			fm.remember(op)
+		}
+	}
+}
+
+// remember notes a destination as placed by wtree
+func (fm *FileManager) remember(op FileOperation) {
+	if fm.placed == nil {
+		fm.placed = make(map[string]FileOperation)
+	}
+	fm.placed[op.Destination] = op
+}
+
+// ownsDestination reports whether dst is still exactly as wtree placed it.
+// Copies recorded before modification times were kept count as untouched.
+func (fm *FileManager) ownsDestination(dst string) bool {
+	op, ok := fm.placed[dst]
+	if !ok {
+		return false
+	}
+	info, err := os.Lstat(dst)
+	if err != nil {
+		return false
+	}
+	if op.Action == "link" {
+		return info.Mode()&os.ModeSymlink != 0
+	}
+	return info.Mode().IsRegular() && (op.ModTime == 0 || info.ModTime().UnixNano() == op.ModTime)
+}
+
+// record notes a copy or link and updates the counters
+func (fm *FileManager) record(action, src, dst string, unchanged bool) {
+	op := FileOperation{Action: action, Source: src, Destination: dst, Unchanged: unchanged}
+	if info, err := os.Lstat(dst); err == nil && action == "copy" {
+		op.ModTime = info.ModTime().UnixNano()
+	}
+	fm.operations = append(fm.operations, op)
+	fm.remember(op)
+	switch {
+	case unchanged:
+		fm.stats.Unchanged++
+	case action == "link":
+		fm.stats.Linked++
+	default:
+		fm.stats.Copied++
+	}
+}
+
 // SetBasePath sets the base directory that all file operations must be within
 func (fm *FileManager) SetBasePath(basePath string) error {
-	abs, err := filepath.Abs(basePath)
+	canonical, err := canonicalPath(basePath)
 	if err != nil {
 		return fmt.Errorf("failed to resolve base path: %w", err)
 	}
 
-	// Resolve symlinks in the base path to get canonical path
-	canonical, err := filepath.EvalSymlinks(abs)
+	fm.allowedBasePath = canonical
+	return nil
+}
+
+// AddAllowedRoot permits file operations to resolve into an additional
+// directory, such as a shared cache that link targets point into
+func (fm *FileManager) AddAllowedRoot(root string) error {
+	canonical, err := canonicalPath(root)
 	if err != nil {
-		log.Printf("Warning: Could not resolve symlinks in base path %s: %v", abs, err)
-		canonical = abs // Use absolute path if symlink resolution fails
+		return fmt.Errorf("failed to resolve allowed root: %w", err)
 	}
 
-	fm.allowedBasePath = canonical
+	fm.allowedRoots = append(fm.allowedRoots, canonical)
 	return nil
 }
 
+// canonicalPath makes a path absolute and resolves symlinks in its longest
+// existing prefix, so that existing and not-yet-created paths under a
+// symlinked directory (e.g. macOS /tmp -> /private/tmp) compare consistently
+func canonicalPath(path string) (string, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+
+	existing := filepath.Clean(abs)
+	var rest []string
+	for {
+		if resolved, err := filepath.EvalSymlinks(existing); err == nil {
+			return filepath.Join(append([]string{resolved}, rest...)...), nil
+		}
+
+		parent := filepath.Dir(existing)
+		if parent == existing {
+			return filepath.Clean(abs), nil
+		}
+		rest = append([]string{filepath.Base(existing)}, rest...)
+		existing = parent
+	}
+}
+
 // CopyFiles copies files matching the specified patterns from source to destination
 func (fm *FileManager) CopyFiles(patterns []string, srcDir, dstDir string, ignorePatterns []string) error {
 	var errs []error
@@ -74,6 +250,84 @@ func (fm *FileManager) LinkFiles(patterns []string, srcDir, dstDir string, ignor
 	return nil
 }
 
+// CopyMappings copies each mapping's source path in srcDir to its destination path in dstDir
+func (fm *FileManager) CopyMappings(mappings []types.FileEntry, srcDir, dstDir string) error {
+	return fm.applyMappings(mappings, srcDir, dstDir, false)
+}
+
+// LinkMappings symlinks each mapping's destination path in dstDir to its source path in srcDir
+func (fm *FileManager) LinkMappings(mappings []types.FileEntry, srcDir, dstDir string) error {
+	return fm.applyMappings(mappings, srcDir, dstDir, true)
+}
+
+func (fm *FileManager) applyMappings(mappings []types.FileEntry, srcDir, dstDir string, link bool) error {
+	operation := "copy"
+	if link {
+		operation = "link"
+	}
+
+	var errs []error
+	for _, mapping := range mappings {
+		if err := fm.applyMapping(mapping, srcDir, dstDir, operation); err != nil {
+			errs = append(errs, fmt.Errorf("%s %s -> %s: %w", operation, mapping.From, mapping.To, err))
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("file %s errors: %v", operation, errs)
+	}
+
+	return nil
+}
+
+func (fm *FileManager) applyMapping(mapping types.FileEntry, srcDir, dstDir, operation string) error {
+	srcPath := filepath.Join(srcDir, mapping.From)
+	dstPath := filepath.Join(dstDir, mapping.To)
+
+	// Skip if source doesn't exist, like unmatched patterns
+	if _, err := os.Lstat(srcPath); os.IsNotExist(err) {
+		if fm.verbose {
+			fmt.Printf("    Source not found: %s\n", mapping.From)
+		}
+		return nil
+	}
+
+	// Security validation: Check for symlinks and path boundaries
+	if err := fm.validatePathSecurity(srcPath, operation); err != nil {
+		log.Printf("Security violation blocked %s operation: %v", operation, err)
+		return fmt.Errorf("security check failed for %s: %w", srcPath, err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dstPath), 0755); err != nil {
+		return fmt.Errorf("failed to create directory for %s: %w", dstPath, err)
+	}
+
+	if operation == "link" {
+		if linkMatches(dstPath, srcPath) {
+			fm.record("link", srcPath, dstPath, true)
+			return nil
+		}
+		if proceed, err := fm.resolveConflict(srcPath, dstPath); err != nil || !proceed {
+			return err
+		}
+		if err := os.Symlink(srcPath, dstPath); err != nil {
+			return fmt.Errorf("failed to create symlink %s -> %s: %w", dstPath, srcPath, err)
+		}
+		fm.record("link", srcPath, dstPath, false)
+	} else if err := fm.copyFileOrDir(srcPath, dstPath); err != nil {
+		return err
+	}
+
+	if fm.verbose {
+		verb := "Copied"
+		if operation == "link" {
+			verb = "Linked"
+		}
+		fmt.Printf("    %s: %s -> %s\n", verb, mapping.From, mapping.To)
+	}
+	return nil
+}
+
 // copyPattern copies all files matching a specific pattern
 func (fm *FileManager) copyPattern(pattern, srcDir, dstDir string, ignorePatterns []string) error {
 	// Get absolute pattern path
@@ -184,10 +438,23 @@ func (fm *FileManager) linkPattern(pattern, srcDir, dstDir string, ignorePattern
 			return fmt.Errorf("failed to create directory for %s: %w", dstPath, err)
 		}
 
+		// Leave an identical link from a previous setup in place
+		if linkMatches(dstPath, srcPath) {
+			fm.record("link", srcPath, dstPath, true)
+			continue
+		}
+
+		if proceed, err := fm.resolveConflict(srcPath, dstPath); err != nil {
+			return err
+		} else if !proceed {
+			continue
+		}
+
 		// Create symbolic link
 		if err := os.Symlink(srcPath, dstPath); err != nil {
 			return fmt.Errorf("failed to create symlink %s -> %s: %w", dstPath, srcPath, err)
 		}
+		fm.record("link", srcPath, dstPath, false)
 
 		if fm.verbose {
 			fmt.Printf("    Linked: %s -> %s\n", relPath, srcPath)
@@ -212,6 +479,19 @@ func (fm *FileManager) copyFileOrDir(src, dst string) error {
 
 // copyFile copies a single file with proper resource management
 func (fm *FileManager) copyFile(src, dst string) error {
+	// Skip files whose size and modification time already match
+	if fileUnchanged(src, dst) {
+		fm.record("copy", src, dst, true)
+		return nil
+	}
+	if sameContent(src, dst) {
+		fm.record("copy", src, dst, true)
+		return nil
+	}
+	if proceed, err := fm.resolveConflict(src, dst); err != nil || !proceed {
+		return err
+	}
+
 	// Create destination directory if it doesn't exist
 	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
 		return fmt.Errorf("failed to create destination directory: %w", err)
@@ -254,14 +534,9 @@ func (fm *FileManager) copyFile(src, dst string) error {
 		}
 	}()
 
-	// Copy content
-	if _, err := io.Copy(dstFile, srcFile); err != nil {
-		return fmt.Errorf("failed to copy file content: %w", err)
-	}
-
-	// Sync to ensure data is written
-	if err := dstFile.Sync(); err != nil {
-		return fmt.Errorf("failed to sync destination file: %w", err)
+	// Copy content, synced to ensure data is written
+	if err := fm.copyFileContent(dstFile, srcFile, dstFile.Sync); err != nil {
+		return err
 	}
 
 	// Copy permissions
@@ -270,11 +545,122 @@ func (fm *FileManager) copyFile(src, dst string) error {
 		// Don't treat permission copy failure as fatal
 	}
 
+	// Preserve the modification time so repeat setups can detect unchanged files
+	if err := os.Chtimes(dst, srcInfo.ModTime(), srcInfo.ModTime()); err != nil {
+		log.Printf("Warning: Failed to copy modification time for %s: %v", dst, err)
+	}
+
 	success = true // Mark operation as successful
+	fm.record("copy", src, dst, false)
 	log.Printf("Successfully copied file: %s -> %s", src, dst)
 	return nil
 }
 
+// fileUnchanged reports whether dst is a regular file with the same size and
+// modification time as src
+func fileUnchanged(src, dst string) bool {
+	srcInfo, err := os.Stat(src)
+	if err != nil {
+		return false
+	}
+	dstInfo, err := os.Lstat(dst)
+	if err != nil || !dstInfo.Mode().IsRegular() {
+		return false
+	}
+	return srcInfo.Size() == dstInfo.Size() && srcInfo.ModTime().Equal(dstInfo.ModTime())
+}
+
+// sameContent reports whether dst is a regular file with the same bytes as src
+func sameContent(src, dst string) bool {
+	srcInfo, err := os.Stat(src)
+	if err != nil {
+		return false
+	}
+	dstInfo, err := os.Lstat(dst)
+	if err != nil || !dstInfo.Mode().IsRegular() || srcInfo.Size() != dstInfo.Size() {
+		return false
+	}
+
+	srcFile, err := os.Open(src)
+	if err != nil {
+		return false
+	}
+	defer srcFile.Close()
+	dstFile, err := os.Open(dst)
+	if err != nil {
+		return false
+	}
+	defer dstFile.Close()
+
+	srcBuf, dstBuf := make([]byte, 32*1024), make([]byte, 32*1024)
+	for {
+		n, srcErr := io.ReadFull(srcFile, srcBuf)
+		m, dstErr := io.ReadFull(dstFile, dstBuf)
+		if n != m || !bytes.Equal(srcBuf[:n], dstBuf[:m]) {
+			return false
+		}
+		if srcErr != nil || dstErr != nil {
+			return srcErr == dstErr || (srcErr == io.ErrUnexpectedEOF && dstErr == io.ErrUnexpectedEOF)
+		}
+	}
+}
+
+// resolveConflict applies the conflict policy to a destination that may
+// already exist, clearing it out of the way unless it is kept. It reports
+// whether the copy or link should go ahead.
+func (fm *FileManager) resolveConflict(src, dst string) (bool, error) {
+	if _, err := os.Lstat(dst); err != nil {
+		return true, nil
+	}
+
+	choice := fm.onConflict
+	if fm.ownsDestination(dst) {
+		choice = OnConflictOverwrite
+	} else if choice == "" || choice == OnConflictAsk {
+		choice = OnConflictKeep
+		if fm.resolver != nil {
+			var err error
+			if choice, err = fm.resolver(src, dst); err != nil {
+				return false, err
+			}
+		}
+	}
+
+	switch choice {
+	case OnConflictKeep:
+		fm.stats.Kept++
+		if fm.verbose {
+			fmt.Printf("    Kept existing: %s\n", dst)
+		}
+		return false, nil
+	case OnConflictRename:
+		old := dst + ".orig"
+		for i := 1; pathExists(old); i++ {
+			old = fmt.Sprintf("%s.orig.%d", dst, i)
+		}
+		if err := os.Rename(dst, old); err != nil {
+			return false, types.NewFileSystemError("rename", dst, "failed to move existing file aside", err)
+		}
+		fm.stats.Renamed++
+		if fm.verbose {
+			fmt.Printf("    Moved existing: %s -> %s\n", dst, old)
+		}
+	case OnConflictOverwrite:
+		if err := os.RemoveAll(dst); err != nil {
+			return false, types.NewFileSystemError("overwrite", dst, "failed to remove existing file", err)
+		}
+	default:
+		return false, types.NewValidationError("on-conflict", fmt.Sprintf("unknown conflict choice '%s'", choice), nil)
+	}
+	return true, nil
+}
+
+// linkMatches reports whether path is already a symlink to target
+func linkMatches(path, target string) bool {
+	existing, err := os.Readlink(path)
+	return err == nil && existing == target
+}
+
 // copyDir copies a directory recursively
 func (fm *FileManager) copyDir(src, dst string) error {
 	// Get source directory info
@@ -283,6 +669,17 @@ func (fm *FileManager) copyDir(src, dst string) error {
 		return err
 	}
 
+	// A directory new to a network file system goes over in one piece
+	if fm.batched && !fileExists(dst) {
+		if used, err := tarCopyDir(src, dst); used {
+			if err != nil {
+				return err
+			}
+			fm.record("copy", src, dst, false)
+			return nil
+		}
+	}
+
 	// Create destination directory
 	if err := os.MkdirAll(dst, srcInfo.Mode()); err != nil {
 		return err
@@ -416,32 +813,30 @@ func (fm *FileManager) validatePathBounds(path, operation string) error {
 		return nil
 	}
 
-	// Get absolute path
-	absPath, err := filepath.Abs(path)
+	// Resolve to a canonical path, the same way the base path was resolved
+	canonical, err := canonicalPath(path)
 	if err != nil {
 		return fmt.Errorf("cannot resolve absolute path for %s: %w", path, err)
 	}
 
-	// Resolve any symlinks to get canonical path
-	canonicalPath, err := filepath.EvalSymlinks(absPath)
-	if err != nil {
-		log.Printf("Warning: Could not resolve symlinks in %s: %v", absPath, err)
-		canonicalPath = absPath // Use absolute path if symlink resolution fails
+	// Check if path is within the base directory or an allowed root
+	for _, root := range append([]string{fm.allowedBasePath}, fm.allowedRoots...) {
+		if isWithinDir(canonical, root) {
+			return nil
+		}
 	}
 
-	// Check if path is within allowed base directory
-	relPath, err := filepath.Rel(fm.allowedBasePath, canonicalPath)
-	if err != nil {
-		return fmt.Errorf("cannot compute relative path from base directory: %w", err)
-	}
+	log.Printf("Security violation: %s operation attempted outside allowed directory: %s (resolved to %s)", operation, path, canonical)
+	return fmt.Errorf("%s operation not allowed outside base directory %s", operation, fm.allowedBasePath)
+}
 
-	// Path is outside base directory if relative path starts with ../
-	if strings.HasPrefix(relPath, "../") || relPath == ".." {
-		log.Printf("Security violation: %s operation attempted outside allowed directory: %s (resolved to %s)", operation, path, canonicalPath)
-		return fmt.Errorf("%s operation not allowed outside base directory %s", operation, fm.allowedBasePath)
+// isWithinDir reports whether the canonical path is dir or below it
+func isWithinDir(path, dir string) bool {
+	rel, err := filepath.Rel(dir, path)
+	if err != nil {
+		return false
 	}
-
-	return nil
+	return rel == "." || (rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)))
 }
 
 // utility functions
@@ -363,6 +363,50 @@ func TestFileManager_RaceConditionResistance(t *testing.T) {
 	assert.Error(t, err, "Should detect file was replaced with malicious symlink")
 }
 
+// TestFileManager_SymlinkedBasePath tests that a repository reached through a
+// symlinked directory is not mistaken for an outside path
+func TestFileManager_SymlinkedBasePath(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	realDir := filepath.Join(tmpDir, "real")
+	realRepo := filepath.Join(realDir, "repo")
+	require.NoError(t, os.MkdirAll(realRepo, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(realRepo, ".env"), []byte("KEY=1"), 0644))
+
+	// Access the repository through a symlinked parent, like /tmp on macOS
+	aliasDir := filepath.Join(tmpDir, "alias")
+	require.NoError(t, os.Symlink(realDir, aliasDir))
+	aliasRepo := filepath.Join(aliasDir, "repo")
+
+	fm := NewFileManager(false)
+	require.NoError(t, fm.SetBasePath(aliasRepo))
+
+	assert.NoError(t, fm.validatePathSecurity(filepath.Join(aliasRepo, ".env"), "copy"))
+	assert.NoError(t, fm.validatePathSecurity(filepath.Join(realRepo, ".env"), "copy"))
+	assert.NoError(t, fm.validatePathBounds(filepath.Join(aliasRepo, "not", "created", "yet"), "copy"),
+		"paths that don't exist yet should still canonicalize under the base")
+	assert.Error(t, fm.validatePathBounds(filepath.Join(realDir, "other"), "copy"))
+}
+
+// TestFileManager_AllowedRoots tests that symlinks into an allowlisted root pass
+func TestFileManager_AllowedRoots(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	srcDir := filepath.Join(tmpDir, "src")
+	cacheDir := filepath.Join(tmpDir, "cache")
+	require.NoError(t, os.MkdirAll(srcDir, 0755))
+	require.NoError(t, os.MkdirAll(cacheDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(cacheDir, "deps.tar"), []byte("deps"), 0644))
+	require.NoError(t, os.Symlink(filepath.Join(cacheDir, "deps.tar"), filepath.Join(srcDir, "deps.tar")))
+
+	fm := NewFileManager(false)
+	require.NoError(t, fm.SetBasePath(srcDir))
+	assert.Error(t, fm.validatePathSecurity(filepath.Join(srcDir, "deps.tar"), "copy"))
+
+	require.NoError(t, fm.AddAllowedRoot(cacheDir))
+	assert.NoError(t, fm.validatePathSecurity(filepath.Join(srcDir, "deps.tar"), "copy"))
+}
+
 // BenchmarkSecurityValidation benchmarks the security validation performance
 func BenchmarkSecurityValidation(b *testing.B) {
 	tmpDir, err := os.MkdirTemp("", "wtree-benchmark")
@@ -5,6 +5,7 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/awhite/wtree/pkg/types"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -178,3 +179,155 @@ func TestFileManager_ValidateFilePatterns(t *testing.T) {
 		})
 	}
 }
+
+func TestFileManager_Mappings(t *testing.T) {
+	tmpDir := t.TempDir()
+	srcDir := filepath.Join(tmpDir, "src")
+	dstDir := filepath.Join(tmpDir, "dst")
+	require.NoError(t, os.MkdirAll(filepath.Join(srcDir, "configs"), 0755))
+	require.NoError(t, os.MkdirAll(dstDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(srcDir, "configs", "dev.env"), []byte("DEV=1"), 0644))
+
+	fm := NewFileManager(false)
+	require.NoError(t, fm.SetBasePath(srcDir))
+
+	err := fm.CopyMappings([]types.FileEntry{
+		{From: "configs/dev.env", To: ".env"},
+		{From: "configs/missing.env", To: "missing.env"},
+	}, srcDir, dstDir)
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(filepath.Join(dstDir, ".env"))
+	require.NoError(t, err)
+	assert.Equal(t, "DEV=1", string(data))
+	assert.NoFileExists(t, filepath.Join(dstDir, "missing.env"))
+
+	err = fm.LinkMappings([]types.FileEntry{{From: "configs", To: "settings/configs"}}, srcDir, dstDir)
+	require.NoError(t, err)
+
+	target, err := os.Readlink(filepath.Join(dstDir, "settings", "configs"))
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(srcDir, "configs"), target)
+}
+
+func TestFileManager_Clone(t *testing.T) {
+	tmpDir := t.TempDir()
+	srcDir := filepath.Join(tmpDir, "src")
+	dstDir := filepath.Join(tmpDir, "dst")
+	require.NoError(t, os.MkdirAll(srcDir, 0755))
+	require.NoError(t, os.MkdirAll(dstDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(srcDir, ".env"), []byte("A=1"), 0644))
+
+	fm := NewFileManager(false)
+	require.NoError(t, fm.SetBasePath(srcDir))
+	fm.SetConflictPolicy(OnConflictOverwrite, nil)
+	require.NoError(t, fm.CopyMappings([]types.FileEntry{{From: ".env", To: ".env"}}, srcDir, dstDir))
+	require.Len(t, fm.Operations(), 1)
+
+	clone := fm.Clone()
+	assert.Empty(t, clone.Operations())
+	assert.Equal(t, FileStats{}, clone.Stats())
+	assert.Equal(t, fm.onConflict, clone.onConflict)
+
+	// The clone keeps the restrictions of the original
+	outside := filepath.Join(tmpDir, "outside")
+	require.NoError(t, os.MkdirAll(outside, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(outside, ".env"), []byte("B=2"), 0644))
+	assert.Error(t, clone.CopyMappings([]types.FileEntry{{From: ".env", To: ".env"}}, outside, dstDir))
+
+	// Operations recorded by the clone are its own
+	other := filepath.Join(tmpDir, "other")
+	require.NoError(t, os.MkdirAll(other, 0755))
+	require.NoError(t, clone.CopyMappings([]types.FileEntry{{From: ".env", To: ".env"}}, srcDir, other))
+	require.Len(t, clone.Operations(), 1)
+	assert.Equal(t, filepath.Join(other, ".env"), clone.Operations()[0].Destination)
+	assert.Len(t, fm.Operations(), 1)
+}
+
+func TestFileManager_IncrementalCopy(t *testing.T) {
+	tmpDir := t.TempDir()
+	srcDir := filepath.Join(tmpDir, "src")
+	dstDir := filepath.Join(tmpDir, "dst")
+	require.NoError(t, os.MkdirAll(filepath.Join(srcDir, "assets"), 0755))
+	require.NoError(t, os.MkdirAll(dstDir, 0755))
+	for _, name := range []string{"a.png", "b.png", "c.png"} {
+		require.NoError(t, os.WriteFile(filepath.Join(srcDir, "assets", name), []byte(name), 0644))
+	}
+
+	fm := NewFileManager(false)
+
+	require.NoError(t, fm.CopyFiles([]string{"assets"}, srcDir, dstDir, nil))
+	assert.Equal(t, FileStats{Copied: 3}, fm.Stats())
+
+	// A repeat run copies nothing
+	fm.ResetStats()
+	require.NoError(t, fm.CopyFiles([]string{"assets"}, srcDir, dstDir, nil))
+	assert.Equal(t, FileStats{Unchanged: 3}, fm.Stats())
+
+	// Only the modified file is copied again
+	require.NoError(t, os.WriteFile(filepath.Join(srcDir, "assets", "b.png"), []byte("changed"), 0644))
+	fm.ResetStats()
+	require.NoError(t, fm.CopyFiles([]string{"assets"}, srcDir, dstDir, nil))
+	assert.Equal(t, FileStats{Copied: 1, Unchanged: 2}, fm.Stats())
+	assert.Equal(t, "1 copied, 2 unchanged", fm.Stats().String())
+
+	// Existing identical links are left alone
+	fm.ResetStats()
+	require.NoError(t, fm.LinkFiles([]string{"assets/a.png"}, srcDir, filepath.Join(tmpDir, "linked"), nil))
+	require.NoError(t, fm.LinkFiles([]string{"assets/a.png"}, srcDir, filepath.Join(tmpDir, "linked"), nil))
+	assert.Equal(t, FileStats{Linked: 1, Unchanged: 1}, fm.Stats())
+}
+
+func TestFileManager_Conflicts(t *testing.T) {
+	tmpDir := t.TempDir()
+	srcDir := filepath.Join(tmpDir, "src")
+	dstDir := filepath.Join(tmpDir, "dst")
+	require.NoError(t, os.MkdirAll(filepath.Join(srcDir, "cache"), 0755))
+	require.NoError(t, os.MkdirAll(filepath.Join(dstDir, "cache"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(srcDir, ".env"), []byte("A=1"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dstDir, ".env"), []byte("A=22"), 0644))
+	readEnv := func() string {
+		data, err := os.ReadFile(filepath.Join(dstDir, ".env"))
+		require.NoError(t, err)
+		return string(data)
+	}
+
+	// Without a resolver, ask keeps existing files and directories
+	fm := NewFileManager(false)
+	require.NoError(t, fm.CopyFiles([]string{".env"}, srcDir, dstDir, nil))
+	require.NoError(t, fm.LinkFiles([]string{"cache"}, srcDir, dstDir, nil))
+	assert.Equal(t, FileStats{Kept: 2}, fm.Stats())
+	assert.Equal(t, "A=22", readEnv())
+
+	// Identical content is not a conflict
+	require.NoError(t, os.WriteFile(filepath.Join(dstDir, "same"), []byte("x"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(srcDir, "same"), []byte("x"), 0644))
+	fm.ResetStats()
+	require.NoError(t, fm.CopyFiles([]string{"same"}, srcDir, dstDir, nil))
+	assert.Equal(t, FileStats{Unchanged: 1}, fm.Stats())
+
+	var asked []string
+	fm.SetConflictPolicy(OnConflictAsk, func(src, dst string) (string, error) {
+		asked = append(asked, dst)
+		return OnConflictRename, nil
+	})
+	fm.ResetStats()
+	require.NoError(t, fm.CopyFiles([]string{".env"}, srcDir, dstDir, nil))
+	assert.Equal(t, []string{filepath.Join(dstDir, ".env")}, asked)
+	assert.Equal(t, FileStats{Copied: 1, Renamed: 1}, fm.Stats())
+	assert.Equal(t, "A=1", readEnv())
+	old, err := os.ReadFile(filepath.Join(dstDir, ".env.orig"))
+	require.NoError(t, err)
+	assert.Equal(t, "A=22", string(old))
+
+	// A copy wtree placed and nobody touched is updated without asking
+	require.NoError(t, os.WriteFile(filepath.Join(srcDir, ".env"), []byte("A=333"), 0644))
+	require.NoError(t, fm.CopyFiles([]string{".env"}, srcDir, dstDir, nil))
+	assert.Len(t, asked, 1)
+	assert.Equal(t, "A=333", readEnv())
+
+	// Overwrite replaces an existing directory with the link
+	fm.SetConflictPolicy(OnConflictOverwrite, nil)
+	require.NoError(t, fm.LinkFiles([]string{"cache"}, srcDir, dstDir, nil))
+	assert.True(t, linkMatches(filepath.Join(dstDir, "cache"), filepath.Join(srcDir, "cache")))
+}
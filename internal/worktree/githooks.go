@@ -0,0 +1,125 @@
+package worktree
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/awhite/wtree/pkg/types"
+)
+
+// setupGitHooks makes commit hooks work in a new worktree according to the
+// project's git_hooks setting.
+//
+// Hooks in the shared .git/hooks directory (or an absolute core.hooksPath)
+// already apply to every worktree. Tools like Husky instead set a relative
+// core.hooksPath whose generated, untracked files only exist in the checkout
+// where the installer ran; those are the files copied or linked here.
+func (m *Manager) setupGitHooks(worktreePath string) error {
+	if m.projectConfig == nil || m.projectConfig.GitHooks == "" {
+		return nil
+	}
+
+	if m.projectConfig.GitHooks == types.GitHooksInstall {
+		return m.installGitHooks(worktreePath)
+	}
+
+	hooksPath, err := m.repo.GetConfigValue("core.hooksPath")
+	if err != nil {
+		return err
+	}
+	if hooksPath == "" || filepath.IsAbs(hooksPath) {
+		// Shared hooks directory; nothing to do per worktree
+		return nil
+	}
+
+	repoRoot, err := m.repo.GetRepoRoot()
+	if err != nil {
+		return err
+	}
+
+	src := filepath.Join(repoRoot, hooksPath)
+	dst := filepath.Join(worktreePath, hooksPath)
+	m.progress("Setting up git hooks (%s)...", m.projectConfig.GitHooks)
+	return m.syncHooksDir(src, dst, m.projectConfig.GitHooks == types.GitHooksLink)
+}
+
+// syncHooksDir copies or links entries from src that are missing in dst,
+// recursing into directories present in both so tracked files are left alone
+func (m *Manager) syncHooksDir(src, dst string, link bool) error {
+	entries, err := os.ReadDir(src)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return types.NewFileSystemError("git-hooks", src, "failed to read hooks directory", err)
+	}
+
+	if err := os.MkdirAll(dst, 0755); err != nil {
+		return types.NewFileSystemError("git-hooks", dst, "failed to create hooks directory", err)
+	}
+
+	for _, entry := range entries {
+		srcPath := filepath.Join(src, entry.Name())
+		dstPath := filepath.Join(dst, entry.Name())
+
+		if info, err := os.Lstat(dstPath); err == nil {
+			if entry.IsDir() && info.IsDir() {
+				if err := m.syncHooksDir(srcPath, dstPath, link); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		if link {
+			if err := os.Symlink(srcPath, dstPath); err != nil {
+				return types.NewFileSystemError("git-hooks", dstPath, "failed to link hook", err)
+			}
+		} else if err := m.fileManager.copyFileOrDir(srcPath, dstPath); err != nil {
+			return types.NewFileSystemError("git-hooks", dstPath, "failed to copy hook", err)
+		}
+	}
+
+	return nil
+}
+
+// installGitHooks runs the detected hook manager's installer in the worktree
+func (m *Manager) installGitHooks(worktreePath string) error {
+	args := detectHookInstaller(worktreePath)
+	if args == nil {
+		m.warn("git_hooks: install is set but no hook manager (husky, lefthook, pre-commit) was detected")
+		return nil
+	}
+
+	m.progress("Installing git hooks: %s...", args[0])
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Dir = worktreePath
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return types.NewHookError("git-hooks",
+			fmt.Sprintf("hook installer failed: %s", string(output)), err)
+	}
+
+	return nil
+}
+
+// detectHookInstaller returns the install command for the hook manager
+// configured in a worktree, or nil when none is found
+func detectHookInstaller(worktreePath string) []string {
+	has := func(name string) bool {
+		_, err := os.Stat(filepath.Join(worktreePath, name))
+		return err == nil
+	}
+
+	switch {
+	case has("lefthook.yml") || has("lefthook.yaml") || has(".lefthook.yml"):
+		return []string{"lefthook", "install"}
+	case has(".husky"):
+		return []string{"npx", "--no-install", "husky"}
+	case has(".pre-commit-config.yaml"):
+		return []string{"pre-commit", "install"}
+	default:
+		return nil
+	}
+}
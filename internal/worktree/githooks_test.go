@@ -0,0 +1,61 @@
+package worktree
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestManager_syncHooksDir(t *testing.T) {
+	root := t.TempDir()
+	src := filepath.Join(root, "main", ".husky")
+	dst := filepath.Join(root, "feature", ".husky")
+
+	// Tracked hook present in both checkouts, generated dir only in main
+	require.NoError(t, os.MkdirAll(filepath.Join(src, "_"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(src, "pre-commit"), []byte("main"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(src, "_", "h"), []byte("runner"), 0755))
+	require.NoError(t, os.MkdirAll(dst, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dst, "pre-commit"), []byte("worktree"), 0755))
+
+	m := &Manager{fileManager: NewFileManager(false)}
+
+	t.Run("copy", func(t *testing.T) {
+		require.NoError(t, m.syncHooksDir(src, dst, false))
+
+		data, err := os.ReadFile(filepath.Join(dst, "_", "h"))
+		require.NoError(t, err)
+		assert.Equal(t, "runner", string(data))
+
+		data, err = os.ReadFile(filepath.Join(dst, "pre-commit"))
+		require.NoError(t, err)
+		assert.Equal(t, "worktree", string(data), "tracked hook should not be overwritten")
+	})
+
+	t.Run("link", func(t *testing.T) {
+		linkDst := filepath.Join(root, "linked", ".husky")
+		require.NoError(t, m.syncHooksDir(src, linkDst, true))
+
+		target, err := os.Readlink(filepath.Join(linkDst, "_"))
+		require.NoError(t, err)
+		assert.Equal(t, filepath.Join(src, "_"), target)
+	})
+
+	t.Run("missing source", func(t *testing.T) {
+		assert.NoError(t, m.syncHooksDir(filepath.Join(root, "nope"), dst, false))
+	})
+}
+
+func TestDetectHookInstaller(t *testing.T) {
+	dir := t.TempDir()
+	assert.Nil(t, detectHookInstaller(dir))
+
+	require.NoError(t, os.Mkdir(filepath.Join(dir, ".husky"), 0755))
+	assert.Equal(t, []string{"npx", "--no-install", "husky"}, detectHookInstaller(dir))
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "lefthook.yml"), nil, 0644))
+	assert.Equal(t, []string{"lefthook", "install"}, detectHookInstaller(dir))
+}
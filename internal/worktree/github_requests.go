@@ -0,0 +1,53 @@
+package worktree
+
+import (
+	"sync"
+
+	"github.com/awhite/wtree/internal/github"
+)
+
+// githubWorkers returns how many GitHub CLI calls bulk operations run at
+// once (github.max_concurrent_requests)
+func (m *Manager) githubWorkers() int {
+	if m.globalConfig != nil && m.globalConfig.GitHub.MaxConcurrentRequests > 1 {
+		return m.globalConfig.GitHub.MaxConcurrentRequests
+	}
+	return 1
+}
+
+// forEachConcurrently calls work for every index below count with at most
+// workers calls running at once, and returns when all have finished. work
+// stores its result at its own index, so results keep the input order
+// whatever order the calls finish in.
+func forEachConcurrently(count, workers int, work func(i int)) {
+	if workers > count {
+		workers = count
+	}
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				work(i)
+			}
+		}()
+	}
+	for i := 0; i < count; i++ {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+}
+
+// fetchPRs looks up the PR of every PR worktree on GitHub concurrently;
+// infos and errs line up with prWorktrees
+func (pm *PRManager) fetchPRs(prWorktrees []*PRWorktreeInfo) ([]*github.PRInfo, []error) {
+	infos := make([]*github.PRInfo, len(prWorktrees))
+	errs := make([]error, len(prWorktrees))
+	forEachConcurrently(len(prWorktrees), pm.githubWorkers(), func(i int) {
+		infos[i], errs[i] = pm.github.GetPR(prWorktrees[i].PRNumber)
+	})
+	return infos, errs
+}
@@ -0,0 +1,41 @@
+package worktree
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/awhite/wtree/pkg/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestForEachConcurrently(t *testing.T) {
+	var running, peak int32
+	results := make([]int, 20)
+	forEachConcurrently(len(results), 3, func(i int) {
+		now := atomic.AddInt32(&running, 1)
+		for {
+			seen := atomic.LoadInt32(&peak)
+			if now <= seen || atomic.CompareAndSwapInt32(&peak, seen, now) {
+				break
+			}
+		}
+		// Later indexes finish first
+		time.Sleep(time.Duration(len(results)-i) * time.Millisecond)
+		results[i] = i * i
+		atomic.AddInt32(&running, -1)
+	})
+
+	assert.LessOrEqual(t, peak, int32(3))
+	for i, result := range results {
+		assert.Equal(t, i*i, result)
+	}
+	forEachConcurrently(0, 3, func(int) { t.Fatal("nothing to do") })
+}
+
+func TestManager_GitHubWorkers(t *testing.T) {
+	assert.Equal(t, 1, (&Manager{}).githubWorkers())
+	config := types.DefaultWTreeConfig()
+	config.GitHub.MaxConcurrentRequests = 6
+	assert.Equal(t, 6, (&Manager{globalConfig: config}).githubWorkers())
+}
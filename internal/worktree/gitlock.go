@@ -0,0 +1,66 @@
+package worktree
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+
+	"github.com/awhite/wtree/pkg/types"
+)
+
+// gitLockTurn serializes withGitLock between goroutines
+var gitLockTurn sync.Mutex
+
+// withGitLock runs fn holding the repository-wide git lock, so that changes
+// to branches and worktree registrations never interleave with those of
+// other wtree processes or of hooks using 'wtree internal-lock'. Hooks run
+// outside it, or a hook taking it would wait on its own operation.
+func (m *Manager) withGitLock(fn func() error) error {
+	if m.lockManager == nil {
+		return fn()
+	}
+	// The lock file is held once per process, so concurrent operations of
+	// this process (create-many) take turns here before taking it
+	gitLockTurn.Lock()
+	defer gitLockTurn.Unlock()
+	release, err := m.acquireOperationLock(LockTypeGit, m.mainWorktreePath())
+	if err != nil {
+		return err
+	}
+	defer release()
+	return fn()
+}
+
+// RunWithGitLock runs command holding the repository-wide git lock, for
+// hooks and scripts that run git commands (fetch, lfs pull) while wtree
+// may be changing the repository. With heldByHook the command must be run
+// by a hook of a running wtree operation, identified by its lock token.
+func (m *Manager) RunWithGitLock(command []string, heldByHook bool) error {
+	if len(command) == 0 {
+		return types.NewValidationError("internal-lock", "a command to run is required", nil)
+	}
+	if heldByHook && (m.lockManager == nil || !m.lockManager.TokenHeld(os.Getenv(LockTokenEnv))) {
+		return types.NewValidationError("internal-lock",
+			fmt.Sprintf("--held-by-hook must be run from a wtree hook (%s is missing or belongs to no running operation)", LockTokenEnv), nil)
+	}
+
+	return m.withGitLock(func() error {
+		cmd := exec.Command(command[0], command[1:]...)
+		cmd.Stdin = os.Stdin
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+
+		err := cmd.Run()
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return &types.ExitCodeError{Command: strings.Join(command, " "), Code: exitErr.ExitCode()}
+		}
+		if err != nil {
+			return fmt.Errorf("failed to run '%s': %w", strings.Join(command, " "), err)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,170 @@
+package worktree
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/awhite/wtree/internal/git"
+	"github.com/awhite/wtree/pkg/types"
+)
+
+// Compact state glyphs shown next to a worktree's branch
+const (
+	glyphDirty  = "●"
+	glyphAhead  = "↑"
+	glyphBehind = "↓"
+	glyphPR     = "⚑"
+	glyphLocked = "🔒"
+)
+
+// worktreeMarks is the state summarized by glyphs or porcelain markers
+type worktreeMarks struct {
+	Dirty    bool
+	Ahead    int
+	Behind   int
+	PRNumber int
+	Locked   bool
+}
+
+// marksFor collects the markable state of a worktree; status may be nil when
+// it was not fetched
+func (m *Manager) marksFor(wt *types.WorktreeInfo, status *git.WorktreeStatus) worktreeMarks {
+	marks := worktreeMarks{Locked: wt.Locked}
+	if status != nil {
+		marks.Dirty = !status.IsClean
+		marks.Ahead = status.Ahead
+		marks.Behind = status.Behind
+	}
+	pm := &PRManager{Manager: m}
+	marks.PRNumber = pm.extractPRNumber(wt.Path, m.repo.GetRepoName())
+	return marks
+}
+
+// Glyphs renders the marks compactly, e.g. "● ↑2 ↓1 ⚑ 🔒"
+func (w worktreeMarks) Glyphs() string {
+	var parts []string
+	if w.Dirty {
+		parts = append(parts, glyphDirty)
+	}
+	if w.Ahead > 0 {
+		parts = append(parts, fmt.Sprintf("%s%d", glyphAhead, w.Ahead))
+	}
+	if w.Behind > 0 {
+		parts = append(parts, fmt.Sprintf("%s%d", glyphBehind, w.Behind))
+	}
+	if w.PRNumber > 0 {
+		parts = append(parts, glyphPR)
+	}
+	if w.Locked {
+		parts = append(parts, glyphLocked)
+	}
+	return strings.Join(parts, " ")
+}
+
+// Words renders the marks as a readable phrase for accessible output, e.g.
+// "(dirty, 2 ahead, 1 behind, PR #42, locked)"
+func (w worktreeMarks) Words() string {
+	var parts []string
+	if w.Dirty {
+		parts = append(parts, "dirty")
+	}
+	if w.Ahead > 0 {
+		parts = append(parts, fmt.Sprintf("%d ahead", w.Ahead))
+	}
+	if w.Behind > 0 {
+		parts = append(parts, fmt.Sprintf("%d behind", w.Behind))
+	}
+	if w.PRNumber > 0 {
+		parts = append(parts, fmt.Sprintf("PR #%d", w.PRNumber))
+	}
+	if w.Locked {
+		parts = append(parts, "locked")
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return "(" + strings.Join(parts, ", ") + ")"
+}
+
+// marksText renders marks as glyphs, or as words in accessible mode
+func (m *Manager) marksText(marks worktreeMarks) string {
+	if m.ui.Accessible() {
+		return marks.Words()
+	}
+	return marks.Glyphs()
+}
+
+// Porcelain renders the marks as stable plain-text markers for scripts, e.g.
+// "dirty,ahead=2,behind=1,pr=42,locked", or "-" when there are none
+func (w worktreeMarks) Porcelain() string {
+	var parts []string
+	if w.Dirty {
+		parts = append(parts, "dirty")
+	}
+	if w.Ahead > 0 {
+		parts = append(parts, fmt.Sprintf("ahead=%d", w.Ahead))
+	}
+	if w.Behind > 0 {
+		parts = append(parts, fmt.Sprintf("behind=%d", w.Behind))
+	}
+	if w.PRNumber > 0 {
+		parts = append(parts, fmt.Sprintf("pr=%d", w.PRNumber))
+	}
+	if w.Locked {
+		parts = append(parts, "locked")
+	}
+	if len(parts) == 0 {
+		return "-"
+	}
+	return strings.Join(parts, ",")
+}
+
+// glyphLegend explains the glyphs used by any of the marks
+func glyphLegend(all []worktreeMarks) string {
+	var used worktreeMarks
+	for _, marks := range all {
+		used.Dirty = used.Dirty || marks.Dirty
+		used.Ahead += marks.Ahead
+		used.Behind += marks.Behind
+		used.PRNumber += marks.PRNumber
+		used.Locked = used.Locked || marks.Locked
+	}
+
+	var entries []string
+	if used.Dirty {
+		entries = append(entries, glyphDirty+" dirty")
+	}
+	if used.Ahead > 0 {
+		entries = append(entries, glyphAhead+"N ahead")
+	}
+	if used.Behind > 0 {
+		entries = append(entries, glyphBehind+"N behind")
+	}
+	if used.PRNumber > 0 {
+		entries = append(entries, glyphPR+" PR")
+	}
+	if used.Locked {
+		entries = append(entries, glyphLocked+" locked")
+	}
+	return strings.Join(entries, "  ")
+}
+
+// printPorcelain prints one tab-separated line per worktree, without headers
+// or colors: path, branch (or detached@sha), type and markers. The format is
+// stable for scripts.
+func (m *Manager) printPorcelain(worktrees []*types.WorktreeInfo, keep func(*types.WorktreeInfo) bool) {
+	for _, wt := range worktrees {
+		if !keep(wt) {
+			continue
+		}
+		var status *git.WorktreeStatus
+		if s, err := m.repo.GetWorktreeStatus(wt.Path); err == nil {
+			status = s
+		}
+		wtType := "worktree"
+		if wt.IsMainRepo {
+			wtType = "main"
+		}
+		fmt.Printf("%s\t%s\t%s\t%s\n", wt.Path, wt.DisplayBranch(), wtType, m.marksFor(wt, status).Porcelain())
+	}
+}
@@ -0,0 +1,21 @@
+package worktree
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorktreeMarks(t *testing.T) {
+	marks := worktreeMarks{Dirty: true, Ahead: 2, Behind: 1, PRNumber: 42, Locked: true}
+	assert.Equal(t, "● ↑2 ↓1 ⚑ 🔒", marks.Glyphs())
+	assert.Equal(t, "dirty,ahead=2,behind=1,pr=42,locked", marks.Porcelain())
+	assert.Equal(t, "(dirty, 2 ahead, 1 behind, PR #42, locked)", marks.Words())
+
+	assert.Equal(t, "", worktreeMarks{}.Glyphs())
+	assert.Equal(t, "-", worktreeMarks{}.Porcelain())
+	assert.Equal(t, "", worktreeMarks{}.Words())
+
+	assert.Equal(t, "● dirty  ⚑ PR", glyphLegend([]worktreeMarks{{Dirty: true}, {PRNumber: 7}, {}}))
+	assert.Equal(t, "", glyphLegend([]worktreeMarks{{}}))
+}
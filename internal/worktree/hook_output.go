@@ -0,0 +1,237 @@
+package worktree
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/awhite/wtree/pkg/types"
+)
+
+// hookLogKeep is how many hook logs a repository keeps; starting a new one
+// removes the oldest beyond it
+const hookLogKeep = 20
+
+// hookSpinnerFrames animate the elapsed-time line under a running hook
+var hookSpinnerFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+
+// hookStream receives a running hook's stdout and stderr. It keeps all of
+// the output and copies it to the log; when shown, each line is printed as
+// it completes. When live, a spinner with the elapsed time is redrawn below
+// the output.
+type hookStream struct {
+	mu      sync.Mutex
+	output  bytes.Buffer
+	partial []byte // Shown output after its last newline
+	show    bool
+	live    bool
+	out     io.Writer // Where shown output and the spinner are printed
+	log     io.Writer
+	started time.Time
+	frame   int
+	stop    chan struct{}
+	stopped chan struct{}
+}
+
+// newHookStream starts a stream printing to out; show prints the lines, live
+// animates the spinner and log, if not nil, gets a copy of the output
+func newHookStream(out io.Writer, show, live bool, log io.Writer) *hookStream {
+	s := &hookStream{show: show, live: live, out: out, log: log, started: time.Now()}
+	if live {
+		s.stop = make(chan struct{})
+		s.stopped = make(chan struct{})
+		go s.spin()
+	}
+	return s
+}
+
+// Write implements io.Writer
+func (s *hookStream) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.output.Write(p)
+	if s.log != nil {
+		_, _ = s.log.Write(p)
+	}
+	if !s.show {
+		return len(p), nil
+	}
+
+	s.partial = append(s.partial, p...)
+	for {
+		end := bytes.IndexByte(s.partial, '\n')
+		if end < 0 {
+			break
+		}
+		s.printLine(string(s.partial[:end]))
+		s.partial = s.partial[end+1:]
+	}
+	return len(p), nil
+}
+
+// printLine prints one line of output, keeping the spinner below it
+func (s *hookStream) printLine(line string) {
+	if s.live {
+		fmt.Fprint(s.out, "\r\033[K")
+	}
+	fmt.Fprintf(s.out, "    │ %s\n", strings.TrimRight(line, "\r"))
+	if s.live {
+		s.drawSpinner()
+	}
+}
+
+// spin redraws the spinner until finish
+func (s *hookStream) spin() {
+	defer close(s.stopped)
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		s.mu.Lock()
+		s.drawSpinner()
+		s.mu.Unlock()
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// drawSpinner draws the next spinner frame on the current line
+func (s *hookStream) drawSpinner() {
+	frame := hookSpinnerFrames[s.frame%len(hookSpinnerFrames)]
+	s.frame++
+	fmt.Fprintf(s.out, "\r\033[K    %s %s", frame, time.Since(s.started).Round(time.Second))
+}
+
+// finish stops the spinner, prints a last line without a newline and
+// returns all of the output
+func (s *hookStream) finish() []byte {
+	if s.live {
+		close(s.stop)
+		<-s.stopped
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.live {
+		fmt.Fprint(s.out, "\r\033[K")
+	}
+	if s.show && len(s.partial) > 0 {
+		fmt.Fprintf(s.out, "    │ %s\n", strings.TrimRight(string(s.partial), "\r"))
+		s.partial = nil
+	}
+	return s.output.Bytes()
+}
+
+// showOutput reports whether a hook's output is printed as it runs: unless
+// the hook sets output: quiet, and always with --verbose
+func (he *HookExecutor) showOutput(hook types.HookEntry) bool {
+	return he.verbose || hook.Output != types.HookOutputQuiet
+}
+
+// hookLog is the file a run of an event's hooks is captured in
+type hookLog struct {
+	file *os.File
+	path string
+}
+
+// openHookLog creates a log for event's hooks in dir, named by the time so
+// they sort oldest first, and removes old ones beyond hookLogKeep
+func openHookLog(dir string, event string) (*hookLog, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, types.NewFileSystemError("hook-log", dir, "failed to create the hook log directory", err)
+	}
+	pruneHookLogs(dir, hookLogKeep-1)
+
+	path := filepath.Join(dir, fmt.Sprintf("%s-%s.log", time.Now().Format("20060102-150405.000"), event))
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, types.NewFileSystemError("hook-log", path, "failed to create the hook log", err)
+	}
+	return &hookLog{file: file, path: path}, nil
+}
+
+// start records a hook command about to run
+func (l *hookLog) start(cmd string) {
+	if l != nil {
+		fmt.Fprintf(l.file, "$ %s\n", cmd)
+	}
+}
+
+// end records how a hook command ended
+func (l *hookLog) end(took time.Duration, err error) {
+	if l == nil {
+		return
+	}
+	if err != nil {
+		fmt.Fprintf(l.file, "[failed after %s: %v]\n\n", took.Round(time.Millisecond), err)
+	} else {
+		fmt.Fprintf(l.file, "[completed in %s]\n\n", took.Round(time.Millisecond))
+	}
+}
+
+// writer returns where hook output is copied, nil without a log
+func (l *hookLog) writer() io.Writer {
+	if l == nil {
+		return nil
+	}
+	return l.file
+}
+
+// close closes the log file
+func (l *hookLog) close() {
+	if l != nil {
+		l.file.Close()
+	}
+}
+
+// pruneHookLogs removes the oldest logs in dir beyond keep
+func pruneHookLogs(dir string, keep int) {
+	logs, err := filepath.Glob(filepath.Join(dir, "*.log"))
+	if err != nil || len(logs) <= keep {
+		return
+	}
+	sort.Strings(logs)
+	for _, path := range logs[:len(logs)-keep] {
+		_ = os.Remove(path)
+	}
+}
+
+// hookLogDir returns where the repository's hook logs are kept, shared by
+// all of its worktrees
+func (m *Manager) hookLogDir() (string, error) {
+	commonDir, err := m.repo.GetGitCommonDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(commonDir, "wtree", "logs"), nil
+}
+
+// hookOutput configures how executor shows and captures hook output: it
+// prints where the UI does, the spinner is drawn only on a terminal without
+// accessible output, and logs go to .git/wtree/logs
+func (m *Manager) hookOutput(executor *HookExecutor) {
+	executor.out = m.ui.Output()
+	executor.live = m.ui != nil && !m.ui.Accessible() && isTerminal(executor.out)
+	if dir, err := m.hookLogDir(); err == nil {
+		executor.logDir = dir
+	}
+}
+
+// isTerminal reports whether out is an interactive terminal
+func isTerminal(out io.Writer) bool {
+	file, ok := out.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := file.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,63 @@
+package worktree
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/awhite/wtree/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHookStream(t *testing.T) {
+	var log bytes.Buffer
+	stream := newHookStream(io.Discard, false, false, &log)
+
+	_, _ = stream.Write([]byte("installing\npart"))
+	_, _ = stream.Write([]byte("ial\n"))
+
+	assert.Equal(t, "installing\npartial\n", string(stream.finish()))
+	assert.Equal(t, "installing\npartial\n", log.String())
+}
+
+func TestHookExecutor_LogsOutput(t *testing.T) {
+	executor := NewHookExecutor(&types.ProjectConfig{
+		Hooks: map[types.HookEvent][]string{
+			types.HookPostCreate: {"echo built", "echo broken >&2; exit 3"},
+		},
+	}, 30*time.Second, false)
+	executor.logDir = t.TempDir()
+
+	err := executor.ExecuteHooks(types.HookPostCreate, types.HookContext{WorktreePath: t.TempDir()})
+	require.Error(t, err)
+	assert.Nil(t, executor.log, "the log is closed")
+
+	logs, err := filepath.Glob(filepath.Join(executor.logDir, "*-post_create.log"))
+	require.NoError(t, err)
+	require.Len(t, logs, 1)
+	data, err := os.ReadFile(logs[0])
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "$ echo built\nbuilt\n[completed in ")
+	assert.Contains(t, string(data), "$ echo broken >&2; exit 3\nbroken\n[failed after ")
+}
+
+func TestPruneHookLogs(t *testing.T) {
+	dir := t.TempDir()
+	for i := 1; i <= 5; i++ {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, fmt.Sprintf("2026010%d-120000.000-post_create.log", i)), nil, 0644))
+	}
+
+	pruneHookLogs(dir, 2)
+
+	logs, err := filepath.Glob(filepath.Join(dir, "*.log"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		filepath.Join(dir, "20260104-120000.000-post_create.log"),
+		filepath.Join(dir, "20260105-120000.000-post_create.log"),
+	}, logs)
+}
@@ -0,0 +1,102 @@
+package worktree
+
+import (
+	"fmt"
+
+	"github.com/awhite/wtree/pkg/types"
+)
+
+// HookSecurityAcknowledged reports whether the user acknowledged that the
+// current .wtreerc's hooks run unchecked (hooks.security: off). Changing the
+// file in any way takes the acknowledgement back.
+func (m *Manager) HookSecurityAcknowledged() bool {
+	if m.projectConfig == nil || m.projectConfig.Digest == "" {
+		return false
+	}
+	key, err := m.trustKey()
+	if err != nil {
+		return false
+	}
+	store, err := loadTrust()
+	if err != nil {
+		return false
+	}
+	return store.HookSecurity[key] == m.projectConfig.Digest
+}
+
+// AcknowledgeHookSecurity records that hooks of the current .wtreerc may run
+// without command checks in this repository
+func (m *Manager) AcknowledgeHookSecurity() error {
+	if m.projectConfig == nil || m.projectConfig.HookSecurity != types.HookSecurityOff {
+		return types.NewValidationError("hook-security", ".wtreerc doesn't set hooks.security: off; nothing to acknowledge", nil)
+	}
+	key, err := m.trustKey()
+	if err != nil {
+		return err
+	}
+	store, err := loadTrust()
+	if err != nil {
+		return err
+	}
+	store.HookSecurity[key] = m.projectConfig.Digest
+	if err := store.save(); err != nil {
+		return err
+	}
+	m.audit(auditEntry{Event: "hook-security-acknowledged", Dir: key, Detail: "digest " + m.projectConfig.Digest})
+	return nil
+}
+
+// hookSecurity returns the hooks.security level hooks run with. The global
+// hooks.security.mode, the user's own choice, replaces the .wtreerc level.
+// Off from .wtreerc only takes effect once the user has acknowledged it for
+// this exact file, here or with 'wtree config trust --hooks'; until then
+// hooks get the standard checks.
+func (m *Manager) hookSecurity() string {
+	if mode := m.hookSecurityPolicy().Mode; mode != "" {
+		return mode
+	}
+	if m.projectConfig == nil || m.projectConfig.HookSecurity == "" {
+		return types.HookSecurityStandard
+	}
+	level := m.projectConfig.HookSecurity
+	if level != types.HookSecurityOff || m.HookSecurityAcknowledged() {
+		return level
+	}
+
+	m.warn(".wtreerc sets hooks.security: off, so its hooks would run without checks for destructive commands")
+	err := m.confirmTyped("Type 'trust' to run this .wtreerc's hooks unchecked (asked again if it changes)", "trust")
+	if err == nil {
+		err = m.AcknowledgeHookSecurity()
+	}
+	if err != nil {
+		m.warn("Checking hooks as hooks.security: standard (%v)", err)
+		return types.HookSecurityStandard
+	}
+	return level
+}
+
+// hookSecurityPolicy returns the global hooks.security settings
+func (m *Manager) hookSecurityPolicy() types.HookSecurityPolicy {
+	if m.globalConfig == nil {
+		return types.HookSecurityPolicy{}
+	}
+	return m.globalConfig.Hooks.Security
+}
+
+// hookChecks configures executor with the repository's hooks.security level
+// and the global allow and deny lists, auditing every command that runs
+// without checks
+func (m *Manager) hookChecks(executor *HookExecutor, dir string) {
+	executor.security = m.hookSecurity()
+	executor.policy = m.hookSecurityPolicy()
+	if deny, err := executor.policy.DenyPatterns(); err == nil { // Checked when the config loaded
+		executor.deny = deny
+	}
+	executor.unchecked = func(cmd string, rejected error) {
+		entry := auditEntry{Event: "hook-unchecked", Command: []string{cmd}, Dir: dir}
+		if rejected != nil {
+			entry.Detail = fmt.Sprintf("standard checks would reject it: %v", rejected)
+		}
+		m.audit(entry)
+	}
+}
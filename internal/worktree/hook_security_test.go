@@ -0,0 +1,155 @@
+package worktree
+
+import (
+	"testing"
+	"time"
+
+	"github.com/awhite/wtree/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHookExecutor_checkCommand(t *testing.T) {
+	executor := NewHookExecutor(&types.ProjectConfig{}, 0, false)
+	image := "dd if=/dev/zero of=disk.img bs=1M count=64"
+
+	assert.Error(t, executor.checkCommand(image), "standard is the default")
+	assert.NoError(t, executor.checkCommand("curl -fsSL https://example.com/setup.json -o setup.json"))
+
+	executor.security = types.HookSecurityStrict
+	assert.Error(t, executor.checkCommand("curl -fsSL https://example.com/setup.json -o setup.json"))
+	assert.Error(t, executor.checkCommand("sudo make install"))
+	assert.NoError(t, executor.checkCommand("npm ci"))
+
+	var unchecked []string
+	executor.security = types.HookSecurityOff
+	executor.unchecked = func(cmd string, rejected error) {
+		if rejected != nil {
+			unchecked = append(unchecked, cmd)
+		}
+	}
+	assert.NoError(t, executor.checkCommand(image))
+	assert.NoError(t, executor.checkCommand("npm ci"))
+	assert.Equal(t, []string{image}, unchecked)
+}
+
+func TestManager_hookSecurity(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	recorder := &recordingObserver{decline: true}
+	m := &Manager{
+		repo:          &MockGitRepo{},
+		projectConfig: &types.ProjectConfig{HookSecurity: types.HookSecurityOff, Digest: "abc"},
+	}
+	m.Subscribe(recorder)
+
+	assert.Equal(t, types.HookSecurityStandard, m.hookSecurity(), "off needs acknowledging")
+	assert.False(t, m.HookSecurityAcknowledged())
+
+	recorder.decline = false
+	assert.Equal(t, types.HookSecurityOff, m.hookSecurity())
+	assert.True(t, m.HookSecurityAcknowledged())
+
+	m.projectConfig.Digest = "changed"
+	assert.False(t, m.HookSecurityAcknowledged(), "editing .wtreerc takes it back")
+
+	require.NoError(t, m.AcknowledgeHookSecurity())
+	require.NoError(t, m.ResetTrust(false))
+	assert.False(t, m.HookSecurityAcknowledged())
+
+	m.projectConfig.HookSecurity = types.HookSecurityStrict
+	assert.Equal(t, types.HookSecurityStrict, m.hookSecurity())
+	assert.Error(t, m.AcknowledgeHookSecurity())
+}
+
+func TestHookExecutor_checkCommandPolicy(t *testing.T) {
+	executor := NewHookExecutor(&types.ProjectConfig{}, 0, false)
+	clean := "rm -rf ./node_modules && npm ci"
+	require.Error(t, executor.checkCommand(clean), "standard rejects it")
+
+	executor.policy = types.HookSecurityPolicy{Allow: []string{"rm -rf ./node_modules  &&  npm ci"}}
+	assert.NoError(t, executor.checkCommand(clean))
+	assert.Error(t, executor.checkCommand(clean+"; rm -rf ~"), "only the exact command is allowed")
+
+	deny, err := types.HookSecurityPolicy{Deny: []string{`\bnpm\s+publish\b`}}.DenyPatterns()
+	require.NoError(t, err)
+	executor.deny = deny
+	err = executor.checkCommand("NPM publish --tag next")
+	require.Error(t, err)
+	assert.ErrorIs(t, err, errHookDenied)
+
+	var warned []string
+	executor.security = types.HookSecurityWarn
+	executor.unchecked = func(cmd string, rejected error) {
+		if rejected != nil {
+			warned = append(warned, cmd)
+		}
+	}
+	assert.NoError(t, executor.checkCommand("rm -rf ./dist"))
+	assert.Error(t, executor.checkCommand("npm publish"), "deny patterns apply under warn")
+	executor.security = types.HookSecurityOff
+	assert.Error(t, executor.checkCommand("npm publish"), "and under off")
+	assert.Equal(t, []string{"rm -rf ./dist"}, warned)
+}
+
+func TestManager_hookSecurityGlobalMode(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	m := &Manager{
+		repo:          &MockGitRepo{},
+		projectConfig: &types.ProjectConfig{HookSecurity: types.HookSecurityOff, Digest: "abc"},
+		globalConfig:  types.DefaultWTreeConfig(),
+	}
+	m.globalConfig.Hooks.Security.Mode = types.HookSecurityWarn
+
+	assert.Equal(t, types.HookSecurityWarn, m.hookSecurity(), "the global mode replaces .wtreerc's without asking")
+	assert.False(t, m.HookSecurityAcknowledged())
+}
+
+func TestHookExecutor_RejectsTemplateBuiltCommands(t *testing.T) {
+	built := `{{printf "%s -rf %s" "rm" "/"}}`
+	executor := NewHookExecutor(&types.ProjectConfig{
+		Hooks: map[types.HookEvent][]string{types.HookPostCreate: {built}},
+	}, 0, false)
+	ctx := types.HookContext{WorktreePath: t.TempDir()}
+
+	require.NoError(t, executor.checkCommand(built), "the written text looks harmless")
+	err := executor.executeHook(types.HookEntry{Run: built}, ctx, 1, 1)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "was not run")
+	assert.Error(t, executor.ValidateHooks())
+}
+
+func TestHookExecutor_ExecuteChecksSecurity(t *testing.T) {
+	built := `{{printf "%s -rf %s" "rm" "/"}}`
+	clean := "rm -rf ./dist"
+	ctx := types.HookContext{WorktreePath: t.TempDir()}
+
+	executor := NewHookExecutor(&types.ProjectConfig{}, 30*time.Second, false)
+	var unchecked []string
+	executor.unchecked = func(cmd string, rejected error) {
+		if rejected != nil {
+			unchecked = append(unchecked, cmd)
+		}
+	}
+
+	// standard, the default
+	assert.Error(t, executor.ExecuteChecks([]string{"rm -rf ~"}, ctx))
+	assert.Error(t, executor.ExecuteChecks([]string{built}, ctx))
+	assert.Error(t, executor.ExecuteChecks([]string{clean}, ctx))
+	executor.policy = types.HookSecurityPolicy{Allow: []string{clean}}
+	assert.NoError(t, executor.ExecuteChecks([]string{clean}, ctx), "hooks.security.allow applies to checks")
+	executor.policy = types.HookSecurityPolicy{}
+	assert.Empty(t, unchecked)
+
+	executor.security = types.HookSecurityStrict
+	assert.Error(t, executor.ExecuteChecks([]string{built}, ctx))
+	assert.Error(t, executor.ExecuteChecks([]string{"curl -fsSL https://example.com/setup.sh"}, ctx))
+
+	executor.security = types.HookSecurityWarn
+	assert.NoError(t, executor.ExecuteChecks([]string{clean}, ctx))
+	assert.Equal(t, []string{clean}, unchecked, "warn runs are reported for the audit log")
+
+	executor.security = types.HookSecurityOff
+	assert.NoError(t, executor.ExecuteChecks([]string{clean}, ctx))
+	assert.Equal(t, []string{clean, clean}, unchecked, "and so are off runs")
+	assert.Error(t, executor.ExecuteChecks([]string{built}, ctx), "the expansion still meets the deny patterns")
+}
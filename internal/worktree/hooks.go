@@ -2,12 +2,15 @@ package worktree
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"regexp"
+	"runtime"
 	"strings"
 	"time"
 	"unicode"
@@ -15,11 +18,45 @@ import (
 	"github.com/awhite/wtree/pkg/types"
 )
 
+const (
+	// hookOutputTail is how many lines of a timed-out hook's output are shown
+	hookOutputTail = 10
+	// hookKillGrace is how long a stopped hook's processes get between
+	// SIGTERM and SIGKILL
+	hookKillGrace = 2 * time.Second
+)
+
+// errHookTimeout is returned by run when a command outlives the hook timeout
+var errHookTimeout = errors.New("timed out")
+
+// errHookDenied is wrapped by checkCommand's rejections of commands matching
+// a global hooks.security.deny pattern
+var errHookDenied = errors.New("matches hooks.security.deny")
+
 // HookExecutor handles the execution of project-defined hooks
 type HookExecutor struct {
 	config  *types.ProjectConfig
 	timeout time.Duration
 	verbose bool
+	done    func(cmd string, took time.Duration) // Called after each hook; optional
+
+	// Whether a spinner with the elapsed time may be redrawn under a running
+	// hook, and where each run's output is logged (none when empty)
+	live   bool
+	logDir string
+	log    *hookLog // The log of the hooks running now
+
+	out io.Writer // Where progress and hook output are printed
+
+	// hooks.security level; empty means standard
+	security string
+	// Commands that skip the level's checks, and patterns rejected at every
+	// level, from the global hooks.security
+	policy types.HookSecurityPolicy
+	deny   []*regexp.Regexp
+	// Called for each command run unchecked under hooks.security: off, with
+	// what the standard checks would have rejected it for; optional
+	unchecked func(cmd string, rejected error)
 }
 
 // NewHookExecutor creates a new hook executor
@@ -28,77 +65,219 @@ func NewHookExecutor(config *types.ProjectConfig, timeout time.Duration, verbose
 		config:  config,
 		timeout: timeout,
 		verbose: verbose,
+		out:     os.Stdout,
 	}
 }
 
 // ExecuteHooks runs all hooks for the specified event
 func (he *HookExecutor) ExecuteHooks(event types.HookEvent, ctx types.HookContext) error {
-	hooks := he.config.Hooks[event]
+	hooks := he.config.HookEntriesFor(event, conditionEnv(ctx, runtime.GOOS))
 	if len(hooks) == 0 {
-		return nil // No hooks defined for this event
+		return nil // No hooks defined for this event, or none whose when: holds
 	}
 
-	fmt.Printf("Running %s hooks...\n", event)
+	fmt.Fprintf(he.out, "Running %s hooks...\n", event)
+	he.openLog(string(event))
+	defer he.closeLog()
 
-	for i, hookCmd := range hooks {
-		if err := he.executeHook(hookCmd, ctx, i+1, len(hooks)); err != nil {
-			return fmt.Errorf("hook failed: %s: %w", hookCmd, err)
+	for i, hook := range hooks {
+		if err := he.executeHook(hook, ctx, i+1, len(hooks)); err != nil {
+			if he.log != nil {
+				fmt.Fprintf(he.out, "    Output logged to %s\n", he.log.path)
+			}
+			if errors.Is(err, errHookTimeout) {
+				return err // Names the hook already
+			}
+			return fmt.Errorf("hook failed: %s: %w", hook.Run, err)
 		}
 	}
 
 	return nil
 }
 
-// executeHook runs a single hook command
-func (he *HookExecutor) executeHook(cmd string, ctx types.HookContext, current, total int) error {
-	// Show progress
-	fmt.Printf("  [%d/%d] Running: %s\n", current, total, cmd)
+// openLog starts capturing the output of the hooks about to run, named
+// after them, when there is a log directory
+func (he *HookExecutor) openLog(name string) {
+	if he.logDir == "" {
+		return
+	}
+	log, err := openHookLog(he.logDir, name)
+	if err != nil {
+		fmt.Fprintf(he.out, "  ⚠ Hook output won't be logged: %v\n", err)
+		return
+	}
+	he.log = log
+}
 
-	// Expand command with context variables
-	expandedCmd := he.expandCommand(cmd, ctx)
+// closeLog finishes the log openLog started
+func (he *HookExecutor) closeLog() {
+	he.log.close()
+	he.log = nil
+}
 
-	// Create execution context with timeout
-	execCtx, cancel := context.WithTimeout(context.Background(), he.timeout)
+// executeHook runs a single hook command, streaming its output unless it
+// sets output: quiet
+func (he *HookExecutor) executeHook(hook types.HookEntry, ctx types.HookContext, current, total int) error {
+	cmd := hook.Run
+	var argv []string
+	if types.IsHookRecipe(cmd) {
+		planned, err := planRecipe(cmd, ctx.WorktreePath)
+		if err != nil {
+			fmt.Fprintf(he.out, "  [%d/%d] Running %s\n    ✗ %v\n", current, total, cmd, err)
+			return err
+		}
+		argv = planned
+		fmt.Fprintf(he.out, "  [%d/%d] Running %s: %s\n", current, total, cmd, strings.Join(argv, " "))
+	} else {
+		// Show progress
+		fmt.Fprintf(he.out, "  [%d/%d] Running: %s\n", current, total, cmd)
+		expanded, err := he.expandCommand(cmd, ctx)
+		if err != nil {
+			fmt.Fprintf(he.out, "    ✗ %v\n", err)
+			return err
+		}
+		if err := he.checkExpanded(cmd, expanded); err != nil {
+			fmt.Fprintf(he.out, "    ✗ Rejected: %v\n", err)
+			return he.rejectedError(cmd, err)
+		}
+		argv = []string{"sh", "-c", expanded}
+	}
+
+	show := he.showOutput(hook)
+	he.log.start(cmd)
+	started := time.Now()
+	output, err := he.run(argv, ctx, show)
+	took := time.Since(started)
+	he.log.end(took, err)
+	if he.done != nil {
+		he.done(cmd, took)
+	}
+	if errors.Is(err, errHookTimeout) {
+		if show {
+			output = nil // Already shown
+		}
+		return he.timeoutError(cmd, took, output)
+	}
+	if err != nil {
+		if show {
+			fmt.Fprintf(he.out, "    ✗ Hook failed after %s\n", formatPhaseDuration(took))
+		} else {
+			fmt.Fprintf(he.out, "    ✗ Hook failed: %s\n", string(output))
+		}
+		return err
+	}
+
+	fmt.Fprintf(he.out, "    ✓ Completed in %s\n", formatPhaseDuration(took))
+	return nil
+}
+
+// run executes argv in the worktree with the hook timeout and environment,
+// returning its combined output. show prints the output line by line as it
+// arrives, and it is copied to the current log.
+func (he *HookExecutor) run(argv []string, ctx types.HookContext, show bool) ([]byte, error) {
+	// Create execution context with timeout, cancelled on SIGINT/SIGTERM
+	execCtx, cancel := context.WithTimeout(interruptCtx, he.timeout)
 	defer cancel()
 
 	// Prepare command execution
-	command := exec.CommandContext(execCtx, "sh", "-c", expandedCmd)
+	command := exec.CommandContext(execCtx, argv[0], argv[1:]...)
 	command.Dir = ctx.WorktreePath
 	command.Env = he.buildEnvironment(ctx)
-
-	// Execute command and capture output
-	output, err := command.CombinedOutput()
-
-	if err != nil {
-		fmt.Printf("    ✗ Hook failed: %s\n", string(output))
-		return err
+	killProcessGroup(command)
+	// Don't wait forever on output pipes a killed hook's children held open
+	command.WaitDelay = hookKillGrace
+
+	// Execute command, capturing and streaming its output
+	stream := newHookStream(he.out, show, he.live, he.log.writer())
+	command.Stdout = stream
+	command.Stderr = stream
+	err := command.Run()
+	output := stream.finish()
+	if err != nil && errors.Is(execCtx.Err(), context.DeadlineExceeded) {
+		return output, errHookTimeout
 	}
+	return output, err
+}
 
-	if he.verbose && len(output) > 0 {
-		fmt.Printf("    ✓ Output: %s\n", string(output))
-	} else {
-		fmt.Printf("    ✓ Completed\n")
+// timeoutError reports a command stopped at the hook timeout, with the end
+// of its output, and describes it: how long it ran and where to raise the
+// limit
+func (he *HookExecutor) timeoutError(cmd string, took time.Duration, output []byte) error {
+	fmt.Fprintf(he.out, "    ✗ Timed out after %s\n", he.timeout)
+	if tail := lastLines(string(output), hookOutputTail); tail != "" {
+		fmt.Fprintf(he.out, "    Last output:\n")
+		for _, line := range strings.Split(tail, "\n") {
+			fmt.Fprintf(he.out, "      %s\n", line)
+		}
 	}
 
-	return nil
+	hookErr := types.NewHookError("hook-timeout",
+		fmt.Sprintf("'%s' was stopped after running for %s", cmd, took.Round(time.Millisecond)),
+		fmt.Errorf("%w (limit %s)", errHookTimeout, he.timeout))
+	hookErr.BaseError = hookErr.WithSuggestedActions(
+		"Raise the hook time limit with 'timeout' in .wtreerc, or hooks.timeout in the global config",
+		"Check whether the command is waiting for input, which hooks don't get",
+	)
+	return hookErr
 }
 
-// expandCommand replaces placeholders in hook commands with actual values
-func (he *HookExecutor) expandCommand(cmd string, ctx types.HookContext) string {
-	replacements := map[string]string{
-		"{repo}":          filepath.Base(ctx.RepoPath),
-		"{branch}":        ctx.Branch,
-		"{target_branch}": ctx.TargetBranch,
-		"{worktree_path}": ctx.WorktreePath,
-		"{repo_path}":     ctx.RepoPath,
+// lastLines returns the last n lines of text, ignoring trailing newlines
+func lastLines(text string, n int) string {
+	lines := strings.Split(strings.TrimRight(text, "\n"), "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
 	}
+	return strings.Join(lines, "\n")
+}
+
+// ExecuteChecks runs check commands in order, stopping at the first failure.
+// The returned error carries the failing command's output.
+func (he *HookExecutor) ExecuteChecks(checks []string, ctx types.HookContext) error {
+	if len(checks) == 0 {
+		return nil
+	}
+
+	fmt.Fprintf(he.out, "Running pre-merge checks in %s...\n", ctx.WorktreePath)
+	he.openLog("pre_merge_checks")
+	defer he.closeLog()
+
+	for i, check := range checks {
+		fmt.Fprintf(he.out, "  [%d/%d] Running: %s\n", i+1, len(checks), check)
+		expanded, err := he.expandCommand(check, ctx)
+		if err != nil {
+			fmt.Fprintf(he.out, "    ✗ %v\n", err)
+			return err
+		}
+		if err := he.checkExpanded(check, expanded); err != nil {
+			fmt.Fprintf(he.out, "    ✗ Rejected: %v\n", err)
+			return he.rejectedError(check, err)
+		}
+
+		// The output is part of a failure's error, so it is only streamed
+		// with --verbose
+		he.log.start(check)
+		started := time.Now()
+		output, err := he.run([]string{"sh", "-c", expanded}, ctx, he.verbose)
+		took := time.Since(started)
+		he.log.end(took, err)
+		if errors.Is(err, errHookTimeout) {
+			return he.timeoutError(check, took, output)
+		}
+		if err != nil {
+			fmt.Fprintf(he.out, "    ✗ Check failed\n")
+			return types.NewHookError("pre-merge-check",
+				fmt.Sprintf("check '%s' failed:\n%s", check, strings.TrimRight(string(output), "\n")), err)
+		}
 
-	expanded := cmd
-	for placeholder, value := range replacements {
-		expanded = strings.ReplaceAll(expanded, placeholder, value)
+		fmt.Fprintf(he.out, "    ✓ Passed in %s\n", formatPhaseDuration(took))
 	}
 
-	return expanded
+	return nil
+}
+
+// expandCommand fills in a hook command's template from the hook context
+func (he *HookExecutor) expandCommand(cmd string, ctx types.HookContext) (string, error) {
+	return expandTemplate(cmd, cmd, hookTemplateData(ctx, he.config.BranchSlug))
 }
 
 // buildEnvironment creates the environment for hook execution
@@ -106,26 +285,41 @@ func (he *HookExecutor) buildEnvironment(ctx types.HookContext) []string {
 	// Start with current environment
 	env := os.Environ()
 
-	// Add WTree-specific environment variables
+	// Add WTree environment variables to env slice
+	for key, value := range hookEnvironment(ctx) {
+		env = append(env, fmt.Sprintf("%s=%s", key, value))
+	}
+
+	return env
+}
+
+// conditionEnv describes a hook context for evaluating when: conditions
+func conditionEnv(ctx types.HookContext, goos string) types.ConditionEnv {
+	return types.ConditionEnv{
+		OS:     goos,
+		Branch: ctx.Branch,
+		PR:     ctx.Environment["WTREE_PR_NUMBER"] != "",
+	}
+}
+
+// hookEnvironment returns the WTREE_* variables for a hook context, including
+// any custom variables from the context
+func hookEnvironment(ctx types.HookContext) map[string]string {
 	wtreeEnv := map[string]string{
 		"WTREE_EVENT":         string(ctx.Event),
 		"WTREE_BRANCH":        ctx.Branch,
+		"WTREE_REPO":          filepath.Base(ctx.RepoPath),
 		"WTREE_REPO_PATH":     ctx.RepoPath,
 		"WTREE_WORKTREE_PATH": ctx.WorktreePath,
 		"WTREE_TARGET_BRANCH": ctx.TargetBranch,
+		"WTREE_CONFIG_SOURCE": ctx.ConfigSource,
 	}
 
-	// Add WTree environment variables to env slice
-	for key, value := range wtreeEnv {
-		env = append(env, fmt.Sprintf("%s=%s", key, value))
-	}
-
-	// Add any custom environment variables from context
 	for key, value := range ctx.Environment {
-		env = append(env, fmt.Sprintf("%s=%s", key, value))
+		wtreeEnv[key] = value
 	}
 
-	return env
+	return wtreeEnv
 }
 
 // ValidateHooks checks if all hook commands are valid
@@ -137,8 +331,27 @@ func (he *HookExecutor) ValidateHooks() error {
 					fmt.Sprintf("empty hook command in %s", event), nil)
 			}
 
-			// Basic command validation - check for dangerous patterns
-			if err := he.validateHookCommand(hook); err != nil {
+			// Recipes run without a shell, so only the name needs checking
+			if types.IsHookRecipe(hook) {
+				if !types.KnownHookRecipe(hook) {
+					return types.NewValidationError("hook-validation",
+						fmt.Sprintf("unknown hook recipe in %s: %s", event, hook), nil)
+				}
+				continue
+			}
+
+			if _, err := parseTemplate(hook, hook); err != nil {
+				return types.NewValidationError("hook-validation",
+					fmt.Sprintf("invalid hook command in %s: %s", event, hook), err)
+			}
+
+			// Basic command validation - check for dangerous patterns, also
+			// in what template functions such as printf build
+			err := he.validateHookCommand(hook)
+			if expanded, expandErr := he.expandCommand(hook, types.HookContext{}); err == nil && expandErr == nil && expanded != hook {
+				err = he.validateHookCommand(expanded)
+			}
+			if err != nil {
 				return types.NewValidationError("hook-validation",
 					fmt.Sprintf("dangerous hook command in %s: %s", event, hook), err)
 			}
@@ -153,30 +366,137 @@ func (he *HookExecutor) validateHookCommand(cmd string) error {
 	// Log the command being validated for security auditing
 	log.Printf("Hook validation: Checking command: %s", cmd)
 
+	if err := he.checkStandardPatterns(cmd); err != nil {
+		log.Printf("Security violation: %v in command: %s", err, cmd)
+		return err
+	}
+
+	return nil
+}
+
+// checkStandardPatterns applies the dangerous, injection and obfuscation
+// checks of hooks.security: standard
+func (he *HookExecutor) checkStandardPatterns(cmd string) error {
 	// Normalize and clean the command for analysis
 	normalizedCmd := he.normalizeCommand(cmd)
 
 	// Check for dangerous patterns with comprehensive detection
 	if err := he.checkDangerousPatterns(normalizedCmd); err != nil {
-		log.Printf("Security violation: %v in command: %s", err, cmd)
 		return err
 	}
 
 	// Check for command injection techniques
 	if err := he.checkInjectionPatterns(normalizedCmd); err != nil {
-		log.Printf("Security violation: %v in command: %s", err, cmd)
 		return err
 	}
 
 	// Check for shell escape sequences and obfuscation
-	if err := he.checkObfuscationPatterns(cmd); err != nil {
-		log.Printf("Security violation: %v in command: %s", err, cmd)
+	return he.checkObfuscationPatterns(cmd)
+}
+
+// checkCommand applies the hooks.security level to a shell command before
+// it runs. Deny patterns reject at every level and allowlisted commands skip
+// the rest. Under off nothing else is rejected, and under warn the standard
+// checks only print a warning; either way the command is reported to
+// unchecked.
+func (he *HookExecutor) checkCommand(cmd string) error {
+	if err := he.checkDenied(cmd); err != nil {
 		return err
 	}
+	if he.policy.Allows(cmd) {
+		return nil
+	}
+
+	switch he.security {
+	case types.HookSecurityOff:
+		if he.unchecked != nil {
+			he.unchecked(cmd, he.checkStandardPatterns(cmd))
+		}
+		return nil
+	case types.HookSecurityWarn:
+		err := he.checkStandardPatterns(cmd)
+		if err != nil {
+			fmt.Fprintf(he.out, "    ⚠ %v; running it anyway (hooks.security.mode: warn)\n", err)
+		}
+		if he.unchecked != nil {
+			he.unchecked(cmd, err)
+		}
+		return nil
+	case types.HookSecurityStrict:
+		if err := he.checkStandardPatterns(cmd); err != nil {
+			return err
+		}
+		return he.checkStrictPatterns(he.normalizeCommand(cmd))
+	}
+	return he.checkStandardPatterns(cmd)
+}
+
+// checkExpanded applies checkCommand to a shell command as written and, when
+// its template changes it, as expanded: template functions such as printf
+// can build a command the written text doesn't show. An allowlisted command
+// is trusted as written; under off only the deny patterns apply to the
+// expansion, so each command is reported to unchecked once.
+func (he *HookExecutor) checkExpanded(cmd, expanded string) error {
+	if err := he.checkCommand(cmd); err != nil {
+		return err
+	}
+	if expanded == cmd || he.policy.Allows(cmd) {
+		return nil
+	}
+	if he.security == types.HookSecurityOff {
+		return he.checkDenied(expanded)
+	}
+	return he.checkCommand(expanded)
+}
+
+// checkDenied rejects a command matching one of the global deny patterns
+func (he *HookExecutor) checkDenied(cmd string) error {
+	normalizedCmd := he.normalizeCommand(cmd)
+	for _, pattern := range he.deny {
+		if pattern.MatchString(normalizedCmd) {
+			return fmt.Errorf("%w pattern '%s'", errHookDenied, strings.TrimPrefix(pattern.String(), "(?i)"))
+		}
+	}
+	return nil
+}
+
+// checkStrictPatterns rejects what hooks.security: strict adds: raising
+// privileges, downloading, and evaluating built-up strings
+func (he *HookExecutor) checkStrictPatterns(normalizedCmd string) error {
+	strictPatterns := []struct {
+		pattern     *regexp.Regexp
+		description string
+	}{
+		{regexp.MustCompile(`\b(sudo|doas|su)\b`), "privilege escalation"},
+		{regexp.MustCompile(`\b(curl|wget)\b`), "downloads"},
+		{regexp.MustCompile(`\beval\b`), "eval"},
+	}
+
+	for _, sp := range strictPatterns {
+		if sp.pattern.MatchString(normalizedCmd) {
+			return fmt.Errorf("not allowed by hooks.security strict: %s", sp.description)
+		}
+	}
 
 	return nil
 }
 
+// rejectedError describes a command the hooks.security checks stopped
+func (he *HookExecutor) rejectedError(cmd string, reason error) error {
+	hookErr := types.NewHookError("hook-validation", fmt.Sprintf("'%s' was not run", cmd), reason)
+	if errors.Is(reason, errHookDenied) {
+		hookErr.BaseError = hookErr.WithSuggestedActions(
+			"The pattern is in hooks.security.deny of your global config; remove it there if the command is intended",
+		)
+		return hookErr
+	}
+	hookErr.BaseError = hookErr.WithSuggestedActions(
+		"If the command is intended, add it to hooks.security.allow in your global config",
+		"Or set hooks.security: off in .wtreerc; wtree asks once to trust the file",
+	)
+	return hookErr
+}
+
 // normalizeCommand removes comments, extra spaces, and normalizes case for analysis
 func (he *HookExecutor) normalizeCommand(cmd string) string {
 	// Remove shell comments (everything after unescaped #)
@@ -339,12 +659,17 @@ func NewHookRunner(config *types.ProjectConfig, timeout time.Duration, verbose,
 func (hr *HookRunner) RunHooks(event types.HookEvent, ctx types.HookContext) error {
 	err := hr.executor.ExecuteHooks(event, ctx)
 	if err != nil && hr.allowFailure {
-		fmt.Printf("⚠ Hook %s failed but continuing due to allow_failure: %v\n", event, err)
+		fmt.Fprintf(hr.executor.out, "⚠ Hook %s failed but continuing due to allow_failure: %v\n", event, err)
 		return nil
 	}
 	return err
 }
 
+// OnHookDone registers fn to be called with each hook's duration once it finishes
+func (hr *HookRunner) OnHookDone(fn func(cmd string, took time.Duration)) {
+	hr.executor.done = fn
+}
+
 // Validate validates the hook configuration
 func (hr *HookRunner) Validate() error {
 	return hr.executor.ValidateHooks()
@@ -44,11 +44,22 @@ func TestHookExecutor_expandCommand(t *testing.T) {
 			command:  "echo {repo}",
 			expected: "echo repo", // filepath.Base("/path/to/repo")
 		},
+		{
+			name:     "template fields and helpers",
+			command:  "echo {{.Repo}} {{.BranchSlug}} {{.Branch | truncate 7 | lower}}",
+			expected: "echo repo feature-branch feature",
+		},
+		{
+			name:     "unknown placeholder kept",
+			command:  "echo {other} ${HOME}",
+			expected: "echo {other} ${HOME}",
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := executor.expandCommand(tt.command, ctx)
+			result, err := executor.expandCommand(tt.command, ctx)
+			assert.NoError(t, err)
 			assert.Equal(t, tt.expected, result)
 		})
 	}
@@ -120,6 +131,7 @@ func TestHookExecutor_buildEnvironment(t *testing.T) {
 	expectedVars := map[string]string{
 		"WTREE_EVENT":         "post_create",
 		"WTREE_BRANCH":        "test-branch",
+		"WTREE_REPO":          "repo",
 		"WTREE_REPO_PATH":     "/repo",
 		"WTREE_WORKTREE_PATH": "/worktree",
 		"CUSTOM_VAR":          "custom_value",
@@ -161,6 +173,23 @@ func TestHookExecutor_ValidateHooks(t *testing.T) {
 			},
 			expectError: true,
 		},
+		{
+			name: "known recipe",
+			config: &types.ProjectConfig{
+				Hooks: map[types.HookEvent][]string{
+					types.HookPostCreate: {types.RecipeNodeInstall},
+				},
+			},
+		},
+		{
+			name: "unknown recipe",
+			config: &types.ProjectConfig{
+				Hooks: map[types.HookEvent][]string{
+					types.HookPostCreate: {"@make-coffee"},
+				},
+			},
+			expectError: true,
+		},
 		{
 			name: "dangerous hook command",
 			config: &types.ProjectConfig{
@@ -184,3 +213,59 @@ func TestHookExecutor_ValidateHooks(t *testing.T) {
 		})
 	}
 }
+
+func TestHookExecutor_ExecuteChecks(t *testing.T) {
+	executor := NewHookExecutor(&types.ProjectConfig{}, 30*time.Second, false)
+	ctx := types.HookContext{
+		Branch:       "feature",
+		WorktreePath: t.TempDir(),
+	}
+
+	t.Run("all checks pass", func(t *testing.T) {
+		err := executor.ExecuteChecks([]string{"true", "echo {branch}"}, ctx)
+		assert.NoError(t, err)
+	})
+
+	t.Run("failure includes output", func(t *testing.T) {
+		err := executor.ExecuteChecks([]string{"echo 'tests failed: 3' && exit 1", "echo never"}, ctx)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "tests failed: 3")
+
+		var hookErr *types.HookError
+		assert.ErrorAs(t, err, &hookErr)
+	})
+
+	t.Run("no checks", func(t *testing.T) {
+		assert.NoError(t, executor.ExecuteChecks(nil, ctx))
+	})
+}
+
+func TestHookExecutor_Timeout(t *testing.T) {
+	executor := NewHookExecutor(&types.ProjectConfig{}, 200*time.Millisecond, false)
+	ctx := types.HookContext{WorktreePath: t.TempDir()}
+
+	err := executor.executeHook(types.HookEntry{Run: "echo installing; echo waiting for lock; sleep 30"}, ctx, 1, 1)
+	assert.ErrorIs(t, err, errHookTimeout)
+	assert.Contains(t, err.Error(), "was stopped after running for")
+	assert.Contains(t, err.Error(), "limit 200ms")
+
+	var hookErr *types.HookError
+	if assert.ErrorAs(t, err, &hookErr) {
+		assert.Contains(t, hookErr.SuggestedActions()[0], "timeout")
+	}
+}
+
+func TestLastLines(t *testing.T) {
+	assert.Equal(t, "b\nc", lastLines("a\nb\nc\n", 2))
+	assert.Equal(t, "a", lastLines("a", 5))
+}
+
+func TestHookExecutor_expandCommand_Errors(t *testing.T) {
+	executor := NewHookExecutor(&types.ProjectConfig{}, 30*time.Second, false)
+
+	_, err := executor.expandCommand("echo {{.Branch", types.HookContext{})
+	assert.ErrorContains(t, err, "invalid template")
+
+	_, err = executor.expandCommand("echo {{.Unknown}}", types.HookContext{})
+	assert.ErrorContains(t, err, "failed to expand")
+}
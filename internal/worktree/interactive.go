@@ -0,0 +1,296 @@
+package worktree
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/awhite/wtree/pkg/types"
+)
+
+// parseSelection parses a selection line such as "1 3", "2,4", "2-5" or
+// "all" into sorted, zero-based indexes into a list of max items
+func parseSelection(input string, max int) ([]int, error) {
+	fields := strings.FieldsFunc(input, func(r rune) bool {
+		return r == ' ' || r == ',' || r == '\t'
+	})
+
+	seen := make(map[int]bool)
+	for _, field := range fields {
+		if strings.EqualFold(field, "all") {
+			for i := 0; i < max; i++ {
+				seen[i] = true
+			}
+			continue
+		}
+
+		start, end := field, field
+		if before, after, ok := strings.Cut(field, "-"); ok {
+			start, end = before, after
+		}
+		first, err := strconv.Atoi(start)
+		if err != nil {
+			return nil, fmt.Errorf("invalid selection: %s", field)
+		}
+		last, err := strconv.Atoi(end)
+		if err != nil {
+			return nil, fmt.Errorf("invalid selection: %s", field)
+		}
+		if first > last {
+			first, last = last, first
+		}
+		if first < 1 || last > max {
+			return nil, fmt.Errorf("invalid selection: %s (choose 1-%d)", field, max)
+		}
+		for i := first; i <= last; i++ {
+			seen[i-1] = true
+		}
+	}
+
+	indexes := make([]int, 0, len(seen))
+	for i := range seen {
+		indexes = append(indexes, i)
+	}
+	sort.Ints(indexes)
+	return indexes, nil
+}
+
+// selectBranches shows branches with checkboxes and lets the user toggle
+// entries until an empty line confirms. It returns nil when cancelled.
+func (m *Manager) selectBranches(branches []string, existing map[string]bool) []string {
+	selected := make(map[int]bool)
+	reader := bufio.NewReader(os.Stdin)
+
+	for {
+		m.ui.Info("\nAvailable branches:")
+		for i, branch := range branches {
+			mark := "[ ]"
+			if selected[i] {
+				mark = "[x]"
+			}
+			status := ""
+			if existing[branch] {
+				status = " [has worktree]"
+			}
+			if note := m.branchNote(branch); note != "" {
+				status += " - " + note
+			}
+			m.ui.Info("  %s %d. %s%s", mark, i+1, branch, status)
+		}
+
+		m.ui.Info("\nToggle entries by number (e.g. 1 3 5-7, all), Enter to confirm, q to cancel:")
+
+		line, err := reader.ReadString('\n')
+		input := strings.TrimSpace(line)
+		if (err != nil && input == "") || strings.EqualFold(input, "q") {
+			return nil
+		}
+
+		if input == "" {
+			if len(selected) == 0 {
+				return nil
+			}
+			var result []string
+			for i, branch := range branches {
+				if selected[i] {
+					result = append(result, branch)
+				}
+			}
+			return result
+		}
+
+		indexes, err := parseSelection(input, len(branches))
+		if err != nil {
+			m.warn("%v", err)
+			continue
+		}
+		for _, i := range indexes {
+			if selected[i] {
+				delete(selected, i)
+			} else {
+				selected[i] = true
+			}
+		}
+	}
+}
+
+// batchResult is the outcome of one branch in an interactive batch
+type batchResult struct {
+	branch string
+	err    error
+}
+
+// createBatch creates worktrees for several branches, printing a progress row
+// as each finishes and a report at the end
+func (m *Manager) createBatch(branches []string) {
+	// Each create reports through the progress rows instead of printing
+	quiet := m.quietCopy()
+	quiet.timings = nil
+
+	results := make([]batchResult, len(branches))
+	for i, branch := range branches {
+		results[i] = batchResult{branch: branch, err: quiet.Create(branch, CreateOptions{})}
+		if results[i].err != nil {
+			m.ui.Error("[%d/%d] %s: %v", i+1, len(branches), branch, results[i].err)
+		} else {
+			m.completed("[%d/%d] %s", i+1, len(branches), branch)
+		}
+	}
+
+	paths := make(map[string]string)
+	if worktrees, err := m.repo.ListWorktrees(); err == nil {
+		for _, wt := range worktrees {
+			paths[wt.Branch] = wt.Path
+		}
+	}
+
+	m.ui.Header("Create Summary")
+	table := m.ui.NewTable()
+	table.SetHeaders("Branch", "Path", "Result")
+	created := 0
+	for _, result := range results {
+		outcome := "created"
+		if result.err != nil {
+			outcome = "failed: " + result.err.Error()
+		} else {
+			created++
+		}
+		table.AddRow(result.branch, paths[result.branch], outcome)
+	}
+	table.Render()
+
+	if created < len(branches) {
+		m.warn("Created %d/%d worktrees", created, len(branches))
+	} else {
+		m.completed("Created %d/%d worktrees", created, len(branches))
+	}
+}
+
+// cleanupBatch removes the worktrees of several branches using the cleanup
+// worker pool and prints the cleanup report
+func (m *Manager) cleanupBatch(branches []string, worktrees []*types.WorktreeInfo) {
+	byBranch := make(map[string]*types.WorktreeInfo)
+	for _, wt := range worktrees {
+		byBranch[wt.Branch] = wt
+	}
+
+	var candidates []CleanupCandidate
+	for _, branch := range branches {
+		wt := byBranch[branch]
+		if wt == nil || wt.IsMainRepo {
+			m.warn("Skipping %s: not a removable worktree", branch)
+			continue
+		}
+		candidates = append(candidates, CleanupCandidate{
+			Branch: branch,
+			Path:   wt.Path,
+			Reason: "selected",
+			Trash:  m.useTrash(DeleteOptions{}),
+		})
+	}
+	if len(candidates) == 0 {
+		return
+	}
+
+	// Selected worktrees may hold work; show it and ask what to do first
+	if dirty := m.markDirtyCandidates(candidates); dirty > 0 {
+		m.showDirtyChanges(candidates)
+		candidates = m.resolveDirtyCandidates(candidates, dirty, CleanupOptions{})
+		if len(candidates) == 0 {
+			m.ui.Info("Nothing left to clean up")
+			return
+		}
+	}
+
+	if err := m.confirm(ConfirmCleanup, fmt.Sprintf("Clean up %d worktrees?", len(candidates))); err != nil {
+		m.ui.Info("Cleanup cancelled")
+		return
+	}
+
+	if cleaned := m.renderCleanupSummary(m.removeCleanupCandidates(candidates)); cleaned > 0 {
+		m.notify(types.NotifyCleanup, map[string]string{"count": strconv.Itoa(cleaned)})
+	}
+}
+
+// fuzzyMatch reports whether the characters of pattern appear in order in s,
+// ignoring case
+func fuzzyMatch(pattern, s string) bool {
+	s = strings.ToLower(s)
+	for _, r := range strings.ToLower(pattern) {
+		i := strings.IndexRune(s, r)
+		if i < 0 {
+			return false
+		}
+		s = s[i+len(string(r)):]
+	}
+	return true
+}
+
+// pickWorktree lets the user choose an existing worktree by number or by
+// typing part of its branch or path. The picker is drawn on stderr so stdout
+// carries only the command for eval. It returns nil when cancelled.
+func (m *Manager) pickWorktree() (*types.WorktreeInfo, error) {
+	worktrees, err := m.repo.ListWorktrees()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list worktrees: %w", err)
+	}
+	if len(worktrees) == 0 {
+		return nil, types.NewValidationError("switch", "no worktrees found", nil)
+	}
+
+	// Dirty state and last activity for each row
+	details := make(map[string]string)
+	for _, wt := range worktrees {
+		state := "clean"
+		if status, err := m.repo.GetWorktreeStatus(wt.Path); err == nil && !status.IsClean {
+			state = "dirty"
+		}
+		activity := "-"
+		if commit, err := m.repo.GetLastCommit(wt.Path); err == nil && commit != nil {
+			activity = m.ui.Time(commit.Time)
+		}
+		details[wt.Path] = fmt.Sprintf("%-5s  %s", state, activity)
+	}
+
+	reader := bufio.NewReader(os.Stdin)
+	matches := worktrees
+	for {
+		fmt.Fprintln(os.Stderr)
+		for i, wt := range matches {
+			fmt.Fprintf(os.Stderr, "  %d. %-30s %s  %s\n", i+1, wt.DisplayBranch(), details[wt.Path], wt.Path)
+		}
+		fmt.Fprint(os.Stderr, "\nSelect a worktree by number or type to filter (Enter to cancel): ")
+
+		line, _ := reader.ReadString('\n')
+		input := strings.TrimSpace(line)
+		if input == "" {
+			return nil, nil
+		}
+
+		if n, err := strconv.Atoi(input); err == nil {
+			if n < 1 || n > len(matches) {
+				fmt.Fprintf(os.Stderr, "invalid selection: %d\n", n)
+				continue
+			}
+			return matches[n-1], nil
+		}
+
+		var filtered []*types.WorktreeInfo
+		for _, wt := range worktrees {
+			if fuzzyMatch(input, wt.DisplayBranch()) || fuzzyMatch(input, wt.Path) {
+				filtered = append(filtered, wt)
+			}
+		}
+		switch len(filtered) {
+		case 0:
+			fmt.Fprintf(os.Stderr, "no worktree matches '%s'\n", input)
+		case 1:
+			return filtered[0], nil
+		default:
+			matches = filtered
+		}
+	}
+}
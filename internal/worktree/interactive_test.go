@@ -0,0 +1,48 @@
+package worktree
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSelection(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    []int
+		wantErr bool
+	}{
+		{input: "1", want: []int{0}},
+		{input: "1 3 5", want: []int{0, 2, 4}},
+		{input: "2,4, 4", want: []int{1, 3}},
+		{input: "2-4", want: []int{1, 2, 3}},
+		{input: "4-2 1", want: []int{0, 1, 2, 3}},
+		{input: "all", want: []int{0, 1, 2, 3, 4}},
+		{input: "", want: []int{}},
+		{input: "0", wantErr: true},
+		{input: "6", wantErr: true},
+		{input: "2-9", wantErr: true},
+		{input: "x", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := parseSelection(tt.input, 5)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestFuzzyMatch(t *testing.T) {
+	assert.True(t, fuzzyMatch("fa", "feature/auth"))
+	assert.True(t, fuzzyMatch("AUTH", "feature/auth"))
+	assert.True(t, fuzzyMatch("", "main"))
+	assert.False(t, fuzzyMatch("af", "fa"))
+	assert.False(t, fuzzyMatch("x", "main"))
+}
@@ -0,0 +1,90 @@
+package worktree
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/awhite/wtree/pkg/types"
+)
+
+// interruptGrace is how long an interrupted operation gets to roll back
+// before wtree gives up on it and exits
+const interruptGrace = 10 * time.Second
+
+var (
+	// interruptCtx is cancelled by the first SIGINT or SIGTERM; hooks run
+	// under it, so their process groups are killed
+	interruptCtx, cancelInterrupt = context.WithCancel(context.Background())
+	interrupted                   atomic.Bool
+)
+
+// HandleInterrupts makes SIGINT and SIGTERM stop the running operation
+// cleanly: running hooks are killed with their child processes, and the
+// operation rolls back what it did and releases its locks as it returns,
+// failing with types.ErrInterrupted; onSignal is called first. On a second
+// signal, or when rolling back takes longer than interruptGrace, release is
+// called and wtree exits at once with types.ExitInterrupted, leaving the
+// rollback to 'wtree rollback --last'. The returned function stops the
+// handling.
+func HandleInterrupts(onSignal, release func()) (stop func()) {
+	signals := make(chan os.Signal, 2)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	done := make(chan struct{})
+
+	go func() {
+		select {
+		case <-signals:
+		case <-done:
+			return
+		}
+		interrupted.Store(true)
+		onSignal()
+		cancelInterrupt()
+		fmt.Fprintln(os.Stderr, "\nInterrupted; rolling back (press Ctrl-C again to exit now)")
+
+		select {
+		case <-signals:
+		case <-time.After(interruptGrace):
+		case <-done:
+			return
+		}
+		release()
+		fmt.Fprintln(os.Stderr, "Exiting before rollback finished; run 'wtree rollback --last' to undo the partial operation")
+		os.Exit(types.ExitInterrupted)
+	}()
+
+	return func() {
+		signal.Stop(signals)
+		close(done)
+	}
+}
+
+// Interrupted reports whether SIGINT or SIGTERM has stopped the operation
+func Interrupted() bool {
+	return interrupted.Load()
+}
+
+// ReleaseLocks releases every operation lock the manager holds, for exiting
+// without unwinding the operation that took them
+func (m *Manager) ReleaseLocks() {
+	if m.lockManager != nil {
+		_ = m.lockManager.ReleaseAll()
+	}
+}
+
+// abortIfInterrupted rolls back what the operation has done so far and
+// returns types.ErrInterrupted once a signal has stopped it; operations
+// call it between steps
+func (m *Manager) abortIfInterrupted() error {
+	if !Interrupted() {
+		return nil
+	}
+	m.warn("Interrupted; rolling back")
+	_ = m.runRollback()
+	return types.ErrInterrupted
+}
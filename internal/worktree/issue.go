@@ -0,0 +1,110 @@
+package worktree
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/awhite/wtree/internal/github"
+	"github.com/awhite/wtree/pkg/types"
+)
+
+// issueSlugLength caps the part of a generated branch name taken from the
+// issue title
+const issueSlugLength = 40
+
+// issueBranchName generates a branch name from an issue: fix-<n>-<title>
+// for bugs and issue-<n>-<title> otherwise
+func issueBranchName(issue *github.IssueInfo) string {
+	kind := "issue"
+	for _, label := range issue.Labels {
+		if strings.EqualFold(label, "bug") {
+			kind = "fix"
+		}
+	}
+
+	var words []string
+	for _, word := range slugWords(issue.Title) {
+		if len(strings.Join(append(words, word), "-")) > issueSlugLength {
+			break
+		}
+		words = append(words, word)
+	}
+
+	name := fmt.Sprintf("%s-%d", kind, issue.Number)
+	if len(words) > 0 {
+		name += "-" + strings.Join(words, "-")
+	}
+	return name
+}
+
+// recordIssue stores the issue a worktree was created for
+func recordIssue(worktreePath string, issue *github.IssueInfo) error {
+	_, err := saveWorktreeMetaJSON("record-issue", worktreePath, "issue.json", issue)
+	return err
+}
+
+// worktreeIssue returns the issue a worktree was created for, or nil
+func worktreeIssue(worktreePath string) *github.IssueInfo {
+	var issue github.IssueInfo
+	if found, _ := loadWorktreeMetaJSON("read-issue", worktreePath, "issue.json", &issue); !found {
+		return nil
+	}
+	return &issue
+}
+
+// CreateFromIssue creates a worktree on a new branch for a GitHub issue. The
+// branch is named after the issue unless branch is given, the issue title
+// becomes the branch note, and the issue is recorded with the worktree.
+func (m *Manager) CreateFromIssue(number int, branch string, options IssueOptions) error {
+	if m.github == nil {
+		return types.NewConfigError("github", "creating from an issue needs the GitHub CLI", nil)
+	}
+	if err := m.github.IsAvailable(); err != nil {
+		return err
+	}
+	issue, err := m.github.GetIssue(number)
+	if err != nil {
+		return err
+	}
+	if issue.State != "open" {
+		m.warn("Issue #%d is %s", issue.Number, issue.State)
+	}
+
+	if branch == "" {
+		branch = issueBranchName(issue)
+	}
+	create := options.Create
+	create.CreateBranch = true
+	// The issue title describes the branch, unless it already has a description
+	if create.Note == "" && m.branchNote(branch) == "" {
+		create.Note = fmt.Sprintf("#%d %s", issue.Number, issue.Title)
+	}
+
+	m.ui.Info("Issue #%d: %s", issue.Number, issue.Title)
+	if err := m.Create(branch, create); err != nil {
+		return err
+	}
+	if create.DryRun {
+		if options.Comment {
+			m.ui.Info("[DRY RUN] Would comment on issue #%d", issue.Number)
+		}
+		return nil
+	}
+
+	wt := m.branchWorktree(branch)
+	if wt == nil {
+		return nil
+	}
+	if err := recordIssue(wt.Path, issue); err != nil {
+		m.warn("Failed to record the issue: %v", err)
+	}
+	if options.Comment {
+		body := fmt.Sprintf("Started work on this in branch `%s`.", branch)
+		if err := m.github.CommentOnIssue(issue.Number, body); err != nil {
+			m.warn("Failed to comment on issue #%d: %v", issue.Number, err)
+		} else {
+			m.completed("Commented on %s", issue.URL)
+		}
+	}
+	return nil
+}
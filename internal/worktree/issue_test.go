@@ -0,0 +1,32 @@
+package worktree
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/awhite/wtree/internal/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIssueBranchName(t *testing.T) {
+	assert.Equal(t, "fix-456-login-times-out-on-slow-networks",
+		issueBranchName(&github.IssueInfo{Number: 456, Title: "Login times out on slow networks!", Labels: []string{"Bug"}}))
+	assert.Equal(t, "issue-7-add-dark-mode",
+		issueBranchName(&github.IssueInfo{Number: 7, Title: "Add: dark mode", Labels: []string{"enhancement"}}))
+	assert.Equal(t, "issue-8", issueBranchName(&github.IssueInfo{Number: 8, Title: "🎉"}))
+
+	long := issueBranchName(&github.IssueInfo{Number: 9, Title: "a very long issue title that goes on and on about many things"})
+	assert.Equal(t, "issue-9-a-very-long-issue-title-that-goes-on-and", long)
+}
+
+func TestRecordIssue(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "fix-456")
+	require.NoError(t, os.MkdirAll(filepath.Join(path, ".git"), 0755))
+	assert.Nil(t, worktreeIssue(path))
+
+	issue := &github.IssueInfo{Number: 456, Title: "Login times out", URL: "https://github.com/o/r/issues/456"}
+	require.NoError(t, recordIssue(path, issue))
+	assert.Equal(t, issue, worktreeIssue(path))
+}
@@ -0,0 +1,375 @@
+package worktree
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/awhite/wtree/pkg/types"
+)
+
+// JobFileEnv names the environment variable a background wtree process finds
+// its job record through, so it can record how it finished
+const JobFileEnv = "WTREE_JOB_FILE"
+
+// Job states reported by Job.State
+const (
+	JobRunning   = "running"
+	JobDone      = "done"
+	JobFailed    = "failed"
+	JobLost      = "lost" // The process exited without recording a result
+	JobCancelled = "cancelled"
+)
+
+// Job is a wtree command running detached in the background, recorded under
+// the repository's git directory with its output in a log next to it
+type Job struct {
+	ID        string    `json:"id"`
+	Kind      string    `json:"kind"`   // e.g. "setup"
+	Target    string    `json:"target"` // Branch or worktree the job works on
+	Args      []string  `json:"args"`
+	PID       int       `json:"pid"`
+	StartedAt time.Time `json:"started_at"`
+	Log       string    `json:"log"`
+
+	// From the job's result, once it has finished
+	FinishedAt *time.Time `json:"-"`
+	Error      string     `json:"-"`
+	Cancelled  bool       `json:"-"`
+}
+
+// State reports whether the job is running, done, failed or lost
+func (j *Job) State() string {
+	switch {
+	case j.Cancelled:
+		return JobCancelled
+	case j.FinishedAt != nil && j.Error == "":
+		return JobDone
+	case j.FinishedAt != nil:
+		return JobFailed
+	case processAlive(j.PID):
+		return JobRunning
+	default:
+		return JobLost
+	}
+}
+
+// processAlive reports whether a process with pid exists
+func processAlive(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	// FindProcess only succeeds for live processes on Windows; elsewhere it
+	// always succeeds and signal 0 probes without delivering anything
+	if runtime.GOOS == "windows" {
+		return true
+	}
+	return process.Signal(syscall.Signal(0)) == nil
+}
+
+// jobsDir returns where the repository's jobs are recorded, shared by all of
+// its worktrees
+func (m *Manager) jobsDir() (string, error) {
+	commonDir, err := m.repo.GetGitCommonDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(commonDir, "wtree", "jobs"), nil
+}
+
+// newJobID returns a short random job identifier
+func newJobID() string {
+	b := make([]byte, 4)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("%08x", time.Now().UnixNano()&0xffffffff)
+	}
+	return hex.EncodeToString(b)
+}
+
+// StartJob runs wtree with args as a detached background process in the main
+// worktree and records it as a job of kind working on target
+func (m *Manager) StartJob(kind, target string, args []string) (*Job, error) {
+	dir, err := m.jobsDir()
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, types.NewFileSystemError("start-job", dir, "failed to create jobs directory", err)
+	}
+
+	executable, err := os.Executable()
+	if err != nil {
+		return nil, fmt.Errorf("failed to locate the wtree executable: %w", err)
+	}
+
+	job := &Job{
+		ID:        newJobID(),
+		Kind:      kind,
+		Target:    target,
+		Args:      args,
+		StartedAt: time.Now(),
+	}
+	job.Log = filepath.Join(dir, job.ID+".log")
+	jobFile := filepath.Join(dir, job.ID+".json")
+
+	logFile, err := os.Create(job.Log)
+	if err != nil {
+		return nil, types.NewFileSystemError("start-job", job.Log, "failed to create job log", err)
+	}
+	defer logFile.Close()
+
+	// Run with the same global config as this process
+	if file := m.configMgr.GlobalConfigFile(); file != "" {
+		args = append([]string{"--config", file}, args...)
+	}
+	command := exec.Command(executable, args...)
+	command.Dir = m.mainWorktreePath()
+	command.Stdout = logFile
+	command.Stderr = logFile
+	command.Env = append(os.Environ(), JobFileEnv+"="+jobFile, "WTREE_NO_COLOR=true")
+	detachProcess(command)
+	if err := command.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start background job: %w", err)
+	}
+	job.PID = command.Process.Pid
+	_ = command.Process.Release()
+
+	if err := writeJob(jobFile, job); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+// writeJob stores a job record
+func writeJob(path string, job *Job) error {
+	data, err := json.MarshalIndent(job, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode job: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return types.NewFileSystemError("write-job", path, "failed to write job record", err)
+	}
+	return nil
+}
+
+// jobResult is how a background job finished. The job writes it to its own
+// file so it can never race the parent writing the job record.
+type jobResult struct {
+	FinishedAt time.Time `json:"finished_at"`
+	Error      string    `json:"error,omitempty"`
+	Cancelled  bool      `json:"cancelled,omitempty"`
+}
+
+// jobResultPath returns where the job recorded at jobFile stores its result
+func jobResultPath(jobFile string) string {
+	return strings.TrimSuffix(jobFile, ".json") + ".result"
+}
+
+// readJob loads a job record along with its result, if it has finished
+func readJob(path string) (*Job, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var job Job
+	if err := json.Unmarshal(data, &job); err != nil {
+		return nil, fmt.Errorf("invalid job record %s: %w", path, err)
+	}
+
+	if data, err := os.ReadFile(jobResultPath(path)); err == nil {
+		var result jobResult
+		if json.Unmarshal(data, &result) == nil {
+			job.FinishedAt = &result.FinishedAt
+			job.Error = result.Error
+			job.Cancelled = result.Cancelled
+		}
+	}
+	return &job, nil
+}
+
+// FinishJob records the result of the background job whose record is at path.
+// It is called by the background process itself and does nothing when path
+// is empty.
+func FinishJob(path string, runErr error) {
+	if path == "" {
+		return
+	}
+	result := jobResult{FinishedAt: time.Now()}
+	if runErr != nil {
+		result.Error = runErr.Error()
+	}
+	_ = writeJobResult(path, result)
+}
+
+// writeJobResult stores how the job recorded at jobFile finished, unless a
+// result is already there (a cancelled job keeps its cancellation)
+func writeJobResult(jobFile string, result jobResult) error {
+	file, err := os.OpenFile(jobResultPath(jobFile), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	return json.NewEncoder(file).Encode(result)
+}
+
+// ListJobs returns the repository's jobs, newest first
+func (m *Manager) ListJobs() ([]*Job, error) {
+	dir, err := m.jobsDir()
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, types.NewFileSystemError("list-jobs", dir, "failed to read jobs directory", err)
+	}
+
+	var jobs []*Job
+	for _, entry := range entries {
+		if !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		job, err := readJob(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			continue
+		}
+		jobs = append(jobs, job)
+	}
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].StartedAt.After(jobs[j].StartedAt) })
+	return jobs, nil
+}
+
+// ShowJobs prints the repository's background jobs
+func (m *Manager) ShowJobs() error {
+	jobs, err := m.ListJobs()
+	if err != nil {
+		return err
+	}
+
+	m.ui.Header("Background Jobs")
+	if len(jobs) == 0 {
+		m.ui.Info("No background jobs")
+		return nil
+	}
+
+	table := m.ui.NewTable()
+	table.SetHeaders("ID", "Kind", "Target", "State", "Started")
+	for _, job := range jobs {
+		table.AddRow(job.ID, job.Kind, job.Target, job.State(), m.ui.Time(job.StartedAt))
+	}
+	table.Render()
+	return nil
+}
+
+// findJob returns the job whose ID is or starts with id
+func (m *Manager) findJob(id string) (*Job, error) {
+	jobs, err := m.ListJobs()
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []*Job
+	for _, job := range jobs {
+		if job.ID == id {
+			return job, nil
+		}
+		if id != "" && strings.HasPrefix(job.ID, id) {
+			matches = append(matches, job)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return nil, types.NewValidationError("job", fmt.Sprintf("no job '%s'; see 'wtree jobs list'", id), nil)
+	case 1:
+		return matches[0], nil
+	default:
+		return nil, types.NewValidationError("job", fmt.Sprintf("job ID '%s' is ambiguous", id), nil)
+	}
+}
+
+// ShowJobLog prints a job's output. With follow, it keeps printing new output
+// until the job stops running.
+func (m *Manager) ShowJobLog(id string, follow bool) error {
+	job, err := m.findJob(id)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Open(job.Log)
+	if err != nil {
+		return types.NewFileSystemError("job-logs", job.Log, "failed to open job log", err)
+	}
+	defer file.Close()
+
+	for {
+		if _, err := io.Copy(os.Stdout, file); err != nil {
+			return types.NewFileSystemError("job-logs", job.Log, "failed to read job log", err)
+		}
+		if !follow || job.State() != JobRunning {
+			break
+		}
+		time.Sleep(500 * time.Millisecond)
+		if job, err = m.findJob(job.ID); err != nil {
+			return err
+		}
+	}
+
+	// Read whatever was written between the last copy and the job stopping
+	_, _ = io.Copy(os.Stdout, file)
+	if follow {
+		m.ui.Info("Job %s %s", job.ID, job.State())
+	}
+	return nil
+}
+
+// CancelJob stops a running job and records it as cancelled
+func (m *Manager) CancelJob(id string) error {
+	job, err := m.findJob(id)
+	if err != nil {
+		return err
+	}
+	if state := job.State(); state != JobRunning {
+		return types.NewValidationError("job-cancel", fmt.Sprintf("job %s is not running (%s)", job.ID, state), nil)
+	}
+
+	process, err := os.FindProcess(job.PID)
+	if err != nil {
+		return fmt.Errorf("failed to find job process %d: %w", job.PID, err)
+	}
+	// Windows can't deliver SIGTERM; elsewhere let the job exit cleanly
+	if runtime.GOOS == "windows" {
+		err = process.Kill()
+	} else {
+		err = process.Signal(syscall.SIGTERM)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to stop job %s: %w", job.ID, err)
+	}
+
+	dir, err := m.jobsDir()
+	if err != nil {
+		return err
+	}
+	if err := writeJobResult(filepath.Join(dir, job.ID+".json"), jobResult{FinishedAt: time.Now(), Cancelled: true}); err != nil && !os.IsExist(err) {
+		return types.NewFileSystemError("job-cancel", dir, "failed to record cancellation", err)
+	}
+
+	m.completed("Cancelled job %s (%s %s)", job.ID, job.Kind, job.Target)
+	return nil
+}
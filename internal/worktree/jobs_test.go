@@ -0,0 +1,70 @@
+package worktree
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFinishJob(t *testing.T) {
+	jobFile := filepath.Join(t.TempDir(), "abc.json")
+	require.NoError(t, writeJob(jobFile, &Job{ID: "abc", Kind: "setup", StartedAt: time.Now()}))
+
+	job, err := readJob(jobFile)
+	require.NoError(t, err)
+	assert.Nil(t, job.FinishedAt)
+	assert.Equal(t, JobLost, job.State(), "no pid and no result")
+
+	FinishJob(jobFile, nil)
+	job, err = readJob(jobFile)
+	require.NoError(t, err)
+	require.NotNil(t, job.FinishedAt)
+	assert.Equal(t, JobDone, job.State())
+
+	// The first result wins, so a cancellation is never overwritten
+	FinishJob(jobFile, errors.New("late failure"))
+	job, err = readJob(jobFile)
+	require.NoError(t, err)
+	assert.Equal(t, JobDone, job.State())
+}
+
+func TestFinishJob_Failed(t *testing.T) {
+	jobFile := filepath.Join(t.TempDir(), "abc.json")
+	require.NoError(t, writeJob(jobFile, &Job{ID: "abc", StartedAt: time.Now()}))
+
+	FinishJob(jobFile, errors.New("hook failed"))
+	job, err := readJob(jobFile)
+	require.NoError(t, err)
+	assert.Equal(t, JobFailed, job.State())
+	assert.Equal(t, "hook failed", job.Error)
+}
+
+func TestJobState_Cancelled(t *testing.T) {
+	jobFile := filepath.Join(t.TempDir(), "abc.json")
+	require.NoError(t, writeJob(jobFile, &Job{ID: "abc", PID: os.Getpid(), StartedAt: time.Now()}))
+	require.NoError(t, writeJobResult(jobFile, jobResult{FinishedAt: time.Now(), Cancelled: true}))
+
+	job, err := readJob(jobFile)
+	require.NoError(t, err)
+	assert.Equal(t, JobCancelled, job.State())
+}
+
+func TestJobState_Running(t *testing.T) {
+	job := &Job{PID: os.Getpid()}
+	assert.Equal(t, JobRunning, job.State())
+}
+
+func TestFinishJob_NoJob(t *testing.T) {
+	assert.NotPanics(t, func() { FinishJob("", errors.New("ignored")) })
+}
+
+func TestBackgroundSetupArgs(t *testing.T) {
+	assert.Equal(t, []string{"setup", "/wt/feature", "--hooks"}, backgroundSetupArgs("/wt/feature", CreateOptions{}))
+	assert.Equal(t, []string{"setup", "/wt/feature", "--skip-copy", "--skip-link"},
+		backgroundSetupArgs("/wt/feature", CreateOptions{SkipHooks: true, SkipCopy: true, SkipLink: true}))
+}
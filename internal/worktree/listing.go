@@ -0,0 +1,285 @@
+package worktree
+
+import (
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/awhite/wtree/internal/git"
+	"github.com/awhite/wtree/pkg/types"
+)
+
+// listRow is a single rendered row of `wtree list`
+type listRow struct {
+	cells   []string // Branch, Path, Status, Type
+	setup   string
+	created string
+	note    string
+	tags    string
+	group   string
+}
+
+// listColumns are the optional columns of `wtree list`
+type listColumns struct {
+	setup, created, notes, tags bool
+}
+
+// ageGroups orders the age buckets from newest to oldest
+var ageGroups = []string{"today", "this week", "this month", "older", "unknown"}
+
+// renderListRows prints rows as one table, or one table per group when grouped
+func (m *Manager) renderListRows(rows []listRow, columns listColumns) {
+	groups := make(map[string][]listRow)
+	var order []string
+	for _, row := range rows {
+		if _, seen := groups[row.group]; !seen {
+			order = append(order, row.group)
+		}
+		groups[row.group] = append(groups[row.group], row)
+	}
+	sortGroups(order)
+
+	for _, group := range order {
+		if group != "" {
+			m.ui.Header("%s (%d)", group, len(groups[group]))
+		}
+
+		headers := []string{"Branch", "Path", "Status", "Type"}
+		if columns.setup {
+			headers = append(headers, "Setup")
+		}
+		if columns.created {
+			headers = append(headers, "Created")
+		}
+		if columns.tags {
+			headers = append(headers, "Tags")
+		}
+		if columns.notes {
+			headers = append(headers, "Note")
+		}
+
+		table := m.ui.NewTable()
+		table.SetHeaders(headers...)
+		for _, row := range groups[group] {
+			cells := append([]string{}, row.cells...)
+			if columns.setup {
+				cells = append(cells, row.setup)
+			}
+			if columns.created {
+				if row.created == "" {
+					row.created = "-"
+				}
+				cells = append(cells, row.created)
+			}
+			if columns.tags {
+				cells = append(cells, row.tags)
+			}
+			if columns.notes {
+				cells = append(cells, row.note)
+			}
+			table.AddRow(cells...)
+		}
+		table.Render()
+	}
+}
+
+// sortGroups puts age buckets in chronological order and everything else
+// alphabetically, with remote worktrees last
+func sortGroups(groups []string) {
+	rank := func(group string) int {
+		for i, age := range ageGroups {
+			if group == age {
+				return i
+			}
+		}
+		if group == "remote" {
+			return len(ageGroups) + 1
+		}
+		return len(ageGroups)
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		ri, rj := rank(groups[i]), rank(groups[j])
+		if ri != rj {
+			return ri < rj
+		}
+		return groups[i] < groups[j]
+	})
+}
+
+// listGroup returns the group a worktree belongs to for the given mode
+func (m *Manager) listGroup(wt *types.WorktreeInfo, status *git.WorktreeStatus, groupBy string) string {
+	switch groupBy {
+	case GroupByState:
+		switch {
+		case wt.IsMainRepo:
+			return "main"
+		case status == nil:
+			return "unknown"
+		case status.Operation != nil:
+			return "in progress"
+		case !status.IsClean:
+			return "dirty"
+		default:
+			return "clean"
+		}
+	case GroupByAge:
+		commit, err := m.repo.GetLastCommit(wt.Path)
+		if err != nil || commit == nil {
+			return "unknown"
+		}
+		return ageBucket(time.Since(commit.Time))
+	case GroupByAuthor:
+		commit, err := m.repo.GetLastCommit(wt.Path)
+		if err != nil || commit == nil || commit.Author == "" {
+			return "unknown"
+		}
+		return commit.Author
+	default:
+		return ""
+	}
+}
+
+// ageBucket maps the age of a worktree's last commit to a display group
+func ageBucket(age time.Duration) string {
+	switch {
+	case age < 24*time.Hour:
+		return "today"
+	case age < 7*24*time.Hour:
+		return "this week"
+	case age < 30*24*time.Hour:
+		return "this month"
+	default:
+		return "older"
+	}
+}
+
+// listSummary prints aggregate counts for the worktrees instead of rows
+func (m *Manager) listSummary(worktrees []*types.WorktreeInfo, options ListOptions) error {
+	var total, clean, dirty, inProgress, merged, unmerged int
+	var diskBytes int64
+
+	mainBranch := ""
+	for _, wt := range worktrees {
+		if wt.IsMainRepo {
+			mainBranch = wt.Branch
+		}
+	}
+
+	for _, wt := range worktrees {
+		if wt.IsMainRepo || (options.BranchFilter != "" && !strings.Contains(wt.Branch, options.BranchFilter)) {
+			continue
+		}
+		// --dirty counts only what the rows would show
+		if options.OnlyDirty && (!wt.StatusLoaded || wt.IsClean) {
+			continue
+		}
+		total++
+
+		if wt.StatusLoaded {
+			if wt.IsClean {
+				clean++
+			} else {
+				dirty++
+			}
+			if wt.Operation != "" {
+				inProgress++
+			}
+		}
+
+		if mainBranch != "" && wt.Branch != "" {
+			if isMerged, err := m.repo.IsBranchMerged(wt.Branch, mainBranch); err == nil {
+				if isMerged {
+					merged++
+				} else {
+					unmerged++
+				}
+			}
+		}
+
+		diskBytes += diskUsage(wt.Path)
+	}
+
+	table := m.ui.NewTable()
+	table.SetHeaders("Metric", "Count")
+	table.AddRow("Worktrees", fmt.Sprintf("%d", total))
+	table.AddRow("Clean", fmt.Sprintf("%d", clean))
+	table.AddRow("Dirty", fmt.Sprintf("%d", dirty))
+	if inProgress > 0 {
+		table.AddRow("Operation in progress", fmt.Sprintf("%d", inProgress))
+	}
+	if mainBranch != "" {
+		table.AddRow(fmt.Sprintf("Merged into %s", mainBranch), fmt.Sprintf("%d", merged))
+		table.AddRow("Unmerged", fmt.Sprintf("%d", unmerged))
+	}
+	table.AddRow("Total disk", formatBytes(diskBytes))
+	table.Render()
+
+	return nil
+}
+
+// diskUsage returns the total size of regular files under path, without
+// following symlinks. Unreadable entries are skipped.
+func diskUsage(path string) int64 {
+	var size int64
+	_ = filepath.WalkDir(path, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.Type().IsRegular() {
+			if info, err := d.Info(); err == nil {
+				size += info.Size()
+			}
+		}
+		return nil
+	})
+	return size
+}
+
+// formatBytes renders a byte count using binary units, e.g. "1.5 GiB"
+func formatBytes(bytes int64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+
+	div, exp := int64(unit), 0
+	for n := bytes / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(bytes)/float64(div), "KMGTPE"[exp])
+}
+
+// DescribeWorktree returns a short one-line description of a worktree for
+// shell completion, e.g. "dirty (3 files) · PR #42 · cache spike"
+func (m *Manager) DescribeWorktree(wt *types.WorktreeInfo) string {
+	var parts []string
+
+	if wt.IsMainRepo {
+		parts = append(parts, "main")
+	} else if status, err := m.repo.GetWorktreeStatus(wt.Path); err == nil {
+		switch {
+		case status.Operation != nil:
+			parts = append(parts, status.Operation.String())
+		case !status.IsClean:
+			parts = append(parts, fmt.Sprintf("dirty (%d files)", status.ChangedFiles))
+		default:
+			parts = append(parts, "clean")
+		}
+	}
+
+	pm := &PRManager{Manager: m}
+	if prNumber := pm.extractPRNumber(wt.Path, m.repo.GetRepoName()); prNumber > 0 {
+		parts = append(parts, fmt.Sprintf("PR #%d", prNumber))
+	}
+
+	if note := m.worktreeNote(wt); note != "" {
+		parts = append(parts, note)
+	}
+
+	return strings.Join(parts, " · ")
+}
@@ -0,0 +1,80 @@
+package worktree
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/awhite/wtree/internal/git"
+	"github.com/awhite/wtree/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAgeBucket(t *testing.T) {
+	assert.Equal(t, "today", ageBucket(2*time.Hour))
+	assert.Equal(t, "this week", ageBucket(3*24*time.Hour))
+	assert.Equal(t, "this month", ageBucket(20*24*time.Hour))
+	assert.Equal(t, "older", ageBucket(90*24*time.Hour))
+}
+
+func TestSortGroups(t *testing.T) {
+	groups := []string{"remote", "older", "today", "this month"}
+	sortGroups(groups)
+	assert.Equal(t, []string{"today", "this month", "older", "remote"}, groups)
+
+	authors := []string{"remote", "zoe", "adam"}
+	sortGroups(authors)
+	assert.Equal(t, []string{"adam", "zoe", "remote"}, authors)
+}
+
+func TestFormatBytes(t *testing.T) {
+	assert.Equal(t, "512 B", formatBytes(512))
+	assert.Equal(t, "1.5 KiB", formatBytes(1536))
+	assert.Equal(t, "2.0 GiB", formatBytes(2*1024*1024*1024))
+}
+
+func TestDiskUsage(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "a"), make([]byte, 100), 0644))
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "sub"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "sub", "b"), make([]byte, 50), 0644))
+	require.NoError(t, os.Symlink(filepath.Join(dir, "a"), filepath.Join(dir, "link")))
+
+	assert.Equal(t, int64(150), diskUsage(dir))
+}
+
+// describeMockRepo returns canned statuses by path and notes by branch
+type describeMockRepo struct {
+	MockGitRepo
+	statuses map[string]*git.WorktreeStatus
+	notes    map[string]string
+}
+
+func (r *describeMockRepo) GetWorktreeStatus(path string) (*git.WorktreeStatus, error) {
+	return r.statuses[path], nil
+}
+func (r *describeMockRepo) GetConfigValue(key string) (string, error) { return r.notes[key], nil }
+
+func TestManager_DescribeWorktree(t *testing.T) {
+	repo := &describeMockRepo{
+		statuses: map[string]*git.WorktreeStatus{
+			"/repo/test-repo-clean": {IsClean: true},
+			"/repo/test-repo-dirty": {ChangedFiles: 3},
+			"/repo/test-repo-rebase": {
+				Operation: &git.OperationState{Kind: git.OperationRebase, Current: 3, Total: 7},
+			},
+			"/repo/test-repo-pr-42": {IsClean: true},
+		},
+		notes: map[string]string{"branch.spike.description": "cache spike\nwith details"},
+	}
+	m := &Manager{repo: repo, globalConfig: types.DefaultWTreeConfig()}
+
+	assert.Equal(t, "main", m.DescribeWorktree(&types.WorktreeInfo{Path: "/repo", Branch: "main", IsMainRepo: true}))
+	assert.Equal(t, "clean", m.DescribeWorktree(&types.WorktreeInfo{Path: "/repo/test-repo-clean", Branch: "clean"}))
+	assert.Equal(t, "dirty (3 files) · cache spike",
+		m.DescribeWorktree(&types.WorktreeInfo{Path: "/repo/test-repo-dirty", Branch: "spike"}))
+	assert.Equal(t, "REBASING 3/7", m.DescribeWorktree(&types.WorktreeInfo{Path: "/repo/test-repo-rebase", Branch: "rebase"}))
+	assert.Equal(t, "clean · PR #42", m.DescribeWorktree(&types.WorktreeInfo{Path: "/repo/test-repo-pr-42", Branch: "pr-42"}))
+}
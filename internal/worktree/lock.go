@@ -1,7 +1,9 @@
 package worktree
 
 import (
+	"crypto/rand"
 	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -21,6 +23,7 @@ type OperationLock struct {
 	lockFile   *os.File
 	pid        int
 	operation  string
+	token      string // Token of the lock manager, recorded for internal-lock
 	acquired   bool
 	timeout    time.Duration
 	mu         sync.Mutex
@@ -36,16 +39,37 @@ const (
 	LockTypeMerge   LockType = "merge"
 	LockTypeSwitch  LockType = "switch"
 	LockTypeCleanup LockType = "cleanup"
+	LockTypeRestack LockType = "restack"
+
+	// LockTypeGit serializes changes to the repository's shared git state
+	// (branches, worktree registrations) across wtree processes and the
+	// hooks that take it through 'wtree internal-lock'
+	LockTypeGit LockType = "git"
+
+	// LockTypeHooks is held while hooks run, vouching for the lock token
+	// they are given
+	LockTypeHooks LockType = "hooks"
+
+	// LockTypeState guards the per-user workspace state file, which every
+	// repository's wtree processes rewrite
+	LockTypeState LockType = "state"
 )
 
+// LockTokenEnv passes hooks the lock token of the wtree operation running
+// them, which 'wtree internal-lock --held-by-hook' checks
+const LockTokenEnv = "WTREE_LOCK_TOKEN"
+
 // LockManager manages multiple operation locks
 type LockManager struct {
 	lockDir string
+	repoID  string // Identity of the repository lock keys are namespaced by
+	token   string // Random per-process token written into every lock taken
 	locks   map[string]*OperationLock
 	mu      sync.RWMutex
 }
 
-// NewLockManager creates a new lock manager
+// NewLockManager creates a new lock manager using the per-user global lock
+// directory
 func NewLockManager() (*LockManager, error) {
 	lockDir, err := getLockDirectory()
 	if err != nil {
@@ -54,17 +78,85 @@ func NewLockManager() (*LockManager, error) {
 
 	return &LockManager{
 		lockDir: lockDir,
+		token:   newLockToken(),
 		locks:   make(map[string]*OperationLock),
 	}, nil
 }
 
+// NewRepoLockManager creates a lock manager for the repository whose git
+// common directory is commonDir. Locks live in its wtree/locks directory,
+// so they are shared by everyone using the repository and go away with it;
+// when that directory can't be created the global one is used. Keys are
+// namespaced by the repository either way.
+func NewRepoLockManager(commonDir string) (*LockManager, error) {
+	lockDir := filepath.Join(commonDir, "wtree", "locks")
+	if err := os.MkdirAll(lockDir, 0755); err != nil {
+		if lockDir, err = getLockDirectory(); err != nil {
+			return nil, fmt.Errorf("failed to create lock directory: %w", err)
+		}
+	}
+
+	return &LockManager{
+		lockDir: lockDir,
+		repoID:  commonDir,
+		token:   newLockToken(),
+		locks:   make(map[string]*OperationLock),
+	}, nil
+}
+
+// newLockToken returns a random token identifying this process's locks
+func newLockToken() string {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return fmt.Sprintf("pid-%d-%d", os.Getpid(), time.Now().UnixNano())
+	}
+	return hex.EncodeToString(buf)
+}
+
+// Token returns the token written into the locks this manager takes
+func (lm *LockManager) Token() string {
+	return lm.token
+}
+
+// TokenHeld reports whether a live process on this host holds a lock in
+// this manager's directory under token, i.e. whether token belongs to a
+// running wtree operation
+func (lm *LockManager) TokenHeld(token string) bool {
+	if token == "" {
+		return false
+	}
+	paths, err := filepath.Glob(filepath.Join(lm.lockDir, "*.lock"))
+	if err != nil {
+		return false
+	}
+	for _, path := range paths {
+		lock := &OperationLock{lockPath: path}
+		lockInfo, err := lock.readLockInfo()
+		if err != nil {
+			continue
+		}
+		if holder := parseLockInfo(lockInfo); holder.Token == token && !lock.isLockStale() {
+			return true
+		}
+	}
+	return false
+}
+
+// lockKey returns the key of the lock for lockType on targetPath
+func (lm *LockManager) lockKey(lockType LockType, targetPath string) string {
+	if lm.repoID == "" {
+		return generateLockKey(string(lockType), targetPath)
+	}
+	return generateLockKey(string(lockType), lm.repoID+"\x00"+targetPath)
+}
+
 // AcquireLock acquires a lock for the specified operation on the target path
 func (lm *LockManager) AcquireLock(lockType LockType, targetPath string, timeout time.Duration) (*OperationLock, error) {
 	lm.mu.Lock()
 	defer lm.mu.Unlock()
 
 	// Create a unique lock key based on the target path and operation type
-	lockKey := generateLockKey(string(lockType), targetPath)
+	lockKey := lm.lockKey(lockType, targetPath)
 
 	// Check if we already have this lock
 	if existingLock, exists := lm.locks[lockKey]; exists && existingLock.acquired {
@@ -77,6 +169,7 @@ func (lm *LockManager) AcquireLock(lockType LockType, targetPath string, timeout
 	if err != nil {
 		return nil, fmt.Errorf("failed to create lock: %w", err)
 	}
+	lock.token = lm.token
 
 	// Attempt to acquire the lock
 	if err := lock.acquire(); err != nil {
@@ -160,8 +253,12 @@ func (ol *OperationLock) acquire() error {
 			ol.acquired = true
 
 			// Write lock information to the file
-			lockInfo := fmt.Sprintf("pid=%d\noperation=%s\ntime=%s\n",
-				ol.pid, ol.operation, time.Now().Format(time.RFC3339))
+			hostname, _ := os.Hostname()
+			lockInfo := fmt.Sprintf("pid=%d\noperation=%s\nhost=%s\ntime=%s\n",
+				ol.pid, ol.operation, hostname, time.Now().Format(time.RFC3339))
+			if ol.token != "" {
+				lockInfo += fmt.Sprintf("token=%s\n", ol.token)
+			}
 
 			if _, writeErr := file.WriteString(lockInfo); writeErr != nil {
 				// Clean up on write failure
@@ -182,12 +279,7 @@ func (ol *OperationLock) acquire() error {
 		// Check timeout
 		if time.Since(startTime) >= ol.timeout {
 			// Try to provide helpful information about who owns the lock
-			if lockInfo, readErr := ol.readLockInfo(); readErr == nil {
-				return types.NewValidationError("acquire-lock-timeout",
-					fmt.Sprintf("timeout waiting for lock (held by %s)", lockInfo), err)
-			}
-			return types.NewValidationError("acquire-lock-timeout",
-				"timeout waiting for lock", err)
+			return newLockHeldError(ol, err)
 		}
 
 		// Check if the existing lock is stale
@@ -201,6 +293,103 @@ func (ol *OperationLock) acquire() error {
 	}
 }
 
+// LockHolder describes the process holding a lock, as recorded in the lock file
+type LockHolder struct {
+	PID       int
+	Operation string
+	Host      string
+	Since     time.Time
+	Token     string
+}
+
+// String formats the holder for display, e.g. "pid 4242 (create) on devbox for 2m30s"
+func (lh *LockHolder) String() string {
+	desc := fmt.Sprintf("pid %d", lh.PID)
+	if lh.Operation != "" {
+		desc += fmt.Sprintf(" (%s)", lh.Operation)
+	}
+	if lh.Host != "" {
+		desc += " on " + lh.Host
+	}
+	if !lh.Since.IsZero() {
+		desc += fmt.Sprintf(" for %s", time.Since(lh.Since).Round(time.Second))
+	}
+	return desc
+}
+
+// LockHeldError is returned when a lock could not be acquired before the timeout
+type LockHeldError struct {
+	*types.ValidationError
+	LockPath string
+	Holder   *LockHolder // nil if the lock file could not be read
+}
+
+// newLockHeldError builds a timeout error describing who holds the lock
+func newLockHeldError(ol *OperationLock, cause error) *LockHeldError {
+	message := "timeout waiting for lock"
+
+	var holder *LockHolder
+	if lockInfo, readErr := ol.readLockInfo(); readErr == nil {
+		holder = parseLockInfo(lockInfo)
+		message = fmt.Sprintf("timeout waiting for lock (held by %s)", holder)
+	}
+
+	validationErr := types.NewValidationError("acquire-lock-timeout", message, cause)
+	validationErr.BaseError = validationErr.WithSuggestedActions(
+		"Retry with --wait <duration> to keep waiting for the lock",
+		fmt.Sprintf("If the holder is gone, rerun with --steal to clear %s", ol.lockPath),
+	)
+
+	return &LockHeldError{
+		ValidationError: validationErr,
+		LockPath:        ol.lockPath,
+		Holder:          holder,
+	}
+}
+
+// parseLockInfo parses the key=value lines written by acquire
+func parseLockInfo(lockInfo string) *LockHolder {
+	holder := &LockHolder{PID: extractPIDFromLockInfo(lockInfo)}
+	for _, line := range strings.Split(lockInfo, "\n") {
+		key, value, found := strings.Cut(line, "=")
+		if !found {
+			continue
+		}
+		switch key {
+		case "operation":
+			holder.Operation = value
+		case "host":
+			holder.Host = value
+		case "token":
+			holder.Token = value
+		case "time":
+			if since, err := time.Parse(time.RFC3339, value); err == nil {
+				holder.Since = since
+			}
+		}
+	}
+	return holder
+}
+
+// StealLock forcibly removes a lock held by another process. Callers must
+// confirm with the user first; the previous holder is not notified.
+func (lm *LockManager) StealLock(lockType LockType, targetPath string) error {
+	lm.mu.Lock()
+	defer lm.mu.Unlock()
+
+	lockKey := lm.lockKey(lockType, targetPath)
+	if existingLock, exists := lm.locks[lockKey]; exists && existingLock.acquired {
+		return types.NewValidationError("steal-lock",
+			fmt.Sprintf("lock for %s on %s is held by this process", lockType, targetPath), nil)
+	}
+
+	lockPath := filepath.Join(lm.lockDir, lockKey+".lock")
+	if err := os.Remove(lockPath); err != nil && !os.IsNotExist(err) {
+		return types.NewFileSystemError("steal-lock", lockPath, "failed to remove lock file", err)
+	}
+	return nil
+}
+
 // Release releases the lock
 func (ol *OperationLock) Release() error {
 	ol.mu.Lock()
@@ -236,25 +425,39 @@ func (ol *OperationLock) cleanup() error {
 	return nil
 }
 
-// isLockStale checks if an existing lock file is stale (process no longer exists)
+// isLockStale checks if an existing lock file is stale (process no longer
+// exists). A lock taken on another host, e.g. over NFS, is never stale:
+// its process can't be checked from here.
 func (ol *OperationLock) isLockStale() bool {
 	lockInfo, err := ol.readLockInfo()
 	if err != nil {
 		return true // Assume stale if we can't read it
 	}
 
+	holder := parseLockInfo(lockInfo)
+	if hostname, err := os.Hostname(); err == nil && holder.Host != "" && holder.Host != hostname {
+		return false
+	}
+
 	// Extract PID from lock info
-	if pid := extractPIDFromLockInfo(lockInfo); pid > 0 {
-		// Check if process still exists
-		if runtime.GOOS == "windows" {
-			return !processExistsWindows(pid)
-		}
-		return !processExistsUnix(pid)
+	if pid := holder.PID; pid > 0 {
+		return !processRunning(pid)
 	}
 
 	return true // Assume stale if no valid PID
 }
 
+// processRunning reports whether a process with the given PID exists
+func processRunning(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	if runtime.GOOS == "windows" {
+		return processExistsWindows(pid)
+	}
+	return processExistsUnix(pid)
+}
+
 // cleanupStaleLock removes a stale lock file
 func (ol *OperationLock) cleanupStaleLock() error {
 	return os.Remove(ol.lockPath)
@@ -278,14 +481,16 @@ func generateLockKey(operation, targetPath string) string {
 	return fmt.Sprintf("wtree-%s-%s", operation, pathHash)
 }
 
-// getLockDirectory returns the directory to use for lock files
+// getLockDirectory returns the directory to use for lock files. Each user
+// gets their own, so users sharing a machine never need write access to
+// another user's lock files.
 func getLockDirectory() (string, error) {
 	var lockDir string
 
 	if runtime.GOOS == "windows" {
-		lockDir = filepath.Join(os.TempDir(), "wtree-locks")
+		lockDir = filepath.Join(os.TempDir(), "wtree-locks-"+currentUser())
 	} else {
-		lockDir = filepath.Join("/tmp", "wtree-locks")
+		lockDir = filepath.Join("/tmp", "wtree-locks-"+currentUser())
 	}
 
 	if err := os.MkdirAll(lockDir, 0755); err != nil {
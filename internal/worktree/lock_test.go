@@ -278,3 +278,126 @@ func TestLockManager_StresTest(t *testing.T) {
 	assert.Greater(t, successCount, int64(0), "Should have some successful lock acquisitions")
 	assert.Less(t, successCount, int64(numWorkers*numOperations), "Not all acquisitions should succeed due to contention")
 }
+
+func TestLockManager_TimeoutReportsHolder(t *testing.T) {
+	lm1, err := NewLockManager()
+	require.NoError(t, err)
+	defer func() { _ = lm1.ReleaseAll() }()
+
+	lm2, err := NewLockManager()
+	require.NoError(t, err)
+	defer func() { _ = lm2.ReleaseAll() }()
+
+	targetPath := "/test/holder-info"
+
+	lock1, err := lm1.AcquireLock(LockTypeDelete, targetPath, 5*time.Second)
+	require.NoError(t, err)
+	defer func() { _ = lm1.ReleaseLock(lock1) }()
+
+	_, err = lm2.AcquireLock(LockTypeDelete, targetPath, 100*time.Millisecond)
+	require.Error(t, err)
+
+	var heldErr *LockHeldError
+	require.ErrorAs(t, err, &heldErr)
+	require.NotNil(t, heldErr.Holder)
+	assert.Equal(t, os.Getpid(), heldErr.Holder.PID)
+	assert.Equal(t, "delete", heldErr.Holder.Operation)
+	assert.False(t, heldErr.Holder.Since.IsZero())
+	assert.Equal(t, lock1.lockPath, heldErr.LockPath)
+	assert.Contains(t, heldErr.SuggestedActions()[0], "--wait")
+}
+
+func TestLockManager_StealLock(t *testing.T) {
+	lm1, err := NewLockManager()
+	require.NoError(t, err)
+	defer func() { _ = lm1.ReleaseAll() }()
+
+	lm2, err := NewLockManager()
+	require.NoError(t, err)
+	defer func() { _ = lm2.ReleaseAll() }()
+
+	targetPath := "/test/steal"
+
+	lock1, err := lm1.AcquireLock(LockTypeCreate, targetPath, 5*time.Second)
+	require.NoError(t, err)
+
+	// The owning manager refuses to steal its own lock
+	assert.Error(t, lm1.StealLock(LockTypeCreate, targetPath))
+
+	require.NoError(t, lm2.StealLock(LockTypeCreate, targetPath))
+	assert.NoFileExists(t, lock1.lockPath)
+
+	lock2, err := lm2.AcquireLock(LockTypeCreate, targetPath, 100*time.Millisecond)
+	require.NoError(t, err)
+	assert.NoError(t, lm2.ReleaseLock(lock2))
+}
+
+func TestParseLockInfo(t *testing.T) {
+	holder := parseLockInfo("pid=4242\noperation=create\nhost=devbox\ntime=2024-01-02T03:04:05Z\n")
+
+	assert.Equal(t, 4242, holder.PID)
+	assert.Equal(t, "create", holder.Operation)
+	assert.Equal(t, "devbox", holder.Host)
+	assert.Equal(t, 2024, holder.Since.Year())
+	assert.Contains(t, holder.String(), "pid 4242 (create) on devbox for ")
+}
+
+func TestNewRepoLockManager(t *testing.T) {
+	commonDir := filepath.Join(t.TempDir(), ".git")
+	require.NoError(t, os.Mkdir(commonDir, 0755))
+	other := filepath.Join(t.TempDir(), ".git")
+	require.NoError(t, os.Mkdir(other, 0755))
+
+	lm1, err := NewRepoLockManager(commonDir)
+	require.NoError(t, err)
+	defer func() { _ = lm1.ReleaseAll() }()
+	lm2, err := NewRepoLockManager(other)
+	require.NoError(t, err)
+	defer func() { _ = lm2.ReleaseAll() }()
+
+	lock1, err := lm1.AcquireLock(LockTypeCreate, "/src/feature", time.Second)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(commonDir, "wtree", "locks"), filepath.Dir(lock1.lockPath))
+
+	// Another repository never contends for the same path
+	lock2, err := lm2.AcquireLock(LockTypeCreate, "/src/feature", 100*time.Millisecond)
+	require.NoError(t, err)
+	assert.NotEqual(t, filepath.Base(lock1.lockPath), filepath.Base(lock2.lockPath))
+}
+
+func TestOperationLock_ForeignHostIsNotStale(t *testing.T) {
+	lockPath := filepath.Join(t.TempDir(), "test.lock")
+	require.NoError(t, os.WriteFile(lockPath, []byte("pid=999999\noperation=create\nhost=wtree-other-host\n"), 0600))
+
+	lock := &OperationLock{lockPath: lockPath}
+	assert.False(t, lock.isLockStale())
+
+	require.NoError(t, os.WriteFile(lockPath, []byte("pid=999999\noperation=create\n"), 0600))
+	assert.True(t, lock.isLockStale())
+}
+
+func TestLockManager_TokenHeld(t *testing.T) {
+	commonDir := filepath.Join(t.TempDir(), ".git")
+	require.NoError(t, os.Mkdir(commonDir, 0755))
+
+	lm, err := NewRepoLockManager(commonDir)
+	require.NoError(t, err)
+	defer func() { _ = lm.ReleaseAll() }()
+	require.NotEmpty(t, lm.Token())
+
+	// A token only counts while a lock taken under it is held
+	assert.False(t, lm.TokenHeld(lm.Token()))
+	lock, err := lm.AcquireLock(LockTypeHooks, "/src/feature", time.Second)
+	require.NoError(t, err)
+	assert.True(t, lm.TokenHeld(lm.Token()))
+	assert.False(t, lm.TokenHeld("not-a-token"))
+	assert.False(t, lm.TokenHeld(""))
+
+	// Another process checks the same directory
+	other, err := NewRepoLockManager(commonDir)
+	require.NoError(t, err)
+	assert.True(t, other.TokenHeld(lm.Token()))
+
+	require.NoError(t, lm.ReleaseLock(lock))
+	assert.False(t, other.TokenHeld(lm.Token()))
+}
@@ -1,15 +1,20 @@
 package worktree
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"runtime"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/awhite/wtree/internal/config"
 	"github.com/awhite/wtree/internal/git"
+	"github.com/awhite/wtree/internal/github"
 	"github.com/awhite/wtree/internal/ui"
 	"github.com/awhite/wtree/pkg/types"
 )
@@ -24,27 +29,67 @@ type Manager struct {
 	lockManager   *LockManager
 	globalConfig  *types.WTreeConfig
 	projectConfig *types.ProjectConfig
+	configSource  string         // Where projectConfig came from, e.g. types.ProjectConfigMain
+	lockWait      time.Duration  // Overrides the lock timeout when set
+	stealLocks    bool           // Offer to clear locks held by other processes
+	github        *github.Client // Used by cleanup to check PR state; optional
+	readOnly      bool           // Refuse anything that writes; set by SetReadOnly
+	timings       *Timings       // Phase durations for --timings; nil when off
+	observers     []Observer     // Receive progress, warnings and confirmations
+	printCommands bool           // Print editor and terminal commands instead of running them
+	newWindow     bool           // Open editors in a new window rather than focusing one showing the worktree
+	onConflict    string         // OnConflict* policy for existing copy/link destinations
 }
 
 // NewManager creates a new worktree manager
 func NewManager(repo git.Repository, configMgr *config.Manager, ui *ui.Manager) *Manager {
-	lockManager, err := NewLockManager()
-	if err != nil {
-		// Log error but don't fail - fall back to no locking
-		if ui != nil {
-			ui.Warning("Failed to initialize lock manager, concurrency protection disabled: %v", err)
-		}
-		lockManager = nil
-	}
-
-	return &Manager{
+	m := &Manager{
 		repo:        repo,
 		configMgr:   configMgr,
 		ui:          ui,
 		fileManager: NewFileManager(ui != nil),
 		rollback:    NewRollbackManager(repo),
-		lockManager: lockManager,
 	}
+	if ui != nil {
+		m.Subscribe(NewCLIObserver(ui))
+	}
+	return m
+}
+
+// SetReadOnly makes the manager perform no writes: Initialize skips the lock
+// directory and any operation that needs a lock fails. Call before Initialize.
+func (m *Manager) SetReadOnly() {
+	m.readOnly = true
+}
+
+// SetLockOptions configures how lock contention is handled. A positive wait
+// replaces the operation timeout; steal offers to clear a held lock.
+func (m *Manager) SetLockOptions(wait time.Duration, steal bool) {
+	m.lockWait = wait
+	m.stealLocks = steal
+}
+
+// SetPrintCommands makes editor and terminal launches print the command
+// they would run, ready to copy into a shell, instead of running it
+func (m *Manager) SetPrintCommands(print bool) {
+	m.printCommands = print
+}
+
+// SetNewWindow makes editors open a new window even when one already shows
+// the worktree
+func (m *Manager) SetNewWindow(newWindow bool) {
+	m.newWindow = newWindow
+}
+
+// SetOnConflict sets how copy and link destinations that already exist with
+// different content are handled: ask (the default), keep, overwrite or rename
+func (m *Manager) SetOnConflict(policy string) {
+	m.onConflict = policy
+}
+
+// SetGitHubClient enables GitHub lookups, such as PR state during cleanup
+func (m *Manager) SetGitHubClient(client *github.Client) {
+	m.github = client
 }
 
 // GetRepository returns the git repository
@@ -71,6 +116,17 @@ func (m *Manager) Initialize() error {
 	if err != nil {
 		return fmt.Errorf("failed to load global config: %w", err)
 	}
+	if m.ui != nil && m.globalConfig.UI.Accessible {
+		m.ui.SetAccessible(true)
+	}
+	if m.ui != nil {
+		m.ui.SetAbsoluteTimes(m.globalConfig.UI.TimeFormat == types.TimeFormatAbsolute)
+	}
+
+	// Record the git version once and warn early when it is too old
+	if v, err := git.DetectVersion(); err == nil && m.ui != nil && !git.Supports(git.MinimumVersion) {
+		m.warn("git %s is older than %s; some commands will fail (see 'wtree doctor')", v, git.MinimumVersion.MinVersion())
+	}
 
 	// Load project configuration
 	repoRoot, err := m.repo.GetRepoRoot()
@@ -78,7 +134,8 @@ func (m *Manager) Initialize() error {
 		return err
 	}
 
-	m.projectConfig, err = m.configMgr.LoadProjectConfig(repoRoot)
+	m.projectConfig, m.configSource, err = m.configMgr.ResolveProjectConfig(
+		repoRoot, m.mainWorktreePath(), m.globalConfig.ProjectConfigSource)
 	if err != nil {
 		return fmt.Errorf("failed to load project config: %w", err)
 	}
@@ -88,9 +145,48 @@ func (m *Manager) Initialize() error {
 		m.fileManager = NewFileManager(m.globalConfig.UI.Verbose)
 	}
 
+	// Restrict copy/link sources to the repository plus configured roots
+	if err := m.fileManager.SetBasePath(repoRoot); err != nil {
+		return err
+	}
+	for _, root := range m.globalConfig.Paths.AllowedRoots {
+		if err := m.fileManager.AddAllowedRoot(expandHome(root)); err != nil {
+			return err
+		}
+	}
+
+	// The lock directory is created on disk, so read-only runs go without
+	if !m.readOnly {
+		if commonDir, commonErr := m.repo.GetGitCommonDir(); commonErr == nil {
+			m.lockManager, err = NewRepoLockManager(commonDir)
+			m.rollback.SetPlanDir(filepath.Join(commonDir, "wtree", "rollback"))
+		} else {
+			m.lockManager, err = NewLockManager()
+		}
+		if err != nil {
+			// Log error but don't fail - fall back to no locking
+			if m.ui != nil {
+				m.warn("Failed to initialize lock manager, concurrency protection disabled: %v", err)
+			}
+			m.lockManager = nil
+		}
+	}
+
 	return nil
 }
 
+// expandHome expands a leading "~/" to the user's home directory
+func expandHome(path string) string {
+	if !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, path[2:])
+}
+
 // GetGlobalConfig returns the global configuration
 func (m *Manager) GetGlobalConfig() *types.WTreeConfig {
 	return m.globalConfig
@@ -108,10 +204,24 @@ func (m *Manager) GetUI() *ui.Manager {
 
 // Create creates a new worktree with the specified branch
 func (m *Manager) Create(branchName string, options CreateOptions) error {
+	defer m.beginTimings()()
+	endValidation := m.timings.Start("validation")
+
 	if err := m.validateCreateOptions(branchName, options); err != nil {
 		return err
 	}
 
+	// Creating a worktree that already exists succeeds, so scripts can
+	// create unconditionally
+	if existing := m.branchWorktree(branchName); existing != nil {
+		endValidation()
+		return m.reuseWorktree(existing, options)
+	}
+
+	if err := m.checkWorktreeLimit(); err != nil {
+		return err
+	}
+
 	m.ui.Header("Creating worktree for branch '%s'", branchName)
 
 	// Create multi-step progress for worktree creation
@@ -126,38 +236,50 @@ func (m *Manager) Create(branchName string, options CreateOptions) error {
 	// Step 1: Validation
 	progress.StartStep(0)
 
+	if options.CreateBranch && !m.repo.BranchExists(branchName) {
+		if err := m.checkBranchCase(branchName); err != nil {
+			progress.FailStep(0)
+			return err
+		}
+	}
+
 	// Generate worktree path
 	worktreePath, err := m.generateWorktreePath(branchName)
 	if err != nil {
 		progress.FailStep(0)
 		return fmt.Errorf("failed to generate worktree path: %w", err)
 	}
+	m.checkSymlinkSupport(filepath.Dir(worktreePath))
 	progress.CompleteStep(0)
+	endValidation()
 
 	// Acquire operation lock to prevent concurrent creation
-	var operationLock *OperationLock
-	if m.lockManager != nil {
-		timeout := m.getOperationTimeout()
-		operationLock, err = m.lockManager.AcquireLock(LockTypeCreate, worktreePath, timeout)
-		if err != nil {
-			return fmt.Errorf("failed to acquire operation lock: %w", err)
-		}
-		defer func() {
-			if releaseErr := m.lockManager.ReleaseLock(operationLock); releaseErr != nil {
-				m.ui.Warning("Failed to release operation lock: %v", releaseErr)
-			}
-		}()
+	endLock := m.timings.Start("operation lock")
+	release, err := m.acquireOperationLock(LockTypeCreate, worktreePath)
+	endLock()
+	if err != nil {
+		return err
 	}
+	defer release()
+
+	// Bring remote refs up to date before branching from them
+	endFetch := m.timings.Start("fetch")
+	m.autoFetch()
+	endFetch()
 
 	// Clear any previous rollback operations
 	m.rollback.Clear()
 
 	// Atomically check and prepare the worktree path
-	if err := m.atomicPathPreparation(worktreePath, options.Force); err != nil {
+	if err := m.atomicPathPreparation(worktreePath, options.OverwritePath); err != nil {
+		return err
+	}
+	if err := m.abortIfInterrupted(); err != nil {
 		return err
 	}
 
 	branchCreated := false
+	base := ""
 	// Create branch if needed
 	if !m.repo.BranchExists(branchName) {
 		if !options.CreateBranch {
@@ -165,20 +287,36 @@ func (m *Manager) Create(branchName string, options CreateOptions) error {
 				fmt.Sprintf("branch '%s' does not exist", branchName), nil)
 		}
 
+		if options.FromBranch == "" || options.FromBranch == "HEAD" {
+			if head, err := m.repo.GetHeadState(); err == nil && head.Unborn {
+				return types.NewGitError("create-worktree",
+					fmt.Sprintf("'%s' has no commits yet; make an initial commit before creating worktrees", head.Branch), nil)
+			}
+		}
+
 		m.ui.Info("Creating branch '%s' from '%s'", branchName, options.FromBranch)
-		if err := m.repo.CreateBranch(branchName, options.FromBranch); err != nil {
+		endBranch := m.timings.Start("create branch")
+		err := m.withGitLock(func() error { return m.repo.CreateBranch(branchName, options.FromBranch) })
+		endBranch()
+		if err != nil {
 			return fmt.Errorf("failed to create branch: %w", err)
 		}
 		branchCreated = true
 		m.rollback.AddBranchCleanup(branchName)
+		base = m.describeBase(options.FromBranch)
 	}
 
 	// Execute pre-create hooks
 	hookCtx := m.buildHookContext(types.HookPreCreate, branchName, worktreePath)
-	if err := m.executeHooks(types.HookPreCreate, hookCtx); err != nil {
+	if options.SkipHooks {
+		m.ui.Info("Skipping pre_create and post_create hooks (--skip-hooks)")
+	} else if err := m.executeHooks(types.HookPreCreate, hookCtx); err != nil {
+		if abortErr := m.abortIfInterrupted(); abortErr != nil {
+			return abortErr
+		}
 		if branchCreated {
-			m.ui.Warning("Rolling back branch creation due to pre-create hook failure")
-			_ = m.rollback.Execute()
+			m.warn("Rolling back branch creation due to pre-create hook failure")
+			_ = m.runRollback()
 		}
 		return fmt.Errorf("pre-create hook failed: %w", err)
 	}
@@ -186,46 +324,73 @@ func (m *Manager) Create(branchName string, options CreateOptions) error {
 	// Step 2: Create the worktree
 	progress.StartStep(1)
 	m.ui.Info("Creating worktree at: %s", worktreePath)
-	if err := m.repo.CreateWorktree(worktreePath, branchName); err != nil {
+	endAdd := m.timings.Start("git worktree add")
+	err = m.withGitLock(func() error { return m.repo.CreateWorktree(worktreePath, branchName) })
+	endAdd()
+	if err != nil {
 		progress.FailStep(1)
 		if branchCreated {
-			m.ui.Warning("Rolling back branch creation due to worktree creation failure")
-			_ = m.rollback.Execute()
+			m.warn("Rolling back branch creation due to worktree creation failure")
+			_ = m.runRollback()
 		}
 		return fmt.Errorf("failed to create worktree: %w", err)
 	}
 	m.rollback.AddWorktreeCleanup(worktreePath)
+	if err := m.abortIfInterrupted(); err != nil {
+		progress.FailStep(1)
+		return err
+	}
+	if err := recordCreation(worktreePath, base); err != nil {
+		m.warn("Failed to record worktree metadata: %v", err)
+	}
 	progress.CompleteStep(1)
 
 	// Step 3: Project setup
 	progress.StartStep(2)
-
-	// Copy/link files based on configuration
-	if err := m.handleFileOperations(worktreePath); err != nil {
-		progress.FailStep(2)
-		m.ui.Warning("File operations failed: %v", err)
-		m.ui.Warning("Rolling back worktree creation")
-		_ = m.rollback.Execute()
-		return fmt.Errorf("file operations failed: %w", err)
-	}
-
-	// Execute post-create hooks
-	hookCtx.Event = types.HookPostCreate
-	if err := m.executeHooks(types.HookPostCreate, hookCtx); err != nil {
-		m.ui.Warning("Post-create hook failed, but worktree was created: %v", err)
+	switch {
+	case options.NoSetup:
+		m.ui.Info("Skipping project setup; run 'wtree setup --hooks %s' when you need it", branchName)
+	case m.backgroundSetup():
+		// Setting up with copies and hooks can take minutes; let the user in now
+		job, err := m.StartJob("setup", branchName, backgroundSetupArgs(worktreePath, options))
+		if err != nil {
+			m.warn("Could not start background setup: %v; run 'wtree setup --hooks %s'", err, branchName)
+		} else {
+			m.ui.Info("Project setup running in the background (job %s); see 'wtree jobs'", job.ID)
+		}
+	default:
+		if err := m.runCreateSetup(worktreePath, hookCtx, options); err != nil {
+			progress.FailStep(2)
+			return err
+		}
 	}
 	progress.CompleteStep(2)
 
 	// Success - clear rollback operations
 	m.rollback.Clear()
-	m.ui.Success("Worktree created successfully: %s", worktreePath)
+	m.completed("Worktree created successfully: %s", worktreePath)
+	m.notify(types.NotifyWorktreeCreated, map[string]string{"branch": branchName, "path": worktreePath})
+
+	if branchCreated {
+		m.setupNewBranchUpstream(branchName, worktreePath, options)
+	}
+
+	m.saveNoteAndTags(branchName, worktreePath, options)
+	if !branchCreated {
+		m.restoreAutostash(&types.WorktreeInfo{Path: worktreePath, Branch: branchName})
+	}
+
+	m.openConfiguredURLs(map[string]string{"branch": branchName})
 
 	// Step 4: Open in editor if configured
 	if options.OpenEditor || m.shouldAutoOpenEditor() {
 		progress.StartStep(3)
-		if err := m.openInEditor(worktreePath); err != nil {
+		endEditor := m.timings.Start("editor open")
+		err := m.openInEditor(worktreePath)
+		endEditor()
+		if err != nil {
 			progress.FailStep(3)
-			m.ui.Warning("Failed to open in editor: %v", err)
+			m.warn("Failed to open in editor: %v", err)
 		} else {
 			progress.CompleteStep(3)
 		}
@@ -236,14 +401,180 @@ func (m *Manager) Create(branchName string, options CreateOptions) error {
 	return nil
 }
 
+// branchWorktree returns the linked worktree that has branch checked out,
+// or nil
+func (m *Manager) branchWorktree(branch string) *types.WorktreeInfo {
+	worktrees, err := m.repo.ListWorktrees()
+	if err != nil {
+		return nil
+	}
+	for _, wt := range worktrees {
+		if wt.Branch == branch && !wt.IsMainRepo {
+			return wt
+		}
+	}
+	return nil
+}
+
+// reuseWorktree is create for a branch that already has a worktree: it
+// reports the worktree, reruns setup with --refresh and opens it with --open
+func (m *Manager) reuseWorktree(wt *types.WorktreeInfo, options CreateOptions) error {
+	m.ui.Info("Worktree for '%s' already exists: %s", wt.Branch, wt.Path)
+
+	if options.DryRun {
+		if options.Refresh {
+			m.ui.Info("[DRY RUN] Would rerun setup in %s", wt.Path)
+		}
+		if options.Note != "" {
+			m.ui.Info("[DRY RUN] Would set the note of %s", wt.Branch)
+		}
+		if len(options.Tags) > 0 {
+			m.ui.Info("[DRY RUN] Would tag %s: %s", wt.Branch, strings.Join(options.Tags, ", "))
+		}
+		return nil
+	}
+
+	m.saveNoteAndTags(wt.Branch, wt.Path, options)
+
+	if options.Refresh {
+		setup := SetupOptions{RunHooks: !options.SkipHooks, SkipCopy: options.SkipCopy, SkipLink: options.SkipLink}
+		if err := m.Setup(wt.Path, setup); err != nil {
+			return err
+		}
+	}
+
+	if options.OpenEditor {
+		if err := m.openInEditor(wt.Path); err != nil {
+			m.warn("Failed to open in editor: %v", err)
+		}
+		return nil
+	}
+
+	m.ui.InfoIndented("Switch to it:  eval \"$(wtree switch %s)\"", wt.Branch)
+	m.ui.InfoIndented("Open it:       wtree create %s --open", wt.Branch)
+	if !options.Refresh {
+		m.ui.InfoIndented("Rerun setup:   wtree create %s --refresh", wt.Branch)
+	}
+	return nil
+}
+
+// saveNoteAndTags stores create's --note as the branch description and adds
+// its --tag tags to the worktree's; failures only warn
+func (m *Manager) saveNoteAndTags(branch, worktreePath string, options CreateOptions) {
+	if options.Note != "" {
+		if err := m.repo.SetBranchDescription(branch, options.Note); err != nil {
+			m.warn("Failed to save note: %v", err)
+		}
+	}
+	if len(options.Tags) > 0 {
+		tags, _ := NormalizeTags(append(worktreeTags(worktreePath), options.Tags...))
+		if err := saveTags(worktreePath, tags); err != nil {
+			m.warn("Failed to save tags: %v", err)
+		}
+	}
+}
+
+// backgroundSetup reports whether setup.background moves project setup after
+// create into a background job
+func (m *Manager) backgroundSetup() bool {
+	return m.globalConfig != nil && m.globalConfig.Setup.Background
+}
+
+// runCreateSetup copies/links files, sets up git hooks and runs post_create
+// hooks in a newly created worktree, rolling the creation back if the file
+// operations fail or a signal stops it
+func (m *Manager) runCreateSetup(worktreePath string, hookCtx types.HookContext, options CreateOptions) error {
+	// Copy/link files based on configuration
+	endFiles := m.timings.Start("copy/link files")
+	err := m.handleFileOperations(hookCtx, options.SkipCopy, options.SkipLink)
+	endFiles()
+	if err != nil {
+		m.warn("File operations failed: %v", err)
+		m.warn("Rolling back worktree creation")
+		_ = m.runRollback()
+		return fmt.Errorf("file operations failed: %w", err)
+	}
+
+	endGitHooks := m.timings.Start("git hooks setup")
+	if err := m.setupGitHooks(worktreePath); err != nil {
+		m.warn("Git hook setup failed: %v", err)
+	}
+	endGitHooks()
+
+	// Execute post-create hooks
+	if options.SkipHooks {
+		return m.abortIfInterrupted()
+	}
+	hookCtx.Event = types.HookPostCreate
+	if err := m.executeHooks(types.HookPostCreate, hookCtx); err != nil {
+		if abortErr := m.abortIfInterrupted(); abortErr != nil {
+			return abortErr
+		}
+		m.warn("Post-create hook failed, but worktree was created: %v", err)
+	}
+	return m.abortIfInterrupted()
+}
+
+// backgroundSetupArgs returns the wtree arguments of the job that sets up a
+// new worktree in the background, keeping create's --skip-* choices
+func backgroundSetupArgs(worktreePath string, options CreateOptions) []string {
+	args := []string{"setup", worktreePath}
+	if !options.SkipHooks {
+		args = append(args, "--hooks")
+	}
+	if options.SkipCopy {
+		args = append(args, "--skip-copy")
+	}
+	if options.SkipLink {
+		args = append(args, "--skip-link")
+	}
+	return args
+}
+
+// checkWorktreeLimit enforces limits.max_worktrees before a new worktree is created
+func (m *Manager) checkWorktreeLimit() error {
+	if m.globalConfig == nil || m.globalConfig.Limits.MaxWorktrees <= 0 {
+		return nil
+	}
+
+	worktrees, err := m.repo.ListWorktrees()
+	if err != nil {
+		return fmt.Errorf("failed to list worktrees: %w", err)
+	}
+
+	active := 0
+	for _, wt := range worktrees {
+		if !wt.IsMainRepo {
+			active++
+		}
+	}
+
+	limit := m.globalConfig.Limits.MaxWorktrees
+	if active < limit {
+		return nil
+	}
+
+	msg := fmt.Sprintf("repository has %d active worktrees (limit %d); run 'wtree cleanup' to remove stale worktrees", active, limit)
+	if m.globalConfig.Limits.OnLimit == types.LimitModeBlock {
+		return types.NewValidationError("worktree-limit", msg, nil)
+	}
+
+	m.warn("%s", msg)
+	return nil
+}
+
 // Delete removes a worktree and optionally its branch
 func (m *Manager) Delete(identifier string, options DeleteOptions) error {
+	defer m.beginTimings()()
+
 	if err := m.validateDeleteOptions(identifier, options); err != nil {
 		return err
 	}
 
 	// Resolve identifier to worktree info
+	endResolve := m.timings.Start("validation")
 	worktree, err := m.resolveWorktree(identifier)
+	endResolve()
 	if err != nil {
 		return err
 	}
@@ -253,50 +584,85 @@ func (m *Manager) Delete(identifier string, options DeleteOptions) error {
 			"cannot delete main repository worktree", nil)
 	}
 
-	// Acquire operation lock to prevent concurrent operations on this worktree
-	var operationLock *OperationLock
-	if m.lockManager != nil {
-		timeout := m.getOperationTimeout()
-		operationLock, err = m.lockManager.AcquireLock(LockTypeDelete, worktree.Path, timeout)
-		if err != nil {
-			return fmt.Errorf("failed to acquire operation lock: %w", err)
+	// Deleting the worktree the shell is in would leave it in a dead path
+	returnPath := ""
+	if cwd, err := os.Getwd(); err == nil && pathWithin(cwd, worktree.Path) {
+		if !options.Yes {
+			return types.NewValidationError("delete-worktree",
+				fmt.Sprintf("refusing to delete the worktree you are currently in: %s; cd elsewhere or use --yes", worktree.Path), nil)
 		}
-		defer func() {
-			if releaseErr := m.lockManager.ReleaseLock(operationLock); releaseErr != nil {
-				m.ui.Warning("Failed to release operation lock: %v", releaseErr)
-			}
-		}()
+		// Resolve now: git can't run from the current directory once it's gone
+		returnPath = m.mainWorktreePath()
 	}
 
-	m.ui.Header("Deleting worktree: %s", worktree.Branch)
+	// Acquire operation lock to prevent concurrent operations on this worktree
+	endLock := m.timings.Start("operation lock")
+	release, err := m.acquireOperationLock(LockTypeDelete, worktree.Path)
+	endLock()
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	m.ui.Header("Deleting worktree: %s", worktree.DisplayBranch())
 
-	// Check for uncommitted changes
-	if !options.Force {
+	// Check for uncommitted changes; they can be stashed for the branch
+	stash := false
+	if !options.IgnoreDirty {
+		endStatus := m.timings.Start("status check")
 		status, err := m.repo.GetWorktreeStatus(worktree.Path)
-		if err == nil && !status.IsClean {
-			if !options.IgnoreDirty {
+		endStatus()
+		if err == nil && status.Operation != nil {
+			return types.NewValidationError("delete-worktree",
+				fmt.Sprintf("worktree has a git operation in progress (%s): %s; finish or abort it, or use --ignore-dirty",
+					status.Operation, worktree.Path), nil)
+		}
+		if err == nil && (!status.IsClean || hasUntrackedFiles(worktree.Path)) {
+			if stash = m.offerDeleteAutostash(worktree, options); !stash {
 				return types.NewValidationError("delete-worktree",
-					fmt.Sprintf("worktree has uncommitted changes: %s", worktree.Path), nil)
+					fmt.Sprintf("worktree has uncommitted changes: %s; commit or stash them, or use --autostash or --ignore-dirty", worktree.Path), nil)
 			}
-			m.ui.Warning("Worktree has uncommitted changes but ignoring due to --ignore-dirty")
 		}
 	}
 
-	// Confirm deletion unless forced
-	if !options.Force {
-		msg := fmt.Sprintf("Delete worktree '%s' at %s?", worktree.Branch, worktree.Path)
-		if err := m.ui.Confirm(msg); err != nil {
+	// Keep branches that are protected or in review on GitHub
+	if options.DeleteBranch && worktree.Branch != "" && !options.AllowProtected {
+		if err := m.checkBranchDeletable(worktree.Branch); err != nil {
 			return err
 		}
 	}
 
+	// Confirm deletion unless --yes
+	if !options.Yes {
+		msg := fmt.Sprintf("Delete worktree '%s' at %s?", worktree.DisplayBranch(), worktree.Path)
+		if err := m.confirm(ConfirmDelete, msg); err != nil {
+			return err
+		}
+	}
+
+	// A detached worktree has no branch to delete
+	if options.DeleteBranch && worktree.Branch == "" {
+		m.warn("HEAD is detached in %s; there is no branch to delete", worktree.Path)
+		options.DeleteBranch = false
+	}
+
 	// If dry run, show what would be done and exit
 	if options.DryRun {
-		m.ui.Info("[DRY RUN] Would remove worktree: %s", worktree.Path)
+		if m.useTrash(options) {
+			m.ui.Info("[DRY RUN] Would move worktree to the trash: %s", worktree.Path)
+		} else {
+			m.ui.Info("[DRY RUN] Would remove worktree: %s", worktree.Path)
+		}
+		for _, link := range submoduleLinks(worktree.Path) {
+			m.ui.Info("[DRY RUN] Would remove submodule worktree: %s", link.Worktree)
+		}
+		if stash {
+			m.ui.Info("[DRY RUN] Would stash uncommitted changes as %s", autostashMessage(worktree.Branch))
+		}
 		if options.DeleteBranch {
 			m.ui.Info("[DRY RUN] Would delete branch: %s", worktree.Branch)
 		}
-		m.ui.Success("[DRY RUN] Deletion preview completed")
+		m.completed("[DRY RUN] Deletion preview completed")
 		return nil
 	}
 
@@ -306,62 +672,192 @@ func (m *Manager) Delete(identifier string, options DeleteOptions) error {
 		return fmt.Errorf("pre-delete hook failed: %w", err)
 	}
 
-	// Remove the worktree
-	m.ui.Info("Removing worktree: %s", worktree.Path)
-	if err := m.repo.RemoveWorktree(worktree.Path, options.Force); err != nil {
-		return fmt.Errorf("failed to remove worktree: %w", err)
+	// Stash only once the hooks let the delete go ahead, and put the changes
+	// back if it stops after all
+	abort := func(err error) error { return err }
+	if stash {
+		if err := m.autostash(worktree); err != nil {
+			return err
+		}
+		abort = func(err error) error {
+			m.restoreAutostash(worktree)
+			return err
+		}
+	}
+
+	if err := m.removeSubmoduleWorktrees(worktree.Path, options.IgnoreDirty); err != nil {
+		return abort(err)
+	}
+
+	// Remove the worktree, or keep it in the trash for restore
+	if m.useTrash(options) {
+		m.ui.Info("Moving worktree to the trash: %s", worktree.Path)
+		endTrash := m.timings.Start("move to trash")
+		entry, err := m.moveToTrash(worktree)
+		endTrash()
+		if err != nil {
+			return abort(fmt.Errorf("failed to trash worktree: %w", err))
+		}
+		m.ui.Info("Restore with: wtree trash restore %s", entry.ID)
+		m.purgeExpiredTrash()
+	} else {
+		m.ui.Info("Removing worktree: %s", worktree.Path)
+		endRemove := m.timings.Start("git worktree remove")
+		err := m.withGitLock(func() error { return m.repo.RemoveWorktree(worktree.Path, options.IgnoreDirty) })
+		endRemove()
+		if err != nil {
+			return abort(fmt.Errorf("failed to remove worktree: %w", err))
+		}
 	}
 
 	// Delete branch if requested
 	if options.DeleteBranch {
 		m.ui.Info("Deleting branch: %s", worktree.Branch)
-		if err := m.repo.DeleteBranch(worktree.Branch, options.Force); err != nil {
-			m.ui.Warning("Failed to delete branch: %v", err)
+		endBranch := m.timings.Start("delete branch")
+		if err := m.withGitLock(func() error { return m.repo.DeleteBranch(worktree.Branch, options.ForceBranchDelete) }); err != nil {
+			if options.ForceBranchDelete {
+				m.warn("Failed to delete branch: %v", err)
+			} else {
+				m.warn("Failed to delete branch (is it unmerged? use --force-branch-delete): %v", err)
+			}
 		}
+		endBranch()
 	}
 
 	// Execute post-delete hooks
 	hookCtx.Event = types.HookPostDelete
 	if err := m.executeHooks(types.HookPostDelete, hookCtx); err != nil {
-		m.ui.Warning("Post-delete hook failed: %v", err)
+		m.warn("Post-delete hook failed: %v", err)
 	}
 
-	m.ui.Success("Worktree deleted successfully: %s", worktree.Branch)
+	m.completed("Worktree deleted successfully: %s", worktree.DisplayBranch())
+
+	if returnPath != "" {
+		m.leaveDeletedWorktree(returnPath)
+	}
 	return nil
 }
 
-// List displays all worktrees with their status
-func (m *Manager) List(options ListOptions) error {
-	m.ui.Header("Git Worktrees")
+// leaveDeletedWorktree points a shell that was inside a deleted worktree back
+// at the main repository: shell integration changes directory automatically
+// via WTREE_CD_FILE, otherwise a cd hint is printed
+func (m *Manager) leaveDeletedWorktree(mainPath string) {
+	if m.requestShellCD(mainPath) {
+		return
+	}
+	m.warn("Your shell was inside the deleted worktree. Run: cd %s", shellescape(mainPath))
+}
+
+// requestShellCD asks the wrapping shell function to change directory by
+// writing the target to $WTREE_CD_FILE. It reports whether a request was made.
+func (m *Manager) requestShellCD(path string) bool {
+	cdFile := os.Getenv("WTREE_CD_FILE")
+	if cdFile == "" {
+		return false
+	}
+	if err := os.WriteFile(cdFile, []byte(path+"\n"), 0600); err != nil {
+		m.warn("Failed to write %s: %v", cdFile, err)
+		return false
+	}
+	return true
+}
 
+// mainWorktreePath returns the path of the main repository worktree
+func (m *Manager) mainWorktreePath() string {
 	worktrees, err := m.repo.ListWorktrees()
+	if err != nil {
+		return ""
+	}
+	for _, wt := range worktrees {
+		if wt.IsMainRepo {
+			return wt.Path
+		}
+	}
+	return ""
+}
+
+// List displays all worktrees with their status
+func (m *Manager) List(options ListOptions) error {
+	listWorktrees := m.repo.ListWorktrees
+	if options.Summary {
+		listWorktrees = m.repo.ListWorktreesWithStatus
+	}
+	worktrees, err := listWorktrees()
 	if err != nil {
 		return fmt.Errorf("failed to list worktrees: %w", err)
 	}
 
+	if options.Mine {
+		worktrees = m.filterMine(worktrees)
+	}
+	worktrees = filterTagged(worktrees, options.Tags)
+
+	if options.Porcelain {
+		m.printPorcelain(worktrees, func(wt *types.WorktreeInfo) bool {
+			return options.BranchFilter == "" || strings.Contains(wt.Branch, options.BranchFilter)
+		})
+		return nil
+	}
+
+	m.ui.Header("Git Worktrees")
+
 	if len(worktrees) == 0 {
 		m.ui.Info("No worktrees found")
 		return nil
 	}
 
-	// Create table
-	table := m.ui.NewTable()
-	table.SetHeaders("Branch", "Path", "Status", "Type")
+	if options.Summary {
+		return m.listSummary(worktrees, options)
+	}
+
+	// Only show the created, note and tags columns when at least one
+	// worktree has one
+	notes := make(map[string]string)
+	tags := make(map[string]string)
+	created := make(map[string]string)
+	for _, wt := range worktrees {
+		if note := m.worktreeNote(wt); note != "" {
+			notes[wt.Path] = note
+		}
+		if when := createdAt(wt.Path); !when.IsZero() {
+			created[wt.Path] = m.ui.Time(when)
+		}
+		if wtTags := worktreeTags(wt.Path); len(wtTags) > 0 {
+			tags[wt.Path] = strings.Join(wtTags, ",")
+		}
+	}
+
+	// Only show the setup column when the repository's toolchain is recognized
+	var toolchain ToolchainDetector
+	for _, wt := range worktrees {
+		if wt.IsMainRepo {
+			toolchain = detectToolchain(wt.Path)
+		}
+	}
 
+	var rows []listRow
+	var allMarks []worktreeMarks
 	for _, wt := range worktrees {
 		status := "clean"
 		wtType := "worktree"
+		group := ""
 
 		if wt.IsMainRepo {
 			wtType = "main"
 		}
 
-		// Get status if requested
-		if options.ShowStatus && !wt.IsMainRepo {
-			if wtStatus, err := m.repo.GetWorktreeStatus(wt.Path); err == nil {
+		// Get status if requested (grouping by state needs it too)
+		var wtStatus *git.WorktreeStatus
+		if (options.ShowStatus || options.GroupBy == GroupByState) && !wt.IsMainRepo {
+			if wtStatus, err = m.repo.GetWorktreeStatus(wt.Path); err == nil {
 				if !wtStatus.IsClean {
 					status = fmt.Sprintf("dirty (%d files)", wtStatus.ChangedFiles)
 				}
+				if wtStatus.Operation != nil {
+					status = fmt.Sprintf("%s, %s", wtStatus.Operation, status)
+				}
+			} else {
+				wtStatus = nil
 			}
 		}
 
@@ -373,10 +869,59 @@ func (m *Manager) List(options ListOptions) error {
 			continue
 		}
 
-		table.AddRow(wt.Branch, wt.Path, status, wtType)
+		if options.GroupBy != "" {
+			group = m.listGroup(wt, wtStatus, options.GroupBy)
+		}
+
+		branch := wt.DisplayBranch()
+		marks := m.marksFor(wt, wtStatus)
+		if text := m.marksText(marks); text != "" {
+			branch += " " + text
+		}
+		allMarks = append(allMarks, marks)
+
+		row := listRow{
+			cells:   []string{branch, wt.Path, status, wtType},
+			created: created[wt.Path],
+			note:    notes[wt.Path],
+			tags:    tags[wt.Path],
+			group:   group,
+		}
+		if toolchain != nil {
+			row.setup = setupState(toolchain, wt.Path)
+		}
+		rows = append(rows, row)
+	}
+
+	// Remote worktrees created with --host
+	if remotes, err := m.loadRemoteWorktrees(); err == nil && len(options.Tags) == 0 {
+		for _, rw := range remotes {
+			if options.BranchFilter != "" && !strings.Contains(rw.Branch, options.BranchFilter) {
+				continue
+			}
+			if options.OnlyDirty {
+				continue
+			}
+			rows = append(rows, listRow{
+				cells: []string{rw.Branch, rw.Location(), "remote", "remote"},
+				setup: "-",
+				group: "remote",
+			})
+		}
 	}
 
-	table.Render()
+	m.renderListRows(rows, listColumns{
+		setup:   toolchain != nil,
+		created: len(created) > 0,
+		notes:   len(notes) > 0,
+		tags:    len(tags) > 0,
+	})
+	if options.ShowStatus {
+		m.ui.Info("Ahead/behind from %s", m.remoteFreshness())
+	}
+	if legend := glyphLegend(allMarks); legend != "" && !m.ui.Accessible() {
+		m.ui.Info("Legend: %s", legend)
+	}
 	return nil
 }
 
@@ -386,15 +931,24 @@ func (m *Manager) Merge(sourceBranch string, options MergeOptions) error {
 		return err
 	}
 
-	currentBranch, err := m.repo.GetCurrentBranch()
+	head, err := m.repo.GetHeadState()
 	if err != nil {
 		return fmt.Errorf("failed to get current branch: %w", err)
 	}
+	if head.Detached {
+		return types.NewValidationError("merge",
+			fmt.Sprintf("cannot merge into a detached HEAD (%s); check out a branch first", head), nil)
+	}
+	if head.Unborn {
+		return types.NewValidationError("merge",
+			fmt.Sprintf("cannot merge into '%s': it has no commits yet", head.Branch), nil)
+	}
+	currentBranch := head.Branch
 
 	m.ui.Header("Merging '%s' into '%s'", sourceBranch, currentBranch)
 
 	// Check working directory is clean
-	if !options.Force {
+	if !options.IgnoreDirty {
 		isClean, err := m.repo.IsClean()
 		if err != nil {
 			return fmt.Errorf("failed to check repository status: %w", err)
@@ -405,6 +959,17 @@ func (m *Manager) Merge(sourceBranch string, options MergeOptions) error {
 		}
 	}
 
+	if options.DryRun {
+		return m.previewMerge(sourceBranch, currentBranch, options)
+	}
+
+	// Run project checks in the source branch's worktree
+	if !options.SkipChecks {
+		if err := m.runPreMergeChecks(sourceBranch, currentBranch); err != nil {
+			return err
+		}
+	}
+
 	// Execute pre-merge hooks
 	repoRoot, _ := m.repo.GetRepoRoot()
 	hookCtx := m.buildHookContext(types.HookPreMerge, currentBranch, repoRoot)
@@ -413,47 +978,260 @@ func (m *Manager) Merge(sourceBranch string, options MergeOptions) error {
 		return fmt.Errorf("pre-merge hook failed: %w", err)
 	}
 
-	// Perform the merge
-	m.ui.Info("Merging branch: %s", sourceBranch)
-	if err := m.repo.Merge(sourceBranch, options.Message); err != nil {
-		return fmt.Errorf("merge failed: %w", err)
+	// Perform the merge
+	m.ui.Info("Merging branch: %s", sourceBranch)
+	if err := m.repo.Merge(sourceBranch, m.resolveGitMergeOptions(options)); err != nil {
+		return fmt.Errorf("merge failed: %w", err)
+	}
+
+	// Execute post-merge hooks
+	hookCtx.Event = types.HookPostMerge
+	if err := m.executeHooks(types.HookPostMerge, hookCtx); err != nil {
+		m.warn("Post-merge hook failed: %v", err)
+	}
+
+	m.completed("Merge completed successfully")
+	return nil
+}
+
+// previewMerge shows what Merge would do: the merge base, the commits to be
+// merged and the files predicted to conflict
+func (m *Manager) previewMerge(sourceBranch, currentBranch string, options MergeOptions) error {
+	preview, err := m.repo.PreviewMerge(sourceBranch)
+	if err != nil {
+		return err
+	}
+
+	m.ui.Info("[DRY RUN] Merge base: %s", git.ShortHash(preview.MergeBase))
+	if len(preview.Commits) == 0 {
+		m.completed("[DRY RUN] '%s' is already up to date with '%s'; nothing to merge", currentBranch, sourceBranch)
+		return nil
+	}
+	m.ui.Info("[DRY RUN] Would merge %d commits:", len(preview.Commits))
+	for _, commit := range preview.Commits {
+		m.ui.Info("  %s", commit)
+	}
+
+	switch {
+	case !preview.Predicted:
+		m.ui.Info("[DRY RUN] Conflict prediction needs git %s or newer", git.FeatureMergeTree.MinVersion())
+	case len(preview.Conflicts) > 0:
+		m.warn("[DRY RUN] Would conflict in %d files:", len(preview.Conflicts))
+		for _, file := range preview.Conflicts {
+			m.ui.Info("  %s", file)
+		}
+	default:
+		m.ui.Info("[DRY RUN] No conflicts predicted")
+	}
+
+	if !options.SkipChecks && m.projectConfig != nil && len(m.projectConfig.PreMergeChecks) > 0 {
+		m.ui.Info("[DRY RUN] Would run %d pre-merge checks in '%s'", len(m.projectConfig.PreMergeChecks), sourceBranch)
+	}
+	m.completed("[DRY RUN] Merge preview completed")
+	return nil
+}
+
+// runPreMergeChecks executes the configured pre_merge_checks inside the source branch's worktree
+func (m *Manager) runPreMergeChecks(sourceBranch, currentBranch string) error {
+	if m.projectConfig == nil || len(m.projectConfig.PreMergeChecks) == 0 {
+		return nil
+	}
+
+	sourceWorktree, err := m.resolveWorktree(sourceBranch)
+	if err != nil {
+		return types.NewValidationError("pre-merge-check",
+			fmt.Sprintf("no worktree found for '%s' to run pre-merge checks in (use --skip-checks to bypass)", sourceBranch), err)
+	}
+
+	hookCtx := m.buildHookContext(types.HookPreMerge, sourceBranch, sourceWorktree.Path)
+	hookCtx.TargetBranch = currentBranch
+
+	timeout := m.configMgr.ResolveTimeout(m.globalConfig, m.projectConfig)
+	executor := NewHookExecutor(m.projectConfig, timeout, m.globalConfig.UI.Verbose)
+	m.hookChecks(executor, sourceWorktree.Path)
+	m.hookOutput(executor)
+	if err := executor.ExecuteChecks(m.projectConfig.PreMergeChecks, hookCtx); err != nil {
+		return fmt.Errorf("pre-merge checks failed, merge aborted: %w", err)
+	}
+
+	m.completed("Pre-merge checks passed")
+	return nil
+}
+
+// resolveGitMergeOptions combines merge flags with signing defaults from git config.
+// wtree.signoff, wtree.gpgSign and user.signingKey may be set per worktree.
+func (m *Manager) resolveGitMergeOptions(options MergeOptions) git.MergeOptions {
+	gitOpts := git.MergeOptions{
+		Message:  options.Message,
+		Signoff:  options.Signoff || m.gitConfigBool("wtree.signoff"),
+		GPGSign:  options.GPGSign || options.GPGKeyID != "" || m.gitConfigBool("wtree.gpgSign"),
+		GPGKeyID: options.GPGKeyID,
+	}
+
+	if gitOpts.GPGSign && gitOpts.GPGKeyID == "" {
+		gitOpts.GPGKeyID, _ = m.repo.GetConfigValue("user.signingKey")
+	}
+
+	return gitOpts
+}
+
+// gitConfigBool reads a boolean git config value in any of git's spellings,
+// treating unset or invalid values as false
+func (m *Manager) gitConfigBool(key string) bool {
+	value, err := m.repo.GetConfigValue(key)
+	if err != nil {
+		return false
+	}
+	enabled, err := git.ParseConfigBool(value)
+	return err == nil && enabled
+}
+
+// Switch changes to a different worktree/branch. Leaving another worktree
+// runs pre_switch there first, which can stop the switch, and post_switch in
+// the worktree switched to.
+func (m *Manager) Switch(identifier string, options SwitchOptions) error {
+	var worktree *types.WorktreeInfo
+	var err error
+	if identifier == "" {
+		if worktree, err = m.pickWorktree(); err != nil {
+			return err
+		}
+		if worktree == nil {
+			m.ui.Info("Selection cancelled")
+			return nil
+		}
+	} else if worktree, err = m.resolveWorktree(identifier); err != nil {
+		return err
+	}
+
+	if !pathExists(worktree.Path) {
+		return types.NewFileSystemError("switch", worktree.Path,
+			fmt.Sprintf("worktree path does not exist: %s", worktree.Path), nil)
+	}
+
+	if options.DryRun {
+		fmt.Println(worktree.Path)
+		return nil
+	}
+
+	// eval "$(wtree switch ...)" runs whatever reaches stdout, so hook
+	// output and messages go to stderr and only the cd command to stdout
+	return m.withUI(m.ui.WithOutput(os.Stderr)).enterWorktree(worktree, options)
+}
+
+// enterWorktree runs the switch to worktree once it is resolved: the switch
+// hooks, autostash and the cd
+func (m *Manager) enterWorktree(worktree *types.WorktreeInfo, options SwitchOptions) error {
+	current, _ := m.currentWorktree()
+	leaving := current != nil && current.Path != worktree.Path
+	if leaving {
+		if err := m.executeHooks(types.HookPreSwitch, m.switchHookContext(types.HookPreSwitch, current, worktree)); err != nil {
+			return fmt.Errorf("pre-switch hook failed: %w", err)
+		}
+	}
+
+	if err := m.stashOnLeave(worktree, options.Autostash); err != nil {
+		return err
+	}
+
+	if status, err := m.repo.GetWorktreeStatus(worktree.Path); err == nil && status.Operation != nil {
+		m.warn("Worktree has a git operation in progress: %s", status.Operation)
+	}
+
+	m.completed("Switching to worktree: %s (%s)", worktree.DisplayBranch(), worktree.Path)
+	m.restoreAutostash(worktree)
+	if leaving {
+		// The switch has happened, so a failure is only a warning
+		if err := m.executeHooks(types.HookPostSwitch, m.switchHookContext(types.HookPostSwitch, current, worktree)); err != nil {
+			m.warn("Post-switch hook failed: %v", err)
+		}
+	}
+
+	// With shell integration (wtree shell-init) the calling shell changes
+	// directory itself; otherwise print the command for eval "$(wtree switch ...)"
+	if !m.requestShellCD(worktree.Path) {
+		fmt.Printf("cd %s\n", shellescape(worktree.Path))
+	}
+
+	if options.OpenEditor || m.shouldAutoOpenEditor() {
+		if err := m.openInEditor(worktree.Path); err != nil {
+			m.warn("Failed to open in editor: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// switchHookContext describes a switch from one worktree to another for its
+// hooks. pre_switch runs in the worktree left and post_switch in the one
+// switched to; TargetBranch is the branch switched to, and WTREE_SWITCH_FROM
+// and WTREE_SWITCH_TO are both worktree paths.
+func (m *Manager) switchHookContext(event types.HookEvent, from, to *types.WorktreeInfo) types.HookContext {
+	runIn := to
+	if event == types.HookPreSwitch {
+		runIn = from
+	}
+	hookCtx := m.buildHookContext(event, runIn.Branch, runIn.Path)
+	hookCtx.TargetBranch = to.Branch
+	hookCtx.Environment["WTREE_SWITCH_FROM"] = from.Path
+	hookCtx.Environment["WTREE_SWITCH_TO"] = to.Path
+	return hookCtx
+}
+
+// Setup re-applies the project's file operations and git hook setup to an
+// existing worktree. Files that are already up to date are skipped.
+func (m *Manager) Setup(identifier string, options SetupOptions) error {
+	worktree, err := m.resolveWorktree(identifier)
+	if err != nil {
+		return err
+	}
+
+	if worktree.IsMainRepo {
+		return types.NewValidationError("setup",
+			"cannot run setup in the main repository worktree", nil)
+	}
+
+	m.ui.Header("Setting up worktree: %s", worktree.DisplayBranch())
+
+	hookCtx := m.buildHookContext(types.HookPostCreate, worktree.Branch, worktree.Path)
+	pm := &PRManager{Manager: m}
+	if prNumber := pm.extractPRNumber(worktree.Path, m.repo.GetRepoName()); prNumber > 0 {
+		hookCtx.Environment["WTREE_PR_NUMBER"] = strconv.Itoa(prNumber)
+	}
+
+	if err := m.handleFileOperations(hookCtx, options.SkipCopy, options.SkipLink); err != nil {
+		return fmt.Errorf("file operations failed: %w", err)
+	}
+
+	if err := m.setupGitHooks(worktree.Path); err != nil {
+		m.warn("Git hook setup failed: %v", err)
 	}
 
-	// Execute post-merge hooks
-	hookCtx.Event = types.HookPostMerge
-	if err := m.executeHooks(types.HookPostMerge, hookCtx); err != nil {
-		m.ui.Warning("Post-merge hook failed: %v", err)
+	if options.RunHooks {
+		if err := m.executeHooks(types.HookPostCreate, hookCtx); err != nil {
+			return fmt.Errorf("post-create hook failed: %w", err)
+		}
 	}
 
-	m.ui.Success("Merge completed successfully")
+	m.completed("Setup complete: %s", worktree.Path)
 	return nil
 }
 
-// Switch changes to a different worktree/branch
-func (m *Manager) Switch(identifier string, options SwitchOptions) error {
+// Which returns the filesystem path of the worktree for a branch or path
+// identifier, failing when none exists. It prints nothing so the result can
+// be used directly in scripts.
+func (m *Manager) Which(identifier string) (string, error) {
 	worktree, err := m.resolveWorktree(identifier)
 	if err != nil {
-		return err
+		return "", err
 	}
 
 	if !pathExists(worktree.Path) {
-		return types.NewFileSystemError("switch", worktree.Path,
+		return "", types.NewFileSystemError("which", worktree.Path,
 			fmt.Sprintf("worktree path does not exist: %s", worktree.Path), nil)
 	}
 
-	m.ui.Success("Switching to worktree: %s (%s)", worktree.Branch, worktree.Path)
-
-	// Output shell command to change directory
-	// This allows the user to run: eval "$(wtree switch branch-name)"
-	fmt.Printf("cd %s\n", shellescape(worktree.Path))
-
-	if options.OpenEditor || m.shouldAutoOpenEditor() {
-		if err := m.openInEditor(worktree.Path); err != nil {
-			m.ui.Warning("Failed to open in editor: %v", err)
-		}
-	}
-
-	return nil
+	return worktree.Path, nil
 }
 
 // shellescape escapes a path for safe use in shell commands
@@ -464,20 +1242,44 @@ func shellescape(path string) string {
 
 // Status shows detailed status information for worktrees
 func (m *Manager) Status(options StatusOptions) error {
-	m.ui.Header("Worktree Status")
+	m.autoFetch()
 
 	worktrees, err := m.repo.ListWorktrees()
 	if err != nil {
 		return fmt.Errorf("failed to list worktrees: %w", err)
 	}
 
+	// Get current working directory to identify current worktree
+	currentDir, _ := os.Getwd()
+
+	if options.Check {
+		return m.checkStatus(worktrees, options, currentDir)
+	}
+
+	if options.Porcelain {
+		m.printPorcelain(worktrees, func(wt *types.WorktreeInfo) bool {
+			return (options.BranchFilter == "" || strings.Contains(wt.Branch, options.BranchFilter)) &&
+				(!options.CurrentOnly || strings.HasPrefix(currentDir, wt.Path))
+		})
+		return nil
+	}
+
+	m.ui.Header("Worktree Status")
+
 	if len(worktrees) == 0 {
 		m.ui.Info("No worktrees found")
 		return nil
 	}
 
-	// Get current working directory to identify current worktree
-	currentDir, _ := os.Getwd()
+	// Ahead/behind is only as current as the last fetch
+	m.ui.Info("Ahead/behind from %s", m.remoteFreshness())
+
+	mainBranch := ""
+	for _, wt := range worktrees {
+		if wt.IsMainRepo {
+			mainBranch = wt.Branch
+		}
+	}
 
 	// Create detailed status display
 	for _, wt := range worktrees {
@@ -493,7 +1295,7 @@ func (m *Manager) Status(options StatusOptions) error {
 		}
 
 		// Display worktree header
-		header := wt.Branch
+		header := wt.DisplayBranch()
 		if isCurrent {
 			header += " (current)"
 		}
@@ -501,16 +1303,58 @@ func (m *Manager) Status(options StatusOptions) error {
 			header += " [main repository]"
 		}
 
+		var status *git.WorktreeStatus
+		var statusErr error
+		if !wt.IsMainRepo {
+			status, statusErr = m.repo.GetWorktreeStatus(wt.Path)
+		}
+		if text := m.marksText(m.marksFor(wt, status)); text != "" {
+			header += " " + text
+		}
+
 		m.ui.Header("%s", header)
 		m.ui.Info("Path: %s", wt.Path)
+		if wt.Upstream != "" {
+			m.ui.Info("Upstream: %s", wt.Upstream)
+		}
+		if wt.LockReason != "" {
+			m.ui.Info("Locked: %s", wt.LockReason)
+		}
+		if wt.Prunable {
+			m.ui.Info("Prunable: its directory is gone; 'git worktree prune' removes it")
+		}
+		if metadata := worktreeMetadata(wt.Path); metadata != nil {
+			m.ui.Info("Created: %s", m.provenance(metadata))
+		}
+		for _, link := range submoduleLinks(wt.Path) {
+			m.ui.Info("Submodule: %s on %s", link.Path, link.Branch)
+		}
+		if note := m.branchNote(wt.Branch); note != "" {
+			m.ui.Info("Note: %s", note)
+		}
+		if issue := worktreeIssue(wt.Path); issue != nil {
+			m.ui.Info("Issue: #%d %s (%s)", issue.Number, issue.Title, issue.URL)
+		}
+		m.showRecentNotes(wt.Path)
+		if options.TODOs && !wt.IsMainRepo && mainBranch != "" {
+			if added, removed, err := m.todoDelta(wt.Path, mainBranch); err == nil {
+				m.ui.Info("TODO/FIXME vs %s: +%d -%d", mainBranch, added, removed)
+			}
+		}
+		if manifest, err := loadSetupManifest(wt.Path); err == nil && manifest != nil {
+			m.ui.Info("Setup: %s (%s)", manifest.Stats(), m.ui.Time(manifest.CreatedAt))
+		}
 
 		// Get detailed status if not main repo
 		if !wt.IsMainRepo {
-			if status, err := m.repo.GetWorktreeStatus(wt.Path); err == nil {
+			if statusErr == nil {
+				if status.Operation != nil {
+					m.warn("Operation in progress: %s", status.Operation)
+				}
 				if status.IsClean {
-					m.ui.Success("Status: Clean")
+					m.completed("Status: Clean")
 				} else {
-					m.ui.Warning("Status: Dirty (%d changed files)", status.ChangedFiles)
+					m.warn("Status: Dirty (%d changed files)", status.ChangedFiles)
 					if options.Verbose && status.ChangedFiles < 10 {
 						// Show changed files if not too many
 						// Note: This would need the git status to include file names
@@ -524,14 +1368,14 @@ func (m *Manager) Status(options StatusOptions) error {
 						m.ui.Info("Ahead of remote by %d commits", status.Ahead)
 					}
 					if status.Behind > 0 {
-						m.ui.Warning("Behind remote by %d commits", status.Behind)
+						m.warn("Behind remote by %d commits", status.Behind)
 					}
 					if status.Ahead == 0 && status.Behind == 0 {
-						m.ui.Success("Up to date with remote")
+						m.completed("Up to date with remote")
 					}
 				}
 			} else {
-				m.ui.Error("Failed to get status: %v", err)
+				m.ui.Error("Failed to get status: %v", statusErr)
 			}
 		}
 
@@ -543,6 +1387,12 @@ func (m *Manager) Status(options StatusOptions) error {
 
 // Cleanup performs intelligent cleanup of worktrees
 func (m *Manager) Cleanup(options CleanupOptions) error {
+	if options.OlderThan != "" {
+		if _, err := types.ParseDuration(options.OlderThan); err != nil {
+			return types.NewValidationError("older-than", err.Error(), nil)
+		}
+	}
+	defer m.beginTimings()()
 	m.ui.Header("Smart Worktree Cleanup")
 
 	worktrees, err := m.repo.ListWorktrees()
@@ -550,6 +1400,18 @@ func (m *Manager) Cleanup(options CleanupOptions) error {
 		return fmt.Errorf("failed to list worktrees: %w", err)
 	}
 
+	mainBranch, mainPath := "", ""
+	for _, wt := range worktrees {
+		if wt.IsMainRepo {
+			mainBranch, mainPath = wt.Branch, wt.Path
+		}
+	}
+
+	if options.Mine {
+		worktrees = m.filterMine(worktrees)
+	}
+	worktrees = filterTagged(worktrees, options.Tags)
+
 	if len(worktrees) == 0 {
 		m.ui.Info("No worktrees found")
 		return nil
@@ -558,7 +1420,12 @@ func (m *Manager) Cleanup(options CleanupOptions) error {
 	// Find cleanup candidates with spinner
 	spinner := m.ui.NewSpinner("Analyzing worktrees for cleanup candidates...")
 	spinner.Start()
+	endAnalysis := m.timings.Start("find candidates")
 	candidates, err := m.findCleanupCandidates(worktrees, options)
+	if err == nil {
+		m.addCandidateFacts(candidates, mainBranch)
+	}
+	endAnalysis()
 	if err != nil {
 		spinner.ErrorStop("Failed to analyze worktrees")
 		return fmt.Errorf("failed to find cleanup candidates: %w", err)
@@ -566,27 +1433,48 @@ func (m *Manager) Cleanup(options CleanupOptions) error {
 	spinner.SuccessStop(fmt.Sprintf("Found %d cleanup candidates", len(candidates)))
 
 	if len(candidates) == 0 {
-		m.ui.Success("No worktrees found that need cleanup")
+		m.completed("No worktrees found that need cleanup")
 		return nil
 	}
 
+	if !options.AllowProtected {
+		m.keepProtectedBranches(candidates)
+	}
+
 	// Display candidates
 	if options.DryRun || options.Verbose {
 		m.ui.Header("Cleanup Candidates")
 		table := m.ui.NewTable()
-		table.SetHeaders("Branch", "Path", "Reason", "Last Activity")
+		table.SetHeaders("Branch", "Path", "Reason", "Ahead", "Merge Base", "Last Activity")
 
 		for _, candidate := range candidates {
 			table.AddRow(
 				candidate.Branch,
 				candidate.Path,
 				candidate.Reason,
+				candidateAhead(candidate),
+				m.candidateMergeBase(candidate),
 				candidate.LastActivity,
 			)
 		}
+		if mainBranch != "" {
+			m.ui.Info("Ahead and merge base are relative to %s", mainBranch)
+		}
 		table.Render()
 	}
 
+	// Show what dirty candidates would lose and decide how to handle them
+	if dirty := m.markDirtyCandidates(candidates); dirty > 0 {
+		m.showDirtyChanges(candidates)
+		if !options.DryRun {
+			candidates = m.resolveDirtyCandidates(candidates, dirty, options)
+			if len(candidates) == 0 {
+				m.ui.Info("Nothing left to clean up")
+				return nil
+			}
+		}
+	}
+
 	if options.DryRun {
 		m.ui.Info("Dry run: %d worktrees would be cleaned up", len(candidates))
 		return nil
@@ -594,32 +1482,151 @@ func (m *Manager) Cleanup(options CleanupOptions) error {
 
 	// Confirm cleanup unless auto mode
 	if !options.Auto {
-		if err := m.ui.Confirm(fmt.Sprintf("Clean up %d worktrees?", len(candidates))); err != nil {
+		if err := m.confirm(ConfirmCleanup, fmt.Sprintf("Clean up %d worktrees?", len(candidates))); err != nil {
 			m.ui.Info("Cleanup cancelled")
 			return nil
 		}
 	}
 
 	// Perform cleanup
-	cleaned := 0
-	for _, candidate := range candidates {
-		m.ui.Info("Cleaning up %s...", candidate.Branch)
+	results := m.removeCleanupCandidates(candidates)
+
+	if cleaned := m.renderCleanupSummary(results); cleaned > 0 {
+		m.notify(types.NotifyCleanup, map[string]string{"count": strconv.Itoa(cleaned)})
+		m.runPruneHooks(results, mainBranch, mainPath)
+	}
+	return nil
+}
 
-		deleteOptions := DeleteOptions{
-			DeleteBranch: candidate.ShouldDeleteBranch,
-			Force:        true,
-			IgnoreDirty:  true,
+// runPruneHooks runs on_prune once in the main checkout after cleanup,
+// passing the worktrees removed as newline-separated WTREE_PRUNED_WORKTREES
+// paths and WTREE_PRUNED_BRANCHES. They are gone, so a failure is only a
+// warning.
+func (m *Manager) runPruneHooks(results []cleanupResult, mainBranch, mainPath string) {
+	var paths, branches []string
+	for _, result := range results {
+		if result.err == nil {
+			paths = append(paths, result.candidate.Path)
+			branches = append(branches, result.candidate.Branch)
 		}
+	}
+
+	hookCtx := m.buildHookContext(types.HookOnPrune, mainBranch, mainPath)
+	hookCtx.Environment["WTREE_PRUNED_WORKTREES"] = strings.Join(paths, "\n")
+	hookCtx.Environment["WTREE_PRUNED_BRANCHES"] = strings.Join(branches, "\n")
+	if err := m.executeHooks(types.HookOnPrune, hookCtx); err != nil {
+		m.warn("on_prune hook failed: %v", err)
+	}
+}
 
-		if err := m.Delete(candidate.Branch, deleteOptions); err != nil {
-			m.ui.Warning("Failed to clean up %s: %v", candidate.Branch, err)
+// renderCleanupSummary prints the outcome table for removed candidates and
+// returns how many were cleaned up
+func (m *Manager) renderCleanupSummary(results []cleanupResult) int {
+	m.ui.Header("Cleanup Summary")
+	table := m.ui.NewTable()
+	table.SetHeaders("Branch", "Path", "Result")
+	cleaned := 0
+	for _, result := range results {
+		outcome := "removed"
+		if result.err != nil {
+			outcome = "failed: " + result.err.Error()
 		} else {
 			cleaned++
+			if result.branchErr != nil {
+				outcome = "removed; branch kept: " + result.branchErr.Error()
+			} else if result.candidate.ShouldDeleteBranch {
+				outcome = "removed with branch"
+			} else if result.candidate.Trash {
+				outcome = "moved to trash"
+			}
 		}
+		table.AddRow(result.candidate.Branch, result.candidate.Path, outcome)
 	}
+	table.Render()
 
-	m.ui.Success("Cleaned up %d/%d worktrees", cleaned, len(candidates))
-	return nil
+	if cleaned < len(results) {
+		m.warn("Cleaned up %d/%d worktrees", cleaned, len(results))
+	} else {
+		m.completed("Cleaned up %d/%d worktrees", cleaned, len(results))
+	}
+	return cleaned
+}
+
+// cleanupResult is the outcome of removing one cleanup candidate
+type cleanupResult struct {
+	candidate CleanupCandidate
+	err       error // Worktree removal failed
+	branchErr error // Worktree removed but the branch could not be deleted
+}
+
+// removeCleanupCandidates deletes candidates with a bounded worker pool
+// (performance.max_concurrent_operations), printing a row as each finishes.
+// Branches are deleted afterwards, one at a time, since concurrent ref
+// updates contend on git's packed-refs lock. Results keep candidate order.
+func (m *Manager) removeCleanupCandidates(candidates []CleanupCandidate) []cleanupResult {
+	workers := 1
+	if m.globalConfig != nil && m.globalConfig.Performance.MaxConcurrentOps > 1 {
+		workers = m.globalConfig.Performance.MaxConcurrentOps
+	}
+
+	// Workers report through the progress rows below instead of printing,
+	// and their time is recorded here as one phase per candidate
+	quiet := m.quietCopy()
+	quiet.timings = nil
+
+	results := make([]cleanupResult, len(candidates))
+	jobs := make(chan int)
+	done := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				deleteOptions := DeleteOptions{
+					Yes:         true,
+					IgnoreDirty: true,
+					Trash:       candidates[i].Trash,
+				}
+				endRemove := m.timings.Start("remove " + candidates[i].Branch)
+				results[i] = cleanupResult{
+					candidate: candidates[i],
+					err:       quiet.Delete(candidates[i].Path, deleteOptions),
+				}
+				endRemove()
+				done <- i
+			}
+		}()
+	}
+
+	go func() {
+		for i := range candidates {
+			jobs <- i
+		}
+		close(jobs)
+		wg.Wait()
+		close(done)
+	}()
+
+	finished := 0
+	for i := range done {
+		finished++
+		if results[i].err != nil {
+			m.ui.Error("[%d/%d] %s: %v", finished, len(candidates), candidates[i].Branch, results[i].err)
+		} else {
+			m.completed("[%d/%d] %s", finished, len(candidates), candidates[i].Branch)
+		}
+	}
+
+	for i := range results {
+		if results[i].err == nil && results[i].candidate.ShouldDeleteBranch {
+			endBranch := m.timings.Start("delete branch " + results[i].candidate.Branch)
+			results[i].branchErr = m.repo.DeleteBranch(results[i].candidate.Branch, true)
+			endBranch()
+		}
+	}
+
+	return results
 }
 
 // CleanupCandidate represents a worktree that could be cleaned up
@@ -629,12 +1636,22 @@ type CleanupCandidate struct {
 	Reason             string
 	LastActivity       string
 	ShouldDeleteBranch bool
+	Dirty              bool // Has uncommitted changes
+	Trash              bool // Move to the trash instead of removing
+
+	// When LastActivity happened; zero when unknown
+	LastActiveAt time.Time
+
+	// Merge base, divergence and last commit versus the main branch; nil
+	// when unknown, e.g. for detached worktrees
+	Facts *git.BranchFacts
 }
 
 // findCleanupCandidates analyzes worktrees to find cleanup candidates
 func (m *Manager) findCleanupCandidates(worktrees []*types.WorktreeInfo, options CleanupOptions) ([]CleanupCandidate, error) {
 	var candidates []CleanupCandidate
 	currentDir, _ := os.Getwd()
+	prs := m.cleanupPRs(worktrees)
 
 	for _, wt := range worktrees {
 		// Skip main repository
@@ -650,7 +1667,7 @@ func (m *Manager) findCleanupCandidates(worktrees []*types.WorktreeInfo, options
 		// Check if path still exists
 		if !pathExists(wt.Path) {
 			candidates = append(candidates, CleanupCandidate{
-				Branch:             wt.Branch,
+				Branch:             wt.DisplayBranch(),
 				Path:               wt.Path,
 				Reason:             "Path no longer exists",
 				LastActivity:       "N/A",
@@ -659,8 +1676,15 @@ func (m *Manager) findCleanupCandidates(worktrees []*types.WorktreeInfo, options
 			continue
 		}
 
-		// Check if branch is merged (this would need git operations)
-		if options.MergedOnly || !options.MergedOnly {
+		// PR worktrees whose pull request has been merged or closed
+		if candidate, ok := m.prCleanupCandidate(wt, prs[wt.Path], options); ok {
+			candidates = append(candidates, candidate)
+			continue
+		}
+
+		// Check if branch is merged (this would need git operations); a
+		// detached worktree has no branch to check
+		if wt.Branch != "" {
 			// For now, we'll implement a basic check
 			// In a full implementation, this would check git log to see if branch is merged
 			isMerged, err := m.isBranchMerged(wt.Branch)
@@ -676,16 +1700,15 @@ func (m *Manager) findCleanupCandidates(worktrees []*types.WorktreeInfo, options
 			}
 		}
 
-		// Check age if specified
+		// Check age if specified: last commit or uncommitted edit
 		if options.OlderThan != "" {
-			// Parse duration and check file modification time
-			// This is a simplified implementation
-			if isOlderThan, _ := m.isWorktreeOlderThan(wt.Path, options.OlderThan); isOlderThan {
+			if isOlderThan, last, _ := m.isWorktreeOlderThan(wt.Path, options.OlderThan); isOlderThan {
 				candidates = append(candidates, CleanupCandidate{
-					Branch:             wt.Branch,
+					Branch:             wt.DisplayBranch(),
 					Path:               wt.Path,
 					Reason:             fmt.Sprintf("Inactive for more than %s", options.OlderThan),
-					LastActivity:       "N/A", // Would show actual date
+					LastActivity:       m.ui.Time(last),
+					LastActiveAt:       last,
 					ShouldDeleteBranch: false,
 				})
 			}
@@ -695,6 +1718,73 @@ func (m *Manager) findCleanupCandidates(worktrees []*types.WorktreeInfo, options
 	return candidates, nil
 }
 
+// cleanupPRs looks up on GitHub, concurrently, the pull request of every PR
+// worktree, keyed by worktree path. Worktrees are recognized by their PR
+// metadata or naming; lookups that fail are warned about and left out, and
+// nothing is looked up without a GitHub client.
+func (m *Manager) cleanupPRs(worktrees []*types.WorktreeInfo) map[string]*github.PRInfo {
+	if m.github == nil {
+		return nil
+	}
+
+	pm := &PRManager{Manager: m}
+	var paths []string
+	var numbers []int
+	for _, wt := range worktrees {
+		if wt.IsMainRepo || !pathExists(wt.Path) {
+			continue
+		}
+		prNumber := 0
+		if metadata, err := pm.loadPRMetadata(wt.Path); err == nil && metadata.Number > 0 {
+			prNumber = metadata.Number
+		} else {
+			prNumber = pm.extractPRNumber(wt.Path, m.repo.GetRepoName())
+		}
+		if prNumber > 0 {
+			paths = append(paths, wt.Path)
+			numbers = append(numbers, prNumber)
+		}
+	}
+
+	infos := make([]*github.PRInfo, len(numbers))
+	errs := make([]error, len(numbers))
+	forEachConcurrently(len(numbers), m.githubWorkers(), func(i int) {
+		infos[i], errs[i] = m.github.GetPR(numbers[i])
+	})
+
+	prs := make(map[string]*github.PRInfo, len(numbers))
+	for i, prNumber := range numbers {
+		if errs[i] != nil {
+			m.warn("Could not check PR #%d: %v", prNumber, errs[i])
+			continue
+		}
+		prs[paths[i]] = infos[i]
+	}
+	return prs
+}
+
+// prCleanupCandidate reports a PR worktree whose pull request, looked up by
+// cleanupPRs, is merged, or closed unless only merged branches are wanted
+func (m *Manager) prCleanupCandidate(wt *types.WorktreeInfo, prInfo *github.PRInfo, options CleanupOptions) (CleanupCandidate, bool) {
+	if prInfo == nil {
+		return CleanupCandidate{}, false
+	}
+
+	state := strings.ToLower(prInfo.State)
+	if state != "merged" && (state != "closed" || options.MergedOnly) {
+		return CleanupCandidate{}, false
+	}
+
+	return CleanupCandidate{
+		Branch:             wt.Branch,
+		Path:               wt.Path,
+		Reason:             fmt.Sprintf("PR #%d %s", prInfo.Number, state),
+		LastActivity:       m.ui.Time(prInfo.UpdatedAt),
+		LastActiveAt:       prInfo.UpdatedAt,
+		ShouldDeleteBranch: false, // PR branches are left for gh to manage, as in pr clean
+	}, true
+}
+
 // isBranchMerged checks if a branch has been merged into main/master
 func (m *Manager) isBranchMerged(branch string) (bool, error) {
 	// This is a placeholder implementation
@@ -703,15 +1793,65 @@ func (m *Manager) isBranchMerged(branch string) (bool, error) {
 	return false, nil
 }
 
-// isWorktreeOlderThan checks if a worktree is older than the specified duration
-func (m *Manager) isWorktreeOlderThan(path, duration string) (bool, error) {
-	// This is a placeholder implementation
-	// In reality, this would parse the duration and check file/git timestamps
-	return false, nil
+// isWorktreeOlderThan reports whether a worktree has seen no activity for
+// longer than duration (e.g. "30d", "2w"), and when it last did
+func (m *Manager) isWorktreeOlderThan(path, duration string) (bool, time.Time, error) {
+	age, err := types.ParseDuration(duration)
+	if err != nil {
+		return false, time.Time{}, types.NewValidationError("older-than", err.Error(), nil)
+	}
+	last := m.worktreeLastActivity(path)
+	if last.IsZero() {
+		return false, last, nil
+	}
+	return time.Since(last) > age, last, nil
+}
+
+// worktreeLastActivity returns when a worktree was last worked on: the latest
+// of its creation, its last commit and the newest modification of a file
+// with uncommitted changes, so a worktree just made for an old branch isn't
+// stale. It is zero when none is known.
+func (m *Manager) worktreeLastActivity(path string) time.Time {
+	last := createdAt(path)
+	if commit, err := m.repo.GetLastCommit(path); err == nil && commit != nil && commit.Time.After(last) {
+		last = commit.Time
+	}
+	changed, _ := m.repo.ChangedPaths(path)
+	for _, rel := range changed {
+		if info, err := os.Lstat(filepath.Join(path, rel)); err == nil && info.ModTime().After(last) {
+			last = info.ModTime()
+		}
+	}
+	return last
 }
 
 // helper methods
 
+// branchNote returns the first line of a branch's description, if any
+func (m *Manager) branchNote(branch string) string {
+	if branch == "" {
+		return ""
+	}
+	description, err := m.repo.GetConfigValue("branch." + branch + ".description")
+	if err != nil || description == "" {
+		return ""
+	}
+	note, _, _ := strings.Cut(description, "\n")
+	return strings.TrimSpace(note)
+}
+
+// checkNoteReplace refuses a --note that would overwrite a different
+// description the branch already has, unless ReplaceNote is set
+func checkNoteReplace(branch, existing string, options CreateOptions) error {
+	existing = strings.TrimSpace(existing)
+	if existing == "" || existing == options.Note || options.ReplaceNote {
+		return nil
+	}
+	first, _, _ := strings.Cut(existing, "\n")
+	return types.NewValidationError("create-options",
+		fmt.Sprintf("branch '%s' already has a description (%q); use --replace-note to replace it", branch, strings.TrimSpace(first)), nil)
+}
+
 func (m *Manager) generateWorktreePath(branchName string) (string, error) {
 	repoRoot, err := m.repo.GetRepoRoot()
 	if err != nil {
@@ -719,18 +1859,53 @@ func (m *Manager) generateWorktreePath(branchName string) (string, error) {
 	}
 
 	parentDir := filepath.Dir(repoRoot)
-	repoName := m.repo.GetRepoName()
+	dirName, err := m.worktreeDirName(branchName)
+	if err != nil {
+		return "", err
+	}
+	path, err := m.fitWorktreePath(runtime.GOOS, parentDir, dirName)
+	if err != nil {
+		return "", err
+	}
+	return m.avoidCaseCollision(parentDir, path, branchName), nil
+}
 
-	// Apply worktree pattern from project config
+// worktreeDirName applies the project's worktree pattern to a branch name
+func (m *Manager) worktreeDirName(branchName string) (string, error) {
+	// Apply worktree pattern from project config (the loader fills in the
+	// default, and 'wtree config init' used to write {repo}-{branch}, so
+	// treat both as unset)
 	pattern := m.projectConfig.WorktreePattern
-	if pattern == "" {
-		pattern = "{repo}-{branch}"
+	if pattern == "" || pattern == types.DefaultWorktreePattern || pattern == "{repo}-{branch}" {
+		pattern = m.defaultWorktreePattern()
 	}
 
-	dirName := strings.ReplaceAll(pattern, "{repo}", repoName)
-	dirName = strings.ReplaceAll(dirName, "{branch}", branchName)
+	return expandTemplate("worktree_pattern", pattern, TemplateData{
+		Repo:       m.repo.GetRepoName(),
+		Branch:     branchName,
+		BranchSlug: m.branchSlug(branchName),
+		User:       currentUser(),
+		Date:       time.Now().Format("2006-01-02"),
+	})
+}
+
+// defaultWorktreePattern returns the pattern used when the project sets none;
+// with multi_user it includes {user} so users sharing a clone don't collide
+func (m *Manager) defaultWorktreePattern() string {
+	if m.multiUser() {
+		return "{repo}-{user}-{branch_slug}"
+	}
+	return types.DefaultWorktreePattern
+}
 
-	return filepath.Join(parentDir, dirName), nil
+// branchSlug makes a branch name a directory name with the project's
+// branch_slug settings
+func (m *Manager) branchSlug(branch string) string {
+	var config types.BranchSlugConfig
+	if m.projectConfig != nil {
+		config = m.projectConfig.BranchSlug
+	}
+	return branchSlug(branch, config)
 }
 
 func (m *Manager) resolveWorktree(identifier string) (*types.WorktreeInfo, error) {
@@ -746,6 +1921,13 @@ func (m *Manager) resolveWorktree(identifier string) (*types.WorktreeInfo, error
 		}
 	}
 
+	// Then the branch's slug, e.g. feature-login for feature/login
+	for _, wt := range worktrees {
+		if wt.Branch != "" && m.branchSlug(wt.Branch) == identifier {
+			return wt, nil
+		}
+	}
+
 	// Try path match
 	for _, wt := range worktrees {
 		if wt.Path == identifier || filepath.Base(wt.Path) == identifier {
@@ -765,6 +1947,7 @@ func (m *Manager) buildHookContext(event types.HookEvent, branch, worktreePath s
 		Branch:       branch,
 		RepoPath:     repoRoot,
 		WorktreePath: worktreePath,
+		ConfigSource: m.configSource,
 		Environment:  make(map[string]string),
 	}
 }
@@ -777,32 +1960,118 @@ func (m *Manager) executeHooks(event types.HookEvent, ctx types.HookContext) err
 	timeout := m.configMgr.ResolveTimeout(m.globalConfig, m.projectConfig)
 	allowFailure := m.configMgr.ResolveAllowFailure(m.globalConfig, m.projectConfig)
 
+	// Hooks get a token for 'wtree internal-lock --held-by-hook', valid while
+	// this lock is held
+	if m.lockManager != nil && !m.readOnly && ctx.Environment != nil {
+		if hooksLock, err := m.lockManager.AcquireLock(LockTypeHooks, ctx.WorktreePath, m.getOperationTimeout()); err == nil {
+			defer func() { _ = m.lockManager.ReleaseLock(hooksLock) }()
+			ctx.Environment[LockTokenEnv] = m.lockManager.Token()
+		}
+	}
+
 	runner := NewHookRunner(m.projectConfig, timeout, m.globalConfig.UI.Verbose, allowFailure)
+	m.hookChecks(runner.executor, ctx.WorktreePath)
+	m.hookOutput(runner.executor)
+	if m.timings != nil {
+		runner.OnHookDone(func(cmd string, took time.Duration) {
+			m.timings.Record(fmt.Sprintf("%s hook: %s", event, cmd), took)
+		})
+	}
 	return runner.RunHooks(event, ctx)
 }
 
-func (m *Manager) handleFileOperations(worktreePath string) error {
+// handleFileOperations applies copy_files and link_files to ctx.WorktreePath and
+// sets WTREE_SETUP_MANIFEST in ctx.Environment to the manifest it writes.
+// skipCopy and skipLink leave the respective files alone; what an earlier
+// setup recorded for them stays in the manifest.
+func (m *Manager) handleFileOperations(ctx types.HookContext, skipCopy, skipLink bool) error {
+	worktreePath := ctx.WorktreePath
 	repoRoot, err := m.repo.GetRepoRoot()
 	if err != nil {
 		return err
 	}
 
+	m.fileManager.ResetStats()
+	m.fileManager.SetConflictPolicy(m.onConflict, m.askFileConflict)
+	m.prepareNetworkFilesystem(repoRoot, worktreePath)
+	var carried []FileOperation
+	if manifest, err := loadSetupManifest(worktreePath); err == nil && manifest != nil {
+		m.fileManager.SetPreviousOperations(manifest.Operations)
+		for _, op := range manifest.Operations {
+			if (op.Action == "copy" && skipCopy) || (op.Action == "link" && skipLink) {
+				carried = append(carried, op)
+			}
+		}
+	}
+	defer func() {
+		if stats := m.fileManager.Stats(); stats != (FileStats{}) {
+			m.ui.Info("Files: %s", stats)
+		}
+	}()
+
+	// Object entries apply only when their when: condition holds
+	env := conditionEnv(ctx, runtime.GOOS)
+	copyPatterns, copyMappings := types.SplitFileEntries(m.projectConfig.CopyEntries, env)
+	copyPatterns = append(append([]string{}, m.projectConfig.CopyFiles...), copyPatterns...)
+	linkPatterns, linkMappings := types.SplitFileEntries(m.projectConfig.LinkEntries, env)
+	linkPatterns = append(append([]string{}, m.projectConfig.LinkFiles...), linkPatterns...)
+
+	if skipCopy && (len(copyPatterns) > 0 || len(copyMappings) > 0) {
+		m.ui.Info("Skipping copy_files (--skip-copy)")
+		copyPatterns, copyMappings = nil, nil
+	}
+	if skipLink && (len(linkPatterns) > 0 || len(linkMappings) > 0) {
+		m.ui.Info("Skipping link_files (--skip-link)")
+		linkPatterns, linkMappings = nil, nil
+	}
+
+	if len(linkPatterns) > 0 || len(linkMappings) > 0 {
+		copyLinks, err := m.copyLinksAcrossDevices(repoRoot, worktreePath)
+		if err != nil {
+			return err
+		}
+		if copyLinks {
+			copyPatterns = append(copyPatterns, linkPatterns...)
+			copyMappings = append(copyMappings, linkMappings...)
+			linkPatterns, linkMappings = nil, nil
+		}
+	}
+
 	// Copy files
-	if len(m.projectConfig.CopyFiles) > 0 {
-		m.ui.Progress("Copying files...")
-		if err := m.fileManager.CopyFiles(m.projectConfig.CopyFiles, repoRoot, worktreePath, m.projectConfig.IgnoreFiles); err != nil {
+	if len(copyPatterns) > 0 || len(copyMappings) > 0 {
+		m.progress("Copying files...")
+		if err := m.fileManager.CopyFiles(copyPatterns, repoRoot, worktreePath, m.projectConfig.IgnoreFiles); err != nil {
+			return fmt.Errorf("copy files failed: %w", err)
+		}
+		if err := m.fileManager.CopyMappings(copyMappings, repoRoot, worktreePath); err != nil {
 			return fmt.Errorf("copy files failed: %w", err)
 		}
 	}
 
 	// Link files
-	if len(m.projectConfig.LinkFiles) > 0 {
-		m.ui.Progress("Creating file links...")
-		if err := m.fileManager.LinkFiles(m.projectConfig.LinkFiles, repoRoot, worktreePath, m.projectConfig.IgnoreFiles); err != nil {
+	if len(linkPatterns) > 0 || len(linkMappings) > 0 {
+		m.progress("Creating file links...")
+		if err := m.fileManager.LinkFiles(linkPatterns, repoRoot, worktreePath, m.projectConfig.IgnoreFiles); err != nil {
+			return fmt.Errorf("link files failed: %w", err)
+		}
+		if err := m.fileManager.LinkMappings(linkMappings, repoRoot, worktreePath); err != nil {
 			return fmt.Errorf("link files failed: %w", err)
 		}
 	}
 
+	// Record what setup did for post_create hooks and later commands
+	manifestPath, err := writeSetupManifest(SetupManifest{
+		Branch:     ctx.Branch,
+		Worktree:   worktreePath,
+		CreatedAt:  time.Now(),
+		Operations: append(carried, m.fileManager.Operations()...),
+	})
+	if err != nil {
+		m.warn("Failed to write setup manifest: %v", err)
+	} else if ctx.Environment != nil {
+		ctx.Environment["WTREE_SETUP_MANIFEST"] = manifestPath
+	}
+
 	return nil
 }
 
@@ -815,11 +2084,16 @@ func (m *Manager) openInEditor(path string) error {
 	return m.openInSpecificEditor(path, editor)
 }
 
-// executeEditorCommand executes the editor command
+// executeEditorCommand executes the editor command, or prints it with
+// --print-commands. Launches are recorded in the audit log.
 func (m *Manager) executeEditorCommand(cmdArgs []string) error {
 	if len(cmdArgs) == 0 {
 		return fmt.Errorf("no editor command provided")
 	}
+	if m.printCommands {
+		fmt.Fprintln(m.ui.Output(), commandLine(cmdArgs))
+		return nil
+	}
 
 	cmd := exec.Command(cmdArgs[0], cmdArgs[1:]...)
 
@@ -832,16 +2106,26 @@ func (m *Manager) executeEditorCommand(cmdArgs []string) error {
 		"emacs": true,
 	}
 
+	entry := auditEntry{Event: "launch", Command: cmdArgs}
+	var err error
 	if terminalEditors[cmdArgs[0]] {
 		// For terminal editors, run in foreground
 		cmd.Stdin = os.Stdin
-		cmd.Stdout = os.Stdout
+		cmd.Stdout = m.ui.Output()
 		cmd.Stderr = os.Stderr
-		return cmd.Run()
+		err = cmd.Run()
 	} else {
 		// For GUI editors, run in background
-		return cmd.Start()
+		err = cmd.Start()
+		if err == nil {
+			entry.PID = cmd.Process.Pid
+		}
+	}
+	if err != nil {
+		entry.Error = err.Error()
 	}
+	m.audit(entry)
+	return err
 }
 
 func (m *Manager) validateCreateOptions(branchName string, options CreateOptions) error {
@@ -849,10 +2133,24 @@ func (m *Manager) validateCreateOptions(branchName string, options CreateOptions
 		return types.NewValidationError("create-options", "branch name is required", nil)
 	}
 
-	if strings.ContainsAny(branchName, "/\\:*?\"<>|") {
+	if strings.ContainsAny(branchName, "\\:*?\"<>|") {
 		return types.NewValidationError("create-options", "branch name contains invalid characters", nil)
 	}
 
+	if options.Track != "" && options.PushDefault {
+		return types.NewValidationError("create-options",
+			"--track and --push-default both set the upstream; use one", nil)
+	}
+
+	// A remote branch's description is checked on the host
+	if options.Note != "" && options.Host == "" {
+		if existing, err := m.repo.GetConfigValue("branch." + branchName + ".description"); err == nil {
+			if err := checkNoteReplace(branchName, existing, options); err != nil {
+				return err
+			}
+		}
+	}
+
 	return nil
 }
 
@@ -870,6 +2168,20 @@ func (m *Manager) validateMergeOptions(sourceBranch string, options MergeOptions
 	return nil
 }
 
+// pathWithin reports whether path is dir or inside it, comparing
+// symlink-resolved paths so /tmp and /private/tmp style aliases match
+func pathWithin(path, dir string) bool {
+	canonicalDir, err := canonicalPath(dir)
+	if err != nil {
+		return false
+	}
+	canonical, err := canonicalPath(path)
+	if err != nil {
+		return false
+	}
+	return isWithinDir(canonical, canonicalDir)
+}
+
 func pathExists(path string) bool {
 	_, err := os.Stat(path)
 	return err == nil
@@ -877,7 +2189,7 @@ func pathExists(path string) bool {
 
 // atomicPathPreparation atomically checks and prepares the worktree path
 // This fixes the TOCTOU race condition by performing check and creation atomically
-func (m *Manager) atomicPathPreparation(worktreePath string, force bool) error {
+func (m *Manager) atomicPathPreparation(worktreePath string, overwrite bool) error {
 	// Try to create the parent directory first
 	parentDir := filepath.Dir(worktreePath)
 	if err := os.MkdirAll(parentDir, 0755); err != nil {
@@ -898,13 +2210,13 @@ func (m *Manager) atomicPathPreparation(worktreePath string, force bool) error {
 	}
 
 	// Directory already exists
-	if !force {
+	if !overwrite {
 		return types.NewFileSystemError("create-worktree", worktreePath,
-			fmt.Sprintf("worktree path already exists: %s", worktreePath), nil)
+			fmt.Sprintf("worktree path already exists: %s; remove it or use --overwrite-path", worktreePath), nil)
 	}
 
-	// Force flag is set, remove existing path and try again
-	m.ui.Warning("Removing existing path: %s", worktreePath)
+	// Overwriting, so remove the existing path and try again
+	m.warn("Removing existing path: %s", worktreePath)
 	if err := os.RemoveAll(worktreePath); err != nil {
 		return fmt.Errorf("failed to remove existing path: %w", err)
 	}
@@ -975,43 +2287,37 @@ func (m *Manager) Interactive(options InteractiveOptions) error {
 	}
 
 	if len(targetBranches) == 0 {
-		m.ui.Warning("No branches available for %s mode", mode)
+		m.warn("No branches available for %s mode", mode)
 		return nil
 	}
 
-	// Simple interactive selection (placeholder for fuzzy-finding)
-	m.ui.Info("\nAvailable branches:")
-	for i, branch := range targetBranches {
-		status := ""
-		if existingBranches[branch] {
-			status = " [has worktree]"
-		}
-		m.ui.Info("  %d. %s%s", i+1, branch, status)
-	}
-
-	// For now, implement basic selection - in a real implementation,
-	// we would use a library like github.com/manifoldco/promptui or
-	// github.com/AlecAivazis/survey for fuzzy finding
-	m.ui.Info("\nEnter the number of the branch you want to select (or press Enter to cancel):")
-
-	var selection int
-	if _, err := fmt.Scanln(&selection); err != nil {
+	// Simple interactive selection (placeholder for fuzzy-finding). In a real
+	// implementation we would use a library like github.com/manifoldco/promptui
+	// or github.com/AlecAivazis/survey for fuzzy finding.
+	selectedBranches := m.selectBranches(targetBranches, existingBranches)
+	if len(selectedBranches) == 0 {
 		m.ui.Info("Selection cancelled")
 		return nil
 	}
-
-	if selection < 1 || selection > len(targetBranches) {
-		return fmt.Errorf("invalid selection: %d", selection)
+	if len(selectedBranches) > 1 && (mode == "SWITCH" || mode == "BROWSE") {
+		return types.NewValidationError("interactive",
+			fmt.Sprintf("%s mode takes a single selection, got %d", strings.ToLower(mode), len(selectedBranches)), nil)
 	}
 
-	selectedBranch := targetBranches[selection-1]
-	m.ui.Success("Selected: %s", selectedBranch)
+	selectedBranch := selectedBranches[0]
+	m.completed("Selected: %s", strings.Join(selectedBranches, ", "))
 
 	// Execute the appropriate action based on mode
 	switch mode {
 	case "CREATE":
 		if options.DryRun {
-			m.ui.Info("[DRY RUN] Would create worktree for branch: %s", selectedBranch)
+			for _, branch := range selectedBranches {
+				m.ui.Info("[DRY RUN] Would create worktree for branch: %s", branch)
+			}
+			return nil
+		}
+		if len(selectedBranches) > 1 {
+			m.createBatch(selectedBranches)
 			return nil
 		}
 		createOpts := CreateOptions{
@@ -1022,7 +2328,13 @@ func (m *Manager) Interactive(options InteractiveOptions) error {
 
 	case "CLEANUP":
 		if options.DryRun {
-			m.ui.Info("[DRY RUN] Would cleanup worktree for branch: %s", selectedBranch)
+			for _, branch := range selectedBranches {
+				m.ui.Info("[DRY RUN] Would cleanup worktree for branch: %s", branch)
+			}
+			return nil
+		}
+		if len(selectedBranches) > 1 {
+			m.cleanupBatch(selectedBranches, worktrees)
 			return nil
 		}
 		deleteOpts := DeleteOptions{
@@ -1100,18 +2412,20 @@ func (m *Manager) OpenInEditors(identifier string, options EditorsOptions) error
 	// Open each editor
 	for _, editor := range editorsToOpen {
 		if err := m.openInSpecificEditor(worktreePath, editor); err != nil {
-			m.ui.Warning("Failed to open in %s: %v", editor, err)
+			m.warn("Failed to open in %s: %v", editor, err)
 		}
 	}
 
 	// Open terminal if requested
 	if options.OpenTerminal {
 		if err := m.openTerminal(worktreePath); err != nil {
-			m.ui.Warning("Failed to open terminal: %v", err)
+			m.warn("Failed to open terminal: %v", err)
 		}
 	}
 
-	m.ui.Success("Opened worktree in %d editor(s)", len(editorsToOpen))
+	if !m.printCommands {
+		m.completed("Opened worktree in %d editor(s)", len(editorsToOpen))
+	}
 	return nil
 }
 
@@ -1119,10 +2433,12 @@ func (m *Manager) OpenInEditors(identifier string, options EditorsOptions) error
 func (m *Manager) openInSpecificEditor(path, editor string) error {
 	m.ui.Info("Opening in %s: %s", editor, path)
 
+	if windowedEditors[editor] {
+		return m.executeEditorCommand(m.editorArgs(editor, path))
+	}
+
 	// Map of common editors and their command patterns
 	editorCommands := map[string][]string{
-		"code":     {"code", path},
-		"cursor":   {"cursor", path},
 		"vim":      {"vim", path},
 		"nvim":     {"nvim", path},
 		"nano":     {"nano", path},
@@ -1170,6 +2486,11 @@ func (m *Manager) openTerminal(path string) error {
 
 	for _, terminal := range preferredTerminals {
 		if cmdArgs, exists := terminalCommands[terminal]; exists {
+			// Skip terminals that aren't installed, so --print-commands shows
+			// the one that would really open
+			if _, err := exec.LookPath(cmdArgs[0]); err != nil {
+				continue
+			}
 			if err := m.executeEditorCommand(cmdArgs); err == nil {
 				return nil
 			}
@@ -1184,8 +2505,73 @@ func (m *Manager) GetRepo() git.Repository {
 	return m.repo
 }
 
+// acquireOperationLock takes the lock for an operation, reporting the holder on
+// contention and offering to steal it when enabled. The returned func releases it.
+func (m *Manager) acquireOperationLock(lockType LockType, targetPath string) (func(), error) {
+	if m.readOnly {
+		return nil, types.NewValidationError("acquire-lock",
+			fmt.Sprintf("%s is not allowed in read-only mode", lockType), nil)
+	}
+	if m.lockManager == nil {
+		return func() {}, nil
+	}
+
+	if m.lockWait > 0 {
+		m.progress("Waiting up to %s for %s lock...", m.lockWait, lockType)
+	}
+
+	operationLock, err := m.lockManager.AcquireLock(lockType, targetPath, m.getOperationTimeout())
+	if err != nil {
+		var heldErr *LockHeldError
+		if !errors.As(err, &heldErr) {
+			return nil, fmt.Errorf("failed to acquire operation lock: %w", err)
+		}
+
+		m.reportLockHolder(heldErr)
+		if !m.stealLocks {
+			return nil, fmt.Errorf("failed to acquire operation lock: %w", err)
+		}
+
+		m.warn("Stealing the lock may corrupt an operation that is still running")
+		if confirmErr := m.confirmTyped("Type 'steal' to clear the lock and continue", "steal"); confirmErr != nil {
+			return nil, confirmErr
+		}
+		if stealErr := m.lockManager.StealLock(lockType, targetPath); stealErr != nil {
+			return nil, stealErr
+		}
+		m.warn("Removed lock file: %s", heldErr.LockPath)
+
+		operationLock, err = m.lockManager.AcquireLock(lockType, targetPath, m.getOperationTimeout())
+		if err != nil {
+			return nil, fmt.Errorf("failed to acquire operation lock after stealing: %w", err)
+		}
+	}
+
+	return func() {
+		if releaseErr := m.lockManager.ReleaseLock(operationLock); releaseErr != nil {
+			m.warn("Failed to release operation lock: %v", releaseErr)
+		}
+	}, nil
+}
+
+// reportLockHolder shows who holds a contended lock and how to proceed
+func (m *Manager) reportLockHolder(heldErr *LockHeldError) {
+	if heldErr.Holder != nil {
+		m.warn("Lock is held by %s", heldErr.Holder)
+	} else {
+		m.warn("Lock is held by another wtree process")
+	}
+	m.ui.InfoIndented("Lock file: %s", heldErr.LockPath)
+	if !m.stealLocks {
+		m.ui.InfoIndented("Use --wait <duration> to keep retrying, or --steal to clear the lock")
+	}
+}
+
 // getOperationTimeout returns the timeout for operations
 func (m *Manager) getOperationTimeout() time.Duration {
+	if m.lockWait > 0 {
+		return m.lockWait
+	}
 	if m.globalConfig != nil && m.globalConfig.Performance.OperationTimeout > 0 {
 		return m.globalConfig.Performance.OperationTimeout
 	}
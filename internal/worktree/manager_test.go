@@ -0,0 +1,435 @@
+package worktree
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/awhite/wtree/internal/git"
+	"github.com/awhite/wtree/internal/github"
+	"github.com/awhite/wtree/internal/ui"
+	"github.com/awhite/wtree/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPathWithin(t *testing.T) {
+	root := t.TempDir()
+	worktree := filepath.Join(root, "repo-feature")
+	require.NoError(t, os.MkdirAll(filepath.Join(worktree, "src"), 0755))
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "repo-feature-2"), 0755))
+
+	assert.True(t, pathWithin(worktree, worktree))
+	assert.True(t, pathWithin(filepath.Join(worktree, "src"), worktree))
+	assert.False(t, pathWithin(filepath.Join(root, "repo-feature-2"), worktree), "sibling with shared prefix")
+	assert.False(t, pathWithin(root, worktree))
+
+	// A symlinked alias of the worktree still counts as inside it
+	alias := filepath.Join(root, "alias")
+	require.NoError(t, os.Symlink(worktree, alias))
+	assert.True(t, pathWithin(filepath.Join(alias, "src"), worktree))
+}
+
+func TestManager_requestShellCD(t *testing.T) {
+	m := &Manager{}
+
+	t.Setenv("WTREE_CD_FILE", "")
+	assert.False(t, m.requestShellCD("/repo"))
+
+	cdFile := filepath.Join(t.TempDir(), "cd")
+	t.Setenv("WTREE_CD_FILE", cdFile)
+	assert.True(t, m.requestShellCD("/repo"))
+
+	data, err := os.ReadFile(cdFile)
+	require.NoError(t, err)
+	assert.Equal(t, "/repo\n", string(data))
+}
+
+// cleanupMockRepo lists fixed worktrees and records removals safely across goroutines
+type cleanupMockRepo struct {
+	MockGitRepo
+	mu        sync.Mutex
+	worktrees []*types.WorktreeInfo
+	removed   []string
+	failPath  string
+}
+
+func (r *cleanupMockRepo) ListWorktrees() ([]*types.WorktreeInfo, error) {
+	return r.worktrees, nil
+}
+
+func (r *cleanupMockRepo) RemoveWorktree(path string, force bool) error {
+	if path == r.failPath {
+		return errors.New("worktree is locked")
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.removed = append(r.removed, path)
+	return nil
+}
+
+func TestManager_removeCleanupCandidates(t *testing.T) {
+	repo := &cleanupMockRepo{failPath: "/wt/c"}
+	var candidates []CleanupCandidate
+	for _, name := range []string{"a", "b", "c", "d", "e"} {
+		repo.worktrees = append(repo.worktrees, &types.WorktreeInfo{Path: "/wt/" + name, Branch: name})
+		candidates = append(candidates, CleanupCandidate{Branch: name, Path: "/wt/" + name, ShouldDeleteBranch: name == "a"})
+	}
+
+	m := &Manager{
+		repo:          repo,
+		ui:            ui.NewManager(false, false),
+		globalConfig:  types.DefaultWTreeConfig(),
+		projectConfig: types.DefaultProjectConfig(),
+	}
+
+	results := m.removeCleanupCandidates(candidates)
+	require.Len(t, results, 5)
+	for i, result := range results {
+		assert.Equal(t, candidates[i], result.candidate, "results keep candidate order")
+	}
+	assert.Error(t, results[2].err)
+
+	sort.Strings(repo.removed)
+	assert.Equal(t, []string{"/wt/a", "/wt/b", "/wt/d", "/wt/e"}, repo.removed)
+	assert.Equal(t, []string{"a"}, repo.deletedBranches)
+}
+
+func TestManager_readOnlyRefusesLocks(t *testing.T) {
+	m := &Manager{}
+	m.SetReadOnly()
+
+	release, err := m.acquireOperationLock(LockTypeCreate, "/repo-feature")
+	assert.Error(t, err)
+	assert.Nil(t, release)
+}
+
+func TestManager_CreateReusesExistingWorktree(t *testing.T) {
+	repo := &cleanupMockRepo{worktrees: []*types.WorktreeInfo{
+		{Path: "/repo", Branch: "main", IsMainRepo: true},
+		{Path: "/wt/feature", Branch: "feature"},
+	}}
+	m := &Manager{
+		repo:          repo,
+		ui:            ui.NewManager(false, false),
+		globalConfig:  types.DefaultWTreeConfig(),
+		projectConfig: types.DefaultProjectConfig(),
+	}
+
+	assert.Equal(t, "/wt/feature", m.branchWorktree("feature").Path)
+	assert.Nil(t, m.branchWorktree("main"), "the main checkout is not a managed worktree")
+	assert.NoError(t, m.Create("feature", CreateOptions{Refresh: true, DryRun: true}))
+}
+
+// deleteMockRepo serves one linked worktree and records how it and its
+// branch are removed
+type deleteMockRepo struct {
+	MockGitRepo
+	worktree    *types.WorktreeInfo
+	status      git.WorktreeStatus
+	removeForce []bool
+	deleteForce []bool
+	removeErr   error
+	stashes     []string // Messages of stashes not yet popped
+}
+
+func (r *deleteMockRepo) ListWorktrees() ([]*types.WorktreeInfo, error) {
+	return []*types.WorktreeInfo{{Path: "/repo", Branch: "main", IsMainRepo: true}, r.worktree}, nil
+}
+func (r *deleteMockRepo) GetWorktreeStatus(path string) (*git.WorktreeStatus, error) {
+	status := r.status
+	return &status, nil
+}
+func (r *deleteMockRepo) RemoveWorktree(path string, force bool) error {
+	r.removeForce = append(r.removeForce, force)
+	return r.removeErr
+}
+func (r *deleteMockRepo) Stash(path, message string) error {
+	r.stashes = append(r.stashes, message)
+	return nil
+}
+func (r *deleteMockRepo) FindStash(message string) (string, error) {
+	for _, stash := range r.stashes {
+		if stash == message {
+			return stash, nil
+		}
+	}
+	return "", nil
+}
+func (r *deleteMockRepo) StashPop(path, stash string) error {
+	for i, s := range r.stashes {
+		if s == stash {
+			r.stashes = append(r.stashes[:i], r.stashes[i+1:]...)
+		}
+	}
+	return nil
+}
+func (r *deleteMockRepo) DeleteBranch(branch string, force bool) error {
+	r.deleteForce = append(r.deleteForce, force)
+	return nil
+}
+
+func newDeleteManager(t *testing.T) (*Manager, *deleteMockRepo) {
+	repo := &deleteMockRepo{
+		worktree: &types.WorktreeInfo{Path: t.TempDir(), Branch: "feature"},
+		status:   git.WorktreeStatus{IsClean: true},
+	}
+	m := &Manager{
+		repo:          repo,
+		ui:            ui.NewManager(false, false),
+		globalConfig:  types.DefaultWTreeConfig(),
+		projectConfig: types.DefaultProjectConfig(),
+	}
+	return m, repo
+}
+
+func TestManager_DeleteYesSkipsConfirmation(t *testing.T) {
+	m, repo := newDeleteManager(t)
+	m.Subscribe(&recordingObserver{decline: true})
+
+	assert.Error(t, m.Delete("feature", DeleteOptions{Permanent: true}))
+	assert.Empty(t, repo.removeForce)
+
+	require.NoError(t, m.Delete("feature", DeleteOptions{Yes: true, Permanent: true}))
+	assert.Equal(t, []bool{false}, repo.removeForce, "--yes doesn't force the removal")
+}
+
+func TestManager_DeleteYesAllowsCurrentWorktree(t *testing.T) {
+	m, repo := newDeleteManager(t)
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(repo.worktree.Path))
+	t.Cleanup(func() { _ = os.Chdir(wd) })
+	t.Setenv("WTREE_CD_FILE", "")
+
+	err = m.Delete("feature", DeleteOptions{IgnoreDirty: true, Permanent: true})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "currently in")
+	assert.Empty(t, repo.removeForce)
+
+	require.NoError(t, m.Delete("feature", DeleteOptions{Yes: true, Permanent: true}))
+	assert.Len(t, repo.removeForce, 1)
+}
+
+func TestManager_DeleteIgnoreDirtyForcesRemoval(t *testing.T) {
+	m, repo := newDeleteManager(t)
+	repo.status = git.WorktreeStatus{IsClean: false, ChangedFiles: 1}
+
+	err := m.Delete("feature", DeleteOptions{Yes: true, Permanent: true})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "uncommitted changes")
+	assert.Empty(t, repo.removeForce)
+
+	require.NoError(t, m.Delete("feature", DeleteOptions{Yes: true, IgnoreDirty: true, Permanent: true}))
+	assert.Equal(t, []bool{true}, repo.removeForce)
+
+	repo.status = git.WorktreeStatus{IsClean: true, Operation: &git.OperationState{}}
+	err = m.Delete("feature", DeleteOptions{Yes: true, Permanent: true})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "operation in progress")
+}
+
+func TestManager_DeleteForceBranchDelete(t *testing.T) {
+	m, repo := newDeleteManager(t)
+
+	options := DeleteOptions{Yes: true, IgnoreDirty: true, DeleteBranch: true, AllowProtected: true, Permanent: true}
+	require.NoError(t, m.Delete("feature", options))
+	options.ForceBranchDelete = true
+	require.NoError(t, m.Delete("feature", options))
+
+	assert.Equal(t, []bool{false, true}, repo.deleteForce, "only --force-branch-delete forces the branch deletion")
+}
+
+func TestManager_atomicPathPreparationOverwritePath(t *testing.T) {
+	m := &Manager{repo: &MockGitRepo{}, rollback: NewRollbackManager(&MockGitRepo{})}
+	path := filepath.Join(t.TempDir(), "repo-feature")
+	require.NoError(t, os.MkdirAll(path, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(path, "stale"), []byte("x"), 0644))
+
+	err := m.atomicPathPreparation(path, false)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "--overwrite-path")
+	assert.FileExists(t, filepath.Join(path, "stale"))
+
+	require.NoError(t, m.atomicPathPreparation(path, true))
+	assert.NoFileExists(t, filepath.Join(path, "stale"))
+}
+
+// mergeMockRepo answers git config from a map and records merges
+type mergeMockRepo struct {
+	MockGitRepo
+	dirty  bool
+	config map[string]string
+	merges []git.MergeOptions
+}
+
+func (r *mergeMockRepo) IsClean() (bool, error) { return !r.dirty, nil }
+func (r *mergeMockRepo) GetConfigValue(key string) (string, error) {
+	if value, ok := r.config[key]; ok {
+		return value, nil
+	}
+	return "", errors.New("not set")
+}
+func (r *mergeMockRepo) Merge(branch string, options git.MergeOptions) error {
+	r.merges = append(r.merges, options)
+	return nil
+}
+
+func TestManager_MergeIgnoreDirty(t *testing.T) {
+	repo := &mergeMockRepo{dirty: true}
+	m := &Manager{
+		repo:          repo,
+		ui:            ui.NewManager(false, false),
+		globalConfig:  types.DefaultWTreeConfig(),
+		projectConfig: types.DefaultProjectConfig(),
+	}
+
+	err := m.Merge("feature", MergeOptions{SkipChecks: true})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "must be clean")
+	assert.Empty(t, repo.merges)
+
+	require.NoError(t, m.Merge("feature", MergeOptions{SkipChecks: true, IgnoreDirty: true}))
+	assert.Len(t, repo.merges, 1)
+}
+
+func TestManager_resolveGitMergeOptions(t *testing.T) {
+	repo := &mergeMockRepo{}
+	m := &Manager{repo: repo}
+
+	assert.Equal(t, git.MergeOptions{Message: "msg"}, m.resolveGitMergeOptions(MergeOptions{Message: "msg"}))
+
+	// git's boolean spellings turn signing on from config
+	repo.config = map[string]string{"wtree.signoff": "yes", "wtree.gpgSign": "on", "user.signingKey": "ABCD1234"}
+	assert.Equal(t, git.MergeOptions{Signoff: true, GPGSign: true, GPGKeyID: "ABCD1234"},
+		m.resolveGitMergeOptions(MergeOptions{}))
+
+	repo.config = map[string]string{"wtree.signoff": "off", "wtree.gpgSign": "0", "user.signingKey": "ABCD1234"}
+	assert.Equal(t, git.MergeOptions{}, m.resolveGitMergeOptions(MergeOptions{}))
+
+	// Flags win over config, and a given key implies signing
+	assert.Equal(t, git.MergeOptions{Signoff: true, GPGSign: true, GPGKeyID: "FFFF"},
+		m.resolveGitMergeOptions(MergeOptions{Signoff: true, GPGKeyID: "FFFF"}))
+	assert.Equal(t, git.MergeOptions{GPGSign: true, GPGKeyID: "ABCD1234"},
+		m.resolveGitMergeOptions(MergeOptions{GPGSign: true}))
+}
+
+func TestManager_MergePassesSigningOptions(t *testing.T) {
+	repo := &mergeMockRepo{config: map[string]string{"wtree.signoff": "true", "wtree.gpgSign": "yes", "user.signingKey": "ABCD1234"}}
+	m := &Manager{
+		repo:          repo,
+		ui:            ui.NewManager(false, false),
+		globalConfig:  types.DefaultWTreeConfig(),
+		projectConfig: types.DefaultProjectConfig(),
+	}
+
+	require.NoError(t, m.Merge("feature", MergeOptions{SkipChecks: true, Message: "Merge feature"}))
+	require.Len(t, repo.merges, 1)
+	assert.Equal(t, git.MergeOptions{Message: "Merge feature", Signoff: true, GPGSign: true, GPGKeyID: "ABCD1234"}, repo.merges[0])
+}
+
+func TestManager_DeleteAutostashAfterPreDeleteHooks(t *testing.T) {
+	m, repo := newDeleteManager(t)
+	repo.status = git.WorktreeStatus{IsClean: false, ChangedFiles: 1}
+	m.projectConfig.Hooks = map[types.HookEvent][]string{types.HookPreDelete: {"exit 1"}}
+
+	err := m.Delete("feature", DeleteOptions{Yes: true, Autostash: true, Permanent: true})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "pre-delete hook failed")
+	assert.Empty(t, repo.stashes, "a failing hook stops the delete before anything is stashed")
+	assert.Empty(t, repo.removeForce)
+
+	// Stashed once the hooks pass, and restored when the removal fails
+	m.projectConfig.Hooks = nil
+	repo.removeErr = errors.New("worktree is locked")
+	err = m.Delete("feature", DeleteOptions{Yes: true, Autostash: true, Permanent: true})
+	require.Error(t, err)
+	assert.Len(t, repo.removeForce, 1)
+	assert.Empty(t, repo.stashes, "the changes are restored")
+
+	repo.removeErr = nil
+	require.NoError(t, m.Delete("feature", DeleteOptions{Yes: true, Autostash: true, Permanent: true}))
+	assert.Equal(t, []string{autostashMessage("feature")}, repo.stashes)
+}
+
+func TestManager_branchNote(t *testing.T) {
+	repo := &mergeMockRepo{config: map[string]string{
+		"branch.feature.description": "  cache layer spike  \nmore detail\n",
+	}}
+	m := &Manager{repo: repo}
+
+	assert.Equal(t, "cache layer spike", m.branchNote("feature"))
+	assert.Empty(t, m.branchNote("other"))
+	assert.Empty(t, m.branchNote(""))
+}
+
+func TestManager_validateCreateOptionsNote(t *testing.T) {
+	repo := &mergeMockRepo{config: map[string]string{"branch.feature.description": "cache layer spike\nmore detail"}}
+	m := &Manager{repo: repo}
+
+	err := m.validateCreateOptions("feature", CreateOptions{Note: "something else"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "cache layer spike")
+	assert.Contains(t, err.Error(), "--replace-note")
+
+	assert.NoError(t, m.validateCreateOptions("feature", CreateOptions{Note: "something else", ReplaceNote: true}))
+	assert.NoError(t, m.validateCreateOptions("feature", CreateOptions{Note: "cache layer spike\nmore detail"}), "the same description")
+	assert.NoError(t, m.validateCreateOptions("feature", CreateOptions{}))
+	assert.NoError(t, m.validateCreateOptions("new", CreateOptions{Note: "fresh"}))
+}
+
+func TestManager_findCleanupCandidatesChecksPRs(t *testing.T) {
+	// A fake gh answers PR #11 merged, #12 closed and #13 open, and fails
+	// for any other PR
+	bin := t.TempDir()
+	script := `#!/bin/sh
+if [ "$1" = "repo" ]; then echo '{"name":"test-repo"}'; exit 0; fi
+case "$3" in
+11) echo '{"number":11,"state":"MERGED"}' ;;
+12) echo '{"number":12,"state":"CLOSED"}' ;;
+13) echo '{"number":13,"state":"OPEN"}' ;;
+*) echo "HTTP 502" >&2; exit 1 ;;
+esac
+`
+	require.NoError(t, os.WriteFile(filepath.Join(bin, "gh"), []byte(script), 0755))
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	root := t.TempDir()
+	var worktrees []*types.WorktreeInfo
+	for _, number := range []string{"11", "12", "13", "14"} {
+		path := filepath.Join(root, "test-repo-pr-"+number)
+		require.NoError(t, os.MkdirAll(path, 0755))
+		worktrees = append(worktrees, &types.WorktreeInfo{Path: path, Branch: "pr-" + number})
+	}
+
+	recorder := &recordingObserver{}
+	m := &Manager{
+		repo:          &MockGitRepo{},
+		ui:            ui.NewManager(false, false),
+		globalConfig:  types.DefaultWTreeConfig(),
+		projectConfig: types.DefaultProjectConfig(),
+	}
+	m.SetGitHubClient(github.NewClient("gh", 0))
+	m.Subscribe(recorder)
+
+	reasons := func(candidates []CleanupCandidate) []string {
+		var out []string
+		for _, candidate := range candidates {
+			out = append(out, candidate.Reason)
+		}
+		return out
+	}
+
+	candidates, err := m.findCleanupCandidates(worktrees, CleanupOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"PR #11 merged", "PR #12 closed"}, reasons(candidates))
+	require.Len(t, recorder.events, 1, "the failed lookup is warned about without --verbose")
+	assert.Contains(t, recorder.events[0], "warning: Could not check PR #14")
+
+	candidates, err = m.findCleanupCandidates(worktrees, CleanupOptions{MergedOnly: true})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"PR #11 merged"}, reasons(candidates))
+}
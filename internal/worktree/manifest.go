@@ -0,0 +1,42 @@
+package worktree
+
+import "time"
+
+// SetupManifest records the file operations performed when a worktree was
+// set up. It is exposed to post_create hooks as WTREE_SETUP_MANIFEST.
+type SetupManifest struct {
+	Branch     string          `json:"branch"`
+	Worktree   string          `json:"worktree"`
+	CreatedAt  time.Time       `json:"created_at"`
+	Operations []FileOperation `json:"operations"`
+}
+
+// writeSetupManifest stores the manifest for a worktree and returns its path
+func writeSetupManifest(manifest SetupManifest) (string, error) {
+	return saveWorktreeMetaJSON("write-setup-manifest", manifest.Worktree, "setup-manifest.json", manifest)
+}
+
+// loadSetupManifest reads a worktree's manifest, returning nil when none was written
+func loadSetupManifest(worktreePath string) (*SetupManifest, error) {
+	var manifest SetupManifest
+	if found, err := loadWorktreeMetaJSON("read-setup-manifest", worktreePath, "setup-manifest.json", &manifest); !found {
+		return nil, err
+	}
+	return &manifest, nil
+}
+
+// Stats summarizes the manifest's operations
+func (sm *SetupManifest) Stats() FileStats {
+	var stats FileStats
+	for _, op := range sm.Operations {
+		switch {
+		case op.Unchanged:
+			stats.Unchanged++
+		case op.Action == "link":
+			stats.Linked++
+		default:
+			stats.Copied++
+		}
+	}
+	return stats
+}
@@ -0,0 +1,51 @@
+package worktree
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetupManifest_RoundTrip(t *testing.T) {
+	tmpDir := t.TempDir()
+	srcDir := filepath.Join(tmpDir, "src")
+	worktree := filepath.Join(tmpDir, "worktree")
+	require.NoError(t, os.MkdirAll(filepath.Join(srcDir, "node_modules"), 0755))
+	require.NoError(t, os.MkdirAll(filepath.Join(worktree, ".git"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(srcDir, ".env"), []byte("A=1"), 0644))
+
+	fm := NewFileManager(false)
+	require.NoError(t, fm.CopyFiles([]string{".env"}, srcDir, worktree, nil))
+	require.NoError(t, fm.LinkFiles([]string{"node_modules"}, srcDir, worktree, nil))
+
+	envInfo, err := os.Stat(filepath.Join(srcDir, ".env"))
+	require.NoError(t, err)
+	assert.Equal(t, []FileOperation{
+		{Action: "copy", Source: filepath.Join(srcDir, ".env"), Destination: filepath.Join(worktree, ".env"), ModTime: envInfo.ModTime().UnixNano()},
+		{Action: "link", Source: filepath.Join(srcDir, "node_modules"), Destination: filepath.Join(worktree, "node_modules")},
+	}, fm.Operations())
+
+	manifest, err := loadSetupManifest(worktree)
+	require.NoError(t, err)
+	assert.Nil(t, manifest)
+
+	path, err := writeSetupManifest(SetupManifest{
+		Branch:     "feature",
+		Worktree:   worktree,
+		CreatedAt:  time.Now(),
+		Operations: fm.Operations(),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(worktree, ".git", "wtree", "setup-manifest.json"), path)
+
+	manifest, err = loadSetupManifest(worktree)
+	require.NoError(t, err)
+	require.NotNil(t, manifest)
+	assert.Equal(t, "feature", manifest.Branch)
+	assert.Equal(t, fm.Operations(), manifest.Operations)
+	assert.Equal(t, FileStats{Copied: 1, Linked: 1}, manifest.Stats())
+}
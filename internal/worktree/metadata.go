@@ -0,0 +1,78 @@
+package worktree
+
+import (
+	"os"
+	"time"
+)
+
+// WorktreeMetadata records where a worktree came from. It is written once,
+// when wtree creates the worktree; worktrees made with plain git have none.
+type WorktreeMetadata struct {
+	CreatedAt time.Time `json:"created_at"`
+	CreatedBy string    `json:"created_by"`
+	Base      string    `json:"base,omitempty"`    // What a new branch was created from; empty for an existing branch
+	Command   string    `json:"command,omitempty"` // The wtree invocation that created it
+}
+
+// recordCreation stores the owner and metadata of a worktree wtree just
+// created; base is what its branch was created from, if wtree created it
+func recordCreation(worktreePath, base string) error {
+	if err := recordOwner(worktreePath); err != nil {
+		return err
+	}
+	metadata := WorktreeMetadata{
+		CreatedAt: time.Now(),
+		CreatedBy: currentUser(),
+		Base:      base,
+		Command:   commandLine(append([]string{"wtree"}, os.Args[1:]...)),
+	}
+	_, err := saveWorktreeMetaJSON("record-metadata", worktreePath, "metadata.json", metadata)
+	return err
+}
+
+// worktreeMetadata returns what was recorded when a worktree was created,
+// or nil when nothing was
+func worktreeMetadata(worktreePath string) *WorktreeMetadata {
+	var metadata WorktreeMetadata
+	if found, _ := loadWorktreeMetaJSON("read-metadata", worktreePath, "metadata.json", &metadata); !found {
+		return nil
+	}
+	return &metadata
+}
+
+// createdAt returns when wtree created a worktree, or zero when unknown
+func createdAt(worktreePath string) time.Time {
+	if metadata := worktreeMetadata(worktreePath); metadata != nil {
+		return metadata.CreatedAt
+	}
+	return time.Time{}
+}
+
+// describeBase names what a new branch is created from: from, or for HEAD
+// the branch (or commit) checked out where wtree runs
+func (m *Manager) describeBase(from string) string {
+	if from != "" && from != "HEAD" {
+		return from
+	}
+	head, err := m.repo.GetHeadState()
+	if err != nil || head == nil {
+		return from
+	}
+	return head.String()
+}
+
+// provenance describes where a worktree came from for status, e.g. "3 days
+// ago by alice from main (wtree create -b feature)"
+func (m *Manager) provenance(metadata *WorktreeMetadata) string {
+	text := m.ui.Time(metadata.CreatedAt)
+	if metadata.CreatedBy != "" {
+		text += " by " + metadata.CreatedBy
+	}
+	if metadata.Base != "" {
+		text += " from " + metadata.Base
+	}
+	if metadata.Command != "" {
+		text += " (" + metadata.Command + ")"
+	}
+	return text
+}
@@ -0,0 +1,40 @@
+package worktree
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecordCreation(t *testing.T) {
+	path := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(path, ".git"), 0755))
+	assert.Nil(t, worktreeMetadata(path))
+	assert.True(t, createdAt(path).IsZero())
+
+	require.NoError(t, recordCreation(path, "main"))
+	metadata := worktreeMetadata(path)
+	require.NotNil(t, metadata)
+	assert.Equal(t, currentUser(), metadata.CreatedBy)
+	assert.Equal(t, "main", metadata.Base)
+	assert.Contains(t, metadata.Command, "wtree")
+	assert.WithinDuration(t, time.Now(), metadata.CreatedAt, time.Minute)
+	assert.Equal(t, currentUser(), worktreeOwner(path))
+}
+
+func TestManager_worktreeLastActivity_Created(t *testing.T) {
+	path := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(path, ".git"), 0755))
+	require.NoError(t, recordCreation(path, "main"))
+
+	// A worktree just made for a branch with old commits isn't stale
+	m := &Manager{repo: &activityMockRepo{commit: time.Now().Add(-60 * 24 * time.Hour)}}
+	older, last, err := m.isWorktreeOlderThan(path, "30d")
+	require.NoError(t, err)
+	assert.False(t, older)
+	assert.WithinDuration(t, time.Now(), last, time.Minute)
+}
@@ -0,0 +1,90 @@
+package worktree
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"github.com/awhite/wtree/internal/git"
+	"github.com/awhite/wtree/pkg/types"
+)
+
+// worktreeMetaFile returns where wtree keeps the named metadata file of a
+// worktree: inside the worktree's private git directory, so it never shows up
+// in the working tree and goes away with the worktree
+func worktreeMetaFile(worktreePath, name string) (string, error) {
+	gitDir, err := git.ResolveGitDir(worktreePath)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(gitDir, "wtree", name), nil
+}
+
+// readWorktreeMeta reads the named metadata file of a worktree; one that was
+// never written is nil without an error
+func readWorktreeMeta(op, worktreePath, name string) ([]byte, error) {
+	path, err := worktreeMetaFile(worktreePath, name)
+	if err != nil {
+		return nil, types.NewFileSystemError(op, worktreePath, "failed to locate worktree git directory", err)
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, types.NewFileSystemError(op, path, "failed to read "+name, err)
+	}
+	return data, nil
+}
+
+// writeWorktreeMeta stores the named metadata file of a worktree and returns
+// its path
+func writeWorktreeMeta(op, worktreePath, name string, data []byte) (string, error) {
+	path, err := worktreeMetaFile(worktreePath, name)
+	if err != nil {
+		return "", types.NewFileSystemError(op, worktreePath, "failed to locate worktree git directory", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", types.NewFileSystemError(op, path, "failed to create metadata directory", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return "", types.NewFileSystemError(op, path, "failed to write "+name, err)
+	}
+	return path, nil
+}
+
+// removeWorktreeMeta deletes the named metadata file of a worktree; one that
+// was never written is not an error
+func removeWorktreeMeta(op, worktreePath, name string) error {
+	path, err := worktreeMetaFile(worktreePath, name)
+	if err != nil {
+		return types.NewFileSystemError(op, worktreePath, "failed to locate worktree git directory", err)
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return types.NewFileSystemError(op, path, "failed to remove "+name, err)
+	}
+	return nil
+}
+
+// loadWorktreeMetaJSON decodes the named JSON metadata file of a worktree
+// into v, reporting whether it was there
+func loadWorktreeMetaJSON(op, worktreePath, name string, v any) (bool, error) {
+	data, err := readWorktreeMeta(op, worktreePath, name)
+	if err != nil || data == nil {
+		return false, err
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return false, types.NewFileSystemError(op, worktreePath, "invalid "+name, err)
+	}
+	return true, nil
+}
+
+// saveWorktreeMetaJSON stores v as the named JSON metadata file of a worktree
+// and returns its path
+func saveWorktreeMetaJSON(op, worktreePath, name string, v any) (string, error) {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return writeWorktreeMeta(op, worktreePath, name, data)
+}
@@ -0,0 +1,53 @@
+package worktree
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWorktreeMetaFile_LinkedWorktree(t *testing.T) {
+	tmpDir := t.TempDir()
+	worktree := filepath.Join(tmpDir, "worktree")
+	gitDir := filepath.Join(tmpDir, "repo", ".git", "worktrees", "feature")
+	require.NoError(t, os.MkdirAll(worktree, 0755))
+	require.NoError(t, os.MkdirAll(gitDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(worktree, ".git"), []byte("gitdir: "+gitDir+"\n"), 0644))
+
+	path, err := worktreeMetaFile(worktree, "tags.json")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(gitDir, "wtree", "tags.json"), path)
+
+	data, err := readWorktreeMeta("read-tags", worktree, "tags.json")
+	require.NoError(t, err, "a file never written is not an error")
+	assert.Nil(t, data)
+
+	_, err = saveWorktreeMetaJSON("write-tags", worktree, "tags.json", []string{"wip"})
+	require.NoError(t, err)
+	var tags []string
+	found, err := loadWorktreeMetaJSON("read-tags", worktree, "tags.json", &tags)
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, []string{"wip"}, tags)
+
+	require.NoError(t, removeWorktreeMeta("write-tags", worktree, "tags.json"))
+	require.NoError(t, removeWorktreeMeta("write-tags", worktree, "tags.json"), "removing twice is not an error")
+	assert.NoFileExists(t, path)
+}
+
+func TestWorktreeMetaFile_Invalid(t *testing.T) {
+	worktree := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(worktree, ".git", "wtree"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(worktree, ".git", "wtree", "notes.json"), []byte("{"), 0644))
+
+	var notes []WorktreeNote
+	found, err := loadWorktreeMetaJSON("read-notes", worktree, "notes.json", &notes)
+	assert.Error(t, err)
+	assert.False(t, found)
+
+	_, err = writeWorktreeMeta("record-owner", filepath.Join(worktree, "missing"), "owner", []byte("alice\n"))
+	assert.Error(t, err, "a directory that isn't a worktree has nowhere to keep metadata")
+}
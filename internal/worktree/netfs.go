@@ -0,0 +1,102 @@
+package worktree
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// batchedCopyBuffer is the buffer copies use on network file systems, where
+// each round trip is expensive
+const batchedCopyBuffer = 1 << 20
+
+// networkFilesystemOf returns the network file system (nfs, smb...) holding
+// path, or its nearest existing parent; empty for a local one or when it
+// can't be told
+func networkFilesystemOf(path string) string {
+	fsType, err := networkFilesystem(existingParent(path))
+	if err != nil {
+		return ""
+	}
+	return fsType
+}
+
+// prepareNetworkFilesystem switches file setup to batched operations when
+// the main checkout or the worktree is on a network mount, and says how to
+// make worktrees there faster
+func (m *Manager) prepareNetworkFilesystem(repoRoot, worktreePath string) {
+	fsType, path := networkFilesystemOf(worktreePath), worktreePath
+	if fsType == "" {
+		fsType, path = networkFilesystemOf(repoRoot), repoRoot
+	}
+	m.fileManager.SetBatched(fsType != "")
+	if fsType == "" {
+		return
+	}
+	m.warn("%s is on a network file system (%s); copying in batches, which is still slower than a local disk", path, fsType)
+	m.ui.InfoIndented("To speed up worktrees here:")
+	m.ui.InfoIndented("  - create them on a local disk with paths.worktree_parent")
+	m.ui.InfoIndented("  - use link_files rather than copy_files for large directories")
+	m.ui.InfoIndented("  - git config checkout.workers 8 && git config core.untrackedCache true")
+}
+
+// SetBatched makes copies suit a network file system: directories new to
+// the destination are copied with one tar pipe instead of file by file, and
+// files are copied with larger buffers and without an fsync each
+func (fm *FileManager) SetBatched(batched bool) {
+	fm.batched = batched
+}
+
+// copyFileContent copies src's content into dst, syncing it unless batched
+func (fm *FileManager) copyFileContent(dst io.Writer, src io.Reader, sync func() error) error {
+	if fm.batched {
+		if _, err := io.CopyBuffer(dst, src, make([]byte, batchedCopyBuffer)); err != nil {
+			return fmt.Errorf("failed to copy file content: %w", err)
+		}
+		return nil
+	}
+	if _, err := io.Copy(dst, src); err != nil {
+		return fmt.Errorf("failed to copy file content: %w", err)
+	}
+	if err := sync(); err != nil {
+		return fmt.Errorf("failed to sync destination file: %w", err)
+	}
+	return nil
+}
+
+// tarCopyDir copies the directory src to dst, which must not exist yet, by
+// piping one tar archive between them. Symlinks are followed, as file by
+// file copies do. It reports false when tar is unavailable.
+func tarCopyDir(src, dst string) (bool, error) {
+	if _, err := exec.LookPath("tar"); err != nil {
+		return false, nil
+	}
+	if err := os.MkdirAll(dst, 0755); err != nil {
+		return true, err
+	}
+
+	pack := exec.Command("tar", "-c", "-h", "-f", "-", "-C", src, ".")
+	unpack := exec.Command("tar", "-x", "-f", "-", "-C", dst)
+	var stderr strings.Builder
+	pack.Stderr = &stderr
+	unpack.Stderr = &stderr
+	pipe, err := pack.StdoutPipe()
+	if err != nil {
+		return true, err
+	}
+	unpack.Stdin = pipe
+
+	if err := unpack.Start(); err != nil {
+		return true, err
+	}
+	packErr := pack.Run()
+	unpackErr := unpack.Wait()
+	if packErr != nil || unpackErr != nil {
+		_ = os.RemoveAll(dst)
+		return true, fmt.Errorf("failed to copy %s with tar: %s", filepath.Base(src), strings.TrimSpace(stderr.String()))
+	}
+	return true, nil
+}
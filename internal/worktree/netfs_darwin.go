@@ -0,0 +1,34 @@
+//go:build darwin
+
+package worktree
+
+import "syscall"
+
+// networkTypes are the macOS file system type names of network mounts
+var networkTypes = map[string]bool{
+	"nfs":    true,
+	"smbfs":  true,
+	"afpfs":  true,
+	"webdav": true,
+	"cifs":   true,
+}
+
+// networkFilesystem returns the name of the network file system holding
+// path, or empty for a local one
+func networkFilesystem(path string) (string, error) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(path, &stat); err != nil {
+		return "", err
+	}
+	var name []byte
+	for _, c := range stat.Fstypename {
+		if c == 0 {
+			break
+		}
+		name = append(name, byte(c))
+	}
+	if networkTypes[string(name)] {
+		return string(name), nil
+	}
+	return "", nil
+}
@@ -0,0 +1,28 @@
+//go:build linux
+
+package worktree
+
+import "syscall"
+
+// networkMagic maps the statfs magic numbers of network file systems to
+// their names
+var networkMagic = map[uint32]string{
+	0x6969:     "nfs",
+	0x517b:     "smb",
+	0xff534d42: "cifs",
+	0xfe534d42: "smb2",
+	0x5346414f: "afs",
+	0x73757245: "coda",
+	0x00c36400: "ceph",
+	0x01021997: "9p",
+}
+
+// networkFilesystem returns the name of the network file system holding
+// path, or empty for a local one
+func networkFilesystem(path string) (string, error) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(path, &stat); err != nil {
+		return "", err
+	}
+	return networkMagic[uint32(stat.Type)], nil
+}
@@ -0,0 +1,8 @@
+//go:build !linux && !darwin && !windows
+
+package worktree
+
+// networkFilesystem can't tell network mounts apart on this platform
+func networkFilesystem(path string) (string, error) {
+	return "", nil
+}
@@ -0,0 +1,57 @@
+package worktree
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileManager_BatchedCopyDir(t *testing.T) {
+	if _, err := exec.LookPath("tar"); err != nil {
+		t.Skip("tar not available")
+	}
+	src := filepath.Join(t.TempDir(), "deps")
+	require.NoError(t, os.MkdirAll(filepath.Join(src, "pkg", "lib"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(src, "pkg", "lib", "index.js"), []byte("module.exports = 1\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(src, "bin"), []byte("#!/bin/sh\n"), 0755))
+
+	fm := NewFileManager(false)
+	fm.SetBatched(true)
+	dst := filepath.Join(t.TempDir(), "deps")
+	require.NoError(t, fm.copyDir(src, dst))
+
+	data, err := os.ReadFile(filepath.Join(dst, "pkg", "lib", "index.js"))
+	require.NoError(t, err)
+	assert.Equal(t, "module.exports = 1\n", string(data))
+	info, err := os.Stat(filepath.Join(dst, "bin"))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0755), info.Mode().Perm())
+
+	// The whole directory is one operation
+	require.Len(t, fm.Operations(), 1)
+	assert.Equal(t, dst, fm.Operations()[0].Destination)
+}
+
+func TestFileManager_BatchedCopyFile(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "a.env")
+	require.NoError(t, os.WriteFile(src, []byte("KEY=1\n"), 0600))
+
+	fm := NewFileManager(false)
+	fm.SetBatched(true)
+	dst := filepath.Join(dir, "out", "a.env")
+	require.NoError(t, fm.copyFile(src, dst))
+	data, err := os.ReadFile(dst)
+	require.NoError(t, err)
+	assert.Equal(t, "KEY=1\n", string(data))
+}
+
+func TestNetworkFilesystemOf_Local(t *testing.T) {
+	// Temporary directories are local; paths that don't exist yet are
+	// checked at their nearest parent
+	assert.Empty(t, networkFilesystemOf(filepath.Join(t.TempDir(), "not", "yet")))
+}
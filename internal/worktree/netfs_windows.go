@@ -0,0 +1,21 @@
+//go:build windows
+
+package worktree
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// networkFilesystem reports UNC paths (\\server\share) as SMB; mapped
+// drive letters aren't detected
+func networkFilesystem(path string) (string, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	if strings.HasPrefix(filepath.VolumeName(abs), `\\`) {
+		return "smb", nil
+	}
+	return "", nil
+}
@@ -0,0 +1,148 @@
+package worktree
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/awhite/wtree/pkg/types"
+)
+
+// recentNotes is how many notes status shows per worktree
+const recentNotes = 3
+
+// WorktreeNote is a free-form note left on a worktree with 'wtree note add'
+type WorktreeNote struct {
+	Time time.Time `json:"time"`
+	Text string    `json:"text"`
+}
+
+// loadNotes reads a worktree's notes, oldest first; none is not an error
+func loadNotes(worktreePath string) ([]WorktreeNote, error) {
+	var notes []WorktreeNote
+	if _, err := loadWorktreeMetaJSON("read-notes", worktreePath, "notes.json", &notes); err != nil {
+		return nil, err
+	}
+	return notes, nil
+}
+
+// appendNote adds a note to a worktree's notes
+func appendNote(worktreePath string, note WorktreeNote) error {
+	notes, err := loadNotes(worktreePath)
+	if err != nil {
+		return err
+	}
+	_, err = saveWorktreeMetaJSON("write-notes", worktreePath, "notes.json", append(notes, note))
+	return err
+}
+
+// noteWorktree resolves the worktree a note command applies to: the named
+// branch, or the current worktree when empty
+func (m *Manager) noteWorktree(identifier string) (*types.WorktreeInfo, error) {
+	if identifier == "" {
+		return m.currentWorktree()
+	}
+	return m.resolveWorktree(identifier)
+}
+
+// AddNote leaves a note on a worktree, shown by status and list
+func (m *Manager) AddNote(identifier, text string, dryRun bool) error {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return types.NewValidationError("note", "note text is required", nil)
+	}
+	wt, err := m.noteWorktree(identifier)
+	if err != nil {
+		return err
+	}
+	if dryRun {
+		m.ui.Info("[DRY RUN] Would add a note to %s", wt.DisplayBranch())
+		return nil
+	}
+	if err := appendNote(wt.Path, WorktreeNote{Time: time.Now(), Text: text}); err != nil {
+		return err
+	}
+	m.completed("Added a note to %s", wt.DisplayBranch())
+	return nil
+}
+
+// ListNotes prints a worktree's notes, oldest first
+func (m *Manager) ListNotes(identifier string) error {
+	wt, err := m.noteWorktree(identifier)
+	if err != nil {
+		return err
+	}
+	notes, err := loadNotes(wt.Path)
+	if err != nil {
+		return err
+	}
+
+	m.ui.Header("Notes for %s", wt.DisplayBranch())
+	if note := m.branchNote(wt.Branch); note != "" {
+		m.ui.Info("Branch description: %s", note)
+	}
+	if len(notes) == 0 {
+		m.ui.Info("No notes (add one with 'wtree note add')")
+		return nil
+	}
+	for _, note := range notes {
+		fmt.Printf("%s  %s\n", m.ui.Gray(m.ui.Time(note.Time)), note.Text)
+	}
+	return nil
+}
+
+// worktreeNote returns the one-line note shown by list and the picker: the
+// branch description, or else the latest note
+func (m *Manager) worktreeNote(wt *types.WorktreeInfo) string {
+	if note := m.branchNote(wt.Branch); note != "" {
+		return note
+	}
+	notes, err := loadNotes(wt.Path)
+	if err != nil || len(notes) == 0 {
+		return ""
+	}
+	latest, _, _ := strings.Cut(notes[len(notes)-1].Text, "\n")
+	return latest
+}
+
+// todoPattern matches the markers counted by status --todos
+var todoPattern = regexp.MustCompile(`\b(TODO|FIXME)\b`)
+
+// todoDelta counts TODO/FIXME lines added and removed in a worktree,
+// uncommitted changes included, since it diverged from base
+func (m *Manager) todoDelta(worktreePath, base string) (added, removed int, err error) {
+	diff, err := m.repo.DiffFromMergeBase(worktreePath, base)
+	if err != nil {
+		return 0, 0, err
+	}
+	for _, line := range strings.Split(diff, "\n") {
+		switch {
+		case strings.HasPrefix(line, "--- a/"), strings.HasPrefix(line, "+++ b/"),
+			strings.HasPrefix(line, "--- /dev/null"), strings.HasPrefix(line, "+++ /dev/null"):
+			// File headers
+		case strings.HasPrefix(line, "+") && todoPattern.MatchString(line):
+			added++
+		case strings.HasPrefix(line, "-") && todoPattern.MatchString(line):
+			removed++
+		}
+	}
+	return added, removed, nil
+}
+
+// showRecentNotes prints the latest few notes of a worktree for status
+func (m *Manager) showRecentNotes(worktreePath string) {
+	notes, err := loadNotes(worktreePath)
+	if err != nil || len(notes) == 0 {
+		return
+	}
+	if len(notes) > recentNotes {
+		m.ui.Info("Notes (latest %d of %d, see 'wtree note list'):", recentNotes, len(notes))
+		notes = notes[len(notes)-recentNotes:]
+	} else {
+		m.ui.Info("Notes:")
+	}
+	for _, note := range notes {
+		m.ui.Info("  %s  %s", m.ui.Time(note.Time), note.Text)
+	}
+}
@@ -0,0 +1,63 @@
+package worktree
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/awhite/wtree/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// notesMockRepo lists a single worktree and returns a canned diff
+type notesMockRepo struct {
+	MockGitRepo
+	worktree *types.WorktreeInfo
+	diff     string
+}
+
+func (r *notesMockRepo) ListWorktrees() ([]*types.WorktreeInfo, error) {
+	return []*types.WorktreeInfo{r.worktree}, nil
+}
+func (r *notesMockRepo) DiffFromMergeBase(path, base string) (string, error) { return r.diff, nil }
+
+func TestManager_AddNote(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "feature")
+	require.NoError(t, os.MkdirAll(filepath.Join(path, ".git"), 0755))
+	wt := &types.WorktreeInfo{Path: path, Branch: "feature"}
+	m := &Manager{repo: &notesMockRepo{worktree: wt}}
+
+	assert.Equal(t, "", m.worktreeNote(wt))
+	assert.Error(t, m.AddNote("feature", "  ", false))
+
+	require.NoError(t, m.AddNote("feature", "waiting on review", false))
+	require.NoError(t, m.AddNote("feature", "then wire up retries\nand tests", false))
+
+	notes, err := loadNotes(path)
+	require.NoError(t, err)
+	require.Len(t, notes, 2)
+	assert.Equal(t, "waiting on review", notes[0].Text)
+	assert.Equal(t, "then wire up retries", m.worktreeNote(wt))
+
+	assert.Error(t, m.AddNote("missing", "note", false))
+}
+
+func TestManager_todoDelta(t *testing.T) {
+	diff := `diff --git a/main.go b/main.go
+--- a/main.go
++++ b/main.go
+@@ -3 +3,2 @@
+-// TODO: handle errors
++// FIXME: retries
++// TODO(me): tests
++	return nil
+--- TODO in a removed SQL comment
+`
+	m := &Manager{repo: &notesMockRepo{diff: diff}}
+
+	added, removed, err := m.todoDelta("/wt/feature", "main")
+	require.NoError(t, err)
+	assert.Equal(t, 2, added)
+	assert.Equal(t, 2, removed)
+}
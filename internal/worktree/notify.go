@@ -0,0 +1,37 @@
+package worktree
+
+import (
+	"net/http"
+	"os"
+
+	"github.com/awhite/wtree/internal/notify"
+)
+
+// notify sends event to every configured notification that selects it. A
+// failed delivery only warns; the operation itself already succeeded.
+func (m *Manager) notify(event string, vars map[string]string) {
+	if m.globalConfig == nil || len(m.globalConfig.Notifications) == 0 {
+		return
+	}
+
+	host, _ := os.Hostname()
+	values := map[string]string{
+		"repo": m.repo.GetRepoName(),
+		"host": host,
+		"user": currentUser(),
+	}
+	for key, value := range vars {
+		values[key] = value
+	}
+	ev := notify.Event{Name: event, Vars: values}
+
+	client := &http.Client{Timeout: notify.Timeout}
+	for _, target := range m.globalConfig.Notifications {
+		if !notify.Wants(target, ev) {
+			continue
+		}
+		if err := notify.Send(client, target, ev); err != nil {
+			m.warn("Failed to send %s notification: %v", event, err)
+		}
+	}
+}
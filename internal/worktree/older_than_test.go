@@ -0,0 +1,49 @@
+package worktree
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/awhite/wtree/internal/git"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// activityMockRepo has a fixed last commit and uncommitted files
+type activityMockRepo struct {
+	MockGitRepo
+	commit  time.Time
+	changed []string
+}
+
+func (r *activityMockRepo) GetLastCommit(path string) (*git.CommitInfo, error) {
+	return &git.CommitInfo{Author: "sam", Time: r.commit}, nil
+}
+
+func (r *activityMockRepo) ChangedPaths(path string) ([]string, error) { return r.changed, nil }
+
+func TestManager_isWorktreeOlderThan(t *testing.T) {
+	dir := t.TempDir()
+	edited := filepath.Join(dir, "notes.md")
+	require.NoError(t, os.WriteFile(edited, []byte("wip"), 0644))
+	tenDaysAgo := time.Now().Add(-10 * 24 * time.Hour).Truncate(time.Second)
+	require.NoError(t, os.Chtimes(edited, tenDaysAgo, tenDaysAgo))
+
+	// The last commit is old, but an uncommitted edit is more recent
+	repo := &activityMockRepo{commit: time.Now().Add(-60 * 24 * time.Hour), changed: []string{"notes.md", "deleted.go"}}
+	m := &Manager{repo: repo}
+
+	older, last, err := m.isWorktreeOlderThan(dir, "30d")
+	require.NoError(t, err)
+	assert.False(t, older)
+	assert.True(t, last.Equal(tenDaysAgo))
+
+	older, _, err = m.isWorktreeOlderThan(dir, "1w")
+	require.NoError(t, err)
+	assert.True(t, older)
+
+	_, _, err = m.isWorktreeOlderThan(dir, "soon")
+	assert.Error(t, err)
+}
@@ -2,19 +2,66 @@ package worktree
 
 // CreateOptions defines options for creating worktrees
 type CreateOptions struct {
-	CreateBranch bool   // Create branch if it doesn't exist
-	FromBranch   string // Base branch for new branch creation
-	Force        bool   // Force creation even if path exists
-	OpenEditor   bool   // Open in editor after creation
-	DryRun       bool   // Preview what would happen without executing
+	CreateBranch  bool   // Create branch if it doesn't exist
+	FromBranch    string // Base branch for new branch creation
+	OverwritePath bool   // Remove whatever already exists at the worktree path
+	OpenEditor    bool   // Open in editor after creation
+	DryRun        bool   // Preview what would happen without executing
+	Note          string // Description stored on the branch to explain the worktree
+	ReplaceNote   bool   // Let Note replace a description the branch already has
+	Track         string // Upstream for a newly created branch, e.g. "origin/main"
+	PushDefault   bool   // Push a newly created branch to the default remote and track it
+	NoSetup       bool   // Skip copy/link files and post_create hooks
+	SkipHooks     bool   // Skip pre_create and post_create hooks
+	SkipCopy      bool   // Skip copy_files
+	SkipLink      bool   // Skip link_files
+	Refresh       bool   // When the worktree already exists, rerun its setup
+
+	// Tags to give the new worktree, normalized with NormalizeTags
+	Tags []string
+
+	// Experimental: create the worktree on a remote machine over SSH
+	Host           string // SSH destination (user@host)
+	RemoteRepoPath string // Repository path on the remote host (defaults to the local path)
+}
+
+// IssueOptions defines options for creating a worktree from a GitHub issue
+type IssueOptions struct {
+	Create  CreateOptions // Options for the worktree itself
+	Comment bool          // Comment on the issue that work has started
+}
+
+// RollbackOptions defines options for replaying an interrupted rollback
+type RollbackOptions struct {
+	DryRun bool // List the steps without running them
+	Yes    bool // Skip confirmation
+}
+
+// ExecOptions defines options for creating a worktree to run a command in
+type ExecOptions struct {
+	Create  CreateOptions // Options for the worktree itself
+	Command string        // Shell command to run in the worktree after setup
+	Remove  bool          // Delete the worktree afterwards, unless it already existed
+}
+
+// SetupOptions defines options for re-running worktree setup
+type SetupOptions struct {
+	RunHooks bool // Also run post_create hooks
+	SkipCopy bool // Skip copy_files
+	SkipLink bool // Skip link_files
 }
 
 // DeleteOptions defines options for deleting worktrees
 type DeleteOptions struct {
-	DeleteBranch bool // Also delete the branch
-	Force        bool // Force deletion even if dirty
-	IgnoreDirty  bool // Ignore uncommitted changes
-	DryRun       bool // Preview what would happen without executing
+	DeleteBranch      bool // Also delete the branch
+	Yes               bool // Skip confirmation, and allow deleting the current worktree
+	IgnoreDirty       bool // Remove even with uncommitted changes or a git operation in progress
+	ForceBranchDelete bool // Delete the branch even if it isn't merged
+	AllowProtected    bool // Delete the branch even if protected or in an open PR on GitHub
+	DryRun            bool // Preview what would happen without executing
+	Trash             bool // Move the worktree to the trash instead of removing it
+	Permanent         bool // Remove permanently even when trash.enabled is set
+	Autostash         bool // Stash uncommitted changes as wtree-auto/<branch> instead of refusing
 }
 
 // ListOptions defines options for listing worktrees
@@ -22,17 +69,50 @@ type ListOptions struct {
 	ShowStatus   bool   // Show git status for each worktree
 	BranchFilter string // Filter by branch name
 	OnlyDirty    bool   // Show only worktrees with changes
+	GroupBy      string // Group rows by "state", "age", or "author"
+	Summary      bool   // Print aggregate counts instead of rows
+	Mine         bool   // Show only the current user's worktrees
+	Porcelain    bool   // Print stable tab-separated lines for scripts
+
+	// Show only worktrees carrying all of these tags
+	Tags []string
 }
 
+// List grouping modes for ListOptions.GroupBy
+const (
+	GroupByState  = "state"
+	GroupByAge    = "age"
+	GroupByAuthor = "author"
+)
+
 // MergeOptions defines options for merging branches
 type MergeOptions struct {
-	Message string // Custom merge message
-	Force   bool   // Force merge even if working directory is dirty
+	Message     string // Custom merge message
+	IgnoreDirty bool   // Merge even if the working directory is dirty
+	SkipChecks  bool   // Skip pre_merge_checks from .wtreerc
+	Signoff     bool   // Add a Signed-off-by trailer to the merge commit
+	GPGSign     bool   // GPG-sign the merge commit
+	GPGKeyID    string // GPG key to sign with (implies GPGSign)
+	DryRun      bool   // Preview the merge without executing
+}
+
+// RebaseOptions defines options for rebasing the current worktree's branch
+type RebaseOptions struct {
+	Onto        string // Replay the commits after the target onto this instead
+	Interactive bool   // Pass --interactive to git rebase
+	Signoff     bool   // Add a Signed-off-by trailer to each rebased commit
+	GPGSign     bool   // GPG-sign the rebased commits
+	GPGKeyID    string // GPG key to sign with (implies GPGSign)
+	Continue    bool   // Resume a rebase stopped by conflicts
+	Abort       bool   // Abandon a rebase stopped by conflicts
+	DryRun      bool   // Show what would be replayed without rebasing
 }
 
 // SwitchOptions defines options for switching worktrees
 type SwitchOptions struct {
 	OpenEditor bool // Open in editor after switching
+	DryRun     bool // Print the target path only
+	Autostash  bool // Stash the current worktree's uncommitted changes as wtree-auto/<branch>
 }
 
 // StatusOptions defines options for showing worktree status
@@ -40,17 +120,39 @@ type StatusOptions struct {
 	CurrentOnly  bool   // Show only current worktree status
 	BranchFilter string // Filter by branch name
 	Verbose      bool   // Show detailed git information
+	Porcelain    bool   // Print stable tab-separated lines for scripts
+	TODOs        bool   // Count TODO/FIXME lines added and removed versus main
+
+	// With Check, report only worktrees that violate the limits below and
+	// fail if there are any
+	Check       bool
+	MaxBehind   int  // Most commits a worktree may be behind main; negative for no limit
+	FailOnDirty bool // Uncommitted changes or a git operation in progress are a violation
 }
 
 // CleanupOptions defines options for smart worktree cleanup
 type CleanupOptions struct {
-	DryRun     bool   // Preview what would be cleaned up
-	MergedOnly bool   // Clean only merged branches
-	Auto       bool   // Auto cleanup without prompts
-	OlderThan  string // Clean worktrees older than this duration
-	Verbose    bool   // Show detailed information
+	DryRun         bool   // Preview what would be cleaned up
+	MergedOnly     bool   // Clean only merged branches
+	Auto           bool   // Auto cleanup without prompts
+	OlderThan      string // Clean worktrees older than this duration
+	Verbose        bool   // Show detailed information
+	OnDirty        string // How to handle candidates with uncommitted changes: skip, stash, trash, or force
+	Mine           bool   // Consider only the current user's worktrees
+	AllowProtected bool   // Delete branches even if protected or in an open PR on GitHub
+
+	// Consider only worktrees carrying all of these tags
+	Tags []string
 }
 
+// Ways cleanup can handle candidates with uncommitted changes
+const (
+	OnDirtySkip  = "skip"  // Leave the worktree in place
+	OnDirtyStash = "stash" // Stash the changes, then remove
+	OnDirtyTrash = "trash" // Move the worktree to the trash
+	OnDirtyForce = "force" // Remove and discard the changes
+)
+
 // InteractiveOptions defines options for interactive mode
 type InteractiveOptions struct {
 	CreateMode  bool // Launch in branch creation mode
@@ -64,3 +166,50 @@ type EditorsOptions struct {
 	Editors      string // Comma-separated list of editors to open
 	OpenTerminal bool   // Also open a terminal in the worktree
 }
+
+// SplitOptions defines options for moving changes into a new worktree
+type SplitOptions struct {
+	OpenEditor bool // Open the new worktree in the editor
+	DryRun     bool // Preview what would happen without executing
+}
+
+// PublishOptions defines options for pushing a worktree's branch
+type PublishOptions struct {
+	Remote string // Remote to push to (default: remote.pushDefault or origin)
+	DryRun bool   // Preview what would happen without executing
+}
+
+// RestackOptions defines options for restacking a branch stack
+type RestackOptions struct {
+	DryRun bool // Preview what would happen without executing
+}
+
+// ExportPatchOptions defines options for exporting a branch's commits
+type ExportPatchOptions struct {
+	Since  string // Base the exported commits are relative to (default: the main checkout's branch)
+	Output string // Directory for the patch files, or the bundle file (default: current directory)
+	Bundle bool   // Write a git bundle instead of format-patch files
+	DryRun bool   // Preview what would happen without executing
+}
+
+// ImportPatchOptions defines options for importing patches into a new worktree
+type ImportPatchOptions struct {
+	From       string // Branch or commit the patches apply to (default: the main checkout's branch)
+	OpenEditor bool   // Open the new worktree in the editor
+	DryRun     bool   // Preview what would happen without executing
+}
+
+// CompareOptions defines options for opening a branch next to its base
+type CompareOptions struct {
+	Base     string // Branch to compare against (default: the main checkout's branch)
+	Difftool bool   // Run git difftool across the two worktrees instead of the editor
+	NoOpen   bool   // Only prepare the worktrees
+	DryRun   bool   // Preview what would happen without executing
+}
+
+// SubCreateOptions defines options for creating a worktree of a submodule
+type SubCreateOptions struct {
+	In     string // Worktree to link it into (default: the current one)
+	From   string // What a new branch starts from (default: the commit the worktree pins)
+	DryRun bool   // Preview what would happen without executing
+}
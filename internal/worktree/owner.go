@@ -0,0 +1,77 @@
+package worktree
+
+import (
+	"os"
+	"os/user"
+	"strings"
+
+	"github.com/awhite/wtree/pkg/types"
+)
+
+// currentUser returns the name of the user running wtree, safe for use in
+// paths and file names
+func currentUser() string {
+	name := ""
+	if u, err := user.Current(); err == nil {
+		name = u.Username
+	}
+	if name == "" {
+		name = os.Getenv("USER")
+	}
+	if name == "" {
+		name = os.Getenv("USERNAME")
+	}
+	// Windows reports DOMAIN\user
+	if i := strings.LastIndex(name, `\`); i >= 0 {
+		name = name[i+1:]
+	}
+	if name == "" {
+		return "unknown"
+	}
+	return strings.Map(func(r rune) rune {
+		if r == '.' || r == '_' || r == '-' || ('a' <= r && r <= 'z') || ('A' <= r && r <= 'Z') || ('0' <= r && r <= '9') {
+			return r
+		}
+		return '-'
+	}, name)
+}
+
+// multiUser reports whether the global config enables per-user separation
+// for shared clones
+func (m *Manager) multiUser() bool {
+	return m.globalConfig != nil && m.globalConfig.MultiUser
+}
+
+// recordOwner stores the current user as the worktree's owner
+func recordOwner(worktreePath string) error {
+	_, err := writeWorktreeMeta("record-owner", worktreePath, "owner", []byte(currentUser()+"\n"))
+	return err
+}
+
+// worktreeOwner returns the recorded owner of a worktree, or "" when none was
+// recorded
+func worktreeOwner(worktreePath string) string {
+	data, _ := readWorktreeMeta("read-owner", worktreePath, "owner")
+	return strings.TrimSpace(string(data))
+}
+
+// isMine reports whether a worktree belongs to the current user. Without
+// multi_user every worktree with no recorded owner counts as the user's own.
+func (m *Manager) isMine(wt *types.WorktreeInfo) bool {
+	owner := worktreeOwner(wt.Path)
+	if owner == "" {
+		return !m.multiUser()
+	}
+	return owner == currentUser()
+}
+
+// filterMine keeps the main repository and the current user's worktrees
+func (m *Manager) filterMine(worktrees []*types.WorktreeInfo) []*types.WorktreeInfo {
+	var mine []*types.WorktreeInfo
+	for _, wt := range worktrees {
+		if wt.IsMainRepo || m.isMine(wt) {
+			mine = append(mine, wt)
+		}
+	}
+	return mine
+}
@@ -0,0 +1,63 @@
+package worktree
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/awhite/wtree/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestManager_filterMine(t *testing.T) {
+	root := t.TempDir()
+	newWorktree := func(name string) *types.WorktreeInfo {
+		path := filepath.Join(root, name)
+		require.NoError(t, os.MkdirAll(filepath.Join(path, ".git"), 0755))
+		return &types.WorktreeInfo{Path: path, Branch: name}
+	}
+
+	main := newWorktree("main")
+	main.IsMainRepo = true
+	mine := newWorktree("mine")
+	require.NoError(t, recordOwner(mine.Path))
+	theirs := newWorktree("theirs")
+	require.NoError(t, os.MkdirAll(filepath.Join(theirs.Path, ".git", "wtree"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(theirs.Path, ".git", "wtree", "owner"), []byte("someone-else\n"), 0644))
+	unrecorded := newWorktree("unrecorded")
+
+	assert.Equal(t, currentUser(), worktreeOwner(mine.Path))
+	assert.Equal(t, "", worktreeOwner(unrecorded.Path))
+
+	worktrees := []*types.WorktreeInfo{main, mine, theirs, unrecorded}
+
+	m := &Manager{globalConfig: types.DefaultWTreeConfig()}
+	assert.Equal(t, []*types.WorktreeInfo{main, mine, unrecorded}, m.filterMine(worktrees))
+
+	m.globalConfig.MultiUser = true
+	assert.Equal(t, []*types.WorktreeInfo{main, mine}, m.filterMine(worktrees))
+}
+
+func TestManager_worktreeDirNameMultiUser(t *testing.T) {
+	m := &Manager{
+		repo:          &MockGitRepo{},
+		globalConfig:  types.DefaultWTreeConfig(),
+		projectConfig: &types.ProjectConfig{WorktreePattern: "{repo}-{branch}"},
+	}
+	dirName, err := m.worktreeDirName("feature")
+	require.NoError(t, err)
+	assert.Equal(t, "test-repo-feature", dirName)
+
+	m.globalConfig.MultiUser = true
+	dirName, _ = m.worktreeDirName("feature")
+	assert.Equal(t, "test-repo-"+currentUser()+"-feature", dirName)
+
+	m.globalConfig.MultiUser = false
+	dirName, _ = m.worktreeDirName("feature/login")
+	assert.Equal(t, "test-repo-feature-login", dirName)
+
+	m.projectConfig.WorktreePattern = "{user}/{branch}"
+	dirName, _ = m.worktreeDirName("feature")
+	assert.Equal(t, currentUser()+"/feature", dirName)
+}
@@ -0,0 +1,228 @@
+package worktree
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/awhite/wtree/pkg/types"
+)
+
+// mainCheckoutBranch returns the branch checked out in the main worktree,
+// which patches are exported against and imported onto by default
+func (m *Manager) mainCheckoutBranch() (string, error) {
+	worktrees, err := m.repo.ListWorktrees()
+	if err != nil {
+		return "", fmt.Errorf("failed to list worktrees: %w", err)
+	}
+	for _, wt := range worktrees {
+		if wt.IsMainRepo && wt.Branch != "" {
+			return wt.Branch, nil
+		}
+	}
+	return "", types.NewValidationError("main-branch",
+		"the main checkout has no branch checked out; pass a base explicitly", nil)
+}
+
+// ExportPatch writes the commits branch has over a base to files that can
+// be mailed or carried to another machine: format-patch files, one per
+// commit, or with Bundle a single git bundle. An empty branch means the
+// current worktree's.
+func (m *Manager) ExportPatch(branch string, options ExportPatchOptions) error {
+	if branch == "" {
+		current, err := m.currentWorktree()
+		if err != nil {
+			return err
+		}
+		if current.Branch == "" {
+			return types.NewValidationError("export-patch",
+				fmt.Sprintf("HEAD is detached in %s; name the branch to export", current.Path), nil)
+		}
+		branch = current.Branch
+	}
+	if !m.repo.BranchExists(branch) {
+		return types.NewValidationError("export-patch", fmt.Sprintf("branch '%s' does not exist", branch), nil)
+	}
+
+	base := options.Since
+	if base == "" {
+		var err error
+		if base, err = m.mainCheckoutBranch(); err != nil {
+			return err
+		}
+	}
+	if base == branch {
+		return types.NewValidationError("export-patch",
+			fmt.Sprintf("'%s' is the base branch; pass --since to export it against another", branch), nil)
+	}
+	ahead, _, err := m.repo.AheadBehind(branch, base)
+	if err != nil {
+		return err
+	}
+	if ahead == 0 {
+		return types.NewValidationError("export-patch",
+			fmt.Sprintf("'%s' has no commits that '%s' doesn't", branch, base), nil)
+	}
+
+	if wt, err := m.resolveWorktree(branch); err == nil {
+		if status, err := m.repo.GetWorktreeStatus(wt.Path); err == nil && !status.IsClean {
+			m.warn("%s has uncommitted changes; only commits are exported", wt.Path)
+		}
+	}
+
+	output := options.Output
+	if output == "" {
+		output = "."
+	}
+	if options.Bundle && !strings.HasSuffix(output, ".bundle") {
+		output = filepath.Join(output, strings.ReplaceAll(branch, "/", "-")+".bundle")
+	}
+	dir := output
+	if options.Bundle {
+		dir = filepath.Dir(output)
+	}
+
+	if options.DryRun {
+		if options.Bundle {
+			m.ui.Info("[DRY RUN] Would bundle %d commits of '%s' since '%s' into %s", ahead, branch, base, output)
+		} else {
+			m.ui.Info("[DRY RUN] Would write %d patches of '%s' since '%s' to %s", ahead, branch, base, output)
+		}
+		return nil
+	}
+
+	// git runs in the repository root, so relative paths would land there
+	if dir, err = filepath.Abs(dir); err != nil {
+		return types.NewFileSystemError("export-patch", options.Output, "failed to resolve path", err)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return types.NewFileSystemError("export-patch", dir, "failed to create directory", err)
+	}
+
+	if options.Bundle {
+		file := filepath.Join(dir, filepath.Base(output))
+		if err := m.repo.CreateBundle(file, base, branch); err != nil {
+			return err
+		}
+		m.completed("Bundled %d commits of '%s' into %s", ahead, branch, file)
+		m.ui.Info("The receiving repository needs '%s'; import with: wtree import-patch %s <new-branch>", base, file)
+		return nil
+	}
+
+	patches, err := m.repo.FormatPatches(base, branch, dir)
+	if err != nil {
+		return err
+	}
+	m.completed("Wrote %d patches of '%s' to %s", len(patches), branch, dir)
+	return nil
+}
+
+// ImportPatch creates a worktree on a new branch from patches made by
+// export-patch (or git format-patch): a directory of .patch files, a single
+// patch or mailbox, or a git bundle
+func (m *Manager) ImportPatch(source, branch string, options ImportPatchOptions) error {
+	if branch == "" {
+		return types.NewValidationError("import-patch", "branch name cannot be empty", nil)
+	}
+	if m.repo.BranchExists(branch) {
+		return types.NewValidationError("import-patch", fmt.Sprintf("branch '%s' already exists", branch), nil)
+	}
+	source, err := filepath.Abs(source)
+	if err != nil {
+		return types.NewFileSystemError("import-patch", source, "failed to resolve path", err)
+	}
+	info, err := os.Stat(source)
+	if err != nil {
+		return types.NewFileSystemError("import-patch", source, "cannot read patches", err)
+	}
+
+	if !info.IsDir() && isBundle(source) {
+		return m.importBundle(source, branch, options)
+	}
+
+	patches := []string{source}
+	if info.IsDir() {
+		if patches, err = filepath.Glob(filepath.Join(source, "*.patch")); err != nil {
+			return types.NewFileSystemError("import-patch", source, "failed to list patches", err)
+		}
+		// format-patch numbers its files, so name order is commit order
+		sort.Strings(patches)
+	}
+	if len(patches) == 0 {
+		return types.NewValidationError("import-patch", fmt.Sprintf("no .patch files in %s", source), nil)
+	}
+
+	base := options.From
+	if base == "" {
+		if base, err = m.mainCheckoutBranch(); err != nil {
+			return err
+		}
+	}
+
+	if options.DryRun {
+		m.ui.Info("[DRY RUN] Would create branch '%s' from '%s' and a worktree for it", branch, base)
+		m.ui.Info("[DRY RUN] Would apply %d patches from %s there", len(patches), source)
+		return nil
+	}
+
+	createOptions := CreateOptions{
+		CreateBranch: true,
+		FromBranch:   base,
+		OpenEditor:   options.OpenEditor,
+	}
+	if err := m.Create(branch, createOptions); err != nil {
+		return err
+	}
+	target, err := m.resolveWorktree(branch)
+	if err != nil {
+		return err
+	}
+	if err := m.repo.ApplyPatches(target.Path, patches); err != nil {
+		m.warn("%s is left at '%s' without the patches; apply them with 'git am --3way' there, or delete it", target.Path, base)
+		return err
+	}
+
+	m.completed("Applied %d patches to '%s' in %s", len(patches), branch, target.Path)
+	return nil
+}
+
+// importBundle creates branch from the branch a bundle carries and a
+// worktree for it
+func (m *Manager) importBundle(source, branch string, options ImportPatchOptions) error {
+	if options.From != "" {
+		return types.NewValidationError("import-patch", "--from doesn't apply to bundles, which carry their own history", nil)
+	}
+	heads, err := m.repo.BundleHeads(source)
+	if err != nil {
+		return err
+	}
+	if len(heads) != 1 {
+		return types.NewValidationError("import-patch",
+			fmt.Sprintf("%s should hold exactly one branch, found %d (%s)", source, len(heads), strings.Join(heads, ", ")), nil)
+	}
+
+	if options.DryRun {
+		m.ui.Info("[DRY RUN] Would create branch '%s' from %s in %s and a worktree for it",
+			branch, strings.TrimPrefix(heads[0], "refs/heads/"), source)
+		return nil
+	}
+
+	if err := m.repo.Fetch(source, heads[0]+":refs/heads/"+branch); err != nil {
+		return err
+	}
+	return m.Create(branch, CreateOptions{OpenEditor: options.OpenEditor})
+}
+
+// isBundle reports whether a file is a git bundle, by its signature line
+func isBundle(path string) bool {
+	file, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer file.Close()
+	line, _ := bufio.NewReader(file).ReadString('\n')
+	return strings.HasPrefix(line, "# v2 git bundle") || strings.HasPrefix(line, "# v3 git bundle")
+}
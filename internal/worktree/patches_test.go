@@ -0,0 +1,52 @@
+package worktree
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// patchMockRepo has no branches and a bundle holding the given heads
+type patchMockRepo struct {
+	MockGitRepo
+	heads []string
+}
+
+func (r *patchMockRepo) BranchExists(name string) bool             { return false }
+func (r *patchMockRepo) BundleHeads(file string) ([]string, error) { return r.heads, nil }
+
+func TestIsBundle(t *testing.T) {
+	dir := t.TempDir()
+	bundle := filepath.Join(dir, "feature.bundle")
+	patch := filepath.Join(dir, "0001-fix.patch")
+	require.NoError(t, os.WriteFile(bundle, []byte("# v2 git bundle\nabc123 refs/heads/feature\n"), 0644))
+	require.NoError(t, os.WriteFile(patch, []byte("From abc123 Mon Sep 17 00:00:00 2001\n"), 0644))
+
+	assert.True(t, isBundle(bundle))
+	assert.False(t, isBundle(patch))
+	assert.False(t, isBundle(filepath.Join(dir, "missing")))
+}
+
+func TestManager_ImportPatch_Validation(t *testing.T) {
+	dir := t.TempDir()
+
+	m := &Manager{repo: &MockGitRepo{}}
+	err := m.ImportPatch(dir, "feature", ImportPatchOptions{})
+	assert.ErrorContains(t, err, "already exists")
+
+	m = &Manager{repo: &patchMockRepo{}}
+	err = m.ImportPatch(dir, "feature", ImportPatchOptions{From: "main"})
+	assert.ErrorContains(t, err, "no .patch files")
+
+	bundle := filepath.Join(dir, "two.bundle")
+	require.NoError(t, os.WriteFile(bundle, []byte("# v2 git bundle\n"), 0644))
+	m = &Manager{repo: &patchMockRepo{heads: []string{"refs/heads/a", "refs/heads/b"}}}
+	err = m.ImportPatch(bundle, "feature", ImportPatchOptions{})
+	assert.ErrorContains(t, err, "exactly one branch")
+
+	err = m.ImportPatch(bundle, "feature", ImportPatchOptions{From: "main"})
+	assert.ErrorContains(t, err, "--from doesn't apply")
+}
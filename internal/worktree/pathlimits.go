@@ -0,0 +1,164 @@
+package worktree
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/awhite/wtree/pkg/types"
+)
+
+// Path limits for generated worktree paths
+const (
+	windowsMaxPath   = 260  // MAX_PATH, including the terminating NUL
+	unixMaxPath      = 4096 // PATH_MAX on Linux
+	maxNameLength    = 255  // Longest file name on common file systems
+	pathHeadroom     = 60   // Left for files inside the worktree on Windows
+	shortenHashChars = 8
+)
+
+// windowsReservedNames can't be used as file names on Windows, with or
+// without an extension
+var windowsReservedNames = map[string]bool{
+	"CON": true, "PRN": true, "AUX": true, "NUL": true,
+	"COM1": true, "COM2": true, "COM3": true, "COM4": true, "COM5": true,
+	"COM6": true, "COM7": true, "COM8": true, "COM9": true,
+	"LPT1": true, "LPT2": true, "LPT3": true, "LPT4": true, "LPT5": true,
+	"LPT6": true, "LPT7": true, "LPT8": true, "LPT9": true,
+}
+
+// maxWorktreePath returns the longest worktree path allowed on goos. On
+// Windows room is left for the files inside the worktree, which also have
+// to fit in MAX_PATH.
+func maxWorktreePath(goos string, configured int) int {
+	if configured > 0 {
+		return configured
+	}
+	if goos == "windows" {
+		return windowsMaxPath - 1 - pathHeadroom
+	}
+	return unixMaxPath - 1
+}
+
+// checkPathName reports why name can't be a file name on goos, if it can't
+func checkPathName(goos, name string) error {
+	for _, r := range name {
+		if r < 0x20 || r == 0x7f {
+			return fmt.Errorf("'%s' contains a control character", name)
+		}
+	}
+	if len(name) > maxNameLength {
+		return fmt.Errorf("'%s...' is %d bytes, over the %d-byte file name limit", name[:32], len(name), maxNameLength)
+	}
+	if goos != "windows" {
+		return nil
+	}
+	if i := strings.IndexAny(name, `<>:"\|?*`); i >= 0 {
+		return fmt.Errorf("'%s' contains '%c', which Windows does not allow in file names", name, name[i])
+	}
+	if strings.HasSuffix(name, ".") || strings.HasSuffix(name, " ") {
+		return fmt.Errorf("'%s' ends with a dot or space, which Windows does not allow", name)
+	}
+	base, _, _ := strings.Cut(name, ".")
+	if windowsReservedNames[strings.ToUpper(base)] {
+		return fmt.Errorf("'%s' is a reserved name on Windows", name)
+	}
+	return nil
+}
+
+// shortenName fits name in max bytes as a single directory name, keeping
+// its start and ending it with a hash of the whole so shortened names of
+// different branches stay distinct
+func shortenName(name string, max int) string {
+	sum := sha1.Sum([]byte(name))
+	suffix := "-" + hex.EncodeToString(sum[:])[:shortenHashChars]
+	flat := strings.Trim(strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f || strings.ContainsRune(`/\<>:"|?* `, r) {
+			return '-'
+		}
+		return r
+	}, name), "-.")
+
+	keep := max - len(suffix)
+	if keep < 1 {
+		return suffix[1:]
+	}
+	if len(flat) > keep {
+		flat = flat[:keep]
+		// Don't cut a multi-byte character in half
+		for len(flat) > 0 && !utf8.ValidString(flat) {
+			flat = flat[:len(flat)-1]
+		}
+		flat = strings.TrimRight(flat, "-.")
+	}
+	return flat + suffix
+}
+
+// fitWorktreePath checks that the directory name generated for a worktree
+// under parent is valid and short enough on goos, shortening it when
+// paths.shorten is set
+func (m *Manager) fitWorktreePath(goos, parent, dirName string) (string, error) {
+	var paths types.PathConfig
+	if m.globalConfig != nil {
+		paths = m.globalConfig.Paths
+	}
+	limit := maxWorktreePath(goos, paths.MaxLength)
+	path := filepath.Join(parent, dirName)
+
+	problem := ""
+	for _, name := range strings.Split(filepath.ToSlash(dirName), "/") {
+		if err := checkPathName(goos, name); err != nil {
+			problem = err.Error()
+			break
+		}
+	}
+	if problem == "" && len(path) > limit {
+		problem = fmt.Sprintf("worktree path is %d characters, over the limit of %d", len(path), limit)
+	}
+	if problem == "" {
+		return path, nil
+	}
+
+	if !paths.Shorten {
+		return "", types.NewValidationError("worktree-path",
+			fmt.Sprintf("%s: %s (set paths.shorten: true to shorten it automatically, or use a shorter branch name)", problem, path), nil)
+	}
+
+	room := limit - len(parent) - 1
+	if room > maxNameLength {
+		room = maxNameLength
+	}
+	if room <= shortenHashChars {
+		return "", types.NewValidationError("worktree-path",
+			fmt.Sprintf("%s: %s, and the parent directory leaves no room to shorten it", problem, path), nil)
+	}
+	shortened := filepath.Join(parent, shortenName(dirName, room))
+	m.warn("Shortened the worktree path (%s): %s", problem, shortened)
+	return shortened, nil
+}
+
+// checkSymlinkSupport warns when the file system at dir can't create the
+// symlinks link_files needs
+func (m *Manager) checkSymlinkSupport(dir string) {
+	if m.projectConfig == nil || (len(m.projectConfig.LinkFiles) == 0 && len(m.projectConfig.LinkEntries) == 0) {
+		return
+	}
+	probe, err := os.MkdirTemp(dir, ".wtree-symlink-")
+	if err != nil {
+		return
+	}
+	defer os.RemoveAll(probe)
+
+	if err := os.Symlink(probe, filepath.Join(probe, "link")); err != nil {
+		hint := ""
+		if runtime.GOOS == "windows" {
+			hint = "; enable Developer Mode or run as administrator"
+		}
+		m.warn("%s does not support symlinks, so link_files will fail (%v)%s; consider copy_files instead", dir, err, hint)
+	}
+}
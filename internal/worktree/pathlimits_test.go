@@ -0,0 +1,71 @@
+package worktree
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/awhite/wtree/pkg/types"
+)
+
+func TestCheckPathName(t *testing.T) {
+	tests := []struct {
+		goos  string
+		name  string
+		valid bool
+	}{
+		{"linux", "shop-feature-login", true},
+		{"linux", "shop-a:b", true},
+		{"windows", "shop-a:b", false},
+		{"windows", "shop-fix?", false},
+		{"windows", "CON", false},
+		{"windows", "aux.txt", false},
+		{"windows", "shop-trailing.", false},
+		{"linux", "tab\there", false},
+		{"linux", strings.Repeat("x", 256), false},
+	}
+
+	for _, tt := range tests {
+		err := checkPathName(tt.goos, tt.name)
+		assert.Equal(t, tt.valid, err == nil, "%s %q: %v", tt.goos, tt.name, err)
+	}
+}
+
+func TestShortenName(t *testing.T) {
+	long := "shop-feature/" + strings.Repeat("very-long-branch-name-", 10)
+	short := shortenName(long, 40)
+
+	assert.Len(t, short, 40)
+	assert.True(t, strings.HasPrefix(short, "shop-feature-very-long"))
+	assert.NotContains(t, short, "/")
+	assert.NotEqual(t, short, shortenName(long+"2", 40), "different names get different hashes")
+	assert.Equal(t, short, shortenName(long, 40), "shortening is stable")
+}
+
+func TestManager_fitWorktreePath(t *testing.T) {
+	parent := "/home/dev/src"
+	dirName := "shop-feature-" + strings.Repeat("x", 200)
+	m := &Manager{globalConfig: types.DefaultWTreeConfig()}
+
+	path, err := m.fitWorktreePath("linux", parent, "shop-login")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(parent, "shop-login"), path)
+
+	_, err = m.fitWorktreePath("windows", parent, dirName)
+	assert.ErrorContains(t, err, "over the limit of 199")
+	assert.ErrorContains(t, err, "paths.shorten")
+
+	m.globalConfig.Paths.Shorten = true
+	path, err = m.fitWorktreePath("windows", parent, dirName)
+	require.NoError(t, err)
+	assert.Len(t, path, 199)
+	assert.Equal(t, parent, filepath.Dir(path))
+
+	m.globalConfig.Paths.MaxLength = 25
+	path, err = m.fitWorktreePath("linux", parent, "shop-login-form")
+	require.NoError(t, err)
+	assert.Len(t, path, 25)
+}
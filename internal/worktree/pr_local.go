@@ -0,0 +1,131 @@
+package worktree
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/awhite/wtree/pkg/types"
+)
+
+// shareableMarker marks an env file in the main checkout as safe to copy into
+// review worktrees; it must appear on a line of its own
+const shareableMarker = "# wtree: shareable"
+
+// PRSyncLocalOptions defines options for copying local overrides into PR worktrees
+type PRSyncLocalOptions struct {
+	DryRun bool // Show what would be copied
+}
+
+// shareableEnvFiles returns the .env* files at the top of dir that carry
+// the shareable marker
+func shareableEnvFiles(dir string) []string {
+	matches, _ := filepath.Glob(filepath.Join(dir, ".env*"))
+
+	var files []string
+	for _, match := range matches {
+		if info, err := os.Stat(match); err != nil || !info.Mode().IsRegular() {
+			continue
+		}
+		if hasShareableMarker(match) {
+			files = append(files, filepath.Base(match))
+		}
+	}
+	return files
+}
+
+// hasShareableMarker reports whether a file has the shareable marker line
+func hasShareableMarker(path string) bool {
+	f, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if strings.TrimSpace(scanner.Text()) == shareableMarker {
+			return true
+		}
+	}
+	return false
+}
+
+// SyncLocalOverrides copies review.local_files and shareable env files from
+// the main checkout into every PR worktree, so review environments behave
+// like the main checkout. pre_sync and post_sync run in each worktree around
+// its copy; a failing pre_sync skips it.
+func (pm *PRManager) SyncLocalOverrides(options PRSyncLocalOptions) error {
+	pm.ui.Header("Syncing local overrides into PR worktrees")
+
+	prWorktrees, err := pm.ListPRWorktrees()
+	if err != nil {
+		return err
+	}
+	if len(prWorktrees) == 0 {
+		pm.ui.Info("No PR worktrees found")
+		return nil
+	}
+
+	mainPath := pm.mainWorktreePath()
+	patterns := append(append([]string{}, pm.globalConfig.Review.LocalFiles...), shareableEnvFiles(mainPath)...)
+
+	// The main checkout is the source; its files are the user's own
+	fm := NewFileManager(pm.globalConfig.UI.Verbose)
+	if err := fm.SetBasePath(mainPath); err != nil {
+		return err
+	}
+	if err := fm.ValidateFilePatterns(patterns); err != nil {
+		return err
+	}
+
+	if options.DryRun {
+		var present []string
+		for _, pattern := range patterns {
+			if matches, _ := filepath.Glob(filepath.Join(mainPath, pattern)); len(matches) > 0 {
+				present = append(present, pattern)
+			}
+		}
+		if len(present) == 0 {
+			pm.ui.Info("[DRY RUN] None of the local override files exist in %s", mainPath)
+			return nil
+		}
+		pm.ui.Info("[DRY RUN] Would copy %s into %d PR worktrees", strings.Join(present, ", "), len(prWorktrees))
+		return nil
+	}
+
+	table := pm.ui.NewTable()
+	table.SetHeaders("PR", "Path", "Result")
+	failed := 0
+	for _, prWt := range prWorktrees {
+		fm.ResetStats()
+		result := ""
+		hookCtx := pm.buildHookContext(types.HookPreSync, prWt.Branch, prWt.Path)
+		hookCtx.Environment["WTREE_PR_NUMBER"] = strconv.Itoa(prWt.PRNumber)
+		if err := pm.executeHooks(types.HookPreSync, hookCtx); err != nil {
+			failed++
+			result = "skipped: pre_sync hook failed: " + err.Error()
+		} else if err := fm.CopyFiles(patterns, mainPath, prWt.Path, nil); err != nil {
+			failed++
+			result = "failed: " + err.Error()
+		} else {
+			result = fm.Stats().String()
+			hookCtx.Event = types.HookPostSync
+			if err := pm.executeHooks(types.HookPostSync, hookCtx); err != nil {
+				result += "; post_sync hook failed: " + err.Error()
+			}
+		}
+		table.AddRow(fmt.Sprintf("#%d", prWt.PRNumber), prWt.Path, result)
+	}
+	table.Render()
+
+	if failed > 0 {
+		pm.warn("Synced %d/%d PR worktrees", len(prWorktrees)-failed, len(prWorktrees))
+	} else {
+		pm.completed("Synced %d PR worktrees", len(prWorktrees))
+	}
+	return nil
+}
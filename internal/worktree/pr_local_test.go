@@ -0,0 +1,20 @@
+package worktree
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShareableEnvFiles(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".env.local"), []byte("A=1\n# wtree: shareable\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".env.secret"), []byte("TOKEN=x\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".env"), []byte("B=2 # wtree: shareable\n"), 0644))
+	require.NoError(t, os.Mkdir(filepath.Join(dir, ".envs"), 0755))
+
+	assert.Equal(t, []string{".env.local"}, shareableEnvFiles(dir))
+}
@@ -21,16 +21,18 @@ type PRManager struct {
 
 // PRWorktreeOptions defines options for PR worktree creation
 type PRWorktreeOptions struct {
-	Force      bool // Force creation even if path exists
-	OpenEditor bool // Open in editor after creation
+	OverwritePath bool // Remove whatever already exists at the worktree path
+	OpenEditor    bool // Open in editor after creation
 }
 
 // PRCleanupOptions defines options for PR cleanup operations
 type PRCleanupOptions struct {
 	State  string // PR state filter (open, closed, merged, all)
-	Force  bool   // Force cleanup without confirmation
+	Yes    bool   // Skip the confirmation
 	DryRun bool   // Show what would be cleaned up
 	Limit  int    // Maximum number of PRs to process
+
+	AllowProtected bool // Also remove worktrees whose PR is still open
 }
 
 // PRWorktreeInfo represents a PR worktree with metadata
@@ -63,7 +65,7 @@ func (pm *PRManager) CreatePRWorktree(prNumber int, options PRWorktreeOptions) e
 	}
 
 	// Fetch PR information
-	pm.ui.Progress("Fetching PR information...")
+	pm.progress("Fetching PR information...")
 	prInfo, err := pm.github.GetPR(prNumber)
 	if err != nil {
 		return err
@@ -76,7 +78,7 @@ func (pm *PRManager) CreatePRWorktree(prNumber int, options PRWorktreeOptions) e
 
 	// Warn about draft PRs
 	if prInfo.IsDraft {
-		pm.ui.Warning("PR #%d is a draft", prNumber)
+		pm.warn("PR #%d is a draft", prNumber)
 	}
 
 	pm.ui.Info("PR: %s by %s", prInfo.Title, prInfo.Author)
@@ -93,18 +95,18 @@ func (pm *PRManager) CreatePRWorktree(prNumber int, options PRWorktreeOptions) e
 
 	// Check if path already exists
 	if pathExists(worktreePath) {
-		if !options.Force {
+		if !options.OverwritePath {
 			return types.NewFileSystemError("create-pr-worktree", worktreePath,
-				fmt.Sprintf("PR worktree path already exists: %s", worktreePath), nil)
+				fmt.Sprintf("PR worktree path already exists: %s; remove it or use --overwrite-path", worktreePath), nil)
 		}
-		pm.ui.Warning("Removing existing path: %s", worktreePath)
+		pm.warn("Removing existing path: %s", worktreePath)
 		if err := pm.removeExistingPath(worktreePath); err != nil {
 			return err
 		}
 	}
 
 	// Checkout PR branch using GitHub CLI
-	pm.ui.Progress("Checking out PR branch...")
+	pm.progress("Checking out PR branch...")
 	branchName, err := pm.github.CheckoutPR(prNumber)
 	if err != nil {
 		return fmt.Errorf("failed to checkout PR: %w", err)
@@ -120,41 +122,62 @@ func (pm *PRManager) CreatePRWorktree(prNumber int, options PRWorktreeOptions) e
 
 	// Create the worktree
 	pm.ui.Info("Creating PR worktree at: %s", worktreePath)
-	if err := pm.repo.CreateWorktree(worktreePath, branchName); err != nil {
+	if err := pm.withGitLock(func() error { return pm.repo.CreateWorktree(worktreePath, branchName) }); err != nil {
 		return fmt.Errorf("failed to create PR worktree: %w", err)
 	}
 	pm.rollback.AddWorktreeCleanup(worktreePath)
+	if err := recordCreation(worktreePath, prInfo.BaseRef); err != nil {
+		pm.warn("Failed to record worktree metadata: %v", err)
+	}
 
 	// Copy/link files based on configuration
-	if err := pm.handleFileOperations(worktreePath); err != nil {
-		pm.ui.Warning("File operations failed: %v", err)
-		pm.ui.Warning("Rolling back PR worktree creation")
-		_ = pm.rollback.Execute()
+	if err := pm.handleFileOperations(hookCtx, false, false); err != nil {
+		pm.warn("File operations failed: %v", err)
+		pm.warn("Rolling back PR worktree creation")
+		_ = pm.runRollback()
 		return fmt.Errorf("file operations failed: %w", err)
 	}
 
+	if err := pm.setupGitHooks(worktreePath); err != nil {
+		pm.warn("Git hook setup failed: %v", err)
+	}
+
 	// Store PR metadata
 	if err := pm.storePRMetadata(worktreePath, prInfo); err != nil {
-		pm.ui.Warning("Failed to store PR metadata: %v", err)
+		pm.warn("Failed to store PR metadata: %v", err)
 	}
 
 	// Execute post-create hooks
 	hookCtx.Event = types.HookPostCreate
 	if err := pm.executeHooks(types.HookPostCreate, hookCtx); err != nil {
-		pm.ui.Warning("Post-create hook failed, but PR worktree was created: %v", err)
+		pm.warn("Post-create hook failed, but PR worktree was created: %v", err)
 	}
 
 	// Success - clear rollback operations
 	pm.rollback.Clear()
-	pm.ui.Success("PR worktree created successfully: %s", worktreePath)
+	pm.completed("PR worktree created successfully: %s", worktreePath)
 	pm.ui.InfoIndented("PR #%d: %s", prNumber, prInfo.Title)
 	pm.ui.InfoIndented("Author: %s", prInfo.Author)
 	pm.ui.InfoIndented("URL: %s", prInfo.URL)
+	pm.notify(types.NotifyPRCreated, map[string]string{
+		"branch":    branchName,
+		"path":      worktreePath,
+		"pr_number": strconv.Itoa(prNumber),
+		"pr_title":  prInfo.Title,
+		"pr_url":    prInfo.URL,
+		"author":    prInfo.Author,
+	})
+
+	pm.openConfiguredURLs(map[string]string{
+		"branch":    branchName,
+		"pr_url":    prInfo.URL,
+		"pr_number": strconv.Itoa(prInfo.Number),
+	})
 
 	// Open in editor if configured
 	if options.OpenEditor || pm.shouldAutoOpenEditor() {
 		if err := pm.openInEditor(worktreePath); err != nil {
-			pm.ui.Warning("Failed to open in editor: %v", err)
+			pm.warn("Failed to open in editor: %v", err)
 		}
 	}
 
@@ -198,6 +221,38 @@ func (pm *PRManager) ListPRWorktrees() ([]*PRWorktreeInfo, error) {
 	return prWorktrees, nil
 }
 
+// skipOpenPRs leaves out PR worktrees whose PR is open, looking the state up
+// on GitHub when refresh is set and otherwise using the state already known
+func (pm *PRManager) skipOpenPRs(prWorktrees []*PRWorktreeInfo, refresh bool) []*PRWorktreeInfo {
+	if refresh && pm.github.IsAvailable() != nil {
+		refresh = false
+	}
+	var infos []*github.PRInfo
+	if refresh {
+		infos, _ = pm.fetchPRs(prWorktrees)
+	}
+	var kept []*PRWorktreeInfo
+	for i, prWt := range prWorktrees {
+		if refresh && infos[i] != nil {
+			prWt.PRState = infos[i].State
+		}
+		if strings.EqualFold(prWt.PRState, "open") {
+			pm.warn("Keeping PR #%d worktree: the PR is still open (use --allow-protected to remove it)", prWt.PRNumber)
+			continue
+		}
+		kept = append(kept, prWt)
+	}
+	return kept
+}
+
+// cleanupVerdict describes a PR cleanup dry-run decision
+func cleanupVerdict(remove bool) string {
+	if remove {
+		return "would remove"
+	}
+	return "would keep"
+}
+
 // CleanupPRWorktrees removes PR worktrees based on criteria
 func (pm *PRManager) CleanupPRWorktrees(options PRCleanupOptions) error {
 	pm.ui.Header("Cleaning up PR worktrees")
@@ -217,24 +272,44 @@ func (pm *PRManager) CleanupPRWorktrees(options PRCleanupOptions) error {
 	var toCleanup []*PRWorktreeInfo
 	if options.State != "" && options.State != "all" {
 		// Fetch current PR states from GitHub
-		pm.ui.Progress("Checking PR states...")
-
-		for _, prWt := range prWorktrees {
-			if prInfo, err := pm.github.GetPR(prWt.PRNumber); err == nil {
-				if options.State == prInfo.State ||
-					(options.State == "closed" && (prInfo.State == "closed" || prInfo.State == "merged")) {
+		if err := pm.github.IsAvailable(); err != nil {
+			return err
+		}
+		pm.progress("Checking PR states...")
+
+		infos, errs := pm.fetchPRs(prWorktrees)
+		for i, prWt := range prWorktrees {
+			if prInfo, err := infos[i], errs[i]; err == nil {
+				matches := options.State == prInfo.State ||
+					(options.State == "closed" && (prInfo.State == "closed" || prInfo.State == "merged"))
+				if matches {
 					prWt.PRState = prInfo.State
 					toCleanup = append(toCleanup, prWt)
 				}
+				if options.DryRun {
+					pm.ui.Info("[DRY RUN] PR #%d is %s on GitHub: %s", prWt.PRNumber, prInfo.State, cleanupVerdict(matches))
+				}
 			} else {
 				// If we can't fetch PR info, assume it might be deleted/closed
-				if options.State == "closed" {
+				assumeClosed := options.State == "closed"
+				if assumeClosed {
 					toCleanup = append(toCleanup, prWt)
 				}
+				if options.DryRun {
+					pm.ui.Info("[DRY RUN] PR #%d could not be fetched (%v): %s", prWt.PRNumber, err, cleanupVerdict(assumeClosed))
+				}
 			}
 		}
 	} else {
 		toCleanup = prWorktrees
+		if options.DryRun && options.AllowProtected {
+			pm.ui.Info("[DRY RUN] --state all: PR states would not be checked on GitHub")
+		}
+	}
+
+	// Keep worktrees of PRs still in review
+	if !options.AllowProtected {
+		toCleanup = pm.skipOpenPRs(toCleanup, options.State == "" || options.State == "all")
 	}
 
 	// Apply limit if specified
@@ -272,10 +347,10 @@ func (pm *PRManager) CleanupPRWorktrees(options PRCleanupOptions) error {
 		return nil
 	}
 
-	// Confirm cleanup unless forced
-	if !options.Force {
+	// Confirm cleanup unless --yes
+	if !options.Yes {
 		confirmMsg := fmt.Sprintf("Delete %d PR worktrees?", len(toCleanup))
-		if err := pm.ui.Confirm(confirmMsg); err != nil {
+		if err := pm.confirm(ConfirmPRCleanup, confirmMsg); err != nil {
 			return err
 		}
 	}
@@ -287,18 +362,18 @@ func (pm *PRManager) CleanupPRWorktrees(options PRCleanupOptions) error {
 
 		deleteOptions := DeleteOptions{
 			DeleteBranch: false, // Don't delete PR branches automatically
-			Force:        options.Force,
-			IgnoreDirty:  true, // Allow cleanup of dirty PR worktrees
+			Yes:          true,  // Confirmed for the whole batch above
+			IgnoreDirty:  true,  // Allow cleanup of dirty PR worktrees
 		}
 
 		if err := pm.Delete(prWt.Branch, deleteOptions); err != nil {
-			pm.ui.Warning("Failed to remove PR #%d worktree: %v", prWt.PRNumber, err)
+			pm.warn("Failed to remove PR #%d worktree: %v", prWt.PRNumber, err)
 		} else {
 			removed++
 		}
 	}
 
-	pm.ui.Success("Successfully removed %d out of %d PR worktrees", removed, len(toCleanup))
+	pm.completed("Successfully removed %d out of %d PR worktrees", removed, len(toCleanup))
 	return nil
 }
 
@@ -313,29 +388,42 @@ func (pm *PRManager) generatePRWorktreePath(prNumber int) (string, error) {
 	parentDir := filepath.Dir(repoRoot)
 	repoName := pm.repo.GetRepoName()
 
-	// PR worktree pattern: {repo}-pr-{number}
-	dirName := fmt.Sprintf("%s-pr-%d", repoName, prNumber)
+	// PR worktree pattern: {repo}-pr-{number}, or {repo}-{user}-pr-{number}
+	// with multi_user
+	dirName := fmt.Sprintf("%s%d", pm.prWorktreePrefixes(repoName)[0], prNumber)
 
 	return filepath.Join(parentDir, dirName), nil
 }
 
+// prWorktreePrefixes returns the directory name prefixes of PR worktrees, the
+// one used for new worktrees first
+func (pm *PRManager) prWorktreePrefixes(repoName string) []string {
+	if pm.multiUser() {
+		return []string{repoName + "-" + currentUser() + "-pr-", repoName + "-pr-"}
+	}
+	return []string{repoName + "-pr-"}
+}
+
 func (pm *PRManager) isPRWorktree(path, repoName string) bool {
 	baseName := filepath.Base(path)
-	expectedPrefix := repoName + "-pr-"
-	return strings.HasPrefix(baseName, expectedPrefix)
+	for _, prefix := range pm.prWorktreePrefixes(repoName) {
+		if strings.HasPrefix(baseName, prefix) {
+			return true
+		}
+	}
+	return false
 }
 
 func (pm *PRManager) extractPRNumber(path, repoName string) int {
 	baseName := filepath.Base(path)
-	expectedPrefix := repoName + "-pr-"
-
-	if !strings.HasPrefix(baseName, expectedPrefix) {
-		return 0
-	}
 
-	prNumberStr := strings.TrimPrefix(baseName, expectedPrefix)
-	if prNumber, err := parsePositiveInt(prNumberStr); err == nil {
-		return prNumber
+	for _, prefix := range pm.prWorktreePrefixes(repoName) {
+		if !strings.HasPrefix(baseName, prefix) {
+			continue
+		}
+		if prNumber, err := parsePositiveInt(strings.TrimPrefix(baseName, prefix)); err == nil {
+			return prNumber
+		}
 	}
 
 	return 0
@@ -353,18 +441,21 @@ func (pm *PRManager) buildPRHookContext(event types.HookEvent, branch, worktreeP
 		Environment:  make(map[string]string),
 	}
 
-	// Add PR-specific environment variables
-	ctx.Environment["WTREE_PR_NUMBER"] = fmt.Sprintf("%d", prInfo.Number)
-	ctx.Environment["WTREE_PR_TITLE"] = prInfo.Title
-	ctx.Environment["WTREE_PR_AUTHOR"] = prInfo.Author
-	ctx.Environment["WTREE_PR_URL"] = prInfo.URL
-	ctx.Environment["WTREE_PR_STATE"] = prInfo.State
-	ctx.Environment["WTREE_PR_HEAD_REF"] = prInfo.HeadRef
-	ctx.Environment["WTREE_PR_BASE_REF"] = prInfo.BaseRef
-
+	setPREnvironment(ctx.Environment, prInfo)
 	return ctx
 }
 
+// setPREnvironment adds the WTREE_PR_* variables for a PR to env
+func setPREnvironment(env map[string]string, prInfo *github.PRInfo) {
+	env["WTREE_PR_NUMBER"] = fmt.Sprintf("%d", prInfo.Number)
+	env["WTREE_PR_TITLE"] = prInfo.Title
+	env["WTREE_PR_AUTHOR"] = prInfo.Author
+	env["WTREE_PR_URL"] = prInfo.URL
+	env["WTREE_PR_STATE"] = prInfo.State
+	env["WTREE_PR_HEAD_REF"] = prInfo.HeadRef
+	env["WTREE_PR_BASE_REF"] = prInfo.BaseRef
+}
+
 func (pm *PRManager) storePRMetadata(worktreePath string, prInfo *github.PRInfo) error {
 	metadataPath := filepath.Join(worktreePath, ".wtree-pr.json")
 
@@ -0,0 +1,29 @@
+//go:build !windows
+
+package worktree
+
+import (
+	"os/exec"
+	"syscall"
+	"time"
+)
+
+// killProcessGroup runs command in a process group of its own and makes
+// cancelling its context stop the whole group, so children a hook started
+// (a dev server, npm's workers) don't outlive it. The group gets SIGTERM
+// first, to clean up, and SIGKILL if anything is left after hookKillGrace.
+func killProcessGroup(command *exec.Cmd) {
+	command.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	command.Cancel = func() error {
+		pgid := -command.Process.Pid
+		time.AfterFunc(hookKillGrace, func() { _ = syscall.Kill(pgid, syscall.SIGKILL) })
+		return syscall.Kill(pgid, syscall.SIGTERM)
+	}
+}
+
+// detachProcess starts command in a session of its own, without a
+// controlling terminal, so a background job keeps running when the terminal
+// that started it closes and its process group gets SIGHUP
+func detachProcess(command *exec.Cmd) {
+	command.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+}
@@ -0,0 +1,64 @@
+//go:build !windows
+
+package worktree
+
+import (
+	"context"
+	"os/exec"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKillProcessGroup(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	// The background sleep keeps the output pipe open unless it is killed too
+	command := exec.CommandContext(ctx, "sh", "-c", "sleep 30 & sleep 30")
+	killProcessGroup(command)
+
+	started := time.Now()
+	time.AfterFunc(200*time.Millisecond, cancel)
+	_, err := command.CombinedOutput()
+
+	assert.Error(t, err)
+	assert.Less(t, time.Since(started), 5*time.Second)
+}
+
+func TestKillProcessGroup_EscalatesToSIGKILL(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	// Ignoring SIGTERM is inherited, so only SIGKILL stops the group
+	command := exec.CommandContext(ctx, "sh", "-c", "trap '' TERM; sleep 30 & sleep 30")
+	killProcessGroup(command)
+
+	started := time.Now()
+	time.AfterFunc(200*time.Millisecond, cancel)
+	_, err := command.CombinedOutput()
+
+	assert.Error(t, err)
+	assert.GreaterOrEqual(t, time.Since(started), hookKillGrace)
+	assert.Less(t, time.Since(started), hookKillGrace+5*time.Second)
+}
+
+func TestDetachProcess(t *testing.T) {
+	command := exec.Command("sleep", "30")
+	detachProcess(command)
+	require.NoError(t, command.Start())
+	defer func() {
+		_ = command.Process.Kill()
+		_ = command.Wait()
+	}()
+
+	// Leading its own session, the job is out of reach of the SIGHUP sent
+	// when the terminal that started it closes
+	if _, err := exec.LookPath("ps"); err != nil {
+		t.Skip("ps is not available")
+	}
+	pid := strconv.Itoa(command.Process.Pid)
+	sid, err := exec.Command("ps", "-o", "sid=", "-p", pid).Output()
+	require.NoError(t, err)
+	assert.Equal(t, pid, strings.TrimSpace(string(sid)))
+}
@@ -0,0 +1,18 @@
+//go:build windows
+
+package worktree
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// killProcessGroup is a no-op on Windows: cancelling the context kills the
+// hook process itself
+func killProcessGroup(command *exec.Cmd) {}
+
+// detachProcess starts command in a process group of its own, so a
+// background job doesn't get the console's Ctrl+C
+func detachProcess(command *exec.Cmd) {
+	command.SysProcAttr = &syscall.SysProcAttr{CreationFlags: syscall.CREATE_NEW_PROCESS_GROUP}
+}
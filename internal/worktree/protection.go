@@ -0,0 +1,56 @@
+package worktree
+
+import (
+	"fmt"
+
+	"github.com/awhite/wtree/internal/github"
+	"github.com/awhite/wtree/pkg/types"
+)
+
+// checkBranchDeletable refuses to delete a branch GitHub reports as
+// protected or as the head of an open PR. Without a usable GitHub client
+// nothing is checked.
+func (m *Manager) checkBranchDeletable(branch string) error {
+	if m.github == nil || m.github.IsAvailable() != nil {
+		return nil
+	}
+	status, err := m.github.GetBranchStatus(branch)
+	if err != nil {
+		if m.globalConfig != nil && m.globalConfig.UI.Verbose {
+			m.warn("Could not check branch '%s' on GitHub: %v", branch, err)
+		}
+		return nil
+	}
+	return branchGuardError(branch, status)
+}
+
+// branchGuardError explains why a branch must be kept, or returns nil
+func branchGuardError(branch string, status *github.BranchStatus) error {
+	switch {
+	case status.Protected:
+		return types.NewValidationError("delete-branch",
+			fmt.Sprintf("branch '%s' is protected on GitHub; use --allow-protected to delete it anyway", branch), nil)
+	case status.OpenPR > 0:
+		return types.NewValidationError("delete-branch",
+			fmt.Sprintf("branch '%s' has an open PR (#%d); use --allow-protected to delete it anyway", branch, status.OpenPR), nil)
+	}
+	return nil
+}
+
+// keepProtectedBranches stops cleanup from deleting the branches of
+// candidates that are protected or in an open PR; their worktrees still go.
+// Branches are checked concurrently and reported in candidate order.
+func (m *Manager) keepProtectedBranches(candidates []CleanupCandidate) {
+	guards := make([]error, len(candidates))
+	forEachConcurrently(len(candidates), m.githubWorkers(), func(i int) {
+		if candidates[i].ShouldDeleteBranch {
+			guards[i] = m.checkBranchDeletable(candidates[i].Branch)
+		}
+	})
+	for i, err := range guards {
+		if err != nil {
+			m.warn("Keeping branch: %v", err)
+			candidates[i].ShouldDeleteBranch = false
+		}
+	}
+}
@@ -0,0 +1,17 @@
+package worktree
+
+import (
+	"testing"
+
+	"github.com/awhite/wtree/internal/github"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBranchGuardError(t *testing.T) {
+	assert.NoError(t, branchGuardError("feature", &github.BranchStatus{}))
+	assert.ErrorContains(t, branchGuardError("main", &github.BranchStatus{Protected: true}), "protected")
+	assert.ErrorContains(t, branchGuardError("feature", &github.BranchStatus{OpenPR: 42}), "open PR (#42)")
+
+	m := &Manager{}
+	assert.NoError(t, m.checkBranchDeletable("feature"), "nothing is checked without a GitHub client")
+}
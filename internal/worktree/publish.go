@@ -0,0 +1,78 @@
+package worktree
+
+import (
+	"fmt"
+
+	"github.com/awhite/wtree/pkg/types"
+)
+
+// defaultRemote is the remote new branches are pushed to: git's
+// remote.pushDefault, or "origin"
+func (m *Manager) defaultRemote() string {
+	if remote, err := m.repo.GetConfigValue("remote.pushDefault"); err == nil && remote != "" {
+		return remote
+	}
+	return "origin"
+}
+
+// Publish pushes a worktree's branch and sets it as the branch's upstream, so
+// ahead/behind and sync work for it. An empty identifier means the current
+// worktree.
+func (m *Manager) Publish(identifier string, options PublishOptions) error {
+	var wt *types.WorktreeInfo
+	var err error
+	if identifier == "" {
+		wt, err = m.currentWorktree()
+	} else {
+		wt, err = m.resolveWorktree(identifier)
+	}
+	if err != nil {
+		return err
+	}
+	if wt.Branch == "" {
+		return types.NewValidationError("publish",
+			fmt.Sprintf("HEAD is detached in %s; there is no branch to publish", wt.Path), nil)
+	}
+	if wt.Unborn {
+		return types.NewValidationError("publish",
+			fmt.Sprintf("'%s' has no commits yet", wt.Branch), nil)
+	}
+
+	remote := options.Remote
+	if remote == "" {
+		remote = m.defaultRemote()
+	}
+
+	if options.DryRun {
+		m.ui.Info("[DRY RUN] Would push '%s' to '%s' and set it as upstream", wt.Branch, remote)
+		return nil
+	}
+
+	m.ui.Info("Pushing '%s' to '%s'", wt.Branch, remote)
+	if err := m.repo.Push(wt.Path, remote, wt.Branch); err != nil {
+		return err
+	}
+
+	m.completed("Published %s (tracking %s/%s)", wt.Branch, remote, wt.Branch)
+	return nil
+}
+
+// setupNewBranchUpstream applies --track and --push-default to a branch
+// Create just made. Failures only warn: the worktree itself is fine.
+func (m *Manager) setupNewBranchUpstream(branch, worktreePath string, options CreateOptions) {
+	if options.Track != "" {
+		if err := m.repo.SetUpstream(branch, options.Track); err != nil {
+			m.warn("Failed to set upstream: %v", err)
+		} else {
+			m.ui.Info("Branch '%s' tracks '%s'", branch, options.Track)
+		}
+	}
+
+	if options.PushDefault {
+		remote := m.defaultRemote()
+		m.ui.Info("Pushing '%s' to '%s'", branch, remote)
+		if err := m.repo.Push(worktreePath, remote, branch); err != nil {
+			m.warn("Failed to push new branch: %v", err)
+		}
+	}
+}
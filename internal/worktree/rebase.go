@@ -0,0 +1,203 @@
+package worktree
+
+import (
+	"fmt"
+
+	"github.com/awhite/wtree/internal/git"
+	"github.com/awhite/wtree/pkg/types"
+)
+
+// Rebase rebases the current worktree's branch onto target, running the
+// pre_rebase and post_rebase hooks around it. A conflict leaves the rebase
+// stopped for the user to resolve and finish with options.Continue, which
+// runs post_rebase, or give up on with options.Abort.
+func (m *Manager) Rebase(target string, options RebaseOptions) error {
+	if err := m.validateRebaseOptions(target, options); err != nil {
+		return err
+	}
+
+	repoRoot, err := m.repo.GetRepoRoot()
+	if err != nil {
+		return err
+	}
+	operation, err := git.DetectOperationState(repoRoot)
+	if err != nil {
+		return fmt.Errorf("failed to check for an operation in progress: %w", err)
+	}
+	if options.Continue || options.Abort {
+		return m.resumeRebase(repoRoot, operation, options)
+	}
+	if operation != nil {
+		return types.NewValidationError("rebase", inProgressMessage(operation), nil)
+	}
+
+	head, err := m.repo.GetHeadState()
+	if err != nil {
+		return fmt.Errorf("failed to get current branch: %w", err)
+	}
+	if head.Detached {
+		return types.NewValidationError("rebase",
+			fmt.Sprintf("cannot rebase a detached HEAD (%s); check out a branch first", head), nil)
+	}
+	if head.Unborn {
+		return types.NewValidationError("rebase",
+			fmt.Sprintf("cannot rebase '%s': it has no commits yet", head.Branch), nil)
+	}
+	currentBranch := head.Branch
+
+	onto := target
+	if options.Onto != "" {
+		onto = options.Onto
+	}
+	m.ui.Header("Rebasing '%s' onto '%s'", currentBranch, onto)
+
+	isClean, err := m.repo.IsClean()
+	if err != nil {
+		return fmt.Errorf("failed to check repository status: %w", err)
+	}
+	if !isClean {
+		return types.NewValidationError("rebase",
+			"working directory must be clean before rebase; commit or stash your changes", nil)
+	}
+
+	if options.DryRun {
+		return m.previewRebase(currentBranch, target, options)
+	}
+
+	hookCtx := m.buildHookContext(types.HookPreRebase, currentBranch, repoRoot)
+	hookCtx.TargetBranch = onto
+	if err := m.executeHooks(types.HookPreRebase, hookCtx); err != nil {
+		return fmt.Errorf("pre-rebase hook failed: %w", err)
+	}
+
+	if err := m.repo.Rebase(repoRoot, target, m.resolveGitRebaseOptions(options)); err != nil {
+		if stopped, _ := git.DetectOperationState(repoRoot); stopped != nil && stopped.Kind == git.OperationRebase {
+			return types.NewGitError("rebase",
+				fmt.Sprintf("rebase stopped with conflicts (%s); resolve them, then run 'wtree rebase --continue' (or --abort)", stopped), err)
+		}
+		return fmt.Errorf("rebase failed: %w", err)
+	}
+
+	m.runPostRebaseHooks(hookCtx)
+	m.completed("Rebased '%s' onto '%s'", currentBranch, onto)
+	return nil
+}
+
+// resumeRebase continues or aborts the rebase stopped in the worktree at
+// repoRoot
+func (m *Manager) resumeRebase(repoRoot string, operation *git.OperationState, options RebaseOptions) error {
+	if operation == nil || operation.Kind != git.OperationRebase {
+		return types.NewValidationError("rebase", "no rebase in progress", nil)
+	}
+	if options.DryRun {
+		action := "continue"
+		if options.Abort {
+			action = "abort"
+		}
+		m.ui.Info("[DRY RUN] Would %s the rebase in progress (%s)", action, operation)
+		return nil
+	}
+
+	if options.Abort {
+		if err := m.repo.RebaseAbort(repoRoot); err != nil {
+			return err
+		}
+		m.completed("Rebase aborted; the branch is back where it started")
+		return nil
+	}
+
+	if err := m.repo.RebaseContinue(repoRoot); err != nil {
+		if stopped, _ := git.DetectOperationState(repoRoot); stopped != nil && stopped.Kind == git.OperationRebase {
+			return types.NewGitError("rebase",
+				fmt.Sprintf("rebase stopped again (%s); resolve the conflicts, then run 'wtree rebase --continue' (or --abort)", stopped), err)
+		}
+		return err
+	}
+
+	branch := ""
+	if head, err := m.repo.GetHeadState(); err == nil {
+		branch = head.Branch
+	}
+	m.runPostRebaseHooks(m.buildHookContext(types.HookPostRebase, branch, repoRoot))
+	m.completed("Rebase completed")
+	return nil
+}
+
+// runPostRebaseHooks runs post_rebase; the rebase is done, so a failure is
+// only a warning
+func (m *Manager) runPostRebaseHooks(hookCtx types.HookContext) {
+	hookCtx.Event = types.HookPostRebase
+	if err := m.executeHooks(types.HookPostRebase, hookCtx); err != nil {
+		m.warn("Post-rebase hook failed: %v", err)
+	}
+}
+
+// previewRebase shows what Rebase would do: how many commits would be
+// replayed and how far the target has moved on
+func (m *Manager) previewRebase(currentBranch, target string, options RebaseOptions) error {
+	ahead, behind, err := m.repo.AheadBehind(currentBranch, target)
+	if err != nil {
+		return err
+	}
+
+	if behind == 0 && options.Onto == "" {
+		m.completed("[DRY RUN] '%s' is already based on '%s'; nothing to rebase", currentBranch, target)
+		return nil
+	}
+	if options.Onto != "" {
+		m.ui.Info("[DRY RUN] Would replay the %d commits of '%s' after '%s' onto '%s'", ahead, currentBranch, target, options.Onto)
+	} else {
+		m.ui.Info("[DRY RUN] Would replay %d commits of '%s' onto '%s', which has %d new commits", ahead, currentBranch, target, behind)
+	}
+	if m.projectConfig != nil {
+		if hooks := len(m.projectConfig.Hooks[types.HookPreRebase]) + len(m.projectConfig.Hooks[types.HookPostRebase]); hooks > 0 {
+			m.ui.Info("[DRY RUN] Would run %d rebase hooks", hooks)
+		}
+	}
+	m.completed("[DRY RUN] Rebase preview completed")
+	return nil
+}
+
+// resolveGitRebaseOptions applies the wtree.signoff and wtree.gpgSign git
+// config defaults, as merges do
+func (m *Manager) resolveGitRebaseOptions(options RebaseOptions) git.RebaseOptions {
+	merge := m.resolveGitMergeOptions(MergeOptions{
+		Signoff:  options.Signoff,
+		GPGSign:  options.GPGSign,
+		GPGKeyID: options.GPGKeyID,
+	})
+	return git.RebaseOptions{
+		Onto:        options.Onto,
+		Interactive: options.Interactive,
+		Signoff:     merge.Signoff,
+		GPGSign:     merge.GPGSign,
+		GPGKeyID:    merge.GPGKeyID,
+	}
+}
+
+// validateRebaseOptions checks that a target is given unless a stopped
+// rebase is being resumed
+func (m *Manager) validateRebaseOptions(target string, options RebaseOptions) error {
+	if options.Continue && options.Abort {
+		return types.NewValidationError("rebase-options", "--continue and --abort cannot be used together", nil)
+	}
+	if options.Continue || options.Abort {
+		if target != "" || options.Onto != "" || options.Interactive {
+			return types.NewValidationError("rebase-options", "--continue and --abort take no target or other options", nil)
+		}
+		return nil
+	}
+	if target == "" {
+		return types.NewValidationError("rebase-options", "target branch is required", nil)
+	}
+	return nil
+}
+
+// inProgressMessage explains that an operation left in progress blocks a
+// new rebase, and how to get out of it
+func inProgressMessage(operation *git.OperationState) string {
+	if operation.Kind == git.OperationRebase {
+		return fmt.Sprintf("a rebase is already in progress (%s); run 'wtree rebase --continue' or 'wtree rebase --abort'", operation)
+	}
+	return fmt.Sprintf("%s is in progress; finish or abort it first", operation)
+}
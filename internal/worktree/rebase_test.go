@@ -0,0 +1,31 @@
+package worktree
+
+import (
+	"testing"
+
+	"github.com/awhite/wtree/internal/git"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestManager_validateRebaseOptions(t *testing.T) {
+	m := &Manager{}
+
+	assert.NoError(t, m.validateRebaseOptions("main", RebaseOptions{}))
+	assert.NoError(t, m.validateRebaseOptions("feature-a", RebaseOptions{Onto: "main", Interactive: true}))
+	assert.NoError(t, m.validateRebaseOptions("", RebaseOptions{Continue: true}))
+	assert.NoError(t, m.validateRebaseOptions("", RebaseOptions{Abort: true}))
+
+	assert.Error(t, m.validateRebaseOptions("", RebaseOptions{}))
+	assert.Error(t, m.validateRebaseOptions("", RebaseOptions{Continue: true, Abort: true}))
+	assert.Error(t, m.validateRebaseOptions("main", RebaseOptions{Continue: true}))
+	assert.Error(t, m.validateRebaseOptions("", RebaseOptions{Abort: true, Onto: "main"}))
+}
+
+func TestInProgressMessage(t *testing.T) {
+	rebase := &git.OperationState{Kind: git.OperationRebase, Current: 2, Total: 5}
+	assert.Contains(t, inProgressMessage(rebase), "REBASING 2/5")
+	assert.Contains(t, inProgressMessage(rebase), "wtree rebase --continue")
+
+	merge := &git.OperationState{Kind: git.OperationMerge}
+	assert.Equal(t, "MERGING is in progress; finish or abort it first", inProgressMessage(merge))
+}
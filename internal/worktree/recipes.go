@@ -0,0 +1,77 @@
+package worktree
+
+import (
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/awhite/wtree/pkg/types"
+)
+
+// recipePlan picks the program and arguments a recipe runs in dir. Recipes
+// run without a shell, so nothing in the worktree can inject commands.
+type recipePlan func(dir string) ([]string, error)
+
+// hookRecipes implements the recipes listed in types.HookRecipes
+var hookRecipes = map[string]recipePlan{
+	types.RecipeNodeInstall:     planNodeInstall,
+	types.RecipeGoModDownload:   requireFile("go.mod", "go", "mod", "download"),
+	types.RecipeBundleInstall:   requireFile("Gemfile", "bundle", "install"),
+	types.RecipeComposerInstall: requireFile("composer.json", "composer", "install", "--no-interaction"),
+}
+
+// nodeLockfiles maps lockfiles to the install command for their package
+// manager, in the order they are checked
+var nodeLockfiles = []struct {
+	file string
+	argv []string
+}{
+	{"pnpm-lock.yaml", []string{"pnpm", "install", "--frozen-lockfile"}},
+	{"yarn.lock", []string{"yarn", "install"}},
+	{"bun.lockb", []string{"bun", "install"}},
+	{"bun.lock", []string{"bun", "install"}},
+	{"package-lock.json", []string{"npm", "ci"}},
+	{"npm-shrinkwrap.json", []string{"npm", "ci"}},
+}
+
+// planNodeInstall installs with the package manager whose lockfile is present,
+// falling back to npm install
+func planNodeInstall(dir string) ([]string, error) {
+	if !fileExists(filepath.Join(dir, "package.json")) {
+		return nil, fmt.Errorf("no package.json in %s", dir)
+	}
+	for _, lock := range nodeLockfiles {
+		if fileExists(filepath.Join(dir, lock.file)) {
+			return lock.argv, nil
+		}
+	}
+	return []string{"npm", "install"}, nil
+}
+
+// requireFile returns a plan that runs argv when manifest exists in the worktree
+func requireFile(manifest string, argv ...string) recipePlan {
+	return func(dir string) ([]string, error) {
+		if !fileExists(filepath.Join(dir, manifest)) {
+			return nil, fmt.Errorf("no %s in %s", manifest, dir)
+		}
+		return argv, nil
+	}
+}
+
+// planRecipe resolves a recipe hook to the command it runs in dir
+func planRecipe(name, dir string) ([]string, error) {
+	plan, ok := hookRecipes[strings.TrimSpace(name)]
+	if !ok {
+		return nil, types.NewValidationError("hook-recipe",
+			fmt.Sprintf("unknown hook recipe '%s' (expected one of %s)", name, strings.Join(types.HookRecipes, ", ")), nil)
+	}
+	argv, err := plan(dir)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := exec.LookPath(argv[0]); err != nil {
+		return nil, fmt.Errorf("%s is not installed or not on PATH", argv[0])
+	}
+	return argv, nil
+}
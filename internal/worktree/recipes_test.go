@@ -0,0 +1,67 @@
+package worktree
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/awhite/wtree/pkg/types"
+)
+
+func TestHookRecipes_AllImplemented(t *testing.T) {
+	for _, name := range types.HookRecipes {
+		assert.Contains(t, hookRecipes, name)
+	}
+	assert.Len(t, hookRecipes, len(types.HookRecipes))
+}
+
+func TestPlanNodeInstall(t *testing.T) {
+	tests := []struct {
+		name     string
+		files    []string
+		expected []string
+		wantErr  bool
+	}{
+		{name: "no package.json", wantErr: true},
+		{name: "no lockfile", files: []string{"package.json"}, expected: []string{"npm", "install"}},
+		{name: "npm", files: []string{"package.json", "package-lock.json"}, expected: []string{"npm", "ci"}},
+		{name: "pnpm", files: []string{"package.json", "pnpm-lock.yaml"}, expected: []string{"pnpm", "install", "--frozen-lockfile"}},
+		{name: "yarn", files: []string{"package.json", "yarn.lock"}, expected: []string{"yarn", "install"}},
+		{name: "pnpm wins over npm", files: []string{"package.json", "package-lock.json", "pnpm-lock.yaml"}, expected: []string{"pnpm", "install", "--frozen-lockfile"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for _, file := range tt.files {
+				require.NoError(t, os.WriteFile(filepath.Join(dir, file), []byte("{}"), 0644))
+			}
+
+			argv, err := planNodeInstall(dir)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, argv)
+		})
+	}
+}
+
+func TestPlanRecipe(t *testing.T) {
+	dir := t.TempDir()
+
+	_, err := planRecipe("@nope", dir)
+	assert.Error(t, err)
+
+	_, err = planRecipe(types.RecipeGoModDownload, dir)
+	assert.Error(t, err, "go.mod is required")
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module x\n"), 0644))
+	argv, err := planRecipe(types.RecipeGoModDownload, dir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"go", "mod", "download"}, argv)
+}
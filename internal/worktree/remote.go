@@ -0,0 +1,360 @@
+package worktree
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/awhite/wtree/internal/remote"
+	"github.com/awhite/wtree/pkg/types"
+)
+
+// remoteRegistryFile is stored in the repository's git common directory
+const remoteRegistryFile = "wtree/remote-worktrees.json"
+
+// remotePatternChars limits copy/link patterns sent to a remote shell to
+// path and glob characters so they can be expanded without quoting. A
+// leading - is refused so no pattern reads as an option.
+var remotePatternChars = regexp.MustCompile(`^[A-Za-z0-9._/*?\[\]@+][A-Za-z0-9._/*?\[\]@+-]*$`)
+
+// RemoteWorktree records a worktree created on another machine with --host
+type RemoteWorktree struct {
+	Host      string    `json:"host"`
+	RepoPath  string    `json:"repo_path"`
+	Path      string    `json:"path"`
+	Branch    string    `json:"branch"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Location formats the remote worktree as an scp-style host:path
+func (rw RemoteWorktree) Location() string {
+	return rw.Host + ":" + rw.Path
+}
+
+// CreateRemote creates a worktree on a remote machine over SSH. Git commands,
+// file operations, and hooks run on the remote host using the local project
+// configuration, and the result is recorded in the remote worktree registry.
+func (m *Manager) CreateRemote(branchName string, options CreateOptions) error {
+	if err := m.validateCreateOptions(branchName, options); err != nil {
+		return err
+	}
+
+	runner, err := remote.NewRunner(options.Host)
+	if err != nil {
+		return err
+	}
+
+	remoteRepo := options.RemoteRepoPath
+	if remoteRepo == "" {
+		if remoteRepo, err = m.repo.GetRepoRoot(); err != nil {
+			return err
+		}
+	}
+	remoteRepo = filepath.ToSlash(remoteRepo)
+	dirName, err := m.worktreeDirName(branchName)
+	if err != nil {
+		return err
+	}
+	worktreePath := path.Join(path.Dir(remoteRepo), dirName)
+
+	m.ui.Header("Creating remote worktree for branch '%s' on %s", branchName, runner.Host())
+	m.warn("Remote worktrees are experimental")
+
+	if options.DryRun {
+		m.ui.Info("Would create %s:%s from repository %s", runner.Host(), worktreePath, remoteRepo)
+		return nil
+	}
+
+	if _, err := runner.Run(remoteRepo, nil, "git rev-parse --git-dir"); err != nil {
+		return types.NewGitError("create-remote-worktree",
+			fmt.Sprintf("no git repository at %s:%s", runner.Host(), remoteRepo), err)
+	}
+
+	if options.Note != "" {
+		if existing, err := runner.Run(remoteRepo, nil, "git config --get "+remote.Quote("branch."+branchName+".description")); err == nil {
+			if err := checkNoteReplace(branchName, string(existing), options); err != nil {
+				return err
+			}
+		}
+	}
+
+	if _, err := runner.Run("", nil, "test ! -e "+remote.Quote(worktreePath)); err != nil {
+		if !options.OverwritePath {
+			return types.NewValidationError("create-remote-worktree",
+				fmt.Sprintf("path already exists on %s: %s; remove it or use --overwrite-path", runner.Host(), worktreePath), nil)
+		}
+
+		// Overwriting, so remove the existing path as a local create would
+		m.warn("Removing existing path: %s:%s", runner.Host(), worktreePath)
+		if _, err := runner.Run("", nil, "rm -rf "+remote.Quote(worktreePath)); err != nil {
+			return types.NewFileSystemError("create-remote-worktree", worktreePath,
+				fmt.Sprintf("failed to remove existing path on %s", runner.Host()), err)
+		}
+	}
+
+	branchCreated := false
+	if _, err := runner.Run(remoteRepo, nil, "git rev-parse --verify --quiet "+remote.Quote("refs/heads/"+branchName)); err != nil {
+		if !options.CreateBranch {
+			return types.NewGitError("create-remote-worktree",
+				fmt.Sprintf("branch '%s' does not exist on %s", branchName, runner.Host()), nil)
+		}
+
+		m.ui.Info("Creating branch '%s' from '%s'", branchName, options.FromBranch)
+		if _, err := runner.Run(remoteRepo, nil, "git branch "+remote.Quote(branchName)+" "+remote.Quote(options.FromBranch)); err != nil {
+			return types.NewGitError("create-remote-branch", "failed to create branch on remote host", err)
+		}
+		branchCreated = true
+	}
+
+	rollback := func() {
+		m.warn("Rolling back remote worktree creation")
+		_, _ = runner.Run(remoteRepo, nil, "git worktree remove --force "+remote.Quote(worktreePath))
+		if branchCreated {
+			_, _ = runner.Run(remoteRepo, nil, "git branch -D "+remote.Quote(branchName))
+		}
+	}
+
+	hookCtx := types.HookContext{
+		Event:        types.HookPreCreate,
+		Branch:       branchName,
+		RepoPath:     remoteRepo,
+		WorktreePath: worktreePath,
+		Environment:  make(map[string]string),
+	}
+
+	// when: conditions are evaluated against the remote host's OS
+	output, err := runner.Run("", nil, "uname -s")
+	if err != nil {
+		if branchCreated {
+			_, _ = runner.Run(remoteRepo, nil, "git branch -D "+remote.Quote(branchName))
+		}
+		return fmt.Errorf("failed to detect remote OS: %w", err)
+	}
+	remoteOS := remoteGOOS(string(output))
+
+	if err := m.executeRemoteHooks(runner, remoteRepo, hookCtx, remoteOS); err != nil {
+		if branchCreated {
+			_, _ = runner.Run(remoteRepo, nil, "git branch -D "+remote.Quote(branchName))
+		}
+		return fmt.Errorf("pre-create hook failed: %w", err)
+	}
+
+	m.ui.Info("Creating worktree at: %s:%s", runner.Host(), worktreePath)
+	if _, err := runner.Run(remoteRepo, nil, "git worktree add "+remote.Quote(worktreePath)+" "+remote.Quote(branchName)); err != nil {
+		// Nothing was at the path before, so whatever a failed add left there goes
+		_, _ = runner.Run("", nil, "rm -rf "+remote.Quote(worktreePath))
+		if branchCreated {
+			_, _ = runner.Run(remoteRepo, nil, "git branch -D "+remote.Quote(branchName))
+		}
+		return types.NewGitError("create-remote-worktree", "failed to create worktree on remote host", err)
+	}
+
+	if err := m.remoteFileOperations(runner, remoteRepo, worktreePath, conditionEnv(hookCtx, remoteOS)); err != nil {
+		rollback()
+		return fmt.Errorf("file operations failed: %w", err)
+	}
+
+	hookCtx.Event = types.HookPostCreate
+	if err := m.executeRemoteHooks(runner, worktreePath, hookCtx, remoteOS); err != nil {
+		m.warn("Post-create hook failed, but worktree was created: %v", err)
+	}
+
+	if options.Note != "" {
+		if _, err := runner.Run(remoteRepo, nil, "git config "+remote.Quote("branch."+branchName+".description")+" "+remote.Quote(options.Note)); err != nil {
+			m.warn("Failed to save note: %v", err)
+		}
+	}
+
+	if err := m.recordRemoteWorktree(RemoteWorktree{
+		Host:      runner.Host(),
+		RepoPath:  remoteRepo,
+		Path:      worktreePath,
+		Branch:    branchName,
+		CreatedAt: time.Now(),
+	}); err != nil {
+		m.warn("Worktree created but could not be recorded: %v", err)
+	}
+
+	m.completed("Remote worktree created successfully: %s:%s", runner.Host(), worktreePath)
+	return nil
+}
+
+// executeRemoteHooks runs the configured hooks for ctx.Event on the remote host
+func (m *Manager) executeRemoteHooks(runner *remote.Runner, dir string, ctx types.HookContext, remoteOS string) error {
+	if m.projectConfig == nil || len(m.projectConfig.Hooks[ctx.Event]) == 0 {
+		return nil
+	}
+
+	executor := NewHookExecutor(m.projectConfig, 0, m.globalConfig.UI.Verbose)
+	m.hookChecks(executor, runner.Host()+":"+dir)
+	allowFailure := m.configMgr.ResolveAllowFailure(m.globalConfig, m.projectConfig)
+	env := hookEnvironment(ctx)
+
+	for _, hook := range m.projectConfig.HooksFor(ctx.Event, conditionEnv(ctx, remoteOS)) {
+		if types.IsHookRecipe(hook) {
+			return types.NewHookError(string(ctx.Event),
+				fmt.Sprintf("recipe '%s' runs locally only; use a shell command for remote hosts", hook), nil)
+		}
+		expanded, err := executor.expandCommand(hook, ctx)
+		if err != nil {
+			return types.NewHookError(string(ctx.Event), fmt.Sprintf("hook '%s' rejected", hook), err)
+		}
+		if err := executor.checkExpanded(hook, expanded); err != nil {
+			return types.NewHookError(string(ctx.Event), fmt.Sprintf("hook '%s' rejected", hook), err)
+		}
+		output, err := runner.Run(dir, env, expanded)
+		if m.globalConfig.UI.Verbose && len(output) > 0 {
+			m.ui.Info("%s", strings.TrimSpace(string(output)))
+		}
+		if err != nil {
+			if allowFailure {
+				m.warn("Hook failed on %s: %v", runner.Host(), err)
+				continue
+			}
+			return types.NewHookError(string(ctx.Event), fmt.Sprintf("hook '%s' failed on %s", hook, runner.Host()), err)
+		}
+	}
+
+	return nil
+}
+
+// remoteFileOperations applies copy_files and link_files on the remote host
+func (m *Manager) remoteFileOperations(runner *remote.Runner, repoPath, worktreePath string, env types.ConditionEnv) error {
+	if m.projectConfig == nil {
+		return nil
+	}
+
+	copyPatterns, copyMappings := types.SplitFileEntries(m.projectConfig.CopyEntries, env)
+	copyPatterns = append(append([]string{}, m.projectConfig.CopyFiles...), copyPatterns...)
+	linkPatterns, linkMappings := types.SplitFileEntries(m.projectConfig.LinkEntries, env)
+	linkPatterns = append(append([]string{}, m.projectConfig.LinkFiles...), linkPatterns...)
+
+	for _, pattern := range append(append([]string{}, copyPatterns...), linkPatterns...) {
+		if !remotePatternChars.MatchString(pattern) || strings.Contains(pattern, "..") {
+			return types.NewValidationError("remote-file-pattern",
+				fmt.Sprintf("pattern '%s' cannot be used with remote worktrees", pattern), nil)
+		}
+	}
+
+	if len(copyPatterns) > 0 {
+		m.progress("Copying files...")
+		excludes := ""
+		for _, ignore := range m.projectConfig.IgnoreFiles {
+			excludes += " --exclude=" + remote.Quote(ignore)
+		}
+		// Globs left unmatched stay literal; skip them rather than failing tar.
+		// Matches are passed as ./name so a file named like an option isn't one.
+		script := fmt.Sprintf(`set --; for f in %s; do [ -e "$f" ] && set -- "$@" "./$f"; done; [ $# -eq 0 ] || tar cf -%s "$@" | (cd %s && tar xf -)`,
+			strings.Join(copyPatterns, " "), excludes, remote.Quote(worktreePath))
+		if _, err := runner.Run(repoPath, nil, script); err != nil {
+			return fmt.Errorf("copy files failed: %w", err)
+		}
+	}
+
+	for _, mapping := range copyMappings {
+		script := fmt.Sprintf(`[ -e %[1]s ] || exit 0; mkdir -p "$(dirname %[2]s)" && cp -R %[1]s %[2]s`,
+			remote.Quote(path.Join(repoPath, mapping.From)), remote.Quote(path.Join(worktreePath, mapping.To)))
+		if _, err := runner.Run("", nil, script); err != nil {
+			return fmt.Errorf("copy %s -> %s failed: %w", mapping.From, mapping.To, err)
+		}
+	}
+
+	if len(linkPatterns) > 0 {
+		m.progress("Creating file links...")
+		script := fmt.Sprintf(`for f in %s; do [ -e "$f" ] || continue; mkdir -p %s/"$(dirname "./$f")" && ln -sfn %s/"$f" %s/"$f" || exit 1; done`,
+			strings.Join(linkPatterns, " "), remote.Quote(worktreePath), remote.Quote(repoPath), remote.Quote(worktreePath))
+		if _, err := runner.Run(repoPath, nil, script); err != nil {
+			return fmt.Errorf("link files failed: %w", err)
+		}
+	}
+
+	for _, mapping := range linkMappings {
+		script := fmt.Sprintf(`[ -e %[1]s ] || exit 0; mkdir -p "$(dirname %[2]s)" && ln -sfn %[1]s %[2]s`,
+			remote.Quote(path.Join(repoPath, mapping.From)), remote.Quote(path.Join(worktreePath, mapping.To)))
+		if _, err := runner.Run("", nil, script); err != nil {
+			return fmt.Errorf("link %s -> %s failed: %w", mapping.From, mapping.To, err)
+		}
+	}
+
+	return nil
+}
+
+// remoteGOOS maps `uname -s` output onto a GOOS name
+func remoteGOOS(uname string) string {
+	name := strings.ToLower(strings.TrimSpace(uname))
+	if strings.HasPrefix(name, "mingw") || strings.HasPrefix(name, "msys") || strings.HasPrefix(name, "cygwin") {
+		return "windows"
+	}
+	return name
+}
+
+// loadRemoteWorktrees reads the remote worktree registry
+func (m *Manager) loadRemoteWorktrees() ([]RemoteWorktree, error) {
+	registryPath, err := m.remoteRegistryPath()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(registryPath)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, types.NewFileSystemError("read-remote-registry", registryPath, "failed to read remote worktree registry", err)
+	}
+
+	var remotes []RemoteWorktree
+	if err := json.Unmarshal(data, &remotes); err != nil {
+		return nil, types.NewFileSystemError("read-remote-registry", registryPath, "invalid remote worktree registry", err)
+	}
+	return remotes, nil
+}
+
+// recordRemoteWorktree adds or replaces an entry in the remote worktree registry
+func (m *Manager) recordRemoteWorktree(entry RemoteWorktree) error {
+	remotes, err := m.loadRemoteWorktrees()
+	if err != nil {
+		return err
+	}
+
+	filtered := remotes[:0]
+	for _, rw := range remotes {
+		if rw.Host != entry.Host || rw.Path != entry.Path {
+			filtered = append(filtered, rw)
+		}
+	}
+	filtered = append(filtered, entry)
+
+	registryPath, err := m.remoteRegistryPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(registryPath), 0755); err != nil {
+		return types.NewFileSystemError("write-remote-registry", registryPath, "failed to create registry directory", err)
+	}
+
+	data, err := json.MarshalIndent(filtered, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(registryPath, data, 0644); err != nil {
+		return types.NewFileSystemError("write-remote-registry", registryPath, "failed to write remote worktree registry", err)
+	}
+	return nil
+}
+
+func (m *Manager) remoteRegistryPath() (string, error) {
+	commonDir, err := m.repo.GetGitCommonDir()
+	if err != nil {
+		return "", err
+	}
+	if m.multiUser() {
+		return filepath.Join(commonDir, strings.TrimSuffix(remoteRegistryFile, ".json")+"-"+currentUser()+".json"), nil
+	}
+	return filepath.Join(commonDir, remoteRegistryFile), nil
+}
@@ -0,0 +1,131 @@
+package worktree
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/awhite/wtree/internal/ui"
+	"github.com/awhite/wtree/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// remoteMockRepo keeps the remote worktree registry in a temporary directory
+type remoteMockRepo struct {
+	MockGitRepo
+	commonDir string
+}
+
+func (r *remoteMockRepo) GetGitCommonDir() (string, error) { return r.commonDir, nil }
+
+// newRemoteTestRepo puts an ssh on PATH that runs the remote script locally
+// and returns a "remote" repository with an initial commit
+func newRemoteTestRepo(t *testing.T) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("fake ssh needs a POSIX shell")
+	}
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+
+	bin := t.TempDir()
+	fakeSSH := "#!/bin/sh\nwhile [ \"$1\" != \"--\" ]; do shift; done\nexec sh -c \"$2\"\n"
+	require.NoError(t, os.WriteFile(filepath.Join(bin, "ssh"), []byte(fakeSSH), 0755))
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	root, err := filepath.EvalSymlinks(t.TempDir())
+	require.NoError(t, err)
+	repoPath := filepath.Join(root, "repo")
+	require.NoError(t, os.MkdirAll(repoPath, 0755))
+	for _, args := range [][]string{
+		{"init", "--quiet", "--initial-branch=main"},
+		{"-c", "user.name=wtree test", "-c", "user.email=test@example.com", "commit", "--quiet", "--allow-empty", "-m", "Initial commit"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repoPath
+		output, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(output))
+	}
+	return repoPath
+}
+
+func newRemoteManager(t *testing.T, projectConfig *types.ProjectConfig) *Manager {
+	return &Manager{
+		repo:          &remoteMockRepo{commonDir: t.TempDir()},
+		ui:            ui.NewManager(false, false),
+		globalConfig:  types.DefaultWTreeConfig(),
+		projectConfig: projectConfig,
+	}
+}
+
+func TestManager_CreateRemote(t *testing.T) {
+	repoPath := newRemoteTestRepo(t)
+	for name, content := range map[string]string{"a.local": "a", "-rf.local": "dash", "skip.local": "skip"} {
+		require.NoError(t, os.WriteFile(filepath.Join(repoPath, name), []byte(content), 0644))
+	}
+	projectConfig := types.DefaultProjectConfig()
+	projectConfig.CopyFiles = []string{"*.local"}
+	projectConfig.IgnoreFiles = []string{"skip.local"}
+	m := newRemoteManager(t, projectConfig)
+
+	options := CreateOptions{CreateBranch: true, FromBranch: "main", Host: "build@localhost", RemoteRepoPath: repoPath, Note: "remote spike"}
+	require.NoError(t, m.CreateRemote("feature", options))
+
+	worktreePath := filepath.Join(filepath.Dir(repoPath), "test-repo-feature")
+	assert.FileExists(t, filepath.Join(worktreePath, ".git"))
+	assert.FileExists(t, filepath.Join(worktreePath, "a.local"))
+	assert.FileExists(t, filepath.Join(worktreePath, "-rf.local"), "a file named like an option is copied as a file")
+	assert.NoFileExists(t, filepath.Join(worktreePath, "skip.local"))
+	description, err := exec.Command("git", "-C", repoPath, "config", "branch.feature.description").Output()
+	require.NoError(t, err)
+	assert.Equal(t, "remote spike\n", string(description))
+
+	remotes, err := m.loadRemoteWorktrees()
+	require.NoError(t, err)
+	require.Len(t, remotes, 1)
+	assert.Equal(t, RemoteWorktree{Host: "build@localhost", RepoPath: repoPath, Path: worktreePath, Branch: "feature", CreatedAt: remotes[0].CreatedAt}, remotes[0])
+
+	// The path is taken now
+	err = m.CreateRemote("feature", options)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "already exists")
+}
+
+func TestManager_CreateRemoteOverwritePath(t *testing.T) {
+	repoPath := newRemoteTestRepo(t)
+	worktreePath := filepath.Join(filepath.Dir(repoPath), "test-repo-feature")
+	stale := filepath.Join(worktreePath, "stale.txt")
+	require.NoError(t, os.MkdirAll(worktreePath, 0755))
+	require.NoError(t, os.WriteFile(stale, []byte("old"), 0644))
+	m := newRemoteManager(t, types.DefaultProjectConfig())
+
+	options := CreateOptions{CreateBranch: true, FromBranch: "main", Host: "localhost", RemoteRepoPath: repoPath}
+	err := m.CreateRemote("feature", options)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "--overwrite-path")
+	assert.FileExists(t, stale, "the path is left alone without --overwrite-path")
+
+	options.OverwritePath = true
+	require.NoError(t, m.CreateRemote("feature", options))
+	assert.FileExists(t, filepath.Join(worktreePath, ".git"))
+	assert.NoFileExists(t, stale)
+}
+
+func TestManager_CreateRemoteRejectsOptionLikePatterns(t *testing.T) {
+	repoPath := newRemoteTestRepo(t)
+	for _, pattern := range []string{"-rf", "--checkpoint-action=exec=sh", "../secrets", "a b", "$(id)"} {
+		projectConfig := types.DefaultProjectConfig()
+		projectConfig.CopyFiles = []string{pattern}
+		m := newRemoteManager(t, projectConfig)
+
+		err := m.CreateRemote("feature", CreateOptions{CreateBranch: true, FromBranch: "main", Host: "localhost", RemoteRepoPath: repoPath})
+		require.Error(t, err, pattern)
+		assert.Contains(t, err.Error(), "cannot be used with remote worktrees", pattern)
+		// Rolled back, so the next pattern starts over
+		assert.NoDirExists(t, filepath.Join(filepath.Dir(repoPath), "test-repo-feature"), pattern)
+	}
+}
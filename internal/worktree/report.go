@@ -0,0 +1,320 @@
+package worktree
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/awhite/wtree/internal/git"
+	"github.com/awhite/wtree/pkg/types"
+	"gopkg.in/yaml.v3"
+)
+
+// Output formats for --output. The structured ones print the report types
+// below instead of tables, for jq and scripts; field names are stable.
+const (
+	OutputText = "text"
+	OutputJSON = "json"
+	OutputYAML = "yaml"
+)
+
+// ValidateOutputFormat checks an --output value
+func ValidateOutputFormat(format string) error {
+	switch format {
+	case "", OutputText, OutputJSON, OutputYAML:
+		return nil
+	}
+	return types.NewValidationError("output",
+		fmt.Sprintf("unknown output format '%s' (expected text, json or yaml)", format), nil)
+}
+
+// WriteOutput encodes a report as JSON or YAML
+func WriteOutput(w io.Writer, format string, report interface{}) error {
+	switch format {
+	case OutputJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(report)
+	case OutputYAML:
+		encoder := yaml.NewEncoder(w)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(report); err != nil {
+			return err
+		}
+		return encoder.Close()
+	}
+	return ValidateOutputFormat(format)
+}
+
+// WorktreeReport is a worktree in list and status output
+type WorktreeReport struct {
+	Path       string           `json:"path" yaml:"path"`
+	Branch     string           `json:"branch,omitempty" yaml:"branch,omitempty"` // Empty when detached
+	Head       string           `json:"head,omitempty" yaml:"head,omitempty"`
+	Main       bool             `json:"main" yaml:"main"`
+	Current    bool             `json:"current" yaml:"current"` // The working directory is inside it
+	Detached   bool             `json:"detached" yaml:"detached"`
+	Locked     bool             `json:"locked" yaml:"locked"`
+	LockReason string           `json:"lock_reason,omitempty" yaml:"lock_reason,omitempty"`
+	Prunable   bool             `json:"prunable" yaml:"prunable"` // Its directory is gone
+	Upstream   string           `json:"upstream,omitempty" yaml:"upstream,omitempty"`
+	Host       string           `json:"host,omitempty" yaml:"host,omitempty"` // Set for worktrees created with --host
+	PR         int              `json:"pr,omitempty" yaml:"pr,omitempty"`
+	Note       string           `json:"note,omitempty" yaml:"note,omitempty"`
+	Tags       []string         `json:"tags,omitempty" yaml:"tags,omitempty"`
+	Created    *CreatedReport   `json:"created,omitempty" yaml:"created,omitempty"` // Left out for worktrees wtree didn't create
+	Status     *GitStatusReport `json:"status,omitempty" yaml:"status,omitempty"`   // Left out when not gathered
+}
+
+// CreatedReport is when and how wtree created a worktree
+type CreatedReport struct {
+	At      time.Time `json:"at" yaml:"at"`
+	By      string    `json:"by,omitempty" yaml:"by,omitempty"`
+	Base    string    `json:"base,omitempty" yaml:"base,omitempty"` // What a new branch was created from
+	Command string    `json:"command,omitempty" yaml:"command,omitempty"`
+}
+
+// GitStatusReport is the git status of a worktree
+type GitStatusReport struct {
+	Clean        bool   `json:"clean" yaml:"clean"`
+	ChangedFiles int    `json:"changed_files" yaml:"changed_files"`
+	Ahead        int    `json:"ahead" yaml:"ahead"` // Versus the upstream
+	Behind       int    `json:"behind" yaml:"behind"`
+	Operation    string `json:"operation,omitempty" yaml:"operation,omitempty"` // rebase, merge... in progress
+}
+
+// PRWorktreeReport is a PR worktree in pr list output
+type PRWorktreeReport struct {
+	Number  int        `json:"number" yaml:"number"`
+	Title   string     `json:"title,omitempty" yaml:"title,omitempty"`
+	Author  string     `json:"author,omitempty" yaml:"author,omitempty"`
+	State   string     `json:"state,omitempty" yaml:"state,omitempty"` // As recorded locally
+	URL     string     `json:"url,omitempty" yaml:"url,omitempty"`
+	Draft   bool       `json:"draft" yaml:"draft"`
+	Updated *time.Time `json:"updated,omitempty" yaml:"updated,omitempty"`
+	Branch  string     `json:"branch,omitempty" yaml:"branch,omitempty"`
+	Path    string     `json:"path" yaml:"path"`
+}
+
+// CleanupCandidateReport is a worktree cleanup --dry-run would remove
+type CleanupCandidateReport struct {
+	Branch        string     `json:"branch" yaml:"branch"`
+	Path          string     `json:"path" yaml:"path"`
+	Reason        string     `json:"reason" yaml:"reason"`
+	LastActivity  string     `json:"last_activity,omitempty" yaml:"last_activity,omitempty"` // As the table shows it
+	LastActiveAt  *time.Time `json:"last_active_at,omitempty" yaml:"last_active_at,omitempty"`
+	DeleteBranch  bool       `json:"delete_branch" yaml:"delete_branch"`
+	Dirty         bool       `json:"dirty" yaml:"dirty"`
+	Base          string     `json:"base,omitempty" yaml:"base,omitempty"` // What ahead and merge_base are relative to
+	Ahead         *int       `json:"ahead,omitempty" yaml:"ahead,omitempty"`
+	MergeBase     string     `json:"merge_base,omitempty" yaml:"merge_base,omitempty"`
+	MergeBaseTime *time.Time `json:"merge_base_time,omitempty" yaml:"merge_base_time,omitempty"`
+}
+
+// worktreeReport describes a worktree; status is nil when not gathered
+func (m *Manager) worktreeReport(wt *types.WorktreeInfo, status *git.WorktreeStatus, currentDir string) WorktreeReport {
+	report := WorktreeReport{
+		Path:       wt.Path,
+		Branch:     wt.Branch,
+		Head:       wt.Head,
+		Main:       wt.IsMainRepo,
+		Current:    currentDir != "" && strings.HasPrefix(currentDir, wt.Path),
+		Detached:   wt.Detached,
+		Locked:     wt.Locked,
+		LockReason: wt.LockReason,
+		Prunable:   wt.Prunable,
+		Upstream:   wt.Upstream,
+		PR:         m.marksFor(wt, nil).PRNumber,
+		Note:       m.worktreeNote(wt),
+		Tags:       worktreeTags(wt.Path),
+	}
+	if metadata := worktreeMetadata(wt.Path); metadata != nil {
+		report.Created = &CreatedReport{
+			At:      metadata.CreatedAt,
+			By:      metadata.CreatedBy,
+			Base:    metadata.Base,
+			Command: metadata.Command,
+		}
+	}
+	if status != nil {
+		report.Status = &GitStatusReport{
+			Clean:        status.IsClean,
+			ChangedFiles: status.ChangedFiles,
+			Ahead:        status.Ahead,
+			Behind:       status.Behind,
+		}
+		if status.Operation != nil {
+			report.Status.Operation = string(status.Operation.Kind)
+		}
+	}
+	return report
+}
+
+// ListReport returns what List shows, as data. Status is gathered with
+// ShowStatus or OnlyDirty; Summary, GroupBy and Porcelain don't apply.
+func (m *Manager) ListReport(options ListOptions) ([]WorktreeReport, error) {
+	worktrees, err := m.repo.ListWorktrees()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list worktrees: %w", err)
+	}
+	if options.Mine {
+		worktrees = m.filterMine(worktrees)
+	}
+	worktrees = filterTagged(worktrees, options.Tags)
+
+	currentDir, _ := os.Getwd()
+	reports := []WorktreeReport{}
+	for _, wt := range worktrees {
+		if options.BranchFilter != "" && !strings.Contains(wt.Branch, options.BranchFilter) {
+			continue
+		}
+		var status *git.WorktreeStatus
+		if options.ShowStatus || options.OnlyDirty {
+			if status, err = m.repo.GetWorktreeStatus(wt.Path); err != nil {
+				status = nil
+			}
+		}
+		if options.OnlyDirty && (wt.IsMainRepo || status == nil || status.IsClean) {
+			continue
+		}
+		reports = append(reports, m.worktreeReport(wt, status, currentDir))
+	}
+
+	if remotes, err := m.loadRemoteWorktrees(); err == nil && len(options.Tags) == 0 && !options.OnlyDirty {
+		for _, rw := range remotes {
+			if options.BranchFilter != "" && !strings.Contains(rw.Branch, options.BranchFilter) {
+				continue
+			}
+			reports = append(reports, WorktreeReport{Path: rw.Path, Branch: rw.Branch, Host: rw.Host})
+		}
+	}
+	return reports, nil
+}
+
+// StatusReport returns what Status shows, as data, with every worktree's
+// status. Check and Porcelain don't apply.
+func (m *Manager) StatusReport(options StatusOptions) ([]WorktreeReport, error) {
+	// Fetch messages would end up in the middle of the report
+	quiet := m.quietCopy()
+	quiet.autoFetch()
+
+	worktrees, err := m.repo.ListWorktrees()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list worktrees: %w", err)
+	}
+
+	currentDir, _ := os.Getwd()
+	reports := []WorktreeReport{}
+	for _, wt := range worktrees {
+		if options.BranchFilter != "" && !strings.Contains(wt.Branch, options.BranchFilter) {
+			continue
+		}
+		if options.CurrentOnly && !strings.HasPrefix(currentDir, wt.Path) {
+			continue
+		}
+		var status *git.WorktreeStatus
+		if !wt.IsMainRepo {
+			if status, err = m.repo.GetWorktreeStatus(wt.Path); err != nil {
+				status = nil
+			}
+		}
+		reports = append(reports, m.worktreeReport(wt, status, currentDir))
+	}
+	return reports, nil
+}
+
+// ListPRReport returns the PR worktrees pr list shows, as data
+func (pm *PRManager) ListPRReport() ([]PRWorktreeReport, error) {
+	prWorktrees, err := pm.ListPRWorktrees()
+	if err != nil {
+		return nil, err
+	}
+	reports := []PRWorktreeReport{}
+	for _, prWt := range prWorktrees {
+		report := PRWorktreeReport{
+			Number: prWt.PRNumber,
+			Title:  prWt.PRTitle,
+			Author: prWt.PRAuthor,
+			State:  prWt.PRState,
+			URL:    prWt.PRUrl,
+			Draft:  prWt.PRIsDraft,
+			Branch: prWt.Branch,
+			Path:   prWt.Path,
+		}
+		if !prWt.LastUpdate.IsZero() {
+			updated := prWt.LastUpdate
+			report.Updated = &updated
+		}
+		reports = append(reports, report)
+	}
+	return reports, nil
+}
+
+// CleanupReport returns the candidates cleanup --dry-run lists, as data.
+// Branches GitHub protects are reported with delete_branch false unless
+// AllowProtected is set, as cleanup would keep them.
+func (m *Manager) CleanupReport(options CleanupOptions) ([]CleanupCandidateReport, error) {
+	if options.OlderThan != "" {
+		if _, err := types.ParseDuration(options.OlderThan); err != nil {
+			return nil, types.NewValidationError("older-than", err.Error(), nil)
+		}
+	}
+	// Warnings would end up in the middle of the report
+	quiet := m.quietCopy()
+
+	worktrees, err := m.repo.ListWorktrees()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list worktrees: %w", err)
+	}
+	mainBranch := ""
+	for _, wt := range worktrees {
+		if wt.IsMainRepo {
+			mainBranch = wt.Branch
+		}
+	}
+	if options.Mine {
+		worktrees = m.filterMine(worktrees)
+	}
+	worktrees = filterTagged(worktrees, options.Tags)
+
+	candidates, err := quiet.findCleanupCandidates(worktrees, options)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find cleanup candidates: %w", err)
+	}
+	quiet.addCandidateFacts(candidates, mainBranch)
+	if !options.AllowProtected {
+		quiet.keepProtectedBranches(candidates)
+	}
+	quiet.markDirtyCandidates(candidates)
+
+	reports := []CleanupCandidateReport{}
+	for _, candidate := range candidates {
+		report := CleanupCandidateReport{
+			Branch:       candidate.Branch,
+			Path:         candidate.Path,
+			Reason:       candidate.Reason,
+			LastActivity: candidate.LastActivity,
+			DeleteBranch: candidate.ShouldDeleteBranch,
+			Dirty:        candidate.Dirty,
+		}
+		if !candidate.LastActiveAt.IsZero() {
+			when := candidate.LastActiveAt
+			report.LastActiveAt = &when
+		}
+		if facts := candidate.Facts; facts != nil {
+			ahead := facts.Ahead
+			report.Base = mainBranch
+			report.Ahead = &ahead
+			report.MergeBase = facts.MergeBase
+			if !facts.MergeBaseTime.IsZero() {
+				when := facts.MergeBaseTime
+				report.MergeBaseTime = &when
+			}
+		}
+		reports = append(reports, report)
+	}
+	return reports, nil
+}
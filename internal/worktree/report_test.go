@@ -0,0 +1,52 @@
+package worktree
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/awhite/wtree/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestWriteOutput(t *testing.T) {
+	reports := []WorktreeReport{{Path: "/repo", Branch: "main", Main: true, Status: &GitStatusReport{ChangedFiles: 2, Ahead: 1}}}
+
+	var out bytes.Buffer
+	require.NoError(t, WriteOutput(&out, OutputJSON, reports))
+	var decoded []map[string]interface{}
+	require.NoError(t, json.Unmarshal(out.Bytes(), &decoded))
+	assert.Equal(t, "main", decoded[0]["branch"])
+	assert.Equal(t, float64(2), decoded[0]["status"].(map[string]interface{})["changed_files"])
+
+	out.Reset()
+	require.NoError(t, WriteOutput(&out, OutputYAML, reports))
+	var fromYAML []WorktreeReport
+	require.NoError(t, yaml.Unmarshal(out.Bytes(), &fromYAML))
+	assert.Equal(t, reports, fromYAML)
+
+	assert.Error(t, WriteOutput(&out, "xml", reports))
+	assert.NoError(t, ValidateOutputFormat(OutputText))
+	assert.Error(t, ValidateOutputFormat("table"))
+}
+
+func TestManager_ListReport(t *testing.T) {
+	main := &types.WorktreeInfo{Path: t.TempDir(), Branch: "main", IsMainRepo: true}
+	feature := &types.WorktreeInfo{Path: filepath.Join(t.TempDir(), "feature"), Branch: "feature", Locked: true}
+	m := &Manager{repo: &stateMockRepo{worktrees: []*types.WorktreeInfo{main, feature}}}
+
+	reports, err := m.ListReport(ListOptions{BranchFilter: "feat"})
+	require.NoError(t, err)
+	require.Len(t, reports, 1)
+	assert.Equal(t, feature.Path, reports[0].Path)
+	assert.True(t, reports[0].Locked)
+	assert.Nil(t, reports[0].Status, "status is only gathered when asked for")
+
+	reports, err = m.ListReport(ListOptions{OnlyDirty: true})
+	require.NoError(t, err)
+	assert.NotNil(t, reports, "an empty report is an empty array, not null")
+	assert.Empty(t, reports)
+}
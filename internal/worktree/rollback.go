@@ -18,6 +18,15 @@ type RollbackManager struct {
 	failFast              bool
 	failFastExplicitlySet bool // Track if SetFailFast was explicitly called
 	lastError             error
+	results               []RollbackResult // Steps attempted by the last Execute
+	planDir               string           // Where the pending plan is persisted; "" to keep it in memory
+}
+
+// RollbackResult is the outcome of one step of an executed rollback
+type RollbackResult struct {
+	Description string
+	Err         error
+	Skipped     bool // Not attempted because a step it depends on failed
 }
 
 // RollbackOperation represents a single operation that can be rolled back
@@ -25,9 +34,10 @@ type RollbackOperation struct {
 	Type        RollbackType
 	Description string
 	Action      func() error
-	Critical    bool  // If true, failure of this operation stops the rollback
-	ID          int   // Unique ID for dependency tracking
-	DependsOn   []int // IDs of operations this depends on
+	Critical    bool     // If true, failure of this operation stops the rollback
+	ID          int      // Unique ID for dependency tracking
+	DependsOn   []int    // IDs of operations this depends on
+	Targets     []string // Path, branch or links the action applies to, for the persisted plan
 }
 
 // RollbackType defines the type of rollback operation
@@ -58,6 +68,7 @@ func (rm *RollbackManager) AddWorktreeCleanup(path string) int {
 	id := len(rm.operations)
 	op := RollbackOperation{
 		Type:        RollbackRemoveWorktree,
+		Targets:     []string{path},
 		Description: fmt.Sprintf("Remove worktree at %s", path),
 		Action: func() error {
 			return rm.repo.RemoveWorktree(path, true) // force removal
@@ -66,6 +77,7 @@ func (rm *RollbackManager) AddWorktreeCleanup(path string) int {
 		ID:       id,
 	}
 	rm.operations = append(rm.operations, op)
+	rm.persistPlan(rm.operations)
 	return id
 }
 
@@ -77,6 +89,7 @@ func (rm *RollbackManager) AddBranchCleanup(branch string) int {
 	id := len(rm.operations)
 	op := RollbackOperation{
 		Type:        RollbackDeleteBranch,
+		Targets:     []string{branch},
 		Description: fmt.Sprintf("Delete branch %s", branch),
 		Action: func() error {
 			return rm.repo.DeleteBranch(branch, true) // force deletion
@@ -85,6 +98,7 @@ func (rm *RollbackManager) AddBranchCleanup(branch string) int {
 		ID:       id,
 	}
 	rm.operations = append(rm.operations, op)
+	rm.persistPlan(rm.operations)
 	return id
 }
 
@@ -96,6 +110,7 @@ func (rm *RollbackManager) AddFileCleanup(path string) int {
 	id := len(rm.operations)
 	op := RollbackOperation{
 		Type:        RollbackRemoveFiles,
+		Targets:     []string{path},
 		Description: fmt.Sprintf("Remove files at %s", path),
 		Action: func() error {
 			return os.RemoveAll(path)
@@ -104,6 +119,7 @@ func (rm *RollbackManager) AddFileCleanup(path string) int {
 		ID:       id,
 	}
 	rm.operations = append(rm.operations, op)
+	rm.persistPlan(rm.operations)
 	return id
 }
 
@@ -115,6 +131,7 @@ func (rm *RollbackManager) AddLinkCleanup(links []string) int {
 	id := len(rm.operations)
 	op := RollbackOperation{
 		Type:        RollbackCleanupLinks,
+		Targets:     links,
 		Description: fmt.Sprintf("Remove %d symbolic links", len(links)),
 		Action: func() error {
 			for _, link := range links {
@@ -128,6 +145,7 @@ func (rm *RollbackManager) AddLinkCleanup(links []string) int {
 		ID:       id,
 	}
 	rm.operations = append(rm.operations, op)
+	rm.persistPlan(rm.operations)
 	return id
 }
 
@@ -136,6 +154,7 @@ func (rm *RollbackManager) Execute() error {
 	rm.mu.Lock()
 	defer rm.mu.Unlock()
 
+	rm.results = nil
 	if len(rm.operations) == 0 {
 		return nil
 	}
@@ -159,13 +178,15 @@ func (rm *RollbackManager) Execute() error {
 			}
 
 			// Execute the critical operation
-			if err := op.Action(); err != nil {
+			err := op.Action()
+			rm.results = append(rm.results, RollbackResult{Description: op.Description, Err: err})
+			if err != nil {
 				opError := fmt.Errorf("%s: %w", op.Description, err)
 				rm.lastError = opError
 
 				// If SetFailFast was explicitly called, fail immediately and don't execute non-critical operations
 				if rm.failFastExplicitlySet {
-					rm.clearOperations()
+					rm.keepUnfinished(executed)
 					return types.NewFileSystemError("rollback-critical-failure", "",
 						fmt.Sprintf("critical rollback operation failed: %s", op.Description), err)
 				} else {
@@ -192,7 +213,9 @@ func (rm *RollbackManager) Execute() error {
 				}
 
 				// Execute the non-critical operation
-				if err := op.Action(); err != nil {
+				err := op.Action()
+				rm.results = append(rm.results, RollbackResult{Description: op.Description, Err: err})
+				if err != nil {
 					opError := fmt.Errorf("%s: %w", op.Description, err)
 					errors = append(errors, opError)
 					failed[op.ID] = true
@@ -233,7 +256,9 @@ func (rm *RollbackManager) Execute() error {
 
 				if canExecute {
 					// Execute the operation
-					if err := op.Action(); err != nil {
+					err := op.Action()
+					rm.results = append(rm.results, RollbackResult{Description: op.Description, Err: err})
+					if err != nil {
 						opError := fmt.Errorf("%s: %w", op.Description, err)
 						errors = append(errors, opError)
 						failed[op.ID] = true
@@ -264,8 +289,9 @@ func (rm *RollbackManager) Execute() error {
 		}
 	}
 
-	// Clear operations after rollback attempt
-	rm.clearOperations()
+	// Clear operations after rollback attempt, keeping the unfinished ones
+	// on disk for 'wtree rollback --last'
+	rm.keepUnfinished(executed)
 
 	if len(errors) > 0 {
 		return types.NewFileSystemError("rollback", "",
@@ -283,8 +309,38 @@ func (rm *RollbackManager) Clear() {
 	rm.clearOperations()
 }
 
+// Results returns the outcome of each step of the last Execute, in the
+// order they ran
+func (rm *RollbackManager) Results() []RollbackResult {
+	rm.mu.RLock()
+	defer rm.mu.RUnlock()
+	return rm.results
+}
+
+// keepUnfinished clears the operations, still persisting those that were
+// not executed successfully, and reports skipped ones in the results
+func (rm *RollbackManager) keepUnfinished(executed map[int]bool) {
+	var unfinished []RollbackOperation
+	for _, op := range rm.operations {
+		if executed[op.ID] {
+			continue
+		}
+		unfinished = append(unfinished, op)
+		attempted := false
+		for _, result := range rm.results {
+			attempted = attempted || result.Description == op.Description
+		}
+		if !attempted {
+			rm.results = append(rm.results, RollbackResult{Description: op.Description, Skipped: true})
+		}
+	}
+	rm.clearOperations()
+	rm.persistPlan(unfinished)
+}
+
 // clearOperations clears operations without acquiring lock (internal use)
 func (rm *RollbackManager) clearOperations() {
+	rm.persistPlan(nil)
 	rm.operations = make([]RollbackOperation, 0)
 	rm.dependencies = make(map[int][]int)
 	// Don't clear lastError - it should persist after operations are cleared
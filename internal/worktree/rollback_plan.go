@@ -0,0 +1,198 @@
+package worktree
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/awhite/wtree/pkg/types"
+)
+
+// RollbackPlan is the pending rollback of a running operation, kept on disk
+// so that 'wtree rollback --last' can replay it if the process dies first
+type RollbackPlan struct {
+	PID     int            `json:"pid"`
+	Host    string         `json:"host"`
+	Updated time.Time      `json:"updated"`
+	Steps   []RollbackStep `json:"steps"`
+
+	path string // File the plan was read from
+}
+
+// RollbackStep is one persisted rollback operation
+type RollbackStep struct {
+	Type        RollbackType `json:"type"`
+	Description string       `json:"description"`
+	Targets     []string     `json:"targets"`
+}
+
+// SetPlanDir persists the pending rollback plan in dir, one file per process
+func (rm *RollbackManager) SetPlanDir(dir string) {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+	rm.planDir = dir
+}
+
+// persistPlan writes operations as this process's plan, removing the file
+// when there are none. Persisting is best effort: the rollback still runs
+// from memory when the plan can't be written.
+func (rm *RollbackManager) persistPlan(operations []RollbackOperation) {
+	if rm.planDir == "" {
+		return
+	}
+	path := filepath.Join(rm.planDir, strconv.Itoa(os.Getpid())+".json")
+	if len(operations) == 0 {
+		_ = os.Remove(path)
+		return
+	}
+
+	hostname, _ := os.Hostname()
+	plan := RollbackPlan{PID: os.Getpid(), Host: hostname, Updated: time.Now()}
+	for _, op := range operations {
+		plan.Steps = append(plan.Steps, RollbackStep{Type: op.Type, Description: op.Description, Targets: op.Targets})
+	}
+	data, err := json.MarshalIndent(plan, "", "  ")
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(rm.planDir, 0755); err != nil {
+		return
+	}
+	_ = os.WriteFile(path, data, 0644)
+}
+
+// loadPlan queues the steps of a persisted plan for Execute
+func (rm *RollbackManager) loadPlan(plan *RollbackPlan) error {
+	for _, step := range plan.Steps {
+		if len(step.Targets) == 0 {
+			return types.NewValidationError("rollback", fmt.Sprintf("step '%s' has no target", step.Description), nil)
+		}
+		switch step.Type {
+		case RollbackRemoveWorktree:
+			rm.AddWorktreeCleanup(step.Targets[0])
+		case RollbackDeleteBranch:
+			rm.AddBranchCleanup(step.Targets[0])
+		case RollbackRemoveFiles:
+			rm.AddFileCleanup(step.Targets[0])
+		case RollbackCleanupLinks:
+			rm.AddLinkCleanup(step.Targets)
+		default:
+			return types.NewValidationError("rollback", fmt.Sprintf("unknown rollback step type '%s'", step.Type), nil)
+		}
+	}
+	return nil
+}
+
+// interruptedPlans returns the persisted plans of processes on this host
+// that are gone, newest first. Plans of running processes, or of another
+// host that can't be checked, are left alone.
+func interruptedPlans(dir string) ([]*RollbackPlan, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	hostname, _ := os.Hostname()
+
+	var plans []*RollbackPlan
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		var plan RollbackPlan
+		if err := json.Unmarshal(data, &plan); err != nil {
+			continue
+		}
+		if plan.Host != hostname || processRunning(plan.PID) {
+			continue
+		}
+		plan.path = path
+		plans = append(plans, &plan)
+	}
+	sort.Slice(plans, func(i, j int) bool { return plans[i].Updated.After(plans[j].Updated) })
+	return plans, nil
+}
+
+// rollbackPlanDir returns where the repository's rollback plans are kept
+func (m *Manager) rollbackPlanDir() (string, error) {
+	commonDir, err := m.repo.GetGitCommonDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(commonDir, "wtree", "rollback"), nil
+}
+
+// runRollback reverts what a failed operation did so far, under the git
+// lock, and reports each step and its result
+func (m *Manager) runRollback() error {
+	err := m.withGitLock(m.rollback.Execute)
+	for _, result := range m.rollback.Results() {
+		switch {
+		case result.Skipped:
+			m.warn("  Skipped: %s (a step it depends on failed)", result.Description)
+		case result.Err != nil:
+			m.warn("  Failed: %s: %v", result.Description, result.Err)
+		default:
+			m.completed("  Reverted: %s", result.Description)
+		}
+	}
+	if err != nil {
+		m.warn("Rollback incomplete; retry with 'wtree rollback --last' once this command exits")
+	}
+	return err
+}
+
+// RollbackLast replays the rollback plan of the latest operation that was
+// interrupted, or whose rollback failed, before it finished
+func (m *Manager) RollbackLast(options RollbackOptions) error {
+	dir, err := m.rollbackPlanDir()
+	if err != nil {
+		return err
+	}
+	plans, err := interruptedPlans(dir)
+	if err != nil {
+		return types.NewFileSystemError("rollback", dir, "failed to read rollback plans", err)
+	}
+	if len(plans) == 0 {
+		m.completed("Nothing to roll back")
+		return nil
+	}
+
+	plan := plans[0]
+	m.ui.Header("Rollback of interrupted operation (pid %d, %s)", plan.PID, plan.Updated.Format("2006-01-02 15:04:05"))
+	for i := len(plan.Steps) - 1; i >= 0; i-- {
+		m.ui.InfoIndented("%s", plan.Steps[i].Description)
+	}
+	if len(plans) > 1 {
+		m.ui.Info("%d older interrupted operations remain; run again to roll them back", len(plans)-1)
+	}
+	if options.DryRun {
+		m.ui.Info("[DRY RUN] Would run these %d rollback steps", len(plan.Steps))
+		return nil
+	}
+	if !options.Yes {
+		if err := m.confirm("", fmt.Sprintf("Run these %d rollback steps?", len(plan.Steps))); err != nil {
+			return err
+		}
+	}
+
+	m.rollback.Clear()
+	if err := m.rollback.loadPlan(plan); err != nil {
+		m.rollback.Clear()
+		return err
+	}
+	// The steps now belong to this process's plan
+	if err := os.Remove(plan.path); err != nil && !os.IsNotExist(err) {
+		return types.NewFileSystemError("rollback", plan.path, "failed to claim rollback plan", err)
+	}
+
+	if err := m.runRollback(); err != nil {
+		return err
+	}
+	m.completed("Rolled back the interrupted operation")
+	return nil
+}
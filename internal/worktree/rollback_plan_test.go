@@ -0,0 +1,74 @@
+package worktree
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRollbackManager_PersistedPlan(t *testing.T) {
+	dir := t.TempDir()
+	mockRepo := &MockGitRepo{}
+	rm := NewRollbackManager(mockRepo)
+	rm.SetPlanDir(dir)
+
+	rm.AddBranchCleanup("feature")
+	rm.AddWorktreeCleanup("/wt/feature")
+	planPath := filepath.Join(dir, strconv.Itoa(os.Getpid())+".json")
+	data, err := os.ReadFile(planPath)
+	require.NoError(t, err)
+	var plan RollbackPlan
+	require.NoError(t, json.Unmarshal(data, &plan))
+	assert.Equal(t, []RollbackStep{
+		{Type: RollbackDeleteBranch, Description: "Delete branch feature", Targets: []string{"feature"}},
+		{Type: RollbackRemoveWorktree, Description: "Remove worktree at /wt/feature", Targets: []string{"/wt/feature"}},
+	}, plan.Steps)
+
+	// The plan of a running process is not interrupted
+	plans, err := interruptedPlans(dir)
+	require.NoError(t, err)
+	assert.Empty(t, plans)
+
+	require.NoError(t, rm.Execute())
+	assert.NoFileExists(t, planPath)
+	assert.Equal(t, []RollbackResult{
+		{Description: "Remove worktree at /wt/feature"},
+		{Description: "Delete branch feature"},
+	}, rm.Results())
+}
+
+func TestRollbackManager_ReplayInterruptedPlan(t *testing.T) {
+	dir := t.TempDir()
+
+	// A process that has exited
+	dead := exec.Command("true")
+	require.NoError(t, dead.Run())
+	hostname, _ := os.Hostname()
+	data, err := json.Marshal(RollbackPlan{
+		PID: dead.Process.Pid, Host: hostname, Updated: time.Now(),
+		Steps: []RollbackStep{
+			{Type: RollbackDeleteBranch, Description: "Delete branch feature", Targets: []string{"feature"}},
+			{Type: RollbackRemoveWorktree, Description: "Remove worktree at /wt/feature", Targets: []string{"/wt/feature"}},
+		},
+	})
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "1.json"), data, 0644))
+
+	plans, err := interruptedPlans(dir)
+	require.NoError(t, err)
+	require.Len(t, plans, 1)
+
+	mockRepo := &MockGitRepo{}
+	rm := NewRollbackManager(mockRepo)
+	require.NoError(t, rm.loadPlan(plans[0]))
+	require.NoError(t, rm.Execute())
+	assert.Equal(t, []string{"/wt/feature"}, mockRepo.removedWorktrees)
+	assert.Equal(t, []string{"feature"}, mockRepo.deletedBranches)
+}
@@ -21,19 +21,63 @@ type MockGitRepo struct {
 	deleteError      error
 }
 
-func (m *MockGitRepo) GetCurrentBranch() (string, error)                          { return "main", nil }
-func (m *MockGitRepo) BranchExists(name string) bool                              { return true }
-func (m *MockGitRepo) IsClean() (bool, error)                                     { return true, nil }
-func (m *MockGitRepo) GetRepoRoot() (string, error)                               { return "/repo", nil }
-func (m *MockGitRepo) GetRepoName() string                                        { return "test-repo" }
-func (m *MockGitRepo) GetParentDir() string                                       { return "/parent" }
-func (m *MockGitRepo) CreateBranch(name, from string) error                       { return nil }
-func (m *MockGitRepo) CreateWorktree(path, branch string) error                   { return nil }
-func (m *MockGitRepo) ListWorktrees() ([]*types.WorktreeInfo, error)              { return nil, nil }
-func (m *MockGitRepo) GetWorktreeStatus(path string) (*git.WorktreeStatus, error) { return nil, nil }
-func (m *MockGitRepo) Merge(branch string, message string) error                  { return nil }
-func (m *MockGitRepo) Checkout(branch string) error                               { return nil }
-func (m *MockGitRepo) Fetch(remote string, refspec ...string) error               { return nil }
+func (m *MockGitRepo) GetCurrentBranch() (string, error) { return "main", nil }
+func (m *MockGitRepo) GetHeadState() (*git.HeadState, error) {
+	return &git.HeadState{Branch: "main", Commit: "abc123"}, nil
+}
+func (m *MockGitRepo) BranchExists(name string) bool                 { return true }
+func (m *MockGitRepo) IsClean() (bool, error)                        { return true, nil }
+func (m *MockGitRepo) GetRepoRoot() (string, error)                  { return "/repo", nil }
+func (m *MockGitRepo) GetRepoName() string                           { return "test-repo" }
+func (m *MockGitRepo) GetParentDir() string                          { return "/parent" }
+func (m *MockGitRepo) CreateBranch(name, from string) error          { return nil }
+func (m *MockGitRepo) CreateWorktree(path, branch string) error      { return nil }
+func (m *MockGitRepo) ListWorktrees() ([]*types.WorktreeInfo, error) { return nil, nil }
+func (m *MockGitRepo) ListWorktreesWithStatus() ([]*types.WorktreeInfo, error) {
+	return m.ListWorktrees()
+}
+func (m *MockGitRepo) GetWorktreeStatus(path string) (*git.WorktreeStatus, error)    { return nil, nil }
+func (m *MockGitRepo) Merge(branch string, options git.MergeOptions) error           { return nil }
+func (m *MockGitRepo) GetConfigValue(key string) (string, error)                     { return "", nil }
+func (m *MockGitRepo) GetGitCommonDir() (string, error)                              { return "/repo/.git", nil }
+func (m *MockGitRepo) SetConfigValue(key, value string) error                        { return nil }
+func (m *MockGitRepo) ResolveRef(ref string) (string, error)                         { return "", nil }
+func (m *MockGitRepo) Rebase(path, upstream string, options git.RebaseOptions) error { return nil }
+func (m *MockGitRepo) RebaseContinue(path string) error                              { return nil }
+func (m *MockGitRepo) RebaseAbort(path string) error                                 { return nil }
+func (m *MockGitRepo) SetBranchDescription(branch, description string) error         { return nil }
+func (m *MockGitRepo) GetLastCommit(path string) (*git.CommitInfo, error)            { return nil, nil }
+func (m *MockGitRepo) IsBranchMerged(branch, into string) (bool, error)              { return false, nil }
+func (m *MockGitRepo) AheadBehind(branch, base string) (int, int, error)             { return 0, 0, nil }
+func (m *MockGitRepo) BranchFacts(branches []string, base string) (map[string]*git.BranchFacts, error) {
+	return map[string]*git.BranchFacts{}, nil
+}
+func (m *MockGitRepo) Checkout(branch string) error                        { return nil }
+func (m *MockGitRepo) Fetch(remote string, refspec ...string) error        { return nil }
+func (m *MockGitRepo) DescribeChanges(path string) (string, error)         { return "", nil }
+func (m *MockGitRepo) ChangedPaths(path string) ([]string, error)          { return nil, nil }
+func (m *MockGitRepo) DetachHead(path, commit string) error                { return nil }
+func (m *MockGitRepo) DiffFromMergeBase(path, base string) (string, error) { return "", nil }
+func (m *MockGitRepo) PreviewMerge(branch string) (*git.MergePreview, error) {
+	return &git.MergePreview{}, nil
+}
+func (m *MockGitRepo) Stash(path, message string) error { return nil }
+func (m *MockGitRepo) StashPaths(path, message string, paths []string) (string, error) {
+	return "", nil
+}
+func (m *MockGitRepo) StashApply(path, stash string) error       { return nil }
+func (m *MockGitRepo) StashDrop(stash string) error              { return nil }
+func (m *MockGitRepo) StashPop(path, stash string) error         { return nil }
+func (m *MockGitRepo) FindStash(message string) (string, error)  { return "", nil }
+func (m *MockGitRepo) SetUpstream(branch, upstream string) error { return nil }
+func (m *MockGitRepo) Push(path, remote, branch string) error    { return nil }
+func (m *MockGitRepo) PruneWorktrees() error                     { return nil }
+func (m *MockGitRepo) FormatPatches(base, branch, dir string) ([]string, error) {
+	return nil, nil
+}
+func (m *MockGitRepo) CreateBundle(file, base, branch string) error     { return nil }
+func (m *MockGitRepo) BundleHeads(file string) ([]string, error)        { return nil, nil }
+func (m *MockGitRepo) ApplyPatches(path string, patches []string) error { return nil }
 
 func (m *MockGitRepo) RemoveWorktree(path string, force bool) error {
 	if m.removeError != nil {
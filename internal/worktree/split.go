@@ -0,0 +1,138 @@
+package worktree
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/awhite/wtree/internal/git"
+	"github.com/awhite/wtree/pkg/types"
+)
+
+// currentWorktree returns the worktree containing the current directory
+func (m *Manager) currentWorktree() (*types.WorktreeInfo, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current directory: %w", err)
+	}
+
+	worktrees, err := m.repo.ListWorktrees()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list worktrees: %w", err)
+	}
+
+	// Prefer the deepest match: linked worktrees may live inside the main one
+	var current *types.WorktreeInfo
+	for _, wt := range worktrees {
+		if pathWithin(cwd, wt.Path) && (current == nil || len(wt.Path) > len(current.Path)) {
+			current = wt
+		}
+	}
+	if current == nil {
+		return nil, types.NewValidationError("current-worktree",
+			fmt.Sprintf("%s is not inside a worktree", cwd), nil)
+	}
+	return current, nil
+}
+
+// Split moves uncommitted changes to paths (all changes when empty) out of
+// the current worktree into a new worktree on a new branch started from the
+// current commit, leaving those paths clean here
+func (m *Manager) Split(newBranch string, paths []string, options SplitOptions) error {
+	if newBranch == "" {
+		return types.NewValidationError("split", "branch name cannot be empty", nil)
+	}
+	if m.repo.BranchExists(newBranch) {
+		return types.NewValidationError("split",
+			fmt.Sprintf("branch '%s' already exists", newBranch), nil)
+	}
+
+	source, err := m.currentWorktree()
+	if err != nil {
+		return err
+	}
+	// The new branch starts where the source worktree is, not the main one
+	head := &git.HeadState{Branch: source.Branch, Commit: source.Head, Detached: source.Detached, Unborn: source.Unborn}
+	if head.Unborn {
+		return types.NewValidationError("split",
+			fmt.Sprintf("'%s' has no commits yet; make an initial commit before splitting", head.Branch), nil)
+	}
+
+	what := "all uncommitted changes"
+	switch len(paths) {
+	case 0:
+	case 1:
+		what = "changes to " + paths[0]
+	default:
+		what = fmt.Sprintf("changes to %d paths", len(paths))
+	}
+
+	if options.DryRun {
+		m.ui.Info("[DRY RUN] Would stash %s in %s", what, source.Path)
+		m.ui.Info("[DRY RUN] Would create branch '%s' at %s and a worktree for it", newBranch, head)
+		m.ui.Info("[DRY RUN] Would apply the stashed changes there")
+		return nil
+	}
+
+	m.ui.Header("Splitting %s into '%s'", what, newBranch)
+
+	// Paths are relative to where the user is, so stash from there
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+	stash, err := m.repo.StashPaths(cwd, "wtree split: "+newBranch, paths)
+	if err != nil {
+		return err
+	}
+	if stash == "" {
+		return types.NewValidationError("split", "no uncommitted changes to split", nil)
+	}
+
+	// Put the changes back where they came from if they can't move
+	restore := func(cause error) error {
+		if err := m.repo.StashApply(source.Path, stash); err != nil {
+			m.ui.Error("Failed to restore your changes; they are kept in the stash as %s", stash)
+			return cause
+		}
+		_ = m.repo.StashDrop(stash)
+		m.warn("Changes restored to %s", source.Path)
+		return cause
+	}
+
+	createOptions := CreateOptions{
+		CreateBranch: true,
+		FromBranch:   head.Commit,
+		OpenEditor:   options.OpenEditor,
+	}
+	if err := m.Create(newBranch, createOptions); err != nil {
+		return restore(err)
+	}
+
+	// Create is done with its rollback; undo the worktree and branch here
+	// if the changes can't be moved into them
+	m.rollback.Clear()
+	defer m.rollback.Clear()
+	m.rollback.AddBranchCleanup(newBranch)
+	abort := func(cause error) error {
+		err := restore(cause)
+		m.warn("Removing the worktree and branch created for the split")
+		_ = m.runRollback()
+		return err
+	}
+
+	target, err := m.resolveWorktree(newBranch)
+	if err != nil {
+		return abort(err)
+	}
+	m.rollback.AddWorktreeCleanup(target.Path)
+	if err := m.repo.StashApply(target.Path, stash); err != nil {
+		m.warn("Could not apply the changes in %s", target.Path)
+		return abort(err)
+	}
+	if err := m.repo.StashDrop(stash); err != nil {
+		m.warn("Changes moved, but the stash entry was kept: %v", err)
+	}
+
+	m.completed("Moved %s to %s", what, target.Path)
+	return nil
+}
@@ -0,0 +1,319 @@
+package worktree
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/awhite/wtree/internal/git"
+	"github.com/awhite/wtree/pkg/types"
+)
+
+// Git config keys under branch.<name> recording a stacked branch's parent,
+// and the parent commit it was last based on so restack can replay only the
+// branch's own commits
+const (
+	stackParentKey = "wtree-parent"
+	stackBaseKey   = "wtree-parent-base"
+)
+
+// StackBranch is one branch of a stack with the branches stacked on it
+type StackBranch struct {
+	Branch       string
+	Parent       string // Empty for the stack's root, e.g. main
+	Base         string // Parent commit the branch was last based on
+	Worktree     *types.WorktreeInfo
+	NeedsRestack bool // The parent has moved since the branch was based on it
+	Children     []*StackBranch
+}
+
+// walk visits the stack depth first, parents before their children
+func (s *StackBranch) walk(visit func(branch *StackBranch, depth int) bool) {
+	var walk func(branch *StackBranch, depth int)
+	walk = func(branch *StackBranch, depth int) {
+		if !visit(branch, depth) {
+			return
+		}
+		for _, child := range branch.Children {
+			walk(child, depth+1)
+		}
+	}
+	walk(s, 0)
+}
+
+// stackConfig returns branch.<branch>.<key>
+func (m *Manager) stackConfig(branch, key string) string {
+	value, _ := m.repo.GetConfigValue("branch." + branch + "." + key)
+	return value
+}
+
+// SetStackParent records branch as stacked on parent, based on parent's
+// current commit
+func (m *Manager) SetStackParent(branch, parent string) error {
+	if branch == parent {
+		return types.NewValidationError("stack", fmt.Sprintf("'%s' can't be stacked on itself", branch), nil)
+	}
+	for ancestor := parent; ancestor != ""; ancestor = m.stackConfig(ancestor, stackParentKey) {
+		if ancestor == branch {
+			return types.NewValidationError("stack",
+				fmt.Sprintf("stacking '%s' on '%s' would make a cycle", branch, parent), nil)
+		}
+	}
+	base, err := m.repo.ResolveRef(parent)
+	if err != nil {
+		return err
+	}
+	if err := m.repo.SetConfigValue("branch."+branch+"."+stackParentKey, parent); err != nil {
+		return err
+	}
+	return m.repo.SetConfigValue("branch."+branch+"."+stackBaseKey, base)
+}
+
+// LoadStack returns the stack containing branch, from its root down
+func (m *Manager) LoadStack(branch string) (*StackBranch, error) {
+	branches, err := m.repo.ListBranches()
+	if err != nil {
+		return nil, err
+	}
+	worktrees, err := m.repo.ListWorktrees()
+	if err != nil {
+		return nil, err
+	}
+	byBranch := make(map[string]*types.WorktreeInfo)
+	for _, wt := range worktrees {
+		if wt.Branch != "" {
+			byBranch[wt.Branch] = wt
+		}
+	}
+
+	nodes := make(map[string]*StackBranch)
+	node := func(name string) *StackBranch {
+		if nodes[name] == nil {
+			nodes[name] = &StackBranch{Branch: name, Worktree: byBranch[name]}
+		}
+		return nodes[name]
+	}
+	for _, name := range branches {
+		parent := m.stackConfig(name, stackParentKey)
+		if parent == "" {
+			continue
+		}
+		child := node(name)
+		child.Parent = parent
+		child.Base = m.stackConfig(name, stackBaseKey)
+		node(parent).Children = append(node(parent).Children, child)
+	}
+	if nodes[branch] == nil {
+		return nil, types.NewValidationError("stack",
+			fmt.Sprintf("'%s' is not part of a stack (create one with 'wtree stack create')", branch), nil)
+	}
+
+	root := nodes[branch]
+	for seen := map[string]bool{}; root.Parent != "" && !seen[root.Parent]; root = node(root.Parent) {
+		seen[root.Branch] = true
+	}
+	root.walk(func(b *StackBranch, depth int) bool {
+		sort.Slice(b.Children, func(i, j int) bool { return b.Children[i].Branch < b.Children[j].Branch })
+		if b.Parent != "" {
+			head, err := m.repo.ResolveRef(b.Parent)
+			b.NeedsRestack = err == nil && head != b.Base
+		}
+		return true
+	})
+	return root, nil
+}
+
+// stackBranch returns branch, or the current worktree's branch when empty
+func (m *Manager) stackBranch(branch string) (string, error) {
+	if branch != "" {
+		return branch, nil
+	}
+	wt, err := m.currentWorktree()
+	if err != nil {
+		return "", err
+	}
+	if wt.Branch == "" {
+		return "", types.NewValidationError("stack",
+			fmt.Sprintf("HEAD is detached in %s; name a branch of the stack", wt.Path), nil)
+	}
+	return wt.Branch, nil
+}
+
+// CreateStack creates a worktree for each of branches, each stacked on the
+// one before it and the first on base. Existing branches and worktrees are
+// reused, so a stack can be extended by naming it again with more branches.
+func (m *Manager) CreateStack(base string, branches []string, options CreateOptions) error {
+	if _, err := m.repo.ResolveRef(base); err != nil {
+		return err
+	}
+
+	parent := base
+	for _, branch := range branches {
+		if options.DryRun {
+			m.ui.Info("[DRY RUN] Would create '%s' stacked on '%s'", branch, parent)
+			parent = branch
+			continue
+		}
+		// Create reuses a worktree the branch already has
+		branchOptions := options
+		branchOptions.CreateBranch = true
+		branchOptions.FromBranch = parent
+		if err := m.Create(branch, branchOptions); err != nil {
+			return fmt.Errorf("failed to create '%s' in the stack: %w", branch, err)
+		}
+		if m.stackConfig(branch, stackParentKey) != parent {
+			if err := m.SetStackParent(branch, parent); err != nil {
+				return err
+			}
+		}
+		parent = branch
+	}
+
+	if options.DryRun {
+		return nil
+	}
+	m.completed("Stack %s ready", strings.Join(append([]string{base}, branches...), " → "))
+	return nil
+}
+
+// ShowStack prints the stack containing branch as a tree. An empty branch
+// means the current worktree's.
+func (m *Manager) ShowStack(branch string) error {
+	branch, err := m.stackBranch(branch)
+	if err != nil {
+		return err
+	}
+	root, err := m.LoadStack(branch)
+	if err != nil {
+		return err
+	}
+
+	root.walk(func(b *StackBranch, depth int) bool {
+		line := b.Branch
+		if depth > 0 {
+			line = strings.Repeat("   ", depth-1) + "└─ " + b.Branch
+		}
+		if b.Branch == branch {
+			line = m.ui.Bold(line)
+		}
+		var notes []string
+		if b.Worktree != nil {
+			notes = append(notes, b.Worktree.Path)
+		} else if depth > 0 {
+			notes = append(notes, "no worktree")
+		}
+		if b.NeedsRestack {
+			notes = append(notes, m.ui.Yellow("needs restack"))
+		}
+		if len(notes) > 0 {
+			line += "  " + m.ui.Gray("("+strings.Join(notes, ", ")+")")
+		}
+		fmt.Println(line)
+		return true
+	})
+	return nil
+}
+
+// Restack rebases each branch of the stack containing branch onto its
+// parent, parents first. A branch that can't be rebased (conflict, no
+// worktree, uncommitted changes) is reported and its descendants are left
+// alone; other branches continue. Rerun after resolving to finish.
+func (m *Manager) Restack(branch string, options RestackOptions) error {
+	branch, err := m.stackBranch(branch)
+	if err != nil {
+		return err
+	}
+	root, err := m.LoadStack(branch)
+	if err != nil {
+		return err
+	}
+	if !options.DryRun {
+		release, err := m.acquireOperationLock(LockTypeRestack, m.mainWorktreePath())
+		if err != nil {
+			return err
+		}
+		defer release()
+	}
+
+	m.ui.Header("Restacking %s", root.Branch)
+	restacked, failed := 0, 0
+	root.walk(func(b *StackBranch, depth int) bool {
+		if depth == 0 {
+			return true
+		}
+		indent := strings.Repeat("  ", depth-1)
+		if problem := m.restackProblem(b); problem != "" {
+			m.warn("%s%s: %s; skipping it and the branches stacked on it", indent, b.Branch, problem)
+			failed++
+			return false
+		}
+		if !b.NeedsRestack {
+			m.ui.Info("%s%s is up to date with %s", indent, b.Branch, b.Parent)
+			return true
+		}
+		if options.DryRun {
+			m.ui.Info("%s[DRY RUN] Would rebase %s onto %s", indent, b.Branch, b.Parent)
+			return true
+		}
+
+		head, err := m.repo.ResolveRef(b.Parent)
+		if err != nil {
+			m.warn("%s%s: %v", indent, b.Branch, err)
+			failed++
+			return false
+		}
+		m.progress("%sRebasing %s onto %s", indent, b.Branch, b.Parent)
+		upstream := b.Base
+		if upstream == "" {
+			upstream = b.Parent
+		}
+		if err := m.repo.Rebase(b.Worktree.Path, upstream, git.RebaseOptions{Onto: b.Parent}); err != nil {
+			m.warn("%s%s has conflicts with %s; resolve them in %s and run 'git rebase --continue' (or --abort), then restack again",
+				indent, b.Branch, b.Parent, b.Worktree.Path)
+			failed++
+			return false
+		}
+		if err := m.repo.SetConfigValue("branch."+b.Branch+"."+stackBaseKey, head); err != nil {
+			m.warn("%s%s: %v", indent, b.Branch, err)
+		}
+		// Children compare against the rebased branch from here on
+		for _, child := range b.Children {
+			child.NeedsRestack = true
+		}
+		m.completed("%s%s rebased onto %s", indent, b.Branch, b.Parent)
+		restacked++
+		return true
+	})
+
+	if failed > 0 {
+		return fmt.Errorf("%d branch(es) of the stack could not be restacked", failed)
+	}
+	if !options.DryRun {
+		m.completed("Restacked %d branch(es)", restacked)
+	}
+	return nil
+}
+
+// restackProblem reports why a stacked branch can't be rebased now
+func (m *Manager) restackProblem(b *StackBranch) string {
+	if !m.repo.BranchExists(b.Parent) {
+		return fmt.Sprintf("parent '%s' no longer exists", b.Parent)
+	}
+	if !b.NeedsRestack {
+		return ""
+	}
+	if b.Worktree == nil {
+		return "it has no worktree to rebase in"
+	}
+	status, err := m.repo.GetWorktreeStatus(b.Worktree.Path)
+	if err != nil {
+		return err.Error()
+	}
+	if status.Operation != nil {
+		return fmt.Sprintf("a %s is in progress in %s", status.Operation, b.Worktree.Path)
+	}
+	if !status.IsClean {
+		return "it has uncommitted changes"
+	}
+	return ""
+}
@@ -0,0 +1,60 @@
+package worktree
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/awhite/wtree/pkg/types"
+)
+
+// stackMockRepo serves branches, refs and git config from maps
+type stackMockRepo struct {
+	MockGitRepo
+	branches []string
+	refs     map[string]string
+	config   map[string]string
+}
+
+func (r *stackMockRepo) ListBranches() ([]string, error) { return r.branches, nil }
+func (r *stackMockRepo) ListWorktrees() ([]*types.WorktreeInfo, error) {
+	return []*types.WorktreeInfo{{Path: "/wt/api", Branch: "api"}}, nil
+}
+func (r *stackMockRepo) GetConfigValue(key string) (string, error) { return r.config[key], nil }
+func (r *stackMockRepo) SetConfigValue(key, value string) error {
+	r.config[key] = value
+	return nil
+}
+func (r *stackMockRepo) ResolveRef(ref string) (string, error) { return r.refs[ref], nil }
+
+func TestManager_LoadStack(t *testing.T) {
+	repo := &stackMockRepo{
+		branches: []string{"main", "api", "ui", "docs", "other"},
+		refs:     map[string]string{"main": "m2", "api": "a1", "ui": "u1", "docs": "d1"},
+		config:   map[string]string{},
+	}
+	m := &Manager{repo: repo}
+	require.NoError(t, m.SetStackParent("api", "main"))
+	require.NoError(t, m.SetStackParent("ui", "api"))
+	require.NoError(t, m.SetStackParent("docs", "api"))
+	repo.refs["main"] = "m3"
+
+	root, err := m.LoadStack("ui")
+	require.NoError(t, err)
+	assert.Equal(t, "main", root.Branch)
+	require.Len(t, root.Children, 1)
+
+	api := root.Children[0]
+	assert.Equal(t, "api", api.Branch)
+	assert.True(t, api.NeedsRestack, "main moved after api was stacked on it")
+	assert.Equal(t, "/wt/api", api.Worktree.Path)
+	require.Len(t, api.Children, 2)
+	assert.Equal(t, "docs", api.Children[0].Branch)
+	assert.False(t, api.Children[1].NeedsRestack)
+	assert.Nil(t, api.Children[1].Worktree)
+
+	_, err = m.LoadStack("other")
+	assert.ErrorContains(t, err, "not part of a stack")
+	assert.ErrorContains(t, m.SetStackParent("main", "ui"), "cycle")
+}
@@ -0,0 +1,214 @@
+package worktree
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/awhite/wtree/pkg/types"
+)
+
+// StateVersion is the format version of the workspace state file; it
+// changes only when existing fields change meaning
+const StateVersion = 1
+
+// stateLockTimeout is how long a process waits for another one writing the
+// state file
+const stateLockTimeout = 5 * time.Second
+
+// WorkspaceState is the workspace state file editor extensions read: every
+// repository wtree has changed, keyed by its main checkout path, with its
+// worktrees as of the last change
+type WorkspaceState struct {
+	Version      int                         `json:"version"`
+	Updated      time.Time                   `json:"updated"`
+	Repositories map[string]*RepositoryState `json:"repositories"`
+}
+
+// RepositoryState is a repository in the workspace state file
+type RepositoryState struct {
+	Name      string          `json:"name"`
+	Path      string          `json:"path"` // Main checkout
+	Updated   time.Time       `json:"updated"`
+	Worktrees []WorktreeState `json:"worktrees"`
+}
+
+// WorktreeState is a worktree in the workspace state file
+type WorktreeState struct {
+	Path     string   `json:"path"`
+	Branch   string   `json:"branch,omitempty"` // Empty when detached
+	Head     string   `json:"head,omitempty"`
+	Main     bool     `json:"main,omitempty"` // The main checkout
+	Detached bool     `json:"detached,omitempty"`
+	Locked   bool     `json:"locked,omitempty"`
+	Prunable bool     `json:"prunable,omitempty"`
+	Upstream string   `json:"upstream,omitempty"`
+	Ahead    int      `json:"ahead,omitempty"` // Versus the upstream
+	Behind   int      `json:"behind,omitempty"`
+	Note     string   `json:"note,omitempty"`
+	Tags     []string `json:"tags,omitempty"`
+	PR       *PRState `json:"pr,omitempty"`
+}
+
+// PRState is the pull request a PR worktree was created for, as recorded
+// when it was created
+type PRState struct {
+	Number int    `json:"number"`
+	Title  string `json:"title,omitempty"`
+	URL    string `json:"url,omitempty"`
+	State  string `json:"state,omitempty"`
+}
+
+// StateFile returns the workspace state file: state.json in wtree's data
+// directory
+func StateFile() (string, error) {
+	dir, err := dataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "state.json"), nil
+}
+
+// LoadWorkspaceState reads the workspace state file; a missing file is an
+// empty state
+func LoadWorkspaceState(path string) (*WorkspaceState, error) {
+	state := newWorkspaceState()
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, types.NewFileSystemError("read-state", path, "failed to read workspace state", err)
+	}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, types.NewFileSystemError("read-state", path, "invalid workspace state", err)
+	}
+	if state.Repositories == nil {
+		state.Repositories = make(map[string]*RepositoryState)
+	}
+	return state, nil
+}
+
+// newWorkspaceState returns an empty state
+func newWorkspaceState() *WorkspaceState {
+	return &WorkspaceState{Version: StateVersion, Repositories: make(map[string]*RepositoryState)}
+}
+
+// writeWorkspaceState replaces the state file atomically, so readers see
+// either the old or the new state and never a partial one
+func writeWorkspaceState(path string, state *WorkspaceState) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return types.NewFileSystemError("write-state", path, "failed to create data directory", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".state-*.json")
+	if err != nil {
+		return types.NewFileSystemError("write-state", path, "failed to write workspace state", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		return types.NewFileSystemError("write-state", path, "failed to write workspace state", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return types.NewFileSystemError("write-state", path, "failed to write workspace state", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return types.NewFileSystemError("write-state", path, "failed to replace workspace state", err)
+	}
+	return nil
+}
+
+// repositoryState describes the repository's worktrees for the state file
+// from local metadata only, without network calls
+func (m *Manager) repositoryState() (*RepositoryState, error) {
+	worktrees, err := m.repo.ListWorktrees()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list worktrees: %w", err)
+	}
+
+	repo := &RepositoryState{Name: m.repo.GetRepoName(), Updated: time.Now()}
+	pm := &PRManager{Manager: m}
+	for _, wt := range worktrees {
+		entry := WorktreeState{
+			Path:     wt.Path,
+			Branch:   wt.Branch,
+			Head:     wt.Head,
+			Main:     wt.IsMainRepo,
+			Detached: wt.Detached,
+			Locked:   wt.Locked,
+			Prunable: wt.Prunable,
+			Upstream: wt.Upstream,
+			Ahead:    wt.Ahead,
+			Behind:   wt.Behind,
+			Note:     m.worktreeNote(wt),
+			Tags:     worktreeTags(wt.Path),
+		}
+		if wt.IsMainRepo {
+			repo.Path = wt.Path
+		} else if pr, err := pm.loadPRMetadata(wt.Path); err == nil && pr.Number > 0 {
+			entry.PR = &PRState{Number: pr.Number, Title: pr.Title, URL: pr.URL, State: pr.State}
+		}
+		repo.Worktrees = append(repo.Worktrees, entry)
+	}
+	if repo.Path == "" {
+		return nil, fmt.Errorf("main worktree not found")
+	}
+	return repo, nil
+}
+
+// RecordState brings this repository's entry in the workspace state file up
+// to date, and drops repositories that no longer exist. It runs after every
+// command that may have changed something; read-only managers skip it. The
+// state file is an integration aid, so a failure is only a warning.
+func (m *Manager) RecordState() {
+	if m.readOnly {
+		return
+	}
+	if err := m.recordState(); err != nil {
+		m.warn("Failed to update the workspace state file: %v", err)
+	}
+}
+
+func (m *Manager) recordState() error {
+	path, err := StateFile()
+	if err != nil {
+		return err
+	}
+	repo, err := m.repositoryState()
+	if err != nil {
+		return err
+	}
+
+	// Processes in other repositories rewrite the same file
+	locks, err := NewLockManager()
+	if err != nil {
+		return err
+	}
+	lock, err := locks.AcquireLock(LockTypeState, path, stateLockTimeout)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = locks.ReleaseLock(lock) }()
+
+	state, err := LoadWorkspaceState(path)
+	if err != nil {
+		// A corrupt file is rebuilt rather than left to block every update
+		m.warn("%v; starting a new one", err)
+		state = newWorkspaceState()
+	}
+	for key, other := range state.Repositories {
+		if !pathExists(other.Path) {
+			delete(state.Repositories, key)
+		}
+	}
+	state.Version = StateVersion
+	state.Updated = repo.Updated
+	state.Repositories[repo.Path] = repo
+	return writeWorkspaceState(path, state)
+}
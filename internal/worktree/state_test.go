@@ -0,0 +1,63 @@
+package worktree
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/awhite/wtree/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stateMockRepo lists a fixed set of worktrees
+type stateMockRepo struct {
+	MockGitRepo
+	worktrees []*types.WorktreeInfo
+}
+
+func (r *stateMockRepo) ListWorktrees() ([]*types.WorktreeInfo, error) { return r.worktrees, nil }
+
+func TestManager_RecordState(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	main := &types.WorktreeInfo{Path: t.TempDir(), Branch: "main", Head: "abc123", IsMainRepo: true}
+	path := filepath.Join(t.TempDir(), "feature")
+	require.NoError(t, os.MkdirAll(filepath.Join(path, ".git"), 0755))
+	require.NoError(t, saveTags(path, []string{"review"}))
+	wt := &types.WorktreeInfo{Path: path, Branch: "feature", Head: "def456"}
+
+	statePath, err := StateFile()
+	require.NoError(t, err)
+	stale := newWorkspaceState()
+	stale.Repositories["/gone"] = &RepositoryState{Name: "gone", Path: filepath.Join(t.TempDir(), "gone")}
+	require.NoError(t, writeWorkspaceState(statePath, stale))
+
+	m := &Manager{repo: &stateMockRepo{worktrees: []*types.WorktreeInfo{main, wt}}}
+	require.NoError(t, m.recordState())
+
+	state, err := LoadWorkspaceState(statePath)
+	require.NoError(t, err)
+	assert.Equal(t, StateVersion, state.Version)
+	require.Len(t, state.Repositories, 1, "repositories that no longer exist are dropped")
+	repo := state.Repositories[main.Path]
+	require.NotNil(t, repo)
+	assert.Equal(t, "test-repo", repo.Name)
+	require.Len(t, repo.Worktrees, 2)
+	assert.True(t, repo.Worktrees[0].Main)
+	assert.Equal(t, "feature", repo.Worktrees[1].Branch)
+	assert.Equal(t, "def456", repo.Worktrees[1].Head)
+	assert.Equal(t, []string{"review"}, repo.Worktrees[1].Tags)
+
+	// A corrupt file is rebuilt
+	require.NoError(t, os.WriteFile(statePath, []byte("{"), 0644))
+	require.NoError(t, m.recordState())
+	state, err = LoadWorkspaceState(statePath)
+	require.NoError(t, err)
+	assert.Contains(t, state.Repositories, main.Path)
+}
+
+func TestLoadWorkspaceState_Missing(t *testing.T) {
+	state, err := LoadWorkspaceState(filepath.Join(t.TempDir(), "state.json"))
+	require.NoError(t, err)
+	assert.Empty(t, state.Repositories)
+}
@@ -0,0 +1,86 @@
+package worktree
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/awhite/wtree/pkg/types"
+)
+
+// statusViolation is a worktree failing one of the status --check limits
+type statusViolation struct {
+	Worktree *types.WorktreeInfo
+	Reason   string
+}
+
+// statusViolations checks each linked worktree against the limits in options
+func (m *Manager) statusViolations(worktrees []*types.WorktreeInfo, options StatusOptions, include func(*types.WorktreeInfo) bool) ([]statusViolation, int) {
+	mainBranch := ""
+	for _, wt := range worktrees {
+		if wt.IsMainRepo {
+			mainBranch = wt.Branch
+		}
+	}
+
+	var violations []statusViolation
+	checked := 0
+	for _, wt := range worktrees {
+		if wt.IsMainRepo || !include(wt) {
+			continue
+		}
+		checked++
+
+		if options.FailOnDirty {
+			status, err := m.repo.GetWorktreeStatus(wt.Path)
+			switch {
+			case err != nil:
+				violations = append(violations, statusViolation{wt, fmt.Sprintf("status unavailable: %v", err)})
+			case status.Operation != nil:
+				violations = append(violations, statusViolation{wt, fmt.Sprintf("%s in progress", status.Operation)})
+			case !status.IsClean:
+				violations = append(violations, statusViolation{wt, fmt.Sprintf("dirty (%d changed files)", status.ChangedFiles)})
+			}
+		}
+
+		if options.MaxBehind >= 0 && mainBranch != "" && wt.Branch != "" {
+			if _, behind, err := m.repo.AheadBehind(wt.Branch, mainBranch); err != nil {
+				violations = append(violations, statusViolation{wt, fmt.Sprintf("cannot compare with %s: %v", mainBranch, err)})
+			} else if behind > options.MaxBehind {
+				violations = append(violations, statusViolation{wt, fmt.Sprintf("%d commits behind %s (max %d)", behind, mainBranch, options.MaxBehind)})
+			}
+		}
+	}
+	return violations, checked
+}
+
+// checkStatus implements status --check: it reports the worktrees that
+// violate the limits and fails, so the exit status can gate CI or cron jobs
+func (m *Manager) checkStatus(worktrees []*types.WorktreeInfo, options StatusOptions, currentDir string) error {
+	violations, checked := m.statusViolations(worktrees, options, func(wt *types.WorktreeInfo) bool {
+		return (options.BranchFilter == "" || strings.Contains(wt.Branch, options.BranchFilter)) &&
+			(!options.CurrentOnly || strings.HasPrefix(currentDir, wt.Path))
+	})
+
+	if options.Porcelain {
+		for _, v := range violations {
+			fmt.Printf("%s\t%s\t%s\n", v.Worktree.Path, v.Worktree.DisplayBranch(), v.Reason)
+		}
+	} else {
+		for _, v := range violations {
+			m.warn("%s (%s): %s", v.Worktree.DisplayBranch(), v.Worktree.Path, v.Reason)
+		}
+	}
+
+	if len(violations) > 0 {
+		failed := make(map[string]bool)
+		for _, v := range violations {
+			failed[v.Worktree.Path] = true
+		}
+		return types.NewValidationError("status-check",
+			fmt.Sprintf("%d of %d worktrees failed the check", len(failed), checked), nil)
+	}
+	if !options.Porcelain {
+		m.completed("All %d worktrees pass the status check", checked)
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package worktree
+
+import (
+	"testing"
+
+	"github.com/awhite/wtree/internal/git"
+	"github.com/awhite/wtree/pkg/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// statusCheckMockRepo reports canned status and distance from main per path
+type statusCheckMockRepo struct {
+	MockGitRepo
+	dirty  map[string]bool
+	behind map[string]int
+}
+
+func (r *statusCheckMockRepo) GetWorktreeStatus(path string) (*git.WorktreeStatus, error) {
+	return &git.WorktreeStatus{IsClean: !r.dirty[path]}, nil
+}
+func (r *statusCheckMockRepo) AheadBehind(branch, base string) (int, int, error) {
+	return 0, r.behind[branch], nil
+}
+
+func TestManager_statusViolations(t *testing.T) {
+	worktrees := []*types.WorktreeInfo{
+		{Path: "/repo", Branch: "main", IsMainRepo: true},
+		{Path: "/wt/fresh", Branch: "fresh"},
+		{Path: "/wt/stale", Branch: "stale"},
+		{Path: "/wt/dirty", Branch: "dirty"},
+	}
+	m := &Manager{repo: &statusCheckMockRepo{
+		dirty:  map[string]bool{"/repo": true, "/wt/dirty": true},
+		behind: map[string]int{"fresh": 50, "stale": 51},
+	}}
+	all := func(*types.WorktreeInfo) bool { return true }
+
+	violations, checked := m.statusViolations(worktrees, StatusOptions{MaxBehind: 50, FailOnDirty: true}, all)
+	assert.Equal(t, 3, checked)
+	var failed []string
+	for _, v := range violations {
+		failed = append(failed, v.Worktree.Branch+": "+v.Reason)
+	}
+	assert.Equal(t, []string{"stale: 51 commits behind main (max 50)", "dirty: dirty (0 changed files)"}, failed)
+
+	violations, _ = m.statusViolations(worktrees, StatusOptions{MaxBehind: -1}, all)
+	assert.Empty(t, violations)
+}
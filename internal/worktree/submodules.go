@@ -0,0 +1,176 @@
+package worktree
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/awhite/wtree/internal/git"
+	"github.com/awhite/wtree/pkg/types"
+)
+
+// SubmoduleLink records a worktree of a submodule's repository that wtree
+// checked out inside a worktree of the parent, in place of the submodule
+type SubmoduleLink struct {
+	Name       string `json:"name"`
+	Path       string `json:"path"` // Submodule path, relative to the parent worktree
+	Branch     string `json:"branch"`
+	Repository string `json:"repository"` // The submodule checkout in the main worktree that owns the worktree
+	Worktree   string `json:"worktree"`
+}
+
+// submoduleLinks returns the submodule worktrees linked into a worktree;
+// none or unreadable is nil
+func submoduleLinks(worktreePath string) []SubmoduleLink {
+	var links []SubmoduleLink
+	if found, _ := loadWorktreeMetaJSON("read-submodules", worktreePath, "submodules.json", &links); !found {
+		return nil
+	}
+	return links
+}
+
+// saveSubmoduleLinks replaces the submodule links of a worktree
+func saveSubmoduleLinks(worktreePath string, links []SubmoduleLink) error {
+	if len(links) == 0 {
+		return removeWorktreeMeta("write-submodules", worktreePath, "submodules.json")
+	}
+	_, err := saveWorktreeMetaJSON("write-submodules", worktreePath, "submodules.json", links)
+	return err
+}
+
+// findSubmodule looks a submodule of the worktree at worktreePath up by name
+// or path
+func findSubmodule(worktreePath, identifier string) (*git.Submodule, error) {
+	submodules, err := git.Submodules(worktreePath)
+	if err != nil {
+		return nil, err
+	}
+	if len(submodules) == 0 {
+		return nil, types.NewValidationError("sub", fmt.Sprintf("%s has no submodules", worktreePath), nil)
+	}
+	cleaned := filepath.ToSlash(filepath.Clean(identifier))
+	for _, submodule := range submodules {
+		if submodule.Name == identifier || submodule.Path == cleaned {
+			return &submodule, nil
+		}
+	}
+	return nil, types.NewValidationError("sub", fmt.Sprintf("'%s' is not a submodule of %s", identifier, worktreePath), nil)
+}
+
+// SubCreate checks out branch of a submodule in a worktree of the parent,
+// the current one by default. The submodule's checkout in the main worktree
+// owns the new worktree, so the branch is shared with it; a missing branch is
+// created from the commit the parent worktree pins. The link is recorded so
+// status shows it and deleting the parent worktree removes it first.
+func (m *Manager) SubCreate(submodule, branch string, options SubCreateOptions) error {
+	parent, err := m.noteWorktree(options.In)
+	if err != nil {
+		return err
+	}
+	if parent.IsMainRepo {
+		return types.NewValidationError("sub",
+			"submodules of the main worktree are checked out already; run this in a linked worktree or pass --in", nil)
+	}
+	sub, err := findSubmodule(parent.Path, submodule)
+	if err != nil {
+		return err
+	}
+
+	links := submoduleLinks(parent.Path)
+	for _, link := range links {
+		if link.Path == sub.Path {
+			return types.NewValidationError("sub",
+				fmt.Sprintf("%s already has a worktree of '%s' on '%s'", parent.DisplayBranch(), sub.Path, link.Branch), nil)
+		}
+	}
+
+	repository := filepath.Join(m.mainWorktreePath(), filepath.FromSlash(sub.Path))
+	if !pathExists(filepath.Join(repository, ".git")) {
+		return types.NewValidationError("sub",
+			fmt.Sprintf("submodule '%s' is not initialized in the main worktree; run 'git submodule update --init %s' there", sub.Path, sub.Path), nil)
+	}
+	target := filepath.Join(parent.Path, filepath.FromSlash(sub.Path))
+	if entries, err := os.ReadDir(target); err == nil && len(entries) > 0 {
+		return types.NewFileSystemError("sub", target,
+			fmt.Sprintf("%s is already checked out; remove it with 'git submodule deinit %s' first", target, sub.Path), nil)
+	}
+
+	from := options.From
+	if from == "" {
+		if from, err = git.SubmoduleCommit(parent.Path, sub.Path); err != nil {
+			return err
+		}
+	}
+
+	if options.DryRun {
+		m.ui.Info("[DRY RUN] Would check out '%s' of submodule '%s' at %s", branch, sub.Path, target)
+		return nil
+	}
+
+	m.ui.Info("Creating submodule worktree at: %s", target)
+	if err := git.AddLinkedWorktree(repository, target, branch, from); err != nil {
+		return err
+	}
+	if err := recordCreation(target, git.ShortHash(from)); err != nil {
+		m.warn("Failed to record worktree metadata: %v", err)
+	}
+	links = append(links, SubmoduleLink{
+		Name:       sub.Name,
+		Path:       sub.Path,
+		Branch:     branch,
+		Repository: repository,
+		Worktree:   target,
+	})
+	if err := saveSubmoduleLinks(parent.Path, links); err != nil {
+		return err
+	}
+
+	m.completed("Submodule '%s' of %s is on '%s'", sub.Path, parent.DisplayBranch(), branch)
+	m.ui.InfoIndented("Commit there, then commit %s in the parent to pin it", sub.Path)
+	return nil
+}
+
+// ListSubmoduleLinks prints the submodule worktrees of every worktree
+func (m *Manager) ListSubmoduleLinks() error {
+	worktrees, err := m.repo.ListWorktrees()
+	if err != nil {
+		return fmt.Errorf("failed to list worktrees: %w", err)
+	}
+
+	table := m.ui.NewTable()
+	table.SetHeaders("Worktree", "Submodule", "Branch", "Path")
+	found := false
+	for _, wt := range worktrees {
+		for _, link := range submoduleLinks(wt.Path) {
+			table.AddRow(wt.DisplayBranch(), link.Path, link.Branch, link.Worktree)
+			found = true
+		}
+	}
+	if !found {
+		m.ui.Info("No submodule worktrees (create one with 'wtree sub create')")
+		return nil
+	}
+	table.Render()
+	return nil
+}
+
+// removeSubmoduleWorktrees removes the submodule worktrees linked into a
+// worktree about to be deleted: git refuses to remove a worktree with
+// submodules checked out. Their branches stay in the submodule repository.
+func (m *Manager) removeSubmoduleWorktrees(worktreePath string, force bool) error {
+	links := submoduleLinks(worktreePath)
+	if len(links) == 0 {
+		return nil
+	}
+	for i, link := range links {
+		if pathExists(link.Worktree) {
+			m.ui.Info("Removing submodule worktree: %s", link.Worktree)
+			if err := git.RemoveLinkedWorktree(link.Repository, link.Worktree, force); err != nil {
+				_ = saveSubmoduleLinks(worktreePath, links[i:])
+				return fmt.Errorf("failed to remove worktree of submodule '%s': %w", link.Path, err)
+			}
+		}
+		m.ui.InfoIndented("Branch '%s' is kept in %s", link.Branch, link.Repository)
+	}
+	return saveSubmoduleLinks(worktreePath, nil)
+}
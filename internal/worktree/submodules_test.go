@@ -0,0 +1,40 @@
+package worktree
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubmoduleLinks_RoundTrip(t *testing.T) {
+	path := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(path, ".git"), 0755))
+	assert.Nil(t, submoduleLinks(path))
+
+	links := []SubmoduleLink{{Name: "lib", Path: "vendor/lib", Branch: "fix", Repository: "/repo/vendor/lib", Worktree: filepath.Join(path, "vendor/lib")}}
+	require.NoError(t, saveSubmoduleLinks(path, links))
+	assert.Equal(t, links, submoduleLinks(path))
+
+	require.NoError(t, saveSubmoduleLinks(path, nil))
+	assert.Nil(t, submoduleLinks(path))
+}
+
+func TestFindSubmodule(t *testing.T) {
+	path := t.TempDir()
+	gitmodules := "[submodule \"lib\"]\n\tpath = vendor/lib\n\turl = ../lib\n"
+	require.NoError(t, os.WriteFile(filepath.Join(path, ".gitmodules"), []byte(gitmodules), 0644))
+
+	for _, identifier := range []string{"lib", "vendor/lib", "vendor/lib/"} {
+		submodule, err := findSubmodule(path, identifier)
+		require.NoError(t, err, identifier)
+		assert.Equal(t, "vendor/lib", submodule.Path)
+	}
+	_, err := findSubmodule(path, "other")
+	assert.ErrorContains(t, err, "is not a submodule")
+
+	_, err = findSubmodule(t.TempDir(), "lib")
+	assert.ErrorContains(t, err, "has no submodules")
+}
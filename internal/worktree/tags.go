@@ -0,0 +1,170 @@
+package worktree
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/awhite/wtree/pkg/types"
+)
+
+// worktreeTags returns a worktree's tags, sorted; none or unreadable is nil
+func worktreeTags(worktreePath string) []string {
+	var tags []string
+	if found, _ := loadWorktreeMetaJSON("read-tags", worktreePath, "tags.json", &tags); !found {
+		return nil
+	}
+	return tags
+}
+
+// saveTags replaces a worktree's tags
+func saveTags(worktreePath string, tags []string) error {
+	if len(tags) == 0 {
+		return removeWorktreeMeta("write-tags", worktreePath, "tags.json")
+	}
+	_, err := saveWorktreeMetaJSON("write-tags", worktreePath, "tags.json", tags)
+	return err
+}
+
+// NormalizeTags lowercases and de-duplicates tags, rejecting any that aren't
+// a single word of letters, digits, '.', '_' or '-'
+func NormalizeTags(tags []string) ([]string, error) {
+	seen := make(map[string]bool)
+	var normalized []string
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || strings.IndexFunc(tag, func(r rune) bool {
+			return !('a' <= r && r <= 'z') && !('0' <= r && r <= '9') && r != '.' && r != '_' && r != '-'
+		}) >= 0 {
+			return nil, types.NewValidationError("tag",
+				fmt.Sprintf("invalid tag '%s' (use letters, digits, '.', '_' and '-')", tag), nil)
+		}
+		if !seen[tag] {
+			seen[tag] = true
+			normalized = append(normalized, tag)
+		}
+	}
+	sort.Strings(normalized)
+	return normalized, nil
+}
+
+// hasTags reports whether a worktree carries every one of tags
+func hasTags(worktreePath string, tags []string) bool {
+	have := make(map[string]bool)
+	for _, tag := range worktreeTags(worktreePath) {
+		have[tag] = true
+	}
+	for _, tag := range tags {
+		if !have[tag] {
+			return false
+		}
+	}
+	return true
+}
+
+// filterTagged keeps the worktrees carrying every one of tags; the main
+// repository can't be tagged and is dropped. No tags keeps everything.
+func filterTagged(worktrees []*types.WorktreeInfo, tags []string) []*types.WorktreeInfo {
+	if len(tags) == 0 {
+		return worktrees
+	}
+	var tagged []*types.WorktreeInfo
+	for _, wt := range worktrees {
+		if !wt.IsMainRepo && hasTags(wt.Path, tags) {
+			tagged = append(tagged, wt)
+		}
+	}
+	return tagged
+}
+
+// tagWorktree resolves the linked worktree a tag command applies to
+func (m *Manager) tagWorktree(identifier string) (*types.WorktreeInfo, error) {
+	wt, err := m.noteWorktree(identifier)
+	if err != nil {
+		return nil, err
+	}
+	if wt.IsMainRepo {
+		return nil, types.NewValidationError("tag", "the main repository worktree can't be tagged", nil)
+	}
+	return wt, nil
+}
+
+// AddTags tags a worktree, the current one when identifier is empty
+func (m *Manager) AddTags(identifier string, tags []string, dryRun bool) error {
+	tags, err := NormalizeTags(tags)
+	if err != nil {
+		return err
+	}
+	wt, err := m.tagWorktree(identifier)
+	if err != nil {
+		return err
+	}
+	if dryRun {
+		m.ui.Info("[DRY RUN] Would tag %s: %s", wt.DisplayBranch(), strings.Join(tags, ", "))
+		return nil
+	}
+	merged, _ := NormalizeTags(append(worktreeTags(wt.Path), tags...))
+	if err := saveTags(wt.Path, merged); err != nil {
+		return err
+	}
+	m.completed("Tagged %s: %s", wt.DisplayBranch(), strings.Join(merged, ", "))
+	return nil
+}
+
+// RemoveTags removes tags from a worktree, the current one when identifier
+// is empty. Tags it doesn't carry are ignored.
+func (m *Manager) RemoveTags(identifier string, tags []string, dryRun bool) error {
+	tags, err := NormalizeTags(tags)
+	if err != nil {
+		return err
+	}
+	wt, err := m.tagWorktree(identifier)
+	if err != nil {
+		return err
+	}
+	if dryRun {
+		m.ui.Info("[DRY RUN] Would remove from %s: %s", wt.DisplayBranch(), strings.Join(tags, ", "))
+		return nil
+	}
+
+	drop := make(map[string]bool)
+	for _, tag := range tags {
+		drop[tag] = true
+	}
+	var kept []string
+	for _, tag := range worktreeTags(wt.Path) {
+		if !drop[tag] {
+			kept = append(kept, tag)
+		}
+	}
+	if err := saveTags(wt.Path, kept); err != nil {
+		return err
+	}
+	if len(kept) == 0 {
+		m.completed("Removed all tags from %s", wt.DisplayBranch())
+	} else {
+		m.completed("Tags of %s: %s", wt.DisplayBranch(), strings.Join(kept, ", "))
+	}
+	return nil
+}
+
+// ListTags prints the tags of every tagged worktree
+func (m *Manager) ListTags() error {
+	worktrees, err := m.repo.ListWorktrees()
+	if err != nil {
+		return fmt.Errorf("failed to list worktrees: %w", err)
+	}
+
+	m.ui.Header("Worktree tags")
+	found := false
+	for _, wt := range worktrees {
+		if tags := worktreeTags(wt.Path); len(tags) > 0 && !wt.IsMainRepo {
+			fmt.Printf("%s  %s\n", wt.DisplayBranch(), m.ui.Gray(strings.Join(tags, ", ")))
+			found = true
+		}
+	}
+	if !found {
+		m.ui.Info("No tagged worktrees (tag one with 'wtree tag add')")
+	}
+	return nil
+}
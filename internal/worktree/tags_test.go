@@ -0,0 +1,43 @@
+package worktree
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/awhite/wtree/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeTags(t *testing.T) {
+	tags, err := NormalizeTags([]string{"Review", "experiment", " review "})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"experiment", "review"}, tags)
+
+	_, err = NormalizeTags([]string{"two words"})
+	assert.Error(t, err)
+	_, err = NormalizeTags([]string{""})
+	assert.Error(t, err)
+}
+
+func TestManager_Tags(t *testing.T) {
+	main := &types.WorktreeInfo{Path: t.TempDir(), Branch: "main", IsMainRepo: true}
+	path := filepath.Join(t.TempDir(), "feature")
+	require.NoError(t, os.MkdirAll(filepath.Join(path, ".git"), 0755))
+	wt := &types.WorktreeInfo{Path: path, Branch: "feature"}
+	m := &Manager{repo: &notesMockRepo{worktree: wt}}
+
+	require.NoError(t, m.AddTags("feature", []string{"review", "Experiment"}, false))
+	require.NoError(t, m.AddTags("feature", []string{"review"}, false))
+	assert.Equal(t, []string{"experiment", "review"}, worktreeTags(path))
+
+	worktrees := []*types.WorktreeInfo{main, wt}
+	assert.Equal(t, worktrees, filterTagged(worktrees, nil))
+	assert.Equal(t, []*types.WorktreeInfo{wt}, filterTagged(worktrees, []string{"review", "experiment"}))
+	assert.Empty(t, filterTagged(worktrees, []string{"review", "release"}))
+
+	require.NoError(t, m.RemoveTags("feature", []string{"review", "experiment"}, false))
+	assert.Nil(t, worktreeTags(path))
+	assert.Empty(t, filterTagged(worktrees, []string{"review"}))
+}
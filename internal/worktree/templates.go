@@ -0,0 +1,133 @@
+package worktree
+
+import (
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+	"unicode"
+
+	"github.com/awhite/wtree/pkg/types"
+)
+
+// TemplateData is what hook commands and worktree_pattern can refer to, as
+// text/template fields: {{.Branch}}, {{.BranchSlug}}, {{.PRNumber}}...
+type TemplateData struct {
+	Repo         string // Repository name
+	RepoPath     string
+	Branch       string
+	BranchSlug   string // Branch as a single path-safe name, e.g. feature-login for feature/login
+	TargetBranch string
+	WorktreePath string
+	User         string
+	PRNumber     int    // Zero unless the worktree is for a pull request
+	Date         string // Today, as 2006-01-02
+}
+
+// templateFuncs are the helpers templates can call, e.g.
+// {{.Branch | slugify | truncate 20}}
+var templateFuncs = template.FuncMap{
+	"slugify":  slugify,
+	"truncate": truncate,
+	"lower":    strings.ToLower,
+}
+
+// legacyPlaceholders maps the original {name} placeholders to the fields
+// they now stand for, so existing hooks and patterns keep working
+var legacyPlaceholders = map[string]string{
+	"repo":          "{{.Repo}}",
+	"repo_path":     "{{.RepoPath}}",
+	"branch":        "{{.Branch}}",
+	"target_branch": "{{.TargetBranch}}",
+	"worktree_path": "{{.WorktreePath}}",
+	"user":          "{{.User}}",
+	"branch_slug":   "{{.BranchSlug}}",
+}
+
+var legacyPlaceholderPattern = regexp.MustCompile(`\{([a-z_]+)\}`)
+
+// parseTemplate parses a hook command or pattern, accepting the legacy
+// {name} placeholders alongside template actions
+func parseTemplate(name, text string) (*template.Template, error) {
+	text = legacyPlaceholderPattern.ReplaceAllStringFunc(text, func(match string) string {
+		if field, ok := legacyPlaceholders[match[1:len(match)-1]]; ok {
+			return field
+		}
+		return match
+	})
+	tmpl, err := template.New(name).Funcs(templateFuncs).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, types.NewValidationError("template", "invalid template '"+name+"'", err)
+	}
+	return tmpl, nil
+}
+
+// expandTemplate fills in a hook command or pattern
+func expandTemplate(name, text string, data TemplateData) (string, error) {
+	tmpl, err := parseTemplate(name, text)
+	if err != nil {
+		return "", err
+	}
+	var out strings.Builder
+	if err := tmpl.Execute(&out, data); err != nil {
+		return "", types.NewValidationError("template", "failed to expand '"+name+"'", err)
+	}
+	return out.String(), nil
+}
+
+// hookTemplateData describes a hook's context to its command template
+func hookTemplateData(ctx types.HookContext, slug types.BranchSlugConfig) TemplateData {
+	prNumber, _ := strconv.Atoi(ctx.Environment["WTREE_PR_NUMBER"])
+	return TemplateData{
+		Repo:         filepath.Base(ctx.RepoPath),
+		RepoPath:     ctx.RepoPath,
+		Branch:       ctx.Branch,
+		BranchSlug:   branchSlug(ctx.Branch, slug),
+		TargetBranch: ctx.TargetBranch,
+		WorktreePath: ctx.WorktreePath,
+		User:         currentUser(),
+		PRNumber:     prNumber,
+		Date:         time.Now().Format("2006-01-02"),
+	}
+}
+
+// branchSlug makes a branch name a single directory name: '/' and the
+// characters file names can't hold become the separator, never repeated or
+// at either end. E.g. feature/login becomes feature-login.
+func branchSlug(branch string, config types.BranchSlugConfig) string {
+	separator := config.Separator
+	if separator == "" {
+		separator = "-"
+	}
+	if config.Lowercase {
+		branch = strings.ToLower(branch)
+	}
+	return strings.Join(strings.FieldsFunc(branch, func(r rune) bool {
+		return r < ' ' || unicode.IsSpace(r) || strings.ContainsRune(`/\:*?"<>|`, r)
+	}), separator)
+}
+
+// slugWords splits text into lowercase runs of letters and digits
+func slugWords(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !('a' <= r && r <= 'z') && !('0' <= r && r <= '9')
+	})
+}
+
+// slugify turns text into lowercase words joined by '-', e.g. "Feature/Add
+// login" into "feature-add-login"
+func slugify(text string) string {
+	return strings.Join(slugWords(text), "-")
+}
+
+// truncate keeps the first n characters of text; the argument order lets
+// templates pipe into it: {{.Branch | truncate 20}}
+func truncate(n int, text string) string {
+	runes := []rune(text)
+	if n < 0 || len(runes) <= n {
+		return text
+	}
+	return string(runes[:n])
+}
@@ -0,0 +1,62 @@
+package worktree
+
+import (
+	"testing"
+
+	"github.com/awhite/wtree/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSlugify(t *testing.T) {
+	assert.Equal(t, "feature-login", slugify("feature/login"))
+	assert.Equal(t, "fix-crash-on-start", slugify("Fix: crash on start!"))
+	assert.Equal(t, "", slugify("///"))
+}
+
+func TestTruncate(t *testing.T) {
+	assert.Equal(t, "feat", truncate(4, "feature"))
+	assert.Equal(t, "feature", truncate(20, "feature"))
+	assert.Equal(t, "né", truncate(2, "néon"))
+}
+
+func TestExpandTemplate_Pattern(t *testing.T) {
+	data := TemplateData{Repo: "app", Branch: "feature/login", BranchSlug: "feature-login", User: "sam", PRNumber: 12}
+
+	out, err := expandTemplate("pattern", "{repo}-{branch}", data)
+	require.NoError(t, err)
+	assert.Equal(t, "app-feature/login", out)
+
+	out, err = expandTemplate("pattern", "{{.Repo}}-{{if .PRNumber}}pr{{.PRNumber}}-{{end}}{{.BranchSlug}}", data)
+	require.NoError(t, err)
+	assert.Equal(t, "app-pr12-feature-login", out)
+}
+
+func TestBranchSlug(t *testing.T) {
+	assert.Equal(t, "feature-login", branchSlug("feature/login", types.BranchSlugConfig{}))
+	assert.Equal(t, "Team-JIRA-12", branchSlug("/Team//JIRA-12/", types.BranchSlugConfig{}))
+	assert.Equal(t, "team_jira-12", branchSlug("Team/JIRA-12", types.BranchSlugConfig{Separator: "_", Lowercase: true}))
+	assert.Equal(t, "plain", branchSlug("plain", types.BranchSlugConfig{}))
+}
+
+func TestManager_resolveWorktree_Slug(t *testing.T) {
+	repo := &cleanupMockRepo{worktrees: []*types.WorktreeInfo{
+		{Path: "/wt/app-feature-login", Branch: "feature/login"},
+		{Path: "/wt/app-feature-login-2", Branch: "feature-login"},
+	}}
+	m := &Manager{repo: repo, projectConfig: types.DefaultProjectConfig()}
+
+	wt, err := m.resolveWorktree("feature/login")
+	require.NoError(t, err)
+	assert.Equal(t, "feature/login", wt.Branch)
+
+	// A branch named like the slug wins over the slug
+	wt, err = m.resolveWorktree("feature-login")
+	require.NoError(t, err)
+	assert.Equal(t, "feature-login", wt.Branch)
+
+	repo.worktrees = repo.worktrees[:1]
+	wt, err = m.resolveWorktree("feature-login")
+	require.NoError(t, err)
+	assert.Equal(t, "feature/login", wt.Branch)
+}
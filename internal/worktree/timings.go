@@ -0,0 +1,95 @@
+package worktree
+
+import (
+	"sync"
+	"time"
+)
+
+// phaseTiming is how long one phase of an operation took
+type phaseTiming struct {
+	name string
+	took time.Duration
+}
+
+// Timings records phase durations for --timings. A nil *Timings records
+// nothing, so call sites need no checks.
+type Timings struct {
+	mu     sync.Mutex
+	start  time.Time
+	phases []phaseTiming
+}
+
+// Start begins timing a phase and returns the function that ends it
+func (t *Timings) Start(name string) func() {
+	if t == nil {
+		return func() {}
+	}
+	started := time.Now()
+	return func() { t.Record(name, time.Since(started)) }
+}
+
+// Record adds a phase that has already been measured
+func (t *Timings) Record(name string, took time.Duration) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.phases = append(t.phases, phaseTiming{name: name, took: took})
+}
+
+// take returns the recorded phases and the time since the first call to
+// reset, then starts over
+func (t *Timings) take() ([]phaseTiming, time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	phases, total := t.phases, time.Since(t.start)
+	t.phases, t.start = nil, time.Now()
+	return phases, total
+}
+
+// SetTimings enables phase timing reports at the end of create, delete and
+// cleanup
+func (m *Manager) SetTimings(enabled bool) {
+	if enabled {
+		m.timings = &Timings{start: time.Now()}
+	} else {
+		m.timings = nil
+	}
+}
+
+// beginTimings marks the start of a timed operation and returns the function
+// that prints its report
+func (m *Manager) beginTimings() func() {
+	if m.timings == nil {
+		return func() {}
+	}
+	m.timings.take()
+	return m.reportTimings
+}
+
+// reportTimings prints the phases recorded since beginTimings
+func (m *Manager) reportTimings() {
+	phases, total := m.timings.take()
+
+	m.ui.Header("Timings")
+	table := m.ui.NewTable()
+	table.SetHeaders("Phase", "Duration")
+	for _, phase := range phases {
+		table.AddRow(phase.name, formatPhaseDuration(phase.took))
+	}
+	table.AddRow("total", formatPhaseDuration(total))
+	table.Render()
+}
+
+// formatPhaseDuration rounds a duration for display, e.g. "1.24s" or "35ms"
+func formatPhaseDuration(d time.Duration) string {
+	switch {
+	case d >= time.Second:
+		return d.Round(10 * time.Millisecond).String()
+	case d >= time.Millisecond:
+		return d.Round(time.Millisecond).String()
+	default:
+		return d.Round(time.Microsecond).String()
+	}
+}
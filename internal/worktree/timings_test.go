@@ -0,0 +1,33 @@
+package worktree
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimings(t *testing.T) {
+	var off *Timings
+	off.Start("validation")()
+	off.Record("hook", time.Second)
+
+	timings := &Timings{start: time.Now()}
+	timings.Start("validation")()
+	timings.Record("post_create hook: npm install", 2*time.Second)
+
+	phases, total := timings.take()
+	assert.Len(t, phases, 2)
+	assert.Equal(t, "validation", phases[0].name)
+	assert.Equal(t, 2*time.Second, phases[1].took)
+	assert.Greater(t, total, time.Duration(0))
+
+	phases, _ = timings.take()
+	assert.Empty(t, phases)
+}
+
+func TestFormatPhaseDuration(t *testing.T) {
+	assert.Equal(t, "1.24s", formatPhaseDuration(1243*time.Millisecond))
+	assert.Equal(t, "35ms", formatPhaseDuration(35400*time.Microsecond))
+	assert.Equal(t, "12µs", formatPhaseDuration(12345*time.Nanosecond))
+}
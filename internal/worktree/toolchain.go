@@ -0,0 +1,73 @@
+package worktree
+
+import (
+	"path/filepath"
+)
+
+// ToolchainDetector recognizes a language toolchain and whether a worktree
+// has had its dependencies installed
+type ToolchainDetector interface {
+	// Name is a short label such as "node"
+	Name() string
+	// Detect reports whether the repository at dir uses this toolchain
+	Detect(dir string) bool
+	// Ready reports whether the worktree at dir has its dependencies set up
+	Ready(dir string) bool
+}
+
+// markerDetector detects a toolchain by manifest files and considers a
+// worktree ready when any of its dependency directories exists
+type markerDetector struct {
+	name      string
+	manifests []string
+	installed []string
+}
+
+func (d markerDetector) Name() string { return d.name }
+
+func (d markerDetector) Detect(dir string) bool { return anyExists(dir, d.manifests) }
+
+func (d markerDetector) Ready(dir string) bool { return anyExists(dir, d.installed) }
+
+// toolchainDetectors are checked in order; the first that matches the main
+// worktree is the repository's primary toolchain
+var toolchainDetectors = []ToolchainDetector{
+	markerDetector{name: "node", manifests: []string{"package.json"}, installed: []string{"node_modules"}},
+	markerDetector{name: "php", manifests: []string{"composer.json"}, installed: []string{"vendor"}},
+	markerDetector{name: "python", manifests: []string{"pyproject.toml", "requirements.txt", "Pipfile"}, installed: []string{".venv", "venv"}},
+	markerDetector{name: "ruby", manifests: []string{"Gemfile"}, installed: []string{"vendor/bundle", ".bundle"}},
+}
+
+// RegisterToolchainDetector adds a detector, checked after the built-in ones
+func RegisterToolchainDetector(detector ToolchainDetector) {
+	toolchainDetectors = append(toolchainDetectors, detector)
+}
+
+// detectToolchain returns the primary toolchain of the repository at dir, or
+// nil when none is recognized
+func detectToolchain(dir string) ToolchainDetector {
+	for _, detector := range toolchainDetectors {
+		if detector.Detect(dir) {
+			return detector
+		}
+	}
+	return nil
+}
+
+// setupState describes a worktree's readiness for the "Setup" list column,
+// e.g. "ready (node)" or "needs setup (node)"
+func setupState(toolchain ToolchainDetector, dir string) string {
+	if toolchain.Ready(dir) {
+		return "ready (" + toolchain.Name() + ")"
+	}
+	return "needs setup (" + toolchain.Name() + ")"
+}
+
+func anyExists(dir string, names []string) bool {
+	for _, name := range names {
+		if fileExists(filepath.Join(dir, name)) {
+			return true
+		}
+	}
+	return false
+}
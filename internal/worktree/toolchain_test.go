@@ -0,0 +1,37 @@
+package worktree
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectToolchain(t *testing.T) {
+	dir := t.TempDir()
+	assert.Nil(t, detectToolchain(dir))
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "package.json"), []byte("{}"), 0644))
+	toolchain := detectToolchain(dir)
+	require.NotNil(t, toolchain)
+	assert.Equal(t, "node", toolchain.Name())
+	assert.Equal(t, "needs setup (node)", setupState(toolchain, dir))
+
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "node_modules"), 0755))
+	assert.Equal(t, "ready (node)", setupState(toolchain, dir))
+}
+
+func TestDetectToolchain_Python(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "pyproject.toml"), nil, 0644))
+
+	toolchain := detectToolchain(dir)
+	require.NotNil(t, toolchain)
+	assert.Equal(t, "python", toolchain.Name())
+	assert.False(t, toolchain.Ready(dir))
+
+	require.NoError(t, os.Mkdir(filepath.Join(dir, ".venv"), 0755))
+	assert.True(t, toolchain.Ready(dir))
+}
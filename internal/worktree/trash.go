@@ -0,0 +1,360 @@
+package worktree
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/awhite/wtree/internal/git"
+	"github.com/awhite/wtree/pkg/types"
+)
+
+// TrashEntry describes a deleted worktree kept in the trash
+type TrashEntry struct {
+	ID        string    `json:"-"` // Directory name under the trash root
+	Branch    string    `json:"branch"`
+	Path      string    `json:"path"` // Original worktree path
+	Repo      string    `json:"repo"` // Main repository worktree the entry belongs to
+	Head      string    `json:"head"` // Commit checked out when deleted, used to recreate a deleted branch
+	DeletedAt time.Time `json:"deleted_at"`
+}
+
+// TrashEmptyOptions defines options for emptying the trash
+type TrashEmptyOptions struct {
+	ExpiredOnly bool // Only remove entries past trash.retention_days
+	Yes         bool // Skip confirmation
+}
+
+const trashEntryFile = "entry.json"
+
+// dataDir returns wtree's local state directory, $XDG_DATA_HOME/wtree or
+// ~/.local/share/wtree
+func dataDir() (string, error) {
+	if dataHome := os.Getenv("XDG_DATA_HOME"); dataHome != "" {
+		return filepath.Join(dataHome, "wtree"), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(home, ".local", "share", "wtree"), nil
+}
+
+// trashRoot returns the trash directory inside dataDir
+func trashRoot() (string, error) {
+	dir, err := dataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "trash"), nil
+}
+
+// useTrash reports whether a delete should move the worktree to the trash
+func (m *Manager) useTrash(options DeleteOptions) bool {
+	if options.Permanent {
+		return false
+	}
+	return options.Trash || (m.globalConfig != nil && m.globalConfig.Trash.Enabled)
+}
+
+// moveToTrash moves a worktree's directory into the trash and drops git's
+// record of it, leaving the branch untouched
+func (m *Manager) moveToTrash(wt *types.WorktreeInfo) (*TrashEntry, error) {
+	root, err := trashRoot()
+	if err != nil {
+		return nil, err
+	}
+
+	entry := &TrashEntry{
+		ID:        time.Now().Format("20060102-150405") + "-" + strings.ReplaceAll(trashName(wt), "/", "-"),
+		Branch:    wt.Branch,
+		Path:      wt.Path,
+		Repo:      m.mainWorktreePath(),
+		DeletedAt: time.Now(),
+	}
+	if commit, err := m.repo.GetLastCommit(wt.Path); err == nil && commit != nil {
+		entry.Head = commit.Hash
+	}
+
+	// Keep IDs unique when the same branch is trashed twice in a second
+	entryDir := filepath.Join(root, entry.ID)
+	for i := 2; pathExists(entryDir); i++ {
+		entryDir = filepath.Join(root, fmt.Sprintf("%s-%d", entry.ID, i))
+	}
+	entry.ID = filepath.Base(entryDir)
+	if err := os.MkdirAll(entryDir, 0755); err != nil {
+		return nil, types.NewFileSystemError("trash-worktree", entryDir, "failed to create trash directory", err)
+	}
+
+	data, err := json.MarshalIndent(entry, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(filepath.Join(entryDir, trashEntryFile), data, 0644); err != nil {
+		_ = os.RemoveAll(entryDir)
+		return nil, types.NewFileSystemError("trash-worktree", entryDir, "failed to write trash entry", err)
+	}
+
+	if err := m.movePath(wt.Path, filepath.Join(entryDir, "worktree")); err != nil {
+		_ = os.RemoveAll(entryDir)
+		return nil, types.NewFileSystemError("trash-worktree", wt.Path,
+			"failed to move worktree to the trash", err)
+	}
+
+	if err := m.repo.PruneWorktrees(); err != nil {
+		return nil, err
+	}
+
+	return entry, nil
+}
+
+// trashName is the part of a trash ID naming the worktree's checkout
+func trashName(wt *types.WorktreeInfo) string {
+	if wt.Branch == "" {
+		return "detached"
+	}
+	return wt.Branch
+}
+
+// loadTrashEntries reads all trash entries, newest first
+func loadTrashEntries() ([]*TrashEntry, error) {
+	root, err := trashRoot()
+	if err != nil {
+		return nil, err
+	}
+
+	dirs, err := os.ReadDir(root)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, types.NewFileSystemError("read-trash", root, "failed to read trash", err)
+	}
+
+	var entries []*TrashEntry
+	for _, dir := range dirs {
+		data, err := os.ReadFile(filepath.Join(root, dir.Name(), trashEntryFile))
+		if err != nil {
+			continue // Not a trash entry
+		}
+		var entry TrashEntry
+		if err := json.Unmarshal(data, &entry); err != nil {
+			continue
+		}
+		entry.ID = dir.Name()
+		entries = append(entries, &entry)
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].DeletedAt.After(entries[j].DeletedAt)
+	})
+	return entries, nil
+}
+
+// trashRetention returns how long trashed worktrees are kept: trash.retention,
+// or else trash.retention_days. Zero keeps them until emptied.
+func (m *Manager) trashRetention() time.Duration {
+	if retention := m.globalConfig.Trash.Retention; retention > 0 {
+		return retention
+	}
+	return time.Duration(m.globalConfig.Trash.RetentionDays) * types.Day
+}
+
+// trashExpired reports whether an entry is past the retention period
+func (m *Manager) trashExpired(entry *TrashEntry, now time.Time) bool {
+	retention := m.trashRetention()
+	return retention > 0 && now.Sub(entry.DeletedAt) > retention
+}
+
+// purgeExpiredTrash removes entries past the retention period
+func (m *Manager) purgeExpiredTrash() {
+	entries, err := loadTrashEntries()
+	if err != nil {
+		return
+	}
+	root, _ := trashRoot()
+	now := time.Now()
+	for _, entry := range entries {
+		if m.trashExpired(entry, now) {
+			_ = os.RemoveAll(filepath.Join(root, entry.ID))
+		}
+	}
+}
+
+// TrashList displays the trashed worktrees
+func (m *Manager) TrashList() error {
+	m.purgeExpiredTrash()
+
+	entries, err := loadTrashEntries()
+	if err != nil {
+		return err
+	}
+
+	if len(entries) == 0 {
+		m.ui.Info("Trash is empty")
+		return nil
+	}
+
+	table := m.ui.NewTable()
+	table.SetHeaders("ID", "Branch", "Path", "Deleted", "Expires")
+	for _, entry := range entries {
+		expires := "never"
+		if retention := m.trashRetention(); retention > 0 {
+			expires = m.ui.Time(entry.DeletedAt.Add(retention))
+		}
+		branch := entry.Branch
+		if branch == "" {
+			branch = "detached@" + git.ShortHash(entry.Head)
+		}
+		table.AddRow(entry.ID, branch, entry.Path, m.ui.Time(entry.DeletedAt), expires)
+	}
+	table.Render()
+	return nil
+}
+
+// TrashIDs returns the IDs of trashed worktrees, for shell completion
+func (m *Manager) TrashIDs() []string {
+	entries, _ := loadTrashEntries()
+	ids := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		ids = append(ids, entry.ID)
+	}
+	return ids
+}
+
+// TrashRestore recreates a trashed worktree at its original path. The
+// identifier is a trash ID or a branch name, which picks its newest entry.
+func (m *Manager) TrashRestore(identifier string) error {
+	entries, err := loadTrashEntries()
+	if err != nil {
+		return err
+	}
+
+	var entry *TrashEntry
+	for _, candidate := range entries {
+		if candidate.ID == identifier || candidate.Branch == identifier {
+			entry = candidate
+			break
+		}
+	}
+	if entry == nil {
+		return types.NewValidationError("trash-restore",
+			fmt.Sprintf("no trashed worktree matches '%s'", identifier), nil)
+	}
+
+	if mainPath := m.mainWorktreePath(); entry.Repo != "" && mainPath != entry.Repo {
+		return types.NewValidationError("trash-restore",
+			fmt.Sprintf("'%s' belongs to %s; run restore from that repository", entry.ID, entry.Repo), nil)
+	}
+	if pathExists(entry.Path) {
+		return types.NewValidationError("trash-restore",
+			fmt.Sprintf("path already exists: %s", entry.Path), nil)
+	}
+
+	root, err := trashRoot()
+	if err != nil {
+		return err
+	}
+	trashed := filepath.Join(root, entry.ID, "worktree")
+
+	// A detached worktree is recreated detached at its commit
+	if entry.Branch == "" {
+		if entry.Head == "" {
+			return types.NewGitError("trash-restore",
+				fmt.Sprintf("'%s' was detached and no commit was recorded", entry.ID), nil)
+		}
+		m.ui.Header("Restoring detached worktree at %s", entry.Head)
+	} else {
+		m.ui.Header("Restoring worktree: %s", entry.Branch)
+	}
+
+	if entry.Branch != "" && !m.repo.BranchExists(entry.Branch) {
+		if entry.Head == "" {
+			return types.NewGitError("trash-restore",
+				fmt.Sprintf("branch '%s' no longer exists and no commit was recorded", entry.Branch), nil)
+		}
+		m.ui.Info("Recreating branch %s at %s", entry.Branch, entry.Head)
+		if err := m.repo.CreateBranch(entry.Branch, entry.Head); err != nil {
+			return err
+		}
+	}
+
+	ref := entry.Branch
+	if ref == "" {
+		ref = entry.Head
+	}
+	if err := m.repo.CreateWorktree(entry.Path, ref); err != nil {
+		return fmt.Errorf("failed to recreate worktree: %w", err)
+	}
+
+	// Put the trashed files, including uncommitted and untracked changes, back
+	// over the fresh checkout; the new .git file is kept
+	files, err := os.ReadDir(trashed)
+	if err != nil {
+		return types.NewFileSystemError("trash-restore", trashed, "failed to read trashed worktree", err)
+	}
+	for _, file := range files {
+		if file.Name() == ".git" {
+			continue
+		}
+		target := filepath.Join(entry.Path, file.Name())
+		if err := os.RemoveAll(target); err != nil {
+			return types.NewFileSystemError("trash-restore", target, "failed to replace checked-out file", err)
+		}
+		if err := m.movePath(filepath.Join(trashed, file.Name()), target); err != nil {
+			return types.NewFileSystemError("trash-restore", target, "failed to restore file", err)
+		}
+	}
+
+	if err := os.RemoveAll(filepath.Join(root, entry.ID)); err != nil {
+		m.warn("Failed to remove trash entry %s: %v", entry.ID, err)
+	}
+
+	m.completed("Restored worktree: %s", entry.Path)
+	return nil
+}
+
+// TrashEmpty permanently removes trashed worktrees
+func (m *Manager) TrashEmpty(options TrashEmptyOptions) error {
+	entries, err := loadTrashEntries()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	var targets []*TrashEntry
+	for _, entry := range entries {
+		if !options.ExpiredOnly || m.trashExpired(entry, now) {
+			targets = append(targets, entry)
+		}
+	}
+
+	if len(targets) == 0 {
+		m.ui.Info("Nothing to remove from the trash")
+		return nil
+	}
+
+	if !options.Yes {
+		if err := m.confirm(ConfirmTrashEmpty, fmt.Sprintf("Permanently remove %d trashed worktrees?", len(targets))); err != nil {
+			m.ui.Info("Trash not emptied")
+			return nil
+		}
+	}
+
+	root, err := trashRoot()
+	if err != nil {
+		return err
+	}
+	for _, entry := range targets {
+		if err := os.RemoveAll(filepath.Join(root, entry.ID)); err != nil {
+			return types.NewFileSystemError("trash-empty", entry.ID, "failed to remove trash entry", err)
+		}
+	}
+
+	m.completed("Removed %d trashed worktrees", len(targets))
+	return nil
+}
@@ -0,0 +1,47 @@
+package worktree
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/awhite/wtree/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestManager_moveToTrash(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+
+	worktreePath := filepath.Join(t.TempDir(), "repo-feature")
+	require.NoError(t, os.MkdirAll(worktreePath, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(worktreePath, "wip.txt"), []byte("unsaved"), 0644))
+
+	config := types.DefaultWTreeConfig()
+	m := &Manager{repo: &MockGitRepo{}, globalConfig: config}
+	assert.False(t, m.useTrash(DeleteOptions{}))
+	assert.True(t, m.useTrash(DeleteOptions{Trash: true}))
+
+	entry, err := m.moveToTrash(&types.WorktreeInfo{Path: worktreePath, Branch: "feature/x"})
+	require.NoError(t, err)
+	assert.NoDirExists(t, worktreePath)
+	assert.Contains(t, entry.ID, "feature-x")
+
+	entries, err := loadTrashEntries()
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, entry.ID, entries[0].ID)
+	assert.Equal(t, worktreePath, entries[0].Path)
+
+	root, err := trashRoot()
+	require.NoError(t, err)
+	assert.FileExists(t, filepath.Join(root, entry.ID, "worktree", "wip.txt"))
+
+	// Retention and the trash.enabled default
+	assert.False(t, m.trashExpired(entries[0], time.Now()))
+	assert.True(t, m.trashExpired(entries[0], time.Now().AddDate(0, 0, 8)))
+	config.Trash.Enabled = true
+	assert.True(t, m.useTrash(DeleteOptions{}))
+	assert.False(t, m.useTrash(DeleteOptions{Permanent: true}))
+}
@@ -0,0 +1,171 @@
+package worktree
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+
+	"github.com/awhite/wtree/pkg/types"
+)
+
+// Operations whose confirmation can be answered "always"
+const (
+	ConfirmDelete     = "delete"
+	ConfirmCleanup    = "cleanup"
+	ConfirmPRCleanup  = "pr-cleanup"
+	ConfirmTrashEmpty = "trash-empty"
+)
+
+// trustStore is the local record of decisions the user asked wtree to
+// remember, kept in trust.json in dataDir
+type trustStore struct {
+	// Confirmations maps a repository's git common dir to the operations
+	// approved "always" there
+	Confirmations map[string][]string `json:"confirmations"`
+
+	// HookSecurity maps a repository's git common dir to the digest of the
+	// .wtreerc whose hooks.security: off the user acknowledged there
+	HookSecurity map[string]string `json:"hook_security,omitempty"`
+}
+
+// trustFile returns the path of the trust store
+func trustFile() (string, error) {
+	dir, err := dataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "trust.json"), nil
+}
+
+// loadTrust reads the trust store; a missing store is empty
+func loadTrust() (*trustStore, error) {
+	store := &trustStore{Confirmations: make(map[string][]string), HookSecurity: make(map[string]string)}
+	path, err := trustFile()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return store, nil
+	}
+	if err != nil {
+		return nil, types.NewFileSystemError("read-trust", path, "failed to read trust store", err)
+	}
+	if err := json.Unmarshal(data, store); err != nil {
+		return nil, types.NewFileSystemError("read-trust", path, "trust store is corrupt", err)
+	}
+	if store.Confirmations == nil {
+		store.Confirmations = make(map[string][]string)
+	}
+	if store.HookSecurity == nil {
+		store.HookSecurity = make(map[string]string)
+	}
+	return store, nil
+}
+
+// save writes the store atomically so a crash never leaves it half written
+func (s *trustStore) save() error {
+	path, err := trustFile()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return types.NewFileSystemError("write-trust", path, "failed to create data directory", err)
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode trust store: %w", err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return types.NewFileSystemError("write-trust", tmp, "failed to write trust store", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return types.NewFileSystemError("write-trust", path, "failed to replace trust store", err)
+	}
+	return nil
+}
+
+// trustKey identifies the repository in the trust store
+func (m *Manager) trustKey() (string, error) {
+	commonDir, err := m.repo.GetGitCommonDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Abs(commonDir)
+}
+
+// alwaysConfirmed reports whether operation was approved "always" in this
+// repository. Any failure to tell means asking again.
+func (m *Manager) alwaysConfirmed(operation string) bool {
+	if operation == "" || m.repo == nil {
+		return false
+	}
+	key, err := m.trustKey()
+	if err != nil {
+		return false
+	}
+	store, err := loadTrust()
+	if err != nil {
+		return false
+	}
+	return slices.Contains(store.Confirmations[key], operation)
+}
+
+// rememberConfirmation records operation as approved "always" in this
+// repository
+func (m *Manager) rememberConfirmation(operation string) error {
+	key, err := m.trustKey()
+	if err != nil {
+		return err
+	}
+	store, err := loadTrust()
+	if err != nil {
+		return err
+	}
+	if slices.Contains(store.Confirmations[key], operation) {
+		return nil
+	}
+	store.Confirmations[key] = append(store.Confirmations[key], operation)
+	sort.Strings(store.Confirmations[key])
+	return store.save()
+}
+
+// TrustedOperations returns the operations approved "always" in this
+// repository
+func (m *Manager) TrustedOperations() ([]string, error) {
+	key, err := m.trustKey()
+	if err != nil {
+		return nil, err
+	}
+	store, err := loadTrust()
+	if err != nil {
+		return nil, err
+	}
+	return store.Confirmations[key], nil
+}
+
+// ResetTrust forgets the "always" answers and hooks.security
+// acknowledgements given in this repository, or in every repository when
+// all is set, so those operations ask again
+func (m *Manager) ResetTrust(all bool) error {
+	store, err := loadTrust()
+	if err != nil {
+		return err
+	}
+	if all {
+		store.Confirmations = make(map[string][]string)
+		store.HookSecurity = make(map[string]string)
+	} else {
+		key, err := m.trustKey()
+		if err != nil {
+			return err
+		}
+		delete(store.Confirmations, key)
+		delete(store.HookSecurity, key)
+	}
+	return store.save()
+}
@@ -0,0 +1,41 @@
+package worktree
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestManager_confirmAlways(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	recorder := &recordingObserver{always: true}
+	m := &Manager{repo: &MockGitRepo{}}
+	m.Subscribe(recorder)
+
+	require.NoError(t, m.confirm(ConfirmCleanup, "Clean up 2 worktrees?"))
+	require.NoError(t, m.confirm(ConfirmCleanup, "Clean up 3 worktrees?"))
+	assert.Equal(t, []string{
+		"confirm: Clean up 2 worktrees?",
+		"completed: Clean up 3 worktrees? yes (always for this repository; reset with 'wtree config trust --reset')",
+	}, recorder.events, "the second cleanup is not asked")
+
+	operations, err := m.TrustedOperations()
+	require.NoError(t, err)
+	assert.Equal(t, []string{ConfirmCleanup}, operations)
+	assert.False(t, m.alwaysConfirmed(ConfirmDelete), "other operations still ask")
+
+	require.NoError(t, m.ResetTrust(false))
+	assert.False(t, m.alwaysConfirmed(ConfirmCleanup))
+}
+
+func TestManager_confirmAlwaysNeedsOperation(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	m := &Manager{repo: &MockGitRepo{}}
+	m.Subscribe(&recordingObserver{always: true})
+
+	require.NoError(t, m.confirm("", "Continue?"))
+	operations, err := m.TrustedOperations()
+	require.NoError(t, err)
+	assert.Empty(t, operations)
+}
@@ -0,0 +1,71 @@
+package worktree
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// urlPlaceholder matches a {name} placeholder in an open_urls entry
+var urlPlaceholder = regexp.MustCompile(`\{[a-z_]+\}`)
+
+// expandURLTemplate substitutes {name} placeholders in an open_urls entry.
+// Values are escaped for where they sit: query-escaped after the "?",
+// path-escaped before it, so a branch like feature/a&b can't add path
+// segments or parameters. Only a placeholder the entry starts with, such as
+// {pr_url}, is a URL of its own and goes in as is. It reports false when a
+// placeholder has no value (e.g. {pr_url} outside a PR worktree) so the URL
+// can be skipped.
+func expandURLTemplate(template string, vars map[string]string) (string, bool) {
+	query := strings.IndexByte(template, '?')
+	var expanded strings.Builder
+	last := 0
+	for _, loc := range urlPlaceholder.FindAllStringIndex(template, -1) {
+		value := vars[template[loc[0]+1:loc[1]-1]]
+		if value == "" {
+			return "", false
+		}
+		switch {
+		case loc[0] == 0:
+		case query >= 0 && loc[0] > query:
+			value = url.QueryEscape(value)
+		default:
+			value = url.PathEscape(value)
+		}
+		expanded.WriteString(template[last:loc[0]])
+		expanded.WriteString(value)
+		last = loc[1]
+	}
+	expanded.WriteString(template[last:])
+	return expanded.String(), true
+}
+
+// openConfiguredURLs opens the project's open_urls in the browser
+func (m *Manager) openConfiguredURLs(vars map[string]string) {
+	if m.projectConfig == nil || len(m.projectConfig.OpenURLs) == 0 {
+		return
+	}
+
+	if _, ok := vars["repo"]; !ok {
+		vars["repo"] = m.repo.GetRepoName()
+	}
+
+	for _, template := range m.projectConfig.OpenURLs {
+		url, ok := expandURLTemplate(template, vars)
+		if !ok {
+			if m.globalConfig.UI.Verbose {
+				m.ui.Info("Skipping URL with unresolved placeholders: %s", template)
+			}
+			continue
+		}
+		if !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "https://") {
+			m.warn("Skipping non-web URL: %s", url)
+			continue
+		}
+
+		m.ui.Info("Opening %s", url)
+		if err := m.ui.OpenURL(url); err != nil {
+			m.warn("Failed to open URL: %v", err)
+		}
+	}
+}
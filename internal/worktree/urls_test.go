@@ -0,0 +1,54 @@
+package worktree
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpandURLTemplate(t *testing.T) {
+	vars := map[string]string{
+		"branch":    "feature-login",
+		"repo":      "shop",
+		"pr_url":    "",
+		"pr_number": "",
+	}
+
+	url, ok := expandURLTemplate("https://{branch}.staging.example.com/{repo}", vars)
+	assert.True(t, ok)
+	assert.Equal(t, "https://feature-login.staging.example.com/shop", url)
+
+	_, ok = expandURLTemplate("{pr_url}", vars)
+	assert.False(t, ok, "unset placeholder should skip the URL")
+
+	vars["pr_url"] = "https://github.com/acme/shop/pull/7"
+	url, ok = expandURLTemplate("{pr_url}/files", vars)
+	assert.True(t, ok)
+	assert.Equal(t, "https://github.com/acme/shop/pull/7/files", url)
+}
+
+func TestExpandURLTemplate_Escapes(t *testing.T) {
+	vars := map[string]string{
+		"branch":  "feature/a&b c",
+		"repo":    "shop",
+		"pr_url":  "https://github.com/acme/shop/pull/7",
+		"missing": "",
+	}
+
+	url, ok := expandURLTemplate("https://ci.example.com/{repo}/branches/{branch}", vars)
+	assert.True(t, ok)
+	assert.Equal(t, "https://ci.example.com/shop/branches/feature%2Fa&b%20c", url, "a slash can't add a path segment")
+
+	url, ok = expandURLTemplate("https://ci.example.com/builds?branch={branch}&repo={repo}", vars)
+	assert.True(t, ok)
+	assert.Equal(t, "https://ci.example.com/builds?branch=feature%2Fa%26b+c&repo=shop", url, "an & can't add a parameter")
+
+	url, ok = expandURLTemplate("{pr_url}?tab={branch}", vars)
+	assert.True(t, ok)
+	assert.Equal(t, "https://github.com/acme/shop/pull/7?tab=feature%2Fa%26b+c", url, "a leading URL goes in as is")
+
+	_, ok = expandURLTemplate("https://example.com/{missing}", vars)
+	assert.False(t, ok)
+	_, ok = expandURLTemplate("https://example.com/{unknown}", vars)
+	assert.False(t, ok)
+}
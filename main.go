@@ -4,10 +4,11 @@ import (
 	"os"
 
 	"github.com/awhite/wtree/cmd"
+	"github.com/awhite/wtree/pkg/types"
 )
 
 func main() {
 	if err := cmd.Execute(); err != nil {
-		os.Exit(1)
+		os.Exit(types.ExitCode(err))
 	}
 }
@@ -0,0 +1,52 @@
+package plugin
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Command is a subcommand a plugin adds to wtree
+type Command struct {
+	Name  string // wtree <Name>; must not clash with a built-in command
+	Short string // One-line help
+	Long  string // Full help, optional
+	Run   func(ctx *Context, args []string) error
+}
+
+var (
+	commandsMu sync.Mutex
+	commands   = map[string]Command{}
+)
+
+// RegisterCommand adds a command, typically from a plugin package's init.
+// Registering the same name twice is an error.
+func RegisterCommand(cmd Command) error {
+	if cmd.Name == "" {
+		return fmt.Errorf("plugin command needs a name")
+	}
+	if cmd.Run == nil {
+		return fmt.Errorf("plugin command '%s' needs a Run function", cmd.Name)
+	}
+
+	commandsMu.Lock()
+	defer commandsMu.Unlock()
+	if _, exists := commands[cmd.Name]; exists {
+		return fmt.Errorf("plugin command '%s' is already registered", cmd.Name)
+	}
+	commands[cmd.Name] = cmd
+	return nil
+}
+
+// Commands returns the registered commands sorted by name
+func Commands() []Command {
+	commandsMu.Lock()
+	defer commandsMu.Unlock()
+
+	registered := make([]Command, 0, len(commands))
+	for _, cmd := range commands {
+		registered = append(registered, cmd)
+	}
+	sort.Slice(registered, func(i, j int) bool { return registered[i].Name < registered[j].Name })
+	return registered
+}
@@ -0,0 +1,26 @@
+package plugin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegisterCommand(t *testing.T) {
+	t.Cleanup(func() { commands = map[string]Command{} })
+	run := func(ctx *Context, args []string) error { return nil }
+
+	require.NoError(t, RegisterCommand(Command{Name: "zeta", Run: run}))
+	require.NoError(t, RegisterCommand(Command{Name: "alpha", Run: run}))
+
+	assert.Error(t, RegisterCommand(Command{Name: "alpha", Run: run}))
+	assert.Error(t, RegisterCommand(Command{Run: run}))
+	assert.Error(t, RegisterCommand(Command{Name: "beta"}))
+
+	var names []string
+	for _, cmd := range Commands() {
+		names = append(names, cmd.Name)
+	}
+	assert.Equal(t, []string{"alpha", "zeta"}, names)
+}
@@ -0,0 +1,32 @@
+package plugin
+
+// Event names a point in a worktree's lifecycle. The values match the hook
+// names used in .wtreerc.
+type Event string
+
+// Lifecycle events
+const (
+	EventPreCreate  Event = "pre_create"
+	EventPostCreate Event = "post_create"
+	EventPreDelete  Event = "pre_delete"
+	EventPostDelete Event = "post_delete"
+	EventPreMerge   Event = "pre_merge"
+	EventPostMerge  Event = "post_merge"
+	EventPreRebase  Event = "pre_rebase"
+	EventPostRebase Event = "post_rebase"
+	EventPreSwitch  Event = "pre_switch"
+	EventPostSwitch Event = "post_switch"
+	EventPreSync    Event = "pre_sync"
+	EventPostSync   Event = "post_sync"
+	EventOnPrune    Event = "on_prune"
+)
+
+// EventPayload describes the worktree a lifecycle event is about
+type EventPayload struct {
+	Event        Event
+	Branch       string
+	TargetBranch string // Merge source or rebase target; empty for other events
+	RepoPath     string
+	WorktreePath string
+	Environment  map[string]string // WTREE_* variables also passed to hooks
+}
@@ -0,0 +1,23 @@
+// Package plugin is the stable surface for wtree plugins: the context a
+// plugin is given, the lifecycle events it can observe and the commands it
+// can register. It follows semantic versioning as APIVersion, independently
+// of wtree releases: within a major version, nothing here is removed or
+// changed incompatibly. Plugins should import only this package, never
+// wtree's internal packages.
+package plugin
+
+// APIVersion is the version of the plugin API this package provides
+const APIVersion = "1.0.0"
+
+// Context is handed to a plugin when it is loaded and when its commands run.
+// The managers are wtree's own; plugins should treat them as opaque handles
+// and use them only through interfaces they declare themselves.
+type Context struct {
+	WorktreeManager interface{}
+	GitRepo         interface{}
+	ConfigManager   interface{}
+	UIManager       interface{}
+
+	// PluginData is free for plugins to keep state in between calls
+	PluginData map[string]interface{}
+}
@@ -0,0 +1,59 @@
+package types
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// aliasPlaceholder matches {N} argument placeholders and {*} for all arguments
+var aliasPlaceholder = regexp.MustCompile(`\{(\d+|\*)\}`)
+
+// ExpandAlias turns an alias expansion such as "pr create {1} --open" and the
+// arguments given to the alias into wtree arguments. {N} is replaced by the
+// Nth argument and {*} by all of them; arguments no placeholder used are
+// appended, as git does for its aliases.
+func ExpandAlias(expansion string, args []string) ([]string, error) {
+	used := make([]bool, len(args))
+	var expanded []string
+
+	for _, token := range strings.Fields(expansion) {
+		if token == "{*}" {
+			expanded = append(expanded, args...)
+			for i := range used {
+				used[i] = true
+			}
+			continue
+		}
+
+		var missing error
+		token = aliasPlaceholder.ReplaceAllStringFunc(token, func(match string) string {
+			name := match[1 : len(match)-1]
+			if name == "*" {
+				for i := range used {
+					used[i] = true
+				}
+				return strings.Join(args, " ")
+			}
+			n, _ := strconv.Atoi(name)
+			if n < 1 || n > len(args) {
+				missing = fmt.Errorf("missing argument for %s", match)
+				return match
+			}
+			used[n-1] = true
+			return args[n-1]
+		})
+		if missing != nil {
+			return nil, missing
+		}
+		expanded = append(expanded, token)
+	}
+
+	for i, arg := range args {
+		if !used[i] {
+			expanded = append(expanded, arg)
+		}
+	}
+	return expanded, nil
+}
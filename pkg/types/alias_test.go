@@ -0,0 +1,67 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpandAlias(t *testing.T) {
+	tests := []struct {
+		name      string
+		expansion string
+		args      []string
+		want      []string
+		wantErr   bool
+	}{
+		{
+			name:      "positional",
+			expansion: "pr create {1} --open",
+			args:      []string{"123"},
+			want:      []string{"pr", "create", "123", "--open"},
+		},
+		{
+			name:      "argument with spaces stays one argument",
+			expansion: "create -b {1} main",
+			args:      []string{"my branch"},
+			want:      []string{"create", "-b", "my branch", "main"},
+		},
+		{
+			name:      "unused arguments are appended",
+			expansion: "delete {1} --delete-branch --force",
+			args:      []string{"feature", "--dry-run"},
+			want:      []string{"delete", "feature", "--delete-branch", "--force", "--dry-run"},
+		},
+		{
+			name:      "all arguments",
+			expansion: "status {*} --verbose",
+			args:      []string{"a", "b"},
+			want:      []string{"status", "a", "b", "--verbose"},
+		},
+		{
+			name:      "no placeholders",
+			expansion: "cleanup --merged-only",
+			args:      []string{"--auto"},
+			want:      []string{"cleanup", "--merged-only", "--auto"},
+		},
+		{
+			name:      "missing argument",
+			expansion: "pr create {2}",
+			args:      []string{"123"},
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ExpandAlias(tt.expansion, tt.args)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
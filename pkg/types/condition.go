@@ -0,0 +1,190 @@
+package types
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Condition restricts a config entry to matching environments. All fields
+// that are set must match.
+type Condition struct {
+	OS     StringList `yaml:"os"`     // GOOS values, e.g. [darwin, linux]; "macos" is accepted for darwin
+	Branch StringList `yaml:"branch"` // Branch globs, e.g. "release/*"
+	PR     *bool      `yaml:"pr"`     // Whether the worktree is for a pull request
+}
+
+// ConditionEnv is what a Condition is evaluated against
+type ConditionEnv struct {
+	OS     string
+	Branch string
+	PR     bool
+}
+
+// KnownOS lists the operating systems accepted in when: {os: ...} conditions
+var KnownOS = []string{"windows", "darwin", "linux", "freebsd", "openbsd", "netbsd"}
+
+// NormalizeOS maps OS aliases onto GOOS names
+func NormalizeOS(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "macos" || name == "osx" {
+		return "darwin"
+	}
+	return name
+}
+
+// Matches reports whether the condition holds in env
+func (c *Condition) Matches(env ConditionEnv) bool {
+	if len(c.OS) > 0 && !slices.ContainsFunc(c.OS, func(name string) bool { return NormalizeOS(name) == env.OS }) {
+		return false
+	}
+	if len(c.Branch) > 0 && !slices.ContainsFunc(c.Branch, func(pattern string) bool {
+		matched, _ := path.Match(pattern, env.Branch)
+		return matched
+	}) {
+		return false
+	}
+	if c.PR != nil && *c.PR != env.PR {
+		return false
+	}
+	return true
+}
+
+// StringList decodes from either a single string or a list of strings
+type StringList []string
+
+// UnmarshalYAML implements yaml.Unmarshaler
+func (l *StringList) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		*l = StringList{value.Value}
+		return nil
+	}
+	var list []string
+	if err := value.Decode(&list); err != nil {
+		return err
+	}
+	*l = list
+	return nil
+}
+
+// HookEntry is a hook written as an object, e.g.
+// {run: "cp .env.production .env", when: {branch: "release/*"}}
+type HookEntry struct {
+	Run    string     `yaml:"run"`
+	When   *Condition `yaml:"when"`
+	Output string     `yaml:"output"` // HookOutputStream (the default) or HookOutputQuiet
+}
+
+// How a hook's output is shown while it runs, set by HookEntry.Output
+const (
+	HookOutputStream = "stream" // Each line as it is printed
+	HookOutputQuiet  = "quiet"  // Only when the hook fails, or with --verbose
+)
+
+// HooksFor returns the commands for event whose conditions hold in env
+func (c *ProjectConfig) HooksFor(event HookEvent, env ConditionEnv) []string {
+	var hooks []string
+	for _, hook := range c.HookEntriesFor(event, env) {
+		hooks = append(hooks, hook.Run)
+	}
+	return hooks
+}
+
+// HookEntriesFor returns the hooks for event whose conditions hold in env,
+// with their conditions and output settings
+func (c *ProjectConfig) HookEntriesFor(event HookEvent, env ConditionEnv) []HookEntry {
+	conditions := c.HookConditions[event]
+	outputs := c.HookOutputs[event]
+	var hooks []HookEntry
+	for i, hook := range c.Hooks[event] {
+		entry := HookEntry{Run: hook}
+		if i < len(conditions) {
+			entry.When = conditions[i]
+		}
+		if i < len(outputs) {
+			entry.Output = outputs[i]
+		}
+		if entry.When != nil && !entry.When.Matches(env) {
+			continue
+		}
+		hooks = append(hooks, entry)
+	}
+	return hooks
+}
+
+// decodeHooks decodes the hooks mapping, whose entries are commands or
+// HookEntry objects. Conditions and output settings are returned
+// index-aligned with the commands and only for events that have at least one.
+func decodeHooks(node *yaml.Node) (map[HookEvent][]string, map[HookEvent][]*Condition, map[HookEvent][]string, error) {
+	if node == nil || (node.Kind == yaml.ScalarNode && node.Tag == "!!null") {
+		return nil, nil, nil, nil
+	}
+	if node.Kind != yaml.MappingNode {
+		return nil, nil, nil, fmt.Errorf("line %d: hooks must be a mapping of events to commands", node.Line)
+	}
+
+	hooks := make(map[HookEvent][]string)
+	var conditions map[HookEvent][]*Condition
+	var outputs map[HookEvent][]string
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		event := HookEvent(node.Content[i].Value)
+		if event == HookOnCleanup {
+			event = HookOnPrune
+		}
+		if _, ok := hooks[event]; ok && event == HookOnPrune {
+			return nil, nil, nil, fmt.Errorf("line %d: hooks.%s and hooks.%s are the same event; use one", node.Content[i].Line, HookOnPrune, HookOnCleanup)
+		}
+		list := node.Content[i+1]
+		if list.Kind != yaml.SequenceNode {
+			return nil, nil, nil, fmt.Errorf("line %d: hooks.%s must be a list", list.Line, event)
+		}
+
+		var eventConditions []*Condition
+		var eventOutputs []string
+		hasCondition, hasOutput := false, false
+		for _, entry := range list.Content {
+			var hook HookEntry
+			switch entry.Kind {
+			case yaml.ScalarNode:
+				hook.Run = entry.Value
+			case yaml.MappingNode:
+				if err := entry.Decode(&hook); err != nil {
+					return nil, nil, nil, err
+				}
+				if hook.Run == "" {
+					return nil, nil, nil, fmt.Errorf("line %d: hooks.%s entry needs a run command", entry.Line, event)
+				}
+				switch hook.Output {
+				case "", HookOutputStream, HookOutputQuiet:
+				default:
+					return nil, nil, nil, fmt.Errorf("line %d: hooks.%s output must be stream or quiet, got '%s'", entry.Line, event, hook.Output)
+				}
+			default:
+				return nil, nil, nil, fmt.Errorf("line %d: hooks.%s entries must be a command or {run, when, output}", entry.Line, event)
+			}
+			hooks[event] = append(hooks[event], hook.Run)
+			eventConditions = append(eventConditions, hook.When)
+			eventOutputs = append(eventOutputs, hook.Output)
+			hasCondition = hasCondition || hook.When != nil
+			hasOutput = hasOutput || hook.Output != ""
+		}
+
+		if hasCondition {
+			if conditions == nil {
+				conditions = make(map[HookEvent][]*Condition)
+			}
+			conditions[event] = eventConditions
+		}
+		if hasOutput {
+			if outputs == nil {
+				outputs = make(map[HookEvent][]string)
+			}
+			outputs[event] = eventOutputs
+		}
+	}
+
+	return hooks, conditions, outputs, nil
+}
@@ -0,0 +1,110 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestCondition_Matches(t *testing.T) {
+	yes, no := true, false
+
+	tests := []struct {
+		name      string
+		condition Condition
+		env       ConditionEnv
+		expected  bool
+	}{
+		{"empty matches everything", Condition{}, ConditionEnv{OS: "linux", Branch: "main"}, true},
+		{"os alias", Condition{OS: StringList{"macos"}}, ConditionEnv{OS: "darwin"}, true},
+		{"os mismatch", Condition{OS: StringList{"windows"}}, ConditionEnv{OS: "linux"}, false},
+		{"branch glob", Condition{Branch: StringList{"release/*"}}, ConditionEnv{Branch: "release/2.1"}, true},
+		{"branch glob mismatch", Condition{Branch: StringList{"release/*"}}, ConditionEnv{Branch: "feature/x"}, false},
+		{"pr required", Condition{PR: &yes}, ConditionEnv{PR: true}, true},
+		{"pr excluded", Condition{PR: &no}, ConditionEnv{PR: true}, false},
+		{"all fields must hold", Condition{Branch: StringList{"release/*"}, PR: &yes}, ConditionEnv{Branch: "release/2.1"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.condition.Matches(tt.env))
+		})
+	}
+}
+
+func TestProjectConfig_HooksFor(t *testing.T) {
+	data := `
+version: "1.0"
+hooks:
+  post_create:
+    - "npm install"
+    - run: "cp .env.production .env"
+      when: {branch: "release/*"}
+    - run: "gh pr checks"
+      when: {pr: true}
+  pre_delete:
+    - "echo bye"
+`
+
+	var config ProjectConfig
+	require.NoError(t, yaml.Unmarshal([]byte(data), &config))
+
+	assert.Equal(t, []string{"npm install", "cp .env.production .env", "gh pr checks"}, config.Hooks[HookPostCreate])
+	assert.NotContains(t, config.HookConditions, HookPreDelete)
+
+	assert.Equal(t, []string{"npm install"}, config.HooksFor(HookPostCreate, ConditionEnv{Branch: "feature/x"}))
+	assert.Equal(t, []string{"npm install", "cp .env.production .env"}, config.HooksFor(HookPostCreate, ConditionEnv{Branch: "release/2.1"}))
+	assert.Equal(t, []string{"npm install", "gh pr checks"}, config.HooksFor(HookPostCreate, ConditionEnv{Branch: "fix", PR: true}))
+	assert.Equal(t, []string{"echo bye"}, config.HooksFor(HookPreDelete, ConditionEnv{}))
+}
+
+func TestProjectConfig_HookEntriesForOutput(t *testing.T) {
+	data := `
+hooks:
+  post_create:
+    - "npm ci"
+    - run: "npm run build"
+      output: quiet
+      when: {os: linux}
+  pre_delete:
+    - "echo bye"
+`
+
+	var config ProjectConfig
+	require.NoError(t, yaml.Unmarshal([]byte(data), &config))
+
+	assert.NotContains(t, config.HookOutputs, HookPreDelete)
+	assert.Equal(t, []HookEntry{
+		{Run: "npm ci"},
+		{Run: "npm run build", Output: HookOutputQuiet, When: &Condition{OS: StringList{"linux"}}},
+	}, config.HookEntriesFor(HookPostCreate, ConditionEnv{OS: "linux"}))
+	assert.Equal(t, []HookEntry{{Run: "npm ci"}}, config.HookEntriesFor(HookPostCreate, ConditionEnv{OS: "darwin"}))
+}
+
+func TestProjectConfig_UnmarshalHooksInvalid(t *testing.T) {
+	var config ProjectConfig
+	assert.Error(t, yaml.Unmarshal([]byte("hooks:\n  post_create: \"make\"\n"), &config))
+	assert.Error(t, yaml.Unmarshal([]byte("hooks:\n  post_create:\n    - when: {pr: true}\n"), &config))
+	assert.Error(t, yaml.Unmarshal([]byte("hooks:\n  post_create:\n    - {run: make, output: loud}\n"), &config))
+}
+
+func TestProjectConfig_UnmarshalOnCleanup(t *testing.T) {
+	var config ProjectConfig
+	require.NoError(t, yaml.Unmarshal([]byte("hooks:\n  on_cleanup:\n    - \"make clean\"\n"), &config))
+	assert.Equal(t, map[HookEvent][]string{HookOnPrune: {"make clean"}}, config.Hooks)
+
+	err := yaml.Unmarshal([]byte("hooks:\n  on_prune:\n    - a\n  on_cleanup:\n    - b\n"), &config)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "same event")
+}
+
+func TestProjectConfig_UnmarshalHookSecurity(t *testing.T) {
+	var config ProjectConfig
+	require.NoError(t, yaml.Unmarshal([]byte("hooks:\n  security: off\n  post_create:\n    - \"make\"\n"), &config))
+	assert.Equal(t, HookSecurityOff, config.HookSecurity)
+	assert.Equal(t, map[HookEvent][]string{HookPostCreate: {"make"}}, config.Hooks)
+
+	assert.Error(t, yaml.Unmarshal([]byte("hooks:\n  security: [off]\n"), &config))
+}
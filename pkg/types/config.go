@@ -1,11 +1,17 @@
 package types
 
-import "time"
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
 
-// WTreeConfig represents the global WTree tool configuration
+// WTreeConfig represents the global WTree tool configuration. Every key has a
+// desc tag, which `wtree config docs` turns into reference documentation.
 type WTreeConfig struct {
 	// Editor preferences
-	Editor string `yaml:"editor" mapstructure:"editor"`
+	Editor string `yaml:"editor" mapstructure:"editor" desc:"Editor opened by --open and wtree editors (code, cursor, vim, ...)"`
 
 	// UI settings
 	UI UIConfig `yaml:"ui" mapstructure:"ui"`
@@ -21,40 +27,209 @@ type WTreeConfig struct {
 
 	// Performance settings
 	Performance PerformanceConfig `yaml:"performance" mapstructure:"performance"`
+
+	// Resource limits
+	Limits LimitsConfig `yaml:"limits" mapstructure:"limits"`
+
+	// Trash for deleted worktrees
+	Trash TrashConfig `yaml:"trash" mapstructure:"trash"`
+
+	// Personal files copied into PR worktrees by `wtree pr sync-local`
+	Review ReviewConfig `yaml:"review" mapstructure:"review"`
+
+	// Fetching remote refs before create/status
+	Fetch FetchConfig `yaml:"fetch" mapstructure:"fetch"`
+
+	// Project setup after create
+	Setup SetupConfig `yaml:"setup" mapstructure:"setup"`
+
+	// Outbound notifications (Slack, Discord or a webhook) for selected events
+	Notifications []NotificationConfig `yaml:"notifications" mapstructure:"notifications"`
+
+	// Which .wtreerc applies when wtree runs inside a linked worktree
+	ProjectConfigSource string `yaml:"project_config_source" mapstructure:"project_config_source" desc:"Which .wtreerc applies inside a linked worktree: worktree (its own), main (the main checkout's) or merge (the main checkout's with the worktree's keys on top)"`
+
+	// Separate worktree paths, ownership and state per user on shared clones
+	MultiUser bool `yaml:"multi_user" mapstructure:"multi_user" desc:"Name default paths {repo}-{user}-{branch} on clones shared by several users, and record who created each worktree"`
+
+	// Stash uncommitted changes when leaving or deleting a worktree
+	Autostash bool `yaml:"autostash" mapstructure:"autostash" desc:"Stash a dirty worktree's changes as wtree-auto/<branch> when switching away from or deleting it, and restore them on returning to the branch"`
+
+	// Command aliases, e.g. rev: "pr create {1} --open"
+	Aliases map[string]string `yaml:"aliases" mapstructure:"aliases" desc:"Command shortcuts expanded like git aliases; {1}, {2}... are arguments and {*} all of them"`
+
+	// Preset flags per command, e.g. create: {open: true}
+	Defaults map[string]map[string]interface{} `yaml:"defaults" mapstructure:"defaults" desc:"Flag values preset per command (keyed by command, e.g. create or \"pr create\"); flags given on the command line win"`
 }
 
 // UIConfig represents UI/output configuration
 type UIConfig struct {
-	Colors             bool `yaml:"colors" mapstructure:"colors"`
-	ProgressBars       bool `yaml:"progress_bars" mapstructure:"progress_bars"`
-	Verbose            bool `yaml:"verbose" mapstructure:"verbose"`
-	ConfirmDestructive bool `yaml:"confirm_destructive" mapstructure:"confirm_destructive"`
+	Colors             bool   `yaml:"colors" mapstructure:"colors" desc:"Colorize output"`
+	ProgressBars       bool   `yaml:"progress_bars" mapstructure:"progress_bars" desc:"Show progress bars and spinners"`
+	Verbose            bool   `yaml:"verbose" mapstructure:"verbose" desc:"Print hook output and other details"`
+	ConfirmDestructive bool   `yaml:"confirm_destructive" mapstructure:"confirm_destructive" desc:"Ask before destructive operations"`
+	Accessible         bool   `yaml:"accessible" mapstructure:"accessible" desc:"Plain text lines instead of glyphs, spinners and redrawn progress, for screen readers and logs"`
+	TimeFormat         string `yaml:"time_format" mapstructure:"time_format" desc:"How tables show times: relative (3 days ago) or absolute (ISO 8601)"`
 }
 
 // GitHubConfig represents GitHub integration configuration
 type GitHubConfig struct {
-	CLICommand   string        `yaml:"cli_command" mapstructure:"cli_command"`
-	CacheTimeout time.Duration `yaml:"cache_timeout" mapstructure:"cache_timeout"`
+	CLICommand            string        `yaml:"cli_command" mapstructure:"cli_command" desc:"GitHub CLI executable used by pr commands"`
+	CacheTimeout          time.Duration `yaml:"cache_timeout" mapstructure:"cache_timeout" desc:"How long pull request lookups are cached"`
+	MaxConcurrentRequests int           `yaml:"max_concurrent_requests" mapstructure:"max_concurrent_requests" desc:"GitHub CLI calls run at once by bulk PR operations (1-16)"`
 }
 
 // HookConfig represents hook execution configuration
 type HookConfig struct {
-	Timeout      time.Duration `yaml:"timeout" mapstructure:"timeout"`
-	AllowFailure bool          `yaml:"allow_failure" mapstructure:"allow_failure"`
-	MaxParallel  int           `yaml:"max_parallel" mapstructure:"max_parallel"`
+	Timeout      time.Duration `yaml:"timeout" mapstructure:"timeout" desc:"Time limit for each hook command"`
+	AllowFailure bool          `yaml:"allow_failure" mapstructure:"allow_failure" desc:"Continue when a hook fails"`
+	MaxParallel  int           `yaml:"max_parallel" mapstructure:"max_parallel" desc:"Hooks run at once (1-10)"`
+
+	Security HookSecurityPolicy `yaml:"security" mapstructure:"security"`
+}
+
+// HookSecurityPolicy is the user's own hook command checking, which applies
+// to every repository's .wtreerc
+type HookSecurityPolicy struct {
+	Mode  string   `yaml:"mode" mapstructure:"mode" desc:"Hook command checks, replacing each .wtreerc's hooks.security: strict, standard, warn (report what standard would reject, then run it) or off; empty leaves it to .wtreerc"`
+	Allow []string `yaml:"allow" mapstructure:"allow" desc:"Hook commands, exactly as written in .wtreerc, that run without the built-in checks"`
+	Deny  []string `yaml:"deny" mapstructure:"deny" desc:"Regular expressions for further hook commands to reject, matched case-insensitively in every mode"`
+}
+
+// Allows reports whether cmd is on the allowlist. Runs of whitespace count as
+// one space; nothing else may differ.
+func (p HookSecurityPolicy) Allows(cmd string) bool {
+	cmd = strings.Join(strings.Fields(cmd), " ")
+	for _, allowed := range p.Allow {
+		if strings.Join(strings.Fields(allowed), " ") == cmd {
+			return true
+		}
+	}
+	return false
+}
+
+// DenyPatterns compiles the deny entries, ignoring case
+func (p HookSecurityPolicy) DenyPatterns() ([]*regexp.Regexp, error) {
+	patterns := make([]*regexp.Regexp, 0, len(p.Deny))
+	for _, deny := range p.Deny {
+		pattern, err := regexp.Compile("(?i)" + deny)
+		if err != nil {
+			return nil, fmt.Errorf("'%s': %w", deny, err)
+		}
+		patterns = append(patterns, pattern)
+	}
+	return patterns, nil
 }
 
 // PathConfig represents path configuration
 type PathConfig struct {
-	WorktreeParent string `yaml:"worktree_parent" mapstructure:"worktree_parent"`
+	WorktreeParent string `yaml:"worktree_parent" mapstructure:"worktree_parent" desc:"Directory new worktrees are created in (default: the repository's parent)"`
+
+	// Directories outside the repository that copy/link sources may resolve
+	// into, e.g. a shared cache that repository symlinks point at
+	AllowedRoots []string `yaml:"allowed_roots" mapstructure:"allowed_roots" desc:"Absolute directories outside the repository that copy/link sources may resolve into"`
+
+	MaxLength int  `yaml:"max_length" mapstructure:"max_length" desc:"Longest worktree path allowed; 0 uses the platform limit (199 on Windows, leaving room under MAX_PATH for files inside, 4095 elsewhere)"`
+	Shorten   bool `yaml:"shorten" mapstructure:"shorten" desc:"Shorten worktree directory names that are too long or invalid, ending them with a hash of the full name"`
+
+	CrossDevice string `yaml:"cross_device" mapstructure:"cross_device" desc:"When a worktree or the trash is on another volume than the main checkout: copy (link_files are copied instead of symlinked), warn (symlink anyway) or abort"`
 }
 
+// Values of UIConfig.TimeFormat
+const (
+	TimeFormatRelative = "relative" // "3 days ago"
+	TimeFormatAbsolute = "absolute" // 2026-10-11T09:30:00+02:00
+)
+
+// Policies for WTreeConfig.ProjectConfigSource, also reported to hooks as
+// WTREE_CONFIG_SOURCE along with ProjectConfigDefault
+const (
+	ProjectConfigWorktree = "worktree" // The worktree's own .wtreerc
+	ProjectConfigMain     = "main"     // The main checkout's .wtreerc
+	ProjectConfigMerge    = "merge"    // The main checkout's, with the worktree's keys on top
+	ProjectConfigDefault  = "default"  // No .wtreerc was found
+)
+
+// Policies for PathConfig.CrossDevice
+const (
+	CrossDeviceCopy  = "copy"
+	CrossDeviceWarn  = "warn"
+	CrossDeviceAbort = "abort"
+)
+
 // PerformanceConfig represents performance settings
 type PerformanceConfig struct {
-	MaxConcurrentOps int           `yaml:"max_concurrent_operations" mapstructure:"max_concurrent_operations"`
-	OperationTimeout time.Duration `yaml:"operation_timeout" mapstructure:"operation_timeout"`
+	MaxConcurrentOps int           `yaml:"max_concurrent_operations" mapstructure:"max_concurrent_operations" desc:"Worktrees removed at once by cleanup or created at once by create-many"`
+	OperationTimeout time.Duration `yaml:"operation_timeout" mapstructure:"operation_timeout" desc:"Time limit for long operations"`
+}
+
+// LimitsConfig represents resource limits applied per repository
+type LimitsConfig struct {
+	MaxWorktrees int    `yaml:"max_worktrees" mapstructure:"max_worktrees" desc:"Active worktrees allowed per repository; 0 is unlimited"`
+	OnLimit      string `yaml:"on_limit" mapstructure:"on_limit" desc:"What create does at the limit: warn or block"`
+}
+
+// TrashConfig controls moving deleted worktrees to a trash area instead of removing them
+type TrashConfig struct {
+	Enabled       bool          `yaml:"enabled" mapstructure:"enabled" desc:"Move deleted worktrees to the trash instead of removing them"`
+	RetentionDays int           `yaml:"retention_days" mapstructure:"retention_days" desc:"Days trashed worktrees are kept before purging; 0 keeps them until emptied"`
+	Retention     time.Duration `yaml:"retention" mapstructure:"retention" desc:"How long trashed worktrees are kept, e.g. 2w or 36h; overrides retention_days when set"`
+}
+
+// ReviewConfig lists personal local overrides, relative to the main checkout,
+// that `wtree pr sync-local` copies into every PR worktree. Glob patterns
+// are allowed.
+type ReviewConfig struct {
+	LocalFiles []string `yaml:"local_files" mapstructure:"local_files" desc:"Personal files (globs allowed) pr sync-local copies from the main checkout into PR worktrees"`
+}
+
+// FetchConfig controls fetching remote refs automatically so ahead/behind
+// counts are current
+type FetchConfig struct {
+	Auto        bool          `yaml:"auto" mapstructure:"auto" desc:"Fetch before create and status"`
+	MinInterval time.Duration `yaml:"min_interval" mapstructure:"min_interval" desc:"Skip the fetch when the last one is newer than this"`
+	Remote      string        `yaml:"remote" mapstructure:"remote" desc:"Remote to fetch (default: remote.pushDefault or origin)"`
 }
 
+// SetupConfig controls how project setup (copy/link files and post_create
+// hooks) runs after a worktree is created
+type SetupConfig struct {
+	Background bool `yaml:"background" mapstructure:"background" desc:"Run setup after create as a background job tracked by wtree jobs"`
+}
+
+// NotificationConfig sends a message to a URL when selected events happen.
+// Message is a template with {event}, {repo}, {host}, {user} and
+// event-specific placeholders such as {branch}, {pr_number} or {count}.
+type NotificationConfig struct {
+	URL     string   `yaml:"url" mapstructure:"url" desc:"Slack, Discord or webhook URL to post to"`
+	Format  string   `yaml:"format" mapstructure:"format" desc:"Payload format: slack, discord or webhook (default: webhook)"`
+	Events  []string `yaml:"events" mapstructure:"events" desc:"Events to send (worktree_created, pr_created, cleanup); empty sends all"`
+	Message string   `yaml:"message" mapstructure:"message" desc:"Message template overriding the event's default, e.g. {user} cleaned up {count} worktrees"`
+}
+
+// Notification payload formats for NotificationConfig.Format
+const (
+	NotifyFormatSlack   = "slack"   // {"text": message}
+	NotifyFormatDiscord = "discord" // {"content": message}
+	NotifyFormatWebhook = "webhook" // JSON object with the event, message and every placeholder
+)
+
+// Notification events
+const (
+	NotifyWorktreeCreated = "worktree_created"
+	NotifyPRCreated       = "pr_created"
+	NotifyCleanup         = "cleanup"
+)
+
+// NotifyEvents lists the events notifications can select
+var NotifyEvents = []string{NotifyWorktreeCreated, NotifyPRCreated, NotifyCleanup}
+
+// Limit enforcement modes
+const (
+	LimitModeWarn  = "warn"
+	LimitModeBlock = "block"
+)
+
 // DefaultWTreeConfig returns the default configuration
 func DefaultWTreeConfig() *WTreeConfig {
 	return &WTreeConfig{
@@ -64,10 +239,12 @@ func DefaultWTreeConfig() *WTreeConfig {
 			ProgressBars:       true,
 			Verbose:            false,
 			ConfirmDestructive: true,
+			TimeFormat:         TimeFormatRelative,
 		},
 		GitHub: GitHubConfig{
-			CLICommand:   "gh",
-			CacheTimeout: 5 * time.Minute,
+			CLICommand:            "gh",
+			CacheTimeout:          5 * time.Minute,
+			MaxConcurrentRequests: 4,
 		},
 		Hooks: HookConfig{
 			Timeout:      5 * time.Minute,
@@ -76,11 +253,28 @@ func DefaultWTreeConfig() *WTreeConfig {
 		},
 		Paths: PathConfig{
 			WorktreeParent: "", // Auto-detect
+			CrossDevice:    CrossDeviceCopy,
 		},
 		Performance: PerformanceConfig{
 			MaxConcurrentOps: 3,
 			OperationTimeout: 10 * time.Minute,
 		},
+		Limits: LimitsConfig{
+			MaxWorktrees: 0, // Unlimited
+			OnLimit:      LimitModeWarn,
+		},
+		Trash: TrashConfig{
+			Enabled:       false,
+			RetentionDays: 7,
+		},
+		Review: ReviewConfig{
+			LocalFiles: []string{".wtreerc.local", ".vscode/settings.json", "*.code-workspace", ".idea/workspace.xml"},
+		},
+		Fetch: FetchConfig{
+			Auto:        false,
+			MinInterval: 5 * time.Minute,
+		},
+		ProjectConfigSource: ProjectConfigWorktree,
 	}
 }
 
@@ -94,36 +288,99 @@ const (
 	HookPostDelete HookEvent = "post_delete"
 	HookPreMerge   HookEvent = "pre_merge"
 	HookPostMerge  HookEvent = "post_merge"
+	HookPreRebase  HookEvent = "pre_rebase"
+	HookPostRebase HookEvent = "post_rebase"
+	HookPreSwitch  HookEvent = "pre_switch"
+	HookPostSwitch HookEvent = "post_switch"
+	HookPreSync    HookEvent = "pre_sync"
+	HookPostSync   HookEvent = "post_sync"
+	HookOnPrune    HookEvent = "on_prune"
+
+	// HookOnCleanup is another name for on_prune, which .wtreerc files may use
+	HookOnCleanup HookEvent = "on_cleanup"
 )
 
 // ProjectConfig represents project-specific configuration from .wtreerc
 type ProjectConfig struct {
-	Version string `yaml:"version" mapstructure:"version"`
+	Version string `yaml:"version" mapstructure:"version" desc:"Configuration format version; must be 1.0"`
 
 	// Hook definitions (project-specific commands)
-	Hooks map[HookEvent][]string `yaml:"hooks" mapstructure:"hooks"`
+	Hooks map[HookEvent][]string `yaml:"hooks" mapstructure:"hooks" desc:"Commands or @recipes per event (pre_create, post_create, pre_delete, post_delete, pre_merge, post_merge, pre_rebase, post_rebase, pre_switch, post_switch, pre_sync, post_sync, on_prune), optionally as {run, when, output} objects (output: stream or quiet); hooks.security sets how commands are checked: strict, standard or off"`
+
+	// hooks.security: how hook commands are checked before they run
+	HookSecurity string `yaml:"-" mapstructure:"-"`
+
+	// when: conditions of hooks written as {run, when} objects, index-aligned with Hooks
+	HookConditions map[HookEvent][]*Condition `yaml:"-" mapstructure:"-"`
+
+	// output: settings of hooks written as objects, index-aligned with Hooks
+	HookOutputs map[HookEvent][]string `yaml:"-" mapstructure:"-"`
 
 	// File operations
-	CopyFiles   []string `yaml:"copy_files" mapstructure:"copy_files"`
-	LinkFiles   []string `yaml:"link_files" mapstructure:"link_files"`
-	IgnoreFiles []string `yaml:"ignore_files" mapstructure:"ignore_files"`
+	CopyFiles   []string `yaml:"copy_files" mapstructure:"copy_files" desc:"Untracked files copied into new worktrees; patterns, or {path}/{from, to} objects with optional when"`
+	LinkFiles   []string `yaml:"link_files" mapstructure:"link_files" desc:"Untracked files symlinked into new worktrees; same forms as copy_files"`
+	IgnoreFiles []string `yaml:"ignore_files" mapstructure:"ignore_files" desc:"Patterns never copied or linked"`
+
+	// Entries of copy_files/link_files written as objects ({path} or {from, to}, with optional when)
+	CopyEntries []FileEntry `yaml:"-" mapstructure:"-"`
+	LinkEntries []FileEntry `yaml:"-" mapstructure:"-"`
+
+	// Checks run in the source branch's worktree before merging (e.g. "npm test")
+	PreMergeChecks []string `yaml:"pre_merge_checks" mapstructure:"pre_merge_checks" desc:"Commands run in the source worktree before wtree merge"`
+
+	// URLs opened in the browser after create; supports {branch}, {repo}, {pr_url}, {pr_number}
+	OpenURLs []string `yaml:"open_urls" mapstructure:"open_urls" desc:"URLs opened after create; supports {branch}, {repo}, {pr_url} and {pr_number}"`
+
+	// How commit hooks are made to work in new worktrees: "copy", "link", or "install"
+	GitHooks string `yaml:"git_hooks" mapstructure:"git_hooks" desc:"How commit hooks are set up in new worktrees: copy, link or install"`
 
 	// Naming and behavior overrides
-	WorktreePattern string `yaml:"worktree_pattern" mapstructure:"worktree_pattern"`
-	Editor          string `yaml:"editor" mapstructure:"editor"`
+	WorktreePattern string           `yaml:"worktree_pattern" mapstructure:"worktree_pattern" desc:"Worktree directory name, a template with {{.Repo}}, {{.Branch}}, {{.BranchSlug}}, {{.User}} and {{.Date}} ({repo}, {branch}, {branch_slug} and {user} also work)"`
+	BranchSlug      BranchSlugConfig `yaml:"branch_slug" mapstructure:"branch_slug" desc:"How branch names become directory names for {branch_slug}"`
+	Editor          string           `yaml:"editor" mapstructure:"editor" desc:"Editor for this project, overriding the global editor"`
 
 	// Execution settings (overrides global)
-	Timeout      time.Duration `yaml:"timeout" mapstructure:"timeout"`
-	AllowFailure bool          `yaml:"allow_failure" mapstructure:"allow_failure"`
-	Verbose      bool          `yaml:"verbose" mapstructure:"verbose"`
+	Timeout      time.Duration `yaml:"timeout" mapstructure:"timeout" desc:"Hook time limit, overriding hooks.timeout"`
+	AllowFailure bool          `yaml:"allow_failure" mapstructure:"allow_failure" desc:"Continue when a hook fails, overriding hooks.allow_failure"`
+	Verbose      bool          `yaml:"verbose" mapstructure:"verbose" desc:"Print hook output"`
+
+	// SHA-256 of the YAML this was parsed from, which trust decisions
+	// about the file are tied to
+	Digest string `yaml:"-" mapstructure:"-"`
+}
+
+// DefaultWorktreePattern names worktree directories after the repository
+// and the branch, with '/' in branch names replaced
+const DefaultWorktreePattern = "{repo}-{branch_slug}"
+
+// BranchSlugConfig controls how a branch name is made into a single
+// directory name: feature/login becomes feature-login by default
+type BranchSlugConfig struct {
+	Separator string `yaml:"separator" mapstructure:"separator" desc:"What '/' and characters not allowed in file names become (default '-')"`
+	Lowercase bool   `yaml:"lowercase" mapstructure:"lowercase" desc:"Lowercase the slug"`
 }
 
+// Hook command checks for ProjectConfig.HookSecurity and HookSecurityPolicy.Mode
+const (
+	HookSecurityStrict   = "strict"   // Standard, and no sudo, downloads or eval either
+	HookSecurityStandard = "standard" // Reject destructive, injection and obfuscation patterns (the default)
+	HookSecurityOff      = "off"      // No checks; from .wtreerc it needs the user's acknowledgement
+	HookSecurityWarn     = "warn"     // Standard, reported but not rejected; global hooks.security.mode only
+)
+
+// Git hook setup modes for ProjectConfig.GitHooks
+const (
+	GitHooksCopy    = "copy"    // Copy untracked hook files from the main repository
+	GitHooksLink    = "link"    // Symlink untracked hook files from the main repository
+	GitHooksInstall = "install" // Run the project's hook manager installer in the worktree
+)
+
 // DefaultProjectConfig returns the default project configuration
 func DefaultProjectConfig() *ProjectConfig {
 	return &ProjectConfig{
 		Version:         "1.0",
 		Hooks:           make(map[HookEvent][]string),
-		WorktreePattern: "{repo}-{branch}",
+		WorktreePattern: DefaultWorktreePattern,
 		CopyFiles:       []string{},
 		LinkFiles:       []string{},
 		IgnoreFiles:     []string{},
@@ -137,17 +394,47 @@ type HookContext struct {
 	RepoPath     string
 	Branch       string
 	TargetBranch string
+	ConfigSource string // Where the project config came from, e.g. types.ProjectConfigMain
 	Environment  map[string]string
 }
 
 // WorktreeInfo represents information about a worktree
 type WorktreeInfo struct {
-	Path       string
-	Branch     string
-	IsMainRepo bool
-	IsClean    bool
-	Ahead      int
-	Behind     int
+	Path           string
+	Branch         string // Empty when HEAD is detached
+	Head           string // Commit checked out
+	IsMainRepo     bool
+	Detached       bool
+	Unborn         bool   // Branch has no commits yet
+	Locked         bool   // Locked with `git worktree lock`
+	LockReason     string // Reason given to `git worktree lock --reason`
+	Prunable       bool   // Its directory is gone; `git worktree prune` would remove it
+	PrunableReason string
+	Upstream       string // Branch the worktree's branch tracks, e.g. origin/feature
+	Ahead          int    // Commits the branch has that its upstream doesn't
+	Behind         int    // Commits the upstream has that the branch doesn't
+
+	// Filled in only by ListWorktreesWithStatus, which checks each worktree
+	StatusLoaded bool
+	IsClean      bool
+	Operation    string // Rebase, merge... left in progress, e.g. "REBASING 3/7"
+}
+
+// DisplayBranch names the worktree's checkout for display: the branch,
+// "detached@<sha>" or "<branch> (unborn)"
+func (wt *WorktreeInfo) DisplayBranch() string {
+	switch {
+	case wt.Detached:
+		head := wt.Head
+		if len(head) > 7 {
+			head = head[:7]
+		}
+		return "detached@" + head
+	case wt.Unborn:
+		return wt.Branch + " (unborn)"
+	default:
+		return wt.Branch
+	}
 }
 
 // WorktreeStatus represents the status of a worktree for display
@@ -0,0 +1,82 @@
+package types
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Units beyond the hours time.ParseDuration stops at. A month is 30 days
+// and a year 365; both are approximations, good enough for ages.
+const (
+	Day   = 24 * time.Hour
+	Week  = 7 * Day
+	Month = 30 * Day
+	Year  = 365 * Day
+)
+
+// durationUnits maps every unit spelling ParseDuration accepts to its length
+var durationUnits = map[string]time.Duration{
+	"ns": time.Nanosecond, "us": time.Microsecond, "µs": time.Microsecond, "ms": time.Millisecond,
+	"s": time.Second, "sec": time.Second, "secs": time.Second, "second": time.Second, "seconds": time.Second,
+	"m": time.Minute, "min": time.Minute, "mins": time.Minute, "minute": time.Minute, "minutes": time.Minute,
+	"h": time.Hour, "hr": time.Hour, "hrs": time.Hour, "hour": time.Hour, "hours": time.Hour,
+	"d": Day, "day": Day, "days": Day,
+	"w": Week, "wk": Week, "wks": Week, "week": Week, "weeks": Week,
+	"mo": Month, "month": Month, "months": Month,
+	"y": Year, "yr": Year, "yrs": Year, "year": Year, "years": Year,
+}
+
+// ParseDuration parses a duration the way people write them: everything
+// time.ParseDuration accepts, plus days, weeks, months and years, spaces
+// and spelled-out units, e.g. "30d", "2w", "1h30m", "2 weeks", "1 week 3 days".
+// Note that "m" is minutes, as in Go; months are "mo".
+func ParseDuration(s string) (time.Duration, error) {
+	input := strings.ToLower(strings.TrimSpace(s))
+	if input == "" {
+		return 0, fmt.Errorf("empty duration")
+	}
+	if input == "0" {
+		return 0, nil
+	}
+
+	var total float64
+	rest := strings.ReplaceAll(input, ",", " ")
+	for rest = strings.TrimSpace(rest); rest != ""; rest = strings.TrimSpace(rest) {
+		rest = strings.TrimPrefix(rest, "and ")
+
+		end := strings.IndexFunc(rest, func(r rune) bool { return !('0' <= r && r <= '9') && r != '.' })
+		if end == 0 {
+			return 0, fmt.Errorf("invalid duration %q: expected a number at %q", s, rest)
+		}
+		if end < 0 {
+			return 0, fmt.Errorf("invalid duration %q: missing unit after %s (e.g. 30d, 2w, 1h30m)", s, rest)
+		}
+		value, err := strconv.ParseFloat(rest[:end], 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q: bad number %q", s, rest[:end])
+		}
+		rest = strings.TrimLeft(rest[end:], " ")
+
+		unitEnd := strings.IndexFunc(rest, func(r rune) bool { return ('0' <= r && r <= '9') || r == '.' || r == ' ' })
+		if unitEnd < 0 {
+			unitEnd = len(rest)
+		}
+		unit, ok := durationUnits[rest[:unitEnd]]
+		if !ok {
+			if unitEnd == 0 {
+				return 0, fmt.Errorf("invalid duration %q: missing unit after %s (e.g. 30d, 2w, 1h30m)", s, strconv.FormatFloat(value, 'f', -1, 64))
+			}
+			return 0, fmt.Errorf("invalid duration %q: unknown unit %q", s, rest[:unitEnd])
+		}
+		rest = rest[unitEnd:]
+
+		total += value * float64(unit)
+		if total > math.MaxInt64 {
+			return 0, fmt.Errorf("invalid duration %q: too long", s)
+		}
+	}
+	return time.Duration(total), nil
+}
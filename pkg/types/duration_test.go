@@ -0,0 +1,59 @@
+package types
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected time.Duration
+	}{
+		{"0", 0},
+		{"5m", 5 * time.Minute},
+		{"1h30m", 90 * time.Minute},
+		{"1.5h", 90 * time.Minute},
+		{"300ms", 300 * time.Millisecond},
+		{"30d", 30 * Day},
+		{"2w", 2 * Week},
+		{"1w2d", 9 * Day},
+		{"3mo", 90 * Day},
+		{"1y", 365 * Day},
+		{"2 weeks", 2 * Week},
+		{"1 week 3 days", 10 * Day},
+		{"1 week, 3 days", 10 * Day},
+		{"1 day and 12 hours", 36 * time.Hour},
+		{" 45 Minutes ", 45 * time.Minute},
+		{"1 hr 5 mins", 65 * time.Minute},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseDuration(tt.input)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, got)
+		})
+	}
+}
+
+func TestParseDuration_invalid(t *testing.T) {
+	for _, input := range []string{"", "  ", "30", "d", "-5m", "2 fortnights", "1..5h", "5m and", "1000000y"} {
+		t.Run(input, func(t *testing.T) {
+			_, err := ParseDuration(input)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestParseDuration_matchesGo(t *testing.T) {
+	for _, input := range []string{"5m0s", "2h45m", "1.5s", "100us", "10ns"} {
+		expected, err := time.ParseDuration(input)
+		require.NoError(t, err)
+		got, err := ParseDuration(input)
+		require.NoError(t, err)
+		assert.Equal(t, expected, got, input)
+	}
+}
@@ -1,6 +1,9 @@
 package types
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+)
 
 // ErrorType represents the category of error
 type ErrorType int
@@ -76,6 +79,14 @@ func (be *BaseError) SuggestedActions() []string      { return be.suggestedActio
 func (be *BaseError) UserMessage() string             { return be.message }
 func (be *BaseError) Unwrap() error                   { return be.cause }
 
+// WithSuggestedActions returns a copy of the error with the given actions
+// placed ahead of the defaults
+func (be *BaseError) WithSuggestedActions(actions ...string) *BaseError {
+	clone := *be
+	clone.suggestedActions = append(append([]string{}, actions...), be.suggestedActions...)
+	return &clone
+}
+
 // Specific error types
 
 // ValidationError represents validation failures
@@ -194,3 +205,35 @@ func NewHookError(operation, message string, cause error) *HookError {
 		},
 	}
 }
+
+// ExitCodeError reports a command run on the user's behalf exiting non-zero,
+// so that wtree can exit with the same status
+type ExitCodeError struct {
+	Command string
+	Code    int
+}
+
+func (e *ExitCodeError) Error() string {
+	return fmt.Sprintf("'%s' exited with status %d", e.Command, e.Code)
+}
+
+// ExitInterrupted is the status wtree exits with when SIGINT or SIGTERM
+// stops an operation, the one shells report for Ctrl-C
+const ExitInterrupted = 130
+
+// ErrInterrupted reports an operation stopped by SIGINT or SIGTERM
+var ErrInterrupted = errors.New("interrupted")
+
+// ExitCode returns the status wtree exits with for err: ExitInterrupted
+// for ErrInterrupted, that of the command behind an ExitCodeError,
+// otherwise 1
+func ExitCode(err error) int {
+	if errors.Is(err, ErrInterrupted) {
+		return ExitInterrupted
+	}
+	var exitErr *ExitCodeError
+	if errors.As(err, &exitErr) && exitErr.Code > 0 {
+		return exitErr.Code
+	}
+	return 1
+}
@@ -2,6 +2,7 @@ package types
 
 import (
 	"errors"
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -94,3 +95,23 @@ func TestIsWTreeError(t *testing.T) {
 		t.Fatal("Should not be WTreeError")
 	}
 }
+
+func TestWithSuggestedActions(t *testing.T) {
+	err := NewValidationError("test-operation", "test message", nil)
+	defaults := err.SuggestedActions()
+
+	updated := err.WithSuggestedActions("Retry with --wait")
+
+	assert.Equal(t, "Retry with --wait", updated.SuggestedActions()[0])
+	assert.Len(t, updated.SuggestedActions(), len(defaults)+1)
+	assert.Equal(t, defaults, err.SuggestedActions(), "original error is not modified")
+}
+
+func TestExitCode(t *testing.T) {
+	err := fmt.Errorf("exec: %w", &ExitCodeError{Command: "make test", Code: 2})
+	assert.Equal(t, 2, ExitCode(err))
+	assert.Equal(t, "exec: 'make test' exited with status 2", err.Error())
+
+	assert.Equal(t, 1, ExitCode(NewValidationError("exec", "failed", nil)))
+	assert.Equal(t, ExitInterrupted, ExitCode(fmt.Errorf("create: %w", ErrInterrupted)))
+}
@@ -0,0 +1,148 @@
+package types
+
+import (
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FileEntry is a copy_files/link_files entry written as an object. It names
+// either a pattern ({path: "node_modules"}) or a single path copied or linked
+// to a different location ({from: "configs/dev.env", to: ".env"}), optionally
+// restricted by a when condition.
+type FileEntry struct {
+	Path string     `yaml:"path"`
+	From string     `yaml:"from"`
+	To   string     `yaml:"to"`
+	When *Condition `yaml:"when"`
+}
+
+// IsMapping reports whether the entry is a {from, to} mapping rather than a pattern
+func (e FileEntry) IsMapping() bool {
+	return e.Path == ""
+}
+
+// Applies reports whether the entry's condition holds in env
+func (e FileEntry) Applies(env ConditionEnv) bool {
+	return e.When == nil || e.When.Matches(env)
+}
+
+// SplitFileEntries returns the patterns and mappings of the entries that apply in env
+func SplitFileEntries(entries []FileEntry, env ConditionEnv) ([]string, []FileEntry) {
+	var patterns []string
+	var mappings []FileEntry
+	for _, entry := range entries {
+		if !entry.Applies(env) {
+			continue
+		}
+		if entry.IsMapping() {
+			mappings = append(mappings, entry)
+		} else {
+			patterns = append(patterns, entry.Path)
+		}
+	}
+	return patterns, mappings
+}
+
+// UnmarshalYAML accepts copy_files, link_files, and hooks entries that are
+// either plain strings or objects. Plain patterns are decoded into CopyFiles
+// or LinkFiles and objects into CopyEntries or LinkEntries; hook objects are
+// decoded into Hooks with their conditions in HookConditions and output
+// settings in HookOutputs.
+func (c *ProjectConfig) UnmarshalYAML(value *yaml.Node) error {
+	type plain ProjectConfig
+
+	if value.Kind != yaml.MappingNode {
+		return value.Decode((*plain)(c))
+	}
+
+	// Decode everything except the file lists with the default rules
+	rest := *value
+	rest.Content = nil
+	var copyNode, linkNode, hooksNode *yaml.Node
+	for i := 0; i+1 < len(value.Content); i += 2 {
+		switch value.Content[i].Value {
+		case "copy_files":
+			copyNode = value.Content[i+1]
+		case "link_files":
+			linkNode = value.Content[i+1]
+		case "hooks":
+			hooksNode = value.Content[i+1]
+		default:
+			rest.Content = append(rest.Content, value.Content[i], value.Content[i+1])
+		}
+	}
+
+	if err := rest.Decode((*plain)(c)); err != nil {
+		return err
+	}
+
+	var err error
+	if c.CopyFiles, c.CopyEntries, err = decodeFileEntries(copyNode, "copy_files"); err != nil {
+		return err
+	}
+	if c.LinkFiles, c.LinkEntries, err = decodeFileEntries(linkNode, "link_files"); err != nil {
+		return err
+	}
+	if hooksNode != nil {
+		if hooksNode, c.HookSecurity, err = splitHookSecurity(hooksNode); err != nil {
+			return err
+		}
+		if c.Hooks, c.HookConditions, c.HookOutputs, err = decodeHooks(hooksNode); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// splitHookSecurity takes the security setting out of the hooks mapping,
+// whose other keys are events
+func splitHookSecurity(node *yaml.Node) (*yaml.Node, string, error) {
+	if node.Kind != yaml.MappingNode {
+		return node, "", nil
+	}
+	events := *node
+	events.Content = nil
+	security := ""
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value != "security" {
+			events.Content = append(events.Content, node.Content[i], node.Content[i+1])
+			continue
+		}
+		if node.Content[i+1].Kind != yaml.ScalarNode {
+			return nil, "", fmt.Errorf("line %d: hooks.security must be strict, standard or off", node.Content[i+1].Line)
+		}
+		security = node.Content[i+1].Value
+	}
+	return &events, security, nil
+}
+
+// decodeFileEntries splits a copy_files/link_files sequence into plain patterns and object entries
+func decodeFileEntries(node *yaml.Node, key string) ([]string, []FileEntry, error) {
+	if node == nil || (node.Kind == yaml.ScalarNode && node.Tag == "!!null") {
+		return nil, nil, nil
+	}
+	if node.Kind != yaml.SequenceNode {
+		return nil, nil, fmt.Errorf("line %d: %s must be a list", node.Line, key)
+	}
+
+	var patterns []string
+	var entries []FileEntry
+	for _, entry := range node.Content {
+		switch entry.Kind {
+		case yaml.ScalarNode:
+			patterns = append(patterns, entry.Value)
+		case yaml.MappingNode:
+			var fileEntry FileEntry
+			if err := entry.Decode(&fileEntry); err != nil {
+				return nil, nil, err
+			}
+			entries = append(entries, fileEntry)
+		default:
+			return nil, nil, fmt.Errorf("line %d: %s entries must be a path or an object", entry.Line, key)
+		}
+	}
+
+	return patterns, entries, nil
+}
@@ -0,0 +1,60 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestProjectConfig_UnmarshalFileEntries(t *testing.T) {
+	data := `
+version: "1.0"
+copy_files:
+  - ".env.example"
+  - from: "configs/dev.env"
+    to: ".env"
+link_files:
+  - "node_modules"
+  - {from: "../shared/cache", to: ".cache"}
+  - path: "vendor"
+    when: {os: windows}
+worktree_pattern: "{repo}-{branch}"
+`
+
+	var config ProjectConfig
+	require.NoError(t, yaml.Unmarshal([]byte(data), &config))
+
+	assert.Equal(t, "1.0", config.Version)
+	assert.Equal(t, "{repo}-{branch}", config.WorktreePattern)
+	assert.Equal(t, []string{".env.example"}, config.CopyFiles)
+	assert.Equal(t, []FileEntry{{From: "configs/dev.env", To: ".env"}}, config.CopyEntries)
+	assert.Equal(t, []string{"node_modules"}, config.LinkFiles)
+	assert.Equal(t, []FileEntry{
+		{From: "../shared/cache", To: ".cache"},
+		{Path: "vendor", When: &Condition{OS: StringList{"windows"}}},
+	}, config.LinkEntries)
+}
+
+func TestSplitFileEntries(t *testing.T) {
+	entries := []FileEntry{
+		{Path: "node_modules", When: &Condition{OS: StringList{"macos", "linux"}}},
+		{Path: "dist"},
+		{From: "configs/win.env", To: ".env", When: &Condition{OS: StringList{"windows"}}},
+	}
+
+	patterns, mappings := SplitFileEntries(entries, ConditionEnv{OS: "darwin"})
+	assert.Equal(t, []string{"node_modules", "dist"}, patterns)
+	assert.Empty(t, mappings)
+
+	patterns, mappings = SplitFileEntries(entries, ConditionEnv{OS: "windows"})
+	assert.Equal(t, []string{"dist"}, patterns)
+	assert.Equal(t, []FileEntry{entries[2]}, mappings)
+}
+
+func TestProjectConfig_UnmarshalFileEntriesInvalid(t *testing.T) {
+	var config ProjectConfig
+	assert.Error(t, yaml.Unmarshal([]byte("copy_files: \".env\"\n"), &config))
+	assert.Error(t, yaml.Unmarshal([]byte("copy_files:\n  - [a, b]\n"), &config))
+}
@@ -0,0 +1,22 @@
+package types
+
+import "github.com/awhite/wtree/pkg/plugin"
+
+// PluginContext is the context handed to plugins.
+//
+// Deprecated: plugins should use plugin.Context from pkg/plugin, the stable
+// plugin API; this alias remains for existing code.
+type PluginContext = plugin.Context
+
+// PluginPayload converts a hook context into the payload plugins receive for
+// the same lifecycle event
+func (c HookContext) PluginPayload() plugin.EventPayload {
+	return plugin.EventPayload{
+		Event:        plugin.Event(c.Event),
+		Branch:       c.Branch,
+		TargetBranch: c.TargetBranch,
+		RepoPath:     c.RepoPath,
+		WorktreePath: c.WorktreePath,
+		Environment:  c.Environment,
+	}
+}
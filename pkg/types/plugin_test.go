@@ -0,0 +1,45 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/awhite/wtree/pkg/plugin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHookContext_PluginPayload(t *testing.T) {
+	ctx := HookContext{
+		Event:        HookPostCreate,
+		Branch:       "feature",
+		RepoPath:     "/repo",
+		WorktreePath: "/repo-feature",
+		Environment:  map[string]string{"WTREE_BRANCH": "feature"},
+	}
+	assert.Equal(t, plugin.EventPayload{
+		Event:        plugin.EventPostCreate,
+		Branch:       "feature",
+		RepoPath:     "/repo",
+		WorktreePath: "/repo-feature",
+		Environment:  map[string]string{"WTREE_BRANCH": "feature"},
+	}, ctx.PluginPayload())
+
+	// Plugin events keep the hook names
+	events := map[HookEvent]plugin.Event{
+		HookPreCreate:  plugin.EventPreCreate,
+		HookPostCreate: plugin.EventPostCreate,
+		HookPreDelete:  plugin.EventPreDelete,
+		HookPostDelete: plugin.EventPostDelete,
+		HookPreMerge:   plugin.EventPreMerge,
+		HookPostMerge:  plugin.EventPostMerge,
+		HookPreRebase:  plugin.EventPreRebase,
+		HookPostRebase: plugin.EventPostRebase,
+		HookPreSwitch:  plugin.EventPreSwitch,
+		HookPostSwitch: plugin.EventPostSwitch,
+		HookPreSync:    plugin.EventPreSync,
+		HookPostSync:   plugin.EventPostSync,
+		HookOnPrune:    plugin.EventOnPrune,
+	}
+	for hook, event := range events {
+		assert.Equal(t, string(hook), string(event))
+	}
+}
@@ -0,0 +1,38 @@
+package types
+
+import "strings"
+
+// HookRecipePrefix marks a hook that names a built-in recipe instead of a
+// shell command, e.g. "@node-install"
+const HookRecipePrefix = "@"
+
+// Built-in hook recipes
+const (
+	RecipeNodeInstall     = "@node-install"     // npm ci, pnpm, yarn or bun install, picked by lockfile
+	RecipeGoModDownload   = "@go-mod-download"  // go mod download
+	RecipeBundleInstall   = "@bundle-install"   // bundle install
+	RecipeComposerInstall = "@composer-install" // composer install --no-interaction
+)
+
+// HookRecipes lists the built-in recipes in documentation order
+var HookRecipes = []string{
+	RecipeNodeInstall,
+	RecipeGoModDownload,
+	RecipeBundleInstall,
+	RecipeComposerInstall,
+}
+
+// IsHookRecipe reports whether a hook names a recipe rather than a command
+func IsHookRecipe(hook string) bool {
+	return strings.HasPrefix(strings.TrimSpace(hook), HookRecipePrefix)
+}
+
+// KnownHookRecipe reports whether name is a built-in recipe
+func KnownHookRecipe(name string) bool {
+	for _, recipe := range HookRecipes {
+		if recipe == strings.TrimSpace(name) {
+			return true
+		}
+	}
+	return false
+}